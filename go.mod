@@ -16,6 +16,7 @@ require (
 	github.com/clipperhouse/uax29/v2 v2.2.0 // 0 transitive deps!
 	github.com/creack/pty v1.1.24 // 0 transitive deps! (ONLY used for testing, so we could potentially get rid of it)
 	github.com/mattn/go-runewidth v0.0.19 // 1 transitive dep: github.com/clipperhouse/uax29
+	github.com/rivo/uniseg v0.4.7 // 0 transitive deps! (ONLY used for UAX #14 line breaking; uax29 doesn't have it)
 	github.com/yuin/goldmark v1.7.13 // 0 transitive deps!
 	gopkg.in/yaml.v3 v3.0.1 // 1 transitive deps: gopkg.in/check.v1 (a testing lib)
 )
@@ -30,7 +30,7 @@ var newConversation = llmstream.NewConversation
 type Agent struct {
 	sessionID           string                          // The session ID identifies the root session shared by this agent and its subagents.
 	agentID             string                          // The agent ID identifies this agent in emitted Event.Agent metadata.
-	model               llmmodel.ModelID                // The model selects the provider model used for sends and context estimates.
+	model               llmmodel.ModelID                // The model is the conversation's active model (it changes on failover) and is used for context estimates.
 	noStore             bool                            // The no-store flag enables provider ZDR/no-store behavior on every send.
 	subagentLabel       string                          // The subagent label is emitted with the start-subagent event for this agent.
 	callingToolCallID   string                          // The calling tool-call ID records the parent tool call that created this subagent.
@@ -97,17 +97,26 @@ func (a *Agent) TokenUsage() llmstream.TokenUsage {
 	return a.tokenUsage
 }
 
+// Model returns the model the agent's conversation currently sends to. It starts as the model the agent was created with and changes when a send fails over to
+// a fallback model.
+func (a *Agent) Model() llmmodel.ModelID {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.model
+}
+
 // ContextUsagePercent estimates how much of the model's context window is consumed based on the latest assistant turn. Returns 0 when unknown.
 func (a *Agent) ContextUsagePercent() int {
-	info := llmmodel.GetModelInfo(a.model)
-	if info.ContextWindow <= 0 {
-		return 0
-	}
-
 	a.mu.Lock()
 	used := a.contextUsageTokens
+	model := a.model
 	a.mu.Unlock()
 
+	info := llmmodel.GetModelInfo(model)
+	if info.ContextWindow <= 0 {
+		return 0
+	}
+
 	if used <= 0 {
 		return 0
 	}
@@ -198,6 +207,12 @@ func (a *Agent) QueueUserMessage(message string) error {
 // Context cancellation: Cancellation is surfaced as an EventTypeCanceled terminal event. Depending on when ctx is canceled and when the underlying provider/tool
 // stops, some non-terminal events may be delivered before the cancellation is observed.
 func (a *Agent) SendUserMessage(ctx context.Context, message string) <-chan Event {
+	return a.SendUserMessageWithImages(ctx, message, nil)
+}
+
+// SendUserMessageWithImages is like SendUserMessage, but attaches images to the user turn after message. Invalid images (see llmstream.NewImageContent) are reported
+// as an EventTypeError terminal event.
+func (a *Agent) SendUserMessageWithImages(ctx context.Context, message string, images []llmstream.ImageContent) <-chan Event {
 	out := make(chan Event, 32)
 	runCtx := ctx
 	var runCancel context.CancelFunc
@@ -232,7 +247,7 @@ func (a *Agent) SendUserMessage(ctx context.Context, message string) <-chan Even
 		runCtx, runCancel = contextWithLifetime(ctx, a.lifetimeCtx)
 	}
 
-	if err := a.conv.AddUserTurn(message); err != nil {
+	if err := a.conv.AddUserTurnWithImages(message, images); err != nil {
 		a.mu.Unlock()
 		if runCancel != nil {
 			runCancel()
@@ -245,7 +260,7 @@ func (a *Agent) SendUserMessage(ctx context.Context, message string) <-chan Even
 		return out
 	}
 
-	a.turns = append(a.turns, newTextTurn(llmstream.RoleUser, message))
+	a.turns = append(a.turns, newUserTurn(message, images))
 	a.status = StatusRunning
 	a.currentOut = out
 	a.pendingUserMessages = nil
@@ -430,6 +445,8 @@ func (a *Agent) sendOnce(ctx context.Context, out chan<- Event) (*llmstream.Turn
 		}
 	}
 
+	a.syncModel()
+
 	if sendErr != nil {
 		flushBufferedText(false)
 		return nil, nil, sendErr
@@ -613,6 +630,21 @@ func newTextTurn(role llmstream.Role, text string) llmstream.Turn {
 	}
 }
 
+// newUserTurn returns a user turn with text followed by images, matching llmstream's AddUserTurnWithImages (text is omitted when empty and images are present).
+func newUserTurn(text string, images []llmstream.ImageContent) llmstream.Turn {
+	if len(images) == 0 {
+		return newTextTurn(llmstream.RoleUser, text)
+	}
+	turn := llmstream.Turn{Role: llmstream.RoleUser}
+	if text != "" {
+		turn.Parts = append(turn.Parts, llmstream.TextContent{Content: text})
+	}
+	for _, img := range images {
+		turn.Parts = append(turn.Parts, img)
+	}
+	return turn
+}
+
 func toolResultTurn(results []llmstream.ToolResult) llmstream.Turn {
 	parts := make([]llmstream.ContentPart, len(results))
 	for i, r := range results {
@@ -740,6 +772,18 @@ func (a *Agent) addUsage(usage llmstream.TokenUsage) {
 	}
 }
 
+// syncModel records the conversation's active model, which changes when a send fails over to a fallback model.
+func (a *Agent) syncModel() {
+	model := a.conv.Model()
+	if model == "" {
+		return
+	}
+
+	a.mu.Lock()
+	a.model = model
+	a.mu.Unlock()
+}
+
 // updateContextUsage records the latest positive context-window token count for this agent.
 func (a *Agent) updateContextUsage(usage llmstream.TokenUsage) {
 	tokens := contextTokensFromUsage(usage)
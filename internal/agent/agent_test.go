@@ -1430,6 +1430,37 @@ func TestContextUsagePercent(t *testing.T) {
 	}
 }
 
+func TestModelTracksConversationFailover(t *testing.T) {
+	systemPrompt := "You are helpful."
+	active := llmmodel.DefaultModel
+	info := llmmodel.GetModelInfo(active)
+	require.Positive(t, info.ContextWindow)
+
+	usage := llmstream.TokenUsage{TotalInputTokens: info.ContextWindow / 2}
+	turn := llmstream.Turn{
+		Role:         llmstream.RoleAssistant,
+		Parts:        []llmstream.ContentPart{llmstream.TextContent{Content: "done"}},
+		FinishReason: llmstream.FinishReasonEndTurn,
+		Usage:        usage,
+	}
+	conv := newScriptedConversation(systemPrompt, &sendScript{
+		events: []llmstream.Event{{Type: llmstream.EventTypeCompletedSuccess, Turn: &turn}},
+	})
+	// The conversation failed over from the agent's model (which has no known context window) to active.
+	conv.model = active
+	overrideConversation(t, conv)
+
+	a, err := New(systemPrompt, nil, NewOptions{Model: llmmodel.ModelID("unknown-model")})
+	require.NoError(t, err)
+	require.Equal(t, llmmodel.ModelID("unknown-model"), a.Model())
+
+	for range a.SendUserMessage(context.Background(), "ping") {
+	}
+
+	require.Equal(t, active, a.Model())
+	require.Equal(t, roundPercentFloat(float64(usage.TotalInputTokens), float64(info.ContextWindow)), a.ContextUsagePercent())
+}
+
 func TestSendUserMessageTerminalFinishReasons(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -2892,6 +2923,7 @@ func collectEvents(events <-chan Event) []Event {
 
 type scriptedConversation struct {
 	mu          sync.Mutex
+	model       llmmodel.ModelID
 	systemTurn  llmstream.Turn
 	turns       []llmstream.Turn
 	scripts     []*sendScript
@@ -2930,6 +2962,12 @@ func (c *scriptedConversation) Turns() []llmstream.Turn {
 	return cloneTurns(c.turns)
 }
 
+func (c *scriptedConversation) Model() llmmodel.ModelID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.model
+}
+
 func (c *scriptedConversation) AddTools(tools []llmstream.Tool) error {
 	return nil
 }
@@ -2941,6 +2979,17 @@ func (c *scriptedConversation) AddUserTurn(text string) error {
 	return nil
 }
 
+func (c *scriptedConversation) AddUserTurnWithImages(text string, images []llmstream.ImageContent) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	turn := newTextTurn(llmstream.RoleUser, text)
+	for _, img := range images {
+		turn.Parts = append(turn.Parts, img)
+	}
+	c.turns = append(c.turns, turn)
+	return nil
+}
+
 func (c *scriptedConversation) AddToolResults(results []llmstream.ToolResult) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
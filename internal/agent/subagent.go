@@ -78,7 +78,7 @@ func newSubAgentFactory(parent *Agent, toolCallID string) *subAgentFactory {
 		parent:       parent,
 		parentOut:    out,
 		toolCallID:   toolCallID,
-		defaultModel: parent.model,
+		defaultModel: parent.Model(),
 		tools:        cloneToolSlice(parent.toolList),
 	}
 }
@@ -56,6 +56,9 @@ type clarifyPublicAPIRequest struct {
 var (
 	toolOverridesMu sync.RWMutex
 	toolOverrides   = map[string]toolsetinterface.Tool{}
+
+	startupYAMLMu    sync.RWMutex
+	startupYAMLPaths []string
 )
 
 // OverrideTool registers or replaces a named tool builder for future BuildRegistry calls.
@@ -75,6 +78,23 @@ func OverrideTool(toolName string, tool toolsetinterface.Tool) {
 	toolOverrides[toolName] = tool
 }
 
+// SetStartupYAML replaces the YAML files that future BuildRegistry calls load (with AddYAMLToRegistry) after the built-in agents, in order. A nil or empty paths
+// clears them.
+//
+// Process-wide startup configuration for user and project agent files (ex: `.codalotl/agents.yml`).
+func SetStartupYAML(paths []string) {
+	for _, path := range paths {
+		if path == "" {
+			panic("agentbuilder.SetStartupYAML: path is required")
+		}
+	}
+
+	startupYAMLMu.Lock()
+	defer startupYAMLMu.Unlock()
+
+	startupYAMLPaths = append([]string(nil), paths...)
+}
+
 // BuildRegistry builds the registry.
 func BuildRegistry() (*agentregistry.Registry, error) {
 	registry := agentregistry.NewRegistry()
@@ -99,6 +119,12 @@ func BuildRegistry() (*agentregistry.Registry, error) {
 		return nil, err
 	}
 
+	for _, path := range startupYAML() {
+		if err := AddYAMLToRegistry(registry, path); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := registry.ValidateTools(); err != nil {
 		return nil, err
 	}
@@ -106,6 +132,22 @@ func BuildRegistry() (*agentregistry.Registry, error) {
 	return registry, nil
 }
 
+// ToolNames returns the names of the tools in the registry BuildRegistry builds, in sorted order. It returns nil if the registry can't be built.
+func ToolNames() []string {
+	registry, err := BuildRegistry()
+	if err != nil {
+		return nil
+	}
+	return registry.ListToolNames()
+}
+
+func startupYAML() []string {
+	startupYAMLMu.RLock()
+	defer startupYAMLMu.RUnlock()
+
+	return append([]string(nil), startupYAMLPaths...)
+}
+
 func genericTools() map[string]toolsetinterface.Tool {
 	builders := builtinTools()
 
@@ -176,6 +218,9 @@ func builtinTools() map[string]toolsetinterface.Tool {
 				},
 			), nil
 		},
+		pkgtools.ToolNameDepDocs: func(opts toolsetinterface.Options) (llmstream.Tool, error) {
+			return pkgtools.NewDepDocsTool(opts.Authorizer.WithoutCodeUnit()), nil
+		},
 		pkgtools.ToolNameGetPublicAPI: func(opts toolsetinterface.Options) (llmstream.Tool, error) {
 			return pkgtools.NewGetPublicAPITool(opts.Authorizer.WithoutCodeUnit()), nil
 		},
@@ -188,6 +233,9 @@ func builtinTools() map[string]toolsetinterface.Tool {
 		coretools.ToolNameReadFile: func(opts toolsetinterface.Options) (llmstream.Tool, error) {
 			return coretools.NewReadFileTool(opts.Authorizer), nil
 		},
+		coretools.ToolNameReadImage: func(opts toolsetinterface.Options) (llmstream.Tool, error) {
+			return coretools.NewReadImageTool(opts.Authorizer), nil
+		},
 		exttools.ToolNameRunProjectTests: func(opts toolsetinterface.Options) (llmstream.Tool, error) {
 			return exttools.NewRunProjectTestsTool(opts.GoPkgAbsDir, opts.Authorizer.WithoutCodeUnit()), nil
 		},
@@ -269,6 +317,7 @@ func packageAgentTools(opts toolsetinterface.Options) ([]llmstream.Tool, error)
 	return buildPackageModeTools(
 		opts,
 		AgentPackageModeNoContext,
+		coretools.ToolNameReadImage,
 		coretools.ToolNameSkillShell,
 		coretools.ToolNameUpdatePlan,
 		exttools.ToolNameDiagnostics,
@@ -277,6 +326,7 @@ func packageAgentTools(opts toolsetinterface.Options) ([]llmstream.Tool, error)
 		exttools.ToolNameRunProjectTests,
 		pkgtools.ToolNameModuleInfo,
 		pkgtools.ToolNameGetPublicAPI,
+		pkgtools.ToolNameDepDocs,
 		pkgtools.ToolNameClarifyPublicAPI,
 		pkgtools.ToolNameGetUsage,
 		pkgtools.ToolNameUpdateUsage,
@@ -62,7 +62,8 @@ func TestPackageDocumentation_CoversBuiltInAgentsAndYAMLStructure(t *testing.T)
 	}
 
 	for _, snippet := range []string{
-		"top-level `agents` and `tools` arrays",
+		"top-level `agents`, `tools`, and `routing` arrays",
+		"`model`",
 		"`prompts`",
 		"`edit_files`",
 		"`agentsmd`",
@@ -152,6 +153,7 @@ func TestBuildRegistry_InvokeGeneric_OpenAIUsesApplyPatch(t *testing.T) {
 		coretools.ToolNameReadFile,
 		coretools.ToolNameLS,
 		coretools.ToolNameApplyPatch,
+		coretools.ToolNameReadImage,
 		coretools.ToolNameShell,
 		coretools.ToolNameUpdatePlan,
 		spectools.ToolNameCheckSpecConformance,
@@ -174,6 +176,7 @@ func TestBuildRegistry_InvokeGeneric_UsesOptionalExternalToolsWhenOverridden(t *
 		coretools.ToolNameReadFile,
 		coretools.ToolNameLS,
 		coretools.ToolNameApplyPatch,
+		coretools.ToolNameReadImage,
 		yamlOptionalToolCodalotlCLI,
 		yamlOptionalToolRefactor,
 		coretools.ToolNameShell,
@@ -203,6 +206,7 @@ func TestBuildRegistry_InvokeGeneric_NonOpenAIUsesEditWriteDelete(t *testing.T)
 		coretools.ToolNameEdit,
 		coretools.ToolNameWrite,
 		coretools.ToolNameDelete,
+		coretools.ToolNameReadImage,
 		coretools.ToolNameShell,
 		coretools.ToolNameUpdatePlan,
 		spectools.ToolNameCheckSpecConformance,
@@ -220,6 +224,7 @@ func TestBuildRegistry_InvokePackageMode_OpenAIUsesPackagePromptAndTools(t *test
 		coretools.ToolNameReadFile,
 		coretools.ToolNameLS,
 		coretools.ToolNameApplyPatch,
+		coretools.ToolNameReadImage,
 		coretools.ToolNameSkillShell,
 		coretools.ToolNameUpdatePlan,
 		exttools.ToolNameDiagnostics,
@@ -228,6 +233,7 @@ func TestBuildRegistry_InvokePackageMode_OpenAIUsesPackagePromptAndTools(t *test
 		exttools.ToolNameRunProjectTests,
 		pkgtools.ToolNameModuleInfo,
 		pkgtools.ToolNameGetPublicAPI,
+		pkgtools.ToolNameDepDocs,
 		pkgtools.ToolNameClarifyPublicAPI,
 		pkgtools.ToolNameGetUsage,
 		pkgtools.ToolNameUpdateUsage,
@@ -244,6 +250,7 @@ func TestBuildRegistry_InvokePackageMode_NonOpenAIUsesEditWriteDelete(t *testing
 		coretools.ToolNameEdit,
 		coretools.ToolNameWrite,
 		coretools.ToolNameDelete,
+		coretools.ToolNameReadImage,
 		coretools.ToolNameSkillShell,
 		coretools.ToolNameUpdatePlan,
 		exttools.ToolNameDiagnostics,
@@ -252,6 +259,7 @@ func TestBuildRegistry_InvokePackageMode_NonOpenAIUsesEditWriteDelete(t *testing
 		exttools.ToolNameRunProjectTests,
 		pkgtools.ToolNameModuleInfo,
 		pkgtools.ToolNameGetPublicAPI,
+		pkgtools.ToolNameDepDocs,
 		pkgtools.ToolNameClarifyPublicAPI,
 		pkgtools.ToolNameGetUsage,
 		pkgtools.ToolNameUpdateUsage,
@@ -270,6 +278,7 @@ func TestBuildRegistry_InvokePackageModeDefaultContext_OpenAIUsesPackagePromptAn
 		coretools.ToolNameReadFile,
 		coretools.ToolNameLS,
 		coretools.ToolNameApplyPatch,
+		coretools.ToolNameReadImage,
 		coretools.ToolNameSkillShell,
 		coretools.ToolNameUpdatePlan,
 		exttools.ToolNameDiagnostics,
@@ -278,6 +287,7 @@ func TestBuildRegistry_InvokePackageModeDefaultContext_OpenAIUsesPackagePromptAn
 		exttools.ToolNameRunProjectTests,
 		pkgtools.ToolNameModuleInfo,
 		pkgtools.ToolNameGetPublicAPI,
+		pkgtools.ToolNameDepDocs,
 		pkgtools.ToolNameClarifyPublicAPI,
 		pkgtools.ToolNameGetUsage,
 		pkgtools.ToolNameUpdateUsage,
@@ -326,6 +336,7 @@ func TestBuildRegistry_InvokePROrchestrator_LoadsEmbeddedPromptAndTools(t *testi
 		coretools.ToolNameLS,
 		coretools.ToolNameShell,
 		coretools.ToolNameApplyPatch,
+		coretools.ToolNameReadImage,
 		coretools.ToolNameUpdatePlan,
 		spectools.ToolNameCheckSpecConformance,
 		"review",
@@ -351,6 +362,7 @@ func TestBuildRegistry_InvokePROrchestrator_UsesOptionalExternalToolOverrides(t
 		coretools.ToolNameLS,
 		coretools.ToolNameShell,
 		coretools.ToolNameApplyPatch,
+		coretools.ToolNameReadImage,
 		yamlOptionalToolCodalotlCLI,
 		yamlOptionalToolRefactor,
 		coretools.ToolNameUpdatePlan,
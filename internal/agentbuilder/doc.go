@@ -11,7 +11,9 @@
 //
 // OverrideTool installs a process-wide named tool builder that future BuildRegistry calls register before YAML agents are loaded.
 //
-// AddYAMLToRegistry loads YAML with top-level `agents` and `tools` arrays.
+// SetStartupYAML installs process-wide YAML files that future BuildRegistry calls load after the built-in agents.
+//
+// AddYAMLToRegistry loads YAML with top-level `agents`, `tools`, and `routing` arrays.
 //
 // Each `agents` entry includes:
 //   - `name`: agent name.
@@ -22,6 +24,9 @@
 //   - optional `include_package_mode_context`: only for package-mode agents; adds env plus initial package context.
 //   - optional `skills`: defaults to true; requires `shell` or `skill_shell`.
 //   - optional `agentsmd`: defaults to true; adds AGENTS.md initial-turn context from the sandbox or target package.
+//   - optional `model`: pins the agent to a known model, overriding the caller's model.
+//
+// Each `routing` entry sets `agent` and `model`, pinning an existing or same-file agent to a model without redefining it.
 //
 // Each `tools` entry includes:
 //   - `name`, `description`, and `parameters`.
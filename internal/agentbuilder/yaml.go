@@ -52,8 +52,15 @@ var embeddedYAMLData embed.FS
 
 // A yamlRegistrySpec is the top-level YAML registry document decoded from an agents and tools file.
 type yamlRegistrySpec struct {
-	Agents []yamlAgentSpec `yaml:"agents"` // Agents are the agent definitions to validate and register in file order.
-	Tools  []yamlToolSpec  `yaml:"tools"`  // Tools are the tool definitions to validate and register before agents.
+	Agents  []yamlAgentSpec `yaml:"agents"`  // Agents are the agent definitions to validate and register in file order.
+	Tools   []yamlToolSpec  `yaml:"tools"`   // Tools are the tool definitions to validate and register before agents.
+	Routing []yamlRouteSpec `yaml:"routing"` // Routing pins existing or newly defined agents to specific models.
+}
+
+// A yamlRouteSpec pins one agent (role) to a model, overriding the caller's model whenever the agent is prepared.
+type yamlRouteSpec struct {
+	Agent string `yaml:"agent"` // Agent is the name of an agent already in the registry or defined in the same file.
+	Model string `yaml:"model"` // Model is the llmmodel.ModelID the agent runs with.
 }
 
 // A yamlAgentSpec describes one agent loaded from YAML.
@@ -62,6 +69,7 @@ type yamlAgentSpec struct {
 	Prompts []yamlPromptRef `yaml:"prompts"` // Prompts are the ordered prompt blocks used to build the system prompt.
 	Tools   []string        `yaml:"tools"`   // Tools are the ordered tool names available to the agent.
 	Mode    string          `yaml:"mode"`    // Mode selects whether the agent runs in generic or package mode.
+	Model   string          `yaml:"model"`   // Model optionally pins the agent to a model, overriding the caller's model.
 
 	// IncludePackageModeContext adds environment and initial package context for package-mode agents.
 	IncludePackageModeContext bool `yaml:"include_package_mode_context"`
@@ -282,6 +290,11 @@ func addYAMLToRegistryFS(reg *agentregistry.Registry, yamlFS fs.FS, yamlPath str
 		preparedAgents = append(preparedAgents, prepared)
 	}
 
+	routedAgents, err := applyYAMLRouting(reg, spec.Routing, preparedAgents)
+	if err != nil {
+		return err
+	}
+
 	for _, toolSpec := range normalizedTools {
 		if err := reg.RegisterTool(toolSpec.Name, buildYAMLToolBuilder(toolSpec)); err != nil {
 			return err
@@ -292,10 +305,67 @@ func addYAMLToRegistryFS(reg *agentregistry.Registry, yamlFS fs.FS, yamlPath str
 			return err
 		}
 	}
+	for _, def := range routedAgents {
+		if err := reg.RegisterAgent(def); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// applyYAMLRouting validates routes and applies them. Routes to agents in preparedAgents update those definitions in place; routes to agents already in reg are
+// returned as updated definitions to re-register. reg is not mutated.
+func applyYAMLRouting(reg *agentregistry.Registry, routes []yamlRouteSpec, preparedAgents []yamlPreparedAgent) ([]agentregistry.Definition, error) {
+	preparedIndex := make(map[string]int, len(preparedAgents))
+	for i, prepared := range preparedAgents {
+		preparedIndex[prepared.Definition.Name] = i
+	}
+
+	routed := make(map[string]struct{}, len(routes))
+	var existing []agentregistry.Definition
+	for i, route := range routes {
+		if strings.TrimSpace(route.Agent) == "" {
+			return nil, fmt.Errorf("routing[%d]: agent is required", i)
+		}
+		if _, exists := routed[route.Agent]; exists {
+			return nil, fmt.Errorf("routing[%d]: agent %q is routed more than once", i, route.Agent)
+		}
+		modelID, err := validateYAMLModel(route.Model)
+		if err != nil {
+			return nil, fmt.Errorf("routing[%d]: %w", i, err)
+		}
+		routed[route.Agent] = struct{}{}
+
+		if idx, ok := preparedIndex[route.Agent]; ok {
+			if preparedAgents[idx].Definition.Model != "" {
+				return nil, fmt.Errorf("routing[%d]: agent %q already sets model", i, route.Agent)
+			}
+			preparedAgents[idx].Definition.Model = modelID
+			continue
+		}
+		def, ok := reg.Lookup(route.Agent)
+		if !ok {
+			return nil, fmt.Errorf("routing[%d]: unknown agent %q", i, route.Agent)
+		}
+		def.Model = modelID
+		existing = append(existing, def)
+	}
+	return existing, nil
+}
+
+// validateYAMLModel returns model as a ModelID, or an error if it is blank or unknown to llmmodel.
+func validateYAMLModel(model string) (llmmodel.ModelID, error) {
+	if strings.TrimSpace(model) == "" {
+		return "", errors.New("model is required")
+	}
+	modelID := llmmodel.ModelID(model)
+	if !modelID.Valid() {
+		return "", fmt.Errorf("unknown model %q", model)
+	}
+	return modelID, nil
+}
+
 func loadYAMLRegistrySpec(path string) (yamlRegistrySpec, string, error) {
 	if strings.TrimSpace(path) == "" {
 		return yamlRegistrySpec{}, "", errors.New("yaml path is required")
@@ -579,10 +649,19 @@ func prepareYAMLAgent(spec yamlAgentSpec, yamlFS fs.FS, yamlDir string, existing
 	}
 	enableAgentsMD := spec.AgentsMD == nil || *spec.AgentsMD
 
+	var modelID llmmodel.ModelID
+	if spec.Model != "" {
+		modelID, err = validateYAMLModel(spec.Model)
+		if err != nil {
+			return yamlPreparedAgent{}, err
+		}
+	}
+
 	prepared := yamlPreparedAgent{}
 	prepared.Definition = agentregistry.Definition{
 		Name:        spec.Name,
 		Description: "YAML-defined " + spec.Mode + " agent.",
+		Model:       modelID,
 		ToolsBuilder: func(opts toolsetinterface.Options) ([]string, error) {
 			return expandYAMLToolNames(resolvedToolNames, opts.Model), nil
 		},
@@ -41,6 +41,7 @@ func TestEmbeddedYAMLConfig_DefinesBuiltInAgents(t *testing.T) {
 		coretools.ToolNameReadFile,
 		coretools.ToolNameLS,
 		yamlToolVirtualEditFiles,
+		coretools.ToolNameReadImage,
 		yamlOptionalToolCodalotlCLI,
 		yamlOptionalToolRefactor,
 		coretools.ToolNameShell,
@@ -76,6 +77,7 @@ func TestEmbeddedYAMLConfig_DefinesBuiltInAgents(t *testing.T) {
 		coretools.ToolNameLS,
 		coretools.ToolNameShell,
 		yamlToolVirtualEditFiles,
+		coretools.ToolNameReadImage,
 		yamlOptionalToolCodalotlCLI,
 		yamlOptionalToolRefactor,
 		coretools.ToolNameUpdatePlan,
@@ -1408,6 +1410,7 @@ func TestBuildRegistry_YAMLBackedBuiltInAgentsPreserveToolsets(t *testing.T) {
 				coretools.ToolNameReadFile,
 				coretools.ToolNameLS,
 				coretools.ToolNameApplyPatch,
+				coretools.ToolNameReadImage,
 				coretools.ToolNameShell,
 				coretools.ToolNameUpdatePlan,
 				spectools.ToolNameCheckSpecConformance,
@@ -1421,6 +1424,7 @@ func TestBuildRegistry_YAMLBackedBuiltInAgentsPreserveToolsets(t *testing.T) {
 				coretools.ToolNameReadFile,
 				coretools.ToolNameLS,
 				coretools.ToolNameApplyPatch,
+				coretools.ToolNameReadImage,
 				coretools.ToolNameSkillShell,
 				coretools.ToolNameUpdatePlan,
 				"diagnostics",
@@ -1429,6 +1433,7 @@ func TestBuildRegistry_YAMLBackedBuiltInAgentsPreserveToolsets(t *testing.T) {
 				"run_project_tests",
 				"module_info",
 				"get_public_api",
+				"dep_docs",
 				"clarify_public_api",
 				"get_usage",
 				"update_usage",
@@ -1562,3 +1567,133 @@ func (t fakeNamedTool) Run(context.Context, llmstream.ToolCall) llmstream.ToolRe
 }
 
 var _ llmstream.Tool = fakeNamedTool{}
+
+func TestAddYAMLToRegistry_RoutingPinsAgentModels(t *testing.T) {
+	registry, err := BuildRegistry()
+	require.NoError(t, err)
+
+	yamlPath := filepath.Join(t.TempDir(), "routing.yaml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte(`
+agents:
+  - name: yaml_pinned
+    mode: generic
+    model: `+string(llmmodel.DefaultModel)+`
+    prompts:
+      - text: hi
+    tools:
+      - read_file
+    skills: false
+  - name: yaml_routed
+    mode: generic
+    prompts:
+      - text: hi
+    tools:
+      - read_file
+    skills: false
+routing:
+  - agent: `+AgentLimitedPackageMode+`
+    model: `+string(llmmodel.DefaultModel)+`
+  - agent: yaml_routed
+    model: `+string(llmmodel.DefaultModel)+`
+`), 0o644))
+
+	require.NoError(t, AddYAMLToRegistry(registry, yamlPath))
+
+	for _, name := range []string{"yaml_pinned", "yaml_routed", AgentLimitedPackageMode} {
+		def, ok := registry.Lookup(name)
+		require.True(t, ok, name)
+		assert.Equal(t, llmmodel.DefaultModel, def.Model, name)
+	}
+	def, ok := registry.Lookup(AgentGeneric)
+	require.True(t, ok)
+	assert.Empty(t, def.Model)
+}
+
+func TestAddYAMLToRegistry_InvalidRoutingDoesNotMutateRegistry(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "unknown model",
+			yaml: `
+routing:
+  - agent: generic
+    model: not-a-real-model
+`,
+			wantErr: `unknown model "not-a-real-model"`,
+		},
+		{
+			name: "unknown agent",
+			yaml: `
+routing:
+  - agent: does_not_exist
+    model: ` + string(llmmodel.DefaultModel) + `
+`,
+			wantErr: `unknown agent "does_not_exist"`,
+		},
+		{
+			name: "duplicate route",
+			yaml: `
+routing:
+  - agent: generic
+    model: ` + string(llmmodel.DefaultModel) + `
+  - agent: generic
+    model: ` + string(llmmodel.DefaultModel) + `
+`,
+			wantErr: `routed more than once`,
+		},
+		{
+			name: "agent model",
+			yaml: `
+agents:
+  - name: bad_model
+    mode: generic
+    model: not-a-real-model
+    prompts:
+      - text: hi
+    tools:
+      - read_file
+    skills: false
+`,
+			wantErr: `unknown model "not-a-real-model"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, err := BuildRegistry()
+			require.NoError(t, err)
+
+			yamlPath := filepath.Join(t.TempDir(), "bad.yaml")
+			require.NoError(t, os.WriteFile(yamlPath, []byte(tt.yaml), 0o644))
+
+			err = AddYAMLToRegistry(registry, yamlPath)
+			require.ErrorContains(t, err, tt.wantErr)
+
+			def, ok := registry.Lookup(AgentGeneric)
+			require.True(t, ok)
+			assert.Empty(t, def.Model)
+			_, ok = registry.Lookup("bad_model")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestBuildRegistry_LoadsStartupYAML(t *testing.T) {
+	yamlPath := filepath.Join(t.TempDir(), "agents.yml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte(`
+routing:
+  - agent: `+agentClarifyPublicAPI+`
+    model: `+string(llmmodel.DefaultModel)+`
+`), 0o644))
+
+	SetStartupYAML([]string{yamlPath})
+	t.Cleanup(func() { SetStartupYAML(nil) })
+
+	registry, err := BuildRegistry()
+	require.NoError(t, err)
+	def, ok := registry.Lookup(agentClarifyPublicAPI)
+	require.True(t, ok)
+	assert.Equal(t, llmmodel.DefaultModel, def.Model)
+}
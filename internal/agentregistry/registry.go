@@ -8,6 +8,7 @@ import (
 	"sort"
 
 	"github.com/codalotl/codalotl/internal/agent"
+	"github.com/codalotl/codalotl/internal/llmmodel"
 	"github.com/codalotl/codalotl/internal/llmstream"
 	"github.com/codalotl/codalotl/internal/tools/authdomain"
 	"github.com/codalotl/codalotl/internal/tools/toolsetinterface"
@@ -148,6 +149,10 @@ type Definition struct {
 
 	// AuthPolicy indicates how auth and package scoping are derived.
 	AuthPolicy AuthPolicy
+
+	// Model, if set, overrides the caller's model (InvokeRequest.ToolOptions.Model) whenever this agent is prepared. This enables role-based routing, ex: a cheaper
+	// model for read-only clarification subagents. Tools built for the agent (and subagents they invoke) see the routed model.
+	Model llmmodel.ModelID
 }
 
 func cloneDefinition(def Definition) Definition {
@@ -168,6 +173,33 @@ func (d Definition) Validate() error {
 	return nil
 }
 
+// RestrictTools limits the prepared agent to tools named in allowed, preserving order. Names in allowed that the agent does not have are ignored, so callers
+// may list alternatives (ex: model-specific edit tools). A nil allowed leaves the tools unchanged; an empty non-nil allowed removes all tools.
+func (p *PreparedAgent) RestrictTools(allowed []string) {
+	if p == nil || allowed == nil {
+		return
+	}
+	allowedSet := make(map[string]struct{}, len(allowed))
+	for _, name := range allowed {
+		allowedSet[name] = struct{}{}
+	}
+
+	toolNames := make([]string, 0, len(p.ToolNames))
+	for _, name := range p.ToolNames {
+		if _, ok := allowedSet[name]; ok {
+			toolNames = append(toolNames, name)
+		}
+	}
+	tools := make([]llmstream.Tool, 0, len(p.tools))
+	for _, tool := range p.tools {
+		if _, ok := allowedSet[tool.Name()]; ok {
+			tools = append(tools, tool)
+		}
+	}
+	p.ToolNames = toolNames
+	p.tools = tools
+}
+
 // Create constructs an idle agent from the prepared configuration.
 //
 // Create delegates construction to agentCreator.New. If BuildOptions.ToolOptions.Model is set, that model is passed in agent.NewOptions; otherwise no agent.NewOptions
@@ -284,6 +316,9 @@ func (r *Registry) Prepare(ctx context.Context, agentName string, req toolsetint
 		return nil, err
 	}
 
+	if def.Model != "" {
+		effectiveOpts.Model = def.Model
+	}
 	effectiveOpts.AgentInvoker = r
 	effectiveOpts.AgentName = agentName
 
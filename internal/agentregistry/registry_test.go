@@ -262,6 +262,29 @@ func TestPreparedAgent_Create(t *testing.T) {
 	})
 }
 
+func TestPreparedAgent_RestrictTools(t *testing.T) {
+	newPrepared := func() *PreparedAgent {
+		return &PreparedAgent{
+			ToolNames: []string{"read_file", "ls", "shell"},
+			tools:     []llmstream.Tool{stubTool{name: "read_file"}, stubTool{name: "ls"}, stubTool{name: "shell"}},
+		}
+	}
+
+	prepared := newPrepared()
+	prepared.RestrictTools([]string{"shell", "read_file", "apply_patch"})
+	assert.Equal(t, []string{"read_file", "shell"}, prepared.ToolNames)
+	assert.Equal(t, []llmstream.Tool{stubTool{name: "read_file"}, stubTool{name: "shell"}}, prepared.tools)
+
+	prepared = newPrepared()
+	prepared.RestrictTools(nil)
+	assert.Equal(t, []string{"read_file", "ls", "shell"}, prepared.ToolNames)
+
+	prepared = newPrepared()
+	prepared.RestrictTools([]string{})
+	assert.Empty(t, prepared.ToolNames)
+	assert.Empty(t, prepared.tools)
+}
+
 func TestRegistry_PrepareAndCreate(t *testing.T) {
 	r := NewRegistry()
 
@@ -341,6 +364,45 @@ func TestRegistry_PrepareAndCreate(t *testing.T) {
 	})
 }
 
+func TestRegistry_PrepareUsesDefinitionModel(t *testing.T) {
+	r := NewRegistry()
+
+	var toolModel llmmodel.ModelID
+	require.NoError(t, r.RegisterTool("routed-tool", func(opts toolsetinterface.Options) (llmstream.Tool, error) {
+		toolModel = opts.Model
+		return stubTool{name: "routed-tool"}, nil
+	}))
+	require.NoError(t, r.RegisterAgent(Definition{
+		Name:         "routed-agent",
+		SystemPrompt: "prompt",
+		ToolNames:    []string{"routed-tool"},
+		Model:        "routed-model",
+	}))
+	require.NoError(t, r.RegisterAgent(Definition{
+		Name:         "unrouted-agent",
+		SystemPrompt: "prompt",
+		ToolNames:    []string{"routed-tool"},
+	}))
+
+	req := toolsetinterface.InvokeRequest{
+		ToolOptions: toolsetinterface.Options{
+			Model:      "caller-model",
+			Authorizer: authdomain.NewAutoApproveAuthorizer("/sandbox"),
+			SandboxDir: "/sandbox",
+		},
+	}
+
+	prepared, err := r.Prepare(context.Background(), "routed-agent", req)
+	require.NoError(t, err)
+	assert.Equal(t, llmmodel.ModelID("routed-model"), prepared.BuildOptions.ToolOptions.Model)
+	assert.Equal(t, llmmodel.ModelID("routed-model"), toolModel)
+
+	prepared, err = r.Prepare(context.Background(), "unrouted-agent", req)
+	require.NoError(t, err)
+	assert.Equal(t, llmmodel.ModelID("caller-model"), prepared.BuildOptions.ToolOptions.Model)
+	assert.Equal(t, llmmodel.ModelID("caller-model"), toolModel)
+}
+
 func TestRegistry_Invoke(t *testing.T) {
 	r := NewRegistry()
 
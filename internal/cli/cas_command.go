@@ -68,6 +68,11 @@ func registeredCASNamespaceSpecs() []gocas.NamespaceSpec {
 		casconformance.NamespaceSpec,
 		casclarify.NamespaceSpec,
 		docsFixCASNamespaceSpec,
+		docsImproveCASNamespaceSpec,
+		docsPolishCASNamespaceSpec,
+		docsExamplesCASNamespaceSpec,
+		docsPackageDocCASNamespaceSpec,
+		specTraceCASNamespaceSpec,
 	}
 	specs = append(specs, toolrefactor.CASNamespaceSpecs()...)
 	return specs
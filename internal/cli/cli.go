@@ -41,6 +41,7 @@ func Run(args []string, opts *RunOptions) (int, error) {
 	}
 
 	installAgentToolOverrides()
+	installAgentYAML()
 	root, runState := newRootCommand(!hasHelpFlag(argv))
 
 	var in io.Reader = os.Stdin
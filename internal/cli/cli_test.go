@@ -198,8 +198,13 @@ func TestCommandMetadata_ToolFacingCommands(t *testing.T) {
 		{"auth", "openai", "status"},
 		{"pr", "new"},
 		{"pr", "refactor"},
+		{"pr", "status"},
+		{"pr", "finish"},
+		{"pr", "export"},
 		{"spec", "fmt"},
 		{"spec", "diff"},
+		{"spec", "coverage"},
+		{"spec", "trace"},
 		{"spec", "ls-mismatch"},
 		{"spec", "status"},
 		{"cas", "get"},
@@ -320,6 +325,32 @@ func TestRun_Exec_AcceptsModelFlag(t *testing.T) {
 	}
 }
 
+func TestRun_Exec_ImageFlagsForwardImagePathsAndAllowEmptyPrompt(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	chdirForTest(t, tmp)
+
+	origRunNoninteractiveExec := runNoninteractiveExec
+	t.Cleanup(func() { runNoninteractiveExec = origRunNoninteractiveExec })
+
+	var gotPrompt string
+	var gotOpts noninteractive.Options
+	runNoninteractiveExec = func(userPrompt string, opts noninteractive.Options) error {
+		gotPrompt = userPrompt
+		gotOpts = opts
+		return nil
+	}
+
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "exec", "--image", "a.png", "-i", "b.jpg"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Empty(t, gotPrompt)
+	require.Equal(t, []string{"a.png", "b.jpg"}, gotOpts.ImagePaths)
+}
+
 func TestRun_Exec_JSONFlagSetsOutputJSON(t *testing.T) {
 	isolateUserConfig(t)
 
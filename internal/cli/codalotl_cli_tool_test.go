@@ -89,6 +89,10 @@ func TestCodalotlCLITool_OnlyExposesWhitelistedCommands(t *testing.T) {
 	require.Equal(t, 0, helpResult.ExitCode)
 	require.Contains(t, helpResult.Stdout, "codalotl docs add")
 	require.Contains(t, helpResult.Stdout, "codalotl docs fix")
+	require.Contains(t, helpResult.Stdout, "codalotl docs improve")
+	require.Contains(t, helpResult.Stdout, "codalotl docs polish")
+	require.Contains(t, helpResult.Stdout, "codalotl docs package-doc")
+	require.Contains(t, helpResult.Stdout, "codalotl docs examples")
 	require.Contains(t, helpResult.Stdout, "codalotl docs status")
 	require.Contains(t, helpResult.Stdout, "codalotl spec status")
 	require.Contains(t, helpResult.Stdout, "codalotl cas ls-packages")
@@ -135,7 +139,7 @@ func TestCodalotlCLITool_OnlyExposesWhitelistedCommands(t *testing.T) {
 	require.True(t, docsStatusHelp.Success)
 	require.Equal(t, 0, docsStatusHelp.ExitCode)
 	require.Contains(t, docsStatusHelp.Stdout, "codalotl docs status")
-	require.Contains(t, docsStatusHelp.Stdout, "docs-add, docs-fix, and documentation reflow status")
+	require.Contains(t, docsStatusHelp.Stdout, "docs-add, docs-fix, docs-improve, docs-polish, example coverage, and documentation reflow status")
 
 	statusHelp := decodeCodalotlCLIToolResult(t, tool.Run(context.Background(), llmstream.ToolCall{
 		CallID: "call-spec-status-help",
@@ -24,6 +24,7 @@ import (
 	"github.com/codalotl/codalotl/internal/llmstream"
 	"github.com/codalotl/codalotl/internal/noninteractive"
 	qcas "github.com/codalotl/codalotl/internal/q/cas"
+	"github.com/codalotl/codalotl/internal/q/cascade"
 	qcli "github.com/codalotl/codalotl/internal/q/cli"
 	"github.com/codalotl/codalotl/internal/q/health"
 	"github.com/codalotl/codalotl/internal/q/remotemonitor"
@@ -75,6 +76,26 @@ func (s *startupState) validate(ctx context.Context, cfg Config, selectedModels
 	return s.err
 }
 
+// installAgentYAML makes BuildRegistry load the user's global agent file (~/.codalotl/agents.yml) and then the nearest project agent file (.codalotl/agents.yml,
+// searching upward from the working directory), when they exist. Project files load last, so their routing wins.
+func installAgentYAML() {
+	var paths []string
+	if p := cascade.ExpandPath("~/.codalotl/agents.yml"); fileExists(p) {
+		paths = append(paths, p)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if p := cascade.NearestFile(filepath.Join(".codalotl", "agents.yml"), wd); p != "" && (len(paths) == 0 || p != paths[0]) {
+			paths = append(paths, p)
+		}
+	}
+	agentbuilder.SetStartupYAML(paths)
+}
+
+func fileExists(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.Mode().IsRegular()
+}
+
 func installAgentToolOverrides() {
 	agentbuilder.OverrideTool(toolcli.ToolNameCodalotlCLI, func(toolsetinterface.Options) (llmstream.Tool, error) {
 		return toolcli.NewCodalotlCLITool(newCodalotlCLICommandTree), nil
@@ -129,6 +150,9 @@ func newCLIRunWithConfig(loadConfigForRuns bool) (runWithConfigFunc, *cliRunStat
 			if err != nil {
 				return qcli.ExitError{Code: 1, Err: err}
 			}
+			if err := writeConfigWarnings(c.Err, cfg); err != nil {
+				return err
+			}
 
 			m := ensureMonitor(cfg)
 			runState.setEvent(event)
@@ -183,6 +207,9 @@ func newCLIRunWithConfigNoStartup(loadConfigForRuns bool, runState *cliRunState)
 			if err != nil {
 				return qcli.ExitError{Code: 1, Err: err}
 			}
+			if err := writeConfigWarnings(c.Err, cfg); err != nil {
+				return err
+			}
 
 			m := ensureMonitor(cfg)
 			runState.setEvent(event)
@@ -260,13 +287,14 @@ func newRootCommand(loadConfigForRuns bool) (*qcli.Command, *cliRunState) {
 		ArgHelp: []qcli.ArgHelp{
 			{
 				Display:     "[<prompt> ...]",
-				Description: "User message to send to the agent. Required unless --slash-command starts a session that can run without an initial message.",
+				Description: "User message to send to the agent. Required unless --image is given or --slash-command starts a session that can run without an initial message.",
 			},
 		},
 		Example: strings.TrimSpace(`
 codalotl exec "Summarize this repository"
 codalotl exec --package internal/cli "Explain the CLI commands"
 codalotl exec --yes --slash-command=orchestrate "Plan this refactor"
+codalotl exec --image screenshot.png "Why does this layout overflow?"
 `),
 	}
 	execFlags := execCmd.Flags()
@@ -275,12 +303,18 @@ codalotl exec --yes --slash-command=orchestrate "Plan this refactor"
 	execNoColor := execFlags.Bool("no-color", 0, false, "Disable ANSI colors and formatting.")
 	execJSON := execFlags.Bool("json", 0, false, "Output newline-delimited JSON.")
 	execModel := execFlags.String("model", 0, "", "LLM model ID to use (overrides config preferredmodel; empty = default).")
-	execSlashCommand := execFlags.String("slash-command", 0, "", "Apply a TUI-style slash command at session start (supported: orchestrate, /orchestrate).")
+	execSlashCommand := execFlags.String("slash-command", 0, "", "Apply a TUI-style slash command at session start (orchestrate, or a custom command from .codalotl/commands).")
+	execImages := execFlags.StringSlice("image", 'i', nil, "Attach an image file (PNG, JPEG, or WebP) to the prompt. Repeatable.")
+	execFlags.SetComplete("package", completePackageDirs)
+	execFlags.SetComplete("model", completeModelIDs)
+	execFlags.SetComplete("image", completeImageFiles)
 	execArgs := qcli.MinimumArgs(1)
 	execCmd.Args = func(args []string) error {
 		if len(args) == 0 {
-			switch strings.TrimSpace(*execSlashCommand) {
-			case "orchestrate", "/orchestrate":
+			if len(*execImages) > 0 {
+				return nil
+			}
+			if slashCommandAllowsEmptyInitialPrompt(*execSlashCommand) {
 				return nil
 			}
 		}
@@ -311,7 +345,7 @@ codalotl exec --yes --slash-command=orchestrate "Plan this refactor"
 		}
 
 		packagePath := strings.TrimSpace(*execPackage)
-		if packagePath != "" && !slashCommandAllowsEmptyInitialPrompt(slashCommand) {
+		if packagePath != "" && !isOrchestrateSlashCommand(slashCommand) {
 			packagePath, err = resolvePackagePathInsideCWD(packagePath)
 			if err != nil {
 				return err
@@ -323,6 +357,7 @@ codalotl exec --yes --slash-command=orchestrate "Plan this refactor"
 			SlashCommand: slashCommand,
 			ModelID:      modelID,
 			LintSteps:    steps,
+			ImagePaths:   *execImages,
 			AutoYes:      cfg.AutoYes || *execYes,
 			NoFormatting: *execNoColor,
 			OutputJSON:   *execJSON,
@@ -376,27 +411,12 @@ codalotl version
 		},
 	}
 
-	configCmd := &qcli.Command{
-		Name:             "config",
-		Short:            "Print codalotl configuration.",
-		Long:             "Prints the effective codalotl configuration, redacting provider keys and showing config locations, effective model, and provider API key environment variables.",
-		Args:             qcli.NoArgs,
-		NoPositionalArgs: true,
-		Example: strings.TrimSpace(`
-codalotl config
-`),
-		Run: runWithConfig("config", func(c *qcli.Context, cfg Config, m *remotemonitor.Monitor) error {
-			if err := maybeWriteUpdateNotice(c.Out, m, Version, defaultNoticeWaitTimeout); err != nil {
-				return err
-			}
-			return writeConfig(c.Out, cfg)
-		}),
-	}
+	configCmd := newConfigCommand(runWithConfig, runWithConfigNoStartup)
 
 	specCmd := &qcli.Command{
 		Name:  "spec",
 		Short: "SPEC.md tools.",
-		Long:  "Commands for formatting, comparing, and reporting package SPEC.md files.",
+		Long:  "Commands for drafting, implementing, formatting, comparing, and reporting package SPEC.md files.",
 	}
 	fmtCmd := &qcli.Command{
 		Name:  "fmt",
@@ -409,6 +429,7 @@ codalotl config
 				Description: specPathArgDescription,
 			},
 		},
+		Complete: completeArgs(completePackageDirsOrSpecs),
 		Example: strings.TrimSpace(`
 codalotl spec fmt internal/mypkg
 codalotl spec fmt internal/mypkg/SPEC.md
@@ -448,6 +469,7 @@ codalotl spec fmt internal/mypkg/SPEC.md
 				Description: specPathArgDescription,
 			},
 		},
+		Complete: completeArgs(completePackageDirsOrSpecs),
 		Example: strings.TrimSpace(`
 codalotl spec diff internal/mypkg
 codalotl spec diff internal/mypkg/SPEC.md
@@ -472,6 +494,39 @@ codalotl spec diff internal/mypkg/SPEC.md
 			return specmd.FormatDiffs(diffs, c.Out)
 		}),
 	}
+	coverageCmd := &qcli.Command{
+		Name:  "coverage",
+		Short: "Print exported APIs missing from SPEC.md.",
+		Long:  "Lists exported identifiers of the package (declarations, methods, struct fields, and interface methods) that the SPEC.md Public API doesn't declare, with their locations. Prints nothing when every exported identifier is declared.",
+		Usage: "<path/to/pkg_or_SPEC.md>",
+		ArgHelp: []qcli.ArgHelp{
+			{
+				Display:     "<path/to/pkg_or_SPEC.md>",
+				Description: specPathArgDescription,
+			},
+		},
+		Complete: completeArgs(completePackageDirsOrSpecs),
+		Example: strings.TrimSpace(`
+codalotl spec coverage internal/mypkg
+codalotl spec coverage internal/mypkg/SPEC.md
+`),
+		Args: qcli.ExactArgs(1),
+		Run: runWithConfig("spec_coverage", func(c *qcli.Context, _ Config, _ *remotemonitor.Monitor) error {
+			specPath, err := resolveSpecPathArg(c.Args[0])
+			if err != nil {
+				return err
+			}
+			spec, err := specmd.Read(specPath)
+			if err != nil {
+				return err
+			}
+			coverage, err := spec.APICoverage()
+			if err != nil {
+				return err
+			}
+			return specmd.FormatUncoveredAPIs(coverage.Uncovered, c.Out)
+		}),
+	}
 	lsMismatchCmd := &qcli.Command{
 		Name:  "ls-mismatch",
 		Short: "List packages where SPEC.md differs from the implementation.",
@@ -483,6 +538,7 @@ codalotl spec diff internal/mypkg/SPEC.md
 				Description: "Go package pattern to scan, such as ./... or ./internal/...",
 			},
 		},
+		Complete: completeArgs(completePackageDirs),
 		Example: strings.TrimSpace(`
 codalotl spec ls-mismatch ./...
 codalotl spec ls-mismatch ./internal/...
@@ -492,7 +548,7 @@ codalotl spec ls-mismatch ./internal/...
 			return runSpecLsMismatch(c.Context, c.Out, c.Args[0])
 		}),
 	}
-	specCmd.AddCommand(fmtCmd, diffCmd, lsMismatchCmd, newSpecStatusCommand(runWithConfig))
+	specCmd.AddCommand(newSpecInitCommand(runWithConfig), newSpecImplementCommand(runWithConfig), fmtCmd, diffCmd, coverageCmd, lsMismatchCmd, newSpecStatusCommand(runWithConfig), newSpecTraceCommand(runWithConfig))
 	casCmd := &qcli.Command{
 		Name:  "cas",
 		Short: "Content-addressable metadata storage (CAS).",
@@ -513,6 +569,7 @@ codalotl spec ls-mismatch ./internal/...
 				Description: packagePathArgDescription,
 			},
 		},
+		Complete: completeArgs(completeCASNamespaces, completePackageDirs),
 		Example: strings.TrimSpace(`
 codalotl cas get specconforms internal/mypkg
 `),
@@ -613,6 +670,7 @@ codalotl cas prune --days=14
 				Description: packagePathArgDescription,
 			},
 		},
+		Complete: completeArgs(completePackageDirs),
 		Example: strings.TrimSpace(`
 codalotl context public internal/cli
 codalotl context public ./internal/cli
@@ -642,6 +700,7 @@ codalotl context public ./internal/cli
 				Description: packagePathArgDescription,
 			},
 		},
+		Complete: completeArgs(completePackageDirs),
 		Example: strings.TrimSpace(`
 codalotl context initial internal/cli
 codalotl context initial ./internal/cli
@@ -694,11 +753,13 @@ codalotl context packages --deps
 	})
 
 	contextCmd.AddCommand(publicCmd, initialCmd, packagesCmd)
-	root.AddCommand(execCmd, iterateCmd, contextCmd, versionCmd, configCmd, newAuthCommand(runWithConfigNoStartup), newPRCommand(), newDocsCommand(runWithConfig, true), specCmd, casCmd, panicCmd)
+	docsCmd := newDocsCommand(runWithConfig, true)
+	docsCmd.AddCommand(newDocsCheckCommand(runWithConfigNoStartup))
+	root.AddCommand(execCmd, iterateCmd, contextCmd, versionCmd, configCmd, newAuthCommand(runWithConfigNoStartup), newPermissionsCommand(runWithConfigNoStartup), newPRCommand(runWithConfigNoStartup), docsCmd, specCmd, casCmd, panicCmd, newCompletionCommand(root), newHelpCommand(root))
 	return root, runState
 }
 
-// newCodalotlCLICommandTree builds the whitelisted in-process codalotl command tree exposed to agent tools. The returned tree includes docs add/fix/status, spec
+// newCodalotlCLICommandTree builds the whitelisted in-process codalotl command tree exposed to agent tools. The returned tree includes docs add/fix/improve/polish/status, spec
 // status, and CAS ls-packages/recertify commands, and uses normal configuration loading and startup validation.
 func newCodalotlCLICommandTree() *qcli.Command {
 	runWithConfig, _ := newCLIRunWithConfig(true)
@@ -755,6 +816,7 @@ func newCASLsPackagesCommand(runWithConfig runWithConfigFunc) *qcli.Command {
 				Description: "Registered non-versioned namespace name.",
 			},
 		},
+		Complete: completeArgs(completeCASNamespaces),
 		Example: strings.TrimSpace(`
 codalotl cas ls-packages specconforms
 codalotl cas ls-packages specconforms --state=outdated
@@ -799,11 +861,13 @@ func newCASRecertifyCommand(runWithConfig runWithConfigFunc) *qcli.Command {
 				Description: packagePathArgDescription,
 			},
 		},
+		Complete: completeArgs(completePackageDirs),
 		Example: strings.TrimSpace(`
 codalotl cas recertify internal/mypkg --namespaces="docs-fix,specconforms"
 `),
 	}
 	recertifyNamespaces := recertifyCmd.Flags().String("namespaces", 0, "", "Comma-separated registered non-versioned namespace names to recertify.")
+	recertifyCmd.Flags().SetComplete("namespaces", completeCASNamespaceList)
 	recertifyCmd.Args = func(args []string) error {
 		if err := qcli.ExactArgs(1)(args); err != nil {
 			return err
@@ -823,11 +887,15 @@ func newDocsCommand(runWithConfig runWithConfigFunc, includeReflow bool) *qcli.C
 	docsCmd := &qcli.Command{
 		Name:  "docs",
 		Short: "Documentation tools.",
-		Long:  "Commands for adding, fixing, or reflowing Go documentation comments.",
+		Long:  "Commands for adding, fixing, improving, polishing, or reflowing Go documentation comments, and for generating runnable examples.",
 	}
 	children := []*qcli.Command{
 		newDocsAddCommand(runWithConfig),
 		newDocsFixCommand(runWithConfig),
+		newDocsImproveCommand(runWithConfig),
+		newDocsPolishCommand(runWithConfig),
+		newDocsPackageDocCommand(runWithConfig),
+		newDocsExamplesCommand(runWithConfig),
 		newDocsStatusCommand(runWithConfig),
 	}
 	if includeReflow {
@@ -852,6 +920,7 @@ func newDocsAddCommand(runWithConfig runWithConfigFunc) *qcli.Command {
 				Description: packagePathArgDescription,
 			},
 		},
+		Complete: completeArgs(completePackageDirs),
 		Example: strings.TrimSpace(`
 codalotl docs add internal/mypkg
 codalotl docs add --public-only internal/mypkg
@@ -911,6 +980,7 @@ func newDocsReflowCommand(runWithConfig runWithConfigFunc) *qcli.Command {
 				Description: "One or more Go source files or directories to reflow.",
 			},
 		},
+		Complete: completeGoPaths,
 		Example: strings.TrimSpace(`
 codalotl docs reflow internal/mypkg
 codalotl docs reflow --width=100 --check internal/mypkg pkg/foo.go
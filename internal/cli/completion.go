@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+)
+
+// newCompletionCommand builds `codalotl completion <shell>`, which prints a shell completion script for root.
+func newCompletionCommand(root *qcli.Command) *qcli.Command {
+	return &qcli.Command{
+		Name:  "completion",
+		Short: "Print a shell completion script.",
+		Long: "Prints a completion script for bash, zsh, or fish. The script asks codalotl itself for candidates, so completions for commands, flags, package paths, " +
+			"CAS namespaces, model IDs, and refactor names always match the installed version.",
+		Usage: "<shell>",
+		ArgHelp: []qcli.ArgHelp{
+			{
+				Display:     "<shell>",
+				Description: "Shell to print a script for: " + strings.Join(qcli.CompletionShells(), ", ") + ".",
+			},
+		},
+		Example: strings.TrimSpace(`
+source <(codalotl completion bash)
+source <(codalotl completion zsh)
+codalotl completion fish | source
+`),
+		Args: qcli.ExactArgs(1),
+		Complete: func(args []string, _ string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			return qcli.CompletionShells()
+		},
+		Run: func(c *qcli.Context) error {
+			if err := qcli.WriteCompletionScript(c.Out, root, c.Args[0]); err != nil {
+				return qcli.UsageError{Message: err.Error()}
+			}
+			return nil
+		},
+	}
+}
+
+// newHelpCommand builds `codalotl help [command ...]`, which prints help, Markdown, or a man page for a command in root's tree.
+func newHelpCommand(root *qcli.Command) *qcli.Command {
+	helpCmd := &qcli.Command{
+		Name:  "help",
+		Short: "Print help, Markdown docs, or a man page for a command.",
+		Long: "Prints help for the given command (default: codalotl). With --markdown or --man, prints reference documentation for the command and all of its " +
+			"subcommands instead.",
+		Usage: "[command ...]",
+		ArgHelp: []qcli.ArgHelp{
+			{
+				Display:     "[command ...]",
+				Description: "Command path, such as `cas` or `docs add`.",
+			},
+		},
+		Example: strings.TrimSpace(`
+codalotl help spec
+codalotl help --markdown > codalotl.md
+codalotl help --man > codalotl.1
+`),
+	}
+	flags := helpCmd.Flags()
+	markdown := flags.Bool("markdown", 0, false, "Print Markdown reference documentation.")
+	man := flags.Bool("man", 0, false, "Print a roff man page.")
+	helpCmd.Args = func(args []string) error {
+		if *markdown && *man {
+			return qcli.UsageError{Message: "--markdown and --man are mutually exclusive"}
+		}
+		_, err := resolveHelpCommand(root, args)
+		return err
+	}
+	helpCmd.Complete = func(args []string, _ string) []string {
+		cmd, err := resolveHelpCommand(root, args)
+		if err != nil {
+			return nil
+		}
+		var names []string
+		for _, child := range cmd.Commands() {
+			if !child.Hidden {
+				names = append(names, child.Name)
+			}
+		}
+		return names
+	}
+	helpCmd.Run = func(c *qcli.Context) error {
+		cmd, err := resolveHelpCommand(root, c.Args)
+		if err != nil {
+			return err
+		}
+		switch {
+		case *markdown:
+			qcli.WriteMarkdown(c.Out, root, cmd)
+		case *man:
+			qcli.WriteManPage(c.Out, root, cmd, qcli.ManPageOptions{Source: "codalotl " + Version, Manual: "codalotl Manual"})
+		default:
+			qcli.WriteHelp(c.Out, root, cmd, qcli.HelpOptions{})
+		}
+		return nil
+	}
+	return helpCmd
+}
+
+// resolveHelpCommand returns the command named by path (names or aliases, starting below root). It returns a UsageError for unknown commands.
+func resolveHelpCommand(root *qcli.Command, path []string) (*qcli.Command, error) {
+	cmd := root
+	for _, token := range path {
+		var next *qcli.Command
+		for _, child := range cmd.Commands() {
+			if child.Name == token {
+				next = child
+				break
+			}
+			for _, alias := range child.Aliases {
+				if alias == token {
+					next = child
+				}
+			}
+		}
+		if next == nil {
+			return nil, qcli.UsageError{Message: fmt.Sprintf("unknown command: %s", strings.Join(path, " "))}
+		}
+		cmd = next
+	}
+	return cmd, nil
+}
+
+// completeArgs returns a CompleteFunc that completes the Nth positional arg with completers[N]. Args past the last completer, or with a nil completer, aren't
+// completed.
+func completeArgs(completers ...qcli.CompleteFunc) qcli.CompleteFunc {
+	return func(args []string, toComplete string) []string {
+		if len(args) >= len(completers) || completers[len(args)] == nil {
+			return nil
+		}
+		return completers[len(args)](args, toComplete)
+	}
+}
+
+func completePackageDirs(_ []string, toComplete string) []string {
+	return completePaths(toComplete, nil)
+}
+
+func completePackageDirsOrSpecs(_ []string, toComplete string) []string {
+	return completePaths(toComplete, func(name string) bool { return name == "SPEC.md" })
+}
+
+func completeMarkdownFiles(_ []string, toComplete string) []string {
+	return completePaths(toComplete, func(name string) bool { return strings.HasSuffix(name, ".md") })
+}
+
+func completeGoPaths(_ []string, toComplete string) []string {
+	return completePaths(toComplete, func(name string) bool { return strings.HasSuffix(name, ".go") })
+}
+
+func completeImageFiles(_ []string, toComplete string) []string {
+	return completePaths(toComplete, func(name string) bool {
+		switch strings.ToLower(path.Ext(name)) {
+		case ".png", ".jpg", ".jpeg", ".webp":
+			return true
+		}
+		return false
+	})
+}
+
+// completePaths returns the directories (with a trailing "/") and the files accepted by includeFile (nil accepts none) in the directory part of toComplete, keeping
+// that directory part as typed. Hidden entries are only returned when toComplete's last element starts with ".".
+func completePaths(toComplete string, includeFile func(name string) bool) []string {
+	dir, base := path.Split(toComplete)
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".") {
+			continue
+		}
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			if info, err := os.Stat(path.Join(readDir, name)); err == nil {
+				isDir = info.IsDir()
+			}
+		}
+		switch {
+		case isDir:
+			out = append(out, dir+name+"/")
+		case includeFile != nil && includeFile(name):
+			out = append(out, dir+name)
+		}
+	}
+	return out
+}
+
+// completeCASNamespaces returns the registered CAS namespace names.
+func completeCASNamespaces(_ []string, _ string) []string {
+	var names []string
+	for _, spec := range sortedCASNamespaceSpecs() {
+		if len(names) == 0 || names[len(names)-1] != spec.Name {
+			names = append(names, spec.Name)
+		}
+	}
+	return names
+}
+
+// completeCASNamespaceList completes the last element of a comma-separated namespace list, omitting namespaces already in the list.
+func completeCASNamespaceList(args []string, toComplete string) []string {
+	prefix := ""
+	var listed []string
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+		listed = strings.Split(toComplete[:i], ",")
+	}
+	var out []string
+	for _, name := range completeCASNamespaces(args, toComplete) {
+		if !containsString(listed, name) {
+			out = append(out, prefix+name)
+		}
+	}
+	return out
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// completeModelIDs returns the available LLM model IDs.
+func completeModelIDs(_ []string, _ string) []string {
+	var ids []string
+	for _, id := range llmmodel.AvailableModelIDs() {
+		ids = append(ids, string(id))
+	}
+	return ids
+}
+
+func completePRRefactors(_ []string, _ string) []string {
+	return supportedPRRefactors
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runForCompletionTest(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	code, _ := Run(append([]string{"codalotl"}, args...), &RunOptions{Out: &out, Err: &errOut})
+	return code, out.String(), errOut.String()
+}
+
+func completionValuesForTest(t *testing.T, args ...string) []string {
+	t.Helper()
+	code, out, errOut := runForCompletionTest(t, append([]string{"__complete"}, args...)...)
+	require.Equal(t, 0, code, errOut)
+	var values []string
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		value, _, _ := strings.Cut(line, "\t")
+		values = append(values, value)
+	}
+	return values
+}
+
+func TestRun_Completion_PrintsScripts(t *testing.T) {
+	isolateUserConfig(t)
+
+	// Like version, completion doesn't need a valid startup environment.
+	t.Setenv("OPENAI_API_KEY", "")
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		code, out, errOut := runForCompletionTest(t, "completion", shell)
+		require.Equal(t, 0, code, errOut)
+		assert.Contains(t, out, "__complete")
+		assert.Contains(t, out, "codalotl")
+	}
+
+	code, _, errOut := runForCompletionTest(t, "completion", "powershell")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, `unsupported shell "powershell"`)
+}
+
+func TestRun_Complete_DynamicCandidates(t *testing.T) {
+	isolateUserConfig(t)
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "internal", "foo"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "internal", "foo", "SPEC.md"), []byte("# foo\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "internal", "foo", "foo.go"), []byte("package foo\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pr.md"), []byte("# pr\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shot.PNG"), nil, 0644))
+	origWD, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+
+	assert.Equal(t, []string{"cas", "completion", "config", "context"}, completionValuesForTest(t, "c"))
+	assert.Equal(t, []string{"--model"}, completionValuesForTest(t, "exec", "--mod"))
+
+	// Package paths: directories only, hidden ones skipped.
+	assert.Equal(t, []string{"internal/"}, completionValuesForTest(t, "docs", "add", ""))
+	assert.Equal(t, []string{"internal/foo/"}, completionValuesForTest(t, "docs", "add", "internal/"))
+	assert.Equal(t, []string{"internal/foo/"}, completionValuesForTest(t, "exec", "--package", "internal/"))
+
+	// SPEC.md paths, PR files, and images.
+	assert.Equal(t, []string{"internal/foo/SPEC.md"}, completionValuesForTest(t, "spec", "diff", "internal/foo/"))
+	assert.Equal(t, []string{"internal/", "pr.md"}, completionValuesForTest(t, "pr", "status", ""))
+	assert.Equal(t, []string{"internal/", "shot.PNG"}, completionValuesForTest(t, "exec", "--image", ""))
+
+	// Repeated args keep completing; the second cas get arg is a package.
+	assert.Equal(t, []string{"internal/foo/foo.go"}, completionValuesForTest(t, "docs", "reflow", "internal/", "internal/foo/f"))
+	assert.Equal(t, []string{"internal/"}, completionValuesForTest(t, "cas", "get", "specconforms", "i"))
+
+	// Namespaces, models, and refactors.
+	assert.Contains(t, completionValuesForTest(t, "cas", "get", ""), "specconforms")
+	namespaces := completionValuesForTest(t, "cas", "recertify", "internal/foo", "--namespaces=specconforms,")
+	assert.Contains(t, namespaces, "--namespaces=specconforms,docs-fix")
+	assert.NotContains(t, namespaces, "--namespaces=specconforms,specconforms")
+	assert.NotEmpty(t, completionValuesForTest(t, "exec", "--model", ""))
+	assert.Equal(t, []string{"--refactor=docs-add", "--refactor=docs-fix"}, completionValuesForTest(t, "pr", "refactor", "--refactor=docs-"))
+
+	// help completes the command path.
+	assert.Equal(t, []string{"ls-mismatch"}, completionValuesForTest(t, "help", "spec", "ls"))
+}
+
+func TestRun_Help_Command(t *testing.T) {
+	isolateUserConfig(t)
+
+	code, out, errOut := runForCompletionTest(t, "help", "cas", "get")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "codalotl cas get")
+	assert.Contains(t, out, "<namespace>")
+
+	code, out, errOut = runForCompletionTest(t, "help", "--markdown")
+	require.Equal(t, 0, code, errOut)
+	assert.True(t, strings.HasPrefix(out, "# codalotl\n"))
+	assert.Contains(t, out, "\n## codalotl cas get\n")
+	assert.Contains(t, out, "\n## codalotl auth openai login\n")
+
+	code, out, errOut = runForCompletionTest(t, "help", "spec", "--man")
+	require.Equal(t, 0, code, errOut)
+	assert.True(t, strings.HasPrefix(out, `.TH "CODALOTL\-SPEC" "1"`))
+	assert.Contains(t, out, `.SS "codalotl spec diff"`)
+
+	code, _, errOut = runForCompletionTest(t, "help", "nope")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, "unknown command: nope")
+
+	code, _, errOut = runForCompletionTest(t, "help", "--markdown", "--man")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, "mutually exclusive")
+}
@@ -20,15 +20,22 @@ import (
 // NOTE: internal/q/cascade matches keys to struct field names case-insensitively; it does not use json tags. The json tags are for `codalotl config` output and
 // for compatibility with typical config.json naming.
 type Config struct {
-	ProviderKeys          ProviderKeys       `json:"providerkeys"`           // ProviderKeys contains API keys for built-in LLM providers.
-	CustomModels          []CustomModel      `json:"custommodels,omitempty"` // CustomModels lists additional LLM models that can be selected by ID.
-	ReflowWidth           int                `json:"reflowwidth"`            // Max width when reflowing documentation. Defaults to 120.
-	ReflowWidthProvidence cascade.Providence `json:"-"`                      // ReflowWidthProvidence records the source that supplied ReflowWidth.
-	AutoYes               bool               `json:"autoyes,omitempty"`      // AutoYes auto-approves permission checks in TUI and as the default for noninteractive exec.
+	ProviderKeys ProviderKeys  `json:"providerkeys"`           // ProviderKeys contains API keys for built-in LLM providers.
+	CustomModels []CustomModel `json:"custommodels,omitempty"` // CustomModels lists additional LLM models that can be selected by ID.
+
+	// ModelFallbacks configures ordered fallback chains used when a model's provider is rate limited, overloaded, or out of quota.
+	ModelFallbacks []ModelFallback `json:"modelfallbacks,omitempty"`
+
+	ReflowWidth           int                `json:"reflowwidth"`       // Max width when reflowing documentation. Defaults to 120.
+	ReflowWidthProvidence cascade.Providence `json:"-"`                 // ReflowWidthProvidence records the source that supplied ReflowWidth.
+	AutoYes               bool               `json:"autoyes,omitempty"` // AutoYes auto-approves permission checks in TUI and as the default for noninteractive exec.
 
 	// Lints configures the lint pipeline used by `codalotl context initial`. See internal/lints/SPEC.md for full details.
 	Lints lints.Lints `json:"lints,omitempty"`
 
+	// DocsCheck sets the thresholds enforced by `codalotl docs check`.
+	DocsCheck DocsCheckConfig `json:"docscheck"`
+
 	DisableTelemetry      bool   `json:"disabletelemetry,omitempty"`      // DisableTelemetry opts out of anonymous usage metrics and error reporting.
 	DisableCrashReporting bool   `json:"disablecrashreporting,omitempty"` // DisableCrashReporting opts out of panic reporting.
 	Theme                 string `json:"theme"`                           // Theme selects the TUI color palette. Allowed values: "", "dark", "light".
@@ -47,6 +54,9 @@ type Config struct {
 	// configLocations are the JSON config file paths that actually contributed values during load (low->high precedence). This is intentionally not part of the user-visible
 	// JSON schema.
 	configLocations []string
+
+	keySources []cascade.ResolvedKey // keySources lists every key set during load, with the source that set it.
+	warnings   []string              // warnings describe problems that don't prevent loading, such as unknown keys.
 }
 
 // ProviderKeys is kept separate so tests can easily validate its zero value.
@@ -71,20 +81,21 @@ type CustomModel struct {
 	ServiceTier     string `json:"servicetier"`     // ServiceTier is an optional provider-specific service tier override.
 }
 
+// ModelFallback defines an ordered fallback chain for one model.
+type ModelFallback struct {
+	Model     string   `json:"model"`     // Model is the model ID whose failed requests fail over.
+	Fallbacks []string `json:"fallbacks"` // Fallbacks are the model IDs to try, in order.
+}
+
 // loadConfig loads, validates, and applies the effective codalotl configuration.
 func loadConfig() (Config, error) {
-	loader := cascade.New().WithDefaults(map[string]any{
-		"reflowwidth": 120,
-	})
-
-	// Global user config.
-	//
-	// We register at a lower precedence than the nearest  project config so local config can override global config.
-	homeCfg := cascade.ExpandPath("~/.codalotl/config.json")
-	loader = loader.WithJSONFile(homeCfg)
+	loader := cascade.New().WithDefaults(configDefaults)
 
-	// Local project config (highest precedence of the built-ins).
-	loader = loader.WithNearestJSONFile(filepath.Join(".codalotl", "config.json"), "")
+	// The global user config, then the nearest project config (so local config can override global config).
+	files := configFilePaths()
+	for _, path := range files {
+		loader = loader.WithJSONFile(path)
+	}
 
 	var cfg Config
 	report, err := loader.StrictlyLoadWithReport(&cfg)
@@ -93,10 +104,19 @@ func loadConfig() (Config, error) {
 	}
 	cfg.Theme = strings.ToLower(strings.TrimSpace(cfg.Theme))
 	cfg.configLocations = configLocationsFromReport(report)
+	cfg.keySources = report.Keys
+	schema := newConfigSchema()
+	if err := checkConfigFiles(schema, files); err != nil {
+		return Config{}, err
+	}
+	cfg.warnings = configWarnings(schema, files)
 
 	if err := configureCustomModelsFromConfig(cfg.CustomModels); err != nil {
 		return Config{}, err
 	}
+	if err := configureModelFallbacksFromConfig(cfg.ModelFallbacks); err != nil {
+		return Config{}, err
+	}
 	if err := validateConfig(cfg); err != nil {
 		return Config{}, err
 	}
@@ -110,6 +130,9 @@ func validateConfig(cfg Config) error {
 	if cfg.ReflowWidth <= 0 {
 		return fmt.Errorf("invalid configuration: reflowwidth must be > 0 (got %d)", cfg.ReflowWidth)
 	}
+	if err := cfg.DocsCheck.validate(); err != nil {
+		return err
+	}
 	switch cfg.Theme {
 	case "", "dark", "light":
 	default:
@@ -118,6 +141,16 @@ func validateConfig(cfg Config) error {
 	return nil
 }
 
+// writeConfigWarnings writes each of cfg's load warnings to w as a "warning: " line.
+func writeConfigWarnings(w io.Writer, cfg Config) error {
+	for _, warning := range cfg.warnings {
+		if _, err := fmt.Fprintf(w, "warning: %s\n", warning); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func writeConfigJSON(w io.Writer, cfg Config) error {
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
@@ -129,7 +162,8 @@ func writeConfigJSON(w io.Writer, cfg Config) error {
 }
 
 // writeConfig writes the user-facing `codalotl config` report for cfg to w. The report includes redacted configuration JSON, contributing config locations, the
-// effective model, provider API key environment variables, and global and project config file locations. It returns any write or encoding error.
+// source of each set key, the effective model, provider API key environment variables, and global and project config file locations. It returns any write or encoding
+// error.
 func writeConfig(w io.Writer, cfg Config) error {
 	if err := writeStringln(w, "Current Configuration:"); err != nil {
 		return err
@@ -162,6 +196,21 @@ func writeConfig(w io.Writer, cfg Config) error {
 		}
 	}
 
+	if len(cfg.keySources) > 0 {
+		if _, err := fmt.Fprintln(w, "\nKey Sources:"); err != nil {
+			return err
+		}
+		for _, src := range cfg.keySources {
+			where := src.Source.SourceIdentifier
+			if src.Source.Default() {
+				where = "(default)"
+			}
+			if _, err := fmt.Fprintf(w, "- %s: %s\n", src.Key, where); err != nil {
+				return err
+			}
+		}
+	}
+
 	effective := effectiveModel(cfg)
 	if _, err := fmt.Fprintf(w, "\nEffective Model: %s\n\n", effective); err != nil {
 		return err
@@ -282,6 +331,30 @@ func configureCustomModelsFromConfig(models []CustomModel) error {
 	return nil
 }
 
+// configureModelFallbacksFromConfig validates and applies fallback chains from configuration to llmmodel's process-global fallback registry. Models may refer to
+// custom models, so it must run after configureCustomModelsFromConfig.
+func configureModelFallbacksFromConfig(fallbacks []ModelFallback) error {
+	seen := make(map[llmmodel.ModelID]int, len(fallbacks))
+	for i, f := range fallbacks {
+		id := llmmodel.ModelID(strings.TrimSpace(f.Model))
+		if id == "" {
+			return fmt.Errorf("invalid configuration: modelfallbacks[%d].model must be non-empty", i)
+		}
+		if j, ok := seen[id]; ok {
+			return fmt.Errorf("invalid configuration: modelfallbacks[%d] repeats model %q from modelfallbacks[%d]", i, id, j)
+		}
+		seen[id] = i
+		chain := make([]llmmodel.ModelID, 0, len(f.Fallbacks))
+		for _, fb := range f.Fallbacks {
+			chain = append(chain, llmmodel.ModelID(strings.TrimSpace(fb)))
+		}
+		if err := llmmodel.SetFallbackModels(id, chain); err != nil {
+			return fmt.Errorf("invalid configuration: modelfallbacks[%d] (model=%q): %w", i, id, err)
+		}
+	}
+	return nil
+}
+
 // llmProviderEnvVarsForDisplay returns API key environment variable names relevant to the effective configuration.
 func llmProviderEnvVarsForDisplay(cfg Config) []string {
 	// Prefer stable output order.
@@ -0,0 +1,493 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/q/cascade"
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+	"github.com/codalotl/codalotl/internal/q/remotemonitor"
+)
+
+// runConfigEditor opens path in the user's editor and waits for it to exit. Like git, it runs the editor command line with sh (so it may quote paths with spaces);
+// on Windows, the command line is split on whitespace. Tests replace it.
+var runConfigEditor = func(path string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		fields := strings.Fields(configEditorCommand())
+		cmd = exec.Command(fields[0], append(fields[1:], path)...)
+	} else {
+		cmd = exec.Command("sh", "-c", configEditorCommand()+` "$@"`, configEditorCommand(), path)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// configEditorCommand returns the user's editor command line: $VISUAL, else $EDITOR, else a platform default.
+func configEditorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return v
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// A configScope selects which config file `config get/set/unset/edit` operate on.
+type configScope struct {
+	global  *bool // global selects the global config file.
+	project *bool // project selects the nearest project config file.
+}
+
+// addConfigScopeFlags defines --global and --project on cmd.
+func addConfigScopeFlags(cmd *qcli.Command, globalUsage, projectUsage string) configScope {
+	return configScope{
+		global:  cmd.Flags().Bool("global", 0, false, globalUsage),
+		project: cmd.Flags().Bool("project", 0, false, projectUsage),
+	}
+}
+
+// validate reports a usage error if both --global and --project are set.
+func (s configScope) validate() error {
+	if *s.global && *s.project {
+		return qcli.UsageError{Message: "--global and --project are mutually exclusive"}
+	}
+	return nil
+}
+
+// set reports whether --global or --project was given.
+func (s configScope) set() bool {
+	return *s.global || *s.project
+}
+
+// path returns the config file selected by s: the global config unless --project is set.
+func (s configScope) path() (string, error) {
+	if *s.project {
+		return projectConfigPathForWrite()
+	}
+	return globalConfigPath(), nil
+}
+
+// projectConfigPathForWrite returns the project config file loadConfig reads (see configFilePaths). If there is none, it returns .codalotl/config.json in the
+// enclosing git repository root, or in the working directory outside of a repository.
+func projectConfigPathForWrite() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	rel := filepath.Join(".codalotl", "config.json")
+	if p := cascade.NearestFile(rel, wd); p != "" {
+		return p, nil
+	}
+	if root, err := nearestGitRepoRoot(wd); err == nil {
+		return filepath.Join(root, rel), nil
+	}
+	return filepath.Join(wd, rel), nil
+}
+
+// newConfigCommand builds `codalotl config` and its get, set, unset, edit, and schema subcommands. Printing the configuration loads it with runWithConfig; getting
+// the effective value of a key loads it with runWithConfigNoStartup. The other subcommands work on files directly and don't load configuration, so they can repair
+// a configuration that fails to load.
+func newConfigCommand(runWithConfig, runWithConfigNoStartup runWithConfigFunc) *qcli.Command {
+	configCmd := &qcli.Command{
+		Name:             "config",
+		Short:            "Print codalotl configuration.",
+		Long:             "Prints the effective codalotl configuration, redacting provider keys and showing config locations, the source of each set key, effective model, and provider API key environment variables.",
+		Args:             qcli.NoArgs,
+		NoPositionalArgs: true,
+		Example: strings.TrimSpace(`
+codalotl config
+`),
+		Run: runWithConfig("config", func(c *qcli.Context, cfg Config, m *remotemonitor.Monitor) error {
+			if err := maybeWriteUpdateNotice(c.Out, m, Version, defaultNoticeWaitTimeout); err != nil {
+				return err
+			}
+			return writeConfig(c.Out, cfg)
+		}),
+	}
+
+	keyArgHelp := qcli.ArgHelp{
+		Display:     "<key>",
+		Description: "Dot-separated config key, such as `reflowwidth` or `docscheck.minpackagecoverage`. Keys are case-insensitive; arrays are read and written whole.",
+	}
+	completeKey := func(args []string, _ string) []string {
+		if len(args) > 0 {
+			return nil
+		}
+		return configKeyCompletions(newConfigSchema())
+	}
+
+	getCmd := &qcli.Command{
+		Name:    "get",
+		Short:   "Print a config value.",
+		Long:    "Prints the value of a config key: the effective value by default, or the value in one config file with --global or --project. Strings print as-is; other values print as JSON. Provider keys are redacted.",
+		Usage:   "<key>",
+		ArgHelp: []qcli.ArgHelp{keyArgHelp},
+		Example: strings.TrimSpace(`
+codalotl config get reflowwidth
+codalotl config get lints --project
+`),
+		Complete: completeKey,
+	}
+	getScope := addConfigScopeFlags(getCmd, "Read the global config file.", "Read the nearest project config file.")
+	getCmd.Args = func(args []string) error {
+		if err := getScope.validate(); err != nil {
+			return err
+		}
+		return qcli.ExactArgs(1)(args)
+	}
+	getEffective := runWithConfigNoStartup("config_get", func(c *qcli.Context, cfg Config, _ *remotemonitor.Monitor) error {
+		displayCfg := cfg
+		displayCfg.ProviderKeys = providerKeysForDisplay(cfg.ProviderKeys)
+		value, err := effectiveConfigValue(displayCfg, c.Args[0])
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		return writeConfigValue(c.Out, value)
+	})
+	getCmd.Run = func(c *qcli.Context) error {
+		if !getScope.set() {
+			return getEffective(c)
+		}
+		_, key, err := newConfigSchema().lookup(c.Args[0])
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		path, err := getScope.path()
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		obj, err := readJSONObjectFile(path)
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		value, ok := rawConfigValue(obj, key)
+		if !ok {
+			return qcli.ExitError{Code: 1, Err: fmt.Errorf("%s is not set in %s", key, path)}
+		}
+		if strings.HasPrefix(key, "providerkeys.") {
+			if s, ok := value.(string); ok && !isAsteriskPlaceholder(strings.TrimSpace(s)) {
+				value = redactSecret(strings.TrimSpace(s))
+			}
+		}
+		return writeConfigValue(c.Out, value)
+	}
+
+	setCmd := &qcli.Command{
+		Name:  "set",
+		Short: "Set a config value.",
+		Long: "Sets a config key in the global config file (default) or the nearest project config file (--project), creating the file if needed. " +
+			"The value is validated against the config schema: strings are taken as-is, and numbers, booleans, arrays, and objects are parsed as JSON.",
+		Usage: "<key> <value>",
+		ArgHelp: []qcli.ArgHelp{
+			keyArgHelp,
+			{Display: "<value>", Description: "Value to set."},
+		},
+		Example: strings.TrimSpace(`
+codalotl config set reflowwidth 100
+codalotl config set theme dark --project
+codalotl config set lints.disable '["reflow"]'
+`),
+		Complete: completeKey,
+	}
+	setScope := addConfigScopeFlags(setCmd, "Write the global config file (default).", "Write the nearest project config file.")
+	setCmd.Args = func(args []string) error {
+		if err := setScope.validate(); err != nil {
+			return err
+		}
+		return qcli.ExactArgs(2)(args)
+	}
+	setCmd.Run = func(c *qcli.Context) error {
+		schema := newConfigSchema()
+		node, key, err := schema.lookup(c.Args[0])
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		value, err := parseConfigValue(node, key, c.Args[1])
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		path, err := setScope.path()
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		if err := updateConfigFile(path, func(obj map[string]any) { setRawConfigValue(obj, key, value) }); err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		_, err = fmt.Fprintf(c.Out, "Set %s in %s\n", key, path)
+		return err
+	}
+
+	unsetCmd := &qcli.Command{
+		Name:  "unset",
+		Short: "Remove a config value.",
+		Long: "Removes a config key from the global config file (default) or the nearest project config file (--project). Objects left empty are removed too. " +
+			"Keys that aren't part of the configuration (ex: misspelled ones) can be removed if the file has them.",
+		Usage:    "<key>",
+		ArgHelp:  []qcli.ArgHelp{keyArgHelp},
+		Example:  "codalotl config unset preferredmodel --project",
+		Complete: completeKey,
+	}
+	unsetScope := addConfigScopeFlags(unsetCmd, "Write the global config file (default).", "Write the nearest project config file.")
+	unsetCmd.Args = func(args []string) error {
+		if err := unsetScope.validate(); err != nil {
+			return err
+		}
+		return qcli.ExactArgs(1)(args)
+	}
+	unsetCmd.Run = func(c *qcli.Context) error {
+		// A key that isn't in the schema (ex: a typo that loading warns about) can still be removed if the file has it.
+		_, key, lookupErr := newConfigSchema().lookup(c.Args[0])
+		if lookupErr != nil {
+			key = strings.TrimSpace(c.Args[0])
+		}
+		path, err := unsetScope.path()
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		obj, err := readJSONObjectFile(path)
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		if _, ok := rawConfigValue(obj, key); !ok {
+			if lookupErr != nil {
+				return qcli.ExitError{Code: 1, Err: lookupErr}
+			}
+			_, err := fmt.Fprintf(c.Out, "%s is not set in %s\n", key, path)
+			return err
+		}
+		if err := updateConfigFile(path, func(obj map[string]any) { deleteRawConfigValue(obj, key) }); err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		_, err = fmt.Fprintf(c.Out, "Unset %s in %s\n", key, path)
+		return err
+	}
+
+	editCmd := &qcli.Command{
+		Name:  "edit",
+		Short: "Edit a config file in $EDITOR.",
+		Long: "Opens the global config file (default) or the nearest project config file (--project) in $VISUAL or $EDITOR, creating it if needed. " +
+			"After the editor exits, the configuration is loaded and any errors or unknown keys are reported.",
+		Args:             qcli.NoArgs,
+		NoPositionalArgs: true,
+		Example: strings.TrimSpace(`
+codalotl config edit
+EDITOR="code --wait" codalotl config edit --project
+`),
+	}
+	editScope := addConfigScopeFlags(editCmd, "Edit the global config file (default).", "Edit the nearest project config file.")
+	editCmd.Args = func(args []string) error {
+		if err := editScope.validate(); err != nil {
+			return err
+		}
+		return qcli.NoArgs(args)
+	}
+	editCmd.Run = func(c *qcli.Context) error {
+		path, err := editScope.path()
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		if !fileExists(path) {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return qcli.ExitError{Code: 1, Err: err}
+			}
+			if err := os.WriteFile(path, []byte("{}\n"), 0644); err != nil {
+				return qcli.ExitError{Code: 1, Err: err}
+			}
+		}
+		if err := runConfigEditor(path); err != nil {
+			return qcli.ExitError{Code: 1, Err: fmt.Errorf("run editor: %w", err)}
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: fmt.Errorf("%s: %w", path, err)}
+		}
+		return writeConfigWarnings(c.Err, cfg)
+	}
+
+	schemaCmd := &qcli.Command{
+		Name:             "schema",
+		Short:            "Print the config file JSON Schema.",
+		Long:             "Prints a JSON Schema for codalotl config files, generated from the configuration struct. Point an editor at it with a top-level \"$schema\" key for completion and validation.",
+		Args:             qcli.NoArgs,
+		NoPositionalArgs: true,
+		Example: strings.TrimSpace(`
+codalotl config schema > ~/.codalotl/config.schema.json
+`),
+		Run: func(c *qcli.Context) error {
+			enc := json.NewEncoder(c.Out)
+			enc.SetIndent("", "  ")
+			enc.SetEscapeHTML(false)
+			return enc.Encode(newConfigSchema())
+		},
+	}
+
+	configCmd.AddCommand(getCmd, setCmd, unsetCmd, editCmd, schemaCmd)
+	return configCmd
+}
+
+// configKeyCompletions returns every config key that `config get/set/unset` accept: objects and their descendants.
+func configKeyCompletions(s *configSchema) []string {
+	var out []string
+	var walk func(node *configSchema, key string)
+	walk = func(node *configSchema, key string) {
+		for _, name := range node.order {
+			child := node.Properties[name]
+			childKey := joinConfigKey(key, name)
+			out = append(out, childKey)
+			if child.Type == "object" {
+				walk(child, childKey)
+			}
+		}
+	}
+	walk(s, "")
+	return out
+}
+
+// effectiveConfigValue returns the value of key in cfg, found by matching key parts to struct field names case-insensitively.
+func effectiveConfigValue(cfg Config, key string) (any, error) {
+	_, key, err := newConfigSchema().lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	v := reflect.ValueOf(cfg)
+	for _, part := range strings.Split(key, ".") {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil, nil
+			}
+			v = v.Elem()
+		}
+		v = v.FieldByNameFunc(func(name string) bool { return strings.ToLower(name) == part })
+		if !v.IsValid() {
+			return nil, fmt.Errorf("unknown config key %q", key)
+		}
+	}
+	return v.Interface(), nil
+}
+
+// writeConfigValue writes v to w: strings as-is, and anything else as indented JSON.
+func writeConfigValue(w io.Writer, v any) error {
+	if s, ok := v.(string); ok {
+		return writeStringln(w, s)
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeStringln(w, string(b))
+}
+
+// parseConfigValue parses raw as a value for the config key described by node, and validates it against node.
+func parseConfigValue(node *configSchema, key string, raw string) (any, error) {
+	var v any
+	switch node.Type {
+	case "string":
+		v = raw
+	case "integer":
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("%s: expected an integer (got %q)", key, raw)
+		}
+		v = n
+	case "number":
+		f, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: expected a number (got %q)", key, raw)
+		}
+		v = f
+	case "boolean":
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("%s: expected true or false (got %q)", key, raw)
+		}
+		v = b
+	default:
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			return nil, fmt.Errorf("%s: expected a JSON %s: %w", key, node.Type, err)
+		}
+	}
+	if err := node.check(v, key, false); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// updateConfigFile applies update to the JSON object in path and writes it back, creating path and its directory if needed and preserving the file mode.
+func updateConfigFile(path string, update func(obj map[string]any)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create config dir %q: %w", filepath.Dir(path), err)
+	}
+	mode := os.FileMode(0644)
+	if st, err := os.Stat(path); err == nil {
+		mode = st.Mode() & 0777
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	obj, err := readJSONObjectFile(path)
+	if err != nil {
+		return err
+	}
+	update(obj)
+	return writeJSONObjectFileAtomic(path, obj, mode)
+}
+
+// setRawConfigValue sets key (canonical, dot-separated) in obj to value, creating intermediate objects. Existing keys are matched case-insensitively and keep their
+// spelling.
+func setRawConfigValue(obj map[string]any, key string, value any) {
+	parts := strings.Split(key, ".")
+	cur := obj
+	for _, part := range parts[:len(parts)-1] {
+		k, ok := findConfigKey(cur, part)
+		if !ok {
+			k = part
+		}
+		next, ok := cur[k].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			cur[k] = next
+		}
+		cur = next
+	}
+	last := parts[len(parts)-1]
+	if k, ok := findConfigKey(cur, last); ok {
+		last = k
+	}
+	cur[last] = value
+}
+
+// deleteRawConfigValue deletes key (canonical, dot-separated) from obj, matching keys case-insensitively, and removes objects left empty.
+func deleteRawConfigValue(obj map[string]any, key string) {
+	part, rest, nested := strings.Cut(key, ".")
+	k, ok := findConfigKey(obj, part)
+	if !ok {
+		return
+	}
+	if !nested {
+		delete(obj, k)
+		return
+	}
+	child, ok := obj[k].(map[string]any)
+	if !ok {
+		return
+	}
+	deleteRawConfigValue(child, rest)
+	if len(child) == 0 {
+		delete(obj, k)
+	}
+}
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runConfigCLI(t *testing.T, args ...string) (int, string, string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	code, err := Run(append([]string{"codalotl", "config"}, args...), &RunOptions{Out: &out, Err: &errOut})
+	return code, out.String(), errOut.String(), err
+}
+
+func TestConfigSchema_CoversNestedTypes(t *testing.T) {
+	s := newConfigSchema()
+
+	node, key, err := s.lookup("DocsCheck.MinPackageCoverage")
+	require.NoError(t, err)
+	assert.Equal(t, "docscheck.minpackagecoverage", key)
+	assert.Equal(t, "number", node.Type)
+
+	node, _, err = s.lookup("lints")
+	require.NoError(t, err)
+	require.Contains(t, node.Properties, "steps")
+	assert.Equal(t, "object", node.Properties["steps"].Items.Type)
+
+	node, _, err = s.lookup("custommodels")
+	require.NoError(t, err)
+	assert.Contains(t, node.Items.Properties, "apikeyenv")
+
+	_, _, err = s.lookup("lintz")
+	assert.EqualError(t, err, `unknown config key "lintz"`)
+	_, _, err = s.lookup("$schema")
+	assert.Error(t, err)
+
+	// Providence fields are bookkeeping, not config keys.
+	assert.NotContains(t, s.Properties, "reflowwidthprovidence")
+
+	// Property names match cascade's keys, which prefer cascade and json tag names.
+	tagged := schemaForType(reflect.TypeOf(struct {
+		MaxWidth int    `json:"max_width"`
+		Name     string `cascade:"label" json:"name"`
+		Hidden   bool   `cascade:"-"`
+	}{}), "")
+	assert.Equal(t, []string{"max_width", "label"}, tagged.order)
+}
+
+func TestConfigSchema_Check(t *testing.T) {
+	s := newConfigSchema()
+
+	assert.NoError(t, s.check(map[string]any{"reflowwidth": float64(80), "theme": "Dark"}, "", false))
+	assert.EqualError(t, s.check(map[string]any{"reflowwidth": float64(0)}, "", false), "reflowwidth: must be >= 1 (got 0)")
+	assert.EqualError(t, s.check(map[string]any{"reflowwidth": 1.5}, "", false), "reflowwidth: expected an integer")
+	assert.EqualError(t, s.check(map[string]any{"theme": "blue"}, "", false), `theme: must be one of "", "dark", "light" (got "blue")`)
+	assert.EqualError(t, s.check(map[string]any{"custommodels": []any{map[string]any{"nmae": "x"}}}, "", false), `unknown config key "custommodels[0].nmae"`)
+	assert.NoError(t, s.check(map[string]any{"custommodels": []any{map[string]any{"nmae": "x"}}}, "", true))
+}
+
+func TestLoadConfig_WarnsOnUnknownKeys(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	writeProjectConfig(t, tmp, `{"reflowwidth": 90, "lintz": {}, "custommodels": [{"id": "m", "provider": "openai", "model": "gpt-5", "nmae": "x"}]}`)
+	chdirForTest(t, tmp)
+
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 90, cfg.ReflowWidth)
+	require.Len(t, cfg.warnings, 2)
+	assert.Regexp(t, `^unknown config key "custommodels\[0\]\.nmae" in .*config\.json$`, cfg.warnings[0])
+	assert.Regexp(t, `^unknown config key "lintz" in .*config\.json$`, cfg.warnings[1])
+}
+
+func TestLoadConfig_ChecksValuesAgainstSchema(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	writeProjectConfig(t, tmp, `{"lintz": {}, "lints": {"steps": [{"id": "x", "situations": ["sometimes"]}]}}`)
+	chdirForTest(t, tmp)
+
+	_, err := loadConfig()
+	require.Error(t, err)
+	assert.Regexp(t, `^invalid configuration in .*config\.json: lints\.steps\[0\]\.situations\[0\]: must be one of "initial", `, err.Error())
+}
+
+func TestRun_Config_ShowsKeySources(t *testing.T) {
+	isolateUserConfig(t)
+
+	globalPath := globalConfigPath()
+	require.NoError(t, os.MkdirAll(filepath.Dir(globalPath), 0o755))
+	require.NoError(t, os.WriteFile(globalPath, []byte(`{"theme": "dark", "typo": 1}`), 0o644))
+
+	tmp := t.TempDir()
+	writeProjectConfig(t, tmp, `{"reflowwidth": 90}`)
+	chdirForTest(t, tmp)
+
+	code, out, errOut, err := runConfigCLI(t)
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "\nKey Sources:\n")
+	assert.Contains(t, out, "- theme: "+globalPath+"\n")
+	assert.Regexp(t, `- reflowwidth: .*config\.json\n`, out)
+	assert.NotContains(t, out, "- reflowwidth: (default)")
+	assert.Contains(t, errOut, `warning: unknown config key "typo" in `+globalPath)
+}
+
+func TestRun_ConfigSetGetUnset_Project(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	writeProjectConfig(t, tmp, `{"Theme": "light"}`)
+	chdirForTest(t, tmp)
+	cfgPath := filepath.Join(tmp, ".codalotl", "config.json")
+
+	code, out, _, err := runConfigCLI(t, "set", "--project", "theme", "dark")
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "Set theme in ")
+
+	code, _, _, err = runConfigCLI(t, "set", "--project", "docscheck.minpackagecoverage", "75")
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+
+	obj := readJSONObj(t, cfgPath)
+	assert.Equal(t, "dark", obj["Theme"], "existing key spelling is kept")
+	assert.Equal(t, map[string]any{"minpackagecoverage": float64(75)}, obj["docscheck"])
+
+	code, out, _, err = runConfigCLI(t, "get", "--project", "docscheck.minpackagecoverage")
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Equal(t, "75\n", out)
+
+	code, out, _, err = runConfigCLI(t, "get", "theme")
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Equal(t, "dark\n", out)
+
+	code, _, _, err = runConfigCLI(t, "unset", "--project", "docscheck.minpackagecoverage")
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.NotContains(t, readJSONObj(t, cfgPath), "docscheck")
+}
+
+func TestRun_ConfigSet_ProjectSkipsEmptyConfig(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	createGitRepoMarker(t, tmp)
+	writeProjectConfig(t, tmp, `{"theme": "light"}`)
+	sub := filepath.Join(tmp, "sub")
+	writeProjectConfig(t, sub, "\n")
+	chdirForTest(t, sub)
+
+	// Loading skips the empty config, so writes go to the config it reads.
+	code, _, _, err := runConfigCLI(t, "set", "--project", "theme", "dark")
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Equal(t, map[string]any{"theme": "dark"}, readJSONObj(t, filepath.Join(tmp, ".codalotl", "config.json")))
+	data, err := os.ReadFile(filepath.Join(sub, ".codalotl", "config.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "\n", string(data))
+}
+
+func TestRun_ConfigUnset_UnknownKeyInFile(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	writeProjectConfig(t, tmp, `{"reflowwidht": 80, "theme": "dark"}`)
+	chdirForTest(t, tmp)
+	cfgPath := filepath.Join(tmp, ".codalotl", "config.json")
+
+	code, out, _, err := runConfigCLI(t, "unset", "--project", "reflowwidht")
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "Unset reflowwidht in ")
+	assert.Equal(t, map[string]any{"theme": "dark"}, readJSONObj(t, cfgPath))
+
+	// Once it's gone, it's an unknown key again.
+	code, _, _, err = runConfigCLI(t, "unset", "--project", "reflowwidht")
+	require.ErrorContains(t, err, `unknown config key "reflowwidht"`)
+	require.Equal(t, 1, code)
+}
+
+func TestRun_ConfigSet_RejectsInvalidValues(t *testing.T) {
+	isolateUserConfig(t)
+	chdirForTest(t, t.TempDir())
+
+	code, _, _, err := runConfigCLI(t, "set", "reflowwidth", "wide")
+	require.Error(t, err)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, err.Error(), "reflowwidth: expected an integer")
+
+	code, _, _, err = runConfigCLI(t, "set", "lintz", "1")
+	require.Error(t, err)
+	assert.Equal(t, 1, code)
+
+	code, _, _, err = runConfigCLI(t, "set", "--global", "--project", "theme", "dark")
+	require.Error(t, err)
+	assert.Equal(t, 2, code)
+
+	assert.NoFileExists(t, globalConfigPath())
+}
+
+func TestRun_ConfigSet_DefaultsToGlobal(t *testing.T) {
+	isolateUserConfig(t)
+	chdirForTest(t, t.TempDir())
+
+	code, _, _, err := runConfigCLI(t, "set", "lints.disable", `["reflow"]`)
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+
+	obj := readJSONObj(t, globalConfigPath())
+	b, err := json.Marshal(obj)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"lints": {"disable": ["reflow"]}}`, string(b))
+}
+
+func TestRun_ConfigEdit_CreatesFileAndReportsUnknownKeys(t *testing.T) {
+	isolateUserConfig(t)
+	chdirForTest(t, t.TempDir())
+
+	var edited string
+	orig := runConfigEditor
+	runConfigEditor = func(path string) error {
+		edited = path
+		return os.WriteFile(path, []byte(`{"reflowwidht": 100}`), 0o644)
+	}
+	t.Cleanup(func() { runConfigEditor = orig })
+
+	code, _, errOut, err := runConfigCLI(t, "edit")
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Equal(t, globalConfigPath(), edited)
+	assert.Contains(t, errOut, `warning: unknown config key "reflowwidht" in `+globalConfigPath())
+}
@@ -0,0 +1,357 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/lints"
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	"github.com/codalotl/codalotl/internal/q/cascade"
+)
+
+// configSchema is a JSON Schema node describing part of the config file format. The root node is generated from Config by reflection (see newConfigSchema), so new
+// Config fields are picked up automatically.
+//
+// Property names are the keys internal/q/cascade matches (see cascade.FieldKey): the lowercased cascade or json tag name, else the lowercased Go field name. Keys
+// in config files are matched case-insensitively.
+type configSchema struct {
+	Schema               string                   `json:"$schema,omitempty"`
+	Title                string                   `json:"title,omitempty"`
+	Type                 string                   `json:"type"`
+	Properties           map[string]*configSchema `json:"properties,omitempty"`
+	AdditionalProperties *bool                    `json:"additionalProperties,omitempty"`
+	Items                *configSchema            `json:"items,omitempty"`
+	Enum                 []string                 `json:"enum,omitempty"`
+	Minimum              *float64                 `json:"minimum,omitempty"`
+	Maximum              *float64                 `json:"maximum,omitempty"`
+
+	order []string // order lists Properties keys in struct field order.
+}
+
+// configSchemaKey is the optional top-level key that points editors at the schema (ex: "$schema": "./codalotl.schema.json"). It is ignored when loading.
+const configSchemaKey = "$schema"
+
+// configDefaults are the lowest-precedence config values, applied before any config file.
+var configDefaults = map[string]any{
+	"reflowwidth": 120,
+}
+
+// configEnumsByType lists the allowed values of named string types used in Config.
+var configEnumsByType = map[reflect.Type][]string{
+	reflect.TypeOf(lints.ConfigMode("")): {"", string(lints.ConfigModeExtend), string(lints.ConfigModeReplace)},
+	reflect.TypeOf(lints.Situation("")):  {string(lints.SituationInitial), string(lints.SituationPatch), string(lints.SituationFix), string(lints.SituationTests)},
+}
+
+// configBoundsByKey lists numeric bounds ([min, max]; nil means unbounded) for config keys.
+var configBoundsByKey = map[string][2]*float64{
+	"reflowwidth":                    {ptrFloat(1), nil},
+	"docscheck.minexportedcoverage":  {ptrFloat(0), ptrFloat(100)},
+	"docscheck.minimportantcoverage": {ptrFloat(0), ptrFloat(100)},
+	"docscheck.minpackagecoverage":   {ptrFloat(0), ptrFloat(100)},
+}
+
+func ptrFloat(f float64) *float64 {
+	return &f
+}
+
+// configEnumForKey returns the allowed values for a string config key, or nil if any string is allowed.
+func configEnumForKey(key string) []string {
+	switch key {
+	case "theme":
+		return []string{"", "dark", "light"}
+	case "preferredprovider":
+		enum := []string{""}
+		for _, pid := range llmmodel.AllProviderIDs {
+			enum = append(enum, string(pid))
+		}
+		return enum
+	}
+	return nil
+}
+
+// newConfigSchema returns the JSON Schema for codalotl config files.
+func newConfigSchema() *configSchema {
+	s := schemaForType(reflect.TypeOf(Config{}), "")
+	s.Schema = "https://json-schema.org/draft/2020-12/schema"
+	s.Title = "codalotl configuration"
+	s.Properties[configSchemaKey] = &configSchema{Type: "string"}
+	return s
+}
+
+// schemaForType returns the schema for values of type t at config key path key ("" for the root; array elements use the array's key).
+func schemaForType(t reflect.Type, key string) *configSchema {
+	if t.Kind() == reflect.Pointer {
+		return schemaForType(t.Elem(), key)
+	}
+
+	var s *configSchema
+	switch t.Kind() {
+	case reflect.Struct:
+		noAdditional := false
+		s = &configSchema{Type: "object", Properties: map[string]*configSchema{}, AdditionalProperties: &noAdditional}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Type == reflect.TypeOf(cascade.Providence{}) {
+				continue
+			}
+			name := cascade.FieldKey(f)
+			if name == "-" {
+				continue
+			}
+			childKey := name
+			if key != "" {
+				childKey = key + "." + name
+			}
+			s.Properties[name] = schemaForType(f.Type, childKey)
+			s.order = append(s.order, name)
+		}
+	case reflect.Slice, reflect.Array:
+		s = &configSchema{Type: "array", Items: schemaForType(t.Elem(), key)}
+	case reflect.String:
+		s = &configSchema{Type: "string", Enum: configEnumsByType[t]}
+		if enum := configEnumForKey(key); enum != nil {
+			s.Enum = enum
+		}
+	case reflect.Bool:
+		s = &configSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = &configSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		s = &configSchema{Type: "number"}
+	default:
+		panic(fmt.Sprintf("config schema: unsupported type %s at %q", t, key))
+	}
+
+	if bounds, ok := configBoundsByKey[key]; ok {
+		s.Minimum, s.Maximum = bounds[0], bounds[1]
+	}
+	return s
+}
+
+// lookup returns the schema for key (dot-separated, case-insensitive) and its canonical form. It returns an error if key is not a config key. Keys can't index into
+// arrays; arrays are set as a whole.
+func (s *configSchema) lookup(key string) (*configSchema, string, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, "", fmt.Errorf("config key must be non-empty")
+	}
+	node := s
+	var canonical []string
+	for _, part := range strings.Split(key, ".") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		child, ok := node.Properties[part]
+		if !ok || part == configSchemaKey {
+			return nil, "", fmt.Errorf("unknown config key %q", key)
+		}
+		node = child
+		canonical = append(canonical, part)
+	}
+	return node, strings.Join(canonical, "."), nil
+}
+
+// check reports an error if v (decoded JSON; numbers may also be int) doesn't conform to s. key is used in error messages. null is accepted anywhere and means
+// "unset". If unknownKeysOK, object keys that s doesn't define are skipped (see unknownKeys) instead of being errors.
+func (s *configSchema) check(v any, key string, unknownKeysOK bool) error {
+	if v == nil {
+		return nil
+	}
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected an object", key)
+		}
+		for k, child := range obj {
+			childKey := joinConfigKey(key, k)
+			prop, ok := s.Properties[strings.ToLower(k)]
+			if !ok {
+				if unknownKeysOK {
+					continue
+				}
+				return fmt.Errorf("unknown config key %q", childKey)
+			}
+			if err := prop.check(child, childKey, unknownKeysOK); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected an array", key)
+		}
+		for i, item := range arr {
+			if err := s.Items.check(item, fmt.Sprintf("%s[%d]", key, i), unknownKeysOK); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected a string", key)
+		}
+		if len(s.Enum) > 0 && !containsString(s.Enum, strings.ToLower(strings.TrimSpace(str))) {
+			return fmt.Errorf("%s: must be one of %s (got %q)", key, quotedList(s.Enum), str)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: expected true or false", key)
+		}
+	case "integer", "number":
+		var f float64
+		switch n := v.(type) {
+		case float64:
+			f = n
+		case int:
+			f = float64(n)
+		default:
+			return fmt.Errorf("%s: expected a number", key)
+		}
+		if s.Type == "integer" && f != float64(int64(f)) {
+			return fmt.Errorf("%s: expected an integer", key)
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			return fmt.Errorf("%s: must be >= %v (got %v)", key, *s.Minimum, f)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return fmt.Errorf("%s: must be <= %v (got %v)", key, *s.Maximum, f)
+		}
+	}
+	return nil
+}
+
+// unknownKeys returns the keys in v (decoded JSON) that s doesn't define, in sorted order. Array elements are reported with an index (ex: "custommodels[0].name").
+func (s *configSchema) unknownKeys(v any, key string) []string {
+	var out []string
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for k, child := range obj {
+			childKey := joinConfigKey(key, k)
+			prop, ok := s.Properties[strings.ToLower(k)]
+			if !ok {
+				out = append(out, childKey)
+				continue
+			}
+			out = append(out, prop.unknownKeys(child, childKey)...)
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return nil
+		}
+		for i, item := range arr {
+			out = append(out, s.Items.unknownKeys(item, fmt.Sprintf("%s[%d]", key, i))...)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func joinConfigKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func quotedList(list []string) string {
+	quoted := make([]string, len(list))
+	for i, v := range list {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// rawConfigValue returns the value at key (canonical, dot-separated) in obj, matching object keys case-insensitively. ok is false if key is absent or null.
+func rawConfigValue(obj map[string]any, key string) (any, bool) {
+	var cur any = obj
+	for _, part := range strings.Split(key, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		k, ok := findConfigKey(m, part)
+		if !ok {
+			return nil, false
+		}
+		cur = m[k]
+	}
+	return cur, cur != nil
+}
+
+// findConfigKey returns the key in m that matches key case-insensitively. An exact match wins.
+func findConfigKey(m map[string]any, key string) (string, bool) {
+	if _, ok := m[key]; ok {
+		return key, true
+	}
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// configFilePaths returns the config files loadConfig reads, from low to high precedence: the global config, then the nearest non-empty project config (searching
+// upward from the working directory), if there is one.
+func configFilePaths() []string {
+	paths := []string{globalConfigPath()}
+	if p := cascade.NearestFile(filepath.Join(".codalotl", "config.json"), ""); p != "" {
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// readConfigFileObject reads a config file as a JSON object. Missing, unreadable, empty, and unparsable files return nil; loading reports parse errors.
+func readConfigFileObject(path string) map[string]any {
+	data, err := os.ReadFile(path)
+	if err != nil || strings.TrimSpace(string(data)) == "" {
+		return nil
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil
+	}
+	return obj
+}
+
+// checkConfigFiles returns an error if a config file at paths has a value that doesn't conform to schema (ex: an unknown lint situation or an out-of-range coverage).
+// Unknown keys are left to configWarnings. A file listed more than once is checked once.
+func checkConfigFiles(schema *configSchema, paths []string) error {
+	seen := map[string]bool{}
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if obj := readConfigFileObject(path); obj != nil {
+			if err := schema.check(obj, "", true); err != nil {
+				return fmt.Errorf("invalid configuration in %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// configWarnings returns a warning for each key in the config files at paths that isn't part of the config schema. A file listed more than once is checked once.
+func configWarnings(schema *configSchema, paths []string) []string {
+	var warnings []string
+	seen := map[string]bool{}
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		for _, key := range schema.unknownKeys(readConfigFileObject(path), "") {
+			warnings = append(warnings, fmt.Sprintf("unknown config key %q in %s", key, path))
+		}
+	}
+	return warnings
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/codalotl/codalotl/internal/agentbuilder"
 	"github.com/codalotl/codalotl/internal/llmmodel"
 	"github.com/stretchr/testify/require"
 )
@@ -274,3 +275,81 @@ func TestRun_Config_CustomOpenAIModelWithUnsetAPIEnvKeyDoesNotBypassUnusableProv
 	require.Contains(t, got, "codalotl auth openai logout")
 	require.NotContains(t, got, "No usable LLM auth or credentials are configured")
 }
+
+func TestLoadConfig_ModelFallbacks(t *testing.T) {
+	isolateUserConfig(t)
+
+	customID := "custom-" + sanitizeTestModelID(t.Name())
+	t.Cleanup(func() { _ = llmmodel.SetFallbackModels(llmmodel.ModelID(customID), nil) })
+
+	tmp := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmp, ".codalotl"), 0755))
+	cfgPath := filepath.Join(tmp, ".codalotl", "config.json")
+	cfgJSON := `{
+  "custommodels": [
+    {"id": "` + customID + `", "provider": "anthropic", "model": "claude-custom"}
+  ],
+  "modelfallbacks": [
+    {"model": "` + customID + `", "fallbacks": ["` + string(llmmodel.DefaultModel) + `"]}
+  ]
+}
+`
+	require.NoError(t, os.WriteFile(cfgPath, []byte(cfgJSON), 0644))
+
+	origWD, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmp))
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	require.Len(t, cfg.ModelFallbacks, 1)
+	require.Equal(t, []llmmodel.ModelID{llmmodel.DefaultModel}, llmmodel.FallbackModels(llmmodel.ModelID(customID)))
+
+	// Unknown fallback models are configuration errors.
+	require.NoError(t, os.WriteFile(cfgPath, []byte(`{"modelfallbacks": [{"model": "`+string(llmmodel.DefaultModel)+`", "fallbacks": ["no-such-model"]}]}`), 0644))
+	_, err = loadConfig()
+	require.ErrorContains(t, err, "invalid configuration: modelfallbacks[0]")
+	require.Empty(t, llmmodel.FallbackModels(llmmodel.DefaultModel))
+
+	// A model may have only one fallback chain.
+	require.NoError(t, os.WriteFile(cfgPath, []byte(`{"modelfallbacks": [
+  {"model": "`+customID+`", "fallbacks": ["`+string(llmmodel.DefaultModel)+`"]},
+  {"model": "`+customID+`", "fallbacks": []}
+]}`), 0644))
+	_, err = loadConfig()
+	require.ErrorContains(t, err, `invalid configuration: modelfallbacks[1] repeats model "`+customID+`" from modelfallbacks[0]`)
+}
+
+func TestInstallAgentYAML_FindsGlobalAndProjectFiles(t *testing.T) {
+	home := isolateUserConfigWithHome(t)
+	t.Cleanup(func() { agentbuilder.SetStartupYAML(nil) })
+
+	globalPath := filepath.Join(home, ".codalotl", "agents.yml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(globalPath), 0755))
+	require.NoError(t, os.WriteFile(globalPath, []byte("routing: []\n"), 0644))
+
+	project := t.TempDir()
+	projectPath := filepath.Join(project, ".codalotl", "agents.yml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(projectPath), 0755))
+	require.NoError(t, os.WriteFile(projectPath, []byte(`
+routing:
+  - agent: generic
+    model: `+string(llmmodel.DefaultModel)+`
+`), 0644))
+	sub := filepath.Join(project, "sub")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+
+	origWD, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(sub))
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+
+	installAgentYAML()
+
+	reg, err := agentbuilder.BuildRegistry()
+	require.NoError(t, err)
+	def, ok := reg.Lookup(agentbuilder.AgentGeneric)
+	require.True(t, ok)
+	require.Equal(t, llmmodel.DefaultModel, def.Model)
+}
@@ -0,0 +1,385 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/docubot"
+	"github.com/codalotl/codalotl/internal/gocas"
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+	"github.com/codalotl/codalotl/internal/q/remotemonitor"
+)
+
+const (
+	docsCheckFormatText  = "text"
+	docsCheckFormatJSON  = "json"
+	docsCheckFormatJUnit = "junit"
+)
+
+var runDocubotMeasureDocCoverage = docubot.MeasureDocCoverage
+
+// DocsCheckConfig sets the thresholds enforced by `codalotl docs check`. Zero values disable the corresponding check.
+type DocsCheckConfig struct {
+	MinExportedCoverage  float64 `json:"minexportedcoverage,omitempty"`  // MinExportedCoverage is the minimum module-wide exported-identifier doc coverage, in percent.
+	MinImportantCoverage float64 `json:"minimportantcoverage,omitempty"` // MinImportantCoverage is the minimum module-wide important-identifier doc coverage, in percent.
+	MinPackageCoverage   float64 `json:"minpackagecoverage,omitempty"`   // MinPackageCoverage is the minimum exported-identifier doc coverage of each package, in percent.
+	RequireDocsFix       bool    `json:"requiredocsfix,omitempty"`       // RequireDocsFix fails packages without a current whole-package docs-fix CAS record.
+	RequireReflow        bool    `json:"requirereflow,omitempty"`        // RequireReflow fails packages whose docs would change when reflowed.
+}
+
+// validate reports an error if any coverage threshold is outside [0, 100].
+func (c DocsCheckConfig) validate() error {
+	for _, threshold := range []struct {
+		name  string
+		value float64
+	}{
+		{"minexportedcoverage", c.MinExportedCoverage},
+		{"minimportantcoverage", c.MinImportantCoverage},
+		{"minpackagecoverage", c.MinPackageCoverage},
+	} {
+		if threshold.value < 0 || threshold.value > 100 {
+			return fmt.Errorf("invalid configuration: docscheck.%s must be between 0 and 100 (got %v)", threshold.name, threshold.value)
+		}
+	}
+	return nil
+}
+
+// docsCheckCoverage is a coverage measurement in a docs check report.
+type docsCheckCoverage struct {
+	Targets    int     `json:"targets"`    // Targets is the number of documentation targets.
+	Documented int     `json:"documented"` // Documented is the number of Targets with docs.
+	Percent    float64 `json:"percent"`    // Percent is Documented as a percentage of Targets (100 if there are no targets).
+}
+
+func newDocsCheckCoverage(c docubot.DocCoverage) docsCheckCoverage {
+	return docsCheckCoverage{Targets: c.Targets, Documented: c.Documented, Percent: c.Percent()}
+}
+
+func (c docsCheckCoverage) add(other docsCheckCoverage) docsCheckCoverage {
+	sum := docubot.DocCoverage{Targets: c.Targets + other.Targets, Documented: c.Documented + other.Documented}
+	return newDocsCheckCoverage(sum)
+}
+
+func (c docsCheckCoverage) String() string {
+	return fmt.Sprintf("%.1f%% (%d/%d)", c.Percent, c.Documented, c.Targets)
+}
+
+// docsCheckPackage is one package's row in a docs check report.
+type docsCheckPackage struct {
+	Package   string            `json:"package"`         // Package is the display package path.
+	Module    string            `json:"module"`          // Module is the containing module path.
+	Exported  docsCheckCoverage `json:"exported"`        // Exported is coverage of exported identifiers.
+	Important docsCheckCoverage `json:"important"`       // Important is coverage of important identifiers.
+	DocsFix   string            `json:"docs_fix"`        // DocsFix is the docs status value for docs-fix certification.
+	Reflow    string            `json:"reflow"`          // Reflow is the docs status value for reflow drift.
+	Error     string            `json:"error,omitempty"` // Error is set if the package could not be measured.
+}
+
+// docsCheckModule is the module-wide coverage in a docs check report.
+type docsCheckModule struct {
+	Module    string            `json:"module"`    // Module is the module path.
+	Exported  docsCheckCoverage `json:"exported"`  // Exported is coverage of exported identifiers across the module's packages.
+	Important docsCheckCoverage `json:"important"` // Important is coverage of important identifiers across the module's packages.
+}
+
+// docsCheckViolation is a threshold violation. Exactly one of Package and Module is set.
+type docsCheckViolation struct {
+	Package string `json:"package,omitempty"` // Package is the violating package, for package-level checks.
+	Module  string `json:"module,omitempty"`  // Module is the violating module, for module-wide checks.
+	Message string `json:"message"`           // Message describes the violation.
+}
+
+// docsCheckReport is the result of `codalotl docs check`.
+type docsCheckReport struct {
+	Passed     bool                 `json:"passed"`     // Passed reports whether there are no violations.
+	Thresholds DocsCheckConfig      `json:"thresholds"` // Thresholds are the effective thresholds.
+	Modules    []docsCheckModule    `json:"modules"`    // Modules are sorted by module path.
+	Packages   []docsCheckPackage   `json:"packages"`   // Packages are sorted by display path.
+	Violations []docsCheckViolation `json:"violations"` // Violations are in package order, then module order.
+}
+
+func newDocsCheckCommand(runWithConfig runWithConfigFunc) *qcli.Command {
+	cmd := &qcli.Command{
+		Name:  "check",
+		Short: "Check documentation coverage and freshness against configured thresholds (for CI).",
+		Long: "Measures exported and important identifier doc coverage per package and per module, docs-fix certification, and reflow drift across Go modules discovered from the nearest git repo. " +
+			"Exits non-zero if any threshold in the docscheck section of .codalotl/config.json (or --fail-under) is violated. Does not make LLM requests or require LLM configuration.",
+		Args:             qcli.NoArgs,
+		NoPositionalArgs: true,
+		Example: strings.TrimSpace(`
+codalotl docs check
+codalotl docs check --fail-under 80
+codalotl docs check --format junit > docs-check.xml
+`),
+	}
+	flags := cmd.Flags()
+	failUnder := flags.String("fail-under", 0, "", "Minimum module-wide exported doc coverage percent. Overrides docscheck.minexportedcoverage.")
+	format := flags.String("format", 0, docsCheckFormatText, "Output format: text, json, or junit.")
+	cmd.Run = runWithConfig("docs_check", func(c *qcli.Context, cfg Config, _ *remotemonitor.Monitor) error {
+		thresholds := cfg.DocsCheck
+		if strings.TrimSpace(*failUnder) != "" {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(*failUnder), "%"), 64)
+			if err != nil {
+				return qcli.UsageError{Message: fmt.Sprintf("invalid --fail-under %q", *failUnder)}
+			}
+			thresholds.MinExportedCoverage = v
+		}
+		if err := thresholds.validate(); err != nil {
+			return qcli.UsageError{Message: strings.TrimPrefix(err.Error(), "invalid configuration: ")}
+		}
+		var write func(io.Writer, docsCheckReport) error
+		switch *format {
+		case docsCheckFormatText:
+			write = writeDocsCheckText
+		case docsCheckFormatJSON:
+			write = writeDocsCheckJSON
+		case docsCheckFormatJUnit:
+			write = writeDocsCheckJUnit
+		default:
+			return qcli.UsageError{Message: fmt.Sprintf("invalid --format %q (want text, json, or junit)", *format)}
+		}
+
+		report, err := runDocsCheck(c.Context, cfg.ReflowWidth, thresholds)
+		if err != nil {
+			return err
+		}
+		if err := write(c.Out, report); err != nil {
+			return err
+		}
+		if !report.Passed {
+			return qcli.ExitError{Code: 1, Err: fmt.Errorf("docs check failed: %d violation(s)", len(report.Violations))}
+		}
+		return nil
+	})
+	return cmd
+}
+
+// runDocsCheck measures packages discovered under the nearest Git repository and evaluates thresholds. It is read-only. Package-specific failures are recorded
+// in the package's Error and count as violations.
+func runDocsCheck(ctx context.Context, reflowWidth int, thresholds DocsCheckConfig) (docsCheckReport, error) {
+	repoRoot, pkgDirs, err := goListPackageDirsUnderNearestGitRepo(ctx)
+	if err != nil {
+		return docsCheckReport{}, err
+	}
+
+	report := docsCheckReport{Thresholds: thresholds}
+	modules := map[string]*docsCheckModule{}
+	dbs := map[string]*gocas.DB{}
+	for _, pkgDir := range pkgDirs {
+		display, ok := displayPackagePath(repoRoot, pkgDir.absDir)
+		if !ok {
+			continue
+		}
+		row := docsCheckPackage{Package: display, Module: pkgDir.mod.Name, DocsFix: docsStatusError, Reflow: docsStatusError}
+		mod := modules[row.Module]
+		if mod == nil {
+			mod = &docsCheckModule{Module: row.Module, Exported: newDocsCheckCoverage(docubot.DocCoverage{}), Important: newDocsCheckCoverage(docubot.DocCoverage{})}
+			modules[row.Module] = mod
+		}
+
+		pkg, err := loadPackageFromRepoDir(pkgDir)
+		if err == nil {
+			var exported, important docubot.DocCoverage
+			exported, err = runDocubotMeasureDocCoverage(pkg, docubot.AddDocsOptions{OnlyDocumentExportedIdentifiers: true})
+			if err == nil {
+				important, err = runDocubotMeasureDocCoverage(pkg, docubot.AddDocsOptions{OnlyDocumentImportantIdentifiers: true})
+			}
+			row.Exported = newDocsCheckCoverage(exported)
+			row.Important = newDocsCheckCoverage(important)
+			row.DocsFix = docsFixStatus(pkgDir.mod.AbsolutePath, pkg, dbs)
+			row.Reflow = docsReflowStatus(pkg, reflowWidth)
+		}
+		if err != nil {
+			row.Error = err.Error()
+		} else {
+			mod.Exported = mod.Exported.add(row.Exported)
+			mod.Important = mod.Important.add(row.Important)
+		}
+		report.Packages = append(report.Packages, row)
+	}
+
+	sort.Slice(report.Packages, func(i, j int) bool {
+		return report.Packages[i].Package < report.Packages[j].Package
+	})
+	for _, m := range modules {
+		report.Modules = append(report.Modules, *m)
+	}
+	sort.Slice(report.Modules, func(i, j int) bool {
+		return report.Modules[i].Module < report.Modules[j].Module
+	})
+
+	for _, p := range report.Packages {
+		for _, msg := range docsCheckPackageViolations(p, thresholds) {
+			report.Violations = append(report.Violations, docsCheckViolation{Package: p.Package, Message: msg})
+		}
+	}
+	for _, m := range report.Modules {
+		for _, msg := range docsCheckModuleViolations(m, thresholds) {
+			report.Violations = append(report.Violations, docsCheckViolation{Module: m.Module, Message: msg})
+		}
+	}
+	report.Passed = len(report.Violations) == 0
+	return report, nil
+}
+
+func docsCheckPackageViolations(p docsCheckPackage, t DocsCheckConfig) []string {
+	if p.Error != "" {
+		return []string{"error: " + p.Error}
+	}
+	var violations []string
+	if t.MinPackageCoverage > 0 && p.Exported.Percent < t.MinPackageCoverage {
+		violations = append(violations, fmt.Sprintf("exported doc coverage %s is under %v%%", p.Exported, t.MinPackageCoverage))
+	}
+	if t.RequireDocsFix && p.DocsFix != docsStatusCurrent {
+		violations = append(violations, "docs-fix certification is "+p.DocsFix)
+	}
+	if t.RequireReflow && p.Reflow != docsStatusCurrent {
+		violations = append(violations, "reflow is "+p.Reflow)
+	}
+	return violations
+}
+
+func docsCheckModuleViolations(m docsCheckModule, t DocsCheckConfig) []string {
+	var violations []string
+	if t.MinExportedCoverage > 0 && m.Exported.Percent < t.MinExportedCoverage {
+		violations = append(violations, fmt.Sprintf("exported doc coverage %s is under %v%%", m.Exported, t.MinExportedCoverage))
+	}
+	if t.MinImportantCoverage > 0 && m.Important.Percent < t.MinImportantCoverage {
+		violations = append(violations, fmt.Sprintf("important doc coverage %s is under %v%%", m.Important, t.MinImportantCoverage))
+	}
+	return violations
+}
+
+// writeDocsCheckText writes report as aligned package and module tables followed by any violations and a PASS/FAIL line.
+func writeDocsCheckText(w io.Writer, report docsCheckReport) error {
+	rows := make([][]string, 0, len(report.Packages))
+	for _, p := range report.Packages {
+		if p.Error != "" {
+			rows = append(rows, []string{p.Package, docsStatusError, docsStatusError, p.DocsFix, p.Reflow})
+			continue
+		}
+		rows = append(rows, []string{p.Package, p.Exported.String(), p.Important.String(), p.DocsFix, p.Reflow})
+	}
+	if err := writeAlignedTable(w, []string{"package", "exported", "important", "docs_fix", "reflow"}, rows); err != nil {
+		return err
+	}
+	if err := writeStringln(w, ""); err != nil {
+		return err
+	}
+
+	rows = rows[:0]
+	for _, m := range report.Modules {
+		rows = append(rows, []string{m.Module, m.Exported.String(), m.Important.String()})
+	}
+	if err := writeAlignedTable(w, []string{"module", "exported", "important"}, rows); err != nil {
+		return err
+	}
+
+	if report.Passed {
+		return writeStringln(w, "\nPASS")
+	}
+	if err := writeStringln(w, "\nViolations:"); err != nil {
+		return err
+	}
+	for _, v := range report.Violations {
+		scope := v.Package
+		if scope == "" {
+			scope = v.Module
+		}
+		if _, err := fmt.Fprintf(w, "- %s: %s\n", scope, v.Message); err != nil {
+			return err
+		}
+	}
+	return writeStringln(w, "FAIL")
+}
+
+func writeDocsCheckJSON(w io.Writer, report docsCheckReport) error {
+	if report.Violations == nil {
+		report.Violations = []docsCheckViolation{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(report)
+}
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a JUnit XML test suite.
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a JUnit XML test case; Failure is nil for passing cases.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+// junitFailure is a JUnit XML test case failure.
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeDocsCheckJUnit writes report as JUnit XML: one "packages" suite with a test case per package and one "modules" suite with a test case per module. Each
+// case fails if its package or module has violations.
+func writeDocsCheckJUnit(w io.Writer, report docsCheckReport) error {
+	byScope := map[string][]string{}
+	for _, v := range report.Violations {
+		key := "package:" + v.Package
+		if v.Package == "" {
+			key = "module:" + v.Module
+		}
+		byScope[key] = append(byScope[key], v.Message)
+	}
+	newCase := func(classname, name, key string) junitTestCase {
+		tc := junitTestCase{Name: name, ClassName: classname}
+		if msgs := byScope[key]; len(msgs) > 0 {
+			tc.Failure = &junitFailure{Message: msgs[0], Text: strings.Join(msgs, "\n")}
+		}
+		return tc
+	}
+	suite := func(name string, cases []junitTestCase) junitTestSuite {
+		s := junitTestSuite{Name: name, Tests: len(cases), Cases: cases}
+		for _, c := range cases {
+			if c.Failure != nil {
+				s.Failures++
+			}
+		}
+		return s
+	}
+
+	var pkgCases, modCases []junitTestCase
+	for _, p := range report.Packages {
+		pkgCases = append(pkgCases, newCase("codalotl.docs.check.packages", p.Package, "package:"+p.Package))
+	}
+	for _, m := range report.Modules {
+		modCases = append(modCases, newCase("codalotl.docs.check.modules", m.Module, "module:"+m.Module))
+	}
+	doc := junitTestSuites{Suites: []junitTestSuite{suite("packages", pkgCases), suite("modules", modCases)}}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return writeStringln(w, "")
+}
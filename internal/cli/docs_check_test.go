@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/gocas"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDocsCheckRepo(t *testing.T, config string) string {
+	t.Helper()
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	createGitRepoMarker(t, tmp)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	if config != "" {
+		writeProjectConfig(t, tmp, config)
+	}
+	writePackageFile(t, tmp, "p1", "// Package p1 is documented.\npackage p1\n\n// Foo does a thing.\nfunc Foo() {}\n")
+	writePackageFile(t, tmp, "p2", "package p2\n\nfunc Bar() {}\n\n// Baz does a thing.\nfunc Baz() {}\n")
+	t.Setenv(gocas.EnvCASDB, filepath.Join(tmp, "casdb"))
+	chdirForTest(t, tmp)
+	return tmp
+}
+
+func TestRun_DocsCheck_PassesWithoutThresholds(t *testing.T) {
+	setupDocsCheckRepo(t, "")
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "docs", "check"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+
+	got := out.String()
+	require.Contains(t, got, "./p1")
+	require.Contains(t, got, "100.0% (2/2)")
+	require.Contains(t, got, "33.3% (1/3)")
+	require.Contains(t, got, "example.com/tmpmod")
+	require.Contains(t, got, "60.0% (3/5)")
+	require.Contains(t, got, "\nPASS\n")
+}
+
+func TestRun_DocsCheck_FailsConfiguredThresholds(t *testing.T) {
+	setupDocsCheckRepo(t, `{"docscheck": {"minpackagecoverage": 50, "requiredocsfix": true}}`)
+
+	var out, errOut bytes.Buffer
+	code, _ := Run([]string{"codalotl", "docs", "check", "--format", "json"}, &RunOptions{Out: &out, Err: &errOut})
+	require.Equal(t, 1, code)
+
+	var report docsCheckReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	require.False(t, report.Passed)
+	require.Equal(t, 50.0, report.Thresholds.MinPackageCoverage)
+	require.Len(t, report.Packages, 2)
+	require.Equal(t, 3, report.Packages[1].Exported.Targets)
+	require.Equal(t, []docsCheckViolation{
+		{Package: "./p1", Message: "docs-fix certification is needed"},
+		{Package: "./p2", Message: "exported doc coverage 33.3% (1/3) is under 50%"},
+		{Package: "./p2", Message: "docs-fix certification is needed"},
+	}, report.Violations)
+}
+
+func TestRun_DocsCheck_FailUnderAndJUnit(t *testing.T) {
+	setupDocsCheckRepo(t, `{"docscheck": {"minexportedcoverage": 10}}`)
+
+	var out, errOut bytes.Buffer
+	code, _ := Run([]string{"codalotl", "docs", "check", "--fail-under", "75", "--format", "junit"}, &RunOptions{Out: &out, Err: &errOut})
+	require.Equal(t, 1, code)
+
+	got := out.String()
+	require.Contains(t, got, `<testsuite name="packages" tests="2" failures="0">`)
+	require.Contains(t, got, `<testsuite name="modules" tests="1" failures="1">`)
+	require.Contains(t, got, `<failure message="exported doc coverage 60.0% (3/5) is under 75%">`)
+
+	out.Reset()
+	_, err := Run([]string{"codalotl", "docs", "check", "--fail-under", "x"}, &RunOptions{Out: &out, Err: &errOut})
+	require.ErrorContains(t, err, `invalid --fail-under "x"`)
+	_, err = Run([]string{"codalotl", "docs", "check", "--format", "xml"}, &RunOptions{Out: &out, Err: &errOut})
+	require.ErrorContains(t, err, `invalid --format "xml"`)
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/docubot"
+	"github.com/codalotl/codalotl/internal/gocas"
+	"github.com/codalotl/codalotl/internal/gocode"
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+	"github.com/codalotl/codalotl/internal/q/health"
+	"github.com/codalotl/codalotl/internal/q/remotemonitor"
+	"github.com/codalotl/codalotl/internal/tools/exttools"
+)
+
+var docsExamplesCASNamespaceSpec = gocas.NamespaceSpec{
+	Name:     "docs-examples",
+	Version:  1,
+	HashMode: gocas.HashModePackage,
+}
+
+var runDocubotAddExamples = docubot.AddExamples
+
+// docsExamplesCASValue is the stored CAS payload for a docs-examples run. It records example coverage of the package's important exported identifiers.
+type docsExamplesCASValue struct {
+	Schema  string   `json:"schema"`            // Schema identifies the namespace's CAS schema.
+	Targets int      `json:"targets"`           // Targets is the number of important exported identifiers that should have examples.
+	Covered int      `json:"covered"`           // Covered is the number of Targets that have an example.
+	Added   []string `json:"added,omitempty"`   // Added are the Example functions written by the run.
+	Dropped []string `json:"dropped,omitempty"` // Dropped are the generated Example functions removed because they failed.
+}
+
+// newDocsExamplesCommand builds the docs examples command.
+func newDocsExamplesCommand(runWithConfig runWithConfigFunc) *qcli.Command {
+	cmd := &qcli.Command{
+		Name:  "examples",
+		Short: "Generate and verify runnable Example functions for important public APIs.",
+		Long: "Writes Example functions with // Output: blocks for important exported functions, types, and methods that don't have one, using an LLM. " +
+			"The examples are written to example_test.go and run with go test; failing examples get one fix attempt and are then dropped, so only passing examples are kept. " +
+			"Successful runs record example coverage in a docs-examples CAS record, shown by docs status.",
+		Usage: "<path/to/pkg>",
+		ArgHelp: []qcli.ArgHelp{
+			{
+				Display:     "<path/to/pkg>",
+				Description: packagePathArgDescription,
+			},
+		},
+		Complete: completeArgs(completePackageDirs),
+		Example: strings.TrimSpace(`
+codalotl docs examples internal/mypkg
+`),
+		Args: qcli.ExactArgs(1),
+	}
+	cmd.Run = runWithConfig("docs_examples", func(c *qcli.Context, cfg Config, _ *remotemonitor.Monitor) error {
+		pkg, mod, err := loadPackageArg(c.Args[0])
+		if err != nil {
+			return err
+		}
+
+		result, err := runDocubotAddExamples(pkg, docubot.AddExamplesOptions{
+			RunExamples: goTestExampleRunner(c.Context, mod.AbsolutePath),
+			BaseOptions: docubot.BaseOptions{
+				ReflowMaxWidth: cfg.ReflowWidth,
+				Context:        c.Context,
+				Out:            c.Out,
+				Model:          effectiveModel(cfg),
+				Ctx:            health.NewCtx(slog.New(slog.NewTextHandler(io.Discard, nil))),
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Key the CAS record against the package as it is after the new example file was written. pkg.Reload doesn't pick up new files, so load it afresh.
+		pkg, _, err = loadPackageDir(pkg.AbsolutePath())
+		if err != nil {
+			return err
+		}
+		if err := storeDocsExamplesCASRecord(pkg, mod, result); err != nil {
+			return err
+		}
+		return writeDocsExamplesSummary(c.Out, pkg, result)
+	})
+	return cmd
+}
+
+// goTestExampleRunner returns a docubot.ExampleRunner that runs examples with go test (via runGoTests) in the module at moduleRoot.
+func goTestExampleRunner(ctx context.Context, moduleRoot string) docubot.ExampleRunner {
+	return func(pkgDir string, pattern string) (bool, string, error) {
+		return runGoTests(ctx, moduleRoot, pkgDir, pattern)
+	}
+}
+
+// runGoTests runs go test (via the run_tests tool implementation) for the package at pkgDir in the module at moduleRoot, limited to tests matching pattern if
+// it is non-empty. ok reports whether the tests compiled and passed; output is the tool's report.
+var runGoTests = func(ctx context.Context, moduleRoot string, pkgDir string, pattern string) (ok bool, output string, err error) {
+	output, err = exttools.RunTests(ctx, moduleRoot, pkgDir, pattern, false, "")
+	if err != nil {
+		return false, "", err
+	}
+	return strings.Contains(output, `<test-status ok="true">`), output, nil
+}
+
+// storeDocsExamplesCASRecord stores a docs-examples CAS record for pkg's current contents.
+func storeDocsExamplesCASRecord(pkg *gocode.Package, mod *gocode.Module, result docubot.AddExamplesResult) error {
+	db, err := casDBForBaseDir(mod.AbsolutePath)
+	if err != nil {
+		return err
+	}
+	return db.Store(pkg, docsExamplesCASNamespaceSpec, docsExamplesCASValue{
+		Schema:  string(docsExamplesCASNamespaceSpec.Namespace()),
+		Targets: len(result.Targets),
+		Covered: len(result.Covered),
+		Added:   result.Added,
+		Dropped: result.Dropped,
+	})
+}
+
+// writeDocsExamplesSummary writes the added and dropped examples and the resulting example coverage of result.
+func writeDocsExamplesSummary(out io.Writer, pkg *gocode.Package, result docubot.AddExamplesResult) error {
+	var b strings.Builder
+	if len(result.Added) > 0 {
+		fmt.Fprintf(&b, "Added %d example(s) to %s: %s\n", len(result.Added), filepath.ToSlash(filepath.Join(pkg.RelativeDir, result.File)), strings.Join(result.Added, ", "))
+	} else {
+		b.WriteString("Added 0 example(s).\n")
+	}
+	if len(result.Dropped) > 0 {
+		fmt.Fprintf(&b, "Dropped %d failing example(s): %s\n", len(result.Dropped), strings.Join(result.Dropped, ", "))
+	}
+	fmt.Fprintf(&b, "Example coverage: %d/%d important exported identifiers.\n", len(result.Covered), len(result.Targets))
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// docsExamplesStatus reports example coverage for pkg from the module CAS database, reusing and populating dbs by module root. It returns "<covered>/<targets>"
+// for a record of the package's current contents, and docsStatusNeeded otherwise.
+func docsExamplesStatus(moduleRoot string, pkg *gocode.Package, dbs map[string]*gocas.DB) string {
+	db, err := cachedCASReadDBForBaseDir(dbs, moduleRoot)
+	if err != nil {
+		return docsStatusError
+	}
+
+	var value docsExamplesCASValue
+	ok, _, err := db.Retrieve(pkg, docsExamplesCASNamespaceSpec, &value)
+	if err != nil {
+		return docsStatusError
+	}
+	if !ok {
+		return docsStatusNeeded
+	}
+	return fmt.Sprintf("%d/%d", value.Covered, value.Targets)
+}
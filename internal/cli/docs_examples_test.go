@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/docubot"
+	"github.com/codalotl/codalotl/internal/gocas"
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_DocsExamples_WritesCASAndStatusReportsCoverage(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	createGitRepoMarker(t, tmp)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	writePackageFile(t, tmp, "p", "package p\n\n// Foo returns 1.\nfunc Foo() int { return 1 }\n\n// Bar returns 2.\nfunc Bar() int { return 2 }\n")
+	t.Setenv(gocas.EnvCASDB, filepath.Join(tmp, "casdb"))
+	chdirForTest(t, tmp)
+
+	orig := runDocubotAddExamples
+	t.Cleanup(func() { runDocubotAddExamples = orig })
+	runDocubotAddExamples = func(pkg *gocode.Package, opts docubot.AddExamplesOptions) (docubot.AddExamplesResult, error) {
+		require.NotNil(t, opts.RunExamples)
+		src := "package p_test\n\nfunc ExampleFoo() {}\n"
+		if err := os.WriteFile(filepath.Join(pkg.AbsolutePath(), "example_test.go"), []byte(src), 0644); err != nil {
+			return docubot.AddExamplesResult{}, err
+		}
+		return docubot.AddExamplesResult{
+			File:    "example_test.go",
+			Targets: []string{"Bar", "Foo"},
+			Covered: []string{"Foo"},
+			Added:   []string{"ExampleFoo"},
+			Dropped: []string{"ExampleBar"},
+		}, nil
+	}
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "docs", "examples", "./p"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Equal(t, "Added 1 example(s) to p/example_test.go: ExampleFoo\nDropped 1 failing example(s): ExampleBar\nExample coverage: 1/2 important exported identifiers.\n", out.String())
+
+	// The record is keyed against the package including the new example file.
+	var value docsExamplesCASValue
+	ok, _ := retrieveCASTestRecord(t, tmp, "docs-examples", "p", &value)
+	require.True(t, ok)
+	require.Equal(t, docsExamplesCASValue{Schema: "docs-examples-1", Targets: 2, Covered: 1, Added: []string{"ExampleFoo"}, Dropped: []string{"ExampleBar"}}, value)
+
+	out.Reset()
+	code, err = Run([]string{"codalotl", "docs", "status"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	rows, _ := parseDocsStatusRows(out.String())
+	require.Equal(t, "1/2", rows["./p"].examples)
+}
+
+func TestGoTestExampleRunner(t *testing.T) {
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	writePackageFile(t, tmp, "p", "package p\n\n// Foo returns 1.\nfunc Foo() int { return 1 }\n")
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "p", "example_test.go"), []byte(`package p_test
+
+import (
+	"fmt"
+
+	"example.com/tmpmod/p"
+)
+
+func ExampleFoo() {
+	fmt.Println(p.Foo())
+	// Output: 1
+}
+
+func ExampleFoo_wrong() {
+	fmt.Println(p.Foo())
+	// Output: 2
+}
+`), 0644))
+
+	run := goTestExampleRunner(context.Background(), tmp)
+	ok, _, err := run(filepath.Join(tmp, "p"), "^(ExampleFoo)$")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, output, err := run(filepath.Join(tmp, "p"), "^(ExampleFoo|ExampleFoo_wrong)$")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Contains(t, output, "--- FAIL: ExampleFoo_wrong")
+}
@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -26,7 +28,10 @@ const (
 	docsFixModeIdentifiers  = "identifiers"
 )
 
-var runDocubotFindAndFixDocErrors = docubot.FindAndFixDocErrors
+var (
+	runDocubotFindAndFixDocErrors    = docubot.FindAndFixDocErrors
+	runDocubotFindSpecContradictions = docubot.FindSpecContradictions
+)
 
 // docsFixCASValue is the stored CAS payload for a docs-fix run.
 type docsFixCASValue struct {
@@ -34,6 +39,10 @@ type docsFixCASValue struct {
 	Mode        string   `json:"mode"`                  // Mode records whether the value covers the whole package or selected identifiers.
 	Identifiers []string `json:"identifiers,omitempty"` // Identifiers is the sorted allowlist covered by an identifier-limited run.
 	FixCount    int      `json:"fix_count"`             // FixCount is the number of documentation fixes applied by the run.
+
+	// SpecContradictions is the number of contradictions between the package doc comment and SPEC.md reported by a whole-package run. They are reported, not
+	// fixed, so a record with contradictions is not current.
+	SpecContradictions int `json:"spec_contradictions,omitempty"`
 }
 
 // newDocsFixCommand builds the docs fix command.
@@ -43,7 +52,9 @@ func newDocsFixCommand(runWithConfig runWithConfigFunc) *qcli.Command {
 		Short: "Fix materially false documentation comments in a package.",
 		Long: "Finds materially false existing package documentation comments using an LLM and applies fixes. " +
 			"Missing documentation and non-material wording issues are ignored. " +
-			"By default, the command scans non-test files, test files, and black-box _test package files.",
+			"By default, the command scans non-test files, test files, and black-box _test package files. " +
+			"Whole-package runs also check the package doc comment against the package's SPEC.md (if any) and report contradictions without fixing them; " +
+			"use docs package-doc to regenerate the package doc from SPEC.md.",
 		Usage: "<path/to/pkg>",
 		ArgHelp: []qcli.ArgHelp{
 			{
@@ -51,6 +62,7 @@ func newDocsFixCommand(runWithConfig runWithConfigFunc) *qcli.Command {
 				Description: packagePathArgDescription,
 			},
 		},
+		Complete: completeArgs(completePackageDirs),
 		Example: strings.TrimSpace(`
 codalotl docs fix internal/mypkg
 codalotl docs fix --identifiers Foo,Bar ./internal/mypkg
@@ -70,23 +82,33 @@ codalotl docs fix --identifiers Foo,Bar ./internal/mypkg
 			return err
 		}
 
-		changes, err := runDocubotFindAndFixDocErrors(pkg, identifiers, docubot.FindFixDocErrorsOptions{
-			BaseOptions: docubot.BaseOptions{
-				ReflowMaxWidth: cfg.ReflowWidth,
-				Context:        c.Context,
-				Out:            c.Out,
-				Model:          effectiveModel(cfg),
-				Ctx:            health.NewCtx(slog.New(slog.NewTextHandler(io.Discard, nil))),
-			},
-		})
+		base := docubot.BaseOptions{
+			ReflowMaxWidth: cfg.ReflowWidth,
+			Context:        c.Context,
+			Out:            c.Out,
+			Model:          effectiveModel(cfg),
+			Ctx:            health.NewCtx(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		}
+		changes, err := runDocubotFindAndFixDocErrors(pkg, identifiers, docubot.FindFixDocErrorsOptions{BaseOptions: base})
 		if err != nil {
 			return err
 		}
 
-		if err := storeDocsFixCASRecord(pkg, mod, identifiers, len(changes)); err != nil {
+		var contradictions []docubot.SpecContradiction
+		if len(identifiers) == 0 {
+			contradictions, err = findDocsFixSpecContradictions(pkg, base)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := storeDocsFixCASRecord(pkg, mod, identifiers, len(changes), len(contradictions)); err != nil {
+			return err
+		}
+		if err := writeDocsFixSummary(c.Out, len(changes)); err != nil {
 			return err
 		}
-		return writeDocsFixSummary(c.Out, len(changes))
+		return writeSpecContradictions(c.Out, pkg, contradictions)
 	})
 	return cmd
 }
@@ -117,8 +139,8 @@ func parseDocsFixIdentifiers(s string) ([]string, error) {
 }
 
 // storeDocsFixCASRecord stores the docs-fix CAS result for pkg's current contents. A non-empty identifiers slice records an identifier-limited run; otherwise the
-// record covers the whole package. The stored payload includes fixCount and a sorted copy of the identifier allowlist.
-func storeDocsFixCASRecord(pkg *gocode.Package, mod *gocode.Module, identifiers []string, fixCount int) error {
+// record covers the whole package. The stored payload includes fixCount, specContradictions, and a sorted copy of the identifier allowlist.
+func storeDocsFixCASRecord(pkg *gocode.Package, mod *gocode.Module, identifiers []string, fixCount int, specContradictions int) error {
 	db, err := casDBForBaseDir(mod.AbsolutePath)
 	if err != nil {
 		return err
@@ -136,6 +158,8 @@ func storeDocsFixCASRecord(pkg *gocode.Package, mod *gocode.Module, identifiers
 		Mode:        mode,
 		Identifiers: canonicalIdentifiers,
 		FixCount:    fixCount,
+
+		SpecContradictions: specContradictions,
 	}
 	return db.Store(pkg, docsFixCASNamespaceSpec, value)
 }
@@ -144,3 +168,30 @@ func writeDocsFixSummary(w io.Writer, fixCount int) error {
 	_, err := fmt.Fprintf(w, "Applied %d documentation fix(es).\n", fixCount)
 	return err
 }
+
+// findDocsFixSpecContradictions checks the (possibly just fixed) package doc comment of pkg against its SPEC.md. A package without a SPEC.md has no contradictions.
+func findDocsFixSpecContradictions(pkg *gocode.Package, base docubot.BaseOptions) ([]docubot.SpecContradiction, error) {
+	reloaded, err := pkg.Reload()
+	if err != nil {
+		return nil, err
+	}
+	contradictions, err := runDocubotFindSpecContradictions(reloaded, docubot.SpecContradictionsOptions{BaseOptions: base})
+	if errors.Is(err, docubot.ErrNoSpec) {
+		return nil, nil
+	}
+	return contradictions, err
+}
+
+// writeSpecContradictions reports contradictions between pkg's package doc comment and SPEC.md. It writes nothing if there are none.
+func writeSpecContradictions(w io.Writer, pkg *gocode.Package, contradictions []docubot.SpecContradiction) error {
+	if len(contradictions) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d contradiction(s) between the package doc and %s (not fixed):\n", len(contradictions), filepath.ToSlash(filepath.Join(pkg.RelativeDir, "SPEC.md")))
+	for _, c := range contradictions {
+		fmt.Fprintf(&b, "- package doc: %q\n  SPEC.md: %q\n  %s\n", c.PackageDoc, c.Spec, c.Explanation)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
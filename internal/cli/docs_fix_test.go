@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/codalotl/codalotl/internal/docubot"
+	"github.com/codalotl/codalotl/internal/gocas"
 	"github.com/codalotl/codalotl/internal/gocode"
 	"github.com/codalotl/codalotl/internal/llmmodel"
 	"github.com/stretchr/testify/require"
@@ -151,3 +152,46 @@ func TestParseDocsFixIdentifiers(t *testing.T) {
 	_, err = parseDocsFixIdentifiers("Foo,,Bar")
 	require.Error(t, err)
 }
+
+func TestRun_DocsFix_ReportsSpecContradictions(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	writePackageFile(t, tmp, "p", "// Package p may return negative counts.\npackage p\n\n// Count counts.\nfunc Count() int { return 0 }\n")
+	t.Setenv(gocas.EnvCASDB, filepath.Join(tmp, "casdb"))
+	chdirForTest(t, tmp)
+
+	origFix := runDocubotFindAndFixDocErrors
+	origContradictions := runDocubotFindSpecContradictions
+	t.Cleanup(func() {
+		runDocubotFindAndFixDocErrors = origFix
+		runDocubotFindSpecContradictions = origContradictions
+	})
+	runDocubotFindAndFixDocErrors = func(*gocode.Package, []string, docubot.FindFixDocErrorsOptions) ([]docubot.IncorporatedFeedback, error) {
+		return nil, nil
+	}
+	contradictionCalls := 0
+	runDocubotFindSpecContradictions = func(*gocode.Package, docubot.SpecContradictionsOptions) ([]docubot.SpecContradiction, error) {
+		contradictionCalls++
+		return []docubot.SpecContradiction{{PackageDoc: "may return negative counts", Spec: "Counts are never negative.", Explanation: "The sign of counts differs."}}, nil
+	}
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "docs", "fix", "./p"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Equal(t, "Applied 0 documentation fix(es).\n"+
+		"Found 1 contradiction(s) between the package doc and p/SPEC.md (not fixed):\n"+
+		"- package doc: \"may return negative counts\"\n  SPEC.md: \"Counts are never negative.\"\n  The sign of counts differs.\n", out.String())
+
+	var value docsFixCASValue
+	ok, _ := retrieveCASTestRecord(t, tmp, "docs-fix", "p", &value)
+	require.True(t, ok)
+	require.Equal(t, 1, value.SpecContradictions)
+
+	// Identifier-limited runs don't check the package doc.
+	_, err = Run([]string{"codalotl", "docs", "fix", "--identifiers", "Count", "./p"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 1, contradictionCalls)
+}
@@ -0,0 +1,435 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/diff"
+	"github.com/codalotl/codalotl/internal/docubot"
+	"github.com/codalotl/codalotl/internal/gocas"
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/gopackagediff"
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+	"github.com/codalotl/codalotl/internal/q/health"
+	"github.com/codalotl/codalotl/internal/q/remotemonitor"
+)
+
+var docsImproveCASNamespaceSpec = gocas.NamespaceSpec{
+	Name:     "docs-improve",
+	Version:  1,
+	HashMode: gocas.HashModePackage,
+}
+
+var docsPolishCASNamespaceSpec = gocas.NamespaceSpec{
+	Name:     "docs-polish",
+	Version:  1,
+	HashMode: gocas.HashModePackage,
+}
+
+var docsPackageDocCASNamespaceSpec = gocas.NamespaceSpec{
+	Name:     "docs-package-doc",
+	Version:  1,
+	HashMode: gocas.HashModePackage,
+}
+
+var (
+	runDocubotImproveDocs        = docubot.ImproveDocs
+	runDocubotPolish             = docubot.Polish
+	runDocubotGeneratePackageDoc = docubot.GeneratePackageDoc
+)
+
+// docsRewriteCASValue is the stored CAS payload for a docs-improve, docs-polish, or docs-package-doc run.
+type docsRewriteCASValue struct {
+	Schema      string   `json:"schema"`                // Schema identifies the namespace's CAS schema.
+	Mode        string   `json:"mode"`                  // Mode records whether the value covers the whole package or selected identifiers.
+	Identifiers []string `json:"identifiers,omitempty"` // Identifiers is the sorted allowlist covered by an identifier-limited run.
+	ChangeCount int      `json:"change_count"`          // ChangeCount is the number of documentation changes applied by the run.
+}
+
+// docsRewriteFunc rewrites existing docs of identifiers in pkg (all documented identifiers if empty), editing pkg's files and returning the documentation diff.
+type docsRewriteFunc func(pkg *gocode.Package, identifiers []string, base docubot.BaseOptions) ([]*gopackagediff.Change, error)
+
+// docsRewriteCommand describes one of the docs commands that rewrite existing documentation (improve, polish, package-doc).
+type docsRewriteCommand struct {
+	name    string                 // name is the docs subcommand name.
+	short   string                 // short is the one-line help.
+	long    string                 // long is the detailed help, without the shared flag notes.
+	spec    gocas.NamespaceSpec    // spec is the CAS namespace written by successful applied runs.
+	flags   func(*qcli.Command)    // flags optionally registers command-specific flags.
+	rewrite func() docsRewriteFunc // rewrite returns the docubot operation, reading flag values; it is called after flags are parsed.
+
+	packageLevel bool                       // packageLevel means the operation has no per-identifier mode, so --identifiers is not offered.
+	applies      func(*gocode.Package) bool // applies optionally reports whether --all-packages should consider a package at all.
+}
+
+// newDocsImproveCommand builds the docs improve command.
+func newDocsImproveCommand(runWithConfig runWithConfigFunc) *qcli.Command {
+	var hideCurrentDocs *bool
+	return newDocsRewriteCommand(runWithConfig, docsRewriteCommand{
+		name:  "improve",
+		short: "Rewrite existing documentation comments where an LLM judges a better version.",
+		long: "Generates alternative documentation for documented identifiers using an LLM, has the LLM judge each alternative against the current comment, " +
+			"and keeps the winners. Undocumented identifiers are skipped. Use --hide-current-docs to generate alternatives from the code alone.",
+		spec: docsImproveCASNamespaceSpec,
+		flags: func(cmd *qcli.Command) {
+			hideCurrentDocs = cmd.Flags().Bool("hide-current-docs", 0, false, "Hide current docs from the LLM when generating alternatives (larger diffs).")
+		},
+		rewrite: func() docsRewriteFunc {
+			hide := *hideCurrentDocs
+			return func(pkg *gocode.Package, identifiers []string, base docubot.BaseOptions) ([]*gopackagediff.Change, error) {
+				return runDocubotImproveDocs(pkg, identifiers, docubot.ImproveDocsOptions{HideCurrentDocs: hide, BaseOptions: base})
+			}
+		},
+	})
+}
+
+// newDocsPolishCommand builds the docs polish command.
+func newDocsPolishCommand(runWithConfig runWithConfigFunc) *qcli.Command {
+	return newDocsRewriteCommand(runWithConfig, docsRewriteCommand{
+		name:  "polish",
+		short: "Make minimal style edits to existing documentation comments.",
+		long: "Fixes typos, grammar, awkward wording, and comment-style issues in existing documentation using an LLM. " +
+			"Missing documentation and material errors are ignored (see docs add and docs fix). Polishing already-polished docs is a no-op.",
+		spec: docsPolishCASNamespaceSpec,
+		rewrite: func() docsRewriteFunc {
+			return func(pkg *gocode.Package, identifiers []string, base docubot.BaseOptions) ([]*gopackagediff.Change, error) {
+				return runDocubotPolish(pkg, identifiers, docubot.PolishOptions{BaseOptions: base})
+			}
+		},
+	})
+}
+
+// newDocsPackageDocCommand builds the docs package-doc command.
+func newDocsPackageDocCommand(runWithConfig runWithConfigFunc) *qcli.Command {
+	return newDocsRewriteCommand(runWithConfig, docsRewriteCommand{
+		name:  "package-doc",
+		short: "Synthesize or update the package doc comment from SPEC.md and the public API.",
+		long: "Writes the package doc comment (in doc.go, or the file that already holds it) from the package's SPEC.md and its public API using an LLM, " +
+			"so the two don't drift apart. The comment is reflowed to reflowwidth. Packages without a SPEC.md are skipped with --all-packages and are an error otherwise.",
+		spec:         docsPackageDocCASNamespaceSpec,
+		packageLevel: true,
+		applies:      hasSpecMD,
+		rewrite: func() docsRewriteFunc {
+			return func(pkg *gocode.Package, _ []string, base docubot.BaseOptions) ([]*gopackagediff.Change, error) {
+				return runDocubotGeneratePackageDoc(pkg, docubot.PackageDocOptions{BaseOptions: base})
+			}
+		},
+	})
+}
+
+// newDocsRewriteCommand builds a docs command from spec. The command runs against a clone of each target package, prints a unified diff of the result, asks
+// for confirmation (unless --yes), and then copies changed files back and stores a CAS record. With --check, it only prints the diff.
+func newDocsRewriteCommand(runWithConfig runWithConfigFunc, spec docsRewriteCommand) *qcli.Command {
+	cmd := &qcli.Command{
+		Name:  spec.name,
+		Short: spec.short,
+		Long: spec.long + " The diff is printed and you are asked to confirm before it is applied; use --yes to apply without asking, or --check to only print it. " +
+			"With --all-packages, packages across discovered repo modules are processed unless their current contents already have a whole-package " + spec.spec.Name + " CAS record.",
+		Usage: "(<path/to/pkg> | --all-packages)",
+		ArgHelp: []qcli.ArgHelp{
+			{
+				Display:     "<path/to/pkg>",
+				Description: packagePathArgDescription,
+			},
+		},
+		Complete: completeArgs(completePackageDirs),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+codalotl docs %[1]s internal/mypkg
+codalotl docs %[1]s --identifiers Foo,Bar --check ./internal/mypkg
+codalotl docs %[1]s --all-packages --yes
+`, spec.name)),
+		Args: qcli.RangeArgs(0, 1),
+	}
+	flags := cmd.Flags()
+	identifiersFlag := new(string)
+	if spec.packageLevel {
+		cmd.Example = strings.TrimSpace(fmt.Sprintf(`
+codalotl docs %[1]s internal/mypkg
+codalotl docs %[1]s --check ./internal/mypkg
+codalotl docs %[1]s --all-packages --yes
+`, spec.name))
+	} else {
+		identifiersFlag = flags.String("identifiers", 0, "", "Comma-separated identifier allowlist.")
+	}
+	allPackages := flags.Bool("all-packages", 0, false, "Process packages across discovered repo modules that lack a current whole-package CAS record.")
+	check := flags.Bool("check", 0, false, "Don't write files or CAS records; only print the diff.")
+	yes := flags.Bool("yes", 'y', false, "Apply changes without asking for confirmation.")
+	if spec.flags != nil {
+		spec.flags(cmd)
+	}
+	cmd.Run = runWithConfig("docs_"+spec.name, func(c *qcli.Context, cfg Config, _ *remotemonitor.Monitor) error {
+		identifiers, err := parseDocsFixIdentifiers(*identifiersFlag)
+		if err != nil {
+			return qcli.UsageError{Message: err.Error()}
+		}
+		if *allPackages == (len(c.Args) == 1) {
+			return qcli.UsageError{Message: "supply exactly one of <path/to/pkg> or --all-packages"}
+		}
+		if *allPackages && len(identifiers) > 0 {
+			return qcli.UsageError{Message: "--identifiers cannot be combined with --all-packages"}
+		}
+
+		run := docsRewriteRun{
+			spec:    spec.spec,
+			rewrite: spec.rewrite(),
+			applies: spec.applies,
+			check:   *check,
+			yes:     *yes,
+			in:      bufio.NewReader(c.In),
+			out:     c.Out,
+			base: docubot.BaseOptions{
+				ReflowMaxWidth: cfg.ReflowWidth,
+				Context:        c.Context,
+				Out:            c.Out,
+				Model:          effectiveModel(cfg),
+				Ctx:            health.NewCtx(slog.New(slog.NewTextHandler(io.Discard, nil))),
+			},
+		}
+		if *allPackages {
+			return run.allPackages(c.Context)
+		}
+
+		pkg, mod, err := loadPackageArg(c.Args[0])
+		if err != nil {
+			return err
+		}
+		count, err := run.pkg(pkg, mod, identifiers)
+		if err != nil {
+			return err
+		}
+		return run.writeSummary(count)
+	})
+	return cmd
+}
+
+// docsRewriteRun holds the per-invocation state of a docs improve/polish/package-doc run.
+type docsRewriteRun struct {
+	spec    gocas.NamespaceSpec        // spec is the CAS namespace to store records in.
+	rewrite docsRewriteFunc            // rewrite performs the docubot operation.
+	applies func(*gocode.Package) bool // applies optionally filters the packages considered by allPackages.
+	check   bool                       // check means print diffs only; don't write files or CAS records.
+	yes     bool                       // yes means apply diffs without asking for confirmation.
+	in      *bufio.Reader              // in supplies confirmation answers.
+	out     io.Writer                  // out receives diffs, confirmation prompts, and summaries.
+	base    docubot.BaseOptions        // base is passed to rewrite.
+}
+
+// allPackages runs r against every package under the nearest git repo that lacks a current whole-package CAS record. Packages that fail to load are reported
+// and skipped; docubot errors stop the run.
+func (r docsRewriteRun) allPackages(ctx context.Context) error {
+	repoRoot, pkgDirs, err := goListPackageDirsUnderNearestGitRepo(ctx)
+	if err != nil {
+		return err
+	}
+	dbs := map[string]*gocas.DB{}
+	total := 0
+	for _, pkgDir := range pkgDirs {
+		display, ok := displayPackagePath(repoRoot, pkgDir.absDir)
+		if !ok {
+			continue
+		}
+		pkg, err := loadPackageFromRepoDir(pkgDir)
+		if err != nil {
+			if _, err := fmt.Fprintf(r.out, "Skipping %s: %v\n", display, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if r.applies != nil && !r.applies(pkg) {
+			continue
+		}
+		if docsRewriteStatus(pkgDir.mod.AbsolutePath, pkg, r.spec, dbs) == docsStatusCurrent {
+			continue
+		}
+		if _, err := fmt.Fprintf(r.out, "== %s\n", display); err != nil {
+			return err
+		}
+		count, err := r.pkg(pkg, pkgDir.mod, nil)
+		if errors.Is(err, docubot.ErrNoSpec) {
+			if _, err := fmt.Fprintf(r.out, "Skipping %s: %v\n", display, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", display, err)
+		}
+		total += count
+	}
+	return r.writeSummary(total)
+}
+
+// pkg runs r against a clone of pkg, prints the resulting file diffs, and (unless r.check) copies changed files into pkg and stores a CAS record. Unless r.yes,
+// the user must confirm the diff first; if they don't, nothing is written. It returns the number of documentation changes applied (or, with r.check, that would
+// be applied).
+func (r docsRewriteRun) pkg(pkg *gocode.Package, mod *gocode.Module, identifiers []string) (int, error) {
+	clone, err := pkg.Clone()
+	if err != nil {
+		return 0, err
+	}
+	defer clone.Module.DeleteClone()
+
+	changes, err := r.rewrite(clone, identifiers, r.base)
+	if err != nil {
+		return 0, err
+	}
+
+	changed, err := changedGoFiles(pkg.AbsolutePath(), clone.AbsolutePath())
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range changed {
+		name := filepath.ToSlash(filepath.Join(pkg.RelativeDir, f.name))
+		rendered := diff.DiffText(string(f.old), string(f.new)).RenderUnifiedDiff(false, name, name, 3)
+		if err := writeStringln(r.out, strings.TrimRight(rendered, "\n")); err != nil {
+			return 0, err
+		}
+	}
+	if r.check {
+		return len(changes), nil
+	}
+	if len(changed) > 0 && !r.yes {
+		ok, err := r.confirm()
+		if err != nil || !ok {
+			return 0, err
+		}
+	}
+
+	for _, f := range changed {
+		if err := os.WriteFile(filepath.Join(pkg.AbsolutePath(), f.name), f.new, f.mode); err != nil {
+			return 0, err
+		}
+	}
+	// Key the CAS record against the package as written. Reload the package from disk, since new files (ex: doc.go) aren't picked up by pkg.Reload.
+	written, _, err := loadPackageDir(pkg.AbsolutePath())
+	if err != nil {
+		return 0, err
+	}
+	return len(changes), storeDocsRewriteCASRecord(written, mod, r.spec, identifiers, len(changes))
+}
+
+// confirm asks whether to apply the printed diff and reports whether the user answered yes. No answer (ex: stdin at EOF) is a no.
+func (r docsRewriteRun) confirm() (bool, error) {
+	if _, err := io.WriteString(r.out, "Apply these changes? [y/N] "); err != nil {
+		return false, err
+	}
+	answer, err := r.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	msg := "Not applied.\n"
+	if !strings.HasSuffix(answer, "\n") {
+		msg = "\n" + msg // The prompt line wasn't ended by the user's input.
+	}
+	_, err = io.WriteString(r.out, msg)
+	return false, err
+}
+
+func (r docsRewriteRun) writeSummary(count int) error {
+	verb := "Applied"
+	if r.check {
+		verb = "Would apply"
+	}
+	_, err := fmt.Fprintf(r.out, "%s %d documentation change(s).\n", verb, count)
+	return err
+}
+
+// changedGoFile is a .go file whose contents differ between two package dirs.
+type changedGoFile struct {
+	name     string      // name is the file's base name.
+	old, new []byte      // old and new are the file contents before and after.
+	mode     os.FileMode // mode is the original file's permission bits.
+}
+
+// changedGoFiles returns the .go files whose contents differ between oldDir and newDir, sorted by name. Files that only exist in newDir (ex: a new doc.go) are
+// included with empty old contents; files removed from newDir are ignored, since docs operations never delete files.
+func changedGoFiles(oldDir, newDir string) ([]changedGoFile, error) {
+	entries, err := os.ReadDir(newDir)
+	if err != nil {
+		return nil, err
+	}
+	var changed []changedGoFile
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+		newBytes, err := os.ReadFile(filepath.Join(newDir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var oldBytes []byte
+		mode := os.FileMode(0644)
+		info, err := os.Stat(filepath.Join(oldDir, e.Name()))
+		switch {
+		case os.IsNotExist(err):
+		case err != nil:
+			return nil, err
+		default:
+			mode = info.Mode().Perm()
+			if oldBytes, err = os.ReadFile(filepath.Join(oldDir, e.Name())); err != nil {
+				return nil, err
+			}
+		}
+		if bytes.Equal(oldBytes, newBytes) {
+			continue
+		}
+		changed = append(changed, changedGoFile{name: e.Name(), old: oldBytes, new: newBytes, mode: mode})
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i].name < changed[j].name })
+	return changed, nil
+}
+
+// storeDocsRewriteCASRecord stores a spec CAS record for pkg's current contents. A non-empty identifiers slice records an identifier-limited run; otherwise the
+// record covers the whole package.
+func storeDocsRewriteCASRecord(pkg *gocode.Package, mod *gocode.Module, spec gocas.NamespaceSpec, identifiers []string, changeCount int) error {
+	db, err := casDBForBaseDir(mod.AbsolutePath)
+	if err != nil {
+		return err
+	}
+
+	mode := docsFixModeWholePackage
+	if len(identifiers) > 0 {
+		mode = docsFixModeIdentifiers
+	}
+	canonicalIdentifiers := append([]string(nil), identifiers...)
+	sort.Strings(canonicalIdentifiers)
+
+	return db.Store(pkg, spec, docsRewriteCASValue{
+		Schema:      string(spec.Namespace()),
+		Mode:        mode,
+		Identifiers: canonicalIdentifiers,
+		ChangeCount: changeCount,
+	})
+}
+
+// docsRewriteStatus reports the status of spec for pkg from the module CAS database, reusing and populating dbs by module root. Like docsFixStatus, only a whole-package
+// record for the package's current contents is current.
+func docsRewriteStatus(moduleRoot string, pkg *gocode.Package, spec gocas.NamespaceSpec, dbs map[string]*gocas.DB) string {
+	db, err := cachedCASReadDBForBaseDir(dbs, moduleRoot)
+	if err != nil {
+		return docsStatusError
+	}
+
+	var value docsRewriteCASValue
+	ok, _, err := db.Retrieve(pkg, spec, &value)
+	if err != nil {
+		return docsStatusError
+	}
+	if ok && value.Mode == docsFixModeWholePackage {
+		return docsStatusCurrent
+	}
+	return docsStatusNeeded
+}
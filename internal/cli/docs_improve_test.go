@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/docubot"
+	"github.com/codalotl/codalotl/internal/gocas"
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/gopackagediff"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDocsPolish replaces runDocubotPolish with a stub that rewrites "dose" to "does" in every .go file of the package it is given, recording the package dirs
+// and identifiers it was called with.
+func stubDocsPolish(t *testing.T) *[]string {
+	t.Helper()
+
+	var calls []string
+	orig := runDocubotPolish
+	t.Cleanup(func() { runDocubotPolish = orig })
+	runDocubotPolish = func(pkg *gocode.Package, identifiers []string, _ docubot.PolishOptions) ([]*gopackagediff.Change, error) {
+		calls = append(calls, filepath.Base(pkg.AbsolutePath()))
+		path := filepath.Join(pkg.AbsolutePath(), filepath.Base(pkg.AbsolutePath())+".go")
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		polished := bytes.ReplaceAll(b, []byte("dose"), []byte("does"))
+		if bytes.Equal(b, polished) {
+			return nil, nil
+		}
+		return []*gopackagediff.Change{{}}, os.WriteFile(path, polished, 0644)
+	}
+	return &calls
+}
+
+func TestRun_DocsPolish_PrintsDiffAppliesAndWritesCAS(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	writePackageFile(t, tmp, "p", "package p\n\n// Foo dose a thing.\nfunc Foo() {}\n")
+	t.Setenv(gocas.EnvCASDB, filepath.Join(tmp, "casdb"))
+	chdirForTest(t, tmp)
+	stubDocsPolish(t)
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "docs", "polish", "--check", "./p"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Contains(t, out.String(), "-// Foo dose a thing.\n+// Foo does a thing.\n")
+	require.Contains(t, out.String(), "Would apply 1 documentation change(s).\n")
+	got, err := os.ReadFile(filepath.Join(tmp, "p", "p.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(got), "dose")
+
+	// Declining the confirmation writes nothing.
+	out.Reset()
+	code, err = Run([]string{"codalotl", "docs", "polish", "./p"}, &RunOptions{In: strings.NewReader("n\n"), Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Contains(t, out.String(), " func Foo() {}\nApply these changes? [y/N] Not applied.\n")
+	require.Contains(t, out.String(), "Applied 0 documentation change(s).\n")
+	got, err = os.ReadFile(filepath.Join(tmp, "p", "p.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(got), "dose")
+	ok, _ := retrieveCASTestRecord(t, tmp, "docs-polish", "p", &docsRewriteCASValue{})
+	require.False(t, ok)
+
+	out.Reset()
+	code, err = Run([]string{"codalotl", "docs", "polish", "./p"}, &RunOptions{In: strings.NewReader("y\n"), Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Contains(t, out.String(), " func Foo() {}\nApply these changes? [y/N] ")
+	require.Contains(t, out.String(), "Applied 1 documentation change(s).\n")
+	got, err = os.ReadFile(filepath.Join(tmp, "p", "p.go"))
+	require.NoError(t, err)
+	require.Equal(t, "package p\n\n// Foo does a thing.\nfunc Foo() {}\n", string(got))
+
+	var value docsRewriteCASValue
+	ok, _ = retrieveCASTestRecord(t, tmp, "docs-polish", "p", &value)
+	require.True(t, ok)
+	require.Equal(t, docsFixModeWholePackage, value.Mode)
+	require.Equal(t, 1, value.ChangeCount)
+	require.Empty(t, errOut.String())
+}
+
+func TestRun_DocsPolish_AllPackagesSkipsCurrent(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	createGitRepoMarker(t, tmp)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	writePackageFile(t, tmp, "p1", "package p1\n\n// Foo dose a thing.\nfunc Foo() {}\n")
+	writePackageFile(t, tmp, "p2", "package p2\n\n// Bar dose a thing.\nfunc Bar() {}\n")
+	t.Setenv(gocas.EnvCASDB, filepath.Join(tmp, "casdb"))
+	storeCASTestRecord(t, tmp, "docs-polish", "p1", docsRewriteCASValue{Mode: docsFixModeWholePackage})
+	chdirForTest(t, tmp)
+	calls := stubDocsPolish(t)
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "docs", "polish", "--all-packages", "--yes"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Equal(t, []string{"p2"}, *calls)
+	require.Contains(t, out.String(), "== ./p2\n")
+	require.Contains(t, out.String(), "Applied 1 documentation change(s).\n")
+}
+
+func TestRun_DocsImprove_UsageErrors(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	writePackageFile(t, tmp, "p", "package p\n\n// Foo does a thing.\nfunc Foo() {}\n")
+	chdirForTest(t, tmp)
+
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{args: []string{"codalotl", "docs", "improve"}, wantErr: "supply exactly one of <path/to/pkg> or --all-packages"},
+		{args: []string{"codalotl", "docs", "improve", "--all-packages", "./p"}, wantErr: "supply exactly one of <path/to/pkg> or --all-packages"},
+		{args: []string{"codalotl", "docs", "improve", "--all-packages", "--identifiers", "Foo"}, wantErr: "--identifiers cannot be combined with --all-packages"},
+	}
+	for _, tt := range tests {
+		var out, errOut bytes.Buffer
+		_, err := Run(tt.args, &RunOptions{Out: &out, Err: &errOut})
+		require.ErrorContains(t, err, tt.wantErr, "%v", tt.args)
+	}
+}
+
+func TestRun_DocsImprove_ForwardsHideCurrentDocs(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	writePackageFile(t, tmp, "p", "package p\n\n// Foo does a thing.\nfunc Foo() {}\n")
+	t.Setenv(gocas.EnvCASDB, filepath.Join(tmp, "casdb"))
+	chdirForTest(t, tmp)
+
+	orig := runDocubotImproveDocs
+	t.Cleanup(func() { runDocubotImproveDocs = orig })
+	var gotIdentifiers []string
+	var gotOpts docubot.ImproveDocsOptions
+	runDocubotImproveDocs = func(_ *gocode.Package, identifiers []string, opts docubot.ImproveDocsOptions) ([]*gopackagediff.Change, error) {
+		gotIdentifiers = identifiers
+		gotOpts = opts
+		return nil, nil
+	}
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "docs", "improve", "--hide-current-docs", "--identifiers", "Foo", "./p"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.True(t, gotOpts.HideCurrentDocs)
+	require.Equal(t, []string{"Foo"}, gotIdentifiers)
+	require.Equal(t, "Applied 0 documentation change(s).\n", out.String())
+
+	var value docsRewriteCASValue
+	ok, _ := retrieveCASTestRecord(t, tmp, "docs-improve", "p", &value)
+	require.True(t, ok)
+	require.Equal(t, docsFixModeIdentifiers, value.Mode)
+}
+
+func TestRun_DocsPackageDoc_WritesDocGoAndSkipsPackagesWithoutSpec(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	createGitRepoMarker(t, tmp)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	writePackageFile(t, tmp, "p1", "package p1\n\n// Foo does a thing.\nfunc Foo() {}\n")
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "p1", "SPEC.md"), []byte("# p1\n\np1 does things.\n"), 0644))
+	writePackageFile(t, tmp, "p2", "package p2\n\n// Bar does a thing.\nfunc Bar() {}\n")
+	t.Setenv(gocas.EnvCASDB, filepath.Join(tmp, "casdb"))
+	chdirForTest(t, tmp)
+
+	orig := runDocubotGeneratePackageDoc
+	t.Cleanup(func() { runDocubotGeneratePackageDoc = orig })
+	var calls []string
+	runDocubotGeneratePackageDoc = func(pkg *gocode.Package, _ docubot.PackageDocOptions) ([]*gopackagediff.Change, error) {
+		calls = append(calls, filepath.Base(pkg.AbsolutePath()))
+		return []*gopackagediff.Change{{}}, os.WriteFile(filepath.Join(pkg.AbsolutePath(), "doc.go"), []byte("// Package p1 does things.\npackage p1\n"), 0644)
+	}
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "docs", "package-doc", "--all-packages", "--yes"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Equal(t, []string{"p1"}, calls)
+	require.Contains(t, out.String(), "== ./p1\n")
+	require.Contains(t, out.String(), "+// Package p1 does things.\n")
+	require.Contains(t, out.String(), "Applied 1 documentation change(s).\n")
+	got, err := os.ReadFile(filepath.Join(tmp, "p1", "doc.go"))
+	require.NoError(t, err)
+	require.Equal(t, "// Package p1 does things.\npackage p1\n", string(got))
+
+	var value docsRewriteCASValue
+	ok, _ := retrieveCASTestRecord(t, tmp, "docs-package-doc", "p1", &value)
+	require.True(t, ok)
+
+	_, err = Run([]string{"codalotl", "docs", "package-doc", "--identifiers", "Foo", "./p1"}, &RunOptions{Out: &out, Err: &errOut})
+	require.ErrorContains(t, err, "identifiers")
+}
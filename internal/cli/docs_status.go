@@ -29,6 +29,9 @@ type docsStatusRow struct {
 	Package string // Display package path.
 	DocsAdd string // Status of missing-documentation coverage.
 	DocsFix string // Status of material documentation correctness.
+	Improve string // Status of the docs-improve rewrite pass.
+	Polish  string // Status of the docs-polish style pass.
+	Example string // Example coverage ("covered/targets") from the docs-examples pass, or a status.
 	Reflow  string // Status of deterministic documentation reflow.
 }
 
@@ -36,7 +39,7 @@ func newDocsStatusCommand(runWithConfig runWithConfigFunc) *qcli.Command {
 	statusCmd := &qcli.Command{
 		Name:             "status",
 		Short:            "Print per-package documentation status across discovered repo modules.",
-		Long:             "Prints a table for packages across Go modules discovered from the nearest git repo, showing docs-add, docs-fix, and documentation reflow status.",
+		Long:             "Prints a table for packages across Go modules discovered from the nearest git repo, showing docs-add, docs-fix, docs-improve, docs-polish, example coverage, and documentation reflow status.",
 		Args:             qcli.NoArgs,
 		NoPositionalArgs: true,
 		Example: strings.TrimSpace(`
@@ -69,6 +72,9 @@ func runDocsStatus(ctx context.Context, out io.Writer, reflowWidth int) error {
 			Package: display,
 			DocsAdd: docsStatusError,
 			DocsFix: docsStatusError,
+			Improve: docsStatusError,
+			Polish:  docsStatusError,
+			Example: docsStatusError,
 			Reflow:  docsStatusError,
 		}
 
@@ -80,6 +86,9 @@ func runDocsStatus(ctx context.Context, out io.Writer, reflowWidth int) error {
 
 		row.DocsAdd = docsAddStatus(pkg)
 		row.DocsFix = docsFixStatus(pkgDir.mod.AbsolutePath, pkg, dbs)
+		row.Improve = docsRewriteStatus(pkgDir.mod.AbsolutePath, pkg, docsImproveCASNamespaceSpec, dbs)
+		row.Polish = docsRewriteStatus(pkgDir.mod.AbsolutePath, pkg, docsPolishCASNamespaceSpec, dbs)
+		row.Example = docsExamplesStatus(pkgDir.mod.AbsolutePath, pkg, dbs)
 		row.Reflow = docsReflowStatus(pkg, reflowWidth)
 		rows = append(rows, row)
 	}
@@ -116,7 +125,8 @@ func docsAddStatus(pkg *gocode.Package) string {
 }
 
 // docsFixStatus reports the docs-fix status for pkg from the module CAS database. It reuses and populates dbs by module root. It returns docsStatusCurrent only
-// for a whole-package docs-fix record for the package's current contents; identifier-limited records count as docsStatusNeeded, and database errors return docsStatusError.
+// for a whole-package docs-fix record for the package's current contents that reported no SPEC.md contradictions; identifier-limited records count as docsStatusNeeded,
+// and database errors return docsStatusError.
 func docsFixStatus(moduleRoot string, pkg *gocode.Package, dbs map[string]*gocas.DB) string {
 	db, err := cachedCASReadDBForBaseDir(dbs, moduleRoot)
 	if err != nil {
@@ -128,7 +138,7 @@ func docsFixStatus(moduleRoot string, pkg *gocode.Package, dbs map[string]*gocas
 	if err != nil {
 		return docsStatusError
 	}
-	if ok && value.Mode == docsFixModeWholePackage {
+	if ok && value.Mode == docsFixModeWholePackage && value.SpecContradictions == 0 {
 		return docsStatusCurrent
 	}
 	return docsStatusNeeded
@@ -157,7 +167,7 @@ func docsReflowStatus(pkg *gocode.Package, reflowWidth int) string {
 func writeDocsStatusTable(w io.Writer, rows []docsStatusRow) error {
 	tableRows := make([][]string, 0, len(rows))
 	for _, r := range rows {
-		tableRows = append(tableRows, []string{r.Package, r.DocsAdd, r.DocsFix, r.Reflow})
+		tableRows = append(tableRows, []string{r.Package, r.DocsAdd, r.DocsFix, r.Improve, r.Polish, r.Example, r.Reflow})
 	}
-	return writeAlignedTable(w, []string{"package", "docs_add", "docs_fix", "reflow"}, tableRows)
+	return writeAlignedTable(w, []string{"package", "docs_add", "docs_fix", "docs_improve", "docs_polish", "docs_examples", "reflow"}, tableRows)
 }
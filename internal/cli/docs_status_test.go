@@ -53,6 +53,20 @@ func Baz() {}
 		Identifiers: []string{"Bar"},
 		FixCount:    0,
 	})
+	storeCASTestRecord(t, tmp, "docs-polish", "p1", docsRewriteCASValue{
+		Schema: string(docsPolishCASNamespaceSpec.Namespace()),
+		Mode:   docsFixModeWholePackage,
+	})
+	storeCASTestRecord(t, tmp, "docs-polish", "p2", docsRewriteCASValue{
+		Schema:      string(docsPolishCASNamespaceSpec.Namespace()),
+		Mode:        docsFixModeIdentifiers,
+		Identifiers: []string{"Bar"},
+	})
+	storeCASTestRecord(t, tmp, "docs-examples", "p1", docsExamplesCASValue{
+		Schema:  string(docsExamplesCASNamespaceSpec.Namespace()),
+		Targets: 1,
+		Covered: 1,
+	})
 	p2Path := filepath.Join(tmp, "p2", "p2.go")
 	p2ModTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
 	require.NoError(t, os.Chtimes(p2Path, p2ModTime, p2ModTime))
@@ -100,9 +114,9 @@ func Baz() {}
 
 	rows, order := parseDocsStatusRows(out.String())
 	require.Equal(t, []string{"./p1", "./p2", "./p3"}, order)
-	require.Equal(t, docsStatusTestRow{docsAdd: "current", docsFix: "current", reflow: "current"}, rows["./p1"])
-	require.Equal(t, docsStatusTestRow{docsAdd: "needed", docsFix: "needed", reflow: "needed"}, rows["./p2"])
-	require.Equal(t, docsStatusTestRow{docsAdd: "error", docsFix: "needed", reflow: "current"}, rows["./p3"])
+	require.Equal(t, docsStatusTestRow{docsAdd: "current", docsFix: "current", improve: "needed", polish: "current", examples: "1/1", reflow: "current"}, rows["./p1"])
+	require.Equal(t, docsStatusTestRow{docsAdd: "needed", docsFix: "needed", improve: "needed", polish: "needed", examples: "needed", reflow: "needed"}, rows["./p2"])
+	require.Equal(t, docsStatusTestRow{docsAdd: "error", docsFix: "needed", improve: "needed", polish: "needed", examples: "needed", reflow: "current"}, rows["./p3"])
 
 	gotP2, err := os.ReadFile(p2Path)
 	require.NoError(t, err)
@@ -114,9 +128,12 @@ func Baz() {}
 }
 
 type docsStatusTestRow struct {
-	docsAdd string
-	docsFix string
-	reflow  string
+	docsAdd  string
+	docsFix  string
+	improve  string
+	polish   string
+	examples string
+	reflow   string
 }
 
 func parseDocsStatusRows(s string) (map[string]docsStatusTestRow, []string) {
@@ -124,14 +141,14 @@ func parseDocsStatusRows(s string) (map[string]docsStatusTestRow, []string) {
 	var order []string
 	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
 		fields := strings.Fields(line)
-		if len(fields) != 4 {
+		if len(fields) != 7 {
 			continue
 		}
 		if !strings.HasPrefix(fields[0], ".") {
 			continue
 		}
 		pkg := fields[0]
-		rows[pkg] = docsStatusTestRow{docsAdd: fields[1], docsFix: fields[2], reflow: fields[3]}
+		rows[pkg] = docsStatusTestRow{docsAdd: fields[1], docsFix: fields[2], improve: fields[3], polish: fields[4], examples: fields[5], reflow: fields[6]}
 		order = append(order, pkg)
 	}
 	return rows, order
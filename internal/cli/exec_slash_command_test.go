@@ -129,3 +129,25 @@ func TestRun_Exec_SlashCommand_ForwardsPromptAndExistingFlags(t *testing.T) {
 	require.Equal(t, "gpt-5.5-high", string(gotOpts.ModelID))
 	require.Empty(t, errOut.String())
 }
+
+func TestRun_Exec_SlashCommandCustom_AllowsEmptyPrompt(t *testing.T) {
+	isolateUserConfig(t)
+	chdirForTest(t, t.TempDir())
+
+	var gotPrompt string
+	var gotOpts noninteractive.Options
+	stubRunNoninteractiveExec(t, func(userPrompt string, opts noninteractive.Options) error {
+		gotPrompt = userPrompt
+		gotOpts = opts
+		return nil
+	})
+
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "exec", "--slash-command=/review"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Empty(t, gotPrompt)
+	require.Equal(t, "/review", gotOpts.SlashCommand)
+	require.Empty(t, errOut.String())
+}
@@ -80,7 +80,8 @@ codalotl iterate --orchestrate --yes "Implement this plan"
 	noColor := flags.Bool("no-color", 0, false, "Disable ANSI colors and formatting.")
 	outputJSON := flags.Bool("json", 0, false, "Output newline-delimited JSON.")
 	model := flags.String("model", 0, "", "LLM model ID to use (overrides config preferredmodel; empty = default).")
-	slashCommand := flags.String("slash-command", 0, "", "Apply a TUI-style slash command at session start (supported: orchestrate, /orchestrate).")
+	flags.SetComplete("model", completeModelIDs)
+	slashCommand := flags.String("slash-command", 0, "", "Apply a TUI-style slash command at session start (orchestrate, or a custom command from .codalotl/commands).")
 
 	iterateCmd.Args = func(args []string) error {
 		normalizedSlashCommand, err := normalizeIterateSlashCommand(*orchestrate, *slashCommand)
@@ -247,7 +248,14 @@ func resolveIteratePrompt(args []string, promptFile string, allowEmpty bool) (st
 	return "", qcli.UsageError{Message: "prompt is required unless --orchestrate or --slash-command starts a session without an initial message"}
 }
 
+// slashCommandAllowsEmptyInitialPrompt reports whether slashCommand starts a session without an initial message. Orchestrate and custom commands both do (a custom
+// command's prompt is its rendered template).
 func slashCommandAllowsEmptyInitialPrompt(slashCommand string) bool {
+	return strings.TrimSpace(slashCommand) != ""
+}
+
+// isOrchestrateSlashCommand reports whether slashCommand selects the built-in orchestrator, which ignores --package.
+func isOrchestrateSlashCommand(slashCommand string) bool {
 	switch strings.TrimSpace(slashCommand) {
 	case "orchestrate", "/orchestrate":
 		return true
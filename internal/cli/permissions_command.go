@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+	"github.com/codalotl/codalotl/internal/q/remotemonitor"
+	"github.com/codalotl/codalotl/internal/tools/authdomain"
+)
+
+// newPermissionsCommand builds the `permissions` command tree for auditing and editing the project permission rules in the current directory's
+// `.codalotl/permissions.json`. Rules are numbered from 1 in file order, as printed by `permissions ls`.
+func newPermissionsCommand(runWithConfig runWithConfigFunc) *qcli.Command {
+	permissionsCmd := &qcli.Command{
+		Name:  "permissions",
+		Short: "Manage project permission rules.",
+		Long:  "List, add, and remove the allow/ask/deny rules in " + authdomain.PermissionsFile + " (relative to the current directory).",
+	}
+
+	lsCmd := &qcli.Command{
+		Name:             "ls",
+		Short:            "List project permission rules.",
+		Long:             "Lists the rules in " + authdomain.PermissionsFile + ", numbered in file order.",
+		Args:             qcli.NoArgs,
+		NoPositionalArgs: true,
+		Example: strings.TrimSpace(`
+codalotl permissions ls
+`),
+		Run: runWithConfig("permissions_ls", func(c *qcli.Context, _ Config, _ *remotemonitor.Monitor) error {
+			dir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			rules, err := authdomain.LoadPermissionRules(dir)
+			if err != nil {
+				return qcli.ExitError{Code: 1, Err: err}
+			}
+			path := filepath.Join(dir, filepath.FromSlash(authdomain.PermissionsFile))
+			if len(rules) == 0 {
+				return writeStringln(c.Out, "No permission rules in "+path)
+			}
+			if err := writeStringln(c.Out, path+":"); err != nil {
+				return err
+			}
+			for i, rule := range rules {
+				if _, err := fmt.Fprintf(c.Out, "%d. %s\n", i+1, rule.String()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}),
+	}
+
+	addCmd := &qcli.Command{
+		Name:  "add",
+		Short: "Add a project permission rule.",
+		Long: strings.TrimSpace(`
+Adds an allow, ask, or deny rule to ` + authdomain.PermissionsFile + `.
+
+A command rule (--command) matches shell commands that start with the given words, or with --exact, only that exact command.
+A filesystem rule (--path and/or --tool) matches reads and writes. --path is a glob relative to the project dir (or absolute) and covers everything under a matching dir. --tool limits the rule to one tool.
+Precedence is deny > ask > allow.
+`),
+		Usage: "<allow|ask|deny>",
+		Args:  qcli.ExactArgs(1),
+		Example: strings.TrimSpace(`
+codalotl permissions add allow --command "go test"
+codalotl permissions add allow --command "make release" --exact
+codalotl permissions add deny --command "git push"
+codalotl permissions add deny --path .env
+codalotl permissions add ask --tool write_file --path go.mod
+`),
+	}
+	command := addCmd.Flags().String("command", 0, "", "Shell command prefix to match.")
+	path := addCmd.Flags().String("path", 0, "", "Path glob to match, relative to the project dir or absolute.")
+	tool := addCmd.Flags().String("tool", 0, "", "Tool name to match (ex: write_file).")
+	exact := addCmd.Flags().Bool("exact", 0, false, "Match only the exact --command, not longer commands that start with it.")
+	addCmd.Run = runWithConfig("permissions_add", func(c *qcli.Context, _ Config, _ *remotemonitor.Monitor) error {
+		rule := authdomain.PermissionRule{
+			Action:  authdomain.PermissionAction(strings.ToLower(strings.TrimSpace(c.Args[0]))),
+			Command: strings.TrimSpace(*command),
+			Path:    strings.TrimSpace(*path),
+			Tool:    strings.TrimSpace(*tool),
+			Exact:   *exact,
+		}
+		if err := rule.Validate(); err != nil {
+			return qcli.UsageError{Message: err.Error()}
+		}
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		added, err := authdomain.AddPermissionRule(dir, rule)
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: err}
+		}
+		if !added {
+			return writeStringln(c.Out, "Rule already exists: "+rule.String())
+		}
+		return writeStringln(c.Out, "Added rule: "+rule.String())
+	})
+
+	rmCmd := &qcli.Command{
+		Name:  "rm",
+		Short: "Remove a project permission rule.",
+		Long:  "Removes rule number n (as printed by `codalotl permissions ls`) from " + authdomain.PermissionsFile + ".",
+		Usage: "<n>",
+		Args:  qcli.ExactArgs(1),
+		Example: strings.TrimSpace(`
+codalotl permissions rm 2
+`),
+		Run: runWithConfig("permissions_rm", func(c *qcli.Context, _ Config, _ *remotemonitor.Monitor) error {
+			n, err := strconv.Atoi(strings.TrimSpace(c.Args[0]))
+			if err != nil || n < 1 {
+				return qcli.UsageError{Message: fmt.Sprintf("invalid rule number %q", c.Args[0])}
+			}
+			dir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			removed, err := authdomain.RemovePermissionRule(dir, n-1)
+			if err != nil {
+				return qcli.ExitError{Code: 1, Err: err}
+			}
+			return writeStringln(c.Out, "Removed rule: "+removed.String())
+		}),
+	}
+
+	permissionsCmd.AddCommand(lsCmd, addCmd, rmCmd)
+	return permissionsCmd
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/tools/authdomain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Permissions_AddLsRm(t *testing.T) {
+	isolateUserConfig(t)
+	t.Setenv("OPENAI_API_KEY", "")
+
+	dir := t.TempDir()
+	origWD, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+
+	run := func(args ...string) (int, string) {
+		t.Helper()
+		var out, errOut bytes.Buffer
+		code, err := Run(append([]string{"codalotl", "permissions"}, args...), &RunOptions{Out: &out, Err: &errOut})
+		if err != nil && code == 0 {
+			code = 1
+		}
+		return code, out.String() + errOut.String()
+	}
+
+	code, out := run("ls")
+	require.Equal(t, 0, code)
+	require.Contains(t, out, "No permission rules")
+
+	code, out = run("add", "allow", "--command", "go test")
+	require.Equal(t, 0, code)
+	require.Contains(t, out, "Added rule: allow command `go test`")
+
+	code, _ = run("add", "deny", "--path", ".env")
+	require.Equal(t, 0, code)
+
+	code, out = run("add", "allow", "--command", "go test")
+	require.Equal(t, 0, code)
+	require.Contains(t, out, "already exists")
+
+	code, _ = run("add", "maybe", "--path", ".env")
+	require.NotEqual(t, 0, code)
+	code, _ = run("add", "allow", "--command", "ls", "--path", "x")
+	require.NotEqual(t, 0, code)
+	code, _ = run("add", "allow", "--path", "x", "--exact")
+	require.NotEqual(t, 0, code)
+
+	code, out = run("add", "allow", "--command", "make release", "--exact")
+	require.Equal(t, 0, code)
+	require.Contains(t, out, "Added rule: allow exact command `make release`")
+
+	code, out = run("ls")
+	require.Equal(t, 0, code)
+	require.Contains(t, out, "1. allow command `go test`")
+	require.Contains(t, out, "2. deny path .env")
+	require.Contains(t, out, "3. allow exact command `make release`")
+
+	code, out = run("rm", "1")
+	require.Equal(t, 0, code)
+	require.Contains(t, out, "Removed rule: allow command `go test`")
+
+	code, _ = run("rm", "5")
+	require.NotEqual(t, 0, code)
+
+	rules, err := authdomain.LoadPermissionRules(dir)
+	require.NoError(t, err)
+	require.Equal(t, []authdomain.PermissionRule{
+		{Action: authdomain.PermissionDeny, Path: ".env"},
+		{Action: authdomain.PermissionAllow, Command: "make release", Exact: true},
+	}, rules)
+}
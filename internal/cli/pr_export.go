@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+)
+
+// Formats of codalotl pr export.
+const (
+	prExportFormatPatch = "patch"
+	prExportFormatBody  = "body"
+)
+
+// prExportOptions configures runPRExport.
+type prExportOptions struct {
+	PRFileArg string // PRFileArg is the PR file path, or "" to infer it.
+	Format    string // Format is prExportFormatPatch or prExportFormatBody.
+	Out       string // Out is the output directory (patch) or file (body); "" writes to stdout.
+	Title     string // Title is the body's title; "" derives it from the feature name.
+}
+
+// newPRExportCommand builds the pr export command.
+func newPRExportCommand() *qcli.Command {
+	cmd := &qcli.Command{
+		Name:  "export",
+		Short: "Export the PR as a patch series or description body.",
+		Long: "Exports the PR on the current branch for a code review tool. --format=patch (the default) writes the branch's commits since its base as a git format-patch series: " +
+			"to stdout as one mbox, or with --out, as numbered .patch files in that directory. --format=body writes the PR title and the PR file's ## Summary section as markdown, " +
+			"to stdout or the --out file; run codalotl pr finish first if the summary is empty.",
+		Usage: "[--format=patch|body] [--out=<path>] [<path/to/pr-file>]",
+		ArgHelp: []qcli.ArgHelp{
+			{
+				Display:     "<path/to/pr-file>",
+				Description: "PR file to export. Defaults to the .prs file changed on the current branch, or the one named after the branch.",
+			},
+		},
+		Complete: completeArgs(completeMarkdownFiles),
+		Example: strings.TrimSpace(`
+codalotl pr export > cas-prune.mbox
+codalotl pr export --out=patches
+codalotl pr export --format=body --title "Add CAS pruning" --out=body.md
+`),
+		Args: qcli.RangeArgs(0, 1),
+	}
+	flags := cmd.Flags()
+	format := flags.String("format", 0, prExportFormatPatch, "Export format: patch or body.")
+	outPath := flags.String("out", 'o', "", "Output directory (patch) or file (body). Default: stdout.")
+	title := flags.String("title", 0, "", "Title of the body (default: derived from the feature name).")
+	cmd.Run = func(c *qcli.Context) error {
+		opts := prExportOptions{Format: strings.TrimSpace(*format), Out: strings.TrimSpace(*outPath), Title: *title}
+		if len(c.Args) == 1 {
+			opts.PRFileArg = c.Args[0]
+		}
+		return runPRExport(c.Context, c.Out, opts)
+	}
+	return cmd
+}
+
+// runPRExport exports the PR on the current branch (see newPRExportCommand) to out or opts.Out.
+func runPRExport(ctx context.Context, out io.Writer, opts prExportOptions) error {
+	if opts.Format != prExportFormatPatch && opts.Format != prExportFormatBody {
+		return qcli.UsageError{Message: fmt.Sprintf("invalid --format %q: must be patch or body", opts.Format)}
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	branch, err := loadPRBranch(ctx, cwd)
+	if err != nil {
+		return qcli.ExitError{Code: 1, Err: err}
+	}
+
+	if opts.Format == prExportFormatBody {
+		pr, err := findPRFile(branch, opts.PRFileArg)
+		if err != nil {
+			return err
+		}
+		summary := pr.Sections[prSectionSummary]
+		if summary == "" {
+			return qcli.ExitError{Code: 1, Err: fmt.Errorf("%s has no ## Summary; run codalotl pr finish first", pr.RelPath)}
+		}
+		title := strings.TrimSpace(opts.Title)
+		if title == "" {
+			title = prDefaultTitle(pr.FeatureName)
+		}
+		body := "# " + title + "\n\n" + summary + "\n"
+		if opts.Out == "" {
+			_, err := io.WriteString(out, body)
+			return err
+		}
+		if err := os.WriteFile(opts.Out, []byte(body), 0644); err != nil {
+			return err
+		}
+		return writeStringln(out, "Wrote "+opts.Out)
+	}
+
+	if len(branch.Commits) == 0 {
+		return qcli.ExitError{Code: 1, Err: errors.New("no commits on the current branch since its base")}
+	}
+	revRange := branch.BaseCommit + "..HEAD"
+	if opts.Out == "" {
+		patches, err := gitOutput(ctx, branch.RepoRoot, "format-patch", "--stdout", revRange)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(out, patches)
+		return err
+	}
+	outDir, err := filepath.Abs(opts.Out)
+	if err != nil {
+		return err
+	}
+	files, err := gitOutput(ctx, branch.RepoRoot, "format-patch", "--output-directory", outDir, revRange)
+	if err != nil {
+		return err
+	}
+	for _, f := range parseNonEmptyLines([]byte(files)) {
+		display := f
+		if rel, err := filepath.Rel(cwd, f); err == nil && !strings.HasPrefix(rel, "..") {
+			display = rel
+		}
+		if err := writeStringln(out, filepath.ToSlash(display)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_PRExport(t *testing.T) {
+	isolateUserConfigWithRealGit(t)
+	repo := newPRTestRepo(t)
+	prTestGit(t, repo, "checkout", "-b", "cas-prune")
+	writePRTestFile(t, repo, ".prs/2026-05-02_1777680000_cas-prune.md", testPRFileContent)
+	prTestGit(t, repo, "add", ".")
+	prTestGit(t, repo, "commit", "-m", "Add PR file")
+	writePRTestFile(t, repo, "prune.txt", "prune\n")
+	prTestGit(t, repo, "add", ".")
+	prTestGit(t, repo, "commit", "-m", "Add prune")
+	chdirForTest(t, repo)
+
+	run := func(args ...string) (string, int, error) {
+		var out bytes.Buffer
+		var errOut bytes.Buffer
+		code, err := Run(append([]string{"codalotl", "pr", "export"}, args...), &RunOptions{Out: &out, Err: &errOut})
+		return out.String(), code, err
+	}
+
+	got, code, err := run()
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Contains(t, got, "Subject: [PATCH 1/2] Add PR file\n")
+	assert.Contains(t, got, "Subject: [PATCH 2/2] Add prune\n")
+
+	got, code, err = run("--out=patches")
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Equal(t, "patches/0001-Add-PR-file.patch\npatches/0002-Add-prune.patch\n", got)
+	assert.FileExists(t, filepath.Join(repo, "patches", "0002-Add-prune.patch"))
+
+	_, code, err = run("--format=body")
+	require.ErrorContains(t, err, "has no ## Summary; run codalotl pr finish first")
+	require.Equal(t, 1, code)
+
+	prPath := filepath.Join(repo, ".prs", "2026-05-02_1777680000_cas-prune.md")
+	require.NoError(t, os.WriteFile(prPath, []byte(withPRSummary(testPRFileContent, "Adds CAS pruning.")), 0644))
+	got, code, err = run("--format=body")
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Equal(t, "# Cas prune\n\nAdds CAS pruning.\n", got)
+
+	got, code, err = run("--format=body", "--title", "Add CAS pruning", "--out=body.md")
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Equal(t, "Wrote body.md\n", got)
+	body, err := os.ReadFile(filepath.Join(repo, "body.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Add CAS pruning\n\nAdds CAS pruning.\n", string(body))
+
+	_, code, err = run("--format=zip")
+	require.ErrorContains(t, err, "invalid --format")
+	require.Equal(t, 2, code)
+}
@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/gittools"
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+)
+
+// Section keys of a PR file, as returned by prSectionKey.
+const (
+	prSectionUserSummary = "user summary"
+	prSectionPlan        = "plan"
+	prSectionReview      = "review"
+	prSectionSummary     = "summary"
+	prSectionState       = "state"
+)
+
+var (
+	prFileNameExpr    = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}_[0-9]+_(.+)\.md$`)
+	prDoneMarkerExpr  = regexp.MustCompile(`(?i)\s*\[done\]\s*`)
+	prHeadingExpr     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	prHeadingNoteExpr = regexp.MustCompile(`\s*\([^)]*\)\s*$`)
+)
+
+// prFile is a parsed PR orchestrator file (see internal/agentbuilder/data/pr-orchestrator.prompt.md for its sections).
+type prFile struct {
+	RelPath     string            // RelPath is the repo-relative, slash-separated path of the file (ex: ".prs/2026-05-19_1779211919_cas-prune.md").
+	AbsPath     string            // AbsPath is the absolute path of the file.
+	FeatureName string            // FeatureName is the feature name from the file name, or the file name without extension if it isn't a PR file name.
+	Content     string            // Content is the raw file content.
+	Sections    map[string]string // Sections maps "##" section keys (see prSectionKey) to their trimmed bodies.
+	PlanDone    bool              // PlanDone reports whether the "## Plan" heading is marked [DONE].
+	PlanItems   []prPlanItem      // PlanItems are the "###" subheadings of "## Plan", in order.
+}
+
+// prPlanItem is one "###" subheading of a PR file's "## Plan" section.
+type prPlanItem struct {
+	Title string // Title is the heading text without the [DONE] marker.
+	Done  bool   // Done reports whether the heading is marked [DONE], or the whole plan is.
+}
+
+// PlanProgress returns how many plan items are done, out of how many.
+func (f prFile) PlanProgress() (done int, total int) {
+	for _, item := range f.PlanItems {
+		if item.Done {
+			done++
+		}
+	}
+	return done, len(f.PlanItems)
+}
+
+// prSectionKey normalizes a "##" heading title to a section key: lowercase, without a [DONE] marker or a trailing parenthetical (ex: "User Summary (do not
+// modify)" is "user summary").
+func prSectionKey(title string) string {
+	title = prDoneMarkerExpr.ReplaceAllString(title, " ")
+	title = prHeadingNoteExpr.ReplaceAllString(title, "")
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
+// parsePRFile parses content as a PR file. Headings inside fenced code blocks are ignored.
+func parsePRFile(content string) prFile {
+	f := prFile{Content: content, Sections: map[string]string{}}
+	var current string
+	var body []string
+	flush := func() {
+		if current != "" {
+			f.Sections[current] = strings.TrimSpace(strings.Join(body, "\n"))
+		}
+		body = nil
+	}
+
+	inFence := false
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		m := prHeadingExpr.FindStringSubmatch(line)
+		if inFence || m == nil {
+			body = append(body, line)
+			continue
+		}
+		level, title := len(m[1]), m[2]
+		switch {
+		case level == 2:
+			flush()
+			current = prSectionKey(title)
+			if current == prSectionPlan {
+				f.PlanDone = prDoneMarkerExpr.MatchString(title)
+			}
+		case level == 3 && current == prSectionPlan:
+			f.PlanItems = append(f.PlanItems, prPlanItem{
+				Title: strings.TrimSpace(prDoneMarkerExpr.ReplaceAllString(title, " ")),
+				Done:  f.PlanDone || prDoneMarkerExpr.MatchString(title),
+			})
+			body = append(body, line)
+		default:
+			body = append(body, line)
+		}
+	}
+	flush()
+	return f
+}
+
+// readPRFile reads and parses the PR file at absPath, within the repo at repoRoot.
+func readPRFile(repoRoot string, absPath string) (prFile, error) {
+	b, err := os.ReadFile(absPath)
+	if err != nil {
+		return prFile{}, err
+	}
+	f := parsePRFile(string(b))
+	f.AbsPath = absPath
+	f.RelPath = filepath.ToSlash(absPath)
+	if rel, err := filepath.Rel(repoRoot, absPath); err == nil {
+		f.RelPath = filepath.ToSlash(rel)
+	}
+	base := filepath.Base(absPath)
+	if m := prFileNameExpr.FindStringSubmatch(base); m != nil {
+		f.FeatureName = m[1]
+	} else {
+		f.FeatureName = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	return f, nil
+}
+
+// withPRSummary returns content with the body of its "## Summary" section replaced by summary, appending the section if it's missing.
+func withPRSummary(content string, summary string) string {
+	summary = strings.TrimSpace(summary)
+	lines := strings.Split(content, "\n")
+	start, end := -1, len(lines)
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		m := prHeadingExpr.FindStringSubmatch(line)
+		if inFence || m == nil || len(m[1]) > 2 {
+			continue
+		}
+		if start >= 0 {
+			end = i
+			break
+		}
+		if len(m[1]) == 2 && prSectionKey(m[2]) == prSectionSummary {
+			start = i
+		}
+	}
+	if start < 0 {
+		return strings.TrimRight(content, "\n") + "\n\n## Summary\n\n" + summary + "\n"
+	}
+	out := append([]string{}, lines[:start+1]...)
+	out = append(out, "", summary, "")
+	out = append(out, lines[end:]...)
+	return strings.Join(out, "\n")
+}
+
+// prBranch is the git state of the current line of work of a PR.
+type prBranch struct {
+	RepoRoot   string     // RepoRoot is the absolute git repo root.
+	Branch     string     // Branch is the current branch, or "" if HEAD is detached.
+	BaseCommit string     // BaseCommit is the heuristic merge base of the branch (see gittools.HeuristicMergeBase).
+	BaseRef    string     // BaseRef is the ref BaseCommit was found from, or "" if it's ambiguous or HEAD is on a primary branch.
+	Commits    []prCommit // Commits are the commits from BaseCommit to HEAD, oldest first.
+	Dirty      []string   // Dirty are the lines of git status --porcelain for uncommitted changes.
+}
+
+// prCommit is a commit on a PR branch.
+type prCommit struct {
+	Hash    string // Hash is the abbreviated commit hash.
+	Subject string // Subject is the commit subject line.
+}
+
+// loadPRBranch returns the git state of the repo containing cwd.
+func loadPRBranch(ctx context.Context, cwd string) (prBranch, error) {
+	root, err := gitOutput(ctx, cwd, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return prBranch{}, err
+	}
+	b := prBranch{RepoRoot: strings.TrimSpace(root)}
+	branch, err := gitOutput(ctx, b.RepoRoot, "branch", "--show-current")
+	if err != nil {
+		return prBranch{}, err
+	}
+	b.Branch = strings.TrimSpace(branch)
+
+	b.BaseCommit, b.BaseRef, err = gittools.HeuristicMergeBase(b.RepoRoot)
+	if err != nil {
+		return prBranch{}, fmt.Errorf("could not determine the branch's base commit: %w", err)
+	}
+
+	log, err := gitOutput(ctx, b.RepoRoot, "log", "--reverse", "--format=%h %s", b.BaseCommit+"..HEAD")
+	if err != nil {
+		return prBranch{}, err
+	}
+	for _, line := range parseNonEmptyLines([]byte(log)) {
+		hash, subject, _ := strings.Cut(line, " ")
+		b.Commits = append(b.Commits, prCommit{Hash: hash, Subject: subject})
+	}
+
+	status, err := gitOutput(ctx, b.RepoRoot, "status", "--porcelain")
+	if err != nil {
+		return prBranch{}, err
+	}
+	b.Dirty = parseNonEmptyLines([]byte(status))
+	return b, nil
+}
+
+// findPRFile resolves the PR file of branch. If arg is non-empty, it's the path to the PR file. Otherwise the PR file is inferred: the newest .prs/*.md file changed
+// on the branch (committed or not), or else the newest .prs/*.md file whose feature name is the last component of the branch name.
+func findPRFile(branch prBranch, arg string) (prFile, error) {
+	if arg != "" {
+		absPath, err := filepath.Abs(arg)
+		if err != nil {
+			return prFile{}, err
+		}
+		if _, err := os.Stat(absPath); err != nil {
+			return prFile{}, qcli.UsageError{Message: fmt.Sprintf("PR file %s: %v", arg, err)}
+		}
+		return readPRFile(branch.RepoRoot, absPath)
+	}
+
+	var candidates []string
+	if branch.BaseCommit != "" {
+		changed, err := gittools.ChangedPathsSince(branch.RepoRoot, branch.BaseCommit, true)
+		if err != nil {
+			return prFile{}, err
+		}
+		for _, p := range changed {
+			if path.Dir(p) == ".prs" && strings.HasSuffix(p, ".md") {
+				if _, err := os.Stat(filepath.Join(branch.RepoRoot, filepath.FromSlash(p))); err == nil {
+					candidates = append(candidates, p)
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 && branch.Branch != "" {
+		feature := path.Base(branch.Branch)
+		matches, err := filepath.Glob(filepath.Join(branch.RepoRoot, ".prs", "*_"+feature+".md"))
+		if err != nil {
+			return prFile{}, err
+		}
+		for _, m := range matches {
+			if sub := prFileNameExpr.FindStringSubmatch(filepath.Base(m)); sub != nil && sub[1] == feature {
+				candidates = append(candidates, ".prs/"+filepath.Base(m))
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return prFile{}, qcli.UsageError{Message: "could not infer the PR file for this branch; pass <path/to/pr-file>"}
+	}
+	// PR file names start with their creation date and time, so the lexically last one is the newest.
+	sort.Strings(candidates)
+	return readPRFile(branch.RepoRoot, filepath.Join(branch.RepoRoot, filepath.FromSlash(candidates[len(candidates)-1])))
+}
+
+// prDefaultTitle returns a PR title derived from featureName (ex: "cas-prune" is "Cas prune").
+func prDefaultTitle(featureName string) string {
+	title := strings.TrimSpace(strings.NewReplacer("-", " ", "_", " ").Replace(featureName))
+	if title == "" {
+		return "PR"
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPRFileContent = `# PR
+
+## User Summary (do not modify)
+
+Add CAS pruning.
+
+## Plan
+
+### Add prune command [DONE]
+
+Details.
+
+### Document it
+
+` + "```md\n## Summary\n```" + `
+
+## Review
+
+## State
+
+Working on docs.
+`
+
+func TestParsePRFile(t *testing.T) {
+	f := parsePRFile(testPRFileContent)
+
+	assert.Equal(t, "Add CAS pruning.", f.Sections[prSectionUserSummary])
+	assert.Equal(t, "", f.Sections[prSectionReview])
+	assert.Equal(t, "Working on docs.", f.Sections[prSectionState])
+	_, hasSummary := f.Sections[prSectionSummary]
+	assert.False(t, hasSummary, "headings in code fences are not sections")
+	assert.False(t, f.PlanDone)
+	assert.Equal(t, []prPlanItem{{Title: "Add prune command", Done: true}, {Title: "Document it"}}, f.PlanItems)
+
+	done, total := f.PlanProgress()
+	assert.Equal(t, 1, done)
+	assert.Equal(t, 2, total)
+
+	f = parsePRFile("## Plan [DONE]\n\n### A\n\n### B\n")
+	assert.True(t, f.PlanDone)
+	done, total = f.PlanProgress()
+	assert.Equal(t, 2, done)
+	assert.Equal(t, 2, total)
+}
+
+func TestWithPRSummary(t *testing.T) {
+	got := withPRSummary(testPRFileContent, "Adds `cas prune`.\n")
+	f := parsePRFile(got)
+	assert.Equal(t, "Adds `cas prune`.", f.Sections[prSectionSummary])
+	assert.True(t, strings.HasSuffix(got, "## Summary\n\nAdds `cas prune`.\n"))
+
+	replaced := withPRSummary("# PR\n\n## Summary\n\nOld.\n\n## State\n\nDone.\n", "New.")
+	assert.Equal(t, "# PR\n\n## Summary\n\nNew.\n\n## State\n\nDone.\n", replaced)
+}
+
+func TestPRDefaultTitle(t *testing.T) {
+	assert.Equal(t, "Cas prune", prDefaultTitle("cas-prune"))
+	assert.Equal(t, "Refactor p", prDefaultTitle("refactor_p"))
+	assert.Equal(t, "PR", prDefaultTitle(""))
+}
+
+func TestFindPRFile(t *testing.T) {
+	repo := newPRTestRepo(t)
+	writePRTestFile(t, repo, ".prs/2026-05-01_1777593600_other.md", "# PR\n")
+	writePRTestFile(t, repo, ".prs/2026-05-02_1777680000_cas-prune.md", testPRFileContent)
+	prTestGit(t, repo, "add", ".")
+	prTestGit(t, repo, "commit", "-m", "PR files")
+	prTestGit(t, repo, "checkout", "-b", "jn/cas-prune")
+	chdirForTest(t, repo)
+
+	// No PR file changed on the branch, so it's inferred from the branch name.
+	branch, err := loadPRBranch(context.Background(), repo)
+	require.NoError(t, err)
+	f, err := findPRFile(branch, "")
+	require.NoError(t, err)
+	assert.Equal(t, ".prs/2026-05-02_1777680000_cas-prune.md", f.RelPath)
+	assert.Equal(t, "cas-prune", f.FeatureName)
+
+	// A PR file changed on the branch wins over the branch name.
+	writePRTestFile(t, repo, ".prs/2026-05-01_1777593600_other.md", "# PR\n\n## State\n")
+	f, err = findPRFile(branch, "")
+	require.NoError(t, err)
+	assert.Equal(t, "other", f.FeatureName)
+
+	// An explicit path wins over both.
+	f, err = findPRFile(branch, ".prs/2026-05-02_1777680000_cas-prune.md")
+	require.NoError(t, err)
+	assert.Equal(t, "cas-prune", f.FeatureName)
+
+	_, err = findPRFile(branch, ".prs/missing.md")
+	require.Error(t, err)
+
+	prTestGit(t, repo, "checkout", "-b", "unrelated")
+	prTestGit(t, repo, "checkout", "--", ".prs")
+	branch, err = loadPRBranch(context.Background(), repo)
+	require.NoError(t, err)
+	_, err = findPRFile(branch, "")
+	require.ErrorContains(t, err, "could not infer the PR file")
+}
+
+// isolateUserConfigWithRealGit is isolateUserConfig, but keeps the real git ahead of the stubbed one on PATH for tests that drive actual repos.
+func isolateUserConfigWithRealGit(t *testing.T) {
+	t.Helper()
+
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git not available")
+	}
+	isolateUserConfig(t)
+	t.Setenv("PATH", filepath.Dir(gitPath)+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// newPRTestRepo creates a git repo on main with one commit and returns its (symlink-resolved) path.
+func newPRTestRepo(t *testing.T) string {
+	t.Helper()
+
+	repo, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	prTestGit(t, repo, "init", "--initial-branch=main")
+	prTestGit(t, repo, "config", "user.name", "Test User")
+	prTestGit(t, repo, "config", "user.email", "test@example.com")
+	writePRTestFile(t, repo, "README.md", "readme\n")
+	prTestGit(t, repo, "add", ".")
+	prTestGit(t, repo, "commit", "-m", "initial")
+	return repo
+}
+
+// writePRTestFile writes content to the slash-separated path rel under repo.
+func writePRTestFile(t *testing.T, repo string, rel string, content string) {
+	t.Helper()
+
+	p := filepath.Join(repo, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+}
+
+// prTestGit runs git in repo and returns its trimmed output.
+func prTestGit(t *testing.T, repo string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repo
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
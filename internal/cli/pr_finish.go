@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	"github.com/codalotl/codalotl/internal/llmstream"
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+	"github.com/codalotl/codalotl/internal/q/remotemonitor"
+)
+
+// maxPRFinishDiff caps how much of the branch diff is sent to the LLM when generating a PR summary.
+const maxPRFinishDiff = 40_000
+
+var newPRFinishCompleter = llmstream.NewCompleter
+
+// prFinishOptions configures runPRFinish.
+type prFinishOptions struct {
+	PRFileArg  string           // PRFileArg is the PR file path, or "" to infer it.
+	Squash     bool             // Squash squashes the branch's commits into one.
+	Title      string           // Title is the squashed commit's subject; "" derives it from the feature name.
+	Regenerate bool             // Regenerate regenerates the summary even if the PR file has one.
+	Model      llmmodel.ModelID // Model generates the summary; "" means the default model.
+}
+
+// newPRFinishCommand builds the pr finish command.
+func newPRFinishCommand(runWithConfig runWithConfigFunc) *qcli.Command {
+	cmd := &qcli.Command{
+		Name:  "finish",
+		Short: "Write the PR summary and tidy the branch's commits.",
+		Long: "Finishes the PR on the current branch. If the PR file's ## Summary section is empty (or with --regenerate), an LLM writes a PR description from the PR file, " +
+			"the branch's commits, and its diff, and the PR file is committed. Then fixup!/squash! commits are folded into their targets; with --squash, all of the branch's commits " +
+			"are squashed into one whose message is the title and summary. Requires a clean working tree. Nothing is pushed.",
+		Usage: "[<path/to/pr-file>]",
+		ArgHelp: []qcli.ArgHelp{
+			{
+				Display:     "<path/to/pr-file>",
+				Description: "PR file to finish. Defaults to the .prs file changed on the current branch, or the one named after the branch.",
+			},
+		},
+		Complete: completeArgs(completeMarkdownFiles),
+		Example: strings.TrimSpace(`
+codalotl pr finish
+codalotl pr finish --squash --title "Add CAS pruning"
+codalotl pr finish --regenerate --model gpt-5.5-high
+`),
+		Args: qcli.RangeArgs(0, 1),
+	}
+	flags := cmd.Flags()
+	squash := flags.Bool("squash", 0, false, "Squash all of the branch's commits into one.")
+	title := flags.String("title", 0, "", "Subject of the squashed commit (default: derived from the feature name).")
+	regenerate := flags.Bool("regenerate", 0, false, "Regenerate the summary even if the PR file already has one.")
+	model := flags.String("model", 0, "", "LLM model ID to use for the summary (overrides config preferredmodel; empty = default).")
+	flags.SetComplete("model", completeModelIDs)
+	cmd.Run = runWithConfig("pr_finish", func(c *qcli.Context, cfg Config, _ *remotemonitor.Monitor) error {
+		modelID := llmmodel.ModelID(strings.TrimSpace(*model))
+		if modelID == "" {
+			modelID = llmmodel.ModelID(strings.TrimSpace(cfg.PreferredModel))
+		}
+		opts := prFinishOptions{Squash: *squash, Title: *title, Regenerate: *regenerate, Model: modelID}
+		if len(c.Args) == 1 {
+			opts.PRFileArg = c.Args[0]
+		}
+		return runPRFinish(c.Context, c.Out, opts)
+	})
+	return cmd
+}
+
+// runPRFinish finishes the PR on the current branch (see newPRFinishCommand) and writes what it did to out.
+func runPRFinish(ctx context.Context, out io.Writer, opts prFinishOptions) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	branch, err := loadPRBranch(ctx, cwd)
+	if err != nil {
+		return qcli.ExitError{Code: 1, Err: err}
+	}
+	if len(branch.Dirty) > 0 {
+		return qcli.ExitError{Code: 1, Err: errors.New("working tree is not clean")}
+	}
+	if len(branch.Commits) == 0 {
+		return qcli.ExitError{Code: 1, Err: errors.New("no commits on the current branch since its base")}
+	}
+	pr, err := findPRFile(branch, opts.PRFileArg)
+	if err != nil {
+		return err
+	}
+
+	summary := pr.Sections[prSectionSummary]
+	if summary == "" || opts.Regenerate {
+		summary, err = generatePRSummary(ctx, branch, pr, opts.Model)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(pr.AbsPath, []byte(withPRSummary(pr.Content, summary)), 0644); err != nil {
+			return err
+		}
+		if _, err := gitOutput(ctx, branch.RepoRoot, "add", pr.RelPath); err != nil {
+			return err
+		}
+		if _, err := gitOutput(ctx, branch.RepoRoot, "commit", "-m", "Add PR summary for "+pr.FeatureName); err != nil {
+			return err
+		}
+		if err := writeStringln(out, "Wrote summary to "+pr.RelPath); err != nil {
+			return err
+		}
+		if branch, err = loadPRBranch(ctx, cwd); err != nil {
+			return err
+		}
+	}
+
+	if opts.Squash {
+		title := strings.TrimSpace(opts.Title)
+		if title == "" {
+			title = prDefaultTitle(pr.FeatureName)
+		}
+		msgFile, err := os.CreateTemp("", "codalotl-pr-finish-*.txt")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(msgFile.Name())
+		if _, err := io.WriteString(msgFile, title+"\n\n"+summary+"\n"); err != nil {
+			_ = msgFile.Close()
+			return err
+		}
+		if err := msgFile.Close(); err != nil {
+			return err
+		}
+		head, err := gitOutput(ctx, branch.RepoRoot, "rev-parse", "HEAD")
+		if err != nil {
+			return err
+		}
+		if _, err := gitOutput(ctx, branch.RepoRoot, "reset", "--soft", branch.BaseCommit); err != nil {
+			return err
+		}
+		if _, err := gitOutput(ctx, branch.RepoRoot, "commit", "-F", msgFile.Name()); err != nil {
+			// Put the branch back, so a failed commit (ex: a rejecting hook) doesn't leave its commits staged on top of the base.
+			if _, resetErr := gitOutput(ctx, branch.RepoRoot, "reset", "--soft", strings.TrimSpace(head)); resetErr != nil {
+				return errors.Join(err, resetErr)
+			}
+			return err
+		}
+		return writeStringln(out, fmt.Sprintf("Squashed %d commit(s) into: %s", len(branch.Commits), title))
+	}
+
+	fixups := 0
+	for _, c := range branch.Commits {
+		if strings.HasPrefix(c.Subject, "fixup! ") || strings.HasPrefix(c.Subject, "squash! ") || strings.HasPrefix(c.Subject, "amend! ") {
+			fixups++
+		}
+	}
+	if fixups == 0 {
+		return writeStringln(out, fmt.Sprintf("%d commit(s); no fixup commits to fold.", len(branch.Commits)))
+	}
+	// sequence.editor=: accepts the autosquash todo list as-is, and core.editor=true accepts the combined message of squash! commits, so the "interactive" rebase
+	// runs unattended.
+	if _, err := gitOutput(ctx, branch.RepoRoot, "-c", "sequence.editor=:", "-c", "core.editor=true", "rebase", "--interactive", "--autosquash", branch.BaseCommit); err != nil {
+		_, _ = gitOutput(ctx, branch.RepoRoot, "rebase", "--abort")
+		return qcli.ExitError{Code: 1, Err: fmt.Errorf("could not fold fixup commits: %w", err)}
+	}
+	after, err := loadPRBranch(ctx, cwd)
+	if err != nil {
+		return err
+	}
+	return writeStringln(out, fmt.Sprintf("Folded %d fixup commit(s): %d commit(s) remain.", fixups, len(after.Commits)))
+}
+
+const prSummarySystemPrompt = `You write pull request descriptions.
+
+You are given a PR file (the plan and notes used while building the PR), the PR's commits, and its diff. Write the PR description: what the change does and why, in a short opening paragraph, then the notable changes as a concise bulleted list, then how it was verified if the PR file says so. Describe the final state of the code, not the process of building it. Use GitHub-flavored markdown without a title or top-level heading.
+
+Respond with only the description.`
+
+// generatePRSummary asks an LLM for a PR description of the branch, from its PR file, commits, and diff.
+func generatePRSummary(ctx context.Context, branch prBranch, pr prFile, model llmmodel.ModelID) (string, error) {
+	diff, err := gitOutput(ctx, branch.RepoRoot, "diff", branch.BaseCommit+"..HEAD", "--", ".", ":(exclude).prs", ":(exclude).codalotl")
+	if err != nil {
+		return "", err
+	}
+	if len(diff) > maxPRFinishDiff {
+		diff = diff[:maxPRFinishDiff] + "\n... (diff truncated)\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "PR file (%s):\n\n%s\n\nCommits:\n", filepath.Base(pr.RelPath), strings.TrimSpace(pr.Content))
+	for _, c := range branch.Commits {
+		fmt.Fprintf(&b, "- %s\n", c.Subject)
+	}
+	fmt.Fprintf(&b, "\nDiff:\n\n%s", diff)
+
+	turn, err := newPRFinishCompleter().Complete(ctx, llmmodel.ModelIDOrFallback(model), prSummarySystemPrompt, b.String())
+	if err != nil {
+		return "", fmt.Errorf("generate PR summary: %w", err)
+	}
+	summary := strings.TrimSpace(turn.TextContent())
+	if summary == "" {
+		return "", errors.New("generate PR summary: LLM returned an empty summary")
+	}
+	return summary, nil
+}
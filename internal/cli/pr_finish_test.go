@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	"github.com/codalotl/codalotl/internal/llmstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prFinishFakeCompleter returns a canned PR summary and records the user message it was sent.
+type prFinishFakeCompleter struct {
+	response    string
+	calls       int
+	userMessage string
+}
+
+// Complete implements llmstream.Completer.
+func (f *prFinishFakeCompleter) Complete(_ context.Context, _ llmmodel.ModelID, _, userMessage string, _ ...llmstream.SendOptions) (llmstream.Turn, error) {
+	f.calls++
+	f.userMessage = userMessage
+	return llmstream.Turn{
+		Role:         llmstream.RoleAssistant,
+		Parts:        []llmstream.ContentPart{llmstream.TextContent{Content: f.response}},
+		FinishReason: llmstream.FinishReasonEndTurn,
+	}, nil
+}
+
+func stubPRFinishCompleter(t *testing.T, c llmstream.Completer) {
+	t.Helper()
+
+	orig := newPRFinishCompleter
+	newPRFinishCompleter = func() llmstream.Completer { return c }
+	t.Cleanup(func() { newPRFinishCompleter = orig })
+}
+
+// setupPRFinishRepo creates a repo with a cas-prune branch holding a PR file commit, a feature commit, and a fixup of it.
+func setupPRFinishRepo(t *testing.T) string {
+	t.Helper()
+
+	isolateUserConfigWithRealGit(t)
+	repo := newPRTestRepo(t)
+	prTestGit(t, repo, "checkout", "-b", "cas-prune")
+	writePRTestFile(t, repo, ".prs/2026-05-02_1777680000_cas-prune.md", testPRFileContent)
+	prTestGit(t, repo, "add", ".")
+	prTestGit(t, repo, "commit", "-m", "Add PR file")
+	writePRTestFile(t, repo, "prune.txt", "prune\n")
+	prTestGit(t, repo, "add", ".")
+	prTestGit(t, repo, "commit", "-m", "Add prune")
+	writePRTestFile(t, repo, "prune.txt", "prune v2\n")
+	prTestGit(t, repo, "commit", "-am", "fixup! Add prune")
+	chdirForTest(t, repo)
+	return repo
+}
+
+func TestRun_PRFinish_WritesSummaryAndFoldsFixups(t *testing.T) {
+	repo := setupPRFinishRepo(t)
+	completer := &prFinishFakeCompleter{response: "Adds CAS pruning.\n"}
+	stubPRFinishCompleter(t, completer)
+
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "pr", "finish"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err, errOut.String())
+	require.Equal(t, 0, code)
+	assert.Equal(t, "Wrote summary to .prs/2026-05-02_1777680000_cas-prune.md\nFolded 1 fixup commit(s): 3 commit(s) remain.\n", out.String())
+
+	assert.Equal(t, 1, completer.calls)
+	assert.Contains(t, completer.userMessage, "## User Summary (do not modify)")
+	assert.Contains(t, completer.userMessage, "- fixup! Add prune\n")
+	assert.Contains(t, completer.userMessage, "+prune v2")
+	assert.NotContains(t, completer.userMessage, "+++ b/.prs/")
+
+	content, err := os.ReadFile(filepath.Join(repo, ".prs", "2026-05-02_1777680000_cas-prune.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "Adds CAS pruning.", parsePRFile(string(content)).Sections[prSectionSummary])
+	assert.Equal(t, "Add PR summary for cas-prune\nAdd prune\nAdd PR file", prTestGit(t, repo, "log", "--format=%s", "main..HEAD"))
+	assert.Empty(t, prTestGit(t, repo, "status", "--porcelain"))
+
+	// With a summary in place, nothing is regenerated or folded.
+	out.Reset()
+	code, err = Run([]string{"codalotl", "pr", "finish"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Equal(t, 1, completer.calls)
+	assert.Equal(t, "3 commit(s); no fixup commits to fold.\n", out.String())
+}
+
+func TestRun_PRFinish_Squash(t *testing.T) {
+	repo := setupPRFinishRepo(t)
+	completer := &prFinishFakeCompleter{response: "Adds CAS pruning."}
+	stubPRFinishCompleter(t, completer)
+
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "pr", "finish", "--squash"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err, errOut.String())
+	require.Equal(t, 0, code)
+	assert.Contains(t, out.String(), "Squashed 4 commit(s) into: Cas prune\n")
+	assert.Equal(t, "Cas prune\n\nAdds CAS pruning.", prTestGit(t, repo, "log", "--format=%B", "main..HEAD"))
+	assert.Equal(t, "prune v2", prTestGit(t, repo, "show", "HEAD:prune.txt"))
+}
+
+func TestRun_PRFinish_FoldsSquashCommits(t *testing.T) {
+	repo := setupPRFinishRepo(t)
+	writePRTestFile(t, repo, "prune.txt", "prune v3\n")
+	prTestGit(t, repo, "commit", "-am", "squash! Add prune", "-m", "Handle v3.")
+	// An editor that fails would abort the rebase if it were opened for the squashed message.
+	t.Setenv("EDITOR", "false")
+	for _, name := range []string{"VISUAL", "GIT_EDITOR"} {
+		t.Setenv(name, "")
+		require.NoError(t, os.Unsetenv(name))
+	}
+	completer := &prFinishFakeCompleter{response: "Adds CAS pruning."}
+	stubPRFinishCompleter(t, completer)
+
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "pr", "finish"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err, errOut.String())
+	require.Equal(t, 0, code)
+	assert.Contains(t, out.String(), "Folded 2 fixup commit(s): 3 commit(s) remain.\n")
+	assert.Equal(t, "Add PR summary for cas-prune\nAdd prune\nAdd PR file", prTestGit(t, repo, "log", "--format=%s", "main..HEAD"))
+	assert.Contains(t, prTestGit(t, repo, "log", "--format=%B", "-1", "HEAD~1"), "Handle v3.")
+	assert.Equal(t, "prune v3", prTestGit(t, repo, "show", "HEAD~1:prune.txt"))
+	assert.Empty(t, prTestGit(t, repo, "status", "--porcelain"))
+}
+
+func TestRun_PRFinish_SquashRestoresBranchWhenCommitFails(t *testing.T) {
+	repo := setupPRFinishRepo(t)
+	completer := &prFinishFakeCompleter{response: "Adds CAS pruning."}
+	stubPRFinishCompleter(t, completer)
+
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	_, err := Run([]string{"codalotl", "pr", "finish"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err, errOut.String())
+	before := prTestGit(t, repo, "rev-parse", "HEAD")
+
+	// A rejecting hook makes the squash commit fail.
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".git", "hooks", "pre-commit"), []byte("#!/bin/sh\nexit 1\n"), 0755))
+	code, err := Run([]string{"codalotl", "pr", "finish", "--squash"}, &RunOptions{Out: &out, Err: &errOut})
+	require.Error(t, err)
+	require.Equal(t, 1, code)
+	assert.Equal(t, before, prTestGit(t, repo, "rev-parse", "HEAD"))
+	assert.Empty(t, prTestGit(t, repo, "status", "--porcelain"))
+}
+
+func TestRun_PRFinish_RequiresCleanTree(t *testing.T) {
+	repo := setupPRFinishRepo(t)
+	completer := &prFinishFakeCompleter{response: "Adds CAS pruning."}
+	stubPRFinishCompleter(t, completer)
+	writePRTestFile(t, repo, "prune.txt", "dirty\n")
+
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "pr", "finish"}, &RunOptions{Out: &out, Err: &errOut})
+	require.ErrorContains(t, err, "working tree is not clean")
+	require.Equal(t, 1, code)
+	assert.Zero(t, completer.calls)
+}
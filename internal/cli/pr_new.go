@@ -53,11 +53,11 @@ type prRefactorOptions struct {
 }
 
 // newPRCommand returns the command tree for PR orchestrator workflow tools.
-func newPRCommand() *qcli.Command {
+func newPRCommand(runWithConfig runWithConfigFunc) *qcli.Command {
 	prCmd := &qcli.Command{
 		Name:  "pr",
 		Short: "PR orchestrator workflow tools.",
-		Long:  "Commands for creating and managing local PR orchestrator workflow files.",
+		Long:  "Commands for creating and managing PR orchestrator workflow files, and for reporting on, finishing, and exporting the PRs they drive.",
 	}
 
 	newCmd := &qcli.Command{
@@ -119,6 +119,8 @@ codalotl pr refactor --all-packages --refactor=docs-fix
 	refactorPackage := refactorFlags.String("package", 'p', "", "Package to refactor (import path or dir; must resolve to a single Go package).")
 	refactorAllPackages := refactorFlags.Bool("all-packages", 0, false, "Target needed Go packages discovered for the selected refactor. Requires --refactor.")
 	refactorName := refactorFlags.String("refactor", 0, "", "Optional refactor flow: docs-add, docs-fix, dry, test-cleanup, or test-ensure-coverage.")
+	refactorFlags.SetComplete("package", completePackageDirs)
+	refactorFlags.SetComplete("refactor", completePRRefactors)
 	refactorCmd.Args = func(args []string) error {
 		if err := qcli.NoArgs(args); err != nil {
 			return err
@@ -143,7 +145,7 @@ codalotl pr refactor --all-packages --refactor=docs-fix
 		})
 	}
 
-	prCmd.AddCommand(newCmd, refactorCmd)
+	prCmd.AddCommand(newCmd, refactorCmd, newPRStatusCommand(), newPRFinishCommand(runWithConfig), newPRExportCommand())
 	return prCmd
 }
 
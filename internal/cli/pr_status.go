@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/gittools"
+	"github.com/codalotl/codalotl/internal/gocas"
+	"github.com/codalotl/codalotl/internal/gocas/casconformance"
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+)
+
+// newPRStatusCommand builds the pr status command.
+func newPRStatusCommand() *qcli.Command {
+	cmd := &qcli.Command{
+		Name:  "status",
+		Short: "Print the progress of the current PR.",
+		Long: "Prints the state of the PR on the current branch: PR file plan progress and sections, commits since the branch's base, uncommitted changes, " +
+			"and the SPEC.md, CAS conformance, and test status of Go packages touched by the branch. The PR file is inferred from the branch unless given.",
+		Usage: "[<path/to/pr-file>]",
+		ArgHelp: []qcli.ArgHelp{
+			{
+				Display:     "<path/to/pr-file>",
+				Description: "PR file to report on. Defaults to the .prs file changed on the current branch, or the one named after the branch.",
+			},
+		},
+		Complete: completeArgs(completeMarkdownFiles),
+		Example: strings.TrimSpace(`
+codalotl pr status
+codalotl pr status --no-tests
+codalotl pr status .prs/2026-05-19_1779211919_cas-prune.md
+`),
+		Args: qcli.RangeArgs(0, 1),
+	}
+	noTests := cmd.Flags().Bool("no-tests", 0, false, "Don't run the tests of touched packages.")
+	cmd.Run = func(c *qcli.Context) error {
+		arg := ""
+		if len(c.Args) == 1 {
+			arg = c.Args[0]
+		}
+		return runPRStatus(c.Context, c.Out, arg, !*noTests)
+	}
+	return cmd
+}
+
+// prPackageStatusRow is the status of one Go package touched by a PR branch.
+type prPackageStatusRow struct {
+	Package  string // Display package path.
+	HasSpec  string // Whether the package has a SPEC.md file.
+	APIMatch string // Whether the SPEC.md public API matches the implementation, or "-" without a SPEC.md.
+	Conforms string // Stored CAS conformance status for the current package contents.
+	Tests    string // "pass", "fail", "error", or "-" if tests weren't run.
+}
+
+// runPRStatus writes the status of the PR on the current branch to out. prFileArg is the PR file path, or "" to infer it; runTests runs the tests of touched packages.
+func runPRStatus(ctx context.Context, out io.Writer, prFileArg string, runTests bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	branch, err := loadPRBranch(ctx, cwd)
+	if err != nil {
+		return qcli.ExitError{Code: 1, Err: err}
+	}
+	pr, err := findPRFile(branch, prFileArg)
+	if err != nil {
+		return err
+	}
+	rows, err := prTouchedPackageStatus(ctx, branch, runTests)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "PR file: %s\n", pr.RelPath)
+	fmt.Fprintf(&b, "Branch: %s\n", prBranchDescription(branch))
+
+	done, total := pr.PlanProgress()
+	switch {
+	case total > 0:
+		fmt.Fprintf(&b, "Plan: %d/%d done\n", done, total)
+		for _, item := range pr.PlanItems {
+			mark := " "
+			if item.Done {
+				mark = "x"
+			}
+			fmt.Fprintf(&b, "  - [%s] %s\n", mark, item.Title)
+		}
+	case pr.PlanDone:
+		b.WriteString("Plan: done\n")
+	case pr.Sections[prSectionPlan] != "":
+		b.WriteString("Plan: written\n")
+	default:
+		b.WriteString("Plan: missing\n")
+	}
+	fmt.Fprintf(&b, "Review: %s\n", prSectionStatus(pr, prSectionReview))
+	fmt.Fprintf(&b, "Summary: %s\n", prSectionStatus(pr, prSectionSummary))
+
+	fmt.Fprintf(&b, "Commits: %d\n", len(branch.Commits))
+	for _, c := range branch.Commits {
+		fmt.Fprintf(&b, "  %s %s\n", c.Hash, c.Subject)
+	}
+	if len(branch.Dirty) == 0 {
+		b.WriteString("Uncommitted changes: none\n")
+	} else {
+		fmt.Fprintf(&b, "Uncommitted changes: %d file(s)\n", len(branch.Dirty))
+	}
+	if _, err := io.WriteString(out, b.String()); err != nil {
+		return err
+	}
+
+	if len(rows) == 0 {
+		return writeStringln(out, "Touched packages: none")
+	}
+	if err := writeStringln(out, ""); err != nil {
+		return err
+	}
+	table := make([][]string, 0, len(rows))
+	for _, r := range rows {
+		table = append(table, []string{r.Package, r.HasSpec, r.APIMatch, r.Conforms, r.Tests})
+	}
+	return writeAlignedTable(out, []string{"Package", "has_spec", "api_match", "conforms", "tests"}, table)
+}
+
+// prBranchDescription describes the branch and its base (ex: "jn/cas-prune (base: main @ 1a2b3c4)").
+func prBranchDescription(branch prBranch) string {
+	name := branch.Branch
+	if name == "" {
+		name = "(detached HEAD)"
+	}
+	base := branch.BaseCommit
+	if len(base) > 7 {
+		base = base[:7]
+	}
+	if branch.BaseRef != "" {
+		return fmt.Sprintf("%s (base: %s @ %s)", name, branch.BaseRef, base)
+	}
+	return fmt.Sprintf("%s (base: %s)", name, base)
+}
+
+// prSectionStatus returns "written" if pr has a non-empty section key, and "missing" otherwise.
+func prSectionStatus(pr prFile, key string) string {
+	if pr.Sections[key] != "" {
+		return "written"
+	}
+	return "missing"
+}
+
+// prTouchedPackageStatus returns the status of Go packages under the repo whose .go files or SPEC.md changed since the branch's base (committed or not), sorted
+// by package. If runTests is false, tests aren't run.
+func prTouchedPackageStatus(ctx context.Context, branch prBranch, runTests bool) ([]prPackageStatusRow, error) {
+	changed, err := gittools.ChangedPathsSince(branch.RepoRoot, branch.BaseCommit, true)
+	if err != nil {
+		return nil, err
+	}
+	touched := map[string]bool{}
+	for _, p := range changed {
+		if strings.HasSuffix(p, ".go") || path.Base(p) == "SPEC.md" {
+			touched[filepath.Join(branch.RepoRoot, filepath.FromSlash(path.Dir(p)))] = true
+		}
+	}
+	if len(touched) == 0 {
+		return nil, nil
+	}
+
+	repoRoot, pkgDirs, err := goListPackageDirsUnderNearestGitRepo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rows []prPackageStatusRow
+	dbs := map[string]*gocas.DB{}
+	for _, pkgDir := range pkgDirs {
+		if !touched[pkgDir.absDir] {
+			continue
+		}
+		display, ok := displayPackagePath(repoRoot, pkgDir.absDir)
+		if !ok {
+			continue
+		}
+		row := prPackageStatusRow{Package: display, HasSpec: "false", APIMatch: "-", Conforms: "unset", Tests: "-"}
+
+		specPath := filepath.Join(pkgDir.absDir, "SPEC.md")
+		if info, err := os.Stat(specPath); err == nil && !info.IsDir() {
+			row.HasSpec = "true"
+			match, err := specMatchesPublicAPI(specPath)
+			switch {
+			case err != nil:
+				row.APIMatch = "error"
+			case match:
+				row.APIMatch = "true"
+			default:
+				row.APIMatch = "false"
+			}
+		}
+
+		pkg, err := loadPackageFromRepoDir(pkgDir)
+		if err != nil {
+			row.Conforms = "error"
+		} else if db, err := cachedCASReadDBForBaseDir(dbs, pkgDir.mod.AbsolutePath); err != nil {
+			row.Conforms = "error"
+		} else if found, conforms, err := casconformance.Retrieve(db, pkg); err != nil {
+			row.Conforms = "error"
+		} else if found {
+			row.Conforms = fmt.Sprint(conforms)
+		}
+
+		if runTests {
+			ok, _, err := runGoTests(ctx, pkgDir.mod.AbsolutePath, pkgDir.absDir, "")
+			switch {
+			case err != nil:
+				row.Tests = "error"
+			case ok:
+				row.Tests = "pass"
+			default:
+				row.Tests = "fail"
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Package < rows[j].Package })
+	return rows, nil
+}
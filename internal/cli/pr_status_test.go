@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/gocas"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_PRStatus(t *testing.T) {
+	isolateUserConfigWithRealGit(t)
+	repo := newPRTestRepo(t)
+	t.Setenv(gocas.EnvCASDB, filepath.Join(t.TempDir(), "casdb"))
+	writeTestGoPackage(t, repo, "p")
+	writeTestGoPackage(t, repo, "q")
+	prTestGit(t, repo, "add", ".")
+	prTestGit(t, repo, "commit", "-m", "add packages")
+
+	prTestGit(t, repo, "checkout", "-b", "cas-prune")
+	writePRTestFile(t, repo, ".prs/2026-05-02_1777680000_cas-prune.md", testPRFileContent)
+	prTestGit(t, repo, "add", ".")
+	prTestGit(t, repo, "commit", "-m", "Add PR file")
+	writePRTestFile(t, repo, "p/pkg.go", "package mypkg\n\nfunc Foo() {}\n\nfunc Bar() {}\n")
+	prTestGit(t, repo, "commit", "-am", "Add Bar")
+	writePRTestFile(t, repo, "p/SPEC.md", "# mypkg\n\n## Public API\n\n```go\nfunc Foo() int {\n\treturn 0\n}\n```\n")
+	chdirForTest(t, repo)
+
+	var testedDirs []string
+	stubRunGoTests(t, func(_ context.Context, _ string, pkgDir string, _ string) (bool, string, error) {
+		testedDirs = append(testedDirs, pkgDir)
+		return false, "FAIL", nil
+	})
+
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "pr", "status"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Equal(t, []string{filepath.Join(repo, "p")}, testedDirs)
+
+	got := out.String()
+	assert.Contains(t, got, "PR file: .prs/2026-05-02_1777680000_cas-prune.md\n")
+	assert.Contains(t, got, "Branch: cas-prune (base: main @ ")
+	assert.Contains(t, got, "Plan: 1/2 done\n  - [x] Add prune command\n  - [ ] Document it\n")
+	assert.Contains(t, got, "Review: missing\nSummary: missing\nCommits: 2\n")
+	assert.Contains(t, got, " Add PR file\n")
+	assert.Contains(t, got, " Add Bar\n")
+	assert.Contains(t, got, "Uncommitted changes: 1 file(s)\n")
+	assert.Regexp(t, `(?m)^Package\s+has_spec\s+api_match\s+conforms\s+tests\s*$`, got)
+	assert.Regexp(t, `(?m)^\./p\s+true\s+false\s+unset\s+fail\s*$`, got)
+	assert.NotContains(t, got, "./q")
+
+	out.Reset()
+	testedDirs = nil
+	code, err = Run([]string{"codalotl", "pr", "status", "--no-tests"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	assert.Empty(t, testedDirs)
+	assert.Regexp(t, `(?m)^\./p\s+true\s+false\s+unset\s+-\s*$`, out.String())
+}
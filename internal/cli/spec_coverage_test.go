@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_SpecCoverage_PrintsUncoveredAPIs(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+
+	pkgDir := filepath.Join(tmp, "p")
+	require.NoError(t, os.MkdirAll(pkgDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "p.go"), []byte("package p\n\nfunc Foo() {}\n\nfunc Bar() {}\n"), 0644))
+
+	specPath := filepath.Join(pkgDir, "SPEC.md")
+	require.NoError(t, os.WriteFile(specPath, []byte("# p\n\n## Public API\n\n```go\nfunc Foo() {\n}\n```\n"), 0644))
+
+	for _, arg := range []string{pkgDir, specPath} {
+		var out bytes.Buffer
+		var errOut bytes.Buffer
+		code, err := Run([]string{"codalotl", "spec", "coverage", arg}, &RunOptions{Out: &out, Err: &errOut})
+		require.NoError(t, err)
+		require.Equal(t, 0, code)
+		require.Empty(t, errOut.String())
+		require.Equal(t, "Bar (p.go:5)\n", out.String())
+	}
+}
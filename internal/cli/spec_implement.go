@@ -0,0 +1,381 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/agent"
+	"github.com/codalotl/codalotl/internal/gocas/casconformance"
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/lints"
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	"github.com/codalotl/codalotl/internal/noninteractive"
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+	"github.com/codalotl/codalotl/internal/q/remotemonitor"
+	"github.com/codalotl/codalotl/internal/specmd"
+)
+
+// maxSpecImplementTestOutput caps how much go test output is included in a follow-up prompt.
+const maxSpecImplementTestOutput = 10_000
+
+// newSpecImplementCommand builds the spec implement command.
+func newSpecImplementCommand(runWithConfig runWithConfigFunc) *qcli.Command {
+	cmd := &qcli.Command{
+		Name:  "implement",
+		Short: "Run a package-mode agent until the implementation conforms to SPEC.md.",
+		Long: "Runs a noninteractive package-mode agent seeded with the differences reported by codalotl spec diff. " +
+			"After each agent step, the SPEC.md Public API is compared again and the package's tests are run; the agent continues with whatever remains until both are clean, " +
+			"or until --max-steps or --max-cost is reached. A specconforms CAS record is stored for packages that end up conforming. " +
+			"With --all-mismatched, every package across discovered repo modules whose SPEC.md differs from its implementation is processed, in dependency order.",
+		Usage: "(<path/to/pkg> | --all-mismatched)",
+		ArgHelp: []qcli.ArgHelp{
+			{
+				Display:     "<path/to/pkg>",
+				Description: packagePathArgDescription,
+			},
+		},
+		Complete: completeArgs(completePackageDirs),
+		Example: strings.TrimSpace(`
+codalotl spec implement internal/mypkg
+codalotl spec implement --max-steps=3 --max-cost=2.50 --yes ./internal/mypkg
+codalotl spec implement --all-mismatched --yes
+`),
+		Args: qcli.RangeArgs(0, 1),
+	}
+	flags := cmd.Flags()
+	allMismatched := flags.Bool("all-mismatched", 0, false, "Process every package across discovered repo modules whose SPEC.md differs from its implementation.")
+	maxSteps := flags.Int("max-steps", 0, 5, "Stop a package after this many agent steps.")
+	maxCost := flags.String("max-cost", 0, "", "Stop a package once its estimated LLM cost reaches this many USD (empty or 0 = unlimited).")
+	yes := flags.Bool("yes", 'y', false, "Auto-approve any permission checks (noninteractive).")
+	noColor := flags.Bool("no-color", 0, false, "Disable ANSI colors and formatting.")
+	model := flags.String("model", 0, "", "LLM model ID to use (overrides config preferredmodel; empty = default).")
+	flags.SetComplete("model", completeModelIDs)
+
+	startupModel := func(Config) []llmmodel.ModelID {
+		modelID := llmmodel.ModelID(strings.TrimSpace(*model))
+		if modelID == "" {
+			return nil
+		}
+		return []llmmodel.ModelID{modelID}
+	}
+	cmd.Run = runWithConfig("spec_implement", func(c *qcli.Context, cfg Config, _ *remotemonitor.Monitor) error {
+		if *allMismatched == (len(c.Args) == 1) {
+			return qcli.UsageError{Message: "supply exactly one of <path/to/pkg> or --all-mismatched"}
+		}
+		if *maxSteps < 1 {
+			return qcli.UsageError{Message: fmt.Sprintf("invalid --max-steps: must be >= 1 (got %d)", *maxSteps)}
+		}
+		maxCostUSD, err := parseMaxCost(*maxCost)
+		if err != nil {
+			return err
+		}
+
+		modelID := llmmodel.ModelID(strings.TrimSpace(*model))
+		if modelID == "" {
+			modelID = llmmodel.ModelID(strings.TrimSpace(cfg.PreferredModel))
+		}
+		pricingModelID := modelID
+		if pricingModelID == "" {
+			pricingModelID = llmmodel.DefaultModel
+		}
+		steps, err := lints.ResolveSteps(&cfg.Lints, cfg.ReflowWidth)
+		if err != nil {
+			return qcli.ExitError{Code: 1, Err: fmt.Errorf("invalid configuration: lints: %w", err)}
+		}
+
+		run := specImplementRun{
+			sessionOpts: noninteractive.Options{
+				ModelID:      modelID,
+				LintSteps:    steps,
+				AutoYes:      cfg.AutoYes || *yes,
+				NoFormatting: *noColor,
+				Out:          c.Out,
+			},
+			modelInfo: llmmodel.GetModelInfo(pricingModelID),
+			maxSteps:  *maxSteps,
+			maxCost:   maxCostUSD,
+			out:       c.Out,
+		}
+
+		var conformed, total int
+		if *allMismatched {
+			conformed, total, err = run.allMismatched(c.Context)
+		} else {
+			var pkg *gocode.Package
+			var mod *gocode.Module
+			pkg, mod, err = loadPackageArg(c.Args[0])
+			if err != nil {
+				return err
+			}
+			total = 1
+			var ok bool
+			ok, err = run.pkg(c.Context, pkg, mod)
+			if ok {
+				conformed = 1
+			}
+		}
+		if err != nil {
+			if noninteractiveIsPrinted(err) {
+				return qcli.ExitError{Code: 1, Err: errors.New("")}
+			}
+			if errors.Is(err, context.Canceled) {
+				return qcli.ExitError{Code: 1, Err: errors.New("interrupted")}
+			}
+			return err
+		}
+		if *allMismatched {
+			if _, err := fmt.Fprintf(c.Out, "%d of %d package(s) now conform to SPEC.md.\n", conformed, total); err != nil {
+				return err
+			}
+		}
+		if conformed < total {
+			return qcli.ExitError{Code: 1, Err: errors.New("some packages don't conform to SPEC.md")}
+		}
+		return nil
+	}, startupModel)
+	return cmd
+}
+
+// specImplementRun holds the per-invocation state of a spec implement run.
+type specImplementRun struct {
+	sessionOpts noninteractive.Options // sessionOpts are the options for each package's agent session; CWD and PackagePath are set per package.
+	modelInfo   llmmodel.ModelInfo     // modelInfo prices session token usage for maxCost.
+	maxSteps    int                    // maxSteps bounds the agent steps per package.
+	maxCost     float64                // maxCost bounds the estimated USD cost per package; 0 means unlimited.
+	out         io.Writer              // out receives agent output and progress lines.
+}
+
+// allMismatched runs r against every package under the nearest git repo whose SPEC.md Public API differs from the implementation, dependencies first. Packages
+// that fail to load, or whose SPEC.md can't be compared, are skipped. It returns how many packages ended up conforming, out of how many were attempted.
+func (r specImplementRun) allMismatched(ctx context.Context) (conformed int, total int, err error) {
+	repoRoot, pkgDirs, err := goListPackageDirsUnderNearestGitRepo(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	var pkgs []repoPackage
+	for _, pkgDir := range pkgDirs {
+		display, ok := displayPackagePath(repoRoot, pkgDir.absDir)
+		if !ok || !hasSpecMDDir(pkgDir.absDir) {
+			continue
+		}
+		if diffs, err := specImplementationDiffs(pkgDir.absDir); err != nil || len(diffs) == 0 {
+			continue
+		}
+		pkg, err := loadPackageFromRepoDir(pkgDir)
+		if err != nil {
+			if _, err := fmt.Fprintf(r.out, "Skipping %s: %v\n", display, err); err != nil {
+				return 0, 0, err
+			}
+			continue
+		}
+		pkgs = append(pkgs, repoPackage{display: display, pkg: pkg, mod: pkgDir.mod})
+	}
+
+	for _, p := range sortPackagesByDependencies(pkgs) {
+		if _, err := fmt.Fprintf(r.out, "== %s\n", p.display); err != nil {
+			return conformed, total, err
+		}
+		total++
+		ok, err := r.pkg(ctx, p.pkg, p.mod)
+		if err != nil {
+			return conformed, total, fmt.Errorf("%s: %w", p.display, err)
+		}
+		if ok {
+			conformed++
+		}
+	}
+	return conformed, total, nil
+}
+
+// pkg runs a package-mode agent on pkg until its SPEC.md Public API matches the implementation and its tests pass, or a bound is reached. On success, it stores
+// a specconforms CAS record and returns true. A package that already conforms and passes its tests gets a record without an agent run. If the agent edits SPEC.md,
+// it is restored after the step, so conformance is only ever checked (and recorded) against the SPEC.md the run started with.
+func (r specImplementRun) pkg(ctx context.Context, pkg *gocode.Package, mod *gocode.Module) (bool, error) {
+	display := filepath.ToSlash(pkg.RelativeDir)
+	specRel := filepath.ToSlash(filepath.Join(pkg.RelativeDir, "SPEC.md"))
+	if !hasSpecMD(pkg) {
+		return false, fmt.Errorf("%s does not exist", specRel)
+	}
+
+	check := func() (diffs []specmd.SpecDiff, testsOK bool, testOutput string, err error) {
+		diffs, err = specImplementationDiffs(pkg.AbsolutePath())
+		if err != nil {
+			return nil, false, "", err
+		}
+		testsOK, testOutput, err = runGoTests(ctx, mod.AbsolutePath, pkg.AbsolutePath(), "")
+		return diffs, testsOK, testOutput, err
+	}
+	diffs, testsOK, testOutput, err := check()
+	if err != nil {
+		return false, err
+	}
+	if len(diffs) == 0 && testsOK {
+		if _, err := fmt.Fprintf(r.out, "%s already conforms to SPEC.md and its tests pass.\n", display); err != nil {
+			return false, err
+		}
+		return true, storeSpecConformsRecord(pkg, mod)
+	}
+
+	specPath := filepath.Join(pkg.AbsolutePath(), "SPEC.md")
+	origSpec, err := os.ReadFile(specPath)
+	if err != nil {
+		return false, err
+	}
+	// restoreSpec rewrites SPEC.md with origSpec if the agent changed or removed it, and reports whether it did.
+	restoreSpec := func() (bool, error) {
+		current, err := os.ReadFile(specPath)
+		if err == nil && bytes.Equal(current, origSpec) {
+			return false, nil
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, err
+		}
+		return true, os.WriteFile(specPath, origSpec, 0644)
+	}
+
+	opts := r.sessionOpts
+	opts.CWD = mod.AbsolutePath
+	opts.PackagePath = pkg.AbsolutePath()
+	session, err := newNoninteractiveSession(opts)
+	if err != nil {
+		return false, err
+	}
+	defer session.Close()
+
+	prompt, err := specImplementPrompt(specRel, diffs, testsOK, testOutput, true, false)
+	if err != nil {
+		return false, err
+	}
+	for step := 1; ; step++ {
+		res, err := session.SendUserMessage(ctx, prompt)
+		// An agent error that was already printed ends the step, but not the run: the checks below decide whether to continue.
+		if err != nil && !(noninteractiveIsPrinted(err) && res.TerminalEventType == agent.EventTypeError) {
+			return false, err
+		}
+
+		specEdited, err := restoreSpec()
+		if err != nil {
+			return false, err
+		}
+		if specEdited {
+			if _, err := fmt.Fprintf(r.out, "Step %d: the agent edited %s; restored it.\n", step, specRel); err != nil {
+				return false, err
+			}
+		}
+		diffs, testsOK, testOutput, err = check()
+		if err != nil {
+			return false, err
+		}
+		if len(diffs) == 0 && testsOK {
+			if _, err := fmt.Fprintf(r.out, "Step %d: %s conforms to SPEC.md and its tests pass.\n", step, display); err != nil {
+				return false, err
+			}
+			return true, storeSpecConformsRecord(pkg, mod)
+		}
+
+		status := fmt.Sprintf("Step %d: %d SPEC.md difference(s) remain", step, len(diffs))
+		if !testsOK {
+			status += "; tests fail"
+		}
+		cost, costKnown := res.TokenUsage.EstimatedCostUSD(r.modelInfo)
+		switch {
+		case step >= r.maxSteps:
+			status += fmt.Sprintf(". Stopping: reached --max-steps=%d.", r.maxSteps)
+		case r.maxCost > 0 && costKnown && cost >= r.maxCost:
+			status += fmt.Sprintf(". Stopping: estimated cost $%.2f reached --max-cost.", cost)
+		default:
+			status += "."
+			if r.maxCost > 0 && !costKnown {
+				status += " Estimated cost is unknown; --max-cost was not checked."
+			}
+			if _, err := fmt.Fprintln(r.out, status); err != nil {
+				return false, err
+			}
+			prompt, err = specImplementPrompt(specRel, diffs, testsOK, testOutput, false, specEdited)
+			if err != nil {
+				return false, err
+			}
+			continue
+		}
+		_, err = fmt.Fprintln(r.out, status)
+		return false, err
+	}
+}
+
+// parseMaxCost parses the --max-cost flag value s as a USD amount. An empty s is 0 (unlimited).
+func parseMaxCost(s string) (float64, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "$")
+	if s == "" {
+		return 0, nil
+	}
+	usd, err := strconv.ParseFloat(s, 64)
+	if err != nil || usd < 0 || math.IsNaN(usd) || math.IsInf(usd, 0) {
+		return 0, qcli.UsageError{Message: fmt.Sprintf("invalid --max-cost: must be a USD amount >= 0 (got %q)", s)}
+	}
+	return usd, nil
+}
+
+// specImplementPrompt returns the agent prompt for making the package conform to the SPEC.md at specRel, given the remaining diffs and test results. initial selects
+// the opening prompt rather than a follow-up. specRestored reports that the agent's edits to SPEC.md were reverted after the previous step.
+func specImplementPrompt(specRel string, diffs []specmd.SpecDiff, testsOK bool, testOutput string, initial bool, specRestored bool) (string, error) {
+	var b strings.Builder
+	if initial {
+		fmt.Fprintf(&b, "Make this package's implementation conform to %s. Use the $spec-md skill. ", specRel)
+		b.WriteString("SPEC.md is the source of truth: do NOT edit it. Its Public API doc comments must be matched verbatim.\n\n")
+	} else {
+		b.WriteString("The package doesn't conform yet. Please continue.\n\n")
+	}
+	if specRestored {
+		fmt.Fprintf(&b, "You edited %s. Those edits were reverted: do NOT edit it. Change the implementation instead.\n\n", specRel)
+	}
+	if len(diffs) > 0 {
+		b.WriteString("`codalotl spec diff` reports these differences between the SPEC.md Public API and the implementation:\n\n")
+		var diffText bytes.Buffer
+		if err := specmd.FormatDiffs(diffs, &diffText); err != nil {
+			return "", err
+		}
+		b.WriteString(strings.TrimRight(diffText.String(), "\n"))
+		b.WriteString("\n\n")
+	}
+	if !testsOK {
+		if len(testOutput) > maxSpecImplementTestOutput {
+			testOutput = testOutput[:maxSpecImplementTestOutput] + "\n... (truncated)"
+		}
+		b.WriteString("The package's tests fail:\n\n")
+		b.WriteString(strings.TrimRight(testOutput, "\n"))
+		b.WriteString("\n\n")
+	}
+	b.WriteString("When you're done, there must be no differences and the package's tests must pass. Update or add tests for changed behavior.\n")
+	return b.String(), nil
+}
+
+// specImplementationDiffs returns the ImplementationDiffs of the SPEC.md in pkgDir.
+func specImplementationDiffs(pkgDir string) ([]specmd.SpecDiff, error) {
+	spec, err := specmd.Read(filepath.Join(pkgDir, "SPEC.md"))
+	if err != nil {
+		return nil, err
+	}
+	return spec.ImplementationDiffs()
+}
+
+// storeSpecConformsRecord stores a specconforms CAS record for the package at pkg's directory, as it currently is on disk.
+func storeSpecConformsRecord(pkg *gocode.Package, mod *gocode.Module) error {
+	// The agent may have added files, which pkg.Reload doesn't pick up, so load the package afresh.
+	current, _, err := loadPackageDir(pkg.AbsolutePath())
+	if err != nil {
+		return err
+	}
+	db, err := casDBForBaseDir(mod.AbsolutePath)
+	if err != nil {
+		return err
+	}
+	return casconformance.Store(db, current, true)
+}
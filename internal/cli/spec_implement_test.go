@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/gocas"
+	"github.com/codalotl/codalotl/internal/gocas/casconformance"
+	"github.com/codalotl/codalotl/internal/noninteractive"
+	"github.com/stretchr/testify/require"
+)
+
+func stubRunGoTests(t *testing.T, fn func(ctx context.Context, moduleRoot string, pkgDir string, pattern string) (bool, string, error)) {
+	t.Helper()
+
+	orig := runGoTests
+	runGoTests = fn
+	t.Cleanup(func() { runGoTests = orig })
+}
+
+func TestRun_SpecImplement_IteratesUntilConforming(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	createGitRepoMarker(t, tmp)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	writePackageFile(t, tmp, "a", "package a\n\n// A returns 1.\nfunc A() int { return 1 }\n")
+	spec := "# a\n\n## Public API\n\n```go\n// A returns 1.\nfunc A() int\n\n// B returns 2.\nfunc B() int\n```\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "a", "SPEC.md"), []byte(spec), 0644))
+	t.Setenv(gocas.EnvCASDB, filepath.Join(tmp, "casdb"))
+	chdirForTest(t, tmp)
+
+	testRuns := 0
+	stubRunGoTests(t, func(_ context.Context, _ string, _ string, pattern string) (bool, string, error) {
+		require.Empty(t, pattern)
+		testRuns++
+		// Tests fail until the second step.
+		if testRuns < 3 {
+			return false, "--- FAIL: TestB", nil
+		}
+		return true, "ok", nil
+	})
+
+	session := &fakeIterateSession{
+		t:       t,
+		results: []noninteractive.Result{{}, {}},
+		onSend: func(_ context.Context, _ string, call int) {
+			if call == 0 {
+				writePackageFile(t, tmp, "a", "package a\n\n// A returns 1.\nfunc A() int { return 1 }\n\n// B returns 2.\nfunc B() int { return 2 }\n")
+			}
+		},
+	}
+	var gotOpts noninteractive.Options
+	stubNewNoninteractiveSession(t, func(opts noninteractive.Options) (iterateSession, error) {
+		gotOpts = opts
+		return session, nil
+	})
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "spec", "implement", "--yes", "./a"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+
+	require.Equal(t, filepath.Join(tmp, "a"), gotOpts.PackagePath)
+	require.True(t, gotOpts.AutoYes)
+	require.Len(t, session.sends, 2)
+	require.Contains(t, session.sends[0], "a/SPEC.md")
+	require.Contains(t, session.sends[0], "func B() int")
+	require.Contains(t, session.sends[0], "--- FAIL: TestB")
+	require.NotContains(t, session.sends[1], "func B() int")
+	require.Contains(t, session.sends[1], "--- FAIL: TestB")
+	require.Equal(t, 1, session.closeCount)
+	require.Equal(t, "Step 1: 0 SPEC.md difference(s) remain; tests fail.\nStep 2: a conforms to SPEC.md and its tests pass.\n", out.String())
+
+	var md casconformance.Metadata
+	ok, _ := retrieveCASTestRecord(t, tmp, "specconforms", "a", &md)
+	require.True(t, ok)
+	require.True(t, md.Conforms)
+}
+
+func TestRun_SpecImplement_StopsAtMaxSteps(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	createGitRepoMarker(t, tmp)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	writePackageFile(t, tmp, "a", "package a\n\n// A returns 1.\nfunc A() int { return 1 }\n")
+	spec := "# a\n\n## Public API\n\n```go\n// A returns 2.\nfunc A() int\n```\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "a", "SPEC.md"), []byte(spec), 0644))
+	writePackageFile(t, tmp, "b", "package b\n\n// B returns 1.\nfunc B() int { return 1 }\n")
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "b", "SPEC.md"), []byte("# b\n\n## Public API\n\n```go\n// B returns 1.\nfunc B() int\n```\n"), 0644))
+	t.Setenv(gocas.EnvCASDB, filepath.Join(tmp, "casdb"))
+	chdirForTest(t, tmp)
+
+	stubRunGoTests(t, func(context.Context, string, string, string) (bool, string, error) {
+		return true, "ok", nil
+	})
+	session := &fakeIterateSession{t: t, results: []noninteractive.Result{{}}}
+	stubNewNoninteractiveSession(t, func(noninteractive.Options) (iterateSession, error) {
+		return session, nil
+	})
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "spec", "implement", "--all-mismatched", "--max-steps=1"}, &RunOptions{Out: &out, Err: &errOut})
+	require.Error(t, err)
+	require.Equal(t, 1, code)
+	require.Len(t, session.sends, 1)
+	require.Equal(t, "== ./a\nStep 1: 1 SPEC.md difference(s) remain. Stopping: reached --max-steps=1.\n0 of 1 package(s) now conform to SPEC.md.\n", out.String())
+
+	ok, _ := retrieveCASTestRecord(t, tmp, "specconforms", "a", &casconformance.Metadata{})
+	require.False(t, ok)
+}
+
+func TestRun_SpecImplement_RestoresEditedSpec(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	createGitRepoMarker(t, tmp)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	writePackageFile(t, tmp, "a", "package a\n\n// A returns 1.\nfunc A() int { return 1 }\n")
+	spec := "# a\n\n## Public API\n\n```go\n// A returns 1.\nfunc A() int\n\n// B returns 2.\nfunc B() int\n```\n"
+	specPath := filepath.Join(tmp, "a", "SPEC.md")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+	t.Setenv(gocas.EnvCASDB, filepath.Join(tmp, "casdb"))
+	chdirForTest(t, tmp)
+
+	stubRunGoTests(t, func(context.Context, string, string, string) (bool, string, error) {
+		return true, "ok", nil
+	})
+	session := &fakeIterateSession{
+		t:       t,
+		results: []noninteractive.Result{{}, {}},
+		onSend: func(_ context.Context, _ string, call int) {
+			if call == 0 {
+				// Make the package "conform" by dropping B from the spec instead of implementing it.
+				require.NoError(t, os.WriteFile(specPath, []byte("# a\n\n## Public API\n\n```go\n// A returns 1.\nfunc A() int\n```\n"), 0644))
+				return
+			}
+			writePackageFile(t, tmp, "a", "package a\n\n// A returns 1.\nfunc A() int { return 1 }\n\n// B returns 2.\nfunc B() int { return 2 }\n")
+		},
+	}
+	stubNewNoninteractiveSession(t, func(noninteractive.Options) (iterateSession, error) {
+		return session, nil
+	})
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "spec", "implement", "--yes", "--model=no-such-model", "--max-cost=5", "./a"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+
+	got, err := os.ReadFile(specPath)
+	require.NoError(t, err)
+	require.Equal(t, spec, string(got))
+	require.Len(t, session.sends, 2)
+	require.Contains(t, session.sends[1], "You edited a/SPEC.md. Those edits were reverted")
+	require.Contains(t, session.sends[1], "func B() int")
+	require.Equal(t, "Step 1: the agent edited a/SPEC.md; restored it.\n"+
+		"Step 1: 1 SPEC.md difference(s) remain. Estimated cost is unknown; --max-cost was not checked.\n"+
+		"Step 2: a conforms to SPEC.md and its tests pass.\n", out.String())
+}
@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/docubot"
+	"github.com/codalotl/codalotl/internal/gocas/casconformance"
+	"github.com/codalotl/codalotl/internal/gocode"
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+	"github.com/codalotl/codalotl/internal/q/health"
+	"github.com/codalotl/codalotl/internal/q/remotemonitor"
+	"github.com/codalotl/codalotl/internal/specmd"
+)
+
+var runDocubotDraftSpec = docubot.DraftSpec
+
+// newSpecInitCommand builds the spec init command.
+func newSpecInitCommand(runWithConfig runWithConfigFunc) *qcli.Command {
+	cmd := &qcli.Command{
+		Name:  "init",
+		Short: "Draft SPEC.md for an existing package from its code.",
+		Long: "Drafts a SPEC.md from the package's docs, public API, examples, tests, dependencies, and usages using an LLM. " +
+			"The Public API section is generated from the declarations as a {api} block, so the draft conforms to the implementation; a specconforms CAS record is stored when it does. " +
+			"An existing SPEC.md is an error unless --force is set. " +
+			"With --all-packages, packages without a SPEC.md across discovered repo modules are processed in dependency order, so dependencies' SPEC.md overviews are available as context.",
+		Usage: "(<path/to/pkg> | --all-packages)",
+		ArgHelp: []qcli.ArgHelp{
+			{
+				Display:     "<path/to/pkg>",
+				Description: packagePathArgDescription,
+			},
+		},
+		Complete: completeArgs(completePackageDirs),
+		Example: strings.TrimSpace(`
+codalotl spec init internal/mypkg
+codalotl spec init --force ./internal/mypkg
+codalotl spec init --all-packages
+`),
+		Args: qcli.RangeArgs(0, 1),
+	}
+	allPackages := cmd.Flags().Bool("all-packages", 0, false, "Draft SPEC.md for every package across discovered repo modules that doesn't have one.")
+	force := cmd.Flags().Bool("force", 0, false, "Overwrite an existing SPEC.md.")
+	cmd.Run = runWithConfig("spec_init", func(c *qcli.Context, cfg Config, _ *remotemonitor.Monitor) error {
+		if *allPackages == (len(c.Args) == 1) {
+			return qcli.UsageError{Message: "supply exactly one of <path/to/pkg> or --all-packages"}
+		}
+		if *allPackages && *force {
+			return qcli.UsageError{Message: "--force cannot be combined with --all-packages"}
+		}
+
+		base := docubot.BaseOptions{
+			ReflowMaxWidth: cfg.ReflowWidth,
+			Context:        c.Context,
+			Out:            c.Out,
+			Model:          effectiveModel(cfg),
+			Ctx:            health.NewCtx(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		}
+		if *allPackages {
+			return runSpecInitAllPackages(c.Context, c.Out, base)
+		}
+
+		pkg, mod, err := loadPackageArg(c.Args[0])
+		if err != nil {
+			return err
+		}
+		if hasSpecMD(pkg) && !*force {
+			return fmt.Errorf("%s already exists; use --force to overwrite it", filepath.ToSlash(filepath.Join(pkg.RelativeDir, "SPEC.md")))
+		}
+		return specInitPackage(c.Out, pkg, mod, base)
+	})
+	return cmd
+}
+
+// repoPackage is a package loaded from a discovered repo module.
+type repoPackage struct {
+	display string          // display is the package path shown to users (relative to the repo root).
+	pkg     *gocode.Package // pkg is the loaded package.
+	mod     *gocode.Module  // mod is the package's module.
+}
+
+// runSpecInitAllPackages drafts SPEC.md for every package under the nearest git repo that doesn't have one, dependencies first. Packages that fail to load are
+// reported and skipped; docubot errors stop the run.
+func runSpecInitAllPackages(ctx context.Context, out io.Writer, base docubot.BaseOptions) error {
+	repoRoot, pkgDirs, err := goListPackageDirsUnderNearestGitRepo(ctx)
+	if err != nil {
+		return err
+	}
+	var pkgs []repoPackage
+	for _, pkgDir := range pkgDirs {
+		display, ok := displayPackagePath(repoRoot, pkgDir.absDir)
+		if !ok {
+			continue
+		}
+		pkg, err := loadPackageFromRepoDir(pkgDir)
+		if err != nil {
+			if _, err := fmt.Fprintf(out, "Skipping %s: %v\n", display, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if hasSpecMD(pkg) {
+			continue
+		}
+		pkgs = append(pkgs, repoPackage{display: display, pkg: pkg, mod: pkgDir.mod})
+	}
+
+	for _, p := range sortPackagesByDependencies(pkgs) {
+		if _, err := fmt.Fprintf(out, "== %s\n", p.display); err != nil {
+			return err
+		}
+		if err := specInitPackage(out, p.pkg, p.mod, base); err != nil {
+			return fmt.Errorf("%s: %w", p.display, err)
+		}
+	}
+	_, err = fmt.Fprintf(out, "Wrote %d SPEC.md file(s).\n", len(pkgs))
+	return err
+}
+
+// specInitPackage drafts and writes pkg's SPEC.md, overwriting any existing one, and stores a specconforms CAS record if the written SPEC.md's Public API matches
+// the implementation.
+func specInitPackage(out io.Writer, pkg *gocode.Package, mod *gocode.Module, base docubot.BaseOptions) error {
+	body, err := runDocubotDraftSpec(pkg, docubot.DraftSpecOptions{BaseOptions: base})
+	if err != nil {
+		return err
+	}
+	specPath := filepath.Join(pkg.AbsolutePath(), "SPEC.md")
+	if err := os.WriteFile(specPath, []byte(body), 0o644); err != nil {
+		return err
+	}
+	display := filepath.ToSlash(filepath.Join(pkg.RelativeDir, "SPEC.md"))
+
+	spec, err := specmd.Read(specPath)
+	if err != nil {
+		return err
+	}
+	diffs, err := spec.ImplementationDiffs()
+	if err != nil {
+		return err
+	}
+	if len(diffs) > 0 {
+		_, err := fmt.Fprintf(out, "Wrote %s, but its Public API doesn't match the implementation (see codalotl spec diff); conformance was not recorded.\n", display)
+		return err
+	}
+
+	db, err := casDBForBaseDir(mod.AbsolutePath)
+	if err != nil {
+		return err
+	}
+	if err := casconformance.Store(db, pkg, true); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "Wrote %s\n", display)
+	return err
+}
+
+// hasSpecMD reports whether pkg's directory has a SPEC.md file.
+func hasSpecMD(pkg *gocode.Package) bool {
+	return hasSpecMDDir(pkg.AbsolutePath())
+}
+
+// hasSpecMDDir reports whether dir has a SPEC.md file.
+func hasSpecMDDir(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "SPEC.md"))
+	return err == nil && !info.IsDir()
+}
+
+// sortPackagesByDependencies returns pkgs ordered so that each package comes after the packages in pkgs that it imports. Otherwise, the original order is kept.
+func sortPackagesByDependencies(pkgs []repoPackage) []repoPackage {
+	byImportPath := make(map[string]int, len(pkgs))
+	for i, p := range pkgs {
+		byImportPath[p.pkg.ImportPath] = i
+	}
+	visited := make([]bool, len(pkgs))
+	sorted := make([]repoPackage, 0, len(pkgs))
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, dep := range pkgs[i].pkg.ImportPathsModule() {
+			if j, ok := byImportPath[dep]; ok {
+				visit(j)
+			}
+		}
+		sorted = append(sorted, pkgs[i])
+	}
+	for i := range pkgs {
+		visit(i)
+	}
+	return sorted
+}
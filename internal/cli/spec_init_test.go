@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/docubot"
+	"github.com/codalotl/codalotl/internal/gocas"
+	"github.com/codalotl/codalotl/internal/gocas/casconformance"
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/specmd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_SpecInit_AllPackagesInDependencyOrder(t *testing.T) {
+	isolateUserConfig(t)
+
+	tmp := t.TempDir()
+	createGitRepoMarker(t, tmp)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	writePackageFile(t, tmp, "a", "package a\n\nimport \"example.com/tmpmod/b\"\n\n// A returns B.\nfunc A() int { return b.B() }\n")
+	writePackageFile(t, tmp, "b", "package b\n\n// B returns 1.\nfunc B() int { return 1 }\n")
+	writePackageFile(t, tmp, "c", "package c\n\n// C returns 2.\nfunc C() int { return 2 }\n")
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "c", "SPEC.md"), []byte("# c\n"), 0644))
+	t.Setenv(gocas.EnvCASDB, filepath.Join(tmp, "casdb"))
+	chdirForTest(t, tmp)
+
+	orig := runDocubotDraftSpec
+	t.Cleanup(func() { runDocubotDraftSpec = orig })
+	var calls []string
+	runDocubotDraftSpec = func(pkg *gocode.Package, _ docubot.DraftSpecOptions) (string, error) {
+		calls = append(calls, pkg.Name)
+		api, err := specmd.PublicAPI(pkg)
+		if err != nil {
+			return "", err
+		}
+		return "# " + pkg.Name + "\n\n## Public API\n\n```go {api}\n" + api + "```\n", nil
+	}
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "spec", "init", "--all-packages"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Equal(t, []string{"b", "a"}, calls)
+	require.Equal(t, "== ./b\nWrote b/SPEC.md\n== ./a\nWrote a/SPEC.md\nWrote 2 SPEC.md file(s).\n", out.String())
+
+	for _, relDir := range []string{"a", "b"} {
+		var md casconformance.Metadata
+		ok, _ := retrieveCASTestRecord(t, tmp, "specconforms", relDir, &md)
+		require.True(t, ok, relDir)
+		require.True(t, md.Conforms, relDir)
+	}
+
+	// A single package with a SPEC.md needs --force.
+	_, err = Run([]string{"codalotl", "spec", "init", "./c"}, &RunOptions{Out: &out, Err: &errOut})
+	require.ErrorContains(t, err, "c/SPEC.md already exists")
+
+	out.Reset()
+	code, err = Run([]string{"codalotl", "spec", "init", "--force", "./c"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Equal(t, "Wrote c/SPEC.md\n", out.String())
+	got, err := os.ReadFile(filepath.Join(tmp, "c", "SPEC.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(got), "// C returns 2.\nfunc C() int\n")
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -17,12 +18,14 @@ type specStatusRow struct {
 	Package  string // Display package path.
 	HasSpec  string // Whether the package has a SPEC.md file.
 	APIMatch string // Whether the SPEC.md public API matches the implementation.
+	Coverage string // Exported identifiers declared in the SPEC.md public API out of the total (ex: "9/12"), or "-" without a SPEC.md.
+	Examples string // Passing SPEC.md {example} blocks out of the total (ex: "2/3"), or "-" if there are none.
 	Conforms string // Stored CAS conformance status for the current package contents.
 }
 
 // runSpecStatus writes per-package SPEC.md status for Go modules under the nearest Git repository. It is read-only: it reports SPEC.md presence, public API match
-// status, and current CAS conformance status, sorted for the status command. Package-level SPEC or CAS failures are represented in the table when possible; discovery,
-// database, and output failures are returned as errors.
+// status, public API coverage, SPEC.md example results (running them with go test), and current CAS conformance status, sorted for the status command.
+// Package-level SPEC or CAS failures are represented in the table when possible; discovery, database, and output failures are returned as errors.
 func runSpecStatus(ctx context.Context, out io.Writer) error {
 	repoRoot, pkgDirs, err := goListPackageDirsUnderNearestGitRepo(ctx)
 	if err != nil {
@@ -47,6 +50,8 @@ func runSpecStatus(ctx context.Context, out io.Writer) error {
 			Package:  display,
 			HasSpec:  "false",
 			APIMatch: "-",
+			Coverage: "-",
+			Examples: "-",
 			Conforms: "unset",
 		}
 
@@ -61,6 +66,8 @@ func runSpecStatus(ctx context.Context, out io.Writer) error {
 			} else {
 				row.APIMatch = "false"
 			}
+			row.Coverage = specAPICoverageStatus(specPath)
+			row.Examples = specExamplesStatus(ctx, specPath)
 		}
 
 		pkg, err := loadPackageFromRepoDir(pkgDir)
@@ -115,13 +122,51 @@ func specMatchesPublicAPI(specPath string) (bool, error) {
 	return len(diffs) == 0, nil
 }
 
+// specAPICoverageStatus returns "<covered>/<total>" for the exported identifiers declared by the Public API of the SPEC.md at specPath, or "error" if coverage
+// can't be determined.
+func specAPICoverageStatus(specPath string) string {
+	spec, err := specmd.Read(specPath)
+	if err != nil {
+		return "error"
+	}
+	coverage, err := spec.APICoverage()
+	if err != nil {
+		return "error"
+	}
+	return fmt.Sprintf("%d/%d", coverage.Total-len(coverage.Uncovered), coverage.Total)
+}
+
+var runSpecMDExamples = (*specmd.Spec).RunExamples
+
+// specExamplesStatus runs the {example} blocks of the SPEC.md at specPath and returns "<passed>/<total>", "-" if there are none, or "error" if they can't be run.
+func specExamplesStatus(ctx context.Context, specPath string) string {
+	spec, err := specmd.Read(specPath)
+	if err != nil {
+		return "error"
+	}
+	results, err := runSpecMDExamples(spec, ctx)
+	if err != nil {
+		return "error"
+	}
+	if len(results) == 0 {
+		return "-"
+	}
+	passed := 0
+	for _, r := range results {
+		if r.Passed {
+			passed++
+		}
+	}
+	return fmt.Sprintf("%d/%d", passed, len(results))
+}
+
 // writeSpecStatusTable writes rows as an aligned SPEC status table.
 func writeSpecStatusTable(w io.Writer, rows []specStatusRow) error {
 	tableRows := make([][]string, 0, len(rows))
 	for _, r := range rows {
-		tableRows = append(tableRows, []string{r.Package, r.HasSpec, r.APIMatch, r.Conforms})
+		tableRows = append(tableRows, []string{r.Package, r.HasSpec, r.APIMatch, r.Coverage, r.Examples, r.Conforms})
 	}
-	return writeAlignedTable(w, []string{"package", "has_spec", "api_match", "conforms"}, tableRows)
+	return writeAlignedTable(w, []string{"package", "has_spec", "api_match", "api_coverage", "examples", "conforms"}, tableRows)
 }
 
 func boolRankTrueFirst(v string) int {
@@ -2,26 +2,43 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/codalotl/codalotl/internal/specmd"
 	"github.com/stretchr/testify/require"
 )
 
 func TestRun_SpecStatus_PrintsPerPackageStatus(t *testing.T) {
 	isolateUserConfig(t)
 
+	// Running examples is covered by specmd; here, only p1.Foo exists.
+	origRunExamples := runSpecMDExamples
+	t.Cleanup(func() { runSpecMDExamples = origRunExamples })
+	runSpecMDExamples = func(spec *specmd.Spec, _ context.Context) ([]specmd.ExampleResult, error) {
+		examples, err := spec.ExampleGoCodeBlocks()
+		if err != nil {
+			return nil, err
+		}
+		var results []specmd.ExampleResult
+		for _, ex := range examples {
+			results = append(results, specmd.ExampleResult{Example: ex, Passed: strings.Contains(ex.Code, "Foo")})
+		}
+		return results, nil
+	}
+
 	tmp := t.TempDir()
 	createGitRepoMarker(t, tmp)
 	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
 
-	// p1: has SPEC, matches API, CAS conforms true.
+	// p1: has SPEC, matches API but doesn't declare Baz, one of two examples passes, CAS conforms true.
 	p1 := filepath.Join(tmp, "p1")
 	require.NoError(t, os.MkdirAll(p1, 0755))
-	require.NoError(t, os.WriteFile(filepath.Join(p1, "p1.go"), []byte("package p1\n\nfunc Foo() {}\n"), 0644))
-	require.NoError(t, os.WriteFile(filepath.Join(p1, "SPEC.md"), []byte("# p1\n\n## Public API\n\n```go\nfunc Foo() {}\n```\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(p1, "p1.go"), []byte("package p1\n\nfunc Foo() {}\n\nfunc Baz() {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(p1, "SPEC.md"), []byte("# p1\n\n```go {example}\np1.Foo()\n```\n\n```go {example}\np1.Bar()\n```\n\n## Public API\n\n```go\nfunc Foo() {\n}\n```\n"), 0644))
 
 	// p2: has SPEC, mismatches API, CAS conforms false.
 	p2 := filepath.Join(tmp, "p2")
@@ -54,9 +71,9 @@ func TestRun_SpecStatus_PrintsPerPackageStatus(t *testing.T) {
 	rows, order := parseSpecStatusRows(out.String())
 	require.Equal(t, []string{"./p1", "./p2", "./p3"}, order)
 
-	require.Equal(t, specStatusTestRow{hasSpec: "true", apiMatch: "true", cas: "true"}, rows["./p1"])
-	require.Equal(t, specStatusTestRow{hasSpec: "true", apiMatch: "false", cas: "false"}, rows["./p2"])
-	require.Equal(t, specStatusTestRow{hasSpec: "false", apiMatch: "-", cas: "unset"}, rows["./p3"])
+	require.Equal(t, specStatusTestRow{hasSpec: "true", apiMatch: "true", coverage: "1/2", examples: "1/2", cas: "true"}, rows["./p1"])
+	require.Equal(t, specStatusTestRow{hasSpec: "true", apiMatch: "false", coverage: "1/1", examples: "-", cas: "false"}, rows["./p2"])
+	require.Equal(t, specStatusTestRow{hasSpec: "false", apiMatch: "-", coverage: "-", examples: "-", cas: "unset"}, rows["./p3"])
 }
 
 func TestRun_SpecStatus_HonorsWorkspaceDiscoveryFromRepoRoot(t *testing.T) {
@@ -71,7 +88,7 @@ func TestRun_SpecStatus_HonorsWorkspaceDiscoveryFromRepoRoot(t *testing.T) {
 	require.NoError(t, os.MkdirAll(apiModule, 0755))
 	require.NoError(t, os.WriteFile(filepath.Join(apiModule, "go.mod"), []byte("module example.com/api\n\ngo 1.22\n"), 0644))
 	require.NoError(t, os.WriteFile(filepath.Join(apiModule, "api.go"), []byte("package api\n\nfunc API() {}\n"), 0644))
-	require.NoError(t, os.WriteFile(filepath.Join(apiModule, "SPEC.md"), []byte("# api\n\n## Public API\n\n```go\nfunc API() {}\n```\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(apiModule, "SPEC.md"), []byte("# api\n\n## Public API\n\n```go\nfunc API() {\n}\n```\n"), 0644))
 
 	workerModule := filepath.Join(repo, "services", "worker")
 	require.NoError(t, os.MkdirAll(workerModule, 0755))
@@ -96,14 +113,16 @@ func TestRun_SpecStatus_HonorsWorkspaceDiscoveryFromRepoRoot(t *testing.T) {
 
 	rows, order := parseSpecStatusRows(out.String())
 	require.Equal(t, []string{"./services/api", "./services/worker/job"}, order)
-	require.Equal(t, specStatusTestRow{hasSpec: "true", apiMatch: "true", cas: "true"}, rows["./services/api"])
-	require.Equal(t, specStatusTestRow{hasSpec: "false", apiMatch: "-", cas: "unset"}, rows["./services/worker/job"])
+	require.Equal(t, specStatusTestRow{hasSpec: "true", apiMatch: "true", coverage: "1/1", examples: "-", cas: "true"}, rows["./services/api"])
+	require.Equal(t, specStatusTestRow{hasSpec: "false", apiMatch: "-", coverage: "-", examples: "-", cas: "unset"}, rows["./services/worker/job"])
 	require.NotContains(t, rows, "./rootnotworkspace")
 }
 
 type specStatusTestRow struct {
 	hasSpec  string
 	apiMatch string
+	coverage string
+	examples string
 	cas      string
 }
 
@@ -112,14 +131,14 @@ func parseSpecStatusRows(s string) (map[string]specStatusTestRow, []string) {
 	var order []string
 	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
 		fields := strings.Fields(line)
-		if len(fields) != 4 {
+		if len(fields) != 6 {
 			continue
 		}
 		if !strings.HasPrefix(fields[0], ".") {
 			continue
 		}
 		pkg := fields[0]
-		rows[pkg] = specStatusTestRow{hasSpec: fields[1], apiMatch: fields[2], cas: fields[3]}
+		rows[pkg] = specStatusTestRow{hasSpec: fields[1], apiMatch: fields[2], coverage: fields[3], examples: fields[4], cas: fields[5]}
 		order = append(order, pkg)
 	}
 	return rows, order
@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/gocas"
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	qcli "github.com/codalotl/codalotl/internal/q/cli"
+	"github.com/codalotl/codalotl/internal/q/remotemonitor"
+	"github.com/codalotl/codalotl/internal/reqtrace"
+)
+
+var specTraceCASNamespaceSpec = gocas.NamespaceSpec{
+	Name:     "spec-trace",
+	Version:  2,
+	HashMode: gocas.HashModePackage,
+}
+
+var runReqtraceMatchPackage = reqtrace.MatchPackage
+
+// specTraceCASValue is the stored CAS payload for LLM-assisted spec trace matching of a package.
+type specTraceCASValue struct {
+	Schema       string             `json:"schema"`          // Schema identifies the namespace's CAS schema.
+	Requirements string             `json:"requirements"`    // Requirements is the hash of the requirements the package was matched against (see requirementsHash).
+	Model        string             `json:"model"`           // Model is the LLM that produced Links.
+	Links        []specTraceCASLink `json:"links,omitempty"` // Links are the LLM-matched links.
+}
+
+// specTraceCASLink is a reqtrace.Link without its package directory, which is implied by the CAS record.
+type specTraceCASLink struct {
+	RequirementID string `json:"requirement_id"`
+	Kind          string `json:"kind"`
+	Target        string `json:"target"`
+	File          string `json:"file"`
+	Line          int    `json:"line"`
+}
+
+// newSpecTraceCommand builds the spec trace command.
+func newSpecTraceCommand(runWithConfig runWithConfigFunc) *qcli.Command {
+	cmd := &qcli.Command{
+		Name:  "trace",
+		Short: "Map product-spec requirements to SPEC.md sections and tests.",
+		Long: "Reads requirement IDs (ex: - [CAS-3] ...) from product-spec markdown and maps each requirement to the SPEC.md sections and Test functions that trace it, " +
+			"across packages in Go modules discovered from the nearest git repo. Links come from <!-- traces: CAS-3 --> annotations in SPEC.md and // Traces: CAS-3 lines in Test doc comments. " +
+			"With --llm, packages with a SPEC.md are also matched by an LLM; matches are cached in a spec-trace CAS record until the package or requirements change. " +
+			"Exits non-zero if an annotation references an unknown requirement ID.",
+		Usage:            "[--dir=<dir>] [--llm] [--untraced]",
+		Args:             qcli.NoArgs,
+		NoPositionalArgs: true,
+		Example: strings.TrimSpace(`
+codalotl spec trace
+codalotl spec trace --untraced
+codalotl spec trace --llm --dir=product-spec/features
+`),
+	}
+	flags := cmd.Flags()
+	dir := flags.String("dir", 0, "", "Directory of product-spec markdown to read requirements from (default: product-spec/features in the nearest git repo).")
+	useLLM := flags.Bool("llm", 0, false, "Also match requirements to packages with an LLM, caching matches in CAS.")
+	untraced := flags.Bool("untraced", 0, false, "Only print requirements that aren't traced.")
+	model := flags.String("model", 0, "", "LLM model ID to use with --llm (overrides config preferredmodel; empty = default).")
+	flags.SetComplete("model", completeModelIDs)
+
+	startupModel := func(Config) []llmmodel.ModelID {
+		modelID := llmmodel.ModelID(strings.TrimSpace(*model))
+		if !*useLLM || modelID == "" {
+			return nil
+		}
+		return []llmmodel.ModelID{modelID}
+	}
+	cmd.Run = runWithConfig("spec_trace", func(c *qcli.Context, cfg Config, _ *remotemonitor.Monitor) error {
+		var modelID llmmodel.ModelID
+		if *useLLM {
+			modelID = llmmodel.ModelID(strings.TrimSpace(*model))
+			if modelID == "" {
+				modelID = llmmodel.ModelID(strings.TrimSpace(cfg.PreferredModel))
+			}
+		}
+		return runSpecTrace(c.Context, c.Out, specTraceOptions{
+			dir:      *dir,
+			useLLM:   *useLLM,
+			model:    modelID,
+			untraced: *untraced,
+		})
+	}, startupModel)
+	return cmd
+}
+
+// specTraceOptions configures runSpecTrace.
+type specTraceOptions struct {
+	dir      string           // dir is the requirements directory; "" means product-spec/features in the nearest git repo.
+	useLLM   bool             // useLLM enables LLM-assisted matching for packages with a SPEC.md.
+	model    llmmodel.ModelID // model is the LLM used when useLLM is set; "" means the default model.
+	untraced bool             // untraced limits output to untraced requirements.
+}
+
+// runSpecTrace loads requirements, links them to packages under the nearest git repo, and writes the trace report to out.
+func runSpecTrace(ctx context.Context, out io.Writer, opts specTraceOptions) error {
+	repoRoot, pkgDirs, err := goListPackageDirsUnderNearestGitRepo(ctx)
+	if err != nil {
+		return err
+	}
+	reqDir := opts.dir
+	if reqDir == "" {
+		reqDir = filepath.Join(repoRoot, "product-spec", "features")
+	}
+	reqDir, err = filepath.Abs(reqDir)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(reqDir); err != nil {
+		return qcli.UsageError{Message: fmt.Sprintf("requirements directory %s: %v", reqDir, err)}
+	}
+	reqs, err := reqtrace.LoadRequirements(reqDir)
+	if err != nil {
+		return err
+	}
+
+	var links []reqtrace.Link
+	reqHash := requirementsHash(reqs)
+	for _, pkgDir := range pkgDirs {
+		if _, ok := displayPackagePath(repoRoot, pkgDir.absDir); !ok {
+			continue
+		}
+		pkgLinks, err := reqtrace.ScanPackage(pkgDir.absDir)
+		if err != nil {
+			return err
+		}
+		links = append(links, pkgLinks...)
+
+		if !opts.useLLM || len(reqs) == 0 {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(pkgDir.absDir, "SPEC.md")); err != nil {
+			continue
+		}
+		llmLinks, err := specTraceLLMLinks(ctx, pkgDir, reqs, reqHash, opts.model)
+		if err != nil {
+			return err
+		}
+		links = append(links, llmLinks...)
+	}
+
+	report := reqtrace.BuildReport(reqs, links)
+	if err := writeSpecTraceReport(out, repoRoot, reqDir, report, opts.untraced); err != nil {
+		return err
+	}
+	if len(report.Unknown) > 0 {
+		return qcli.ExitError{Code: 1, Err: errors.New("traces annotations reference unknown requirement IDs")}
+	}
+	return nil
+}
+
+// specTraceLLMLinks returns the LLM-matched links of the package at pkgDir, from its spec-trace CAS record when the record was made by model against requirements
+// with hash reqHash, and otherwise by calling the LLM and storing a new record.
+func specTraceLLMLinks(ctx context.Context, pkgDir casRepoPackageDir, reqs []reqtrace.Requirement, reqHash string, model llmmodel.ModelID) ([]reqtrace.Link, error) {
+	pkg, err := loadPackageFromRepoDir(pkgDir)
+	if err != nil {
+		return nil, err
+	}
+	db, err := casDBForBaseDir(pkgDir.mod.AbsolutePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Key records by the model that will actually run, so "" and an explicit default model share a record.
+	recordModel := model
+	if recordModel == "" {
+		recordModel = llmmodel.DefaultModel
+	}
+
+	var cached specTraceCASValue
+	ok, _, err := db.Retrieve(pkg, specTraceCASNamespaceSpec, &cached)
+	if err != nil {
+		return nil, err
+	}
+	if ok && cached.Requirements == reqHash && cached.Model == string(recordModel) {
+		links := make([]reqtrace.Link, 0, len(cached.Links))
+		for _, l := range cached.Links {
+			links = append(links, reqtrace.Link{
+				RequirementID: l.RequirementID,
+				Kind:          reqtrace.LinkKind(l.Kind),
+				PackageDir:    pkgDir.absDir,
+				Target:        l.Target,
+				File:          l.File,
+				Line:          l.Line,
+				Source:        reqtrace.LinkSourceLLM,
+			})
+		}
+		return links, nil
+	}
+
+	links, err := runReqtraceMatchPackage(ctx, pkgDir.absDir, reqs, reqtrace.MatchOptions{Model: model})
+	if err != nil {
+		return nil, err
+	}
+	value := specTraceCASValue{
+		Schema:       string(specTraceCASNamespaceSpec.Namespace()),
+		Requirements: reqHash,
+		Model:        string(recordModel),
+	}
+	for _, l := range links {
+		value.Links = append(value.Links, specTraceCASLink{
+			RequirementID: l.RequirementID,
+			Kind:          string(l.Kind),
+			Target:        l.Target,
+			File:          l.File,
+			Line:          l.Line,
+		})
+	}
+	if err := db.Store(pkg, specTraceCASNamespaceSpec, value); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// requirementsHash returns a hex SHA-256 of the IDs, sections, and text of reqs, so cached LLM matches are redone when requirements change.
+func requirementsHash(reqs []reqtrace.Requirement) string {
+	h := sha256.New()
+	for _, r := range reqs {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\n", r.ID, r.Section, r.Text)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeSpecTraceReport writes each requirement of report with its links (or only untraced requirements if untracedOnly), then any links to unknown requirement
+// IDs, then a summary line. Paths are shown relative to repoRoot when possible.
+func writeSpecTraceReport(out io.Writer, repoRoot string, reqDir string, report reqtrace.Report, untracedOnly bool) error {
+	reqDisplayDir := reqDir
+	if rel, err := filepath.Rel(repoRoot, reqDir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		reqDisplayDir = rel
+	}
+
+	var b strings.Builder
+	traced := 0
+	for _, t := range report.Traces {
+		if len(t.Links) > 0 {
+			traced++
+			if untracedOnly {
+				continue
+			}
+		}
+		r := t.Requirement
+		fmt.Fprintf(&b, "%s (%s:%d) %s\n", r.ID, filepath.ToSlash(filepath.Join(reqDisplayDir, r.File)), r.Line, r.Text)
+		if untracedOnly {
+			continue
+		}
+		if len(t.Links) == 0 {
+			b.WriteString("  untraced\n")
+		}
+		for _, l := range t.Links {
+			fmt.Fprintf(&b, "  %s\n", formatSpecTraceLink(repoRoot, l))
+		}
+	}
+	if len(report.Unknown) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("Unknown requirement IDs:\n")
+		for _, l := range report.Unknown {
+			fmt.Fprintf(&b, "  %s %s\n", l.RequirementID, formatSpecTraceLink(repoRoot, l))
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%d of %d requirement(s) traced.\n", traced, len(report.Traces))
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// formatSpecTraceLink formats l as "<pkg> SPEC.md > <section> (SPEC.md:<line>)" or "<pkg> <TestName> (<file>:<line>)", with an " [llm]" suffix for LLM matches.
+func formatSpecTraceLink(repoRoot string, l reqtrace.Link) string {
+	pkg, ok := displayPackagePath(repoRoot, l.PackageDir)
+	if !ok {
+		pkg = l.PackageDir
+	}
+	target := l.Target
+	if l.Kind == reqtrace.LinkKindSpecSection {
+		target = strings.TrimSuffix("SPEC.md > "+l.Target, " > ")
+	}
+	s := fmt.Sprintf("%s %s (%s:%d)", pkg, target, l.File, l.Line)
+	if l.Source == reqtrace.LinkSourceLLM {
+		s += " [llm]"
+	}
+	return s
+}
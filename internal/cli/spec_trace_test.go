@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/gocas"
+	"github.com/codalotl/codalotl/internal/reqtrace"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSpecTraceRepo writes a git repo with product-spec requirements and packages a (annotated) and b (not annotated) into a temp dir, chdirs into it, and
+// returns its path.
+func writeSpecTraceRepo(t *testing.T) string {
+	t.Helper()
+	tmp := t.TempDir()
+	createGitRepoMarker(t, tmp)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/tmpmod\n\ngo 1.22\n"), 0644))
+	featuresDir := filepath.Join(tmp, "product-spec", "features")
+	require.NoError(t, os.MkdirAll(featuresDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(featuresDir, "feat.md"), []byte("# Feat\n\n- [FEAT-1] Do one.\n- [FEAT-2] Do two.\n"), 0644))
+
+	writePackageFile(t, tmp, "a", "package a\n\n// A returns 1.\nfunc A() int { return 1 }\n")
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "a", "SPEC.md"), []byte("# a\n\n## One\n\n<!-- traces: FEAT-1 -->\nDoes one.\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "a", "a_test.go"), []byte("package a\n\nimport \"testing\"\n\n// Traces: FEAT-1\nfunc TestA(t *testing.T) {}\n"), 0644))
+
+	writePackageFile(t, tmp, "b", "package b\n\n// B returns 2.\nfunc B() int { return 2 }\n")
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "b", "SPEC.md"), []byte("# b\n\n## Two\n\nDoes two.\n"), 0644))
+
+	t.Setenv(gocas.EnvCASDB, filepath.Join(tmp, "casdb"))
+	chdirForTest(t, tmp)
+	return tmp
+}
+
+func TestRun_SpecTrace_Annotations(t *testing.T) {
+	isolateUserConfig(t)
+	writeSpecTraceRepo(t)
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "spec", "trace"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Equal(t, "FEAT-1 (product-spec/features/feat.md:3) Do one.\n"+
+		"  ./a SPEC.md > a > One (SPEC.md:5)\n"+
+		"  ./a TestA (a_test.go:5)\n"+
+		"FEAT-2 (product-spec/features/feat.md:4) Do two.\n"+
+		"  untraced\n"+
+		"\n"+
+		"1 of 2 requirement(s) traced.\n", out.String())
+
+	out.Reset()
+	code, err = Run([]string{"codalotl", "spec", "trace", "--untraced"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+	require.Equal(t, "FEAT-2 (product-spec/features/feat.md:4) Do two.\n\n1 of 2 requirement(s) traced.\n", out.String())
+}
+
+func TestRun_SpecTrace_UnknownIDs(t *testing.T) {
+	isolateUserConfig(t)
+	tmp := writeSpecTraceRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "b", "SPEC.md"), []byte("# b\n\n<!-- traces: FEAT-9 -->\n"), 0644))
+
+	var out, errOut bytes.Buffer
+	code, err := Run([]string{"codalotl", "spec", "trace", "--untraced"}, &RunOptions{Out: &out, Err: &errOut})
+	require.Error(t, err)
+	require.Equal(t, 1, code)
+	require.Contains(t, out.String(), "Unknown requirement IDs:\n  FEAT-9 ./b SPEC.md > b (SPEC.md:3)\n")
+}
+
+func TestRun_SpecTrace_LLMCachesMatches(t *testing.T) {
+	isolateUserConfig(t)
+	tmp := writeSpecTraceRepo(t)
+
+	orig := runReqtraceMatchPackage
+	t.Cleanup(func() { runReqtraceMatchPackage = orig })
+	var calls []string
+	runReqtraceMatchPackage = func(_ context.Context, pkgDir string, reqs []reqtrace.Requirement, _ reqtrace.MatchOptions) ([]reqtrace.Link, error) {
+		calls = append(calls, filepath.Base(pkgDir))
+		require.Len(t, reqs, 2)
+		if filepath.Base(pkgDir) != "b" {
+			return nil, nil
+		}
+		return []reqtrace.Link{{RequirementID: "FEAT-2", Kind: reqtrace.LinkKindSpecSection, PackageDir: pkgDir, Target: "b > Two", File: "SPEC.md", Line: 3, Source: reqtrace.LinkSourceLLM}}, nil
+	}
+
+	want := "FEAT-2 (product-spec/features/feat.md:4) Do two.\n  ./b SPEC.md > b > Two (SPEC.md:3) [llm]\n"
+	for range 2 {
+		var out, errOut bytes.Buffer
+		code, err := Run([]string{"codalotl", "spec", "trace", "--llm"}, &RunOptions{Out: &out, Err: &errOut})
+		require.NoError(t, err)
+		require.Equal(t, 0, code)
+		require.Contains(t, out.String(), want)
+		require.Contains(t, out.String(), "2 of 2 requirement(s) traced.\n")
+	}
+	require.Equal(t, []string{"a", "b"}, calls)
+
+	// Changing the requirements invalidates the cached matches.
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "product-spec", "features", "feat.md"), []byte("# Feat\n\n- [FEAT-1] Do one.\n- [FEAT-2] Do two, better.\n"), 0644))
+	var out, errOut bytes.Buffer
+	_, err := Run([]string{"codalotl", "spec", "trace", "--llm"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "a", "b"}, calls)
+
+	// So does changing the model.
+	_, err = Run([]string{"codalotl", "spec", "trace", "--llm", "--model=gpt-5.3-codex"}, &RunOptions{Out: &out, Err: &errOut})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "a", "b", "a", "b"}, calls)
+}
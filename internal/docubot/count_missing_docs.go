@@ -10,20 +10,59 @@ func CountMissingDocs(pkg *gocode.Package, options AddDocsOptions) (int, error)
 		return 0, options.LogNewErr("OnlyDocumentImportantIdentifiers and OnlyDocumentExportedIdentifiers are mutually exclusive")
 	}
 
+	return countDocTargets(pkg, options, false)
+}
+
+// DocCoverage is the documentation coverage of a set of AddDocs targets.
+type DocCoverage struct {
+	Targets    int // Targets is the number of selected AddDocs targets.
+	Documented int // Documented is the number of Targets that have docs.
+}
+
+// Percent returns Documented as a percentage of Targets. A selection with no targets is 100% covered.
+func (c DocCoverage) Percent() float64 {
+	if c.Targets == 0 {
+		return 100
+	}
+	return 100 * float64(c.Documented) / float64(c.Targets)
+}
+
+// MeasureDocCoverage returns the documentation coverage of the AddDocs targets selected by options. Targets are counted the same way as CountMissingDocs counts
+// missing docs (ex: a type with an undocumented field is one undocumented target).
+//
+// MeasureDocCoverage does not edit files or make LLM requests.
+func MeasureDocCoverage(pkg *gocode.Package, options AddDocsOptions) (DocCoverage, error) {
+	if options.OnlyDocumentExportedIdentifiers && options.OnlyDocumentImportantIdentifiers {
+		return DocCoverage{}, options.LogNewErr("OnlyDocumentImportantIdentifiers and OnlyDocumentExportedIdentifiers are mutually exclusive")
+	}
+
+	missing, err := countDocTargets(pkg, options, false)
+	if err != nil {
+		return DocCoverage{}, err
+	}
+	targets, err := countDocTargets(pkg, options, true)
+	if err != nil {
+		return DocCoverage{}, err
+	}
+	return DocCoverage{Targets: targets, Documented: targets - missing}, nil
+}
+
+// countDocTargets counts the AddDocs targets selected by options that lack docs. If ignoreDocs, existing docs are ignored, so all selected targets are counted.
+func countDocTargets(pkg *gocode.Package, options AddDocsOptions, ignoreDocs bool) (int, error) {
 	if options.OnlyDocumentImportantIdentifiers {
-		return countMissingImportantDocs(pkg, options)
+		return countMissingImportantDocs(pkg, options, ignoreDocs)
 	}
 
-	mainCount := countMissingDocsForPackage(pkg, options, options.DocumentTestFiles)
+	mainCount := countMissingDocsForPackage(pkg, options, options.DocumentTestFiles, ignoreDocs)
 	if options.OnlyDocumentExportedIdentifiers {
-		mainCount = countMissingPublicDocsForPackage(pkg, options, options.DocumentTestFiles)
+		mainCount = countMissingPublicDocsForPackage(pkg, options, options.DocumentTestFiles, ignoreDocs)
 	}
 
 	if options.DocumentTestFiles && !pkg.IsTestPackage() && pkg.HasTestPackage() {
 		if options.OnlyDocumentExportedIdentifiers {
-			mainCount += countMissingPublicDocsForPackage(pkg.TestPackage, options, true)
+			mainCount += countMissingPublicDocsForPackage(pkg.TestPackage, options, true, ignoreDocs)
 		} else {
-			mainCount += countMissingDocsForPackage(pkg.TestPackage, options, true)
+			mainCount += countMissingDocsForPackage(pkg.TestPackage, options, true, ignoreDocs)
 		}
 	}
 
@@ -55,35 +94,41 @@ func NeedsDocs(pkg *gocode.Package, options AddDocsOptions) (bool, error) {
 
 func needsDocsForPackage(pkg *gocode.Package, options AddDocsOptions, includeTest bool) bool {
 	if options.OnlyDocumentExportedIdentifiers {
-		return countMissingPublicDocsForPackage(pkg, options, includeTest) > 0
+		return countMissingPublicDocsForPackage(pkg, options, includeTest, false) > 0
 	}
-	return countMissingDocsForPackage(pkg, options, includeTest) > 0
+	return countMissingDocsForPackage(pkg, options, includeTest, false) > 0
 }
 
-func countMissingDocsForPackage(pkg *gocode.Package, options AddDocsOptions, includeTest bool) int {
-	idents := NewIdentifiersFromPackage(pkg)
-	for _, identifier := range appendExclusionForGeneratedFiles(options.ExcludeIdentifiers, pkg) {
-		idents.MarkDocumented(identifier)
-	}
-	return idents.TotalUndocumented(includeTest)
+func countMissingDocsForPackage(pkg *gocode.Package, options AddDocsOptions, includeTest bool, ignoreDocs bool) int {
+	return identifiersForCount(pkg, options, ignoreDocs).TotalUndocumented(includeTest)
+}
+
+func countMissingPublicDocsForPackage(pkg *gocode.Package, options AddDocsOptions, includeTest bool, ignoreDocs bool) int {
+	return identifiersForCount(pkg, options, ignoreDocs).TotalPublicUndocumented(includeTest)
 }
 
-func countMissingPublicDocsForPackage(pkg *gocode.Package, options AddDocsOptions, includeTest bool) int {
+// identifiersForCount returns pkg's identifiers with excluded and generated identifiers marked documented. If ignoreDocs, existing docs are dropped first, so every
+// other identifier counts as undocumented.
+func identifiersForCount(pkg *gocode.Package, options AddDocsOptions, ignoreDocs bool) *Identifiers {
 	idents := NewIdentifiersFromPackage(pkg)
+	if ignoreDocs {
+		idents.withDocs = make(map[string]struct{})
+		idents.typeDocs = make(map[string]struct{})
+	}
 	for _, identifier := range appendExclusionForGeneratedFiles(options.ExcludeIdentifiers, pkg) {
 		idents.MarkDocumented(identifier)
 	}
-	return idents.TotalPublicUndocumented(includeTest)
+	return idents
 }
 
-func countMissingImportantDocs(pkg *gocode.Package, options AddDocsOptions) (int, error) {
-	_, count, err := importantIdentifiersNeedingDocs(pkg, options.DocumentTestFiles, options, nil)
+func countMissingImportantDocs(pkg *gocode.Package, options AddDocsOptions, ignoreDocs bool) (int, error) {
+	count, err := countImportantDocTargets(pkg, options.DocumentTestFiles, options, ignoreDocs)
 	if err != nil {
 		return 0, options.LogWrappedErr("count_missing_docs.important_identifiers", err)
 	}
 
 	if options.DocumentTestFiles && !pkg.IsTestPackage() && pkg.HasTestPackage() {
-		_, testCount, err := importantIdentifiersNeedingDocs(pkg.TestPackage, true, options, nil)
+		testCount, err := countImportantDocTargets(pkg.TestPackage, true, options, ignoreDocs)
 		if err != nil {
 			return 0, options.LogWrappedErr("count_missing_docs.test_important_identifiers", err)
 		}
@@ -92,3 +137,17 @@ func countMissingImportantDocs(pkg *gocode.Package, options AddDocsOptions) (int
 
 	return count, nil
 }
+
+// countImportantDocTargets counts important identifiers in pkg that lack docs, or all important identifiers if ignoreDocs.
+func countImportantDocTargets(pkg *gocode.Package, includeTest bool, options AddDocsOptions, ignoreDocs bool) (int, error) {
+	if !ignoreDocs {
+		_, count, err := importantIdentifiersNeedingDocs(pkg, includeTest, options, nil)
+		return count, err
+	}
+	importantIDs, err := importantIdentifiersForPackage(pkg, includeTest, nil, options.BaseOptions)
+	if err != nil {
+		return 0, err
+	}
+	removeExcludedImportantIdentifiers(importantIDs, options.ExcludeIdentifiers, pkg)
+	return totalImportantUndocumented(identifiersForCount(pkg, options, true), importantIDs, includeTest), nil
+}
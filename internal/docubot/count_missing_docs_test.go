@@ -77,6 +77,50 @@ func TestCountMissingDocs_OnlyDocumentExportedIdentifiers(t *testing.T) {
 	})
 }
 
+func TestMeasureDocCoverage(t *testing.T) {
+	files := map[string]string{
+		"code.go": dedent(`
+			// Package mypkg exercises coverage.
+			package mypkg
+
+			// DocumentedPublic already has docs.
+			func DocumentedPublic() {}
+
+			func Public() {}
+			func private() {}
+
+			// PublicType has type docs.
+			type PublicType struct {
+				Public string
+			}
+
+			var PublicValue = 1
+		`),
+		"generated.go": dedent(`
+			// Code generated by x; DO NOT EDIT.
+			package mypkg
+
+			func Generated() {}
+		`),
+	}
+
+	gocodetesting.WithMultiCode(t, files, func(pkg *gocode.Package) {
+		coverage, err := MeasureDocCoverage(pkg, AddDocsOptions{OnlyDocumentExportedIdentifiers: true})
+		require.NoError(t, err)
+		assert.Equal(t, DocCoverage{Targets: 5, Documented: 2}, coverage)
+		assert.InDelta(t, 40.0, coverage.Percent(), 0.001)
+
+		coverage, err = MeasureDocCoverage(pkg, AddDocsOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, DocCoverage{Targets: 6, Documented: 2}, coverage)
+
+		_, err = MeasureDocCoverage(pkg, AddDocsOptions{OnlyDocumentExportedIdentifiers: true, OnlyDocumentImportantIdentifiers: true})
+		assert.Error(t, err)
+	})
+
+	assert.Equal(t, 100.0, DocCoverage{}.Percent())
+}
+
 func TestCountMissingDocs_DocumentTestFilesIncludesBlackBoxPackage(t *testing.T) {
 	files := map[string]string{
 		"code.go": dedent(`
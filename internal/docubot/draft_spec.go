@@ -0,0 +1,209 @@
+package docubot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/gocodecontext"
+	"github.com/codalotl/codalotl/internal/gousage"
+	"github.com/codalotl/codalotl/internal/specmd"
+)
+
+// DraftSpecOptions specifies options for drafting a SPEC.md from an existing package.
+type DraftSpecOptions struct {
+	// Shared configuration and dependencies (ex: model, completer, logging) for LLM-backed operations.
+	BaseOptions
+}
+
+// maxDraftSpecUsages caps how many importing packages are listed in the DraftSpec prompt.
+const maxDraftSpecUsages = 30
+
+// DraftSpec uses an LLM to draft SPEC.md contents for an existing package from its package doc, public API, examples, tests, in-module dependencies (including their
+// SPEC.md overviews, when present), and the packages that use it. The LLM writes the prose sections; the `## Public API` section is generated from the declarations
+// themselves (see specmd.PublicAPI) as a `{api}` block, so the draft conforms to pkg.
+//
+// DraftSpec does not write any files. The returned markdown is validated with specmd's Validate; invalid drafts are an error.
+func DraftSpec(pkg *gocode.Package, options DraftSpecOptions) (string, error) {
+	api, err := specmd.PublicAPI(pkg)
+	if err != nil {
+		return "", options.LogWrappedErr("draft_spec.public_api", err)
+	}
+	llmUserMessage, err := draftSpecUserMessage(pkg, api)
+	if err != nil {
+		return "", options.LogWrappedErr("draft_spec.context", err)
+	}
+
+	options.userMessagef("> Drafting SPEC.md for %s (%s)", pkg.Name, formatTokenCount(countTokens([]byte(llmUserMessage))))
+	responseText, err := completeText(promptDraftSpec(), llmUserMessage, options.BaseOptions)
+	if err != nil {
+		return "", options.LogWrappedErr("failed to draft SPEC.md with LLM", err)
+	}
+
+	// The LLM was told not to write a Public API section, but remove any it wrote anyway: only the generated one is guaranteed to conform.
+	prose, err := (&specmd.Spec{Body: unwrapMarkdownFence(responseText)}).WithoutPublicAPI()
+	if err != nil {
+		return "", fmt.Errorf("drafted SPEC.md is invalid markdown: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(prose.Body))
+	b.WriteString("\n")
+	if api != "" {
+		b.WriteString("\n## Public API\n\n```go {api}\n")
+		b.WriteString(api)
+		b.WriteString("```\n")
+	}
+	body := b.String()
+
+	if err := (&specmd.Spec{Body: body}).Validate(); err != nil {
+		return "", fmt.Errorf("drafted SPEC.md is invalid: %w", err)
+	}
+	return body, nil
+}
+
+// draftSpecUserMessage builds the DraftSpec LLM user message for pkg, whose generated public API code is api.
+func draftSpecUserMessage(pkg *gocode.Package, api string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Package %s (import path %s).\n\n", pkg.Name, pkg.ImportPath)
+
+	if doc := currentPackageDoc(pkg); doc != "" {
+		b.WriteString("Current package doc comment:\n\n```go\n")
+		b.WriteString(doc)
+		fmt.Fprintf(&b, "package %s\n```\n\n", pkg.Name)
+	}
+
+	if api != "" {
+		publicDocs, err := gocodecontext.PublicPackageDocumentation(pkg)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("Public API of the package:\n\n```go\n")
+		b.WriteString(strings.TrimRight(publicDocs, "\n"))
+		b.WriteString("\n```\n\n")
+	} else {
+		b.WriteString("The package has no exported declarations.\n\n")
+	}
+
+	examples, err := gocodecontext.PackageExamples(pkg)
+	if err != nil {
+		return "", err
+	}
+	if examples != "" {
+		b.WriteString("Examples:\n\n```go\n")
+		b.WriteString(strings.TrimRight(examples, "\n"))
+		b.WriteString("\n```\n\n")
+	}
+
+	if tests := testFuncNames(pkg); len(tests) > 0 {
+		b.WriteString("Test functions (their names hint at the behaviors that matter):\n")
+		for _, name := range tests {
+			fmt.Fprintf(&b, "- %s\n", name)
+		}
+		b.WriteString("\n")
+	}
+
+	if deps := pkg.ImportPathsModule(); len(deps) > 0 {
+		b.WriteString("Packages in this module that this package imports:\n")
+		for _, dep := range deps {
+			fmt.Fprintf(&b, "- %s\n", dep)
+		}
+		b.WriteString("\n")
+		for _, dep := range deps {
+			overview := dependencySpecOverview(pkg.Module, dep)
+			if overview == "" {
+				continue
+			}
+			fmt.Fprintf(&b, "Overview from the SPEC.md of %s:\n\n````markdown\n%s\n````\n\n", dep, overview)
+		}
+	}
+	if vendor := pkg.ImportPathsVendor(); len(vendor) > 0 {
+		b.WriteString("Third-party packages this package imports:\n")
+		for _, dep := range vendor {
+			fmt.Fprintf(&b, "- %s\n", dep)
+		}
+		b.WriteString("\n")
+	}
+
+	usages, err := gousage.UsedBy(pkg)
+	if err != nil {
+		return "", err
+	}
+	if len(usages) > 0 {
+		b.WriteString("Packages in this module that use this package:\n")
+		for i, u := range usages {
+			if i == maxDraftSpecUsages {
+				fmt.Fprintf(&b, "- (%d more)\n", len(usages)-i)
+				break
+			}
+			fmt.Fprintf(&b, "- %s\n", u.ImportPath)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "Write the SPEC.md for package %s.\n", pkg.Name)
+	return b.String(), nil
+}
+
+// testFuncNames returns the sorted names of Test and Example functions in pkg's test files, including its black-box test package.
+func testFuncNames(pkg *gocode.Package) []string {
+	var names []string
+	for _, p := range []*gocode.Package{pkg, pkg.TestPackage} {
+		if p == nil {
+			continue
+		}
+		for _, fn := range p.FuncSnippets {
+			if fn.Test() && fn.ReceiverType == "" && (strings.HasPrefix(fn.Name, "Test") || strings.HasPrefix(fn.Name, "Example")) {
+				names = append(names, fn.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// dependencySpecOverview returns the overview of the SPEC.md of the package with importPath in mod: everything before the first `##` heading. It returns "" if
+// the package can't be resolved or has no SPEC.md.
+func dependencySpecOverview(mod *gocode.Module, importPath string) string {
+	if mod == nil {
+		return ""
+	}
+	_, pkgDir, _, _, err := mod.ResolvePackageByImport(importPath)
+	if err != nil {
+		return ""
+	}
+	b, err := os.ReadFile(filepath.Join(pkgDir, "SPEC.md"))
+	if err != nil {
+		return ""
+	}
+	overview := string(b)
+	if strings.HasPrefix(overview, "## ") {
+		return ""
+	}
+	if i := strings.Index(overview, "\n## "); i >= 0 {
+		overview = overview[:i]
+	}
+	return strings.TrimSpace(overview)
+}
+
+// unwrapMarkdownFence returns response without a fence wrapping the whole response (ex: ````markdown ... ````), if there is one. Fences inside the markdown are
+// left alone.
+func unwrapMarkdownFence(response string) string {
+	s := strings.TrimSpace(response)
+	first, rest, ok := strings.Cut(s, "\n")
+	if !ok || !strings.HasPrefix(first, "```") {
+		return s
+	}
+	fence := first[:len(first)-len(strings.TrimLeft(first, "`"))]
+	lastNL := strings.LastIndex(rest, "\n")
+	if strings.TrimSpace(rest[lastNL+1:]) != fence {
+		return s
+	}
+	if lastNL < 0 {
+		return ""
+	}
+	return rest[:lastNL]
+}
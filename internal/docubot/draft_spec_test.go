@@ -0,0 +1,58 @@
+package docubot
+
+import (
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/gocodetesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraftSpec(t *testing.T) {
+	files := map[string]string{
+		"code.go": dedent(`
+			package mypkg
+
+			// Count returns the number of widgets.
+			func Count() int { return count }
+
+			var count int
+		`),
+		"code_test.go": dedent(`
+			package mypkg
+
+			import "testing"
+
+			func TestCountNeverNegative(t *testing.T) {}
+		`),
+	}
+	// The LLM wraps its answer in a fence and writes a Public API section anyway; both are removed.
+	response := "````markdown\n# mypkg\n\nCounts widgets.\n\n- Counts are never negative.\n\n## Public API\n\n```go\nfunc Count() string\n```\n````"
+	conv := &responsesCompleter{responses: []string{response}}
+
+	gocodetesting.WithMultiCode(t, files, func(pkg *gocode.Package) {
+		got, err := DraftSpec(pkg, DraftSpecOptions{BaseOptions: BaseOptions{Completer: conv}})
+		require.NoError(t, err)
+		assert.Equal(t, "# mypkg\n\nCounts widgets.\n\n- Counts are never negative.\n\n## Public API\n\n```go {api}\n// Count returns the number of widgets.\nfunc Count() int\n```\n", got)
+
+		if assert.Len(t, conv.convs, 1) {
+			user := conv.convs[0].userMessagesText[0]
+			assert.Contains(t, user, "func Count() int")
+			assert.Contains(t, user, "- TestCountNeverNegative\n")
+			assert.NotContains(t, user, "var count")
+		}
+	})
+}
+
+func TestUnwrapMarkdownFence(t *testing.T) {
+	tests := map[string]string{
+		"# p\n\ntext": "# p\n\ntext",
+		"````markdown\n# p\n\n```go\nx\n```\n````": "# p\n\n```go\nx\n```",
+		"```\n# p\n```":        "# p",
+		"# p\n\n```go\nx\n```": "# p\n\n```go\nx\n```",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, unwrapMarkdownFence(in), in)
+	}
+}
@@ -0,0 +1,466 @@
+package docubot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/gocodecontext"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// ExampleRunner runs the Example functions in the package at pkgDir whose names match the go test -run pattern. ok reports whether all of them compiled and passed;
+// output is the go test output. err is only for failures to run at all.
+type ExampleRunner func(pkgDir string, pattern string) (ok bool, output string, err error)
+
+// AddExamplesOptions specifies options for generating runnable Example functions.
+type AddExamplesOptions struct {
+	// RunExamples runs the generated examples. It is required: docubot never keeps an example it could not verify.
+	RunExamples ExampleRunner
+
+	// Shared configuration and dependencies (ex: model, completer, logging) for LLM-backed operations.
+	BaseOptions
+}
+
+// AddExamplesResult describes the outcome of AddExamples.
+type AddExamplesResult struct {
+	File    string   // File is the test file (relative to the package dir) the kept examples were written to. Empty if no examples were kept.
+	Targets []string // Targets are the sorted identifiers that should have examples: important exported functions, types, and methods.
+	Covered []string // Covered are the sorted Targets that have an example after the run, including examples that already existed.
+	Added   []string // Added are the sorted names of the Example functions that were written and passed.
+	Dropped []string // Dropped are the sorted names of generated Example functions that were removed because they did not compile or pass.
+}
+
+// maxExampleFixAttempts is the number of times the LLM is asked to fix failing examples before they are dropped.
+const maxExampleFixAttempts = 1
+
+// AddExamples uses an LLM to write runnable Example functions (with // Output: blocks) for important exported identifiers of pkg that don't have one yet. Identifiers
+// are selected with the same importance logic as important-only docs. Examples are written to a new example_test.go file in the external test package (or the next
+// free example_N_test.go name), run with options.RunExamples, and given one LLM fix attempt if they fail (an example without an output comment fails, since go
+// test never runs it). Examples that still fail are dropped; if none pass, the file is removed.
+//
+// AddExamples writes into pkg's directory; pkg itself is not reloaded. An error is returned if options.RunExamples is nil, or for LLM and I/O failures.
+func AddExamples(pkg *gocode.Package, options AddExamplesOptions) (AddExamplesResult, error) {
+	if options.RunExamples == nil {
+		return AddExamplesResult{}, errors.New("AddExamples: RunExamples is required")
+	}
+	if pkg.IsTestPackage() {
+		return AddExamplesResult{}, errors.New("AddExamples: pkg must not be a test package")
+	}
+
+	targets, covered, err := exampleTargets(pkg, options.BaseOptions)
+	if err != nil {
+		return AddExamplesResult{}, err
+	}
+	result := AddExamplesResult{Targets: targets}
+
+	var pending []string
+	for _, id := range targets {
+		if _, ok := covered[id]; !ok {
+			pending = append(pending, id)
+		}
+	}
+	if len(pending) == 0 {
+		options.userMessagef("Every important exported identifier already has an example")
+		result.Covered = sortedKeys(covered)
+		return result, nil
+	}
+
+	names := make([]string, len(pending))
+	for i, id := range pending {
+		names[i] = exampleNameForIdentifier(id)
+	}
+
+	src, err := generateExamplesFile(pkg, pending, names, options)
+	if err != nil {
+		return result, err
+	}
+
+	fileName := exampleFileName(pkg.AbsolutePath())
+	path := filepath.Join(pkg.AbsolutePath(), fileName)
+	kept, dropped, err := verifyExamplesFile(path, src, names, options)
+	if err != nil {
+		return result, err
+	}
+
+	if len(kept) > 0 {
+		result.File = fileName
+	}
+	for i, name := range names {
+		if slices.Contains(kept, name) {
+			covered[pending[i]] = struct{}{}
+		}
+	}
+	result.Covered = sortedKeys(covered)
+	result.Added = kept
+	result.Dropped = dropped
+	sort.Strings(result.Added)
+	sort.Strings(result.Dropped)
+	return result, nil
+}
+
+// exampleTargets returns the sorted important exported identifiers of pkg that Example functions can be written for, and the subset of them that already have an
+// example in pkg or its test package.
+func exampleTargets(pkg *gocode.Package, options BaseOptions) ([]string, map[string]struct{}, error) {
+	important, err := importantIdentifiersForPackage(pkg, false, nil, options)
+	if err != nil {
+		return nil, nil, options.LogWrappedErr("add_examples.important_identifiers", err)
+	}
+
+	// Only functions, methods, and types can have Example functions. Note that struct fields share the "T.F" form with methods, so candidates come from snippets.
+	candidates := map[string]struct{}{}
+	for _, fn := range pkg.FuncSnippets {
+		if !fn.Test() && fn.HasExported() {
+			candidates[fn.Identifier] = struct{}{}
+		}
+	}
+	for _, ts := range pkg.TypeSnippets {
+		if ts.Test() {
+			continue
+		}
+		for _, id := range ts.Identifiers {
+			if ast.IsExported(id) {
+				candidates[id] = struct{}{}
+			}
+		}
+	}
+
+	var targets []string
+	for id := range important {
+		if _, ok := candidates[id]; ok {
+			targets = append(targets, id)
+		}
+	}
+	sort.Strings(targets)
+
+	existing := map[string]struct{}{}
+	for _, p := range []*gocode.Package{pkg, pkg.TestPackage} {
+		if p == nil {
+			continue
+		}
+		for _, fn := range p.FuncSnippets {
+			if fn.Test() && fn.ReceiverType == "" && strings.HasPrefix(fn.Name, "Example") {
+				existing[exampleNameTarget(fn.Name)] = struct{}{}
+			}
+		}
+	}
+	covered := map[string]struct{}{}
+	for _, id := range targets {
+		if _, ok := existing[strings.TrimPrefix(id, "*")]; ok {
+			covered[id] = struct{}{}
+		}
+	}
+	return targets, covered, nil
+}
+
+// exampleNameForIdentifier returns the Example function name for identifier (ex: "Foo" -> "ExampleFoo"; "*T.M" -> "ExampleT_M").
+func exampleNameForIdentifier(identifier string) string {
+	return "Example" + strings.ReplaceAll(strings.TrimPrefix(identifier, "*"), ".", "_")
+}
+
+// exampleNameTarget returns the identifier an Example function name documents, without any pointer receiver marker (ex: "ExampleT_M_second" -> "T.M"). Following
+// go doc, a trailing "_suffix" that starts with a lower-case letter is a suffix, not part of the identifier.
+func exampleNameTarget(name string) string {
+	parts := strings.Split(strings.TrimPrefix(name, "Example"), "_")
+	if len(parts) > 1 {
+		if r, _ := utf8.DecodeRuneInString(parts[len(parts)-1]); !unicode.IsUpper(r) {
+			parts = parts[:len(parts)-1]
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// generateExamplesFile asks the LLM for a test file containing an Example function named names[i] for each identifiers[i], and returns it gofmt-ed.
+func generateExamplesFile(pkg *gocode.Package, identifiers []string, names []string, options AddExamplesOptions) ([]byte, error) {
+	specContext, err := specContextForPackage(pkg, nil)
+	if err != nil {
+		return nil, options.LogWrappedErr("add_examples.spec_context", err)
+	}
+	publicDocs, err := gocodecontext.PublicPackageDocumentation(pkg)
+	if err != nil {
+		return nil, options.LogWrappedErr("add_examples.public_docs", err)
+	}
+
+	llmUserMessage := specContext + publicDocs + instructionsForExamples(pkg, identifiers, names)
+	options.userMessagef("> Writing examples for %d identifiers: %s (%s)", len(identifiers), strings.Join(identifiers, ", "), formatTokenCount(countTokens([]byte(llmUserMessage))))
+
+	responseText, err := completeText(promptWriteExamples(), llmUserMessage, options.BaseOptions)
+	if err != nil {
+		return nil, options.LogWrappedErr("failed to write examples with LLM", err)
+	}
+	return exampleFileFromResponse(responseText)
+}
+
+// instructionsForExamples returns the user-message suffix describing the package under test and the Example functions to write.
+func instructionsForExamples(pkg *gocode.Package, identifiers []string, names []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nWrite a file in `package %s_test` that imports %q. Write these Example functions:\n", pkg.Name, pkg.ImportPath)
+	for i, id := range identifiers {
+		fmt.Fprintf(&b, "- %s (for %s)\n", names[i], id)
+	}
+	return b.String()
+}
+
+// exampleFileFromResponse extracts the single Go file from an LLM response and formats it.
+func exampleFileFromResponse(response string) ([]byte, error) {
+	snippets := extractSnippets(response)
+	if len(snippets) != 1 {
+		return nil, fmt.Errorf("expected exactly one code block with the examples file, got %d", len(snippets))
+	}
+	src, err := format.Source([]byte(snippets[0]))
+	if err != nil {
+		return nil, fmt.Errorf("examples file does not parse: %w", err)
+	}
+	return src, nil
+}
+
+// exampleFileName returns "example_test.go", or the first unused "example_N_test.go" name if that already exists in dir.
+func exampleFileName(dir string) string {
+	name := "example_test.go"
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(dir, name)); os.IsNotExist(err) {
+			return name
+		}
+		name = "example_" + strconv.Itoa(i) + "_test.go"
+	}
+}
+
+// verifyExamplesFile writes src to path and runs the named examples, asking the LLM to fix failures up to maxExampleFixAttempts times. An example without an
+// output comment counts as failing, since go test never runs it. Examples that still fail are removed from the file; if the file does not compile or no example
+// passes, the file is removed. It returns the kept and dropped example names.
+func verifyExamplesFile(path string, src []byte, names []string, options AddExamplesOptions) ([]string, []string, error) {
+	pkgDir := filepath.Dir(path)
+	remove := func() error {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		// Names the LLM didn't write are dropped up front.
+		present := exampleFuncNames(src)
+		var kept, dropped []string
+		for _, name := range names {
+			if slices.Contains(present, name) {
+				kept = append(kept, name)
+			} else {
+				dropped = append(dropped, name)
+			}
+		}
+		if len(kept) == 0 {
+			return nil, dropped, nil
+		}
+		src = removeFuncs(src, present, kept)
+
+		if err := os.WriteFile(path, src, 0644); err != nil {
+			return nil, nil, err
+		}
+		ok, output, err := options.RunExamples(pkgDir, examplesRunPattern(kept))
+		if err != nil {
+			return nil, nil, errors.Join(err, remove())
+		}
+		// go test compiles an example without an output comment but never runs it, so it would "pass" unverified. Report it like a failure.
+		if notRun := examplesWithoutOutput(src, kept); len(notRun) > 0 {
+			ok = false
+			var b strings.Builder
+			for _, name := range notRun {
+				fmt.Fprintf(&b, "--- FAIL: %s: no // Output: comment, so go test compiles it but never runs it\n", name)
+			}
+			output = b.String() + output
+		}
+		if ok {
+			return kept, dropped, nil
+		}
+
+		if attempt < maxExampleFixAttempts {
+			options.userMessagef("> Examples failed; asking for a fix")
+			responseText, err := completeText(promptWriteExamples(), instructionsForFixingExamples(src, output), options.BaseOptions)
+			if err != nil {
+				return nil, nil, errors.Join(options.LogWrappedErr("failed to fix examples with LLM", err), remove())
+			}
+			if fixed, err := exampleFileFromResponse(responseText); err == nil {
+				src = fixed
+			}
+			continue
+		}
+
+		// Drop the examples that go test reports as failing and re-verify the rest. If nothing is attributable (ex: a compile error), drop everything.
+		failed := failedExampleNames(output, kept)
+		if len(failed) == 0 || len(failed) == len(kept) {
+			return nil, append(dropped, kept...), remove()
+		}
+		options.userMessagef("< Dropping %d failing examples: %s", len(failed), strings.Join(failed, ", "))
+		var passing []string
+		for _, name := range kept {
+			if !slices.Contains(failed, name) {
+				passing = append(passing, name)
+			}
+		}
+		src = removeFuncs(src, kept, passing)
+		if err := os.WriteFile(path, src, 0644); err != nil {
+			return nil, nil, err
+		}
+		ok, _, err = options.RunExamples(pkgDir, examplesRunPattern(passing))
+		if err != nil {
+			return nil, nil, errors.Join(err, remove())
+		}
+		if !ok {
+			return nil, append(dropped, kept...), remove()
+		}
+		return passing, append(dropped, failed...), nil
+	}
+}
+
+// instructionsForFixingExamples returns the user message asking the LLM to fix src given the failing go test output.
+func instructionsForFixingExamples(src []byte, output string) string {
+	var b strings.Builder
+	b.WriteString("These examples fail. Fix them and return the complete corrected file. Keep every Example function name; do not add new ones. ")
+	b.WriteString("If an example's output is wrong, fix the example or its // Output: block so they agree with the package's actual behavior.\n\n")
+	b.WriteString("```go\n")
+	b.Write(src)
+	b.WriteString("```\n\n")
+	b.WriteString("go test output:\n\n```\n")
+	b.WriteString(strings.TrimRight(output, "\n"))
+	b.WriteString("\n```\n")
+	return b.String()
+}
+
+// examplesRunPattern returns a go test -run pattern that matches exactly names.
+func examplesRunPattern(names []string) string {
+	return "^(" + strings.Join(names, "|") + ")$"
+}
+
+// failedExampleNames returns the names that go test output reports as failing ("--- FAIL: ExampleFoo"), in names order.
+func failedExampleNames(output string, names []string) []string {
+	var failed []string
+	for _, name := range names {
+		re := regexp.MustCompile(`--- FAIL: ` + regexp.QuoteMeta(name) + `\b`)
+		if re.MatchString(output) {
+			failed = append(failed, name)
+		}
+	}
+	return failed
+}
+
+// exampleFuncNames returns the names of top-level Example functions in src. It returns nil if src doesn't parse.
+func exampleFuncNames(src []byte) []string {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && strings.HasPrefix(fn.Name.Name, "Example") {
+			names = append(names, fn.Name.Name)
+		}
+	}
+	return names
+}
+
+// examplesWithoutOutput returns the names (in names order) of Example functions in src that have no output comment, so go test doesn't run them. It returns
+// nil if src doesn't parse.
+func examplesWithoutOutput(src []byte, names []string) []string {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+	runnable := map[string]bool{}
+	for _, ex := range doc.Examples(f) {
+		if ex.Output != "" || ex.EmptyOutput {
+			runnable["Example"+ex.Name] = true
+		}
+	}
+	var missing []string
+	for _, name := range names {
+		if !runnable[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// removeFuncs removes the functions in candidates but not in keep from src, along with any imports that become unused. It returns src unchanged if nothing needs
+// removing or src doesn't parse.
+func removeFuncs(src []byte, candidates []string, keep []string) []byte {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return src
+	}
+
+	removed := false
+	decls := f.Decls[:0]
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && slices.Contains(candidates, fn.Name.Name) && !slices.Contains(keep, fn.Name.Name) {
+			removed = true
+			start := fn.Pos()
+			if fn.Doc != nil {
+				start = fn.Doc.Pos()
+			}
+			removeCommentsInRange(f, start, fn.End())
+			continue
+		}
+		decls = append(decls, decl)
+	}
+	if !removed {
+		return src
+	}
+	f.Decls = decls
+
+	// DeleteNamedImport edits f.Imports, so iterate over a copy.
+	for _, imp := range slices.Clone(f.Imports) {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || astutil.UsesImport(f, path) {
+			continue
+		}
+		name := ""
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		astutil.DeleteNamedImport(fset, f, name, path)
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return src
+	}
+	return buf.Bytes()
+}
+
+// removeCommentsInRange drops comment groups of f that fall within [pos, end].
+func removeCommentsInRange(f *ast.File, pos token.Pos, end token.Pos) {
+	comments := f.Comments[:0]
+	for _, cg := range f.Comments {
+		if cg.Pos() >= pos && cg.End() <= end {
+			continue
+		}
+		comments = append(comments, cg)
+	}
+	f.Comments = comments
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
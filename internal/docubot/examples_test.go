@@ -0,0 +1,231 @@
+package docubot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/gocodetesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubExampleRunner returns an ExampleRunner that fails the examples in the pattern whose function body contains "WRONG" (reporting them like go test), and
+// records the patterns it was called with.
+func stubExampleRunner(patterns *[]string) ExampleRunner {
+	return func(pkgDir string, pattern string) (bool, string, error) {
+		*patterns = append(*patterns, pattern)
+		src, err := os.ReadFile(filepath.Join(pkgDir, "example_test.go"))
+		if err != nil {
+			return false, "", err
+		}
+		var out strings.Builder
+		ok := true
+		names := strings.Split(strings.TrimSuffix(strings.TrimPrefix(pattern, "^("), ")$"), "|")
+		for _, fn := range strings.Split(string(src), "\nfunc ")[1:] {
+			for _, name := range names {
+				if strings.HasPrefix(fn, name+"(") && strings.Contains(fn, "WRONG") {
+					ok = false
+					out.WriteString("--- FAIL: " + name + " (0.00s)\n")
+				}
+			}
+		}
+		return ok, out.String(), nil
+	}
+}
+
+func TestAddExamples(t *testing.T) {
+	code := dedent(`
+		// Foo returns 1.
+		func Foo() int { return 1 }
+
+		// Bar returns 2.
+		func Bar() int { return 2 }
+
+		// T is a thing.
+		type T struct{ N int }
+
+		// M returns N.
+		func (t *T) M() int { return t.N }
+
+		func unexported() {}
+	`)
+	existing := dedent(`
+		package mypkg_test
+
+		func ExampleBar() {}
+	`)
+	generated := dedentWithBackticks(`
+		package mypkg_test
+
+		import (
+			"fmt"
+			"strings"
+
+			"mymodule/mypkg"
+		)
+
+		func ExampleFoo() {
+			fmt.Println(mypkg.Foo())
+			// Output: 1
+		}
+
+		// ExampleT shows T.
+		func ExampleT() {
+			fmt.Println(mypkg.T{N: 3}, WRONG)
+			// Output: {3}
+		}
+
+		// ExampleT_M shows M.
+		func ExampleT_M() {
+			t := &mypkg.T{N: 3}
+			fmt.Println(strings.TrimSpace(" "), t.M(), WRONG)
+			// Output: 3
+		}
+	`)
+	fixed := strings.Replace(generated, "mypkg.T{N: 3}, WRONG", "mypkg.T{N: 3}", 1)
+	conv := &responsesCompleter{responses: []string{generated, fixed}}
+
+	gocodetesting.WithMultiCode(t, map[string]string{"code.go": code, "a_test.go": existing}, func(pkg *gocode.Package) {
+		var patterns []string
+		result, err := AddExamples(pkg, AddExamplesOptions{RunExamples: stubExampleRunner(&patterns), BaseOptions: BaseOptions{Completer: conv}})
+		require.NoError(t, err)
+
+		assert.Equal(t, AddExamplesResult{
+			File:    "example_test.go",
+			Targets: []string{"*T.M", "Bar", "Foo", "T"},
+			Covered: []string{"Bar", "Foo", "T"},
+			Added:   []string{"ExampleFoo", "ExampleT"},
+			Dropped: []string{"ExampleT_M"},
+		}, result)
+		assert.Equal(t, []string{
+			"^(ExampleT_M|ExampleFoo|ExampleT)$",
+			"^(ExampleT_M|ExampleFoo|ExampleT)$",
+			"^(ExampleFoo|ExampleT)$",
+		}, patterns)
+
+		// ExampleBar already exists, so it isn't requested; the fix request includes the failure output.
+		if assert.Len(t, conv.convs, 2) {
+			assert.Contains(t, conv.convs[0].userMessagesText[0], "- ExampleT_M (for *T.M)\n")
+			assert.NotContains(t, conv.convs[0].userMessagesText[0], "ExampleBar")
+			assert.Contains(t, conv.convs[1].userMessagesText[0], "--- FAIL: ExampleT_M")
+		}
+
+		got, err := os.ReadFile(filepath.Join(pkg.AbsolutePath(), "example_test.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(got), "func ExampleT() {")
+		assert.NotContains(t, string(got), "ExampleT_M")
+		assert.NotContains(t, string(got), `"strings"`)
+	})
+}
+
+func TestAddExamples_DropsExamplesWithoutOutput(t *testing.T) {
+	code := dedent(`
+		// Foo returns 1.
+		func Foo() int { return 1 }
+
+		// Bar returns 2.
+		func Bar() int { return 2 }
+	`)
+	generated := dedentWithBackticks(`
+		package mypkg_test
+
+		import (
+			"fmt"
+
+			"mymodule/mypkg"
+		)
+
+		func ExampleFoo() {
+			fmt.Println(mypkg.Foo())
+			// Output: 1
+		}
+
+		func ExampleBar() {
+			fmt.Println(mypkg.Bar())
+		}
+	`)
+	conv := &responsesCompleter{responses: []string{generated, generated}}
+
+	gocodetesting.WithCode(t, code, func(pkg *gocode.Package) {
+		var patterns []string
+		result, err := AddExamples(pkg, AddExamplesOptions{RunExamples: stubExampleRunner(&patterns), BaseOptions: BaseOptions{Completer: conv}})
+		require.NoError(t, err)
+
+		// The runner passes both, but go test would never run ExampleBar.
+		assert.Equal(t, []string{"ExampleFoo"}, result.Added)
+		assert.Equal(t, []string{"ExampleBar"}, result.Dropped)
+		assert.Equal(t, []string{"Foo"}, result.Covered)
+		if assert.Len(t, conv.convs, 2) {
+			assert.Contains(t, conv.convs[1].userMessagesText[0], "--- FAIL: ExampleBar: no // Output: comment")
+		}
+
+		got, err := os.ReadFile(filepath.Join(pkg.AbsolutePath(), "example_test.go"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(got), "ExampleBar")
+	})
+}
+
+func TestAddExamples_CompileFailureRemovesFile(t *testing.T) {
+	code := dedent(`
+		// Foo returns 1.
+		func Foo() int { return 1 }
+	`)
+	broken := dedentWithBackticks(`
+		package mypkg_test
+
+		func ExampleFoo() {
+			WRONG
+		}
+	`)
+	conv := &responsesCompleter{responses: []string{broken, broken}}
+
+	gocodetesting.WithCode(t, code, func(pkg *gocode.Package) {
+		var patterns []string
+		result, err := AddExamples(pkg, AddExamplesOptions{RunExamples: stubExampleRunner(&patterns), BaseOptions: BaseOptions{Completer: conv}})
+		require.NoError(t, err)
+		assert.Empty(t, result.File)
+		assert.Empty(t, result.Added)
+		assert.Empty(t, result.Covered)
+		assert.Equal(t, []string{"ExampleFoo"}, result.Dropped)
+		assert.NoFileExists(t, filepath.Join(pkg.AbsolutePath(), "example_test.go"))
+	})
+}
+
+func TestAddExamples_AllCoveredIsNoOp(t *testing.T) {
+	files := map[string]string{
+		"code.go":   "// Foo returns 1.\nfunc Foo() int { return 1 }\n",
+		"a_test.go": "package mypkg\n\nfunc ExampleFoo_second() {}\n",
+	}
+	gocodetesting.WithMultiCode(t, files, func(pkg *gocode.Package) {
+		result, err := AddExamples(pkg, AddExamplesOptions{
+			RunExamples: func(string, string) (bool, string, error) { panic("unexpected run") },
+			BaseOptions: BaseOptions{Completer: &responsesCompleter{}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Foo"}, result.Targets)
+		assert.Equal(t, []string{"Foo"}, result.Covered)
+		assert.Empty(t, result.Added)
+	})
+
+	_, err := AddExamples(nil, AddExamplesOptions{})
+	assert.ErrorContains(t, err, "RunExamples is required")
+}
+
+func TestExampleNames(t *testing.T) {
+	assert.Equal(t, "ExampleFoo", exampleNameForIdentifier("Foo"))
+	assert.Equal(t, "ExampleT_M", exampleNameForIdentifier("*T.M"))
+
+	tests := map[string]string{
+		"ExampleFoo":        "Foo",
+		"ExampleFoo_second": "Foo",
+		"ExampleT_M":        "T.M",
+		"ExampleT_M_other":  "T.M",
+		"Example":           "",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, exampleNameTarget(name), name)
+	}
+}
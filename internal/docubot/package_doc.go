@@ -0,0 +1,138 @@
+package docubot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/gocodecontext"
+	"github.com/codalotl/codalotl/internal/gopackagediff"
+	"github.com/codalotl/codalotl/internal/updatedocs"
+)
+
+// ErrNoSpec is returned by operations that are driven by a package's SPEC.md when the package has no SPEC.md (or only a Public API section).
+var ErrNoSpec = errors.New("package has no SPEC.md content")
+
+// PackageDocOptions specifies options for generating package documentation from SPEC.md.
+type PackageDocOptions struct {
+	// Shared configuration and dependencies (ex: model, completer, logging) for LLM-backed operations.
+	BaseOptions
+}
+
+// GeneratePackageDoc uses an LLM to synthesize or update pkg's package doc comment from its SPEC.md (excluding Public API sections) and its public API, so that the
+// two don't drift apart. The comment is written to doc.go, or to the file that already holds the package doc, and reflowed to options.ReflowMaxWidth.
+//
+// It returns ErrNoSpec if pkg has no usable SPEC.md. If the LLM returns the current doc unchanged, no change is recorded. pkg's files are edited in place; the returned
+// diff only covers the package doc.
+func GeneratePackageDoc(pkg *gocode.Package, options PackageDocOptions) ([]*gopackagediff.Change, error) {
+	specContext, err := specContextForPackage(pkg, nil)
+	if err != nil {
+		return nil, options.LogWrappedErr("package_doc.spec_context", err)
+	}
+	if specContext == "" {
+		return nil, ErrNoSpec
+	}
+	publicDocs, err := gocodecontext.PublicPackageDocumentation(pkg)
+	if err != nil {
+		return nil, options.LogWrappedErr("package_doc.public_docs", err)
+	}
+
+	var b strings.Builder
+	b.WriteString(specContext)
+	b.WriteString("Public API of the package:\n\n```go\n")
+	b.WriteString(strings.TrimRight(publicDocs, "\n"))
+	b.WriteString("\n```\n\n")
+	if doc := currentPackageDoc(pkg); doc != "" {
+		b.WriteString("Current package doc comment:\n\n```go\n")
+		b.WriteString(doc)
+		fmt.Fprintf(&b, "package %s\n```\n\n", pkg.Name)
+	} else {
+		b.WriteString("The package has no package doc comment yet.\n\n")
+	}
+	fmt.Fprintf(&b, "Write the package doc comment for package %s.\n", pkg.Name)
+	llmUserMessage := b.String()
+
+	options.userMessagef("> Writing package doc for %s from SPEC.md (%s)", pkg.Name, formatTokenCount(countTokens([]byte(llmUserMessage))))
+	responseText, err := completeText(promptPackageDocFromSpec(), llmUserMessage, options.BaseOptions)
+	if err != nil {
+		return nil, options.LogWrappedErr("failed to write package doc with LLM", err)
+	}
+	snippets := extractSnippets(responseText)
+	if len(snippets) != 1 {
+		return nil, fmt.Errorf("expected exactly one package doc snippet, got %d", len(snippets))
+	}
+
+	clonedForDiff, err := pkg.Clone()
+	if err != nil {
+		return nil, options.LogWrappedErr("package_doc.clone.for_diff", err)
+	}
+	defer clonedForDiff.Module.DeleteClone()
+
+	updatedPkg, _, snippetErrs, err := updatedocs.UpdateDocumentation(pkg, snippets, options.updatedocsOptions(false))
+	if err != nil {
+		return nil, options.LogWrappedErr("package_doc.update_documentation", err)
+	}
+	if len(snippetErrs) > 0 {
+		return nil, fmt.Errorf("invalid package doc snippet: %s", snippetErrs[0].UserErrorMessage)
+	}
+
+	changes, err := gopackagediff.Diff(clonedForDiff, updatedPkg, []string{gocode.PackageIdentifier}, nil, true)
+	if err != nil {
+		return nil, options.LogWrappedErr("package_doc.diff", err)
+	}
+	return changes, nil
+}
+
+// currentPackageDoc returns pkg's package doc comment (including comment markers), or "" if it has none.
+func currentPackageDoc(pkg *gocode.Package) string {
+	if s, ok := pkg.GetSnippet(gocode.PackageIdentifier).(*gocode.PackageDocSnippet); ok {
+		return s.Doc
+	}
+	return ""
+}
+
+// SpecContradiction is a statement in a package doc comment that contradicts the package's SPEC.md.
+type SpecContradiction struct {
+	PackageDoc  string `json:"doc"`         // PackageDoc quotes the contradicting text from the package doc comment.
+	Spec        string `json:"spec"`        // Spec quotes the contradicting text from SPEC.md.
+	Explanation string `json:"explanation"` // Explanation describes the contradiction.
+}
+
+// SpecContradictionsOptions specifies options for FindSpecContradictions.
+type SpecContradictionsOptions struct {
+	// Shared configuration and dependencies (ex: model, completer, logging) for LLM-backed operations.
+	BaseOptions
+}
+
+// FindSpecContradictions uses an LLM to find statements in pkg's package doc comment that contradict its SPEC.md. It only reports; nothing is edited, since either
+// side may be the one that is wrong.
+//
+// It returns ErrNoSpec if pkg has no usable SPEC.md, and no contradictions (without an LLM request) if pkg has no package doc comment.
+func FindSpecContradictions(pkg *gocode.Package, options SpecContradictionsOptions) ([]SpecContradiction, error) {
+	specContext, err := specContextForPackage(pkg, nil)
+	if err != nil {
+		return nil, options.LogWrappedErr("spec_contradictions.spec_context", err)
+	}
+	if specContext == "" {
+		return nil, ErrNoSpec
+	}
+	doc := currentPackageDoc(pkg)
+	if doc == "" {
+		return nil, nil
+	}
+
+	llmUserMessage := specContext + "Package doc comment:\n\n```go\n" + doc + "package " + pkg.Name + "\n```\n"
+	options.userMessagef("Checking package doc of %s against SPEC.md...", pkg.Name)
+	responseText, err := completeText(promptFindSpecContradictions(), llmUserMessage, options.BaseOptions)
+	if err != nil {
+		return nil, options.LogWrappedErr("failed to find spec contradictions with LLM", err)
+	}
+
+	var contradictions []SpecContradiction
+	if err := json.Unmarshal([]byte(strings.TrimSpace(unwrapSingleSnippet(responseText))), &contradictions); err != nil {
+		return nil, options.LogWrappedErr("failed to unmarshal LLM response", err)
+	}
+	return contradictions, nil
+}
@@ -0,0 +1,89 @@
+package docubot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/gocodetesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPackageSpec = "# mypkg\n\nmypkg counts widgets. Counts are never negative.\n\n## Public API\n\n```go\nfunc Count() int\n```\n"
+
+func TestGeneratePackageDoc(t *testing.T) {
+	code := dedent(`
+		// Count returns the number of widgets.
+		func Count() int { return 0 }
+	`)
+	response := dedentWithBackticks(`
+		// Package mypkg counts widgets. Counts are never negative, so callers don't need to check.
+		package mypkg
+	`)
+	conv := &responsesCompleter{responses: []string{response}}
+
+	gocodetesting.WithCode(t, code, func(pkg *gocode.Package) {
+		require.NoError(t, os.WriteFile(filepath.Join(pkg.AbsolutePath(), "SPEC.md"), []byte(testPackageSpec), 0644))
+
+		changes, err := GeneratePackageDoc(pkg, PackageDocOptions{BaseOptions: BaseOptions{Completer: conv, ReflowMaxWidth: 60}})
+		require.NoError(t, err)
+		assert.Len(t, changes, 1)
+
+		// The SPEC (without its Public API) and the public API are both sent.
+		if assert.Len(t, conv.convs, 1) {
+			user := conv.convs[0].userMessagesText[0]
+			assert.Contains(t, user, "Counts are never negative.")
+			assert.NotContains(t, user, "## Public API")
+			assert.Contains(t, user, "func Count() int")
+			assert.Contains(t, user, "The package has no package doc comment yet.")
+		}
+
+		got, err := os.ReadFile(filepath.Join(pkg.AbsolutePath(), "doc.go"))
+		require.NoError(t, err)
+		assert.Equal(t, "// Package mypkg counts widgets. Counts are never negative, so\n// callers don't need to check.\npackage mypkg\n", string(got))
+	})
+}
+
+func TestGeneratePackageDoc_NoSpec(t *testing.T) {
+	gocodetesting.WithCode(t, "func Count() int { return 0 }\n", func(pkg *gocode.Package) {
+		_, err := GeneratePackageDoc(pkg, PackageDocOptions{BaseOptions: BaseOptions{Completer: &responsesCompleter{}}})
+		assert.ErrorIs(t, err, ErrNoSpec)
+
+		_, err = FindSpecContradictions(pkg, SpecContradictionsOptions{BaseOptions: BaseOptions{Completer: &responsesCompleter{}}})
+		assert.ErrorIs(t, err, ErrNoSpec)
+	})
+}
+
+func TestFindSpecContradictions(t *testing.T) {
+	files := map[string]string{
+		"doc.go":  "// Package mypkg counts widgets. Counts may be negative.\npackage mypkg\n",
+		"code.go": "package mypkg\n\nfunc Count() int { return 0 }\n",
+	}
+	response := "```json\n" + `[{"doc": "Counts may be negative.", "spec": "Counts are never negative.", "explanation": "The doc allows negative counts; the SPEC forbids them."}]` + "\n```"
+	conv := &responsesCompleter{responses: []string{response}}
+
+	gocodetesting.WithMultiCode(t, files, func(pkg *gocode.Package) {
+		require.NoError(t, os.WriteFile(filepath.Join(pkg.AbsolutePath(), "SPEC.md"), []byte(testPackageSpec), 0644))
+
+		got, err := FindSpecContradictions(pkg, SpecContradictionsOptions{BaseOptions: BaseOptions{Completer: conv}})
+		require.NoError(t, err)
+		assert.Equal(t, []SpecContradiction{{
+			PackageDoc:  "Counts may be negative.",
+			Spec:        "Counts are never negative.",
+			Explanation: "The doc allows negative counts; the SPEC forbids them.",
+		}}, got)
+		if assert.Len(t, conv.convs, 1) {
+			assert.Contains(t, conv.convs[0].userMessagesText[0], "// Package mypkg counts widgets. Counts may be negative.\npackage mypkg\n")
+		}
+	})
+
+	// Without a package doc there is nothing to compare, and no LLM request is made.
+	gocodetesting.WithCode(t, "func Count() int { return 0 }\n", func(pkg *gocode.Package) {
+		require.NoError(t, os.WriteFile(filepath.Join(pkg.AbsolutePath(), "SPEC.md"), []byte(testPackageSpec), 0644))
+		got, err := FindSpecContradictions(pkg, SpecContradictionsOptions{BaseOptions: BaseOptions{Completer: &responsesCompleter{}}})
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
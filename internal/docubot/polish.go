@@ -0,0 +1,107 @@
+package docubot
+
+import (
+	"fmt"
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/gocodecontext"
+	"github.com/codalotl/codalotl/internal/gopackagediff"
+	"github.com/codalotl/codalotl/internal/updatedocs"
+	"strings"
+)
+
+// PolishOptions specifies options for polishing existing Go documentation.
+type PolishOptions struct {
+	// Shared configuration and dependencies (ex: model, completer, logging) for LLM-backed operations.
+	BaseOptions
+}
+
+// Polish uses an LLM to make minimal, style-focused edits to the existing documentation of identifiers in pkg (including identifiers in associated test packages):
+// typos, grammar, awkward wording, and the comment style guidelines. It does not add missing docs or change the meaning of docs; use FindAndFixDocErrors for material
+// errors and ImproveDocs for rewrites.
+//
+// If identifiers is empty, Polish considers all documented identifiers eligible under the default identifier filter; otherwise it processes only the provided set.
+// Undocumented identifiers are skipped without error.
+//
+// Polish edits pkg's source files and returns the documentation-only diff. If the LLM returns a declaration unchanged, no change is recorded, so polishing already
+// polished docs is expected to be a no-op. On error, partial updates may already have been applied.
+func Polish(pkg *gocode.Package, identifiers []string, options PolishOptions) ([]*gopackagediff.Change, error) {
+	var changes []*gopackagediff.Change
+
+	filterOptions := gocode.FilterIdentifiersOptions{OnlyAnyDocs: true}
+	err := gocode.EachPackageWithIdentifiers(pkg, identifiers, filterOptions, gocode.FilterIdentifiersOptionsDocumentedNonAmbiguous, func(p *gocode.Package, ids []string, onlyTests bool) error {
+		pChanges, err := polishForIDs(p, ids, onlyTests, options)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, pChanges...)
+		return nil
+	})
+
+	return changes, err
+}
+
+// polishForIDs polishes the docs of identifiers, which must all be in pkg (not pkg.TestPackage) and all be tests iff onlyTests. It makes one LLM request per context
+// group, applies the returned snippets, and returns the documentation-only diff for identifiers.
+func polishForIDs(pkg *gocode.Package, identifiers []string, onlyTests bool, options PolishOptions) ([]*gopackagediff.Change, error) {
+	specContext, err := specContextForPackage(pkg, nil)
+	if err != nil {
+		return nil, options.LogWrappedErr("polish.spec_context", err)
+	}
+
+	clonedForDiff, err := pkg.Clone()
+	if err != nil {
+		return nil, options.LogWrappedErr("polish.clone.for_diff", err)
+	}
+	defer clonedForDiff.Module.DeleteClone()
+
+	groups, err := gocodecontext.Groups(pkg.Module, pkg, gocodecontext.GroupOptions{IncludePackageDocs: true, IncludeTestFiles: onlyTests, IncludeExternalDeps: true, CountTokens: countTokens})
+	if err != nil {
+		return nil, options.LogWrappedErr("polish.groups", err)
+	}
+	targetGroups := gocodecontext.FilterGroupsForIdentifiers(groups, identifiers)
+	contexts := gocodecontext.NewContextsForIdentifiers(targetGroups, identifiers)
+
+	prompt := promptPolishDocumentation()
+	for ctx, ids := range contexts {
+		idsStr := strings.Join(ids, ", ")
+		llmUserMessage := specContext + ctx.Code() + instructionsForPolish(ids)
+
+		options.userMessagef("> Polishing docs for %d identifiers: %s (%s)", len(ids), idsStr, formatTokenCount(countTokens([]byte(llmUserMessage))))
+
+		responseText, err := completeText(prompt, llmUserMessage, options.BaseOptions)
+		if err != nil {
+			return nil, options.LogWrappedErr("failed to polish documentation with LLM", err, "ids", idsStr)
+		}
+
+		snippets := extractSnippets(responseText)
+		options.userMessagef("< Got %d snippets", len(snippets))
+		if len(snippets) == 0 {
+			continue // Nothing to polish.
+		}
+
+		updatedPkg, _, snippetErrs, err := updatedocs.UpdateDocumentation(pkg, snippets, options.updatedocsOptions(false))
+		if err != nil {
+			return nil, options.LogWrappedErr("polish.update_documentation", err, "ids", idsStr)
+		}
+		logSnippetErrors(options.Logger, "polish snippets", snippetErrs)
+		if updatedPkg != nil {
+			pkg = updatedPkg
+		}
+	}
+
+	docChanges, err := gopackagediff.Diff(clonedForDiff, pkg, identifiers, nil, true)
+	if err != nil {
+		return nil, options.LogWrappedErr("polish.diff", err)
+	}
+	return docChanges, nil
+}
+
+// instructionsForPolish returns the user-message suffix listing the identifiers whose docs should be polished.
+func instructionsForPolish(identifiers []string) string {
+	var b strings.Builder
+	b.WriteString("\nPolish the documentation of these identifiers:\n")
+	for _, id := range identifiers {
+		fmt.Fprintf(&b, "- %s\n", id)
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package docubot
+
+import (
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/gocodetesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolish(t *testing.T) {
+	code := dedent(`
+		// Foo dose something
+		func Foo() {}
+
+		// Bar does something else.
+		func Bar() {}
+
+		func Baz() {}
+	`)
+	polished := dedentWithBackticks(`
+		// Foo does something.
+		func Foo()
+	`)
+	conv := &responsesCompleter{responses: []string{polished, "Nothing to polish."}}
+
+	gocodetesting.WithCode(t, code, func(pkg *gocode.Package) {
+		changes, err := Polish(pkg, nil, PolishOptions{BaseOptions: BaseOptions{Completer: conv}})
+		require.NoError(t, err)
+		if assert.Len(t, changes, 1) {
+			assert.Equal(t, []string{"Foo"}, changes[0].NewIdentifiers)
+			assert.Contains(t, changes[0].NewCode, "// Foo does something.")
+		}
+
+		// Undocumented identifiers are not sent for polishing.
+		if assert.Len(t, conv.convs, 2) {
+			assert.Contains(t, conv.allUserText(), "- Foo\n")
+			assert.Contains(t, conv.allUserText(), "- Bar\n")
+			assert.NotContains(t, conv.allUserText(), "- Baz\n")
+		}
+
+		pkg, err = pkg.Reload()
+		require.NoError(t, err)
+		assert.Contains(t, string(pkg.Files["code.go"].Contents), "// Foo does something.\n")
+	})
+}
+
+func TestPolish_NoSnippetsIsNoOp(t *testing.T) {
+	code := dedent(`
+		// Foo does something.
+		func Foo() {}
+	`)
+	conv := &responsesCompleter{responses: []string{"All comments are already polished."}}
+
+	gocodetesting.WithCode(t, code, func(pkg *gocode.Package) {
+		changes, err := Polish(pkg, []string{"Foo"}, PolishOptions{BaseOptions: BaseOptions{Completer: conv}})
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
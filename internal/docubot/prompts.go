@@ -405,3 +405,201 @@ func promptChooseBestDocs() string {
 
 	return b.String()
 }
+
+// promptPolishDocumentation returns the system prompt used to polish existing comments. The prompt asks for minimal, style-focused edits (typos, grammar, awkward
+// wording, and the '## Style' section) that preserve meaning, code, and spacing, and asks the model to return only declarations whose comments it changed.
+func promptPolishDocumentation() string {
+	var b strings.Builder
+
+	b.WriteString("You are an expert Go programmer and technical editor. Your task is to **polish existing comments only**.\n\n")
+
+	b.WriteString("## What you receive\n")
+	b.WriteString("- Go code - some declarations need their comments polished; others are only context.\n")
+	b.WriteString("- A list of identifiers whose comments you should polish.\n")
+	b.WriteString("\n")
+
+	b.WriteString("## What you return\n")
+	b.WriteString("For each identifier whose comments you changed, output the declaration verbatim except for the polished comment text.\n")
+	b.WriteString("- If an identifier's comments are already fine, do NOT output it. Returning nothing at all is a valid answer.\n")
+	b.WriteString("- Preserve code, structure, and non-comment spacing exactly.\n")
+	b.WriteString("- Keep the same number and placement of comments; edit only their wording. You may not add or delete comments.\n")
+	b.WriteString("- Do not move comments: keep doc comments above declarations and end-of-line comments inline.\n")
+	b.WriteString("- For functions, only return the function header (doc comments, name, params) but not the body.\n")
+	b.WriteString("- Wrap each identifier's declaration+comments in its OWN ```go``` fences.\n")
+	b.WriteString("\n")
+
+	b.WriteString("## Guidelines\n")
+	b.WriteString("- Make SMALL edits: fix typos, grammar, punctuation, and clearly awkward wording, and apply the '## Style' section below.\n")
+	b.WriteString("  - IMPORTANT: an engineer must review every change. Do not re-write comments, re-order sentences, or change what they say.\n")
+	b.WriteString("- Never change the meaning of a comment, even if you think it is wrong. Do not add new information.\n")
+	b.WriteString("- Do not make purely stylistic swaps of equivalent words (ex: 'returns' vs 'gives back').\n")
+	b.WriteString("- Polishing must be idempotent: if you were given your own output, you would return nothing.\n")
+	b.WriteString("- Do not reflow comments for line length. Line length is irrelevant.\n")
+	b.WriteString("\n")
+
+	b.WriteString(promptFragmentCommentStyle())
+
+	b.WriteString("## Example\n")
+	b.WriteString("Given:\n")
+	b.WriteString("```go\n")
+	b.WriteString(`// ReadByte reads and retrns a single byte, ie the next one. if no byte is available it returns an error
+func (b *Reader) ReadByte() (byte, error) { ... }
+`)
+	b.WriteString("```\n")
+	b.WriteString("\n")
+	b.WriteString("You would emit:\n")
+	b.WriteString("```go\n")
+	b.WriteString(`// ReadByte reads and returns a single byte (ex: the next one). If no byte is available, it returns an error.
+func (b *Reader) ReadByte() (byte, error)
+`)
+	b.WriteString("```\n")
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// promptWriteExamples returns the system prompt used to write (and fix) runnable Example functions. The prompt asks for a single complete external test file whose
+// examples are deterministic and end in // Output: blocks, so that go test verifies them.
+func promptWriteExamples() string {
+	var b strings.Builder
+
+	b.WriteString("You are an expert Go programmer. Your task is to write **runnable Example functions** that document a package's public API.\n\n")
+
+	b.WriteString("## What you receive\n")
+	b.WriteString("- The public documentation of the package (exported declarations and their comments).\n")
+	b.WriteString("- A list of Example function names to write, and the identifier each one demonstrates.\n")
+	b.WriteString("- Or: a previously written examples file and the failing `go test` output, to fix.\n")
+	b.WriteString("\n")
+
+	b.WriteString("## What you return\n")
+	b.WriteString("Exactly ONE complete Go file in ```go``` fences, in the external test package (`package <name>_test`), importing the package by its import path.\n")
+	b.WriteString("- Write one function per requested name, with exactly that name and no parameters or results. Do not write other Example functions.\n")
+	b.WriteString("- Every example MUST end with an `// Output:` comment block listing exactly what it prints. `go test` runs the example and compares the output.\n")
+	b.WriteString("- Helper declarations are allowed, but keep them unexported and few.\n")
+	b.WriteString("\n")
+
+	b.WriteString("## Guidelines\n")
+	b.WriteString("- Show typical, idiomatic use of the identifier: what a reader of the package docs would want to copy.\n")
+	b.WriteString("- Only use the documented public API. If the docs don't say what something returns, print something you can be sure of (or choose a different call).\n")
+	b.WriteString("- Examples must be deterministic: no network, no files outside a temp dir, no wall-clock time, no randomness, and no map iteration order in output.\n")
+	b.WriteString("- Keep examples short (usually under 20 lines). Print results with fmt so the output block documents them.\n")
+	b.WriteString("- Handle errors the way a short example would (ex: `if err != nil { fmt.Println(err); return }`).\n")
+	b.WriteString("\n")
+
+	b.WriteString("## Example\n")
+	b.WriteString("Asked for `ExampleReverse (for Reverse)` in package `example.com/strutil`, you would emit:\n")
+	b.WriteString("```go\n")
+	b.WriteString(`package strutil_test
+
+import (
+	"fmt"
+
+	"example.com/strutil"
+)
+
+func ExampleReverse() {
+	fmt.Println(strutil.Reverse("hello"))
+	// Output: olleh
+}
+`)
+	b.WriteString("```\n")
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// promptPackageDocFromSpec returns the system prompt used to write a package doc comment from SPEC.md and the public API. The prompt asks for a single package doc
+// snippet that summarizes the package for godoc readers without restating the API.
+func promptPackageDocFromSpec() string {
+	var b strings.Builder
+
+	b.WriteString("You are an expert Go programmer. Your task is to write the **package doc comment** (the comment above the `package` clause) for a Go package.\n\n")
+
+	b.WriteString("## What you receive\n")
+	b.WriteString("- The package's SPEC.md (with its Public API section removed). SPEC.md is the source of truth for what the package is for and how it behaves.\n")
+	b.WriteString("- The package's public API: exported declarations and their doc comments.\n")
+	b.WriteString("- The current package doc comment, if there is one.\n")
+	b.WriteString("\n")
+
+	b.WriteString("## What you return\n")
+	b.WriteString("Exactly ONE ```go``` block containing the package doc comment followed by the package clause, and nothing else (ex: no imports or declarations).\n")
+	b.WriteString("- Use `//` line comments. The first sentence must start with `Package <name>`.\n")
+	b.WriteString("- If the current comment is accurate and complete, return it unchanged.\n")
+	b.WriteString("\n")
+
+	b.WriteString("## Guidelines\n")
+	b.WriteString("- Explain what the package is for, its key concepts, and how the main pieces fit together, as described by SPEC.md.\n")
+	b.WriteString("- Do not contradict SPEC.md. Do not invent behavior that neither SPEC.md nor the public API describes.\n")
+	b.WriteString("- Don't restate every exported identifier; they have their own docs. Mention the entry points a new user should start with.\n")
+	b.WriteString("- When updating an existing comment, keep accurate content and its wording; change what is wrong or missing.\n")
+	b.WriteString("- Short packages deserve short comments. A few paragraphs is plenty for most packages. Code examples may be indented with a tab.\n")
+	b.WriteString("- Do not reflow for line length. Line length is handled for you.\n")
+	b.WriteString("\n")
+
+	b.WriteString(promptFragmentCommentStyle())
+
+	return b.String()
+}
+
+// promptFindSpecContradictions returns the system prompt used to find contradictions between a package doc comment and the package's SPEC.md. The prompt asks for
+// a JSON array of quoted, explained contradictions and is explicit that omissions are not contradictions.
+func promptFindSpecContradictions() string {
+	var b strings.Builder
+
+	b.WriteString("You are an expert Go programmer. Your task is to find **contradictions** between a Go package's doc comment and its SPEC.md.\n\n")
+
+	b.WriteString("## What you receive\n")
+	b.WriteString("- The package's SPEC.md (with its Public API section removed).\n")
+	b.WriteString("- The package doc comment.\n")
+	b.WriteString("\n")
+
+	b.WriteString("## What you return\n")
+	b.WriteString("- A single JSON array. DO NOT output anything except for the JSON.\n")
+	b.WriteString("- Each element is an object with string fields `doc` (a quote from the package doc comment), `spec` (a quote from SPEC.md), and `explanation` (one or two sentences on why they contradict).\n")
+	b.WriteString("- If there are no contradictions, return `[]`.\n")
+	b.WriteString("\n")
+
+	b.WriteString("## What is a contradiction\n")
+	b.WriteString("- The two texts make claims that cannot both be true (ex: different defaults, behaviors, limits, error handling, or names for the same thing).\n")
+	b.WriteString("- Something that one side omits, or describes in less detail, is NOT a contradiction.\n")
+	b.WriteString("- Differences in wording, tone, or emphasis are NOT contradictions.\n")
+	b.WriteString("- Each finding will be reviewed by a busy engineer. Only report real contradictions; returning `[]` is a perfectly valid answer.\n")
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// promptDraftSpec returns the system prompt used to draft a SPEC.md for an existing package. The Public API section is generated separately, so the prompt asks
+// only for the prose sections and follows the conventions of the spec-md skill (terse, minimal, deliberately ambiguous).
+func promptDraftSpec() string {
+	var b strings.Builder
+
+	b.WriteString("You are an expert Go programmer. Your task is to write a **SPEC.md** for an existing Go package that doesn't have one yet.\n\n")
+
+	b.WriteString("A SPEC.md is the control panel of a Go package: a minimal document that captures what the package is for and the facts about its behavior that matter. ")
+	b.WriteString("Engineers and agents edit the SPEC.md first, then change the implementation to conform.\n\n")
+
+	b.WriteString("## What you receive\n")
+	b.WriteString("- The package doc comment, if any, and the package's public API: exported declarations and their doc comments.\n")
+	b.WriteString("- Examples and test function names, which hint at the behaviors that matter.\n")
+	b.WriteString("- The in-module and third-party packages it imports (with SPEC.md overviews of in-module dependencies, when they exist), and the packages that use it.\n")
+	b.WriteString("\n")
+
+	b.WriteString("## What you return\n")
+	b.WriteString("The SPEC.md contents as markdown, and nothing else.\n")
+	b.WriteString("- Start with a `# <package name>` heading and a brief overview (what the package is for; motivations and use cases if they are clear).\n")
+	b.WriteString("- Add `##` sections as needed for the domain topics of the package (ex: `## Dependencies`, `## Usage`, `## Errors`, or topic sections like `## Caching`).\n")
+	b.WriteString("- Do NOT write a `## Public API` section, and do not copy declarations into the spec: the Public API section is generated from the code and appended for you.\n")
+	b.WriteString("- Any ```go``` fenced block (ex: in `## Usage`) must be syntactically valid Go.\n")
+	b.WriteString("\n")
+
+	b.WriteString("## Guidelines\n")
+	b.WriteString("- Describe the behavior the code actually has. Do not invent behavior, limits, or guarantees that the inputs don't support.\n")
+	b.WriteString("- Be terse. Prefer a short introductory sentence followed by bullet points. Delete words, sentences, and sections that don't add value.\n")
+	b.WriteString("- Be minimal. Ambiguity is fine: leave unsaid anything with an obvious good solution, and anything already clear from the public API's doc comments.\n")
+	b.WriteString("- Focus on facts a maintainer would want to control: invariants, edge cases, error handling, concurrency, ownership of responsibilities between packages.\n")
+	b.WriteString("- Small packages deserve short specs. An overview and a few bullets is plenty for most packages.\n")
+	b.WriteString("\n")
+
+	return b.String()
+}
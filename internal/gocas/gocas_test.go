@@ -203,6 +203,7 @@ func commitGitAt(t *testing.T, dir, msg string, at time.Time) {
 	}, "commit", "-m", msg)
 }
 
+// Traces: CAS-4
 func TestRootDirForBaseDir_EnvOverride(t *testing.T) {
 	baseDir := t.TempDir()
 	casRoot := filepath.Join(t.TempDir(), "cas")
@@ -233,6 +234,7 @@ func TestRootDirForBaseDir_EnvOverrideEmptyErrors(t *testing.T) {
 	require.Error(t, err)
 }
 
+// Traces: CAS-4
 func TestRootDirForBaseDir_GitRootFallback(t *testing.T) {
 	unsetCASDB(t)
 
@@ -0,0 +1,235 @@
+package gocodecontext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+)
+
+// maxDependencySymbolResults caps the symbols rendered into the LLM context by SearchDependencySymbols.
+const maxDependencySymbolResults = 100
+
+// DependencySymbol is an exported identifier defined in a dependency package.
+type DependencySymbol struct {
+	ImportPath string // ImportPath is the import path of the defining package.
+	Identifier string // Identifier is the symbol name; methods are "T.M" (ex: "Client.Do"), regardless of receiver pointer-ness.
+	Kind       string // Kind is one of "func", "method", "type", "const", or "var".
+}
+
+// SearchDependencySymbols searches exported identifiers in the packages of every module in the current module's build list, direct and indirect (dependency internal
+// packages excluded). It identifies the go.mod file by starting at absDir and walking up until it finds a go.mod file. Dependency modules are located with `go list`;
+// modules that aren't present in the module cache or replaced locally are skipped, as are packages that fail to load.
+//
+// search is a Go regexp matched against each identifier (ex: "^New", "(?i)client"). It is also matched against "pkgname.Identifier", so "^yaml\.Unmarshal$" works.
+//
+// It returns the matches sorted by import path and then identifier; a string that can be dropped in as context to an LLM; an error, if any. The returned slice is
+// never truncated, but the LLM context lists at most 100 symbols, with a note on how many were omitted. Canceling ctx stops the search and returns ctx's error.
+func SearchDependencySymbols(ctx context.Context, absDir, search string) ([]DependencySymbol, string, error) {
+	if strings.TrimSpace(search) == "" {
+		return nil, "", fmt.Errorf("search is required")
+	}
+	re, err := regexp.Compile(search)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid search regexp: %w", err)
+	}
+
+	modRoot, _, _, err := moduleRootAndDirectDeps(absDir)
+	if err != nil {
+		return nil, "", err
+	}
+	depMods, err := goListDependencyModules(ctx, modRoot)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var symbols []DependencySymbol
+	modules := map[string]*gocode.Module{}
+	searched := map[string]bool{} // import paths already searched; a module's pattern also matches packages of modules nested under its path.
+	for _, depMod := range depMods {
+		listed, err := goListPackageDirs(ctx, modRoot, depMod+"/...")
+		if err != nil {
+			return nil, "", err
+		}
+		for _, lp := range listed {
+			if err := ctx.Err(); err != nil {
+				return nil, "", err
+			}
+			if lp.moduleDir == "" || searched[lp.importPath] || isInternalToModule(depMod, lp.importPath) {
+				continue
+			}
+			searched[lp.importPath] = true
+			mod := modules[lp.moduleDir]
+			if mod == nil {
+				mod, err = gocode.NewModule(lp.moduleDir)
+				if err != nil {
+					continue
+				}
+				modules[lp.moduleDir] = mod
+			}
+			relDir, err := filepath.Rel(lp.moduleDir, lp.dir)
+			if err != nil {
+				continue
+			}
+			pkg, err := mod.LoadPackageByRelativeDir(filepath.ToSlash(relDir))
+			if err != nil {
+				continue
+			}
+			symbols = append(symbols, matchingPackageSymbols(pkg, lp.importPath, re)...)
+		}
+	}
+
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].ImportPath != symbols[j].ImportPath {
+			return symbols[i].ImportPath < symbols[j].ImportPath
+		}
+		return symbols[i].Identifier < symbols[j].Identifier
+	})
+
+	return symbols, renderDependencySymbols(search, symbols), nil
+}
+
+// matchingPackageSymbols returns pkg's exported identifiers (excluding test files) that match re, either bare or qualified with pkg's name.
+func matchingPackageSymbols(pkg *gocode.Package, importPath string, re *regexp.Regexp) []DependencySymbol {
+	var out []DependencySymbol
+	add := func(id string, kind string) {
+		if !isExportedID(id) {
+			return
+		}
+		if re.MatchString(id) || re.MatchString(pkg.Name+"."+id) {
+			out = append(out, DependencySymbol{ImportPath: importPath, Identifier: id, Kind: kind})
+		}
+	}
+
+	for _, fn := range pkg.FuncSnippets {
+		if fn.Test() {
+			continue
+		}
+		if fn.ReceiverType == "" {
+			add(fn.Name, "func")
+		} else {
+			add(fn.IndirectedReceiverType()+"."+fn.Name, "method")
+		}
+	}
+	for _, ts := range pkg.TypeSnippets {
+		if ts.Test() {
+			continue
+		}
+		for _, id := range ts.Identifiers {
+			add(id, "type")
+		}
+	}
+	for _, vs := range pkg.ValueSnippets {
+		if vs.Test() {
+			continue
+		}
+		kind := "const"
+		if vs.IsVar {
+			kind = "var"
+		}
+		for _, id := range vs.Identifiers {
+			add(id, kind)
+		}
+	}
+	return out
+}
+
+// renderDependencySymbols renders symbols as LLM context, grouped by package.
+func renderDependencySymbols(search string, symbols []DependencySymbol) string {
+	if len(symbols) == 0 {
+		return fmt.Sprintf("No exported dependency symbols match %q.", search)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Exported dependency symbols matching %q (%d):\n", search, len(symbols))
+	lastImportPath := ""
+	for i, sym := range symbols {
+		if i == maxDependencySymbolResults {
+			fmt.Fprintf(&b, "\n... %d more omitted; narrow the search.\n", len(symbols)-i)
+			break
+		}
+		if sym.ImportPath != lastImportPath {
+			b.WriteString("\n" + sym.ImportPath + ":\n")
+			lastImportPath = sym.ImportPath
+		}
+		fmt.Fprintf(&b, "- %s %s\n", sym.Kind, sym.Identifier)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// goListDependencyModules runs `go list -m all` from moduleRoot and returns the paths of the build list's non-main modules that are available locally (in the module
+// cache or replaced by a directory), in go list order. Like goListPackageDirs, it disables workspace mode and uses packageListGoListTimeout (bounded further by ctx).
+func goListDependencyModules(ctx context.Context, moduleRoot string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, packageListGoListTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "go", "list", "-m", "-e", "-f", "{{if not .Main}}{{.Path}}\t{{.Dir}}{{end}}", "all")
+	cmd.Dir = moduleRoot
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(ctxErr, context.Canceled) {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("go list -m all (dir %s) failed: %w: %s", moduleRoot, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var out []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) != 2 || fields[0] == "" || fields[1] == "" {
+			continue
+		}
+		out = append(out, fields[0])
+	}
+	return out, nil
+}
+
+// listedPackage is a package reported by goListPackageDirs.
+type listedPackage struct {
+	importPath string // importPath is the package's import path.
+	dir        string // dir is the package's absolute directory.
+	moduleDir  string // moduleDir is the containing module's absolute root, or "" if none.
+}
+
+// goListPackageDirs runs go list for pattern from moduleRoot and returns each matched package's import path, directory, and module directory, in go list order.
+// Like goListImportPaths, it disables workspace mode and uses packageListGoListTimeout (bounded further by ctx).
+func goListPackageDirs(ctx context.Context, moduleRoot string, pattern string) ([]listedPackage, error) {
+	ctx, cancel := context.WithTimeout(ctx, packageListGoListTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "go", "list", "-e", "-f", "{{.ImportPath}}\t{{.Dir}}\t{{with .Module}}{{.Dir}}{{end}}", pattern)
+	cmd.Dir = moduleRoot
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(ctxErr, context.Canceled) {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("go list %s (dir %s) failed: %w: %s", pattern, moduleRoot, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var out []listedPackage
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) != 3 || fields[0] == "" || fields[1] == "" {
+			continue
+		}
+		out = append(out, listedPackage{importPath: fields[0], dir: fields[1], moduleDir: fields[2]})
+	}
+	return out, nil
+}
@@ -0,0 +1,86 @@
+package gocodecontext
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchDependencySymbols(t *testing.T) {
+	root := t.TempDir()
+	mainDir := filepath.Join(root, "main")
+	depDir := filepath.Join(root, "dep")
+
+	writeFile(t, filepath.Join(depDir, "client", "client.go"), strings.Join([]string{
+		"package client",
+		"",
+		"type Client struct{}",
+		"",
+		"func NewClient() *Client { return nil }",
+		"",
+		"func (c *Client) Close() error { return nil }",
+		"",
+		"func newHelper() {}",
+		"",
+		"const DefaultClientTimeout = 5",
+		"",
+	}, "\n"))
+	writeFile(t, filepath.Join(depDir, "client", "client_test.go"), "package client\n\nfunc ClientFixture() {}\n")
+	writeFile(t, filepath.Join(depDir, "internal", "hidden", "hidden.go"), "package hidden\n\nfunc NewClientHidden() {}\n")
+
+	// dep requires indirect, which main only requires indirectly.
+	indirectDir := filepath.Join(root, "indirect")
+	writeFile(t, filepath.Join(indirectDir, "go.mod"), "module example.com/indirect\n\ngo 1.20\n")
+	writeFile(t, filepath.Join(indirectDir, "pool", "pool.go"), "package pool\n\nfunc NewClientPool() {}\n")
+	writeFile(t, filepath.Join(depDir, "go.mod"), "module example.com/dep\n\ngo 1.20\n\nrequire example.com/indirect v0.0.0\n")
+
+	writeFile(t, filepath.Join(mainDir, "go.mod"), strings.Join([]string{
+		"module example.com/main",
+		"",
+		"go 1.20",
+		"",
+		"require example.com/dep v0.0.0",
+		"",
+		"require example.com/indirect v0.0.0 // indirect",
+		"",
+		"replace example.com/dep => ../dep",
+		"",
+		"replace example.com/indirect => ../indirect",
+		"",
+	}, "\n"))
+	writeFile(t, filepath.Join(mainDir, "main.go"), "package main\n\nfunc NewClientLocal() {}\n\nfunc main() {}\n")
+
+	syms, ctx, err := SearchDependencySymbols(context.Background(), mainDir, "Client")
+	require.NoError(t, err)
+	assert.Equal(t, []DependencySymbol{
+		{ImportPath: "example.com/dep/client", Identifier: "Client", Kind: "type"},
+		{ImportPath: "example.com/dep/client", Identifier: "Client.Close", Kind: "method"},
+		{ImportPath: "example.com/dep/client", Identifier: "DefaultClientTimeout", Kind: "const"},
+		{ImportPath: "example.com/dep/client", Identifier: "NewClient", Kind: "func"},
+		{ImportPath: "example.com/indirect/pool", Identifier: "NewClientPool", Kind: "func"},
+	}, syms)
+	assert.Contains(t, ctx, "example.com/dep/client:\n- type Client\n")
+	assert.Contains(t, ctx, "- func NewClient")
+
+	syms, _, err = SearchDependencySymbols(context.Background(), mainDir, `^client\.New`)
+	require.NoError(t, err)
+	require.Len(t, syms, 1)
+	assert.Equal(t, "NewClient", syms[0].Identifier)
+
+	syms, ctx, err = SearchDependencySymbols(context.Background(), mainDir, "Nope")
+	require.NoError(t, err)
+	assert.Empty(t, syms)
+	assert.Contains(t, ctx, "No exported dependency symbols")
+
+	_, _, err = SearchDependencySymbols(context.Background(), mainDir, "(")
+	assert.Error(t, err)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = SearchDependencySymbols(canceled, mainDir, "Client")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,118 @@
+package gocodecontext
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+)
+
+// PackageExamples returns the testable examples (`func ExampleXxx()` in _test.go files) for pkg, including examples in pkg's black-box test package:
+//   - Grouped by file (with file comment markers, ex: `// example_test.go:`), files sorted by name.
+//   - Each example is its full source (docs, signature, and body), in the order it appears in the file.
+//
+// If identifiers are present, examples are limited to those documenting the identifiers (using go doc's naming rules: ExampleF, ExampleT, ExampleT_M, each with
+// an optional lowercase _suffix). Package examples (Example, Example_suffix) are only included when identifiers are absent. Identifiers use the same forms as
+// PublicPackageDocumentation; a type identifier also matches examples of its methods.
+//
+// Returns "" if there are no matching examples, and an error if pkg is a test package.
+func PackageExamples(pkg *gocode.Package, identifiers ...string) (string, error) {
+	if pkg == nil {
+		return "", fmt.Errorf("nil package")
+	}
+	if pkg.IsTestPackage() {
+		return "", fmt.Errorf("cannot list examples for test package %q", pkg.ImportPath)
+	}
+
+	wanted := make(map[string]struct{}, len(identifiers))
+	for _, id := range identifiers {
+		id = strings.TrimPrefix(strings.TrimSpace(id), "*")
+		if id != "" {
+			wanted[id] = struct{}{}
+		}
+	}
+
+	byFile := map[string][]*gocode.FuncSnippet{}
+	collect := func(p *gocode.Package) {
+		for _, fn := range p.FuncSnippets {
+			if !fn.IsTestFunc() || !strings.HasPrefix(fn.Name, "Example") {
+				continue
+			}
+			if len(wanted) > 0 && !exampleMatches(exampleTarget(fn.Name), wanted) {
+				continue
+			}
+			byFile[fn.FileName] = append(byFile[fn.FileName], fn)
+		}
+	}
+	collect(pkg)
+	if pkg.TestPackage != nil {
+		collect(pkg.TestPackage)
+	}
+	if len(byFile) == 0 {
+		return "", nil
+	}
+
+	fileNames := make([]string, 0, len(byFile))
+	for fileName := range byFile {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	var b strings.Builder
+	for i, fileName := range fileNames {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("// " + fileName + ":\n\n")
+		for j, fn := range byFile[fileName] {
+			if j > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(ensureNewline(string(fn.FullFunc)))
+		}
+	}
+	return b.String(), nil
+}
+
+// exampleTarget returns the identifier documented by the example function named name, or "" for package examples. Ex: "ExampleT_M_second" -> "T.M"; "ExampleF"
+// -> "F"; "Example_foo" -> "".
+func exampleTarget(name string) string {
+	rest := strings.TrimPrefix(name, "Example")
+	if rest == "" || strings.HasPrefix(rest, "_") {
+		return ""
+	}
+	parts := strings.Split(rest, "_")
+	if last := parts[len(parts)-1]; len(parts) > 1 && last != "" && !unicode.IsUpper(rune(last[0])) {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) > 2 {
+		return ""
+	}
+	return strings.Join(parts, ".")
+}
+
+// exampleMatches reports whether an example documenting target should be included for the wanted identifiers.
+func exampleMatches(target string, wanted map[string]struct{}) bool {
+	if target == "" {
+		return false
+	}
+	if _, ok := wanted[target]; ok {
+		return true
+	}
+	typeName, _, isMethod := strings.Cut(target, ".")
+	if !isMethod {
+		return false
+	}
+	_, ok := wanted[typeName]
+	return ok
+}
+
+// ensureNewline returns s with a trailing newline.
+func ensureNewline(s string) string {
+	if strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
@@ -0,0 +1,88 @@
+package gocodecontext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/gocodetesting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageExamples(t *testing.T) {
+	code := map[string]string{
+		"client.go": gocodetesting.Dedent(`
+			package mypkg
+
+			type Client struct{}
+
+			func (c *Client) Do() {}
+
+			func New() *Client { return &Client{} }
+		`),
+		"example_test.go": gocodetesting.Dedent(`
+			package mypkg_test
+
+			import "fmt"
+
+			func Example() {
+				fmt.Println("pkg")
+			}
+
+			// ExampleNew shows construction.
+			func ExampleNew() {
+				fmt.Println("new")
+			}
+
+			func ExampleClient_Do_retry() {
+				fmt.Println("do")
+			}
+		`),
+		"internal_test.go": gocodetesting.Dedent(`
+			package mypkg
+
+			import "testing"
+
+			func TestNew(t *testing.T) {}
+
+			func ExampleClient() {}
+		`),
+	}
+
+	gocodetesting.WithMultiCode(t, code, func(pkg *gocode.Package) {
+		all, err := PackageExamples(pkg)
+		require.NoError(t, err)
+		assert.Contains(t, all, "// example_test.go:\n\nfunc Example() {\n\tfmt.Println(\"pkg\")\n}\n")
+		assert.Contains(t, all, "// ExampleNew shows construction.\nfunc ExampleNew() {")
+		assert.Contains(t, all, "// internal_test.go:\n\nfunc ExampleClient() {}\n")
+		assert.NotContains(t, all, "TestNew")
+		assert.Less(t, strings.Index(all, "example_test.go"), strings.Index(all, "internal_test.go"))
+
+		forType, err := PackageExamples(pkg, "*Client")
+		require.NoError(t, err)
+		assert.Contains(t, forType, "func ExampleClient() {}")
+		assert.Contains(t, forType, "func ExampleClient_Do_retry() {")
+		assert.NotContains(t, forType, "ExampleNew")
+		assert.NotContains(t, forType, "func Example() {")
+
+		forMethod, err := PackageExamples(pkg, "Client.Do")
+		require.NoError(t, err)
+		assert.Contains(t, forMethod, "ExampleClient_Do_retry")
+		assert.NotContains(t, forMethod, "func ExampleClient() {}")
+
+		none, err := PackageExamples(pkg, "Missing")
+		require.NoError(t, err)
+		assert.Equal(t, "", none)
+	})
+}
+
+func TestExampleTarget(t *testing.T) {
+	assert.Equal(t, "", exampleTarget("Example"))
+	assert.Equal(t, "", exampleTarget("Example_second"))
+	assert.Equal(t, "F", exampleTarget("ExampleF"))
+	assert.Equal(t, "F", exampleTarget("ExampleF_second"))
+	assert.Equal(t, "T.M", exampleTarget("ExampleT_M"))
+	assert.Equal(t, "T.M", exampleTarget("ExampleT_M_second"))
+}
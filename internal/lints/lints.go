@@ -7,12 +7,15 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/codalotl/codalotl/internal/codeunit"
 	"github.com/codalotl/codalotl/internal/q/cmdrunner"
 	"github.com/codalotl/codalotl/internal/specmd"
 	"github.com/codalotl/codalotl/internal/updatedocs"
@@ -53,6 +56,7 @@ const (
 	stepIDGolangciLint = "golangci-lint"
 	stepIDSpecDiff     = "spec-diff"
 	stepIDSpecFmt      = "spec-fmt"
+	stepIDSpecCoverage = "spec-coverage"
 )
 
 // ConfigMode represents the configuration mode of specifying steps: do we extend existing steps, or replace them all with the given steps?
@@ -106,6 +110,10 @@ type Step struct {
 
 	// Fix is the command preferred when the step runs in fix mode. If Fix is nil, fix mode falls back to Check so check-only lints can still run.
 	Fix *cmdrunner.Command `json:"fix,omitempty"`
+
+	// Parallel allows the step to run concurrently with adjacent Parallel steps. Output order is unaffected. Steps that write files (ex: fix commands that format code)
+	// should only be marked Parallel if they don't touch the same files as their neighbors.
+	Parallel bool `json:"parallel,omitempty"`
 }
 
 // These constants define default lint options, status messages, and cmdrunner template and input names.
@@ -137,6 +145,9 @@ const (
 
 const reflowCheckInstructions = "never manually fix these unless asked; fixing is automatic on apply_patch"
 
+// specCoverageInstructions follow the uncovered identifiers in spec-coverage output.
+const specCoverageInstructions = "These exported identifiers are not declared in the SPEC.md Public API. If they are part of the package's intended API, add them to SPEC.md; otherwise, consider unexporting them."
+
 func normalizeReflowWidth(reflowWidth int) int {
 	if reflowWidth <= 0 {
 		return defaultReflowWidth
@@ -156,7 +167,12 @@ func defaultSteps(reflowWidth int) []Step {
 	gofmt, _ := preconfiguredStep(stepIDGofmt, reflowWidth)
 	specFmt, _ := preconfiguredStep(stepIDSpecFmt, reflowWidth)
 	specDiff, _ := preconfiguredStep(stepIDSpecDiff, reflowWidth)
-	return []Step{gofmt, specFmt, specDiff}
+
+	// spec-coverage is opt-in: it is present but runs in no situations until
+	// config references it by ID.
+	specCoverage, _ := preconfiguredStep(stepIDSpecCoverage, reflowWidth)
+	specCoverage.Situations = []Situation{}
+	return []Step{gofmt, specFmt, specDiff, specCoverage}
 }
 
 // preconfiguredStep returns the built-in lint step for id. It returns ok=false for unknown IDs. A non-positive reflowWidth uses defaultReflowWidth.
@@ -212,6 +228,7 @@ func preconfiguredStep(id string, reflowWidth int) (Step, bool) {
 		// staticcheck has no built-in fix mode. In fix situations we still run it in
 		// check mode (selectCommand falls back to Check when Fix is nil).
 		staticcheckCheck := newPreconfiguredCommand("staticcheck", []string{"./" + templateRelativePackageDir}, false)
+		staticcheckCheck.ProblemMatchers = []cmdrunner.ProblemMatcher{{Preset: "staticcheck"}}
 
 		return Step{
 			ID:    stepIDStaticcheck,
@@ -276,6 +293,22 @@ func preconfiguredStep(id string, reflowWidth int) (Step, bool) {
 			Situations: []Situation{SituationPatch, SituationFix},
 			Fix:        specFmtFix,
 		}, true
+	case stepIDSpecCoverage:
+		// ID == "spec-coverage" is special-cased during execution (it is NOT executed
+		// as a subprocess), like spec-diff.
+		specCoverageCheck := newPreconfiguredCommand("codalotl",
+			[]string{
+				"spec",
+				"coverage",
+				templateRelativePackageDir,
+			},
+			true,
+		)
+		return Step{
+			ID:         stepIDSpecCoverage,
+			Situations: []Situation{SituationTests, SituationFix},
+			Check:      specCoverageCheck,
+		}, true
 	default:
 		return Step{}, false
 	}
@@ -311,12 +344,12 @@ func ResolveSteps(cfg *Lints, reflowWidth int) ([]Step, error) {
 	switch mode {
 	case ConfigModeExtend:
 		steps = append([]Step(nil), defaultSteps(reflowWidth)...)
-		if err := appendStepsUnique(&steps, cfg.Steps, reflowWidth); err != nil {
+		if err := appendStepsUnique(&steps, cfg.Steps, reflowWidth, true); err != nil {
 			return nil, err
 		}
 	case ConfigModeReplace:
 		steps = nil
-		if err := appendStepsUnique(&steps, cfg.Steps, reflowWidth); err != nil {
+		if err := appendStepsUnique(&steps, cfg.Steps, reflowWidth, false); err != nil {
 			return nil, err
 		}
 	default:
@@ -347,15 +380,21 @@ func ResolveSteps(cfg *Lints, reflowWidth int) ([]Step, error) {
 	return normalizeStepWidths(steps, reflowWidth)
 }
 
-// appendStepsUnique canonicalizes, validates, and appends src steps to dst without duplicating non-empty IDs. Existing dst IDs are treated as already used, empty
-// IDs may repeat, and reflowWidth is used when expanding preconfigured ID-only steps. If an error occurs after an append, dst is not rolled back.
-func appendStepsUnique(dst *[]Step, src []Step, reflowWidth int) error {
+// appendStepsUnique canonicalizes, validates, and appends src steps to dst without duplicating non-empty IDs. Existing dst IDs are treated as already used, except
+// that, when dstIsDefaults is true (dst holds the built-in default steps), one src step replaces (in place) a default step that runs in no situations, so opt-in
+// defaults like spec-coverage can be enabled by ID. User steps are never replaceable, even with empty situations. Empty IDs may repeat, and reflowWidth is used
+// when expanding preconfigured ID-only steps. If an error occurs after an append, dst is not rolled back.
+func appendStepsUnique(dst *[]Step, src []Step, reflowWidth int, dstIsDefaults bool) error {
 	seen := make(map[string]struct{}, len(*dst)+len(src))
-	for _, s := range *dst {
+	optIn := make(map[string]int)
+	for i, s := range *dst {
 		if s.ID == "" {
 			continue
 		}
 		seen[s.ID] = struct{}{}
+		if dstIsDefaults && s.Situations != nil && len(s.Situations) == 0 {
+			optIn[s.ID] = i
+		}
 	}
 
 	for _, s := range src {
@@ -364,6 +403,11 @@ func appendStepsUnique(dst *[]Step, src []Step, reflowWidth int) error {
 			return err
 		}
 		if s.ID != "" {
+			if i, ok := optIn[s.ID]; ok {
+				delete(optIn, s.ID)
+				(*dst)[i] = s
+				continue
+			}
 			if _, ok := seen[s.ID]; ok {
 				return fmt.Errorf("duplicate lint step id %q", s.ID)
 			}
@@ -397,6 +441,9 @@ func canonicalizeStep(s Step, reflowWidth int) Step {
 	if s.Active != nil {
 		pre.Active = s.Active
 	}
+	if s.Parallel {
+		pre.Parallel = true
+	}
 	return pre
 }
 
@@ -470,6 +517,20 @@ func validateCommand(stepID string, which string, c *cmdrunner.Command) error {
 		}
 		return fmt.Errorf("lint step %q: %s command: attrs must have even length, got %d", stepID, which, len(c.Attrs))
 	}
+	var problem string
+	if len(c.DependsOn) > 0 {
+		problem = "dependson is not supported; order steps instead"
+	} else if c.Timeout != "" {
+		if d, err := time.ParseDuration(strings.TrimSpace(c.Timeout)); err != nil || d <= 0 {
+			problem = fmt.Sprintf("timeout must be a positive duration (ex: \"30s\"), got %q", c.Timeout)
+		}
+	}
+	if problem != "" {
+		if stepID == "" {
+			return fmt.Errorf("lint step: %s command: %s", which, problem)
+		}
+		return fmt.Errorf("lint step %q: %s command: %s", stepID, which, problem)
+	}
 	return nil
 }
 
@@ -641,6 +702,7 @@ func parseWidthFlag(args []string) (width int, idx int, ok bool, err error) {
 //   - Run does not stop early: it attempts to execute all steps, even if earlier steps report failures.
 //   - Steps that are inactive are not run, and do not contribute towards the returned XML (it's as if they weren't in steps).
 //   - Command failures are reflected in the XML. Hard errors (invalid config, templating failures, internal errors) return a Go error.
+//   - Consecutive Parallel steps run concurrently. The XML still lists results in step order.
 func Run(ctx context.Context, sandboxDir string, targetPkgAbsDir string, steps []Step, situation Situation) (string, error) {
 	if sandboxDir == "" {
 		return "", errors.New("sandboxDir is required")
@@ -674,76 +736,132 @@ func Run(ctx context.Context, sandboxDir string, targetPkgAbsDir string, steps [
 		return "", err
 	}
 
+	// Consecutive Parallel steps form a batch that runs concurrently. Results are collected per step, so output order matches step order either way.
+	stepResults := make([][]cmdrunner.CommandResult, len(selected))
+	for i := 0; i < len(selected); {
+		j := i + 1
+		if selected[i].Parallel {
+			for j < len(selected) && selected[j].Parallel {
+				j++
+			}
+		}
+		if err := runStepBatch(ctx, sandboxDir, targetPkgAbsDir, moduleDir, relativePackageDir, selected[i:j], act, stepResults[i:j]); err != nil {
+			return "", err
+		}
+		i = j
+	}
+
 	var all cmdrunner.Result
+	for _, rs := range stepResults {
+		all.Results = append(all.Results, rs...)
+	}
 
-	for _, s := range selected {
-		if !stepActive(ctx, sandboxDir, targetPkgAbsDir, moduleDir, relativePackageDir, s) {
-			continue
-		}
+	if len(all.Results) == 0 {
+		return noLintersStatusXML, nil
+	}
+	return all.ToXML("lint-status"), nil
+}
 
-		if s.ID == stepIDReflow {
-			c, modeAttr, dryRun, err := selectCommand(s, act)
-			if err != nil {
-				return "", err
-			}
-			cr, crErr := runReflow(moduleDir, relativePackageDir, targetPkgAbsDir, c, modeAttr, dryRun)
-			if crErr != nil {
-				return "", crErr
-			}
-			all.Results = append(all.Results, cr)
-			continue
+// runStepBatch runs steps, storing each step's results at the same index of results. A batch of more than one step runs concurrently; if any steps return errors,
+// the error of the earliest such step is returned. Steps get their own goroutines, not one cmdrunner.Runner with SetMaxParallel, because a step's active check
+// and in-process steps (reflow, spec-*) aren't runner commands.
+func runStepBatch(ctx context.Context, sandboxDir string, targetPkgAbsDir string, moduleDir string, relativePackageDir string, steps []Step, act action, results [][]cmdrunner.CommandResult) error {
+	if len(steps) == 1 {
+		var err error
+		results[0], err = runStep(ctx, sandboxDir, targetPkgAbsDir, moduleDir, relativePackageDir, steps[0], act)
+		return err
+	}
+
+	errs := make([]error, len(steps))
+	var wg sync.WaitGroup
+	for i, s := range steps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = runStep(ctx, sandboxDir, targetPkgAbsDir, moduleDir, relativePackageDir, s, act)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		if s.ID == stepIDSpecDiff {
-			// This lint is always rendered as a check-only step, even in fix situations.
-			// It's executed in-process so we can use internal/specmd directly and keep
-			// output uniform (cmdrunner-style).
-			cr := runSpecDiff(relativePackageDir, targetPkgAbsDir)
-			all.Results = append(all.Results, cr)
-			continue
+// runStep runs a single step (after checking that it's active) and returns its command results. Inactive steps return no results.
+func runStep(ctx context.Context, sandboxDir string, targetPkgAbsDir string, moduleDir string, relativePackageDir string, s Step, act action) ([]cmdrunner.CommandResult, error) {
+	if !stepActive(ctx, sandboxDir, targetPkgAbsDir, moduleDir, relativePackageDir, s) {
+		return nil, nil
+	}
+
+	if s.ID == stepIDReflow {
+		c, modeAttr, dryRun, err := selectCommand(s, act)
+		if err != nil {
+			return nil, err
 		}
-		if s.ID == stepIDSpecFmt {
-			// This lint is fix-only and is executed in-process so we can format SPEC.md
-			// via internal/specmd without spawning a subprocess.
-			c, _, _, err := selectCommand(s, act)
-			if err != nil {
-				return "", err
-			}
-			reflowWidth, _, hasWidth, err := parseWidthFlag(c.Args)
-			if err != nil {
-				return "", fmt.Errorf("lint step %q: fix command: %w", s.ID, err)
-			}
-			if !hasWidth {
-				reflowWidth = 0
-			}
-			cr := runSpecFmt(moduleDir, relativePackageDir, targetPkgAbsDir, reflowWidth)
-			all.Results = append(all.Results, cr)
-			continue
+		cr, crErr := runReflow(moduleDir, relativePackageDir, targetPkgAbsDir, c, modeAttr, dryRun)
+		if crErr != nil {
+			return nil, crErr
 		}
+		return []cmdrunner.CommandResult{cr}, nil
+	}
 
-		c, modeAttr, _, err := selectCommand(s, act)
+	if s.ID == stepIDSpecDiff {
+		// This lint is always rendered as a check-only step, even in fix situations.
+		// It's executed in-process so we can use internal/specmd directly and keep
+		// output uniform (cmdrunner-style).
+		cr := runSpecDiff(relativePackageDir, targetPkgAbsDir)
+		return []cmdrunner.CommandResult{cr}, nil
+	}
+	if s.ID == stepIDSpecCoverage {
+		// Like spec-diff, this lint is check-only and executed in-process.
+		cr := runSpecCoverage(relativePackageDir, targetPkgAbsDir)
+		return []cmdrunner.CommandResult{cr}, nil
+	}
+	if s.ID == stepIDSpecFmt {
+		// This lint is fix-only and is executed in-process so we can format SPEC.md
+		// via internal/specmd without spawning a subprocess.
+		c, _, _, err := selectCommand(s, act)
 		if err != nil {
-			return "", err
+			return nil, err
+		}
+		reflowWidth, _, hasWidth, err := parseWidthFlag(c.Args)
+		if err != nil {
+			return nil, fmt.Errorf("lint step %q: fix command: %w", s.ID, err)
+		}
+		if !hasWidth {
+			reflowWidth = 0
 		}
+		cr := runSpecFmt(moduleDir, relativePackageDir, targetPkgAbsDir, reflowWidth)
+		return []cmdrunner.CommandResult{cr}, nil
+	}
 
-		runner := newLintRunner()
-		cmd := withModeAttr(*c, modeAttr)
-		runner.AddCommand(cmd)
+	c, modeAttr, _, err := selectCommand(s, act)
+	if err != nil {
+		return nil, err
+	}
 
-		r, runErr := runner.Run(ctx, sandboxDir, lintRunnerInputs(targetPkgAbsDir, moduleDir, relativePackageDir))
-		if runErr != nil {
-			return "", runErr
+	runner := newLintRunner()
+	cmd := withModeAttr(*c, modeAttr)
+	runner.AddCommand(cmd)
+	if len(cmd.ProblemMatchers) > 0 {
+		// Findings are clipped to the package's code unit so the LLM isn't shown issues it can't act on.
+		if unit, err := codeunit.DefaultGoCodeUnit(targetPkgAbsDir); err == nil {
+			runner.SetFindingFilter(unit.Includes)
 		}
-		all.Results = append(all.Results, r.Results...)
 	}
 
-	if len(all.Results) == 0 {
-		return noLintersStatusXML, nil
+	r, runErr := runner.Run(ctx, sandboxDir, lintRunnerInputs(targetPkgAbsDir, moduleDir, relativePackageDir))
+	if runErr != nil {
+		return nil, runErr
 	}
-	return all.ToXML("lint-status"), nil
+	return r.Results, nil
 }
 
-// stepActive reports whether s should run for the package. The spec-diff and spec-fmt steps are active only when the package contains SPEC.md, except that unexpected
+// stepActive reports whether s should run for the package. The spec-diff, spec-fmt, and spec-coverage steps are active only when the package contains SPEC.md, except that unexpected
 // stat errors are treated as active. If s has no Active command, it is active. Otherwise the Active command is run and the step is inactive only when that command
 // exits with code 0, produces no non-whitespace output, and has no exec error.
 func stepActive(ctx context.Context, sandboxDir string, targetPkgAbsDir string, moduleDir string, relativePackageDir string, s Step) bool {
@@ -777,7 +895,7 @@ func stepActive(ctx context.Context, sandboxDir string, targetPkgAbsDir string,
 }
 
 func stepRequiresSpecMD(id string) bool {
-	return id == stepIDSpecDiff || id == stepIDSpecFmt
+	return id == stepIDSpecDiff || id == stepIDSpecFmt || id == stepIDSpecCoverage
 }
 
 func newLintRunner() *cmdrunner.Runner {
@@ -983,6 +1101,53 @@ func runSpecDiff(relativePackageDir string, targetPkgAbsDir string) cmdrunner.Co
 	return cr
 }
 
+// runSpecCoverage runs the in-process spec-coverage lint for targetPkgAbsDir and returns a cmdrunner-style check result. The result fails when SPEC.md cannot be
+// read, coverage cannot be computed, or any exported identifier is missing from the SPEC.md Public API; uncovered identifiers are followed by instructions.
+func runSpecCoverage(relativePackageDir string, targetPkgAbsDir string) cmdrunner.CommandResult {
+	start := time.Now()
+
+	specPath := filepath.Join(targetPkgAbsDir, "SPEC.md")
+	s, execErr := specmd.Read(specPath)
+
+	var out bytes.Buffer
+	var coverage specmd.APICoverage
+	if execErr == nil {
+		coverage, execErr = s.APICoverage()
+	}
+	if execErr == nil && len(coverage.Uncovered) > 0 {
+		execErr = specmd.FormatUncoveredAPIs(coverage.Uncovered, &out)
+	}
+
+	outcome := cmdrunner.OutcomeSuccess
+	if execErr != nil || len(coverage.Uncovered) > 0 {
+		outcome = cmdrunner.OutcomeFailed
+	}
+
+	output := strings.TrimRight(out.String(), "\n")
+	if len(coverage.Uncovered) > 0 {
+		output += "\n\n" + specCoverageInstructions
+	}
+	if execErr != nil {
+		// As with spec-diff, make sure errors are visible rather than rendering MessageIfNoOutput.
+		if output != "" {
+			output += "\n\n"
+		}
+		output += "Error: " + path.Join(relativePackageDir, "SPEC.md") + ": " + execErr.Error()
+	}
+
+	return cmdrunner.CommandResult{
+		Command:           "codalotl",
+		Args:              []string{"spec", "coverage", relativePackageDir},
+		Output:            output,
+		MessageIfNoOutput: noIssuesFound,
+		Attrs:             []string{"mode", "check"},
+		ExecStatus:        cmdrunner.ExecStatusCompleted,
+		ExecError:         execErr,
+		Outcome:           outcome,
+		Duration:          time.Since(start),
+	}
+}
+
 // runSpecFmt formats the package SPEC.md in process and returns a cmdrunner-style result. relativePackageDir is used in the rendered command, moduleDir is used
 // to render changed and error paths, and reflowWidth is passed to specmd formatting. The result fails on read or format errors.
 func runSpecFmt(moduleDir string, relativePackageDir string, targetPkgAbsDir string, reflowWidth int) cmdrunner.CommandResult {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"testing"
@@ -16,7 +17,7 @@ import (
 func TestResolveSteps_Defaults(t *testing.T) {
 	steps, err := ResolveSteps(nil, 0)
 	require.NoError(t, err)
-	require.Len(t, steps, 3)
+	require.Len(t, steps, 4)
 	require.Equal(t, "gofmt", steps[0].ID)
 	require.Equal(t, "spec-fmt", steps[1].ID)
 	require.Equal(t, []Situation{SituationPatch, SituationFix}, steps[1].Situations)
@@ -33,6 +34,12 @@ func TestResolveSteps_Defaults(t *testing.T) {
 
 	require.Equal(t, "{{ .moduleDir }}", steps[0].Check.CWD)
 	require.Contains(t, steps[0].Check.Args, "{{ .relativePackageDir }}")
+
+	// spec-coverage is opt-in: present, but enabled in no situations.
+	require.Equal(t, "spec-coverage", steps[3].ID)
+	require.NotNil(t, steps[3].Situations)
+	require.Empty(t, steps[3].Situations)
+	require.NotNil(t, steps[3].Check)
 }
 
 func TestDefaultSteps(t *testing.T) {
@@ -140,6 +147,28 @@ func TestResolveSteps_ValidationErrors(t *testing.T) {
 			},
 			wantErr: `lint step "unknown-situation": unknown situation "bogus"`,
 		},
+		{
+			name: "invalid timeout",
+			cfg: Lints{
+				Mode: ConfigModeReplace,
+				Steps: []Step{{
+					ID:    "invalid-timeout",
+					Check: &cmdrunner.Command{Command: "anything", Timeout: "forever"},
+				}},
+			},
+			wantErr: `lint step "invalid-timeout": check command: timeout must be a positive duration`,
+		},
+		{
+			name: "dependson",
+			cfg: Lints{
+				Mode: ConfigModeReplace,
+				Steps: []Step{{
+					ID:    "dependson",
+					Check: &cmdrunner.Command{Command: "anything", DependsOn: []string{"gofmt"}},
+				}},
+			},
+			wantErr: `lint step "dependson": check command: dependson is not supported`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -166,7 +195,7 @@ func TestResolveSteps_Disable(t *testing.T) {
 	}
 	steps, err := ResolveSteps(cfg, 120)
 	require.NoError(t, err)
-	require.Len(t, steps, 2)
+	require.Len(t, steps, 3)
 	require.Equal(t, "spec-fmt", steps[0].ID)
 	require.Equal(t, "spec-diff", steps[1].ID)
 }
@@ -181,12 +210,12 @@ func TestResolveSteps_ExtendCanAddPreconfiguredReflowByID(t *testing.T) {
 
 	steps, err := ResolveSteps(cfg, 123)
 	require.NoError(t, err)
-	require.Len(t, steps, 4)
+	require.Len(t, steps, 5)
 	require.Equal(t, "gofmt", steps[0].ID)
 	require.Equal(t, "spec-fmt", steps[1].ID)
 	require.Equal(t, "spec-diff", steps[2].ID)
-	require.Equal(t, "reflow", steps[3].ID)
-	reflow := steps[3]
+	require.Equal(t, "reflow", steps[4].ID)
+	reflow := steps[4]
 
 	require.Equal(t, "{{ .moduleDir }}", reflow.Check.CWD)
 	require.Contains(t, reflow.Check.Args, "{{ .relativePackageDir }}")
@@ -209,9 +238,51 @@ func TestResolveSteps_ExtendAllowsOverridingSituationsForPreconfiguredStep(t *te
 
 	steps, err := ResolveSteps(cfg, 123)
 	require.NoError(t, err)
+	require.Len(t, steps, 5)
+	require.Equal(t, "reflow", steps[4].ID)
+	require.Equal(t, []Situation{SituationFix}, steps[4].Situations)
+}
+
+func TestResolveSteps_ExtendEnablesOptInDefaultByID(t *testing.T) {
+	cfg := &Lints{
+		Mode: ConfigModeExtend,
+		Steps: []Step{
+			{ID: "spec-coverage"},
+		},
+	}
+
+	steps, err := ResolveSteps(cfg, 120)
+	require.NoError(t, err)
 	require.Len(t, steps, 4)
-	require.Equal(t, "reflow", steps[3].ID)
-	require.Equal(t, []Situation{SituationFix}, steps[3].Situations)
+	require.Equal(t, "spec-coverage", steps[3].ID)
+	require.Equal(t, []Situation{SituationTests, SituationFix}, steps[3].Situations)
+	require.Contains(t, steps[3].Check.Args, "coverage")
+
+	// Only one step may replace the opt-in default.
+	cfg.Steps = append(cfg.Steps, Step{ID: "spec-coverage"})
+	_, err = ResolveSteps(cfg, 120)
+	require.ErrorContains(t, err, `duplicate lint step id "spec-coverage"`)
+}
+
+func TestResolveSteps_UserStepsWithNoSituationsAreNotOptInDefaults(t *testing.T) {
+	custom := Step{
+		ID:         "custom",
+		Situations: []Situation{},
+		Check:      &cmdrunner.Command{Command: "true"},
+	}
+
+	// In replace mode there are no defaults, so a repeated ID is a duplicate even if the first step runs in no situations.
+	cfg := &Lints{Mode: ConfigModeReplace, Steps: []Step{custom, {ID: "custom", Check: &cmdrunner.Command{Command: "true"}}}}
+	_, err := ResolveSteps(cfg, 120)
+	require.ErrorContains(t, err, `duplicate lint step id "custom"`)
+
+	cfg = &Lints{Mode: ConfigModeReplace, Steps: []Step{{ID: "spec-coverage", Situations: []Situation{}}, {ID: "spec-coverage"}}}
+	_, err = ResolveSteps(cfg, 120)
+	require.ErrorContains(t, err, `duplicate lint step id "spec-coverage"`)
+
+	cfg = &Lints{Mode: ConfigModeExtend, Steps: []Step{custom, {ID: "custom", Check: &cmdrunner.Command{Command: "true"}}}}
+	_, err = ResolveSteps(cfg, 120)
+	require.ErrorContains(t, err, `duplicate lint step id "custom"`)
 }
 
 func TestResolveSteps_ExtendCanAddPreconfiguredStaticcheckByID(t *testing.T) {
@@ -224,15 +295,15 @@ func TestResolveSteps_ExtendCanAddPreconfiguredStaticcheckByID(t *testing.T) {
 
 	steps, err := ResolveSteps(cfg, 120)
 	require.NoError(t, err)
-	require.Len(t, steps, 4)
+	require.Len(t, steps, 5)
 	require.Equal(t, "gofmt", steps[0].ID)
 	require.Equal(t, "spec-fmt", steps[1].ID)
 	require.Equal(t, "spec-diff", steps[2].ID)
-	require.Equal(t, "staticcheck", steps[3].ID)
-	require.Equal(t, "{{ .moduleDir }}", steps[3].Check.CWD)
-	require.Contains(t, steps[3].Check.Args, "./{{ .relativePackageDir }}")
-	require.Nil(t, steps[3].Situations)
-	require.Nil(t, steps[3].Fix)
+	require.Equal(t, "staticcheck", steps[4].ID)
+	require.Equal(t, "{{ .moduleDir }}", steps[4].Check.CWD)
+	require.Contains(t, steps[4].Check.Args, "./{{ .relativePackageDir }}")
+	require.Nil(t, steps[4].Situations)
+	require.Nil(t, steps[4].Fix)
 }
 
 func TestResolveSteps_ExtendCanAddPreconfiguredGolangciLintByID(t *testing.T) {
@@ -245,15 +316,15 @@ func TestResolveSteps_ExtendCanAddPreconfiguredGolangciLintByID(t *testing.T) {
 
 	steps, err := ResolveSteps(cfg, 120)
 	require.NoError(t, err)
-	require.Len(t, steps, 4)
+	require.Len(t, steps, 5)
 	require.Equal(t, "gofmt", steps[0].ID)
 	require.Equal(t, "spec-fmt", steps[1].ID)
 	require.Equal(t, "spec-diff", steps[2].ID)
-	require.Equal(t, "golangci-lint", steps[3].ID)
-	require.Equal(t, "{{ .moduleDir }}", steps[3].Check.CWD)
-	require.Contains(t, steps[3].Check.Args, "./{{ .relativePackageDir }}")
-	require.Contains(t, steps[3].Fix.Args, "--fix")
-	require.Nil(t, steps[3].Situations)
+	require.Equal(t, "golangci-lint", steps[4].ID)
+	require.Equal(t, "{{ .moduleDir }}", steps[4].Check.CWD)
+	require.Contains(t, steps[4].Check.Args, "./{{ .relativePackageDir }}")
+	require.Contains(t, steps[4].Fix.Args, "--fix")
+	require.Nil(t, steps[4].Situations)
 }
 
 func TestResolveSteps_AllowsDuplicateUnsetID(t *testing.T) {
@@ -571,6 +642,7 @@ func TestRun_SpecStepsSkippedWhenNoSpecMD(t *testing.T) {
 		situation Situation
 	}{
 		{name: "spec diff in fix", stepID: "spec-diff", situation: SituationFix},
+		{name: "spec coverage in tests", stepID: "spec-coverage", situation: SituationTests},
 		{name: "spec fmt in patch", stepID: "spec-fmt", situation: SituationPatch},
 		{name: "spec fmt in fix", stepID: "spec-fmt", situation: SituationFix},
 	}
@@ -629,6 +701,42 @@ func TestRun_SpecDiffRunsInProcess(t *testing.T) {
 	require.NotContains(t, out, "should-not-run")
 }
 
+func TestRun_SpecCoverageRunsInProcess(t *testing.T) {
+	t.Setenv("CODALOTL_LINTS_HELPER_PROCESS", "1")
+
+	sandboxDir, target, relativePackageDir := writeTempModule(t)
+	err := os.WriteFile(filepath.Join(target, "tgt.go"), []byte("package tgt\n\nfunc Foo() {}\n\nfunc Bar() {}\n"), 0o644)
+	require.NoError(t, err)
+	writeSpec(t, target,
+		"# spec",
+		"",
+		"## Public API",
+		"",
+		"```go {api}",
+		"func Foo() {",
+		"}",
+		"```",
+		"",
+	)
+
+	steps := []Step{{
+		ID:         "spec-coverage",
+		Situations: []Situation{SituationTests},
+		Check:      helperCmd("should-not-run", 0, false),
+	}}
+
+	out, err := Run(context.Background(), sandboxDir, target, steps, SituationTests)
+	require.NoError(t, err)
+
+	require.Contains(t, out, `lint-status ok="false"`)
+	require.Contains(t, out, "\n$ codalotl spec coverage "+relativePackageDir+"\n")
+	require.Contains(t, out, `mode="check"`)
+	require.Contains(t, out, "Bar (tgt.go:5)")
+	require.NotContains(t, out, "Foo (")
+	require.Contains(t, out, "not declared in the SPEC.md Public API")
+	require.NotContains(t, out, "should-not-run")
+}
+
 func TestRun_SpecFmtRunsInProcess(t *testing.T) {
 	for _, situation := range []Situation{SituationPatch, SituationFix} {
 		t.Run(string(situation), func(t *testing.T) {
@@ -968,6 +1076,74 @@ func TestRun_ConditionalStepErrorIsTreatedActive(t *testing.T) {
 	require.Contains(t, out, "ran")
 }
 
+func TestRun_ParallelStepsRunConcurrentlyInStepOrder(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	sandboxDir, target, _ := writeTempModule(t)
+
+	// Each step creates its own marker and waits for the other's, so both only succeed if they run at the same time. The second step finishes first.
+	rendezvous := func(mine, theirs, after string) *cmdrunner.Command {
+		return &cmdrunner.Command{
+			Command: "sh",
+			Args:    []string{"-c", fmt.Sprintf("touch %s; while [ ! -f %s ]; do sleep 0.01; done; %secho %s", mine, theirs, after, mine)},
+			Timeout: "10s",
+		}
+	}
+	steps := []Step{
+		{ID: "a", Parallel: true, Check: rendezvous("a", "b", "sleep 0.2; ")},
+		{ID: "b", Parallel: true, Check: rendezvous("b", "a", "")},
+		{ID: "c", Check: &cmdrunner.Command{Command: "sh", Args: []string{"-c", "echo c"}}},
+	}
+
+	out, err := Run(context.Background(), sandboxDir, target, steps, SituationTests)
+	require.NoError(t, err)
+	require.Contains(t, out, `lint-status ok="true"`)
+
+	ia := strings.Index(out, "\na\n")
+	ib := strings.Index(out, "\nb\n")
+	ic := strings.Index(out, "\nc\n")
+	require.True(t, ia >= 0 && ia < ib && ib < ic, out)
+}
+
+func TestRun_ProblemMatcherFindingsAreClippedToCodeUnit(t *testing.T) {
+	t.Setenv("CODALOTL_LINTS_HELPER_PROCESS", "1")
+	sandboxDir, target, _ := writeTempModule(t)
+	require.NoError(t, os.WriteFile(filepath.Join(target, "a.go"), []byte("package tgt\n"), 0o644))
+
+	// Output is relative to the module dir (the command's CWD). The second finding is in another package, and the third duplicates the first.
+	check := helperCmd("internal/tgt/a.go:5:2: this value of err is never used (SA4006)\ninternal/other/b.go:1:1: at least one file in a package should have a package comment (ST1000)\ninternal/tgt/a.go:5:2: this value of err is never used (SA4006)", 1, false)
+	check.CWD = templateModuleDir
+	check.ProblemMatchers = []cmdrunner.ProblemMatcher{{Preset: "staticcheck"}}
+	steps := []Step{{ID: "staticcheck", Check: check}}
+
+	out, err := Run(context.Background(), sandboxDir, target, steps, SituationTests)
+	require.NoError(t, err)
+	require.Contains(t, out, `<lint-status ok="false" mode="check" clipped-findings="1">`)
+
+	// The helper's stdout is echoed on the `$` line; the body follows it.
+	_, body, ok := strings.Cut(out, "exit=1\n")
+	require.True(t, ok, out)
+	require.Equal(t, "internal/tgt/a.go:5:2: warning: this value of err is never used (SA4006)\n</lint-status>", body)
+}
+
+func TestResolveSteps_ExtendCanMakePreconfiguredStepParallel(t *testing.T) {
+	cfg := &Lints{Steps: []Step{{ID: stepIDStaticcheck, Parallel: true}}}
+
+	steps, err := ResolveSteps(cfg, 0)
+	require.NoError(t, err)
+
+	var found bool
+	for _, s := range steps {
+		if s.ID == stepIDStaticcheck {
+			found = true
+			require.True(t, s.Parallel)
+			require.NotNil(t, s.Check)
+		}
+	}
+	require.True(t, found)
+}
+
 func helperCmd(stdout string, exitCode int, failIfAnyOutput bool) *cmdrunner.Command {
 	return &cmdrunner.Command{
 		Command: os.Args[0],
@@ -459,6 +459,50 @@ func GetAPIEndpointURL(id ModelID) string {
 	return info.APIEndpointURL
 }
 
+// SetFallbackModels configures the ordered fallback chain for id. When a request to id fails because the provider is rate limited, overloaded, or out of quota,
+// consumers (ex: llmstream) may retry the request with each fallback in order.
+//
+// An empty fallbacks clears the chain. It returns an error if id or any fallback is not a registered model, if a fallback repeats, or if a fallback is id itself.
+// Chains are not transitive: the fallbacks of a fallback are not consulted.
+func SetFallbackModels(id ModelID, fallbacks []ModelID) error {
+	if !id.Valid() {
+		return fmt.Errorf("model id %q not registered", id)
+	}
+	seen := make(map[ModelID]bool, len(fallbacks))
+	for _, fb := range fallbacks {
+		if !fb.Valid() {
+			return fmt.Errorf("fallback model id %q for %q not registered", fb, id)
+		}
+		if fb == id {
+			return fmt.Errorf("model id %q cannot fall back to itself", id)
+		}
+		if seen[fb] {
+			return fmt.Errorf("fallback model id %q listed more than once for %q", fb, id)
+		}
+		seen[fb] = true
+	}
+
+	modelsMu.Lock()
+	defer modelsMu.Unlock()
+	if len(fallbacks) == 0 {
+		delete(modelFallbacks, id)
+		return nil
+	}
+	modelFallbacks[id] = append([]ModelID(nil), fallbacks...)
+	return nil
+}
+
+// FallbackModels returns the ordered fallback chain configured for id with SetFallbackModels (nil if none).
+func FallbackModels(id ModelID) []ModelID {
+	modelsMu.RLock()
+	defer modelsMu.RUnlock()
+	fallbacks := modelFallbacks[id]
+	if len(fallbacks) == 0 {
+		return nil
+	}
+	return append([]ModelID(nil), fallbacks...)
+}
+
 // internal structures and initialization.
 
 // providerConfigFile is the top-level schema for an embedded provider JSON config.
@@ -524,6 +568,9 @@ var (
 
 	// providerSubscriptionRequired tracks providers whose saved subscription auth must be used when subscription auth applies.
 	providerSubscriptionRequired = make(map[ProviderID]bool)
+
+	// modelFallbacks maps a model ID to its ordered fallback chain configured with SetFallbackModels.
+	modelFallbacks = make(map[ModelID][]ModelID)
 )
 
 var anthropicVersionSuffix = regexp.MustCompile(`-\d{6,}$`)
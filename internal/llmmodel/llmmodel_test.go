@@ -577,3 +577,29 @@ func TestAvailableModelIDsWithAuthUsesSubscriptionEligibility(t *testing.T) {
 	require.True(t, ProviderHasSubscription(ProviderIDOpenAI))
 	require.True(t, modelHasEligibleProviderSubscription(DefaultModel))
 }
+
+func TestSetFallbackModels(t *testing.T) {
+	ids := AvailableModelIDs()
+	require.GreaterOrEqual(t, len(ids), 3)
+	primary, fb1, fb2 := ids[0], ids[1], ids[2]
+	t.Cleanup(func() { require.NoError(t, SetFallbackModels(primary, nil)) })
+
+	require.Nil(t, FallbackModels(primary))
+
+	require.NoError(t, SetFallbackModels(primary, []ModelID{fb1, fb2}))
+	require.Equal(t, []ModelID{fb1, fb2}, FallbackModels(primary))
+
+	// Returned slice is a copy.
+	got := FallbackModels(primary)
+	got[0] = "mutated"
+	require.Equal(t, []ModelID{fb1, fb2}, FallbackModels(primary))
+
+	require.Error(t, SetFallbackModels("no-such-model", []ModelID{fb1}))
+	require.Error(t, SetFallbackModels(primary, []ModelID{"no-such-model"}))
+	require.Error(t, SetFallbackModels(primary, []ModelID{primary}))
+	require.Error(t, SetFallbackModels(primary, []ModelID{fb1, fb1}))
+	require.Equal(t, []ModelID{fb1, fb2}, FallbackModels(primary), "failed calls must not mutate the chain")
+
+	require.NoError(t, SetFallbackModels(primary, nil))
+	require.Nil(t, FallbackModels(primary))
+}
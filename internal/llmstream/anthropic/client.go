@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/codalotl/codalotl/internal/q/sseclient"
 )
@@ -140,10 +141,21 @@ func (c *Client) StreamMessages(ctx context.Context, req MessageRequest) (*Strea
 	}
 
 	sc := sseclient.New(sseclient.WithHTTPClient(c.httpClient))
-	rawStream, err := sc.OpenRequest(httpReq)
+	rawStream, err := sc.Connect(httpReq, streamReconnectOptions)
 	if err != nil {
 		return nil, err
 	}
 
 	return newStream(rawStream), nil
 }
+
+// streamReconnectOptions retries transient stream failures in place: transport errors, 500/502/504 responses, and streams that drop before their first event.
+// 429, 503, and 529 are left to the caller, which may fail over to another model. Messages streams carry no event IDs, so a stream that drops mid-response can't
+// be resumed; the drop is returned rather than replaying the request.
+var streamReconnectOptions = sseclient.ReconnectOptions{
+	InitialDelay:         250 * time.Millisecond,
+	MaxDelay:             2 * time.Second,
+	MaxAttempts:          2,
+	RetryableStatusCodes: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout},
+	RequireEventID:       true,
+}
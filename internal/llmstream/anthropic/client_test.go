@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/codalotl/codalotl/internal/q/sseclient"
@@ -230,6 +231,42 @@ func TestClientStreamMessages_Non200ReturnsOpenError(t *testing.T) {
 	assert.True(t, errors.Is(openErr, sseclient.ErrUnexpectedStatus))
 }
 
+func TestClientStreamMessages_RetriesTransientFailuresWithoutReplaying(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(body), `"model":"claude-test"`)
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		// The stream drops before message_stop.
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\",\"type\":\"message\",\"role\":\"assistant\",\"model\":\"claude-test\",\"content\":[]}}\n\n")
+	}))
+	defer srv.Close()
+
+	client := New("test-key", WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+	stream, err := client.StreamMessages(context.Background(), MessageRequest{
+		Model:     "claude-test",
+		MaxTokens: 8,
+		Messages:  []MessageParam{{Role: "user", Content: []ContentBlockParam{{Type: "text", Text: "hi"}}}},
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, EventTypeMessageStart, event.Type)
+
+	// Without event IDs the response can't be resumed, so the drop is returned instead of re-sending the request.
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
 func TestStream_DefaultMessageEventTypeFallback(t *testing.T) {
 	t.Parallel()
 
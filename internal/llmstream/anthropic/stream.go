@@ -13,12 +13,12 @@ import (
 
 // Stream decodes SSE events for one streaming request.
 type Stream struct {
-	sse     *sseclient.Stream // sse is the underlying SSE stream for the response body.
-	mu      sync.Mutex        // mu guards stopped.
-	stopped bool              // stopped records whether message_stop has been received.
+	sse     *sseclient.EventSource // sse is the underlying SSE stream, which reconnects on transient failures.
+	mu      sync.Mutex             // mu guards stopped.
+	stopped bool                   // stopped records whether message_stop has been received.
 }
 
-func newStream(sse *sseclient.Stream) *Stream {
+func newStream(sse *sseclient.EventSource) *Stream {
 	return &Stream{sse: sse}
 }
 
@@ -29,17 +29,26 @@ type MessageParam struct {
 
 // ContentBlockParam covers block types needed by llmstream conversation encoding.
 type ContentBlockParam struct {
-	Type         string             // "text", "tool_use", "tool_result", "thinking", "redacted_thinking"
-	Text         string             // text
-	ID           string             // tool_use
-	Name         string             // Name is the tool name for a tool_use block.
-	Input        json.RawMessage    // Input is the raw JSON input for a tool_use block.
-	ToolUseID    string             // tool_result
-	Result       string             // Result is the tool_result content sent as Anthropic's content field.
-	IsError      bool               // IsError marks a tool_result block as an error result.
-	Thinking     string             // thinking
-	Signature    string             // Signature verifies a thinking block when Anthropic provides one.
-	CacheControl *CacheControlParam // CacheControl configures prompt caching for this content block.
+	Type         string              // "text", "image", "tool_use", "tool_result", "thinking", "redacted_thinking"
+	Text         string              // text
+	Source       *ImageSourceParam   // image
+	ID           string              // tool_use
+	Name         string              // Name is the tool name for a tool_use block.
+	Input        json.RawMessage     // Input is the raw JSON input for a tool_use block.
+	ToolUseID    string              // tool_result
+	Result       string              // Result is the tool_result content sent as Anthropic's content field.
+	ResultBlocks []ContentBlockParam // replaces Result when set (ex: text and images)
+	IsError      bool                // IsError marks a tool_result block as an error result.
+	Thinking     string              // thinking
+	Signature    string              // Signature verifies a thinking block when Anthropic provides one.
+	CacheControl *CacheControlParam  // CacheControl configures prompt caching for this content block.
+}
+
+// ImageSourceParam is the source of an image content block.
+type ImageSourceParam struct {
+	Type      string // "base64"
+	MediaType string // ex: "image/png"
+	Data      string // base64-encoded
 }
 
 // ToolParam describes a tool available to the model.
@@ -117,11 +126,12 @@ func (m MessageParam) MarshalJSON() ([]byte, error) {
 type contentBlockParamJSON struct {
 	Type         string             `json:"type"`                    // Type is the content block kind.
 	Text         string             `json:"text,omitempty"`          // Text is the text block content.
+	Source       *ImageSourceParam  `json:"source,omitempty"`        // Source is the image block source.
 	ID           string             `json:"id,omitempty"`            // ID identifies a tool_use block.
 	Name         string             `json:"name,omitempty"`          // Name is the tool name for a tool_use block.
 	Input        json.RawMessage    `json:"input,omitempty"`         // Input is the raw JSON input for a tool_use block.
 	ToolUseID    string             `json:"tool_use_id,omitempty"`   // ToolUseID identifies the tool_use block answered by a tool_result block.
-	Content      string             `json:"content,omitempty"`       // Content is the tool_result content.
+	Content      any                `json:"content,omitempty"`       // Content is the tool_result content: a string or content blocks.
 	IsError      bool               `json:"is_error,omitempty"`      // IsError marks a tool_result block as an error result.
 	Thinking     string             `json:"thinking,omitempty"`      // Thinking is the reasoning text for a thinking block.
 	Signature    string             `json:"signature,omitempty"`     // Signature verifies a thinking block when Anthropic provides one.
@@ -130,14 +140,21 @@ type contentBlockParamJSON struct {
 
 // MarshalJSON encodes c as an Anthropic content block object.
 func (c ContentBlockParam) MarshalJSON() ([]byte, error) {
+	var content any
+	if len(c.ResultBlocks) > 0 {
+		content = c.ResultBlocks
+	} else if c.Result != "" {
+		content = c.Result
+	}
 	return json.Marshal(contentBlockParamJSON{
 		Type:         c.Type,
 		Text:         c.Text,
+		Source:       c.Source,
 		ID:           c.ID,
 		Name:         c.Name,
 		Input:        c.Input,
 		ToolUseID:    c.ToolUseID,
-		Content:      c.Result,
+		Content:      content,
 		IsError:      c.IsError,
 		Thinking:     c.Thinking,
 		Signature:    c.Signature,
@@ -145,6 +162,18 @@ func (c ContentBlockParam) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// imageSourceParamJSON is the JSON wire shape for ImageSourceParam.
+type imageSourceParamJSON struct {
+	Type      string `json:"type"`       // Type is the source kind, such as "base64".
+	MediaType string `json:"media_type"` // MediaType is the image media type.
+	Data      string `json:"data"`       // Data is the base64-encoded image.
+}
+
+// MarshalJSON encodes s as an Anthropic image source object.
+func (s ImageSourceParam) MarshalJSON() ([]byte, error) {
+	return json.Marshal(imageSourceParamJSON(s))
+}
+
 // toolParamJSON is the JSON wire shape for ToolParam.
 type toolParamJSON struct {
 	Name         string             `json:"name"`                    // Name is the tool name exposed to the model.
@@ -184,12 +184,26 @@ func anthropicBuildMessageParam(turn Turn) (anthropicapi.MessageParam, bool, err
 			if typed.CallID == "" {
 				return anthropicapi.MessageParam{}, false, errors.New("tool result missing call_id")
 			}
-			blocks = append(blocks, anthropicapi.ContentBlockParam{
+			block := anthropicapi.ContentBlockParam{
 				Type:      "tool_result",
 				ToolUseID: typed.CallID,
 				Result:    typed.Result,
 				IsError:   typed.IsError,
-			})
+			}
+			if len(typed.Images) > 0 {
+				if typed.Result != "" {
+					block.ResultBlocks = append(block.ResultBlocks, anthropicapi.ContentBlockParam{Type: "text", Text: typed.Result})
+				}
+				for _, img := range typed.Images {
+					block.ResultBlocks = append(block.ResultBlocks, anthropicImageBlock(img))
+				}
+			}
+			blocks = append(blocks, block)
+		case ImageContent:
+			if turn.Role != RoleUser {
+				return anthropicapi.MessageParam{}, false, fmt.Errorf("images are only supported in user turns (role=%v)", turn.Role)
+			}
+			blocks = append(blocks, anthropicImageBlock(typed))
 		case ReasoningContent:
 			if typed.Content == "" {
 				continue
@@ -211,6 +225,14 @@ func anthropicBuildMessageParam(turn Turn) (anthropicapi.MessageParam, bool, err
 	}
 	return anthropicapi.MessageParam{Role: role, Content: blocks}, true, nil
 }
+
+// anthropicImageBlock returns img as a base64 image content block.
+func anthropicImageBlock(img ImageContent) anthropicapi.ContentBlockParam {
+	return anthropicapi.ContentBlockParam{
+		Type:   "image",
+		Source: &anthropicapi.ImageSourceParam{Type: "base64", MediaType: img.MediaType, Data: img.Base64()},
+	}
+}
 func anthropicMapTurnRole(role Role) (string, bool) {
 	switch role {
 	case RoleUser:
@@ -413,9 +435,9 @@ func (s *anthropicStreamState) processEvent(evt anthropicapi.Event) (*Event, boo
 			errType = evt.Error.Type
 		}
 		if errType != "" {
-			return nil, true, fmt.Errorf("%s (type=%s)", msg, errType)
+			msg = fmt.Sprintf("%s (type=%s)", msg, errType)
 		}
-		return nil, true, errors.New(msg)
+		return nil, true, &anthropicStreamError{msg: msg, apiErr: evt.Error}
 	case anthropicapi.EventTypePing:
 		return nil, false, nil
 	default:
@@ -718,3 +740,22 @@ func anthropicMapFinishReason(stopReason string, hasToolCalls bool) FinishReason
 		return FinishReasonUnknown
 	}
 }
+
+// anthropicStreamError is an error event received mid-stream. It preserves the provider's APIError (ex: "overloaded_error") for errors.As.
+type anthropicStreamError struct {
+	msg    string                 // msg is the human-readable error message.
+	apiErr *anthropicapi.APIError // apiErr is the provider error object, if the event had one.
+}
+
+// Error returns the error message.
+func (e *anthropicStreamError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the provider error object, or nil.
+func (e *anthropicStreamError) Unwrap() error {
+	if e.apiErr == nil {
+		return nil
+	}
+	return e.apiErr
+}
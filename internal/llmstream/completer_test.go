@@ -144,6 +144,10 @@ func (c *fakeCompleterConversation) Turns() []Turn {
 	return nil
 }
 
+func (c *fakeCompleterConversation) Model() llmmodel.ModelID {
+	return c.modelID
+}
+
 func (c *fakeCompleterConversation) AddTools([]Tool) error {
 	return nil
 }
@@ -153,6 +157,10 @@ func (c *fakeCompleterConversation) AddUserTurn(text string) error {
 	return c.addUserErr
 }
 
+func (c *fakeCompleterConversation) AddUserTurnWithImages(text string, _ []ImageContent) error {
+	return c.AddUserTurn(text)
+}
+
 func (c *fakeCompleterConversation) AddToolResults([]ToolResult) error {
 	return nil
 }
@@ -82,6 +82,9 @@ type ToolResult struct {
 	Type   string `json:"type"`    // Matches type of corresponding ToolCall (ex: "function_call").
 	Result string `json:"result"`  // Can either be raw string (ex: markdown; some text; a bulleted list) or JSON-serialized string; depends on Tool.
 
+	// Images are optional images returned alongside Result (ex: a screenshot read by a tool). Providers receive them as part of the tool output.
+	Images []ImageContent `json:"images,omitempty"`
+
 	// Did the tool call fail? NOTE: IsError should be false for things like failed tests, or shell commands which returned a non-zero error code (but which were otherwise
 	// successfully attempted).
 	IsError bool `json:"is_error"`
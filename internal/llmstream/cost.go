@@ -0,0 +1,54 @@
+package llmstream
+
+import "github.com/codalotl/codalotl/internal/llmmodel"
+
+// EstimatedCostUSD estimates the USD cost of u using info's pricing metadata. Cached reads fall back to CostPer1MIn when there is no cached-read price, as do cache
+// writes when there is no cache-write price.
+//
+// ok is false if the cost can't be estimated: the model is unknown, or a rate needed for a nonzero token count is missing.
+func (u TokenUsage) EstimatedCostUSD(info llmmodel.ModelInfo) (cost float64, ok bool) {
+	if info.ID == llmmodel.ModelIDUnknown {
+		return 0, false
+	}
+
+	const million = 1_000_000.0
+	missing := false
+	add := func(tokens int64, rate float64) {
+		if tokens <= 0 {
+			return
+		}
+		if rate <= 0 {
+			missing = true
+			return
+		}
+		cost += (float64(tokens) / million) * rate
+	}
+
+	uncached := u.TotalInputTokens - u.CachedInputTokens - u.CacheCreationInputTokens
+	if uncached < 0 {
+		uncached = u.TotalInputTokens
+	}
+	add(uncached, info.CostPer1MIn)
+
+	cachedRate := info.CostPer1MInCached
+	if cachedRate <= 0 {
+		cachedRate = info.CostPer1MIn
+	}
+	add(u.CachedInputTokens, cachedRate)
+
+	cacheWriteRate := info.CostPer1MInSaveToCache
+	if cacheWriteRate <= 0 {
+		cacheWriteRate = info.CostPer1MIn
+	}
+	add(u.CacheCreationInputTokens, cacheWriteRate)
+
+	add(u.TotalOutputTokens, info.CostPer1MOut)
+
+	if missing {
+		return 0, false
+	}
+	if cost == 0 && (u.TotalInputTokens > 0 || u.TotalOutputTokens > 0) {
+		return 0, false
+	}
+	return cost, true
+}
@@ -0,0 +1,29 @@
+package llmstream
+
+import (
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenUsageEstimatedCostUSD_AccountsForCacheCreationWrites(t *testing.T) {
+	info := llmmodel.ModelInfo{
+		ID:                     llmmodel.ModelID("fake"),
+		CostPer1MIn:            10,
+		CostPer1MInCached:      2,
+		CostPer1MInSaveToCache: 20,
+		CostPer1MOut:           30,
+	}
+	usage := TokenUsage{
+		TotalInputTokens:         1_000,
+		CachedInputTokens:        200,
+		CacheCreationInputTokens: 300,
+		TotalOutputTokens:        500,
+	}
+
+	cost, ok := usage.EstimatedCostUSD(info)
+	require.True(t, ok)
+	assert.InDelta(t, 0.0264, cost, 0.0000001)
+}
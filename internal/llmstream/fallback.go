@@ -0,0 +1,226 @@
+package llmstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	anthropicapi "github.com/codalotl/codalotl/internal/llmstream/anthropic"
+	geminiapi "github.com/codalotl/codalotl/internal/llmstream/gemini"
+	"github.com/codalotl/codalotl/internal/q/sseclient"
+	"github.com/openai/openai-go/v3/responses"
+)
+
+// failoverErrorFragments are provider error codes/types (lowercased) that indicate the provider cannot serve the request right now, but another provider might.
+var failoverErrorFragments = []string{
+	"rate_limit_exceeded",
+	"rate_limit_error",
+	"insufficient_quota",
+	"usage_limit_reached",
+	"server_is_overloaded",
+	"overloaded_error",
+	"resource_exhausted",
+}
+
+// isFailoverError reports whether err indicates the model's provider is rate limited, overloaded, or out of quota, such that the request should be retried with
+// a fallback model (see llmmodel.FallbackModels). Context cancellation is never a failover error.
+func isFailoverError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var openAIErr *responses.Error
+	if errors.As(err, &openAIErr) {
+		if failoverStatusCode(openAIErr.StatusCode) {
+			return true
+		}
+		return containsFailoverFragment(openAIErr.Code) || containsFailoverFragment(openAIErr.Type)
+	}
+
+	var geminiErr *geminiapi.APIError
+	if errors.As(err, &geminiErr) {
+		return geminiErr.IsRateLimit() || failoverStatusCode(geminiErr.StatusCode)
+	}
+
+	var anthropicErr *anthropicapi.APIError
+	if errors.As(err, &anthropicErr) {
+		return containsFailoverFragment(anthropicErr.Type)
+	}
+
+	var openErr *sseclient.OpenError
+	if errors.As(err, &openErr) && openErr.Response != nil {
+		return failoverStatusCode(openErr.Response.StatusCode)
+	}
+
+	return containsFailoverFragment(err.Error())
+}
+
+// failoverStatusCode reports whether an HTTP status code means the provider is rate limited, overloaded, or out of quota.
+func failoverStatusCode(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusPaymentRequired, http.StatusServiceUnavailable, 529: // 529 is Anthropic's "overloaded".
+		return true
+	default:
+		return false
+	}
+}
+
+func containsFailoverFragment(s string) bool {
+	s = strings.ToLower(s)
+	for _, fragment := range failoverErrorFragments {
+		if strings.Contains(s, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendWithFallbacks tries each of the fallback models configured for the conversation's current model, in order, after a send to that model failed with primaryErr
+// (a failover error). Fallbacks that have no credentials or cannot represent the conversation's tools are skipped. Before each attempt, the conversation is translated
+// to the fallback's provider shape and an EventTypeRetry event is emitted. If a fallback succeeds, the conversation stays on that model for later sends.
+//
+// It returns primaryErr if no fallback is eligible, and otherwise the last fallback's result. If no fallback succeeds, the conversation is restored to its original
+// model and provider state.
+func (sc *streamingConversation) sendWithFallbacks(ctx context.Context, out chan Event, opt *SendOptions, primaryErr error) (Turn, error) {
+	failedModel := sc.modelID
+	snapshot := sc.snapshotProviderState()
+	err := primaryErr
+	for _, fallback := range llmmodel.FallbackModels(failedModel) {
+		if ctx.Err() != nil {
+			break
+		}
+		info := llmmodel.GetModelInfo(fallback)
+		send := sc.sendFuncForModel(info)
+		if send == nil || !modelHasCredentials(fallback) || !modelSupportsTools(info, sc.tools) {
+			sc.Log("conversation.failover.skip", "from", string(failedModel), "to", string(fallback))
+			continue
+		}
+
+		sc.Log("conversation.failover", "from", string(sc.modelID), "to", string(fallback), "err", err.Error())
+		trySendEvent(ctx, out, Event{Type: EventTypeRetry, Error: fmt.Errorf("model %s unavailable; falling back to %s: %w", sc.modelID, fallback, err)})
+		if switchErr := sc.switchModel(fallback); switchErr != nil {
+			sc.restoreProviderState(snapshot)
+			return Turn{}, sc.LogWrappedErr("conversation.failover.switch", switchErr, "to", string(fallback))
+		}
+
+		var newTurn Turn
+		newTurn, err = sc.sendWithRetries(ctx, out, opt, info, send)
+		if err == nil {
+			return newTurn, nil
+		}
+		if !isFailoverError(err) {
+			break
+		}
+	}
+	sc.restoreProviderState(snapshot)
+	return Turn{}, err
+}
+
+// providerState is the part of a streamingConversation that switchModel rewrites.
+type providerState struct {
+	modelID                llmmodel.ModelID
+	turns                  []Turn
+	geminiContents         []*geminiapi.Content
+	providerConversationID string
+	promptCacheKey         string
+}
+
+// snapshotProviderState captures the state switchModel rewrites. switchModel replaces (rather than mutates) the slices, so no deep copy is needed.
+func (sc *streamingConversation) snapshotProviderState() providerState {
+	return providerState{
+		modelID:                sc.modelID,
+		turns:                  sc.turns,
+		geminiContents:         sc.geminiContents,
+		providerConversationID: sc.providerConversationID,
+		promptCacheKey:         sc.promptCacheKey,
+	}
+}
+
+// restoreProviderState undoes any switchModel calls made since s was captured.
+func (sc *streamingConversation) restoreProviderState(s providerState) {
+	sc.modelID = s.modelID
+	sc.turns = s.turns
+	sc.geminiContents = s.geminiContents
+	sc.providerConversationID = s.providerConversationID
+	sc.promptCacheKey = s.promptCacheKey
+}
+
+// modelHasCredentials reports whether modelID has an effective API key or usable provider subscription auth.
+func modelHasCredentials(modelID llmmodel.ModelID) bool {
+	return llmmodel.GetAPIKey(modelID) != "" || llmmodel.ModelUsesProviderSubscription(modelID)
+}
+
+// switchModel moves the conversation to modelID, translating provider-specific state so the existing history can be replayed to the new provider:
+//   - Provider response links (ex: OpenAI previous_response_id) are dropped, so the full history is sent.
+//   - Reasoning and compaction parts are dropped, since their opaque state (signatures, encrypted content) is only valid for the provider that produced it.
+//   - Provider item IDs are cleared. Tool call IDs are kept, since tool results reference them.
+//   - Gemini-native contents are rebuilt when switching to Gemini, and cleared otherwise.
+func (sc *streamingConversation) switchModel(modelID llmmodel.ModelID) error {
+	turns := make([]Turn, 0, len(sc.turns))
+	for _, turn := range sc.turns {
+		turns = append(turns, portableTurn(turn))
+	}
+
+	var geminiContents []*geminiapi.Content
+	if modelSupportsAPIType(llmmodel.GetModelInfo(modelID), llmmodel.ProviderTypeGemini) {
+		for _, turn := range turns {
+			if turn.Role == RoleSystem {
+				continue
+			}
+			content, include, err := geminiBuildContentFromTurn(turn)
+			if err != nil {
+				return err
+			}
+			if include {
+				geminiContents = append(geminiContents, content)
+			}
+		}
+	}
+
+	sc.modelID = modelID
+	sc.turns = turns
+	sc.geminiContents = geminiContents
+	sc.providerConversationID = ""
+	sc.promptCacheKey = computePromptCacheKey(modelID, sc.turns[0].TextContent())
+	return nil
+}
+
+// portableTurn returns a copy of turn without provider-specific state, suitable for replay to any provider.
+func portableTurn(turn Turn) Turn {
+	parts := make([]ContentPart, 0, len(turn.Parts))
+	for _, part := range turn.Parts {
+		switch p := part.(type) {
+		case TextContent:
+			parts = append(parts, TextContent{Content: p.Content})
+		case ToolCall:
+			p.ProviderID = ""
+			parts = append(parts, p)
+		case ToolResult:
+			parts = append(parts, p)
+		case ReasoningContent, CompactionContent:
+			// Opaque provider state; not replayable elsewhere.
+		default:
+			parts = append(parts, part)
+		}
+	}
+	turn.Parts = parts
+	turn.ProviderID = ""
+	return turn
+}
+
+// modelSupportsTools reports whether tools can be sent to the model described by info. Custom (grammar-based) tools are only supported by OpenAI Responses.
+func modelSupportsTools(info llmmodel.ModelInfo, tools []Tool) bool {
+	for _, t := range tools {
+		kind := t.Info().Kind
+		if kind != "" && kind != ToolKindFunction && !modelSupportsAPIType(info, llmmodel.ProviderTypeOpenAIResponses) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,265 @@
+package llmstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	anthropicapi "github.com/codalotl/codalotl/internal/llmstream/anthropic"
+	geminiapi "github.com/codalotl/codalotl/internal/llmstream/gemini"
+	"github.com/codalotl/codalotl/internal/q/sseclient"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsFailoverError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "plain", err: errors.New("boom"), want: false},
+		{name: "canceled", err: fmt.Errorf("send: %w", context.Canceled), want: false},
+		{name: "openai 429", err: fmt.Errorf("wrapped: %w", &responses.Error{StatusCode: http.StatusTooManyRequests}), want: true},
+		{name: "openai quota code", err: &responses.Error{StatusCode: http.StatusBadRequest, Code: "insufficient_quota"}, want: true},
+		{name: "openai 400", err: &responses.Error{StatusCode: http.StatusBadRequest, Code: "invalid_request"}, want: false},
+		{name: "gemini rate limit", err: &geminiapi.APIError{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "gemini 400", err: &geminiapi.APIError{StatusCode: http.StatusBadRequest}, want: false},
+		{name: "anthropic overloaded event", err: &anthropicStreamError{msg: "Overloaded", apiErr: &anthropicapi.APIError{Type: "overloaded_error"}}, want: true},
+		{name: "anthropic invalid request event", err: &anthropicStreamError{msg: "bad", apiErr: &anthropicapi.APIError{Type: "invalid_request_error"}}, want: false},
+		{name: "sse open 529", err: &sseclient.OpenError{Err: sseclient.ErrUnexpectedStatus, Response: &http.Response{StatusCode: 529}}, want: true},
+		{name: "sse open 401", err: &sseclient.OpenError{Err: sseclient.ErrUnexpectedStatus, Response: &http.Response{StatusCode: http.StatusUnauthorized}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isFailoverError(tt.err))
+		})
+	}
+}
+
+func TestPortableTurn(t *testing.T) {
+	turn := Turn{
+		Role:       RoleAssistant,
+		ProviderID: "resp_1",
+		Parts: []ContentPart{
+			ReasoningContent{ProviderID: "rs_1", Content: "thinking", ProviderState: "sig"},
+			TextContent{ProviderID: "msg_1", Content: "hello"},
+			ToolCall{ProviderID: "fc_1", CallID: "call_1", Name: "ls", Type: "function_call", Input: `{}`},
+			CompactionContent{ProviderID: "cmp_1", ProviderState: "enc"},
+		},
+	}
+
+	got := portableTurn(turn)
+	assert.Empty(t, got.ProviderID)
+	assert.Equal(t, []ContentPart{
+		TextContent{Content: "hello"},
+		ToolCall{CallID: "call_1", Name: "ls", Type: "function_call", Input: `{}`},
+	}, got.Parts)
+
+	// The original is not mutated.
+	assert.Len(t, turn.Parts, 4)
+	assert.Equal(t, "resp_1", turn.ProviderID)
+}
+
+func TestSendAsync_FailsOverToFallbackModel(t *testing.T) {
+	anthropicRequests := 0
+	anthropicServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		anthropicRequests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(529)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`))
+	}))
+	defer anthropicServer.Close()
+
+	var openAIRequest map[string]any
+	openAIServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&openAIRequest)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		writeOpenAISSEEvent(t, w, "response.output_text.delta", `{
+			"type":"response.output_text.delta",
+			"sequence_number":0,
+			"item_id":"msg_fb",
+			"output_index":0,
+			"content_index":0,
+			"delta":"from fallback"
+		}`)
+		writeOpenAISSEEvent(t, w, "response.completed", `{
+			"type":"response.completed",
+			"sequence_number":1,
+			"response":{
+				"id":"resp_fb",
+				"object":"response",
+				"created_at":0,
+				"model":"gpt-4o-mini",
+				"status":"completed",
+				"output":[],
+				"usage":{
+					"input_tokens":3,
+					"input_tokens_details":{"cached_tokens":0},
+					"output_tokens":2,
+					"output_tokens_details":{"reasoning_tokens":0},
+					"total_tokens":5
+				}
+			}
+		}`)
+	}))
+	defer openAIServer.Close()
+
+	primary := llmmodel.ModelID("test-failover-primary")
+	fallback := llmmodel.ModelID("test-failover-fallback")
+	require.NoError(t, llmmodel.AddCustomModel(primary, llmmodel.ProviderIDAnthropic, "claude-sonnet-4-6", llmmodel.ModelOverrides{APIActualKey: "test-key", APIEndpointURL: anthropicServer.URL}))
+	require.NoError(t, llmmodel.AddCustomModel(fallback, llmmodel.ProviderIDOpenAI, "gpt-4o-mini", llmmodel.ModelOverrides{APIActualKey: "test-key", APIEndpointURL: openAIServer.URL}))
+	require.NoError(t, llmmodel.SetFallbackModels(primary, []llmmodel.ModelID{fallback}))
+	t.Cleanup(func() { _ = llmmodel.SetFallbackModels(primary, nil) })
+
+	conv := NewConversation(primary, "system instructions")
+	sc := conv.(*streamingConversation)
+	sc.turns = append(sc.turns,
+		Turn{Role: RoleUser, Parts: []ContentPart{TextContent{Content: "first"}}},
+		Turn{Role: RoleAssistant, ProviderID: "msg_a", Parts: []ContentPart{
+			ReasoningContent{ProviderID: "msg_a/thinking/0", Content: "hmm", ProviderState: "sig"},
+			TextContent{ProviderID: "msg_a/text/1", Content: "answer"},
+		}},
+	)
+	require.NoError(t, conv.AddUserTurn("second"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var retries []error
+	var finalTurn *Turn
+	for ev := range conv.SendAsync(ctx) {
+		switch ev.Type {
+		case EventTypeError:
+			require.NoError(t, ev.Error)
+		case EventTypeRetry:
+			retries = append(retries, ev.Error)
+		case EventTypeCompletedSuccess:
+			finalTurn = ev.Turn
+		}
+	}
+
+	assert.Equal(t, 1, anthropicRequests)
+	require.Len(t, retries, 1)
+	assert.Contains(t, retries[0].Error(), "falling back to test-failover-fallback")
+	require.NotNil(t, finalTurn)
+	assert.Equal(t, "from fallback", finalTurn.TextContent())
+
+	// The conversation stays on the fallback, with Anthropic-only state removed before replay.
+	assert.Equal(t, fallback, conv.Model())
+	require.NotNil(t, openAIRequest)
+	input, ok := openAIRequest["input"].([]any)
+	require.True(t, ok)
+	require.Len(t, input, 3)
+	for _, item := range input {
+		assert.NotEqual(t, "reasoning", item.(map[string]any)["type"])
+	}
+	assert.Equal(t, "from fallback", sc.LastTurn().TextContent())
+}
+
+func TestSendAsync_FailoverRestoresStateWhenFallbacksFail(t *testing.T) {
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(529)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`))
+	}))
+	defer primaryServer.Close()
+
+	uncredentialedRequests := 0
+	uncredentialedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uncredentialedRequests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer uncredentialedServer.Close()
+
+	invalidRequests := 0
+	invalidServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		invalidRequests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"invalid_request_error","message":"bad request"}}`))
+	}))
+	defer invalidServer.Close()
+
+	t.Setenv(llmmodel.ProviderKeyEnvVars()[llmmodel.ProviderIDOpenAI], "")
+
+	primary := llmmodel.ModelID("test-failover-restore-primary")
+	uncredentialed := llmmodel.ModelID("test-failover-restore-nokey")
+	invalid := llmmodel.ModelID("test-failover-restore-invalid")
+	require.NoError(t, llmmodel.AddCustomModel(primary, llmmodel.ProviderIDAnthropic, "claude-sonnet-4-6", llmmodel.ModelOverrides{APIActualKey: "test-key", APIEndpointURL: primaryServer.URL}))
+	require.NoError(t, llmmodel.AddCustomModel(uncredentialed, llmmodel.ProviderIDOpenAI, "gpt-4o-mini", llmmodel.ModelOverrides{APIEndpointURL: uncredentialedServer.URL}))
+	require.NoError(t, llmmodel.AddCustomModel(invalid, llmmodel.ProviderIDAnthropic, "claude-sonnet-4-6", llmmodel.ModelOverrides{APIActualKey: "test-key", APIEndpointURL: invalidServer.URL}))
+	require.NoError(t, llmmodel.SetFallbackModels(primary, []llmmodel.ModelID{uncredentialed, invalid}))
+	t.Cleanup(func() { _ = llmmodel.SetFallbackModels(primary, nil) })
+
+	conv := NewConversation(primary, "system instructions")
+	sc := conv.(*streamingConversation)
+	sc.turns = append(sc.turns,
+		Turn{Role: RoleUser, Parts: []ContentPart{TextContent{Content: "first"}}},
+		Turn{Role: RoleAssistant, ProviderID: "msg_a", Parts: []ContentPart{
+			ReasoningContent{ProviderID: "msg_a/thinking/0", Content: "hmm", ProviderState: "sig"},
+			TextContent{ProviderID: "msg_a/text/1", Content: "answer"},
+		}},
+	)
+	require.NoError(t, conv.AddUserTurn("second"))
+	wantTurns := conv.Turns()
+	wantCacheKey := sc.promptCacheKey
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var retries []error
+	var gotErr error
+	for ev := range conv.SendAsync(ctx) {
+		switch ev.Type {
+		case EventTypeError:
+			gotErr = ev.Error
+		case EventTypeRetry:
+			retries = append(retries, ev.Error)
+		}
+	}
+
+	require.Error(t, gotErr)
+	assert.False(t, isFailoverError(gotErr))
+	assert.Zero(t, uncredentialedRequests)
+	assert.Equal(t, 1, invalidRequests)
+	require.Len(t, retries, 1)
+	assert.Contains(t, retries[0].Error(), "falling back to test-failover-restore-invalid")
+
+	// The failed fallback's translated history is discarded; the conversation is back on the primary with its provider state intact.
+	assert.Equal(t, primary, conv.Model())
+	assert.Equal(t, wantTurns, conv.Turns())
+	assert.Equal(t, wantCacheKey, sc.promptCacheKey)
+}
+
+func TestSendAsync_NoFailoverWithoutFallbacks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	modelID := llmmodel.ModelID("test-failover-none")
+	require.NoError(t, llmmodel.AddCustomModel(modelID, llmmodel.ProviderIDAnthropic, "claude-sonnet-4-6", llmmodel.ModelOverrides{APIActualKey: "test-key", APIEndpointURL: server.URL}))
+
+	conv := NewConversation(modelID, "system")
+	require.NoError(t, conv.AddUserTurn("hi"))
+
+	var gotErr error
+	for ev := range conv.SendAsync(context.Background()) {
+		if ev.Type == EventTypeError {
+			gotErr = ev.Error
+		}
+	}
+	require.Error(t, gotErr)
+	assert.True(t, isFailoverError(gotErr))
+	assert.Equal(t, modelID, conv.(*streamingConversation).modelID)
+}
@@ -401,6 +401,15 @@ func geminiBuildContentFromTurn(turn Turn) (*geminiapi.Content, bool, error) {
 				return nil, false, err
 			}
 			parts = append(parts, part)
+			// Images returned by a tool follow its functionResponse as inline data.
+			for _, img := range typed.Images {
+				parts = append(parts, geminiImagePart(img))
+			}
+		case ImageContent:
+			if turn.Role != RoleUser {
+				return nil, false, fmt.Errorf("images are only supported in user turns (role=%v)", turn.Role)
+			}
+			parts = append(parts, geminiImagePart(typed))
 		default:
 			return nil, false, fmt.Errorf("unsupported content part type: %T", part)
 		}
@@ -448,6 +457,11 @@ func geminiToolCallPart(call ToolCall) (*geminiapi.Part, error) {
 	return &geminiapi.Part{FunctionCall: functionCall}, nil
 }
 
+// geminiImagePart returns img as an inline data part.
+func geminiImagePart(img ImageContent) *geminiapi.Part {
+	return &geminiapi.Part{InlineData: &geminiapi.Blob{MIMEType: img.MediaType, Data: img.Data}}
+}
+
 func geminiToolResultPart(result ToolResult) (*geminiapi.Part, error) {
 	if result.CallID == "" {
 		return nil, errors.New("tool result missing call_id")
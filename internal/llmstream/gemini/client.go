@@ -205,7 +205,7 @@ func (m Models) GenerateContentStream(ctx context.Context, model string, content
 		httpReq.Header.Set("x-goog-api-key", client.apiKey)
 
 		streamClient := sseclient.New(sseclient.WithHTTPClient(client.httpClient))
-		stream, err := streamClient.OpenRequest(httpReq)
+		stream, err := streamClient.Connect(httpReq, streamReconnectOptions)
 		if err != nil {
 			yield(nil, normalizeOpenStreamError(err))
 			return
@@ -237,6 +237,17 @@ func (m Models) GenerateContentStream(ctx context.Context, model string, content
 	}
 }
 
+// streamReconnectOptions retries transient stream failures in place: transport errors, 500/502/504 responses, and streams that drop before their first event.
+// 429 and 503 are left to the caller, which may fail over to another model. streamGenerateContent streams carry no event IDs, so a stream that drops mid-response
+// can't be resumed; the drop is returned rather than replaying the request.
+var streamReconnectOptions = sseclient.ReconnectOptions{
+	InitialDelay:         250 * time.Millisecond,
+	MaxDelay:             2 * time.Second,
+	MaxAttempts:          2,
+	RetryableStatusCodes: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout},
+	RequireEventID:       true,
+}
+
 // The streamGenerateContentRequest type is the JSON body sent to the Gemini streamGenerateContent endpoint.
 type streamGenerateContentRequest struct {
 	Contents          []*Content              `json:"contents,omitempty"`          // Contents are the conversation messages sent to the model.
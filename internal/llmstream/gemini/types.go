@@ -56,6 +56,13 @@ type Part struct {
 	ThoughtSignature []byte            `json:"thoughtSignature,omitempty"` // ThoughtSignature is opaque Gemini state associated with thought content.
 	FunctionCall     *FunctionCall     `json:"functionCall,omitempty"`     // FunctionCall is a tool invocation requested by the model.
 	FunctionResponse *FunctionResponse `json:"functionResponse,omitempty"` // FunctionResponse is a tool result returned to the model.
+	InlineData       *Blob             `json:"inlineData,omitempty"`       // InlineData is inline media, such as an image, sent to the model.
+}
+
+// Blob is inline media data. Data is base64-encoded on the wire.
+type Blob struct {
+	MIMEType string `json:"mimeType"` // MIMEType is the media type, such as "image/png".
+	Data     []byte `json:"data"`     // Data is the raw media bytes.
 }
 
 // FunctionCall represents a model-requested function invocation.
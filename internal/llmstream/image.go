@@ -0,0 +1,199 @@
+package llmstream
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register JPEG for image.DecodeConfig.
+	_ "image/png"  // Register PNG for image.DecodeConfig.
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxImageBytes is the largest image accepted by NewImageContent (3.75 MiB). Anthropic, the strictest supported provider, limits each image's base64 encoding to 5
+// MiB, which holds 3.75 MiB of raw data.
+const MaxImageBytes = 5 * 1024 * 1024 / 4 * 3
+
+// Image media types supported by every provider.
+const (
+	ImageMediaTypePNG  = "image/png"
+	ImageMediaTypeJPEG = "image/jpeg"
+	ImageMediaTypeWebP = "image/webp"
+)
+
+var (
+	// ErrImageTooLarge is returned when an image exceeds MaxImageBytes.
+	ErrImageTooLarge = errors.New("image too large")
+
+	// ErrUnsupportedImageType is returned when image data is not PNG, JPEG, or WebP.
+	ErrUnsupportedImageType = errors.New("unsupported image type")
+)
+
+// Image token estimation constants. They follow Anthropic's published sizing (images are scaled so the long edge is at most 1568px, then cost about w*h/750 tokens),
+// which is within the same order of magnitude as OpenAI and Gemini.
+const (
+	imageTokenMaxEdge        = 1568
+	imageTokenPixelsPerToken = 750
+	imageTokenUnknownSize    = 1600 // Used when dimensions are unknown; roughly the cost of a max-size image.
+)
+
+// ImageContent is an image attached to a user turn, or to a ToolResult.
+type ImageContent struct {
+	MediaType string `json:"media_type"`       // "image/png", "image/jpeg", or "image/webp"
+	Data      []byte `json:"data"`             // raw image bytes (not base64-encoded)
+	Width     int    `json:"width,omitempty"`  // pixels; 0 if unknown
+	Height    int    `json:"height,omitempty"` // pixels; 0 if unknown
+}
+
+// isPart marks ImageContent as a ContentPart.
+func (c ImageContent) isPart() {}
+
+// NewImageContent returns an ImageContent for data, sniffing its media type and dimensions.
+//
+// It returns ErrImageTooLarge if data exceeds MaxImageBytes, and ErrUnsupportedImageType if data is empty or not PNG, JPEG, or WebP.
+func NewImageContent(data []byte) (ImageContent, error) {
+	if len(data) > MaxImageBytes {
+		return ImageContent{}, fmt.Errorf("%w: %d bytes (max %d)", ErrImageTooLarge, len(data), MaxImageBytes)
+	}
+	if len(data) == 0 {
+		return ImageContent{}, fmt.Errorf("%w: empty data", ErrUnsupportedImageType)
+	}
+
+	mediaType := http.DetectContentType(data)
+	switch mediaType {
+	case ImageMediaTypePNG, ImageMediaTypeJPEG, ImageMediaTypeWebP:
+	default:
+		return ImageContent{}, fmt.Errorf("%w: %s", ErrUnsupportedImageType, mediaType)
+	}
+
+	img := ImageContent{MediaType: mediaType, Data: data}
+	if mediaType == ImageMediaTypeWebP {
+		img.Width, img.Height = webpDimensions(data)
+	} else if cfg, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		img.Width, img.Height = cfg.Width, cfg.Height
+	}
+	return img, nil
+}
+
+// ReadImageFile reads the image at path and returns it as an ImageContent. See NewImageContent for validation. Files larger than MaxImageBytes are rejected without
+// being read in full.
+func ReadImageFile(path string) (ImageContent, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return ImageContent{}, err
+	}
+	if fi.IsDir() {
+		return ImageContent{}, fmt.Errorf("%s is a directory", path)
+	}
+	if fi.Size() > MaxImageBytes {
+		return ImageContent{}, fmt.Errorf("%s: %w: %d bytes (max %d)", path, ErrImageTooLarge, fi.Size(), MaxImageBytes)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ImageContent{}, err
+	}
+	img, err := NewImageContent(data)
+	if err != nil {
+		return ImageContent{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return img, nil
+}
+
+// IsImagePath reports whether path has a supported image file extension (.png, .jpg, .jpeg, or .webp; case-insensitive). It does not access the filesystem.
+func IsImagePath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg", ".webp":
+		return true
+	default:
+		return false
+	}
+}
+
+// Base64 returns c.Data encoded with standard base64.
+func (c ImageContent) Base64() string {
+	return base64.StdEncoding.EncodeToString(c.Data)
+}
+
+// DataURL returns c as a data URL (ex: "data:image/png;base64,...").
+func (c ImageContent) DataURL() string {
+	return "data:" + c.MediaType + ";base64," + c.Base64()
+}
+
+// EstimatedTokens returns an approximate input token cost of c. Providers report exact usage after a send; this is for budgeting and display before then.
+func (c ImageContent) EstimatedTokens() int {
+	if c.Width <= 0 || c.Height <= 0 {
+		return imageTokenUnknownSize
+	}
+	w, h := float64(c.Width), float64(c.Height)
+	if longEdge := math.Max(w, h); longEdge > imageTokenMaxEdge {
+		scale := imageTokenMaxEdge / longEdge
+		w, h = w*scale, h*scale
+	}
+	return int(math.Ceil(w * h / imageTokenPixelsPerToken))
+}
+
+// String returns a short human-readable description of c (ex: "image/png 800x600, 12.3 KB").
+func (c ImageContent) String() string {
+	size := fmt.Sprintf("%.1f KB", float64(len(c.Data))/1024)
+	if c.Width > 0 && c.Height > 0 {
+		return fmt.Sprintf("%s %dx%d, %s", c.MediaType, c.Width, c.Height, size)
+	}
+	return fmt.Sprintf("%s, %s", c.MediaType, size)
+}
+
+// validateImageContent returns an error if img cannot be sent to a provider.
+func validateImageContent(img ImageContent) error {
+	switch img.MediaType {
+	case ImageMediaTypePNG, ImageMediaTypeJPEG, ImageMediaTypeWebP:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedImageType, img.MediaType)
+	}
+	if len(img.Data) == 0 {
+		return errors.New("image data is empty")
+	}
+	if len(img.Data) > MaxImageBytes {
+		return fmt.Errorf("%w: %d bytes (max %d)", ErrImageTooLarge, len(img.Data), MaxImageBytes)
+	}
+	return nil
+}
+
+// webpDimensions returns the width and height from a WebP header (lossy VP8, lossless VP8L, or extended VP8X), or zeros if the header is not recognized.
+func webpDimensions(data []byte) (int, int) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0
+	}
+	chunk := data[12:]
+	switch string(chunk[0:4]) {
+	case "VP8 ":
+		// Frame header: 3-byte frame tag, 3-byte start code, then 14-bit width and height.
+		if len(chunk) < 18 || chunk[11] != 0x9d || chunk[12] != 0x01 || chunk[13] != 0x2a {
+			return 0, 0
+		}
+		w := int(binary.LittleEndian.Uint16(chunk[14:16]) & 0x3fff)
+		h := int(binary.LittleEndian.Uint16(chunk[16:18]) & 0x3fff)
+		return w, h
+	case "VP8L":
+		// Signature byte, then 14-bit width-1 and 14-bit height-1.
+		if len(chunk) < 13 || chunk[8] != 0x2f {
+			return 0, 0
+		}
+		bits := binary.LittleEndian.Uint32(chunk[9:13])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1
+	case "VP8X":
+		// Flags and reserved bytes, then 24-bit canvas width-1 and height-1.
+		if len(chunk) < 18 {
+			return 0, 0
+		}
+		w := int(chunk[12]) | int(chunk[13])<<8 | int(chunk[14])<<16
+		h := int(chunk[15]) | int(chunk[16])<<8 | int(chunk[17])<<16
+		return w + 1, h + 1
+	default:
+		return 0, 0
+	}
+}
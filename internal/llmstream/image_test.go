@@ -0,0 +1,197 @@
+package llmstream
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+func testImage(t *testing.T) ImageContent {
+	t.Helper()
+
+	img, err := NewImageContent(testPNG(t, 4, 3))
+	require.NoError(t, err)
+	return img
+}
+
+func TestNewImageContent(t *testing.T) {
+	t.Run("png with dimensions", func(t *testing.T) {
+		img, err := NewImageContent(testPNG(t, 40, 30))
+		require.NoError(t, err)
+		assert.Equal(t, ImageMediaTypePNG, img.MediaType)
+		assert.Equal(t, 40, img.Width)
+		assert.Equal(t, 30, img.Height)
+	})
+	t.Run("webp lossless dimensions", func(t *testing.T) {
+		// 1x1 lossless WebP.
+		data := []byte{
+			'R', 'I', 'F', 'F', 0x1a, 0, 0, 0, 'W', 'E', 'B', 'P',
+			'V', 'P', '8', 'L', 0x0d, 0, 0, 0, 0x2f, 0, 0, 0, 0x10, 0x07, 0x10, 0x11, 0x11, 0x88, 0x88, 0xfe, 0x07, 0x00,
+		}
+		img, err := NewImageContent(data)
+		require.NoError(t, err)
+		assert.Equal(t, ImageMediaTypeWebP, img.MediaType)
+		assert.Equal(t, 1, img.Width)
+		assert.Equal(t, 1, img.Height)
+	})
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := NewImageContent([]byte("GIF89a not really"))
+		assert.True(t, errors.Is(err, ErrUnsupportedImageType))
+
+		_, err = NewImageContent(nil)
+		assert.True(t, errors.Is(err, ErrUnsupportedImageType))
+	})
+	t.Run("too large", func(t *testing.T) {
+		_, err := NewImageContent(make([]byte, MaxImageBytes+1))
+		assert.True(t, errors.Is(err, ErrImageTooLarge))
+	})
+	t.Run("base64 fits provider limit", func(t *testing.T) {
+		assert.LessOrEqual(t, base64.StdEncoding.EncodedLen(MaxImageBytes), 5*1024*1024)
+	})
+}
+
+func TestReadImageFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "shot.png")
+	require.NoError(t, os.WriteFile(path, testPNG(t, 8, 8), 0o644))
+
+	img, err := ReadImageFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 8, img.Width)
+
+	textPath := filepath.Join(dir, "notes.png")
+	require.NoError(t, os.WriteFile(textPath, []byte("hello"), 0o644))
+	_, err = ReadImageFile(textPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), textPath)
+	assert.True(t, errors.Is(err, ErrUnsupportedImageType))
+}
+
+func TestIsImagePath(t *testing.T) {
+	assert.True(t, IsImagePath("a/b.PNG"))
+	assert.True(t, IsImagePath("x.jpeg"))
+	assert.True(t, IsImagePath("x.webp"))
+	assert.False(t, IsImagePath("x.gif"))
+	assert.False(t, IsImagePath("png"))
+}
+
+func TestImageContentEstimatedTokens(t *testing.T) {
+	assert.Equal(t, 1, ImageContent{Width: 10, Height: 10}.EstimatedTokens())
+	assert.Equal(t, 1334, ImageContent{Width: 1000, Height: 1000}.EstimatedTokens())
+	// Scaled to 1568x784.
+	assert.Equal(t, 1640, ImageContent{Width: 3136, Height: 1568}.EstimatedTokens())
+	assert.Equal(t, imageTokenUnknownSize, ImageContent{}.EstimatedTokens())
+}
+
+func TestAddUserTurnWithImages(t *testing.T) {
+	img := testImage(t)
+
+	sc := NewConversation(llmmodel.ModelID("gpt-4o-mini"), "sys").(*streamingConversation)
+	require.NoError(t, sc.AddUserTurnWithImages("look", []ImageContent{img}))
+	last := sc.LastTurn()
+	require.Len(t, last.Parts, 2)
+	assert.Equal(t, TextContent{Content: "look"}, last.Parts[0])
+	assert.Equal(t, img, last.Parts[1])
+
+	sc = NewConversation(llmmodel.ModelID("gpt-4o-mini"), "sys").(*streamingConversation)
+	require.NoError(t, sc.AddUserTurnWithImages("", []ImageContent{img}))
+	require.Len(t, sc.LastTurn().Parts, 1)
+
+	err := sc.AddUserTurnWithImages("bad", []ImageContent{{MediaType: "image/gif", Data: []byte("x")}})
+	assert.True(t, errors.Is(err, ErrUnsupportedImageType))
+}
+
+func TestBuildOpenAIResponsesRequestParams_Images(t *testing.T) {
+	img := testImage(t)
+	sc := NewConversation(llmmodel.ModelID("gpt-4o-mini"), "sys").(*streamingConversation)
+	require.NoError(t, sc.AddUserTurnWithImages("what is this", []ImageContent{img, img}))
+	sc.turns = append(sc.turns, Turn{
+		Role:  RoleAssistant,
+		Parts: []ContentPart{ToolCall{CallID: "call_1", Name: "read_image", Type: "function_call", Input: "{}"}},
+	})
+	sc.turns = append(sc.turns, Turn{
+		Role:  RoleUser,
+		Parts: []ContentPart{ToolResult{CallID: "call_1", Name: "read_image", Type: "function_call", Result: "shot.png", Images: []ImageContent{img}}},
+	})
+
+	params, err := sc.buildOpenAIResponsesRequestParams(openAIRequestShapeModelInfo(), &SendOptions{NoStore: true})
+	require.NoError(t, err)
+	req, _ := mustMarshalOpenAIResponsesRequest(t, params)
+	input := openAIResponsesRequestInput(t, req)
+	require.Len(t, input, 3)
+
+	message := input[0].(map[string]any)
+	content := message["content"].([]any)
+	require.Len(t, content, 3)
+	assert.Equal(t, "input_text", content[0].(map[string]any)["type"])
+	assert.Equal(t, "input_image", content[1].(map[string]any)["type"])
+	assert.Equal(t, img.DataURL(), content[1].(map[string]any)["image_url"])
+
+	output := input[2].(map[string]any)["output"].([]any)
+	require.Len(t, output, 2)
+	assert.Equal(t, "shot.png", output[0].(map[string]any)["text"])
+	assert.Equal(t, "input_image", output[1].(map[string]any)["type"])
+}
+
+func TestAnthropicBuildMessageParam_Images(t *testing.T) {
+	img := testImage(t)
+
+	msg, include, err := anthropicBuildMessageParam(Turn{Role: RoleUser, Parts: []ContentPart{TextContent{Content: "hi"}, img}})
+	require.NoError(t, err)
+	require.True(t, include)
+	require.Len(t, msg.Content, 2)
+	assert.Equal(t, "image", msg.Content[1].Type)
+	require.NotNil(t, msg.Content[1].Source)
+	assert.Equal(t, img.Base64(), msg.Content[1].Source.Data)
+
+	msg, _, err = anthropicBuildMessageParam(Turn{Role: RoleUser, Parts: []ContentPart{
+		ToolResult{CallID: "toolu_1", Name: "read_image", Result: "shot.png", Images: []ImageContent{img}},
+	}})
+	require.NoError(t, err)
+	data, err := json.Marshal(msg.Content[0])
+	require.NoError(t, err)
+	var block map[string]any
+	require.NoError(t, json.Unmarshal(data, &block))
+	content := block["content"].([]any)
+	require.Len(t, content, 2)
+	assert.Equal(t, "text", content[0].(map[string]any)["type"])
+	assert.Equal(t, "image", content[1].(map[string]any)["type"])
+
+	_, _, err = anthropicBuildMessageParam(Turn{Role: RoleAssistant, Parts: []ContentPart{img}})
+	assert.Error(t, err)
+}
+
+func TestGeminiBuildContentFromTurn_Images(t *testing.T) {
+	img := testImage(t)
+
+	content, include, err := geminiBuildContentFromTurn(Turn{Role: RoleUser, Parts: []ContentPart{
+		img,
+		ToolResult{CallID: "call_1", Name: "read_image", Result: "shot.png", Images: []ImageContent{img}},
+	}})
+	require.NoError(t, err)
+	require.True(t, include)
+	require.Len(t, content.Parts, 3)
+	require.NotNil(t, content.Parts[0].InlineData)
+	assert.Equal(t, ImageMediaTypePNG, content.Parts[0].InlineData.MIMEType)
+	require.NotNil(t, content.Parts[1].FunctionResponse)
+	require.NotNil(t, content.Parts[2].InlineData)
+	assert.Equal(t, img.Data, content.Parts[2].InlineData.Data)
+}
@@ -56,6 +56,9 @@ type StreamingConversation interface {
 	// Treat the returned slice and its contents as read-only.
 	Turns() []Turn
 
+	// Model returns the model used for sends. It changes when a send fails over to a fallback model (see llmmodel.SetFallbackModels).
+	Model() llmmodel.ModelID
+
 	// AddTools adds tools available to the model.
 	//
 	// It returns an error for an empty list or blank tool names. Adding a tool with an existing name replaces the previous tool.
@@ -66,6 +69,11 @@ type StreamingConversation interface {
 	// It returns an error when the previous assistant turn contains unresolved tool calls; add matching tool results first.
 	AddUserTurn(text string) error
 
+	// AddUserTurnWithImages appends a user turn containing text (if non-empty) followed by images.
+	//
+	// It returns an error under the same conditions as AddUserTurn, or if any image is invalid (see NewImageContent).
+	AddUserTurnWithImages(text string, images []ImageContent) error
+
 	// AddToolResults appends a user turn containing tool results.
 	//
 	// The previous turn must be an assistant turn with tool calls, and each result must match a prior call by call ID, name, and type.
@@ -132,6 +140,11 @@ func (sc *streamingConversation) Turns() []Turn {
 	return sc.turns
 }
 
+// Model returns the model used for sends.
+func (sc *streamingConversation) Model() llmmodel.ModelID {
+	return sc.modelID
+}
+
 // AddTools adds tools to the conversation. It returns an error for an empty list or any tool with a blank name; tools with the same name replace earlier tools.
 func (sc *streamingConversation) AddTools(tools []Tool) error {
 	if len(tools) == 0 {
@@ -161,11 +174,29 @@ func (sc *streamingConversation) AddTools(tools []Tool) error {
 //
 // It returns an error if the current last turn contains tool calls; add matching tool results with AddToolResults before adding another user message.
 func (sc *streamingConversation) AddUserTurn(text string) error {
+	return sc.AddUserTurnWithImages(text, nil)
+}
+
+// AddUserTurnWithImages appends a user turn with text (omitted when empty and images are present) followed by images.
+//
+// It returns an error if the current last turn contains tool calls, or if any image has an unsupported media type, no data, or exceeds MaxImageBytes.
+func (sc *streamingConversation) AddUserTurnWithImages(text string, images []ImageContent) error {
 	lastTurn := sc.LastTurn()
 	if len(lastTurn.ToolCalls()) > 0 {
 		return errors.New("previous message had tool calls - cannot add new user message. Use AddToolResults first")
 	}
 	turn := newTextTurn(RoleUser, text)
+	if len(images) > 0 {
+		if text == "" {
+			turn.Parts = nil
+		}
+		for i, img := range images {
+			if err := validateImageContent(img); err != nil {
+				return fmt.Errorf("image %d: %w", i, err)
+			}
+			turn.Parts = append(turn.Parts, img)
+		}
+	}
 	sc.turns = append(sc.turns, turn)
 	if sc.usesGeminiAPI() {
 		content, include, err := geminiBuildContentFromTurn(turn)
@@ -229,6 +260,11 @@ func (sc *streamingConversation) AddToolResults(toolResults []ToolResult) error
 		if tr.Type != tc.Type {
 			return fmt.Errorf("tool result %s has type %q which does not match tool call type %q", tr.CallID, tr.Type, tc.Type)
 		}
+		for i, img := range tr.Images {
+			if err := validateImageContent(img); err != nil {
+				return fmt.Errorf("tool result %s image %d: %w", tr.CallID, i, err)
+			}
+		}
 		matched[tr.CallID] = true
 		parts = append(parts, tr)
 	}
@@ -279,6 +315,9 @@ func (sc *streamingConversation) AddToolResults(toolResults []ToolResult) error
 //
 // Errors may be retried. If a network issue (or other retryable error) happens mid-stream, an EventTypeRetry event will be sent and the same output channel will
 // be used to try again.
+//
+// If the provider is rate limited, overloaded, or out of quota, the send fails over to the model's llmmodel.FallbackModels in order (each attempt preceded by an
+// EventTypeRetry event). The history is translated to the fallback's provider shape, and the conversation stays on the fallback that succeeds.
 func (sc *streamingConversation) SendAsync(ctx context.Context, options ...SendOptions) <-chan Event {
 	out := make(chan Event, 1024) // NOTE: I am somewhat concerned about this. If each letter in the output gets a delta event, that's a lot of events
 	go func() {
@@ -320,62 +359,15 @@ func (sc *streamingConversation) SendAsync(ctx context.Context, options ...SendO
 			out <- newErrorEvent(sc.LogNewErr("conversation.provider.unknown", "model_id", string(sc.modelID)))
 			return
 		}
-		var sendAsync func(context.Context, chan Event, *SendOptions, llmmodel.ModelInfo) (Turn, error)
-		switch {
-		case modelSupportsAPIType(modelInfo, llmmodel.ProviderTypeOpenAIResponses):
-			sendAsync = sc.sendAsyncOpenAIResponses
-		case modelSupportsAPIType(modelInfo, llmmodel.ProviderTypeAnthropic):
-			sendAsync = sc.sendAsyncAnthropic
-		case modelSupportsAPIType(modelInfo, llmmodel.ProviderTypeGemini):
-			sendAsync = sc.sendAsyncGemini
-		default:
+		sendAsync := sc.sendFuncForModel(modelInfo)
+		if sendAsync == nil {
 			out <- newErrorEvent(sc.LogNewErr("conversation.model.unsupported_api", "model_id", string(sc.modelID), "provider", modelInfo.ProviderID, "required_api", "openai_responses|anthropic|gemini"))
 			return
 		}
 
-		var newTurn Turn
-		var err error
-
-		const retryMaxAttempts = 3
-
-		for attempt := 1; attempt <= retryMaxAttempts; attempt++ {
-			newTurn, err = sendAsync(ctx, out, opt, modelInfo)
-
-			if err == nil {
-				break
-			}
-
-			if isRetryable(err) && attempt < retryMaxAttempts {
-				sleep := 0 * time.Millisecond
-				if (attempt - 1) < len(retrySleepDurations) {
-					sleep = retrySleepDurations[attempt-1]
-				} else {
-					sleep = retrySleepDurations[len(retrySleepDurations)-1]
-				}
-				sc.Log("conversation.retry", "attempt", attempt, "max", retryMaxAttempts, "sleep", sleep, "err", err.Error())
-
-				timer := time.NewTimer(sleep)
-				ctxCancelled := false
-				select {
-				case <-timer.C:
-				case <-ctx.Done():
-					timer.Stop()
-					err = sc.LogWrappedErr("conversation.retry", ctx.Err())
-					ctxCancelled = true
-				}
-
-				// NOTE: a break inside the above select breaks out of the select. So we need to re-detect the ctx err and break out of loop.
-				if ctxCancelled {
-					break
-				}
-
-				trySendEvent(ctx, out, Event{Type: EventTypeRetry, Error: err})
-				sc.Log("conversation.retry", "attempt", attempt, "max", retryMaxAttempts, "sleep", sleep, "err", err.Error())
-				continue
-			}
-
-			// Not retryable or out of attempts
-			break
+		newTurn, err := sc.sendWithRetries(ctx, out, opt, modelInfo, sendAsync)
+		if isFailoverError(err) {
+			newTurn, err = sc.sendWithFallbacks(ctx, out, opt, err)
 		}
 
 		if err != nil {
@@ -399,6 +391,74 @@ func (sc *streamingConversation) SendAsync(ctx context.Context, options ...SendO
 	return out
 }
 
+// sendFunc sends the conversation's current state to one provider API and returns the new assistant turn.
+type sendFunc func(context.Context, chan Event, *SendOptions, llmmodel.ModelInfo) (Turn, error)
+
+// sendFuncForModel returns the provider send function for the model described by info, or nil if none of its API types are supported.
+func (sc *streamingConversation) sendFuncForModel(info llmmodel.ModelInfo) sendFunc {
+	switch {
+	case modelSupportsAPIType(info, llmmodel.ProviderTypeOpenAIResponses):
+		return sc.sendAsyncOpenAIResponses
+	case modelSupportsAPIType(info, llmmodel.ProviderTypeAnthropic):
+		return sc.sendAsyncAnthropic
+	case modelSupportsAPIType(info, llmmodel.ProviderTypeGemini):
+		return sc.sendAsyncGemini
+	default:
+		return nil
+	}
+}
+
+// sendWithRetries calls sendAsync, retrying retryable errors with backoff (emitting EventTypeRetry before each retry). It returns the new assistant turn or the
+// last error.
+func (sc *streamingConversation) sendWithRetries(ctx context.Context, out chan Event, opt *SendOptions, modelInfo llmmodel.ModelInfo, sendAsync sendFunc) (Turn, error) {
+	var newTurn Turn
+	var err error
+
+	const retryMaxAttempts = 3
+
+	for attempt := 1; attempt <= retryMaxAttempts; attempt++ {
+		newTurn, err = sendAsync(ctx, out, opt, modelInfo)
+
+		if err == nil {
+			break
+		}
+
+		if isRetryable(err) && attempt < retryMaxAttempts {
+			sleep := 0 * time.Millisecond
+			if (attempt - 1) < len(retrySleepDurations) {
+				sleep = retrySleepDurations[attempt-1]
+			} else {
+				sleep = retrySleepDurations[len(retrySleepDurations)-1]
+			}
+			sc.Log("conversation.retry", "attempt", attempt, "max", retryMaxAttempts, "sleep", sleep, "err", err.Error())
+
+			timer := time.NewTimer(sleep)
+			ctxCancelled := false
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				err = sc.LogWrappedErr("conversation.retry", ctx.Err())
+				ctxCancelled = true
+			}
+
+			// NOTE: a break inside the above select breaks out of the select. So we need to re-detect the ctx err and break out of loop.
+			if ctxCancelled {
+				break
+			}
+
+			trySendEvent(ctx, out, Event{Type: EventTypeRetry, Error: err})
+			sc.Log("conversation.retry", "attempt", attempt, "max", retryMaxAttempts, "sleep", sleep, "err", err.Error())
+			continue
+		}
+
+		// Not retryable or out of attempts
+		break
+	}
+
+	return newTurn, err
+}
+
 func modelSupportsAPIType(info llmmodel.ModelInfo, apiType llmmodel.ProviderAPIType) bool {
 	for _, t := range info.SupportedTypes {
 		if t == apiType {
@@ -852,13 +852,18 @@ func (sc *streamingConversation) buildOpenAIResponsesParams(modelInfo llmmodel.M
 		// Collect all text parts. A text part maps to a message, for a single msg on our side, we only want to make one message on their side.
 		// I have no idea these parts exist in practice, but in theory they could be interleaved in msg.Parts. So the first part we see, we write a message with all text parts.
 		// Note that we want to insert this message in the order it goes based on msg.parts, so we can't just dump in the message first.
+		// Images attach to the same message, after the text.
 		var allTextParts []TextContent
+		var allImageParts []ImageContent
 		for _, part := range partsToEncode {
 			switch tpart := part.(type) {
 			case TextContent:
 				allTextParts = append(allTextParts, tpart)
+			case ImageContent:
+				allImageParts = append(allImageParts, tpart)
 			}
 		}
+		messageAdded := false
 
 		// We need to group reasoning parts by ID, b/c that's Responses data model.
 		idToReasoningParts := map[string][]ReasoningContent{}
@@ -874,8 +879,15 @@ func (sc *streamingConversation) buildOpenAIResponsesParams(modelInfo llmmodel.M
 
 		for _, part := range partsToEncode {
 			switch tpart := part.(type) {
-			case TextContent:
-				contentList := make(responses.ResponseInputMessageContentListParam, 0, len(allTextParts))
+			case TextContent, ImageContent:
+				if messageAdded {
+					continue
+				}
+				messageAdded = true
+				if len(allImageParts) > 0 && resp.Role != RoleUser {
+					return responses.ResponseNewParams{}, fmt.Errorf("images are only supported in user turns (role=%v)", resp.Role)
+				}
+				contentList := make(responses.ResponseInputMessageContentListParam, 0, len(allTextParts)+len(allImageParts))
 				for _, tp := range allTextParts {
 					paramUnion := responses.ResponseInputContentParamOfInputText(tp.Content)
 					if textParam := paramUnion.OfInputText; textParam != nil {
@@ -887,6 +899,9 @@ func (sc *streamingConversation) buildOpenAIResponsesParams(modelInfo llmmodel.M
 					}
 					contentList = append(contentList, paramUnion)
 				}
+				for _, img := range allImageParts {
+					contentList = append(contentList, responses.ResponseInputContentUnionParam{OfInputImage: openAIResponsesInputImage(img)})
+				}
 				message := responses.EasyInputMessageParam{
 					Role:    openaiResponesMapMessageRole(resp.Role),
 					Type:    "message",
@@ -924,10 +939,16 @@ func (sc *streamingConversation) buildOpenAIResponsesParams(modelInfo llmmodel.M
 				switch tpart.Type {
 				case "function_call":
 					outUnion := responses.ResponseInputItemFunctionCallOutputOutputUnionParam{OfString: param.NewOpt(tpart.Result)}
+					if len(tpart.Images) > 0 {
+						outUnion = responses.ResponseInputItemFunctionCallOutputOutputUnionParam{OfResponseFunctionCallOutputItemArray: openAIResponsesFunctionCallOutputItems(tpart)}
+					}
 					item := responses.ResponseInputItemFunctionCallOutputParam{CallID: tpart.CallID, Output: outUnion}
 					inputItems = append(inputItems, responses.ResponseInputItemUnionParam{OfFunctionCallOutput: &item})
 				case "custom_tool_call":
 					outUnion := responses.ResponseCustomToolCallOutputOutputUnionParam{OfString: param.NewOpt(tpart.Result)}
+					if len(tpart.Images) > 0 {
+						outUnion = responses.ResponseCustomToolCallOutputOutputUnionParam{OfOutputContentList: openAIResponsesCustomToolCallOutputItems(tpart)}
+					}
 					item := responses.ResponseCustomToolCallOutputParam{CallID: tpart.CallID, Type: "custom_tool_call_output", Output: outUnion}
 					inputItems = append(inputItems, responses.ResponseInputItemUnionParam{OfCustomToolCallOutput: &item})
 				default:
@@ -987,6 +1008,37 @@ func (sc *streamingConversation) buildOpenAIResponsesParams(modelInfo llmmodel.M
 	return req, nil
 }
 
+// openAIResponsesInputImage returns img as an inline (data URL) input image.
+func openAIResponsesInputImage(img ImageContent) *responses.ResponseInputImageParam {
+	return &responses.ResponseInputImageParam{
+		Detail:   responses.ResponseInputImageDetailAuto,
+		ImageURL: param.NewOpt(img.DataURL()),
+	}
+}
+
+// openAIResponsesFunctionCallOutputItems returns tr's result text followed by its images as function_call_output content items.
+func openAIResponsesFunctionCallOutputItems(tr ToolResult) responses.ResponseFunctionCallOutputItemListParam {
+	items := make(responses.ResponseFunctionCallOutputItemListParam, 0, len(tr.Images)+1)
+	items = append(items, responses.ResponseFunctionCallOutputItemUnionParam{OfInputText: &responses.ResponseInputTextContentParam{Text: tr.Result}})
+	for _, img := range tr.Images {
+		items = append(items, responses.ResponseFunctionCallOutputItemUnionParam{OfInputImage: &responses.ResponseInputImageContentParam{
+			Detail:   responses.ResponseInputImageContentDetailAuto,
+			ImageURL: param.NewOpt(img.DataURL()),
+		}})
+	}
+	return items
+}
+
+// openAIResponsesCustomToolCallOutputItems returns tr's result text followed by its images as custom_tool_call_output content items.
+func openAIResponsesCustomToolCallOutputItems(tr ToolResult) []responses.ResponseCustomToolCallOutputOutputOutputContentListItemUnionParam {
+	items := make([]responses.ResponseCustomToolCallOutputOutputOutputContentListItemUnionParam, 0, len(tr.Images)+1)
+	items = append(items, responses.ResponseCustomToolCallOutputOutputOutputContentListItemUnionParam{OfInputText: &responses.ResponseInputTextParam{Text: tr.Result}})
+	for _, img := range tr.Images {
+		items = append(items, responses.ResponseCustomToolCallOutputOutputOutputContentListItemUnionParam{OfInputImage: openAIResponsesInputImage(img)})
+	}
+	return items
+}
+
 func openAIResponsesLatestCompactionPosition(turns []Turn) (turnIdx int, partIdx int, ok bool) {
 	for i := len(turns) - 1; i >= 0; i-- {
 		for j := len(turns[i].Parts) - 1; j >= 0; j-- {
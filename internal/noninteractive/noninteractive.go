@@ -22,6 +22,7 @@ import (
 	"github.com/codalotl/codalotl/internal/lints"
 	"github.com/codalotl/codalotl/internal/llmmodel"
 	"github.com/codalotl/codalotl/internal/llmstream"
+	"github.com/codalotl/codalotl/internal/slashcommands"
 	"github.com/codalotl/codalotl/internal/tools/authdomain"
 	"github.com/codalotl/codalotl/internal/tools/toolsetinterface"
 	"golang.org/x/term"
@@ -72,10 +73,14 @@ type Options struct {
 	// "foo/bar"; "./foo/bar"). It must be rooted inside of CWD.
 	PackagePath string
 
-	// SlashCommand applies a TUI-style slash command at session start. Supported values are "", "orchestrate", and "/orchestrate".
+	// SlashCommand applies a TUI-style slash command at session start. Supported values are "", "orchestrate", or the name of a custom command from `.codalotl/commands`
+	// (see package slashcommands), each with or without a leading "/".
 	//
 	// "orchestrate" and "/orchestrate" start a fresh generic-mode orchestrator session around the built-in orchestrator agent, matching the TUI's `/orchestrate` behavior.
 	// PackagePath is ignored for orchestrate mode.
+	//
+	// A custom command is loaded from slashcommands.SearchPaths(CWD). Its front matter may select the agent, mode, model (overriding ModelID), and allowed tools. The
+	// first user message is used as the command's arguments and replaced by the rendered command prompt; it may be empty.
 	SlashCommand string
 
 	// ModelID selects the LLM model for this run. If empty or whitespace, uses the existing default model behavior. Non-empty values are trimmed and used as provided;
@@ -85,6 +90,10 @@ type Options struct {
 	// LintSteps controls which lint steps the agent runs.
 	LintSteps []lints.Step
 
+	// ImagePaths are image files (PNG, JPEG, or WebP) attached to Exec's user message. Relative paths are resolved against CWD (or the process working directory
+	// if CWD is "").
+	ImagePaths []string
+
 	// Answers 'Yes' to any permission check. If false, we answer 'No' to any permission check. The end-user is never asked.
 	AutoYes bool
 
@@ -118,20 +127,22 @@ var newSessionForExec = NewSession
 
 // A sessionStart identifies the agent variant used to construct a session agent.
 type sessionStart struct {
-	agentName string // It is the registry name used to prepare the agent.
-	pkgMode   bool   // It reports whether package-mode environment and tool options should be used.
+	agentName    string   // It is the registry name used to prepare the agent.
+	pkgMode      bool     // It reports whether package-mode environment and tool options should be used.
+	allowedTools []string // If non-nil, it restricts the agent's tools to these names.
 }
 
+// normalizeSlashCommand trims slashCommand and removes a leading "/". It returns an error if the remainder is not a valid command name.
 func normalizeSlashCommand(slashCommand string) (string, error) {
 	original := strings.TrimSpace(slashCommand)
-	switch original {
-	case "", slashCommandOrchestrate:
-		return original, nil
-	case "/" + slashCommandOrchestrate:
-		return slashCommandOrchestrate, nil
-	default:
+	if original == "" {
+		return "", nil
+	}
+	name, args, ok := slashcommands.ParseInvocation("/" + strings.TrimPrefix(original, "/"))
+	if !ok || args != "" {
 		return "", fmt.Errorf("unsupported slash command %q", original)
 	}
+	return name, nil
 }
 
 // A lockedWriter serializes writes to a shared output writer.
@@ -409,6 +420,11 @@ func shouldTrackTerminalError(ev agent.Event) bool {
 // to read non-existant files; shell commands will fail; etc. These do not typically constitute errors worthy of being returned (instead, the LLM is just told a
 // file doesn't exist).
 func Exec(userPrompt string, opts Options) error {
+	images, err := loadImages(opts.CWD, opts.ImagePaths)
+	if err != nil {
+		return err
+	}
+
 	session, err := newSessionForExec(opts)
 	if err != nil {
 		return err
@@ -420,10 +436,26 @@ func Exec(userPrompt string, opts Options) error {
 	runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
-	_, err = session.SendUserMessage(runCtx, userPrompt)
+	_, err = session.SendUserMessageWithImages(runCtx, userPrompt, images)
 	return err
 }
 
+// loadImages reads the image files at paths, resolving relative paths against cwd (or the process working directory if cwd is "").
+func loadImages(cwd string, paths []string) ([]llmstream.ImageContent, error) {
+	images := make([]llmstream.ImageContent, 0, len(paths))
+	for _, path := range paths {
+		if !filepath.IsAbs(path) && cwd != "" {
+			path = filepath.Join(cwd, path)
+		}
+		img, err := llmstream.ReadImageFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read image: %w", err)
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
 // A grantsAdder adds authorization grants from a user message to an authorizer.
 type grantsAdder func(authorizer authdomain.Authorizer, userMessage string) error
 
@@ -451,6 +483,11 @@ func (c *turnSnapshotConversation) Turns() []llmstream.Turn {
 	return c.turns
 }
 
+// Model returns "" because the snapshot is not bound to a model.
+func (c *turnSnapshotConversation) Model() llmmodel.ModelID {
+	return ""
+}
+
 // AddTools rejects tool additions because the snapshot is read-only.
 func (c *turnSnapshotConversation) AddTools(_ []llmstream.Tool) error {
 	return errors.New("turn snapshot conversation is read-only")
@@ -461,6 +498,11 @@ func (c *turnSnapshotConversation) AddUserTurn(_ string) error {
 	return errors.New("turn snapshot conversation is read-only")
 }
 
+// AddUserTurnWithImages rejects user-turn additions because the snapshot is read-only.
+func (c *turnSnapshotConversation) AddUserTurnWithImages(_ string, _ []llmstream.ImageContent) error {
+	return errors.New("turn snapshot conversation is read-only")
+}
+
 // AddToolResults rejects tool-result additions because the snapshot is read-only.
 func (c *turnSnapshotConversation) AddToolResults(_ []llmstream.ToolResult) error {
 	return errors.New("turn snapshot conversation is read-only")
@@ -843,6 +885,7 @@ func buildAgent(start sessionStart, sandboxDir string, pkgRelPath string, pkgAbs
 	if err != nil {
 		return nil, fmt.Errorf("prepare agent: %w", err)
 	}
+	prepared.RestrictTools(start.allowedTools)
 
 	envMsg := buildEnvironmentInfo(sandboxDir)
 	if start.pkgMode {
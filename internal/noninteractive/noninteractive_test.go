@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"image"
+	"image/png"
 	"io"
 	"os"
 	"path/filepath"
@@ -18,6 +20,7 @@ import (
 	"github.com/codalotl/codalotl/internal/agentformatter"
 	"github.com/codalotl/codalotl/internal/llmmodel"
 	"github.com/codalotl/codalotl/internal/llmstream"
+	"github.com/codalotl/codalotl/internal/slashcommands"
 	"github.com/codalotl/codalotl/internal/tools/authdomain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -163,6 +166,44 @@ func TestBuildSessionConfig(t *testing.T) {
 	})
 }
 
+func TestBuildSessionConfig_CustomCommand(t *testing.T) {
+	t.Parallel()
+
+	cwd := t.TempDir()
+	commandsDir := filepath.Join(cwd, ".codalotl", "commands")
+	require.NoError(t, os.MkdirAll(commandsDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(commandsDir, "review.md"), []byte("---\nmode: package\nallowed-tools: [read_file]\n---\nReview {{.Package}}: {{.Args}}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(commandsDir, "plan.md"), []byte("---\nmode: generic\nagent: pr-orchestrator\n---\nPlan it.\n"), 0o644))
+
+	t.Run("package command keeps package mode", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := buildSessionConfig(Options{CWD: cwd, PackagePath: "pkg", SlashCommand: "/review"})
+		require.NoError(t, err)
+		require.Equal(t, agentbuilder.AgentPackageModeNoContext, config.agentName)
+		require.True(t, config.pkgMode)
+		require.True(t, config.allowEmptyInitialUser)
+		require.NotNil(t, config.command)
+		require.Equal(t, []string{"read_file"}, config.command.AllowedTools)
+	})
+
+	t.Run("package command requires package path", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := buildSessionConfig(Options{CWD: cwd, SlashCommand: "review"})
+		require.EqualError(t, err, `slash command "review" requires package mode`)
+	})
+
+	t.Run("generic command with agent", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := buildSessionConfig(Options{CWD: cwd, PackagePath: "pkg", SlashCommand: "plan"})
+		require.NoError(t, err)
+		require.Equal(t, "pr-orchestrator", config.agentName)
+		require.False(t, config.pkgMode)
+	})
+}
+
 func TestBuildSessionConfig_ModeSelection(t *testing.T) {
 	t.Parallel()
 
@@ -767,11 +808,17 @@ type fakeSessionSend struct {
 type fakeSessionAgent struct {
 	sends    []fakeSessionSend
 	messages []string
+	images   [][]llmstream.ImageContent
 	call     int
 }
 
-func (a *fakeSessionAgent) SendUserMessage(_ context.Context, message string) <-chan agent.Event {
+func (a *fakeSessionAgent) SendUserMessage(ctx context.Context, message string) <-chan agent.Event {
+	return a.SendUserMessageWithImages(ctx, message, nil)
+}
+
+func (a *fakeSessionAgent) SendUserMessageWithImages(_ context.Context, message string, images []llmstream.ImageContent) <-chan agent.Event {
 	a.messages = append(a.messages, message)
+	a.images = append(a.images, images)
 
 	if a.call >= len(a.sends) {
 		ch := make(chan agent.Event)
@@ -1150,6 +1197,56 @@ func TestSessionSendUserMessageRequiresPromptOutsideInitialOrchestrateStep(t *te
 	require.Empty(t, buf.String())
 }
 
+func TestSessionSendUserMessageWithImagesAllowsEmptyPrompt(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	fake := &fakeSessionAgent{}
+	session := newTestSession(Options{OutputJSON: true}, fake, &buf)
+	img := llmstream.ImageContent{MediaType: llmstream.ImageMediaTypePNG, Data: []byte("png")}
+
+	_, err := session.SendUserMessageWithImages(context.Background(), "", []llmstream.ImageContent{img})
+	require.NoError(t, err)
+	require.Equal(t, []string{""}, fake.messages)
+	require.Equal(t, [][]llmstream.ImageContent{{img}}, fake.images)
+}
+
+func TestSessionSendUserMessageRendersCustomCommandOnFirstStep(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	fake := &fakeSessionAgent{}
+	session := newTestSession(Options{OutputJSON: true}, fake, &buf)
+	session.startInfo.pkgRelPath = "internal/foo"
+	session.config = sessionConfig{
+		allowEmptyInitialUser: true,
+		command:               &slashcommands.Command{Name: "review", Body: "Review {{.Package}}."},
+	}
+
+	_, err := session.SendUserMessage(context.Background(), "focus on errors")
+	require.NoError(t, err)
+	_, err = session.SendUserMessage(context.Background(), "thanks")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Review internal/foo.\n\nfocus on errors", "thanks"}, fake.messages)
+}
+
+func TestLoadImagesResolvesRelativeToCWD(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	var pngData bytes.Buffer
+	require.NoError(t, png.Encode(&pngData, image.NewRGBA(image.Rect(0, 0, 2, 2))))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shot.png"), pngData.Bytes(), 0o644))
+
+	images, err := loadImages(dir, []string{"shot.png"})
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	require.Equal(t, llmstream.ImageMediaTypePNG, images[0].MediaType)
+
+	_, err = loadImages(dir, []string{"missing.png"})
+	require.ErrorContains(t, err, "read image")
+}
+
 func TestSessionCloseIsIdempotentAndPreventsFurtherUse(t *testing.T) {
 	t.Parallel()
 
@@ -14,14 +14,16 @@ import (
 	"github.com/codalotl/codalotl/internal/llmmodel"
 	"github.com/codalotl/codalotl/internal/llmstream"
 	"github.com/codalotl/codalotl/internal/prompt"
+	"github.com/codalotl/codalotl/internal/slashcommands"
 	"github.com/codalotl/codalotl/internal/tools/authdomain"
 )
 
 // A sessionConfig selects the agent and initial-message rules for a session.
 type sessionConfig struct {
-	agentName             string // It is the registry name used to prepare the agent.
-	pkgMode               bool   // It reports whether package-mode behavior is active.
-	allowEmptyInitialUser bool   // It reports whether the first user message may be empty.
+	agentName             string                 // It is the registry name used to prepare the agent.
+	pkgMode               bool                   // It reports whether package-mode behavior is active.
+	allowEmptyInitialUser bool                   // It reports whether the first user message may be empty.
+	command               *slashcommands.Command // If non-nil, it is the custom command whose rendered prompt replaces the first user message.
 }
 
 // sessionAgent is the agent contract required by a reusable noninteractive session.
@@ -29,6 +31,9 @@ type sessionAgent interface {
 	// SendUserMessage sends message to the agent and streams events until the turn finishes.
 	SendUserMessage(ctx context.Context, message string) <-chan agent.Event
 
+	// SendUserMessageWithImages is like SendUserMessage, but attaches images to the user turn.
+	SendUserMessageWithImages(ctx context.Context, message string, images []llmstream.ImageContent) <-chan agent.Event
+
 	// TokenUsage returns the token usage accumulated by the agent conversation.
 	TokenUsage() llmstream.TokenUsage
 
@@ -419,10 +424,38 @@ func buildSessionConfig(opts Options) (sessionConfig, error) {
 		config.agentName = agentbuilder.AgentPackageModeNoContext
 	}
 
-	if slashCommand == slashCommandOrchestrate {
+	switch slashCommand {
+	case "":
+	case slashCommandOrchestrate:
 		config.agentName = orchestratorAgentName
 		config.pkgMode = false
 		config.allowEmptyInitialUser = true
+	default:
+		commands, failed, err := slashcommands.Load(slashcommands.SearchPaths(opts.CWD), agentbuilder.ToolNames())
+		if err != nil {
+			return sessionConfig{}, err
+		}
+		command, ok := slashcommands.Lookup(commands, slashCommand)
+		if !ok {
+			if loadErr := slashcommands.LookupFailure(failed, slashCommand); loadErr != nil {
+				return sessionConfig{}, fmt.Errorf("slash command %q: %w", slashCommand, loadErr)
+			}
+			return sessionConfig{}, fmt.Errorf("unsupported slash command %q", strings.TrimSpace(opts.SlashCommand))
+		}
+		switch command.Mode {
+		case slashcommands.ModeGeneric:
+			config.pkgMode = false
+			config.agentName = agentbuilder.AgentGeneric
+		case slashcommands.ModePackage:
+			if !config.pkgMode {
+				return sessionConfig{}, fmt.Errorf("slash command %q requires package mode", command.Name)
+			}
+		}
+		if command.Agent != "" {
+			config.agentName = command.Agent
+		}
+		config.allowEmptyInitialUser = true
+		config.command = &command
 	}
 
 	return config, nil
@@ -462,6 +495,9 @@ func NewSession(opts Options) (*Session, error) {
 	})
 	terminalWidth := detectTerminalWidth(rawOut)
 	modelID := effectiveModelID(opts)
+	if config.command != nil && config.command.Model != "" {
+		modelID = config.command.Model
+	}
 	prompt.SetModel(modelID)
 
 	sandboxAuthorizer, userRequests, err := authdomain.NewSessionAuthorizer(sandboxDir, nil, opts.AutoYes)
@@ -478,6 +514,9 @@ func NewSession(opts Options) (*Session, error) {
 		agentName: config.agentName,
 		pkgMode:   config.pkgMode,
 	}
+	if config.command != nil {
+		agentStart.allowedTools = config.command.AllowedTools
+	}
 	agentInstance, err := buildAgent(agentStart, sandboxDir, pkgRelPath, pkgAbsPath, modelID, authorizerForTools, opts.LintSteps)
 	if err != nil {
 		authorizerForTools.Close()
@@ -542,6 +581,11 @@ func (s *Session) Close() error {
 
 // SendUserMessage runs one top-level user message on an existing session, writes output according to the session options, and returns structured step metadata.
 func (s *Session) SendUserMessage(ctx context.Context, userPrompt string) (Result, error) {
+	return s.SendUserMessageWithImages(ctx, userPrompt, nil)
+}
+
+// SendUserMessageWithImages is like SendUserMessage, but attaches images to the user message. The prompt may be empty when images are attached.
+func (s *Session) SendUserMessageWithImages(ctx context.Context, userPrompt string, images []llmstream.ImageContent) (Result, error) {
 	if s == nil {
 		return Result{}, fmt.Errorf("nil session")
 	}
@@ -558,7 +602,18 @@ func (s *Session) SendUserMessage(ctx context.Context, userPrompt string) (Resul
 	}
 
 	userPrompt = strings.TrimSpace(userPrompt)
-	if userPrompt == "" && !(s.stepsSent == 0 && s.config.allowEmptyInitialUser) {
+	if s.stepsSent == 0 && s.config.command != nil {
+		rendered, err := s.config.command.Render(slashcommands.TemplateData{
+			Args:       userPrompt,
+			Package:    s.startInfo.pkgRelPath,
+			SandboxDir: s.startInfo.sandboxDir,
+		})
+		if err != nil {
+			return Result{}, err
+		}
+		userPrompt = rendered
+	}
+	if userPrompt == "" && len(images) == 0 && !(s.stepsSent == 0 && s.config.allowEmptyInitialUser) {
 		return Result{}, fmt.Errorf("prompt is required")
 	}
 
@@ -581,7 +636,7 @@ func (s *Session) SendUserMessage(ctx context.Context, userPrompt string) (Resul
 	var terminalErr error
 	displayFilter := newSubagentDisplayFilter(!s.opts.OutputJSON)
 
-	for ev := range s.agent.SendUserMessage(ctx, userPrompt) {
+	for ev := range s.agent.SendUserMessageWithImages(ctx, userPrompt, images) {
 		flush, forceToolCallID, hide := displayFilter.Prepare(ev)
 		if toolCallPrinter != nil && forceToolCallID != "" {
 			toolCallPrinter.Force(forceToolCallID)
@@ -11,11 +11,12 @@ import (
 	"strings"
 )
 
-// computeFieldKey returns the case-insensitive configuration key for f.
+// FieldKey returns the case-insensitive (lowercased) configuration key for struct field f. Tools that describe a config struct (ex: a JSON Schema generator) should
+// use it so their key names match the keys Loader accepts.
 //
 // A cascade tag name takes priority over a JSON tag name, which takes priority over the Go field name. A cascade tag name of "-" returns "-"; json:"-" is ignored
 // for naming and does not make the field unavailable.
-func computeFieldKey(f reflect.StructField) string {
+func FieldKey(f reflect.StructField) string {
 	// Highest priority: cascade tag name (before first comma). If empty, fall back.
 	if tag := f.Tag.Get("cascade"); tag != "" {
 		parts := strings.Split(tag, ",")
@@ -61,16 +62,40 @@ type Loader struct {
 
 // Providence identifies the configuration source that supplied a value. The zero value records no source.
 type Providence struct {
-	SourceType       string // ex: "default", "env", "json_file"
+	SourceType       string // ex: "default", "env", "json_file", "yaml_file", "toml_file"
 	SourceIdentifier string // ex: "/path/to/file.json". Can be "" for things without identifiers (default map, env).
 }
 
-// LoadReport describes which sources actually contributed values during a successful load, in low->high precedence order.
+// LoadReport describes which sources actually contributed values during a successful load, in low->high precedence order, and which source set each key.
 //
 // A source is included only if it was readable/present (as applicable), successfully parsed, and assigned at least one value into the destination struct during
 // the load. Missing/unreadable files, empty files, and sources that only provided unknown keys are excluded.
 type LoadReport struct {
 	Sources []Providence // Sources are the contributing sources in low->high precedence order.
+
+	// Keys lists every key that some source set, in struct field order. Keys are filled only on a successful load.
+	Keys []ResolvedKey
+}
+
+// ResolvedKey is a configuration key that some source set, with its final value.
+//
+// Keys are lowercased, dot-separated paths to non-struct fields (ex: "server.port"). A slice is a single key, even if it holds structs: the highest-priority source
+// that set it replaces it as a whole.
+type ResolvedKey struct {
+	Key    string     // Key is the lowercased, dot-separated key (ex: "server.port").
+	Value  any        // Value is the field's final value in the destination struct (ex: int(8080)).
+	Source Providence // Source is the highest-priority source that set Key.
+}
+
+// KeySource returns the source that set key (case-insensitive, dot-separated), and false if no source set it.
+func (r LoadReport) KeySource(key string) (Providence, bool) {
+	key = strings.ToLower(key)
+	for _, k := range r.Keys {
+		if k.Key == key {
+			return k.Source, true
+		}
+	}
+	return Providence{}, false
 }
 
 // IsSet reports whether p records a source.
@@ -115,6 +140,52 @@ func (c *Loader) WithJSONFile(absolutePath string) *Loader {
 //
 // The method returns the receiver to allow chaining.
 func (c *Loader) WithNearestJSONFile(fileName string, startingAbsolutePath string) *Loader {
+	if path := NearestFile(fileName, startingAbsolutePath); path != "" {
+		c.sources = append(c.sources, &sourceJSONFile{path: path})
+	}
+	return c
+}
+
+// WithYAMLFile registers a YAML file as a source on the Loader. It is like WithJSONFile, but the file is parsed as YAML; mappings must have string keys.
+//
+// The method returns the Loader to allow chaining.
+func (c *Loader) WithYAMLFile(absolutePath string) *Loader {
+	c.sources = append(c.sources, &sourceYAMLFile{path: absolutePath})
+	return c
+}
+
+// WithNearestYAMLFile is like WithNearestJSONFile, but registers the file found as a YAML source. It panics if fileName is absolute.
+//
+// The method returns the receiver to allow chaining.
+func (c *Loader) WithNearestYAMLFile(fileName string, startingAbsolutePath string) *Loader {
+	if path := NearestFile(fileName, startingAbsolutePath); path != "" {
+		c.sources = append(c.sources, &sourceYAMLFile{path: path})
+	}
+	return c
+}
+
+// WithTOMLFile registers a TOML file as a source on the Loader. It is like WithJSONFile, but the file is parsed as TOML; date-time values are not supported.
+//
+// The method returns the Loader to allow chaining.
+func (c *Loader) WithTOMLFile(absolutePath string) *Loader {
+	c.sources = append(c.sources, &sourceTOMLFile{path: absolutePath})
+	return c
+}
+
+// WithNearestTOMLFile is like WithNearestJSONFile, but registers the file found as a TOML source. It panics if fileName is absolute.
+//
+// The method returns the receiver to allow chaining.
+func (c *Loader) WithNearestTOMLFile(fileName string, startingAbsolutePath string) *Loader {
+	if path := NearestFile(fileName, startingAbsolutePath); path != "" {
+		c.sources = append(c.sources, &sourceTOMLFile{path: path})
+	}
+	return c
+}
+
+// NearestFile searches upward from startingAbsolutePath (or, if empty, from the current working directory) for the first readable, non-empty file named fileName,
+// and returns its path, or "" if there is none. It is the search WithNearestJSONFile, WithNearestYAMLFile, and WithNearestTOMLFile use, so callers can find the
+// file those would load. It panics if fileName is absolute.
+func NearestFile(fileName string, startingAbsolutePath string) string {
 	if filepath.IsAbs(fileName) {
 		panic("fileName shouldn't be absolute")
 	}
@@ -127,7 +198,7 @@ func (c *Loader) WithNearestJSONFile(fileName string, startingAbsolutePath strin
 		}
 	}
 	if start == "" {
-		return c
+		return ""
 	}
 
 	// If a file path was provided, use its directory as the starting point.
@@ -141,8 +212,7 @@ func (c *Loader) WithNearestJSONFile(fileName string, startingAbsolutePath strin
 
 		if data, err := os.ReadFile(candidate); err == nil {
 			if strings.TrimSpace(string(data)) != "" {
-				c.sources = append(c.sources, &sourceJSONFile{path: candidate})
-				return c
+				return candidate
 			}
 		}
 
@@ -152,7 +222,7 @@ func (c *Loader) WithNearestJSONFile(fileName string, startingAbsolutePath strin
 		}
 	}
 
-	return c
+	return ""
 }
 
 // WithEnv registers an environment-variable-backed source. The mapping m associates a configuration key (dots denote nesting) with an environment variable name;
@@ -199,8 +269,8 @@ func (c *Loader) StrictlyLoadWithReport(dest any) (LoadReport, error) {
 		return report, fmt.Errorf("dest must be a pointer to struct, got %s", structVal.Kind())
 	}
 
-	// Track which required fields were set by any source, using lowercased dot-paths.
-	present := map[string]bool{}
+	// Track which fields were set by any source (and by which source, last one wins), using lowercased dot-paths.
+	present := map[string]Providence{}
 
 	for _, src := range c.sources {
 		m, err := src.ToMap()
@@ -230,14 +300,51 @@ func (c *Loader) StrictlyLoadWithReport(dest any) (LoadReport, error) {
 	if err := validateRequiredFields(structVal, "", present); err != nil {
 		return report, err
 	}
+	report.Keys = resolvedKeys(structVal, "", present)
 	return report, nil
 }
 
+// resolvedKeys returns a ResolvedKey for each non-struct field of structVal (recursing into structs and non-nil pointers to structs) whose path is in present, in
+// struct field order. basePath carries the path prefix used during recursion.
+func resolvedKeys(structVal reflect.Value, basePath string, present map[string]Providence) []ResolvedKey {
+	var out []ResolvedKey
+	structType := structVal.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		f := structType.Field(i)
+		fv := structVal.Field(i)
+		key := FieldKey(f)
+		if key == "-" || key == "" || !fv.CanInterface() {
+			continue
+		}
+		path := key
+		if basePath != "" {
+			path = basePath + "." + key
+		}
+
+		inner := fv
+		if inner.Kind() == reflect.Ptr {
+			if inner.IsNil() {
+				continue
+			}
+			inner = inner.Elem()
+		}
+		if inner.Kind() == reflect.Struct {
+			out = append(out, resolvedKeys(inner, path, present)...)
+			continue
+		}
+
+		if prov, ok := present[path]; ok {
+			out = append(out, ResolvedKey{Key: path, Value: inner.Interface(), Source: prov})
+		}
+	}
+	return out
+}
+
 // applyMapToStruct writes values from m into structVal, matching keys to settable struct fields case-insensitively and recursing into nested objects. basePath is
 // a dot-separated prefix used in error messages and in the present map, which records lowercase paths for fields that were successfully assigned. Unknown keys are
 // ignored. Values are assigned via setFieldValue, which handles pointer allocation, recursion, and type coercion. Returns an error if a provided value has the wrong
 // shape or cannot be coerced to the destination field type. The present map must be non-nil. Case-insensitive field name collisions are not supported.
-func applyMapToStruct(structVal reflect.Value, m map[string]any, basePath string, present map[string]bool, prov Providence, onAnyAssigned func()) error {
+func applyMapToStruct(structVal reflect.Value, m map[string]any, basePath string, present map[string]Providence, prov Providence, onAnyAssigned func()) error {
 	structType := structVal.Type()
 
 	// Build case-insensitive index of fields: lower(key) -> index
@@ -249,7 +356,7 @@ func applyMapToStruct(structVal reflect.Value, m map[string]any, basePath string
 		if !structVal.Field(i).CanSet() {
 			continue
 		}
-		key := computeFieldKey(f)
+		key := FieldKey(f)
 		if key == "-" || key == "" {
 			continue
 		}
@@ -311,7 +418,7 @@ func applyMapToStruct(structVal reflect.Value, m map[string]any, basePath string
 //
 // On mismatch of shape or type (after coercion), an error is returned with the offending path. Unsupported destination kinds or slice element kinds also return
 // an error. The present map must be non-nil.
-func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path string, present map[string]bool, prov Providence, onAssigned func(), onAnyAssigned func()) error {
+func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path string, present map[string]Providence, prov Providence, onAssigned func(), onAnyAssigned func()) error {
 	// Handle pointers by allocating as needed
 	if fVal.Kind() == reflect.Ptr {
 		if fVal.IsNil() {
@@ -347,7 +454,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 		case reflect.Float32, reflect.Float64:
 			fVal.SetFloat(coerced.(float64))
 		}
-		present[path] = true
+		present[path] = prov
 		if onAnyAssigned != nil {
 			onAnyAssigned()
 		}
@@ -358,7 +465,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 		// Allow empty arrays to coerce to any destination slice type:
 		if rv := reflect.ValueOf(raw); rv.Kind() == reflect.Slice && rv.Len() == 0 {
 			fVal.Set(reflect.MakeSlice(fVal.Type(), 0, 0))
-			present[path] = true
+			present[path] = prov
 			if onAnyAssigned != nil {
 				onAnyAssigned()
 			}
@@ -381,7 +488,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 				}
 			}
 			fVal.Set(slice)
-			present[path] = true
+			present[path] = prov
 			if onAnyAssigned != nil {
 				onAnyAssigned()
 			}
@@ -399,7 +506,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 					slice.Index(i).SetString(coerced.(string))
 				}
 				fVal.Set(slice)
-				present[path] = true
+				present[path] = prov
 				if onAnyAssigned != nil {
 					onAnyAssigned()
 				}
@@ -415,7 +522,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 					slice.Index(i).SetString(coerced.(string))
 				}
 				fVal.Set(slice)
-				present[path] = true
+				present[path] = prov
 				if onAnyAssigned != nil {
 					onAnyAssigned()
 				}
@@ -431,7 +538,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 					slice.Index(i).SetString(coerced.(string))
 				}
 				fVal.Set(slice)
-				present[path] = true
+				present[path] = prov
 				if onAnyAssigned != nil {
 					onAnyAssigned()
 				}
@@ -447,7 +554,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 					slice.Index(i).SetString(coerced.(string))
 				}
 				fVal.Set(slice)
-				present[path] = true
+				present[path] = prov
 				if onAnyAssigned != nil {
 					onAnyAssigned()
 				}
@@ -468,7 +575,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 					slice.Index(i).SetBool(coerced.(bool))
 				}
 				fVal.Set(slice)
-				present[path] = true
+				present[path] = prov
 				if onAnyAssigned != nil {
 					onAnyAssigned()
 				}
@@ -484,7 +591,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 					slice.Index(i).SetBool(coerced.(bool))
 				}
 				fVal.Set(slice)
-				present[path] = true
+				present[path] = prov
 				if onAnyAssigned != nil {
 					onAnyAssigned()
 				}
@@ -505,7 +612,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 					slice.Index(i).SetInt(coerced.(int64))
 				}
 				fVal.Set(slice)
-				present[path] = true
+				present[path] = prov
 				if onAnyAssigned != nil {
 					onAnyAssigned()
 				}
@@ -521,7 +628,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 					slice.Index(i).SetInt(coerced.(int64))
 				}
 				fVal.Set(slice)
-				present[path] = true
+				present[path] = prov
 				if onAnyAssigned != nil {
 					onAnyAssigned()
 				}
@@ -537,7 +644,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 					slice.Index(i).SetInt(coerced.(int64))
 				}
 				fVal.Set(slice)
-				present[path] = true
+				present[path] = prov
 				if onAnyAssigned != nil {
 					onAnyAssigned()
 				}
@@ -558,7 +665,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 					slice.Index(i).SetFloat(coerced.(float64))
 				}
 				fVal.Set(slice)
-				present[path] = true
+				present[path] = prov
 				if onAnyAssigned != nil {
 					onAnyAssigned()
 				}
@@ -574,7 +681,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 					slice.Index(i).SetFloat(coerced.(float64))
 				}
 				fVal.Set(slice)
-				present[path] = true
+				present[path] = prov
 				if onAnyAssigned != nil {
 					onAnyAssigned()
 				}
@@ -590,7 +697,7 @@ func setFieldValue(fVal reflect.Value, fType reflect.StructField, raw any, path
 					slice.Index(i).SetFloat(coerced.(float64))
 				}
 				fVal.Set(slice)
-				present[path] = true
+				present[path] = prov
 				if onAnyAssigned != nil {
 					onAnyAssigned()
 				}
@@ -618,6 +725,10 @@ func providenceForSource(src cascadeSource) Providence {
 	case *sourceJSONFile:
 		// ExpandPath ensures SourceIdentifier is absolute and has "~" expanded when applicable.
 		return Providence{SourceType: "json_file", SourceIdentifier: ExpandPath(s.path)}
+	case *sourceYAMLFile:
+		return Providence{SourceType: "yaml_file", SourceIdentifier: ExpandPath(s.path)}
+	case *sourceTOMLFile:
+		return Providence{SourceType: "toml_file", SourceIdentifier: ExpandPath(s.path)}
 	case *sourceEnv:
 		return Providence{SourceType: "env"}
 	default:
@@ -698,12 +809,12 @@ func coerceScalar(raw any, targetKind reflect.Kind, path string) (any, error) {
 // field names joined by dots, with slice indices included (ex: "items[0].name"). It recurses into structs, non-nil pointers to structs, and slices of structs. It
 // returns an error naming the first missing required key, or nil if all required fields are present. structVal must be a struct value; basePath carries the path
 // prefix used during recursion.
-func validateRequiredFields(structVal reflect.Value, basePath string, present map[string]bool) error {
+func validateRequiredFields(structVal reflect.Value, basePath string, present map[string]Providence) error {
 	structType := structVal.Type()
 	for i := 0; i < structType.NumField(); i++ {
 		f := structType.Field(i)
 		fv := structVal.Field(i)
-		nameLower := computeFieldKey(f)
+		nameLower := FieldKey(f)
 		if nameLower == "-" || nameLower == "" {
 			continue
 		}
@@ -713,7 +824,7 @@ func validateRequiredFields(structVal reflect.Value, basePath string, present ma
 		}
 
 		if requiredFromCascadeTag(f) {
-			if !present[path] {
+			if !present[path].IsSet() {
 				return fmt.Errorf("missing required key: %s", path)
 			}
 		}
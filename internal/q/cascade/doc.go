@@ -7,6 +7,8 @@
 //   - Defaults from a map[string]any whose keys may use dot-notation to denote nesting.
 //   - JSON files read at load time. WithJSONFile registers a specific path (absolute or relative). WithNearestJSONFile searches upward from a starting path for the
 //     first readable, non-empty file with a given relative name; it panics if fileName is absolute.
+//   - YAML and TOML files, registered like JSON files with WithYAMLFile/WithNearestYAMLFile and WithTOMLFile/WithNearestTOMLFile. YAML mappings must have string keys;
+//     TOML date-time values are not supported.
 //   - Environment variables mapped to configuration keys via WithEnv; missing variables are ignored and present values are strings.
 //
 // Keys, matching, and coercion Keys are case-insensitive and dot-separated for nesting. Struct field names are matched case-insensitively (ex: "server.port" sets
@@ -17,8 +19,11 @@
 // an error when a readable source cannot be parsed or when a value cannot be coerced to the field type; it fails fast and does not continue to later sources to
 // "fix" bad values. Missing or unreadable sources, empty/whitespace-only files, and unknown keys do not cause errors. Errors include the source's name for context.
 //
+// Provenance A struct field named <Field>Providence of type Providence (or *Providence) records which source set <Field>. StrictlyLoadWithReport also returns a
+// LoadReport that lists the contributing sources and, for every key some source set, its final value and source (LoadReport.Keys; see LoadReport.KeySource).
+//
 // Paths ExpandPath expands a leading "~" to the current user's home directory. InUserConfigDirectory returns an absolute, OS-appropriate path for user-specific
-// config files joined with a subpath.
+// config files joined with a subpath. NearestFile returns the file the WithNearest* methods would register.
 //
 // Example
 //
@@ -94,3 +94,76 @@ func TestStrictlyLoadWithReport_JSONFiles(t *testing.T) {
 		assert.Equal(t, []string{ExpandPath(global), ExpandPath(local)}, jsonFilePaths(r))
 	})
 }
+
+func TestStrictlyLoadWithReport_Keys(t *testing.T) {
+	type Step struct {
+		ID string
+	}
+	type Server struct {
+		Host string
+		Port int
+	}
+	type C struct {
+		Name    string
+		Server  Server
+		TLS     *Server
+		Steps   []Step
+		Unset   string
+		private string
+	}
+
+	base := t.TempDir()
+	yamlPath := filepath.Join(base, "cfg.yaml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte("server:\n  host: yaml-host\nsteps:\n  - id: a\n"), 0o644))
+	tomlPath := filepath.Join(base, "cfg.toml")
+	require.NoError(t, os.WriteFile(tomlPath, []byte("[server]\nport = 9090\n"), 0o644))
+
+	withEnv(t, map[string]string{"APP_NAME": "env-name"}, func() {
+		var cfg C
+		r, err := New().
+			WithDefaults(map[string]any{"name": "def", "server.port": 80}).
+			WithYAMLFile(yamlPath).
+			WithTOMLFile(tomlPath).
+			WithEnv(map[string]string{"name": "APP_NAME"}).
+			StrictlyLoadWithReport(&cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, []ResolvedKey{
+			{Key: "name", Value: "env-name", Source: Providence{SourceType: "env"}},
+			{Key: "server.host", Value: "yaml-host", Source: Providence{SourceType: "yaml_file", SourceIdentifier: yamlPath}},
+			{Key: "server.port", Value: 9090, Source: Providence{SourceType: "toml_file", SourceIdentifier: tomlPath}},
+			{Key: "steps", Value: []Step{{ID: "a"}}, Source: Providence{SourceType: "yaml_file", SourceIdentifier: yamlPath}},
+		}, r.Keys)
+
+		src, ok := r.KeySource("Server.Port")
+		assert.True(t, ok)
+		assert.Equal(t, "toml_file", src.SourceType)
+		_, ok = r.KeySource("unset")
+		assert.False(t, ok)
+	})
+}
+
+func TestWithNearestYAMLAndTOMLFile(t *testing.T) {
+	type C struct {
+		A string
+		B string
+	}
+
+	base := t.TempDir()
+	child := filepath.Join(base, "x", "y")
+	require.NoError(t, os.MkdirAll(child, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "app.yaml"), []byte("a: from-yaml\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "x", "app.toml"), []byte("b = \"from-toml\"\n"), 0o644))
+
+	var cfg C
+	r, err := New().
+		WithNearestYAMLFile("app.yaml", child).
+		WithNearestTOMLFile("app.toml", child).
+		WithNearestYAMLFile("missing.yaml", child).
+		StrictlyLoadWithReport(&cfg)
+	require.NoError(t, err)
+	assert.Equal(t, C{A: "from-yaml", B: "from-toml"}, cfg)
+	assert.Len(t, r.Sources, 2)
+
+	assert.Panics(t, func() { New().WithNearestYAMLFile(filepath.Join(base, "app.yaml"), "") })
+}
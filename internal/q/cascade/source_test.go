@@ -375,3 +375,92 @@ func TestSourceEnv_ToMap(t *testing.T) {
 		})
 	})
 }
+
+func TestSourceYAMLFile_ToMap(t *testing.T) {
+	t.Run("nested values and arrays", func(t *testing.T) {
+		withJSON(t, "cfg.yaml", `
+# comment
+Server:
+  Host: example.com
+  Port: 8080
+ratios: [1, 2.5]
+ids: [1, 2]
+steps:
+  - id: fmt
+  - id: vet
+empty: []
+`, func(p string) {
+			m, err := (&sourceYAMLFile{path: p}).ToMap()
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{
+				"server": map[string]any{"host": "example.com", "port": 8080},
+				"ratios": []float64{1, 2.5},
+				"ids":    []int{1, 2},
+				"steps":  []map[string]any{{"id": "fmt"}, {"id": "vet"}},
+				"empty":  []string{},
+			}, m)
+		})
+	})
+
+	t.Run("comment-only file returns empty map", func(t *testing.T) {
+		withJSON(t, "cfg.yaml", "# nothing here\n", func(p string) {
+			m, err := (&sourceYAMLFile{path: p}).ToMap()
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{}, m)
+		})
+	})
+
+	t.Run("top-level not mapping", func(t *testing.T) {
+		withJSON(t, "cfg.yaml", "- a\n- b\n", func(p string) {
+			_, err := (&sourceYAMLFile{path: p}).ToMap()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "top-level YAML must be an object")
+		})
+	})
+
+	t.Run("non-string keys", func(t *testing.T) {
+		withJSON(t, "cfg.yaml", "ports:\n  1: a\n", func(p string) {
+			_, err := (&sourceYAMLFile{path: p}).ToMap()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "object keys must be strings")
+		})
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		withJSON(t, "cfg.yaml", "a: [1, 2\n", func(p string) {
+			_, err := (&sourceYAMLFile{path: p}).ToMap()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "parse yaml")
+		})
+	})
+}
+
+func TestSourceTOMLFile_ToMap(t *testing.T) {
+	t.Run("tables and arrays of tables", func(t *testing.T) {
+		withJSON(t, "cfg.toml", `
+name = "app"
+
+[Server]
+port = 8080
+
+[[steps]]
+id = "fmt"
+`, func(p string) {
+			m, err := (&sourceTOMLFile{path: p}).ToMap()
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{
+				"name":   "app",
+				"server": map[string]any{"port": 8080},
+				"steps":  []map[string]any{{"id": "fmt"}},
+			}, m)
+		})
+	})
+
+	t.Run("parse error includes line", func(t *testing.T) {
+		withJSON(t, "cfg.toml", "a = 1\nb =\n", func(p string) {
+			_, err := (&sourceTOMLFile{path: p}).ToMap()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "parse toml: line 2")
+		})
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // cascadeSource represents a configuration source that can supply key/value data to the loader in a normalized map form.
@@ -39,6 +41,18 @@ type sourceJSONFile struct {
 	path string // Path to the JSON file to read at load time. May be absolute or relative and is expanded with ExpandPath.
 }
 
+// sourceYAMLFile implements cascadeSource for a single YAML file whose contents are read and normalized at load time. Empty, whitespace-only, and comment-only
+// files contribute no values.
+type sourceYAMLFile struct {
+	path string // Path to the YAML file to read at load time. May be absolute or relative and is expanded with ExpandPath.
+}
+
+// sourceTOMLFile implements cascadeSource for a single TOML file whose contents are read and normalized at load time. Empty or whitespace-only files contribute
+// no values.
+type sourceTOMLFile struct {
+	path string // Path to the TOML file to read at load time. May be absolute or relative and is expanded with ExpandPath.
+}
+
 // sourceEnv implements cascadeSource backed by environment variables mapped to configuration keys.
 type sourceEnv struct {
 	// envToKey maps a key in a map ("." allowed for nesting) to an ENV variable. Ex: {"server.host": "SERVER_HOST", "server.port": "SERVER_PORT"} creates {server: {host:
@@ -187,14 +201,69 @@ func (s *sourceJSONFile) Name() string {
 //
 // NOTE: empty arrays are considered []string{}.
 func (s *sourceJSONFile) ToMap() (map[string]any, error) {
-	if s == nil || s.path == "" {
+	if s == nil {
 		return map[string]any{}, nil
 	}
+	return fileToMap(s.path, "json", func(data []byte) (any, error) {
+		var raw any
+		err := json.Unmarshal(data, &raw)
+		return raw, err
+	})
+}
+
+// Name implements cascadeSource and returns a human-readable label that includes the file path, e.g., "YAML File: <path>".
+func (s *sourceYAMLFile) Name() string {
+	return fmt.Sprintf("YAML File: %s", s.path)
+}
 
-	data, err := os.ReadFile(ExpandPath(s.path))
+// Implements cascadeSource.
+//
+// NOTE: empty arrays are considered []string{}. Mappings must have string keys.
+func (s *sourceYAMLFile) ToMap() (map[string]any, error) {
+	if s == nil {
+		return map[string]any{}, nil
+	}
+	return fileToMap(s.path, "yaml", func(data []byte) (any, error) {
+		var raw any
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		if raw == nil {
+			// A document with only comments.
+			return map[string]any{}, nil
+		}
+		return raw, nil
+	})
+}
+
+// Name implements cascadeSource and returns a human-readable label that includes the file path, e.g., "TOML File: <path>".
+func (s *sourceTOMLFile) Name() string {
+	return fmt.Sprintf("TOML File: %s", s.path)
+}
+
+// Implements cascadeSource.
+//
+// NOTE: empty arrays are considered []string{}. Date-time values are not supported.
+func (s *sourceTOMLFile) ToMap() (map[string]any, error) {
+	if s == nil {
+		return map[string]any{}, nil
+	}
+	return fileToMap(s.path, "toml", func(data []byte) (any, error) {
+		return parseTOML(string(data))
+	})
+}
+
+// fileToMap reads the file at path (expanded with ExpandPath), decodes it with decode, and normalizes the result. format names the file format in errors (ex:
+// "json"). Empty paths and empty/whitespace-only files contribute no values. The decoded top-level value must be an object.
+func fileToMap(path string, format string, decode func(data []byte) (any, error)) (map[string]any, error) {
+	if path == "" {
+		return map[string]any{}, nil
+	}
+
+	data, err := os.ReadFile(ExpandPath(path))
 	if err != nil {
 		// TODO: determine if err is not found or permission (not errors)
-		return nil, fmt.Errorf("read json file: %w", err)
+		return nil, fmt.Errorf("read %s file: %w", format, err)
 	}
 
 	// Treat empty/whitespace-only files as empty config.
@@ -202,17 +271,17 @@ func (s *sourceJSONFile) ToMap() (map[string]any, error) {
 		return map[string]any{}, nil
 	}
 
-	var raw any
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return nil, fmt.Errorf("parse json: %w", err)
+	raw, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", format, err)
 	}
 
 	obj, ok := raw.(map[string]any)
 	if !ok {
-		return nil, fmt.Errorf("top-level JSON must be an object")
+		return nil, fmt.Errorf("top-level %s must be an object", strings.ToUpper(format))
 	}
 
-	normalized, err := normalizeJSONMap(obj)
+	normalized, err := normalizeDecodedMap(obj)
 	if err != nil {
 		return nil, err
 	}
@@ -224,11 +293,11 @@ func (s *sourceJSONFile) ToMap() (map[string]any, error) {
 	return dest, nil
 }
 
-// normalizeJSONMap normalizes a JSON-decoded map by recursively normalizing values into allowed scalar/slice/object forms used by this package.
-func normalizeJSONMap(m map[string]any) (map[string]any, error) {
+// normalizeDecodedMap normalizes a decoded JSON, YAML, or TOML map by recursively normalizing values into allowed scalar/slice/object forms used by this package.
+func normalizeDecodedMap(m map[string]any) (map[string]any, error) {
 	out := make(map[string]any, len(m))
 	for k, v := range m {
-		nv, err := normalizeJSONValue(v)
+		nv, err := normalizeDecodedValue(v)
 		if err != nil {
 			return nil, fmt.Errorf("key '%s': %w", k, err)
 		}
@@ -237,15 +306,18 @@ func normalizeJSONMap(m map[string]any) (map[string]any, error) {
 	return out, nil
 }
 
-// normalizeJSONValue converts JSON-decoded values (which use []any for arrays and float64 for numbers) into the strictly allowed types defined by validateAllowedValue.
-func normalizeJSONValue(v any) (any, error) {
+// normalizeDecodedValue converts decoded values (which use []any for arrays, float64 and/or int for numbers, and map[string]any for objects) into the strictly allowed
+// types defined by validateAllowedValue. Arrays of numbers become []int if every element is an integer, and []float64 otherwise.
+func normalizeDecodedValue(v any) (any, error) {
 	switch vv := v.(type) {
 	case nil:
 		return nil, nil
-	case string, bool, float64:
+	case string, bool, float64, int:
 		return vv, nil
+	case int64:
+		return int(vv), nil
 	case map[string]any:
-		return normalizeJSONMap(vv)
+		return normalizeDecodedMap(vv)
 	case []any:
 		if len(vv) == 0 {
 			return []string{}, nil
@@ -271,16 +343,27 @@ func normalizeJSONValue(v any) (any, error) {
 				out[i] = b
 			}
 			return out, nil
-		case float64:
-			out := make([]float64, len(vv))
+		case float64, int, int64:
+			floats := make([]float64, len(vv))
+			ints := make([]int, len(vv))
+			allInts := true
 			for i, e := range vv {
-				f, ok := e.(float64)
-				if !ok {
+				switch n := e.(type) {
+				case float64:
+					floats[i] = n
+					allInts = false
+				case int:
+					floats[i], ints[i] = float64(n), n
+				case int64:
+					floats[i], ints[i] = float64(n), int(n)
+				default:
 					return nil, fmt.Errorf("array contains mixed types (expected number)")
 				}
-				out[i] = f
 			}
-			return out, nil
+			if allInts {
+				return ints, nil
+			}
+			return floats, nil
 		case map[string]any:
 			out := make([]map[string]any, len(vv))
 			for i, e := range vv {
@@ -288,7 +371,7 @@ func normalizeJSONValue(v any) (any, error) {
 				if !ok {
 					return nil, fmt.Errorf("array contains mixed types (expected object)")
 				}
-				nm, err := normalizeJSONMap(m)
+				nm, err := normalizeDecodedMap(m)
 				if err != nil {
 					return nil, fmt.Errorf("array object[%d]: %w", i, err)
 				}
@@ -298,6 +381,8 @@ func normalizeJSONValue(v any) (any, error) {
 		default:
 			return nil, fmt.Errorf("unsupported array element type %T", vv[0])
 		}
+	case map[any]any:
+		return nil, fmt.Errorf("object keys must be strings")
 	default:
 		return nil, fmt.Errorf("unsupported type %T", v)
 	}
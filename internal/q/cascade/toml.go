@@ -0,0 +1,636 @@
+package cascade
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// parseTOML parses a TOML document into a map. Tables become map[string]any, arrays and arrays of tables become []any, integers become int, and floats become float64.
+//
+// It supports the TOML 1.0 syntax that configuration files use: comments, bare/quoted/dotted keys, [table] and [[array of tables]] headers, basic/literal/multi-line
+// strings, integers (decimal, hex, octal, binary), floats (including inf and nan), booleans, arrays, and inline tables. Date-time values are not supported and
+// return an error. Errors include the line number.
+func parseTOML(src string) (map[string]any, error) {
+	p := &tomlParser{src: src, explicitTables: map[string]bool{}, inlineTables: map[uintptr]bool{}, dottedTables: map[uintptr]bool{}}
+	root := map[string]any{}
+	cur := root
+
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return root, nil
+		}
+
+		if p.peek() == '[' {
+			isArray := strings.HasPrefix(p.src[p.pos:], "[[")
+			if isArray {
+				p.pos += 2
+			} else {
+				p.pos++
+			}
+			p.skipSpace()
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			closing := "]"
+			if isArray {
+				closing = "]]"
+			}
+			if !strings.HasPrefix(p.src[p.pos:], closing) {
+				return nil, p.errorf("expected %q to close table header", closing)
+			}
+			p.pos += len(closing)
+			cur, err = p.openTable(root, keys, isArray)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectLineEnd(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		keys, value, err := p.parseKeyValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.setKey(cur, keys, value); err != nil {
+			return nil, err
+		}
+		if err := p.expectLineEnd(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// tomlParser holds the state of a single parseTOML call.
+type tomlParser struct {
+	src string
+	pos int
+
+	// explicitTables records the paths of tables defined by a [table] header, so a second header for the same table is an error. Paths of tables inside arrays of
+	// tables include the element index (ex: "servers[1].tls").
+	explicitTables map[string]bool
+
+	// inlineTables and dottedTables hold the identities (see tableID) of tables defined by an inline table or by a dotted key. Inline tables are complete: nothing
+	// can be added to them later. Tables defined by dotted keys can't be defined again by a [table] header, but can get sub-tables.
+	inlineTables map[uintptr]bool
+	dottedTables map[uintptr]bool
+}
+
+// tableID returns the identity of table m, which stays the same while m is in the document.
+func tableID(m map[string]any) uintptr {
+	return reflect.ValueOf(m).Pointer()
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// errorf returns an error annotated with the current line number.
+func (p *tomlParser) errorf(format string, args ...any) error {
+	line := strings.Count(p.src[:min(p.pos, len(p.src))], "\n") + 1
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips spaces and tabs.
+func (p *tomlParser) skipSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+// skipComment skips a comment, if one starts at the current position, up to (but not including) the newline.
+func (p *tomlParser) skipComment() {
+	if p.peek() != '#' {
+		return
+	}
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+// skipBlank skips whitespace, newlines, and comments.
+func (p *tomlParser) skipBlank() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		case '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+// expectLineEnd consumes optional whitespace and a comment, then a newline or the end of input.
+func (p *tomlParser) expectLineEnd() error {
+	p.skipSpace()
+	p.skipComment()
+	switch {
+	case p.eof():
+		return nil
+	case p.peek() == '\n':
+		p.pos++
+		return nil
+	case strings.HasPrefix(p.src[p.pos:], "\r\n"):
+		p.pos += 2
+		return nil
+	}
+	return p.errorf("expected end of line, got %q", p.peek())
+}
+
+// parseKeyValue parses `key = value` and returns the key parts and value.
+func (p *tomlParser) parseKeyValue() ([]string, any, error) {
+	keys, err := p.parseKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	p.skipSpace()
+	if p.peek() != '=' {
+		return nil, nil, p.errorf("expected '=' after key %q", strings.Join(keys, "."))
+	}
+	p.pos++
+	p.skipSpace()
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, nil, err
+	}
+	return keys, value, nil
+}
+
+// parseKey parses a possibly dotted key and returns its parts.
+func (p *tomlParser) parseKey() ([]string, error) {
+	var parts []string
+	for {
+		p.skipSpace()
+		var part string
+		var err error
+		switch c := p.peek(); {
+		case c == '"':
+			part, err = p.parseBasicString()
+		case c == '\'':
+			part, err = p.parseLiteralString()
+		default:
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if p.pos == start {
+				return nil, p.errorf("expected a key")
+			}
+			part = p.src[start:p.pos]
+		}
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+
+		p.skipSpace()
+		if p.peek() != '.' {
+			return parts, nil
+		}
+		p.pos++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// parseValue parses any TOML value at the current position.
+func (p *tomlParser) parseValue() (any, error) {
+	switch {
+	case p.eof():
+		return nil, p.errorf("expected a value")
+	case strings.HasPrefix(p.src[p.pos:], `"""`):
+		return p.parseMultilineString(`"""`, true)
+	case p.peek() == '"':
+		return p.parseBasicString()
+	case strings.HasPrefix(p.src[p.pos:], "'''"):
+		return p.parseMultilineString("'''", false)
+	case p.peek() == '\'':
+		return p.parseLiteralString()
+	case p.peek() == '[':
+		return p.parseArray()
+	case p.peek() == '{':
+		return p.parseInlineTable()
+	}
+
+	start := p.pos
+	for !p.eof() && strings.IndexByte("0123456789abcdefABCDEFinoxtrulsINOXTRULS_+-.:", p.peek()) >= 0 {
+		p.pos++
+	}
+	token := p.src[start:p.pos]
+	switch token {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "":
+		return nil, p.errorf("expected a value, got %q", p.peek())
+	}
+	return p.parseNumber(token)
+}
+
+// parseNumber parses an integer or float token.
+func (p *tomlParser) parseNumber(token string) (any, error) {
+	if isTOMLDateTime(token) {
+		return nil, p.errorf("date-time values are not supported (got %q)", token)
+	}
+
+	switch strings.TrimLeft(token, "+-") {
+	case "inf":
+		if strings.HasPrefix(token, "-") {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+
+	base := 10
+	if len(token) >= 2 && token[0] == '0' {
+		switch token[1] {
+		case 'x':
+			base = 16
+		case 'o':
+			base = 8
+		case 'b':
+			base = 2
+		}
+	}
+	// Underscores must sit between two digits (so not next to a sign, base prefix, '.', or exponent).
+	for i := 0; i < len(token); i++ {
+		if token[i] == '_' && (i == 0 || i == len(token)-1 || !isTOMLDigit(token[i-1], base) || !isTOMLDigit(token[i+1], base)) {
+			return nil, p.errorf("invalid number %q", token)
+		}
+	}
+	digits := strings.ReplaceAll(token, "_", "")
+
+	if base != 10 {
+		n, err := strconv.ParseInt(digits[2:], base, 64)
+		if err != nil || !isTOMLDigit(digits[2], base) {
+			return nil, p.errorf("invalid integer %q", token)
+		}
+		return int(n), nil
+	}
+
+	// Decimal integers, and the integer part of floats, can't have leading zeros.
+	intPart := strings.TrimLeft(digits, "+-")
+	if i := strings.IndexAny(intPart, ".eE"); i >= 0 {
+		intPart = intPart[:i]
+	}
+	if len(intPart) > 1 && intPart[0] == '0' {
+		return nil, p.errorf("invalid number %q: leading zeros are not allowed", token)
+	}
+
+	if strings.ContainsAny(digits, ".eE") {
+		f, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %q", token)
+		}
+		return f, nil
+	}
+
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return nil, p.errorf("invalid integer %q", token)
+	}
+	return int(n), nil
+}
+
+// isTOMLDigit reports whether c is a digit in base (2, 8, 10, or 16).
+func isTOMLDigit(c byte, base int) bool {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c-'0') < base
+	case base == 16:
+		return (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+	}
+	return false
+}
+
+// isTOMLDateTime reports whether token looks like a TOML date or time (ex: "1979-05-27", "07:32:00").
+func isTOMLDateTime(token string) bool {
+	if strings.Contains(token, ":") {
+		return true
+	}
+	return len(token) >= 10 && token[4] == '-' && token[7] == '-' && strings.IndexFunc(token[:4], func(r rune) bool { return r < '0' || r > '9' }) < 0
+}
+
+// parseBasicString parses a single-line "..." string, processing escapes.
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.pos++ // opening quote
+	var b strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.peek()
+		switch c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\\':
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// parseLiteralString parses a single-line '...' string, which has no escapes.
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.pos++ // opening quote
+	end := strings.IndexAny(p.src[p.pos:], "'\n")
+	if end < 0 || p.src[p.pos+end] != '\'' {
+		return "", p.errorf("unterminated string")
+	}
+	s := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+	return s, nil
+}
+
+// parseMultilineString parses a multi-line string delimited by delim: basic (three double quotes, with escapes) or literal (three single quotes, without escapes).
+// A newline immediately after the opening delimiter is trimmed.
+func (p *tomlParser) parseMultilineString(delim string, basic bool) (string, error) {
+	p.pos += len(delim)
+	if strings.HasPrefix(p.src[p.pos:], "\r\n") {
+		p.pos += 2
+	} else if p.peek() == '\n' {
+		p.pos++
+	}
+
+	quote := delim[0]
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated multi-line string")
+		}
+		c := p.peek()
+		if c == quote && strings.HasPrefix(p.src[p.pos:], delim) {
+			// Up to two quotes may directly precede the closing delimiter.
+			n := 0
+			for p.pos+n < len(p.src) && p.src[p.pos+n] == quote {
+				n++
+			}
+			if n > 5 {
+				return "", p.errorf("too many quotes in multi-line string")
+			}
+			b.WriteString(strings.Repeat(string(quote), n-3))
+			p.pos += n
+			return b.String(), nil
+		}
+		if basic && c == '\\' {
+			// A line-ending backslash trims the newline and any whitespace that follows it.
+			rest := p.src[p.pos+1:]
+			trimmed := strings.TrimLeft(rest, " \t")
+			if strings.HasPrefix(trimmed, "\n") || strings.HasPrefix(trimmed, "\r\n") {
+				p.pos = len(p.src) - len(strings.TrimLeft(trimmed, " \t\r\n"))
+				continue
+			}
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+			continue
+		}
+		b.WriteByte(c)
+		p.pos++
+	}
+}
+
+// parseEscape parses a backslash escape at the current position and writes the result to b.
+func (p *tomlParser) parseEscape(b *strings.Builder) error {
+	p.pos++ // backslash
+	if p.eof() {
+		return p.errorf("unterminated escape")
+	}
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case 'e':
+		b.WriteByte('\x1b')
+	case '"':
+		b.WriteByte('"')
+	case '\\':
+		b.WriteByte('\\')
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.src) {
+			return p.errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid unicode escape %q", p.src[p.pos-2:p.pos+n])
+		}
+		b.WriteRune(rune(code))
+		p.pos += n
+	default:
+		return p.errorf("invalid escape \\%c", c)
+	}
+	return nil
+}
+
+// parseArray parses a [ ... ] array, which may span lines and contain comments and a trailing comma.
+func (p *tomlParser) parseArray() ([]any, error) {
+	p.pos++ // [
+	arr := []any{}
+	for {
+		p.skipBlank()
+		if p.peek() == ']' {
+			p.pos++
+			return arr, nil
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+
+		p.skipBlank()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return arr, nil
+		default:
+			return nil, p.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+// parseInlineTable parses a { key = value, ... } inline table.
+func (p *tomlParser) parseInlineTable() (map[string]any, error) {
+	p.pos++ // {
+	m := map[string]any{}
+	p.inlineTables[tableID(m)] = true
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.pos++
+		return m, nil
+	}
+	for {
+		keys, value, err := p.parseKeyValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.setKey(m, keys, value); err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return m, nil
+		default:
+			return nil, p.errorf("expected ',' or '}' in inline table")
+		}
+	}
+}
+
+// setKey sets keys (a dotted key's parts) in table to value, creating intermediate tables. Defining a key twice, or extending an inline table, is an error.
+func (p *tomlParser) setKey(table map[string]any, keys []string, value any) error {
+	for _, k := range keys[:len(keys)-1] {
+		switch existing := table[k].(type) {
+		case nil:
+			child := map[string]any{}
+			p.dottedTables[tableID(child)] = true
+			table[k] = child
+			table = child
+		case map[string]any:
+			if p.inlineTables[tableID(existing)] {
+				return p.errorf("key %q extends an inline table", strings.Join(keys, "."))
+			}
+			table = existing
+		default:
+			return p.errorf("key %q is not a table", strings.Join(keys, "."))
+		}
+	}
+	last := keys[len(keys)-1]
+	if _, exists := table[last]; exists {
+		return p.errorf("duplicate key %q", strings.Join(keys, "."))
+	}
+	table[last] = value
+	return nil
+}
+
+// openTable handles a [keys] or [[keys]] header and returns the table that subsequent key/value pairs are written to.
+func (p *tomlParser) openTable(root map[string]any, keys []string, isArray bool) (map[string]any, error) {
+	table := root
+	path := ""
+	for _, k := range keys[:len(keys)-1] {
+		path = joinPath(path, k)
+		switch existing := table[k].(type) {
+		case nil:
+			child := map[string]any{}
+			table[k] = child
+			table = child
+		case map[string]any:
+			if p.inlineTables[tableID(existing)] {
+				return nil, p.errorf("table %q extends an inline table", strings.Join(keys, "."))
+			}
+			table = existing
+		case []any:
+			// Headers below an array of tables refer to its last element.
+			last, ok := lastTable(existing)
+			if !ok {
+				return nil, p.errorf("key %q is not a table", strings.Join(keys, "."))
+			}
+			path = fmt.Sprintf("%s[%d]", path, len(existing)-1)
+			table = last
+		default:
+			return nil, p.errorf("key %q is not a table", strings.Join(keys, "."))
+		}
+	}
+
+	last := keys[len(keys)-1]
+	path = joinPath(path, last)
+	if isArray {
+		var arr []any
+		switch existing := table[last].(type) {
+		case nil:
+		case []any:
+			if _, ok := lastTable(existing); !ok {
+				return nil, p.errorf("key %q is not an array of tables", strings.Join(keys, "."))
+			}
+			arr = existing
+		default:
+			return nil, p.errorf("key %q is not an array of tables", strings.Join(keys, "."))
+		}
+		child := map[string]any{}
+		table[last] = append(arr, child)
+		return child, nil
+	}
+
+	if p.explicitTables[path] {
+		return nil, p.errorf("table %q is defined twice", strings.Join(keys, "."))
+	}
+	p.explicitTables[path] = true
+	switch existing := table[last].(type) {
+	case nil:
+		child := map[string]any{}
+		table[last] = child
+		return child, nil
+	case map[string]any:
+		switch {
+		case p.inlineTables[tableID(existing)]:
+			return nil, p.errorf("table %q is already defined as an inline table", strings.Join(keys, "."))
+		case p.dottedTables[tableID(existing)]:
+			return nil, p.errorf("table %q is already defined by dotted keys", strings.Join(keys, "."))
+		}
+		return existing, nil
+	default:
+		return nil, p.errorf("key %q is not a table", strings.Join(keys, "."))
+	}
+}
+
+// lastTable returns the last element of arr if it is a table.
+func lastTable(arr []any) (map[string]any, bool) {
+	if len(arr) == 0 {
+		return nil, false
+	}
+	m, ok := arr[len(arr)-1].(map[string]any)
+	return m, ok
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,122 @@
+package cascade
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTOML(t *testing.T) {
+	src := `
+# top-level comment
+title = "TOML \"example\"" # trailing comment
+path = 'C:\Users\me'
+count = 1_000
+hex = 0xff
+ratio = 0.5
+big = 1e3
+neg_inf = -inf
+enabled = true
+tags = ["a", "b",]
+matrix = [
+  1, # one
+  2,
+]
+owner.name = "Tom"
+zero = 0
+
+# A table defined by dotted keys can still get sub-tables.
+[owner.address]
+city = "Oslo"
+
+[server]
+host = "localhost"
+"quoted key" = 1
+limits = { cpu = 2, mem.max = 512 }
+
+[server.tls]
+enabled = false
+
+[[steps]]
+id = "fmt"
+
+[[steps]]
+id = "vet"
+
+[steps.check]
+cmd = "go"
+
+text = """
+line one \
+  continued
+line two"""
+raw = '''
+keep \n as is'''
+`
+	got, err := parseTOML(src)
+	require.NoError(t, err)
+
+	assert.Equal(t, `TOML "example"`, got["title"])
+	assert.Equal(t, `C:\Users\me`, got["path"])
+	assert.Equal(t, 1000, got["count"])
+	assert.Equal(t, 255, got["hex"])
+	assert.Equal(t, 0.5, got["ratio"])
+	assert.Equal(t, 1000.0, got["big"])
+	assert.True(t, math.IsInf(got["neg_inf"].(float64), -1))
+	assert.Equal(t, true, got["enabled"])
+	assert.Equal(t, []any{"a", "b"}, got["tags"])
+	assert.Equal(t, []any{1, 2}, got["matrix"])
+	assert.Equal(t, 0, got["zero"])
+	assert.Equal(t, map[string]any{"name": "Tom", "address": map[string]any{"city": "Oslo"}}, got["owner"])
+
+	assert.Equal(t, map[string]any{
+		"host":       "localhost",
+		"quoted key": 1,
+		"limits":     map[string]any{"cpu": 2, "mem": map[string]any{"max": 512}},
+		"tls":        map[string]any{"enabled": false},
+	}, got["server"])
+
+	assert.Equal(t, []any{
+		map[string]any{"id": "fmt"},
+		map[string]any{"id": "vet", "check": map[string]any{
+			"cmd":  "go",
+			"text": "line one continued\nline two",
+			"raw":  `keep \n as is`,
+		}},
+	}, got["steps"])
+}
+
+func TestParseTOML_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{name: "duplicate key", src: "a = 1\na = 2\n", err: `line 2: duplicate key "a"`},
+		{name: "duplicate table", src: "[a]\nx = 1\n[a]\ny = 2\n", err: `line 3: table "a" is defined twice`},
+		{name: "missing equals", src: "a 1\n", err: `line 1: expected '=' after key "a"`},
+		{name: "unterminated string", src: "a = \"abc\n", err: "line 1: unterminated string"},
+		{name: "date-time", src: "a = 1979-05-27T07:32:00Z\n", err: "date-time values are not supported"},
+		{name: "trailing garbage", src: "a = 1 2\n", err: "line 1: expected end of line"},
+		{name: "bad escape", src: `a = "\q"`, err: `invalid escape \q`},
+		{name: "table over value", src: "a = 1\n[a.b]\n", err: `key "a.b" is not a table`},
+		{name: "header reopens inline table", src: "a = {b = 1}\n[a]\nc = 2\n", err: `line 2: table "a" is already defined as an inline table`},
+		{name: "header extends inline table", src: "a = {b = 1}\n[a.c]\n", err: `line 2: table "a.c" extends an inline table`},
+		{name: "dotted key extends inline table", src: "a = {b = 1}\na.c = 2\n", err: `line 2: key "a.c" extends an inline table`},
+		{name: "header reopens dotted table", src: "a.b = 1\n[a]\nc = 2\n", err: `line 2: table "a" is already defined by dotted keys`},
+		{name: "leading zero", src: "a = 01\n", err: `line 1: invalid number "01": leading zeros are not allowed`},
+		{name: "leading zero float", src: "a = 01.5\n", err: `invalid number "01.5"`},
+		{name: "underscore after prefix", src: "a = 0x_1\n", err: `line 1: invalid number "0x_1"`},
+		{name: "underscore before exponent", src: "a = 1_e5\n", err: `invalid number "1_e5"`},
+		{name: "empty prefixed integer", src: "a = 0x\n", err: `invalid integer "0x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTOML(tt.src)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
@@ -232,3 +232,31 @@ func TestRun_HandlerUsageErrorPrintsUsage(t *testing.T) {
 		t.Fatalf("expected usage error message and usage; stderr=%q", stderr)
 	}
 }
+
+func TestRun_StringSliceFlagCollectsEachOccurrence(t *testing.T) {
+	root := &Command{Name: "prog", Run: func(*Context) error { return nil }}
+	images := root.Flags().StringSlice("image", 'i', []string{"default.png"}, "Attach an image")
+
+	code, stdout, stderr := runCLI(t, root, []string{"--image", "a.png", "-i=b.png", "--image=c.png"})
+	if code != 0 {
+		t.Fatalf("code=%d stdout=%q stderr=%q", code, stdout, stderr)
+	}
+	if strings.Join(*images, ",") != "a.png,b.png,c.png" {
+		t.Fatalf("unexpected images: %v", *images)
+	}
+
+	root = &Command{Name: "prog", Run: func(*Context) error { return nil }}
+	images = root.Flags().StringSlice("image", 'i', []string{"default.png"}, "Attach an image")
+	code, stdout, stderr = runCLI(t, root, nil)
+	if code != 0 {
+		t.Fatalf("code=%d stdout=%q stderr=%q", code, stdout, stderr)
+	}
+	if strings.Join(*images, ",") != "default.png" {
+		t.Fatalf("expected default to be kept, got %v", *images)
+	}
+
+	code, stdout, _ = runCLI(t, root, []string{"-h"})
+	if code != 0 || !strings.Contains(stdout, "--image <string>...") {
+		t.Fatalf("expected repeatable flag in help; code=%d stdout=%q", code, stdout)
+	}
+}
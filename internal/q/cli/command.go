@@ -11,21 +11,22 @@ type ArgsFunc func(args []string) error
 
 // Command defines one CLI command in a command tree.
 type Command struct {
-	Name             string     // Name is the token used to invoke this command (e.g. "add" in "doc add").
-	Aliases          []string   // Aliases are additional tokens that invoke this command.
-	Hidden           bool       // Hidden hides this command from parent help listings, but it may still be invoked normally by name or alias.
-	Short            string     // Short is a concise one-line description.
-	Long             string     // Long is a longer description for detailed help.
-	Usage            string     // Usage is the complete non-option fragment after the resolved command path.
-	ArgHelp          []ArgHelp  // ArgHelp describes positional args in detailed help.
-	Example          string     // Example is example text shown in detailed help.
-	NoPositionalArgs bool       // NoPositionalArgs suppresses generic [args] help.
-	Args             ArgsFunc   // Args validates usage after flag parsing and before Run.
-	Run              RunFunc    // Run handles the command after Args succeeds.
-	parent           *Command   // parent is the enclosing command; nil means this command is a root or unattached.
-	children         []*Command // children are the direct subcommands added with AddCommand.
-	localFlags       *FlagSet   // localFlags stores flags accepted when this command is selected.
-	persistentFlags  *FlagSet   // persistentFlags stores flags defined on this command, not inherited ancestor flags.
+	Name             string       // Name is the token used to invoke this command (e.g. "add" in "doc add").
+	Aliases          []string     // Aliases are additional tokens that invoke this command.
+	Hidden           bool         // Hidden hides this command from parent help listings, but it may still be invoked normally by name or alias.
+	Short            string       // Short is a concise one-line description.
+	Long             string       // Long is a longer description for detailed help.
+	Usage            string       // Usage is the complete non-option fragment after the resolved command path.
+	ArgHelp          []ArgHelp    // ArgHelp describes positional args in detailed help.
+	Example          string       // Example is example text shown in detailed help.
+	NoPositionalArgs bool         // NoPositionalArgs suppresses generic [args] help.
+	Args             ArgsFunc     // Args validates usage after flag parsing and before Run.
+	Run              RunFunc      // Run handles the command after Args succeeds.
+	Complete         CompleteFunc // Complete optionally returns shell completion candidates for positional args (see Complete).
+	parent           *Command     // parent is the enclosing command; nil means this command is a root or unattached.
+	children         []*Command   // children are the direct subcommands added with AddCommand.
+	localFlags       *FlagSet     // localFlags stores flags accepted when this command is selected.
+	persistentFlags  *FlagSet     // persistentFlags stores flags defined on this command, not inherited ancestor flags.
 }
 
 // ArgHelp describes one positional argument in help output.
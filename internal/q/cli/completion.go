@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// completeToken is the hidden first argument that makes Run print completions instead of running a command (see Complete).
+const completeToken = "__complete"
+
+// CompleteFunc returns completion candidates for the word being completed. args are the positional args (or, for a flag value, the command's positional args) before
+// the word; toComplete is the partial word, possibly empty. Candidates that don't start with toComplete are dropped, so a CompleteFunc may return all candidates.
+type CompleteFunc func(args []string, toComplete string) []string
+
+// Completion is one shell completion candidate.
+type Completion struct {
+	Value       string // Value replaces the word being completed.
+	Description string // Description is optional help shown by shells that support it (zsh, fish).
+}
+
+// SetComplete sets fn as the completion hook for values of the flag named name. It panics if fs has no such flag.
+func (fs *FlagSet) SetComplete(name string, fn CompleteFunc) {
+	def, ok := fs.byLong[name]
+	if !ok {
+		panic("cli: SetComplete called for unknown flag: --" + name)
+	}
+	def.complete = fn
+}
+
+// Complete returns completion candidates for a command line. args is the command line after the program name; its last element is the word being completed (an
+// empty string when completing a new word). Candidates are, depending on where the word is:
+//   - A flag value (after a non-bool flag, or after "--name="): the flag's SetComplete hook.
+//   - A word starting with "-": the active flags' long names, with their usage as description.
+//   - Otherwise: the visible subcommand names (while command selection hasn't ended), with their Short as description, followed by the selected command's Complete
+//     hook (if it's runnable).
+//
+// Only candidates starting with the word are returned. Command selection and flag parsing follow Run, except that unknown flags are ignored rather than being errors.
+func Complete(root *Command, args []string) []Completion {
+	if root == nil {
+		panic("cli: Complete called with nil root")
+	}
+	toComplete := ""
+	words := args
+	if len(args) > 0 {
+		toComplete = args[len(args)-1]
+		words = args[:len(args)-1]
+	}
+
+	selected := root
+	selectionEnded := false
+	parsingEnded := false
+	var positional []string
+	var pendingFlag *flagDef
+	for _, token := range words {
+		switch {
+		case pendingFlag != nil:
+			pendingFlag = nil
+		case parsingEnded:
+			positional = append(positional, token)
+		case token == "--":
+			parsingEnded = true
+			selectionEnded = true
+		case isFlagToken(token):
+			def := selected.activeFlags().lookup(token)
+			if def != nil && def.kind != flagBool && !strings.Contains(token, "=") {
+				pendingFlag = def
+			}
+		default:
+			if !selectionEnded {
+				if child := selected.childByToken(token); child != nil {
+					selected = child
+					continue
+				}
+				selectionEnded = true
+			}
+			positional = append(positional, token)
+		}
+	}
+
+	var candidates []Completion
+	switch {
+	case pendingFlag != nil:
+		candidates = flagValueCompletions(pendingFlag, positional, toComplete, "")
+	case !parsingEnded && strings.HasPrefix(toComplete, "-"):
+		if name, value, ok := splitFlagValue(strings.TrimLeft(toComplete, "-")); ok {
+			if def := selected.activeFlags().byLong[name]; def != nil {
+				prefix := toComplete[:len(toComplete)-len(value)]
+				candidates = flagValueCompletions(def, positional, value, prefix)
+			}
+			break
+		}
+		for _, fh := range flagsForHelp(selected) {
+			candidates = append(candidates, Completion{Value: "--" + fh.def.name, Description: strings.TrimSpace(fh.def.usage)})
+		}
+		candidates = append(candidates, Completion{Value: "--help", Description: "Show help."})
+	default:
+		if !selectionEnded {
+			for _, child := range commandsForHelp(selected, HelpOptions{}) {
+				candidates = append(candidates, Completion{Value: child.Name, Description: child.Short})
+			}
+		}
+		if selected.Run != nil && selected.Complete != nil {
+			for _, value := range selected.Complete(positional, toComplete) {
+				candidates = append(candidates, Completion{Value: value})
+			}
+		}
+	}
+
+	out := candidates[:0]
+	for _, c := range candidates {
+		if strings.HasPrefix(c.Value, toComplete) && c.Value != "" && !strings.ContainsAny(c.Value, "\t\n") {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// flagValueCompletions returns def's value completions for toComplete, each prefixed with prefix (ex: "--name=").
+func flagValueCompletions(def *flagDef, positional []string, toComplete string, prefix string) []Completion {
+	if def.complete == nil {
+		return nil
+	}
+	var out []Completion
+	for _, value := range def.complete(positional, toComplete) {
+		out = append(out, Completion{Value: prefix + value})
+	}
+	return out
+}
+
+// lookup returns the active flag named by token (ex: "--name", "--name=value", "-n", "-n=value", or "-name"), or nil if there is none.
+func (a activeFlags) lookup(token string) *flagDef {
+	if strings.HasPrefix(token, "--") {
+		name, _, _ := splitFlagValue(token[2:])
+		return a.byLong[name]
+	}
+	if len(token) >= 3 && token[2] != '=' {
+		name, _, _ := splitFlagValue(token[1:])
+		return a.byLong[name]
+	}
+	if len(token) < 2 {
+		return nil
+	}
+	return a.byShort[rune(token[1])]
+}
+
+// writeCompletions writes completions one per line, as the value optionally followed by a tab and the description's first line.
+func writeCompletions(w io.Writer, completions []Completion) {
+	for _, c := range completions {
+		desc, _, _ := strings.Cut(strings.TrimSpace(c.Description), "\n")
+		desc = strings.ReplaceAll(desc, "\t", " ")
+		if desc == "" {
+			fmt.Fprintln(w, c.Value)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\n", c.Value, desc)
+	}
+}
+
+// CompletionShells returns the shells supported by WriteCompletionScript, sorted.
+func CompletionShells() []string {
+	return []string{"bash", "fish", "zsh"}
+}
+
+// WriteCompletionScript writes a completion script for root's program to w, for shell ("bash", "zsh", or "fish"). The script completes by running the program with
+// a hidden first argument, which makes Run print the candidates from Complete; so completions always match the installed program. It returns an error for other
+// shells.
+func WriteCompletionScript(w io.Writer, root *Command, shell string) error {
+	if root == nil {
+		panic("cli: WriteCompletionScript called with nil root")
+	}
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletionScript
+	case "zsh":
+		script = zshCompletionScript
+	case "fish":
+		script = fishCompletionScript
+	default:
+		return fmt.Errorf("unsupported shell %q (supported: %s)", shell, strings.Join(CompletionShells(), ", "))
+	}
+	script = strings.NewReplacer(
+		"PROGRAM_FUNC", shellIdentifier(root.Name),
+		"PROGRAM", root.Name,
+		"COMPLETE", completeToken,
+	).Replace(script)
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+var nonIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// shellIdentifier returns name with characters that aren't valid in shell function names replaced by "_".
+func shellIdentifier(name string) string {
+	return nonIdentifierChars.ReplaceAllString(name, "_")
+}
+
+// bashCompletionScript completes words as split by whitespace (not COMP_WORDBREAKS, which would split "--name=value"). Since bash replaces only the part of the word
+// after the last break character, each candidate is trimmed to that part.
+const bashCompletionScript = `# bash completion for PROGRAM
+# Load it with: source <(PROGRAM completion bash)
+_PROGRAM_FUNC_complete() {
+    local line="${COMP_LINE:0:COMP_POINT}"
+    local -a words
+    read -ra words <<< "$line"
+    if [[ -z "$line" || "$line" == *[[:space:]] ]]; then
+        words+=("")
+    fi
+    local typed="${words[${#words[@]}-1]}"
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    [[ "$cur" == "=" || "$cur" == ":" ]] && cur=""
+    local strip=$(( ${#typed} - ${#cur} ))
+    (( strip < 0 )) && strip=0
+
+    COMPREPLY=()
+    local candidate
+    while IFS= read -r candidate; do
+        [[ -n "$candidate" ]] && COMPREPLY+=("${candidate:strip}")
+    done < <("${words[0]}" COMPLETE "${words[@]:1}" 2>/dev/null | cut -f1)
+
+    if [[ ${#COMPREPLY[@]} -eq 1 && "${COMPREPLY[0]}" == */ ]]; then
+        compopt -o nospace 2>/dev/null
+    fi
+}
+complete -o default -F _PROGRAM_FUNC_complete PROGRAM
+`
+
+// zshCompletionScript adds candidates ending in "/" (directories) without a trailing space, so completion can continue into them.
+const zshCompletionScript = `#compdef PROGRAM
+# zsh completion for PROGRAM
+# Load it with: source <(PROGRAM completion zsh)   (after compinit)
+_PROGRAM_FUNC() {
+    local -a lines values displays dirs
+    local line value desc
+    lines=("${(@f)$("${words[1]}" COMPLETE "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    for line in "${lines[@]}"; do
+        [[ -z "$line" ]] && continue
+        value="${line%%$'\t'*}"
+        if [[ "$value" == */ ]]; then
+            dirs+=("$value")
+            continue
+        fi
+        desc=""
+        [[ "$line" == *$'\t'* ]] && desc="${line#*$'\t'}"
+        values+=("$value")
+        if [[ -n "$desc" ]]; then
+            displays+=("$value  -- $desc")
+        else
+            displays+=("$value")
+        fi
+    done
+    (( ${#values} )) && compadd -l -d displays -a values
+    (( ${#dirs} )) && compadd -S '' -a dirs
+    return 0
+}
+compdef _PROGRAM_FUNC PROGRAM
+`
+
+const fishCompletionScript = `# fish completion for PROGRAM
+# Load it with: PROGRAM completion fish | source
+function __PROGRAM_FUNC_complete
+    set -l tokens (commandline -opc)
+    set -l current (commandline -ct)
+    PROGRAM COMPLETE $tokens[2..-1] $current 2>/dev/null
+end
+complete -c PROGRAM -f -a '(__PROGRAM_FUNC_complete)'
+`
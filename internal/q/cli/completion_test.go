@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCompletionTestTree() *Command {
+	root := &Command{Name: "prog"}
+	root.PersistentFlags().Bool("verbose", 'v', false, "Verbose output.")
+
+	doc := &Command{Name: "doc", Short: "Docs commands."}
+	add := &Command{
+		Name:  "add",
+		Short: "Add docs.",
+		Run:   func(*Context) error { return nil },
+		Complete: func(args []string, toComplete string) []string {
+			return []string{"pkg/a", "pkg/b", "other/" + strings.Join(args, ",")}
+		},
+	}
+	add.Flags().String("model", 'm', "", "Model to use.")
+	add.Flags().SetComplete("model", func(args []string, toComplete string) []string {
+		return []string{"gpt", "claude", "bad\tvalue"}
+	})
+	add.Flags().Bool("dry-run", 0, false, "Don't write.")
+	hidden := &Command{Name: "secret", Hidden: true, Run: func(*Context) error { return nil }}
+	doc.AddCommand(add, hidden)
+
+	version := &Command{Name: "version", Short: "Print version.", Run: func(*Context) error { return nil }}
+	root.AddCommand(doc, version)
+	return root
+}
+
+func completionValues(completions []Completion) []string {
+	var out []string
+	for _, c := range completions {
+		out = append(out, c.Value)
+	}
+	return out
+}
+
+func TestComplete(t *testing.T) {
+	root := newCompletionTestTree()
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "no args", args: nil, want: []string{"doc", "version"}},
+		{name: "subcommands", args: []string{""}, want: []string{"doc", "version"}},
+		{name: "subcommand prefix", args: []string{"d"}, want: []string{"doc"}},
+		{name: "hidden excluded", args: []string{"doc", ""}, want: []string{"add"}},
+		{name: "alias path and positional", args: []string{"doc", "add", "pkg/"}, want: []string{"pkg/a", "pkg/b"}},
+		{name: "positional args passed", args: []string{"doc", "add", "x", "y", "o"}, want: []string{"other/x,y"}},
+		{name: "persistent flag skipped", args: []string{"-v", "doc", "add", "--dry-run", "p"}, want: []string{"pkg/a", "pkg/b"}},
+		{name: "flag names", args: []string{"doc", "add", "--"}, want: []string{"--dry-run", "--model", "--verbose", "--help"}},
+		{name: "flag name prefix", args: []string{"doc", "add", "--m"}, want: []string{"--model"}},
+		{name: "flag value", args: []string{"doc", "add", "--model", ""}, want: []string{"gpt", "claude"}},
+		{name: "short flag value", args: []string{"doc", "add", "-m", "c"}, want: []string{"claude"}},
+		{name: "inline flag value", args: []string{"doc", "add", "--model=g"}, want: []string{"--model=gpt"}},
+		{name: "flag value consumed", args: []string{"doc", "add", "--model", "gpt", "pkg/a"}, want: []string{"pkg/a"}},
+		{name: "after double dash", args: []string{"doc", "add", "--", "-"}, want: nil},
+		{name: "unknown flag ignored", args: []string{"doc", "--nope", "a"}, want: []string{"add"}},
+		{name: "no hook", args: []string{"version", ""}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, completionValues(Complete(root, tt.args)))
+		})
+	}
+
+	completions := Complete(root, []string{""})
+	require.Len(t, completions, 2)
+	assert.Equal(t, Completion{Value: "doc", Description: "Docs commands."}, completions[0])
+}
+
+func TestSetCompleteUnknownFlagPanics(t *testing.T) {
+	cmd := &Command{Name: "prog"}
+	assert.Panics(t, func() { cmd.Flags().SetComplete("nope", nil) })
+}
+
+func TestRunPrintsCompletions(t *testing.T) {
+	root := newCompletionTestTree()
+	var out, errOut bytes.Buffer
+	code := Run(context.Background(), root, Options{Args: []string{completeToken, "doc", ""}, Out: &out, Err: &errOut})
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "add\tAdd docs.\n", out.String())
+	assert.Empty(t, errOut.String())
+}
+
+func TestWriteCompletionScript(t *testing.T) {
+	root := &Command{Name: "my-prog"}
+	for _, shell := range CompletionShells() {
+		t.Run(shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteCompletionScript(&buf, root, shell))
+			script := buf.String()
+			assert.Contains(t, script, "my-prog")
+			assert.Contains(t, script, completeToken)
+			assert.Contains(t, script, "my_prog")
+			assert.NotContains(t, script, "PROGRAM")
+			assert.NotContains(t, script, "COMPLETE")
+		})
+	}
+
+	var buf bytes.Buffer
+	err := WriteCompletionScript(&buf, root, "powershell")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "powershell")
+	assert.Empty(t, buf.String())
+}
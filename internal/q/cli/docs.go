@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown writes Markdown reference documentation for cmd and its visible descendants to w. root supplies the program name and command path. cmd gets a
+// level-1 heading; each descendant, in depth-first order with siblings sorted by name, gets a level-2 heading naming its full command path. Each section has the
+// same content as WriteHelp (description, usage, options, args, and examples), except that subcommands get their own sections instead of a listing.
+func WriteMarkdown(w io.Writer, root, cmd *Command) {
+	if root == nil {
+		panic("cli: WriteMarkdown called with nil root")
+	}
+	if cmd == nil {
+		panic("cli: WriteMarkdown called with nil cmd")
+	}
+	for i, c := range docCommands(cmd) {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		heading := "##"
+		if c == cmd {
+			heading = "#"
+		}
+		fmt.Fprintf(w, "%s %s\n", heading, commandDisplayName(root, c))
+
+		if desc := commandDescription(c); desc != "" {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, desc)
+		}
+
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "```")
+		fmt.Fprintln(w, usageLine(root, c))
+		fmt.Fprintln(w, "```")
+
+		if flags := flagsForHelp(c); len(flags) > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "Options:")
+			fmt.Fprintln(w)
+			for _, fh := range flags {
+				fmt.Fprintf(w, "- %s\n", markdownItem(flagDisplay(fh), strings.TrimSpace(fh.def.usage)))
+			}
+		}
+
+		if len(c.ArgHelp) > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "Args:")
+			fmt.Fprintln(w)
+			for _, arg := range c.ArgHelp {
+				fmt.Fprintf(w, "- %s\n", markdownItem(arg.Display, arg.Description))
+			}
+		}
+
+		if c.Example != "" {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "Examples:")
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "```")
+			fmt.Fprintln(w, strings.TrimRight(c.Example, "\n"))
+			fmt.Fprintln(w, "```")
+		}
+	}
+}
+
+// ManPageOptions controls WriteManPage.
+type ManPageOptions struct {
+	Section string // Section is the manual section (default "1").
+	Date    string // Date is shown in the page footer (ex: "2026-01-02"). Empty omits it, keeping output reproducible.
+	Source  string // Source is shown in the page footer, typically the program name and version (ex: "mytool 1.2.3").
+	Manual  string // Manual is the manual's title, shown in the page header (ex: "User Commands").
+}
+
+// WriteManPage writes a roff man page for cmd and its visible descendants to w. root supplies the program name and command path. The page has NAME, SYNOPSIS, DESCRIPTION,
+// OPTIONS, ARGUMENTS, and EXAMPLES sections for cmd (each when applicable), followed by a COMMANDS section with a subsection per descendant (as in WriteMarkdown).
+func WriteManPage(w io.Writer, root, cmd *Command, opts ManPageOptions) {
+	if root == nil {
+		panic("cli: WriteManPage called with nil root")
+	}
+	if cmd == nil {
+		panic("cli: WriteManPage called with nil cmd")
+	}
+	section := opts.Section
+	if section == "" {
+		section = "1"
+	}
+	name := commandDisplayName(root, cmd)
+	fmt.Fprintf(w, ".TH %s %s %s %s %s\n", manQuote(strings.ToUpper(strings.ReplaceAll(name, " ", "-"))), manQuote(section), manQuote(opts.Date), manQuote(opts.Source), manQuote(opts.Manual))
+
+	fmt.Fprintln(w, ".SH NAME")
+	if cmd.Short != "" {
+		fmt.Fprintf(w, "%s \\- %s\n", manEscape(name), manEscape(cmd.Short))
+	} else {
+		fmt.Fprintln(w, manEscape(name))
+	}
+
+	fmt.Fprintln(w, ".SH SYNOPSIS")
+	writeManSynopsis(w, root, cmd)
+
+	if desc := commandDescription(cmd); desc != "" {
+		fmt.Fprintln(w, ".SH DESCRIPTION")
+		writeManText(w, desc)
+	}
+	writeManDetails(w, cmd, ".SH")
+
+	descendants := docCommands(cmd)[1:]
+	if len(descendants) > 0 {
+		fmt.Fprintln(w, ".SH COMMANDS")
+		for _, c := range descendants {
+			fmt.Fprintf(w, ".SS %s\n", manQuote(commandDisplayName(root, c)))
+			writeManSynopsis(w, root, c)
+			if desc := commandDescription(c); desc != "" {
+				fmt.Fprintln(w, ".PP")
+				writeManText(w, desc)
+			}
+			writeManDetails(w, c, ".PP\n.B")
+		}
+	}
+}
+
+// docCommands returns cmd followed by its visible descendants, depth-first with siblings sorted by name.
+func docCommands(cmd *Command) []*Command {
+	out := []*Command{cmd}
+	for _, child := range commandsForHelp(cmd, HelpOptions{}) {
+		out = append(out, docCommands(child)...)
+	}
+	return out
+}
+
+// flagDisplay returns the flag forms shown in docs (ex: "-p, --package <string>").
+func flagDisplay(fh flagHelp) string {
+	def := fh.def
+	names := "--" + def.name
+	if def.shorthand != 0 {
+		names = fmt.Sprintf("-%c, --%s", def.shorthand, def.name)
+	}
+	if def.kind != flagBool {
+		names += fmt.Sprintf(" <%s>", fh.kind)
+	}
+	if def.kind == flagStringSlice {
+		names += "..."
+	}
+	return names
+}
+
+func markdownItem(display, description string) string {
+	if description == "" {
+		return "`" + display + "`"
+	}
+	return "`" + display + "`: " + description
+}
+
+// writeManSynopsis writes cmd's usage line, with the command path in bold.
+func writeManSynopsis(w io.Writer, root, cmd *Command) {
+	path := commandDisplayName(root, cmd)
+	rest := strings.TrimPrefix(usageLine(root, cmd), path)
+	fmt.Fprintf(w, ".B %s\n", manEscape(path))
+	if rest = strings.TrimSpace(rest); rest != "" {
+		fmt.Fprintln(w, manEscape(rest))
+	}
+}
+
+// writeManDetails writes cmd's options, args, and examples. heading introduces each block: a section macro (".SH") for the page's command, or a bold paragraph
+// (".PP\n.B") for commands in the COMMANDS section.
+func writeManDetails(w io.Writer, cmd *Command, heading string) {
+	upper := heading == ".SH"
+	title := func(s string) string {
+		if upper {
+			return strings.ToUpper(s)
+		}
+		return s + ":"
+	}
+
+	if flags := flagsForHelp(cmd); len(flags) > 0 {
+		fmt.Fprintf(w, "%s %s\n", heading, title("Options"))
+		for _, fh := range flags {
+			fmt.Fprintln(w, ".TP")
+			fmt.Fprintf(w, "\\fB%s\\fR\n", manEscape(flagDisplay(fh)))
+			writeManText(w, strings.TrimSpace(fh.def.usage))
+		}
+	}
+	if len(cmd.ArgHelp) > 0 {
+		fmt.Fprintf(w, "%s %s\n", heading, title("Arguments"))
+		for _, arg := range cmd.ArgHelp {
+			fmt.Fprintln(w, ".TP")
+			fmt.Fprintf(w, "\\fB%s\\fR\n", manEscape(arg.Display))
+			writeManText(w, arg.Description)
+		}
+	}
+	if cmd.Example != "" {
+		fmt.Fprintf(w, "%s %s\n", heading, title("Examples"))
+		fmt.Fprintln(w, ".PP")
+		fmt.Fprintln(w, ".RS 4")
+		fmt.Fprintln(w, ".nf")
+		writeManText(w, strings.TrimRight(cmd.Example, "\n"))
+		fmt.Fprintln(w, ".fi")
+		fmt.Fprintln(w, ".RE")
+	}
+}
+
+// writeManText writes s as roff text lines. Blank lines become paragraph breaks.
+func writeManText(w io.Writer, s string) {
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) == "" {
+			fmt.Fprintln(w, ".PP")
+			continue
+		}
+		fmt.Fprintln(w, manEscape(line))
+	}
+}
+
+// manEscape escapes s for use as roff text: backslashes and hyphens are escaped, and a leading "." or "'" (which roff would read as a request) is protected.
+func manEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// manQuote returns s escaped and double-quoted, for use as a roff macro argument.
+func manQuote(s string) string {
+	return `"` + strings.ReplaceAll(manEscape(s), `"`, `\(dq`) + `"`
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newDocsTestTree() *Command {
+	root := &Command{Name: "prog", Short: "A test program."}
+	root.PersistentFlags().Bool("verbose", 'v', false, "Verbose output.")
+
+	doc := &Command{Name: "doc", Short: "Docs commands."}
+	add := &Command{
+		Name:    "add",
+		Short:   "Add docs.",
+		Long:    "Add docs to a package.\n\n.Leading dot stays text.",
+		Run:     func(*Context) error { return nil },
+		ArgHelp: []ArgHelp{{Display: "<path/to/pkg>", Description: "Package to document."}},
+		Example: "prog doc add ./internal/foo\n",
+	}
+	add.Flags().String("model", 'm', "", "Model to use.")
+	hidden := &Command{Name: "secret", Hidden: true, Run: func(*Context) error { return nil }}
+	doc.AddCommand(add, hidden)
+	root.AddCommand(doc)
+	return root
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	root := newDocsTestTree()
+	var buf bytes.Buffer
+	WriteMarkdown(&buf, root, root)
+
+	want := "# prog\n" +
+		"\n" +
+		"A test program.\n" +
+		"\n" +
+		"```\n" +
+		"prog [--verbose] <command>\n" +
+		"```\n" +
+		"\n" +
+		"Options:\n" +
+		"\n" +
+		"- `-v, --verbose`: Verbose output.\n" +
+		"\n" +
+		"## prog doc\n" +
+		"\n" +
+		"Docs commands.\n" +
+		"\n" +
+		"```\n" +
+		"prog doc [--verbose] <command>\n" +
+		"```\n" +
+		"\n" +
+		"Options:\n" +
+		"\n" +
+		"- `-v, --verbose`: Verbose output.\n" +
+		"\n" +
+		"## prog doc add\n" +
+		"\n" +
+		"Add docs to a package.\n" +
+		"\n" +
+		".Leading dot stays text.\n" +
+		"\n" +
+		"```\n" +
+		"prog doc add [--model=<STRING>] [--verbose] <path/to/pkg>\n" +
+		"```\n" +
+		"\n" +
+		"Options:\n" +
+		"\n" +
+		"- `-m, --model <string>`: Model to use.\n" +
+		"- `-v, --verbose`: Verbose output.\n" +
+		"\n" +
+		"Args:\n" +
+		"\n" +
+		"- `<path/to/pkg>`: Package to document.\n" +
+		"\n" +
+		"Examples:\n" +
+		"\n" +
+		"```\n" +
+		"prog doc add ./internal/foo\n" +
+		"```\n"
+	assert.Equal(t, want, buf.String())
+
+	// Only the given subtree is documented.
+	buf.Reset()
+	WriteMarkdown(&buf, root, root.Commands()[0].Commands()[0])
+	assert.Contains(t, buf.String(), "# prog doc add\n")
+	assert.NotContains(t, buf.String(), "secret")
+	assert.NotContains(t, buf.String(), "## ")
+}
+
+func TestWriteManPage(t *testing.T) {
+	root := newDocsTestTree()
+	var buf bytes.Buffer
+	WriteManPage(&buf, root, root, ManPageOptions{Source: "prog 1.0", Manual: "User Commands"})
+	page := buf.String()
+
+	assert.Contains(t, page, ".TH \"PROG\" \"1\" \"\" \"prog 1.0\" \"User Commands\"\n")
+	assert.Contains(t, page, ".SH NAME\nprog \\- A test program.\n")
+	assert.Contains(t, page, ".SH SYNOPSIS\n.B prog\n[\\-\\-verbose] <command>\n")
+	assert.Contains(t, page, ".SH OPTIONS\n.TP\n\\fB\\-v, \\-\\-verbose\\fR\nVerbose output.\n")
+	assert.Contains(t, page, ".SH COMMANDS\n.SS \"prog doc\"\n")
+	assert.Contains(t, page, ".SS \"prog doc add\"\n.B prog doc add\n[\\-\\-model=<STRING>] [\\-\\-verbose] <path/to/pkg>\n.PP\nAdd docs to a package.\n.PP\n\\&.Leading dot stays text.\n")
+	assert.Contains(t, page, ".PP\n.B Arguments:\n.TP\n\\fB<path/to/pkg>\\fR\nPackage to document.\n")
+	assert.Contains(t, page, ".PP\n.B Examples:\n.PP\n.RS 4\n.nf\nprog doc add ./internal/foo\n.fi\n.RE\n")
+	assert.NotContains(t, page, "secret")
+
+	buf.Reset()
+	WriteManPage(&buf, root, root.Commands()[0], ManPageOptions{Section: "7", Date: "2026-01-02"})
+	assert.Contains(t, buf.String(), ".TH \"PROG\\-DOC\" \"7\" \"2026\\-01\\-02\" \"\" \"\"\n")
+}
+
+func TestManEscape(t *testing.T) {
+	assert.Equal(t, `a\e\-b`, manEscape(`a\-b`))
+	assert.Equal(t, `\&.x`, manEscape(".x"))
+	assert.Equal(t, `\&'x`, manEscape("'x"))
+	assert.Equal(t, `"say \(dqhi\(dq"`, manQuote(`say "hi"`))
+}
@@ -12,10 +12,11 @@ type flagKind uint8
 
 // flagKind constants identify the supported flag value types.
 const (
-	flagBool     flagKind = iota + 1 // Boolean flags parse bool values and may omit an explicit value.
-	flagString                       // String flags store raw string values.
-	flagInt                          // Integer flags parse decimal int values.
-	flagDuration                     // Duration flags parse time.Duration values such as "5s".
+	flagBool        flagKind = iota + 1 // Boolean flags parse bool values and may omit an explicit value.
+	flagString                          // String flags store raw string values.
+	flagInt                             // Integer flags parse decimal int values.
+	flagDuration                        // Duration flags parse time.Duration values such as "5s".
+	flagStringSlice                     // String slice flags collect one raw string per occurrence.
 )
 
 // FlagSet is a typed flag registry for a command.
@@ -34,6 +35,9 @@ type flagDef struct {
 	stringPtr   *string        // stringPtr receives parsed values when kind is flagString.
 	intPtr      *int           // intPtr receives parsed values when kind is flagInt.
 	durationPtr *time.Duration // durationPtr receives parsed values when kind is flagDuration.
+	slicePtr    *[]string      // slicePtr receives parsed values when kind is flagStringSlice.
+	sliceSet    bool           // sliceSet reports whether slicePtr has been set by parsing (the first occurrence replaces the default).
+	complete    CompleteFunc   // complete is the optional completion hook for the flag's values (see FlagSet.SetComplete).
 }
 
 func newFlagSet() *FlagSet {
@@ -111,6 +115,24 @@ func (fs *FlagSet) Duration(name string, shorthand rune, def time.Duration, usag
 	return ptr
 }
 
+// StringSlice registers a repeatable string flag and returns the storage updated during parsing. Each occurrence appends one value; the first occurrence replaces
+// def.
+func (fs *FlagSet) StringSlice(name string, shorthand rune, def []string, usage string) *[]string {
+	if name == "" {
+		panic("cli: flag name must be non-empty")
+	}
+	ptr := new([]string)
+	*ptr = append([]string(nil), def...)
+	fs.add(&flagDef{
+		name:      name,
+		shorthand: shorthand,
+		usage:     usage,
+		kind:      flagStringSlice,
+		slicePtr:  ptr,
+	})
+	return ptr
+}
+
 // add registers def in fs's long-name and optional shorthand indexes.
 func (fs *FlagSet) add(def *flagDef) {
 	if _, ok := fs.byLong[def.name]; ok {
@@ -191,6 +213,8 @@ func flagsForHelp(cmd *Command) []flagHelp {
 			kind = "int"
 		case flagDuration:
 			kind = "duration"
+		case flagStringSlice:
+			kind = "string"
 		}
 		helps = append(helps, flagHelp{def: def, kind: kind})
 	}
@@ -271,6 +295,13 @@ func setFlagValue(def *flagDef, raw string) error {
 		}
 		*def.durationPtr = v
 		return nil
+	case flagStringSlice:
+		if !def.sliceSet {
+			*def.slicePtr = nil
+			def.sliceSet = true
+		}
+		*def.slicePtr = append(*def.slicePtr, raw)
+		return nil
 	default:
 		return fmt.Errorf("unknown flag kind")
 	}
@@ -226,26 +226,18 @@ func optionUsage(fh flagHelp) string {
 	if def.kind == flagBool {
 		return fmt.Sprintf("[--%s]", def.name)
 	}
+	if def.kind == flagStringSlice {
+		return fmt.Sprintf("[--%s=<%s>...]", def.name, strings.ToUpper(fh.kind))
+	}
 	return fmt.Sprintf("[--%s=<%s>]", def.name, strings.ToUpper(fh.kind))
 }
 
 func formatFlagHelpLine(fh flagHelp) string {
-	def := fh.def
-	var names string
-	if def.shorthand != 0 {
-		names = fmt.Sprintf("-%c, --%s", def.shorthand, def.name)
-	} else {
-		names = fmt.Sprintf("    --%s", def.name)
-	}
-	suffix := ""
-	if def.kind != flagBool {
-		suffix = fmt.Sprintf(" <%s>", fh.kind)
-	}
-	usage := strings.TrimSpace(def.usage)
-	if usage == "" {
-		return formatHelpLine(names+suffix, "")
+	names := flagDisplay(fh)
+	if fh.def.shorthand == 0 {
+		names = "    " + names
 	}
-	return formatHelpLine(names+suffix, usage)
+	return formatHelpLine(names, strings.TrimSpace(fh.def.usage))
 }
 
 func formatArgHelpLine(arg ArgHelp) string {
@@ -51,6 +51,11 @@ func Run(ctx context.Context, root *Command, opts Options) int {
 		errOut = os.Stderr
 	}
 
+	if len(opts.Args) > 0 && opts.Args[0] == completeToken {
+		writeCompletions(out, Complete(root, opts.Args[1:]))
+		return 0
+	}
+
 	selected, args, parseErr := parseArgv(root, opts.Args, out)
 	if parseErr != nil {
 		if errors.Is(parseErr, errHelpPrinted) {
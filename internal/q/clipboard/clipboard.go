@@ -1,6 +1,7 @@
 package clipboard
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"os/exec"
@@ -10,6 +11,12 @@ import (
 // ErrUnavailable indicates that the clipboard is not usable on this system (typically because the required OS integration or command-line utilities are missing).
 var ErrUnavailable = errors.New("clipboard unavailable")
 
+// ErrNoImage indicates that the clipboard does not currently hold an image.
+var ErrNoImage = errors.New("clipboard has no image")
+
+// pngMagic is the signature that begins every PNG file.
+var pngMagic = []byte("\x89PNG\r\n\x1a\n")
+
 // A backend provides clipboard read and write operations.
 type backend interface {
 	// It returns the current clipboard text.
@@ -17,6 +24,9 @@ type backend interface {
 
 	// It replaces the clipboard contents with the provided text.
 	write(string) error
+
+	// It returns the current clipboard image encoded as PNG.
+	readImage() ([]byte, error)
 }
 
 var (
@@ -46,6 +56,22 @@ func Write(s string) error {
 	return b.write(s)
 }
 
+// ReadImage reads an image from the clipboard and returns it encoded as PNG. It returns ErrNoImage if the clipboard holds no image (for instance, only text).
+func ReadImage() ([]byte, error) {
+	b, err := getBackend()
+	if err != nil {
+		return nil, err
+	}
+	data, err := b.readImage()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, pngMagic) {
+		return nil, ErrNoImage
+	}
+	return data, nil
+}
+
 // Available reports whether the clipboard is available on this system.
 //
 // This is intended as a cheap capability check for gating UI/feature flags.
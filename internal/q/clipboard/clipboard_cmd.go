@@ -1,6 +1,9 @@
 package clipboard
 
-import "os/exec"
+import (
+	"fmt"
+	"os/exec"
+)
 
 // A cmdBackend implements backend by using external paste and copy commands.
 type cmdBackend struct {
@@ -8,6 +11,10 @@ type cmdBackend struct {
 	pasteArgs []string // It supplies arguments to pasteCmd.
 	copyCmd   string   // It names the executable used to write clipboard text.
 	copyArgs  []string // It supplies arguments to copyCmd.
+
+	imagePasteCmd  string                       // It names the executable used to read a clipboard image. If empty, images are unsupported.
+	imagePasteArgs []string                     // It supplies arguments to imagePasteCmd.
+	imageDecode    func([]byte) ([]byte, error) // If set, it converts imagePasteCmd's output to PNG bytes.
 }
 
 // The read method runs the configured paste command and returns its standard output as clipboard text. It returns an error if the command cannot run or exits unsuccessfully.
@@ -39,3 +46,19 @@ func (b cmdBackend) write(s string) error {
 	}
 	return cmd.Wait()
 }
+
+// The readImage method runs the configured image paste command and returns its output (decoded by imageDecode, if set). A failing command is reported as ErrNoImage,
+// since paste utilities exit unsuccessfully when the clipboard holds no image of the requested type.
+func (b cmdBackend) readImage() ([]byte, error) {
+	if b.imagePasteCmd == "" {
+		return nil, fmt.Errorf("%s does not support reading images", b.pasteCmd)
+	}
+	out, err := exec.Command(b.imagePasteCmd, b.imagePasteArgs...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoImage, err)
+	}
+	if b.imageDecode != nil {
+		return b.imageDecode(out)
+	}
+	return out, nil
+}
@@ -2,7 +2,12 @@
 
 package clipboard
 
-import "errors"
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
 
 func selectBackend() (backend, error) {
 	if _, err := lookPath("pbcopy"); err != nil {
@@ -11,9 +16,28 @@ func selectBackend() (backend, error) {
 	if _, err := lookPath("pbpaste"); err != nil {
 		return nil, errors.New("missing pbpaste")
 	}
-
 	return cmdBackend{
 		pasteCmd: "pbpaste",
 		copyCmd:  "pbcopy",
+
+		// pbpaste only handles text, so images are read through AppleScript.
+		imagePasteCmd:  "osascript",
+		imagePasteArgs: []string{"-e", "get the clipboard as «class PNGf»"},
+		imageDecode:    decodeAppleScriptPNG,
 	}, nil
 }
+
+// decodeAppleScriptPNG decodes osascript's printed form of PNG clipboard data (ex: «data PNGf89504E47...»).
+func decodeAppleScriptPNG(out []byte) ([]byte, error) {
+	const prefix = "«data PNGf"
+	out = bytes.TrimSpace(out)
+	if !bytes.HasPrefix(out, []byte(prefix)) || !bytes.HasSuffix(out, []byte("»")) {
+		return nil, ErrNoImage
+	}
+	hexData := out[len(prefix) : len(out)-len("»")]
+	data := make([]byte, hex.DecodedLen(len(hexData)))
+	if _, err := hex.Decode(data, hexData); err != nil {
+		return nil, fmt.Errorf("decode clipboard image: %w", err)
+	}
+	return data, nil
+}
@@ -44,3 +44,12 @@ func TestAvailableTrueWhenCommandsPresent(t *testing.T) {
 
 	require.True(t, Available())
 }
+
+func TestDecodeAppleScriptPNG(t *testing.T) {
+	data, err := decodeAppleScriptPNG([]byte("«data PNGf89504E470D0A1A0A»\n"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("\x89PNG\r\n\x1a\n"), data)
+
+	_, err = decodeAppleScriptPNG([]byte("some text"))
+	require.ErrorIs(t, err, ErrNoImage)
+}
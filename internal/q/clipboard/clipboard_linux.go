@@ -10,9 +10,11 @@ func selectBackend() (backend, error) {
 		if _, err := lookPath("wl-copy"); err == nil {
 			if _, err := lookPath("wl-paste"); err == nil {
 				return cmdBackend{
-					pasteCmd:  "wl-paste",
-					pasteArgs: []string{"--no-newline"},
-					copyCmd:   "wl-copy",
+					pasteCmd:       "wl-paste",
+					pasteArgs:      []string{"--no-newline"},
+					copyCmd:        "wl-copy",
+					imagePasteCmd:  "wl-paste",
+					imagePasteArgs: []string{"--no-newline", "--type", "image/png"},
 				}, nil
 			}
 		}
@@ -24,9 +26,13 @@ func selectBackend() (backend, error) {
 			pasteArgs: []string{"-out", "-selection", "clipboard"},
 			copyCmd:   "xclip",
 			copyArgs:  []string{"-in", "-selection", "clipboard"},
+
+			imagePasteCmd:  "xclip",
+			imagePasteArgs: []string{"-out", "-selection", "clipboard", "-target", "image/png"},
 		}, nil
 	}
 
+	// xsel only handles text; reading images with it is unsupported.
 	if _, err := lookPath("xsel"); err == nil {
 		return cmdBackend{
 			pasteCmd:  "xsel",
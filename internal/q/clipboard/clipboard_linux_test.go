@@ -35,6 +35,8 @@ func TestSelectBackendPrefersWaylandWhenAvailable(t *testing.T) {
 	require.Equal(t, []string{"--no-newline"}, cb.pasteArgs)
 	require.Equal(t, "wl-copy", cb.copyCmd)
 	require.Empty(t, cb.copyArgs)
+	require.Equal(t, "wl-paste", cb.imagePasteCmd)
+	require.Equal(t, []string{"--no-newline", "--type", "image/png"}, cb.imagePasteArgs)
 }
 
 func TestSelectBackendFallsBackToXclipWhenWaylandMissingTools(t *testing.T) {
@@ -65,6 +67,8 @@ func TestSelectBackendFallsBackToXclipWhenWaylandMissingTools(t *testing.T) {
 	require.Equal(t, []string{"-out", "-selection", "clipboard"}, cb.pasteArgs)
 	require.Equal(t, "xclip", cb.copyCmd)
 	require.Equal(t, []string{"-in", "-selection", "clipboard"}, cb.copyArgs)
+	require.Equal(t, "xclip", cb.imagePasteCmd)
+	require.Equal(t, []string{"-out", "-selection", "clipboard", "-target", "image/png"}, cb.imagePasteArgs)
 }
 
 func TestSelectBackendUsesXselIfXclipMissing(t *testing.T) {
@@ -85,6 +89,10 @@ func TestSelectBackendUsesXselIfXclipMissing(t *testing.T) {
 	require.Equal(t, []string{"--output", "--clipboard"}, cb.pasteArgs)
 	require.Equal(t, "xsel", cb.copyCmd)
 	require.Equal(t, []string{"--input", "--clipboard"}, cb.copyArgs)
+	require.Empty(t, cb.imagePasteCmd)
+
+	_, err = cb.readImage()
+	require.ErrorContains(t, err, "xsel does not support reading images")
 }
 
 func TestBackendUnavailableWhenNoTools(t *testing.T) {
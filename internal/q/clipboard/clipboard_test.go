@@ -0,0 +1,41 @@
+package clipboard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend is an in-memory backend for tests.
+type fakeBackend struct {
+	text  string
+	image []byte
+}
+
+func (b *fakeBackend) read() (string, error)      { return b.text, nil }
+func (b *fakeBackend) write(s string) error       { b.text = s; return nil }
+func (b *fakeBackend) readImage() ([]byte, error) { return b.image, nil }
+
+func useFakeBackend(t *testing.T, b *fakeBackend) {
+	t.Helper()
+
+	resetForTest(t)
+	backendOnce.Do(func() {})
+	backendImpl = b
+}
+
+func TestReadImageReturnsPNG(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), 1, 2, 3)
+	useFakeBackend(t, &fakeBackend{image: png})
+
+	data, err := ReadImage()
+	require.NoError(t, err)
+	require.Equal(t, png, data)
+}
+
+func TestReadImageRejectsNonPNG(t *testing.T) {
+	useFakeBackend(t, &fakeBackend{image: []byte("hello")})
+
+	_, err := ReadImage()
+	require.ErrorIs(t, err, ErrNoImage)
+}
@@ -3,6 +3,9 @@
 package clipboard
 
 import (
+	"encoding/base64"
+	"fmt"
+	"os/exec"
 	"runtime"
 	"syscall"
 	"time"
@@ -158,3 +161,20 @@ func (winBackend) write(text string) error {
 	}
 	return nil
 }
+
+// imageScript writes the clipboard image to standard output as base64-encoded PNG, or exits with status 1 when the clipboard holds no image.
+const imageScript = `Add-Type -AssemblyName System.Windows.Forms, System.Drawing
+$img = [System.Windows.Forms.Clipboard]::GetImage()
+if ($img -eq $null) { exit 1 }
+$ms = New-Object System.IO.MemoryStream
+$img.Save($ms, [System.Drawing.Imaging.ImageFormat]::Png)
+[Console]::Out.Write([Convert]::ToBase64String($ms.ToArray()))`
+
+// readImage uses PowerShell (which exposes the clipboard's bitmap as a .NET image) rather than decoding CF_DIB data directly.
+func (winBackend) readImage() ([]byte, error) {
+	out, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-STA", "-Command", imageScript).Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoImage, err)
+	}
+	return base64.StdEncoding.DecodeString(string(out))
+}
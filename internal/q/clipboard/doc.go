@@ -1,4 +1,4 @@
-// Package clipboard reads and writes text from the system clipboard.
+// Package clipboard reads and writes text from the system clipboard, and reads clipboard images as PNG.
 //
 // It supports Linux, Windows, and macOS. Use Available to gate clipboard-dependent features; operations may return ErrUnavailable when the current system does not
 // provide usable clipboard access.
@@ -13,6 +13,7 @@ import (
 	"syscall"
 	"text/template"
 	"time"
+	"unicode/utf8"
 )
 
 // InputType represents the type of value that a command expects for a given input key.
@@ -38,8 +39,13 @@ type Runner struct {
 	inputSchema    map[string]InputType // inputSchema defines accepted input keys and their expected types.
 	requiredInputs []string             // requiredInputs names schema keys that must be provided to Run.
 	commands       []Command            // commands contains the commands executed by Run in registration order.
+	maxParallel    int                  // maxParallel is the maximum number of commands run at once. Values below 1 mean 1.
+	findingFilter  func(string) bool    // findingFilter, if set, reports whether a finding's absolute path should be kept.
 }
 
+// waitDelay bounds how long a killed command's output pipes are drained after a timeout or cancellation (ex: when a grandchild process still holds them open).
+const waitDelay = 2 * time.Second
+
 // NewRunner constructs a Runner with the provided schema and required inputs. Defensive copies are taken to ensure subsequent callers cannot mutate the Runner's
 // internal state by modifying the arguments.
 func NewRunner(inputSchema map[string]InputType, requiredInputs []string) *Runner {
@@ -97,7 +103,11 @@ func (r *Runner) Run(ctx context.Context, rootDir string, inputs map[string]any)
 	helpers := newTemplateHelperProvider(absRoot, normalizedInputs)
 	funcs := helpers.funcMap()
 
-	results := make([]CommandResult, len(r.commands))
+	if err := validateDependencies(r.commands); err != nil {
+		return Result{}, err
+	}
+
+	prepared := make([]preparedCommand, len(r.commands))
 	for i, cmd := range r.commands {
 		if len(cmd.Attrs)%2 != 0 {
 			return Result{}, fmt.Errorf("cmdrunner: command[%d]: attrs must have even length, got %d", i, len(cmd.Attrs))
@@ -165,20 +175,44 @@ func (r *Runner) Run(ctx context.Context, rootDir string, inputs map[string]any)
 			renderedEnv = append(renderedEnv, trimmed)
 		}
 
-		initial := CommandResult{
-			Command:           renderedCommand,
-			Args:              renderedArgs,
-			CWD:               renderedCWD,
-			Env:               append([]string(nil), renderedEnv...),
-			MessageIfNoOutput: cmd.MessageIfNoOutput,
-			ShowCWD:           cmd.ShowCWD,
-			Attrs:             append([]string(nil), cmd.Attrs...),
+		var timeout time.Duration
+		if cmd.Timeout != "" {
+			timeout, err = time.ParseDuration(strings.TrimSpace(cmd.Timeout))
+			if err != nil || timeout <= 0 {
+				return Result{}, fmt.Errorf("cmdrunner: command[%d]: timeout must be a positive duration (ex: \"30s\"), got %q", i, cmd.Timeout)
+			}
+		}
+		if cmd.MaxOutputBytes < 0 {
+			return Result{}, fmt.Errorf("cmdrunner: command[%d]: maxoutputbytes must not be negative, got %d", i, cmd.MaxOutputBytes)
+		}
+		matchers := make([]problemMatcher, 0, len(cmd.ProblemMatchers))
+		for j, m := range cmd.ProblemMatchers {
+			pm, err := compileProblemMatcher(m)
+			if err != nil {
+				return Result{}, fmt.Errorf("cmdrunner: command[%d]: problemmatchers[%d]: %w", i, j, err)
+			}
+			matchers = append(matchers, pm)
 		}
 
-		results[i] = executeCommand(ctx, cmd, renderedEnv, initial)
+		prepared[i] = preparedCommand{
+			cmd:      cmd,
+			env:      renderedEnv,
+			timeout:  timeout,
+			matchers: matchers,
+			initial: CommandResult{
+				ID:                cmd.ID,
+				Command:           renderedCommand,
+				Args:              renderedArgs,
+				CWD:               renderedCWD,
+				Env:               append([]string(nil), renderedEnv...),
+				MessageIfNoOutput: cmd.MessageIfNoOutput,
+				ShowCWD:           cmd.ShowCWD,
+				Attrs:             append([]string(nil), cmd.Attrs...),
+			},
+		}
 	}
 
-	return Result{Results: results}, nil
+	return Result{Results: r.execute(ctx, absRoot, prepared)}, nil
 }
 
 // AddCommand registers the provided command with the Runner.
@@ -186,6 +220,176 @@ func (r *Runner) AddCommand(c Command) {
 	r.commands = append(r.commands, c)
 }
 
+// SetMaxParallel sets the maximum number of commands Run executes at once. The default (and any n below 1) is 1: commands run one at a time, in registration order
+// except where DependsOn requires otherwise. Results are always in registration order, regardless of n.
+func (r *Runner) SetMaxParallel(n int) {
+	r.maxParallel = n
+}
+
+// SetFindingFilter clips findings to those whose absolute path keep accepts (ex: files in the current code unit). Rejected findings are counted in CommandResult.ClippedFindings.
+// A nil keep (the default) keeps all findings.
+func (r *Runner) SetFindingFilter(keep func(absPath string) bool) {
+	r.findingFilter = keep
+}
+
+// preparedCommand is a command whose templates have been rendered, ready to execute.
+type preparedCommand struct {
+	cmd      Command          // cmd is the original, unrendered command.
+	env      []string         // env are the rendered KEY=VALUE entries.
+	timeout  time.Duration    // timeout is the parsed Command.Timeout, or 0 for none.
+	matchers []problemMatcher // matchers are the compiled Command.ProblemMatchers.
+	initial  CommandResult    // initial is the result populated with rendered fields, before execution.
+}
+
+// execute runs prepared commands, at most r.maxParallel at a time, and returns their results in the same order as prepared. A command starts once all commands it
+// depends on have finished; if one of them did not succeed, the command is skipped instead. Among ready commands, lower indexes start first.
+//
+// Findings are extracted from each executed command's output, with paths reported relative to rootDir.
+func (r *Runner) execute(ctx context.Context, rootDir string, prepared []preparedCommand) []CommandResult {
+	maxParallel := max(r.maxParallel, 1)
+
+	indexByID := make(map[string]int, len(prepared))
+	for i, p := range prepared {
+		if p.cmd.ID != "" {
+			indexByID[p.cmd.ID] = i
+		}
+	}
+
+	results := make([]CommandResult, len(prepared))
+	started := make([]bool, len(prepared))
+	finished := make([]bool, len(prepared))
+	done := make(chan int)
+	running := 0
+	completed := 0
+
+	// ready reports whether all of prepared[i]'s dependencies have finished, and the ID of the first one that did not succeed (if any).
+	ready := func(i int) (bool, string) {
+		failed := ""
+		for _, dep := range prepared[i].cmd.DependsOn {
+			j := indexByID[dep]
+			if !finished[j] {
+				return false, ""
+			}
+			if failed == "" && results[j].Outcome != OutcomeSuccess {
+				failed = dep
+			}
+		}
+		return true, failed
+	}
+
+	for completed < len(prepared) {
+		for progressed := true; progressed; {
+			progressed = false
+			for i := range prepared {
+				if started[i] {
+					continue
+				}
+				ok, failedDep := ready(i)
+				if !ok {
+					continue
+				}
+				if failedDep != "" {
+					started[i], finished[i] = true, true
+					results[i] = skippedResult(prepared[i].initial, failedDep)
+					completed++
+					progressed = true
+					continue
+				}
+				if running >= maxParallel {
+					continue
+				}
+				started[i] = true
+				running++
+				progressed = true
+				go func(i int) {
+					p := prepared[i]
+					res := executeCommand(ctx, p.cmd, p.env, p.timeout, p.initial)
+					if len(p.matchers) > 0 {
+						res.Findings, res.ClippedFindings, res.UnmatchedOutput = matchFindings(res.Output, res.CWD, rootDir, p.matchers, r.findingFilter)
+						// A failure whose only problems were clipped isn't a failure of the code the filter keeps. Truncated output may have hidden other problems, so it
+						// stays a failure.
+						if res.Outcome == OutcomeFailed && res.ExecStatus == ExecStatusCompleted && len(res.Findings) == 0 && res.ClippedFindings > 0 && res.UnmatchedOutput == "" && res.OmittedOutputBytes == 0 {
+							res.Outcome = OutcomeSuccess
+						}
+					}
+					results[i] = res
+					done <- i
+				}(i)
+			}
+		}
+		if completed == len(prepared) {
+			break
+		}
+		i := <-done
+		running--
+		finished[i] = true
+		completed++
+	}
+
+	return results
+}
+
+// skippedResult returns result marked as skipped because the dependency with ID failedDep did not succeed.
+func skippedResult(result CommandResult, failedDep string) CommandResult {
+	result.ExecStatus = ExecStatusSkipped
+	result.ExecError = fmt.Errorf("dependency %q did not succeed", failedDep)
+	result.ExitCode = -1
+	result.FailedDependency = failedDep
+	result.Outcome = OutcomeFailed
+	return result
+}
+
+// validateDependencies checks that command IDs are unique, that DependsOn only names IDs of other commands, and that dependencies have no cycles.
+func validateDependencies(commands []Command) error {
+	indexByID := make(map[string]int, len(commands))
+	for i, c := range commands {
+		if c.ID == "" {
+			continue
+		}
+		if _, ok := indexByID[c.ID]; ok {
+			return fmt.Errorf("cmdrunner: command[%d]: duplicate id %q", i, c.ID)
+		}
+		indexByID[c.ID] = i
+	}
+	for i, c := range commands {
+		for _, dep := range c.DependsOn {
+			j, ok := indexByID[dep]
+			if !ok {
+				return fmt.Errorf("cmdrunner: command[%d]: depends on unknown id %q", i, dep)
+			}
+			if j == i {
+				return fmt.Errorf("cmdrunner: command[%d]: depends on itself", i)
+			}
+		}
+	}
+
+	// Depth-first search for cycles. state: 0 = unvisited, 1 = visiting, 2 = done.
+	state := make([]int, len(commands))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case 1:
+			return fmt.Errorf("cmdrunner: command[%d]: dependency cycle through id %q", i, commands[i].ID)
+		case 2:
+			return nil
+		}
+		state[i] = 1
+		for _, dep := range commands[i].DependsOn {
+			if err := visit(indexByID[dep]); err != nil {
+				return err
+			}
+		}
+		state[i] = 2
+		return nil
+	}
+	for i := range commands {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // A Command is a templated command to run. The Command/Args/CWD/Env fields support templates.
 type Command struct {
 	Command string   `json:"command"` // Command is the executable name or path template.
@@ -207,11 +411,28 @@ type Command struct {
 	//
 	// This can be used to communicate metadata to a consuming LLM about the command.
 	Attrs []string `json:"attrs"`
+
+	// ID optionally names the command so other commands can depend on it. IDs must be unique within a Runner.
+	ID string `json:"id"`
+
+	// DependsOn lists IDs of commands that must finish before this one starts. If any of them does not succeed, this command is skipped (ExecStatusSkipped). Cycles
+	// and unknown IDs make Run return an error.
+	DependsOn []string `json:"dependson"`
+
+	// Timeout optionally limits how long the command may run, as a Go duration string (ex: "90s"). A command that exceeds it is killed and has ExecStatusTimedOut.
+	Timeout string `json:"timeout"`
+
+	// MaxOutputBytes optionally limits how much output is kept. Output beyond the limit is discarded (the command keeps running) and counted in CommandResult.OmittedOutputBytes.
+	// 0 means no limit.
+	MaxOutputBytes int `json:"maxoutputbytes"`
+
+	// ProblemMatchers optionally extract structured findings from the command's output into CommandResult.Findings. Matchers are applied in order; findings are deduplicated.
+	ProblemMatchers []ProblemMatcher `json:"problemmatchers"`
 }
 
 // Result aggregates all command executions performed by Run.
 type Result struct {
-	Results []CommandResult // Results contains command results in execution order.
+	Results []CommandResult // Results contains command results in registration order, even when commands run in parallel.
 }
 
 // Success returns true if all results are OutcomeSuccess.
@@ -234,6 +455,7 @@ const (
 	ExecStatusTimedOut      ExecStatus = "timed_out"       // ExecStatusTimedOut means execution stopped because the context deadline was exceeded.
 	ExecStatusCanceled      ExecStatus = "canceled"        // ExecStatusCanceled means execution stopped because the context was canceled.
 	ExecStatusTerminated    ExecStatus = "terminated"      // by signal; see Signal
+	ExecStatusSkipped       ExecStatus = "skipped"         // not run because a dependency did not succeed; see FailedDependency
 )
 
 // Outcome is a semantic command-relative status. Examples:
@@ -256,11 +478,12 @@ const (
 
 // CommandResult captures the execution details for a single command.
 type CommandResult struct {
+	ID                string   // ID is the Command's ID, if any.
 	Command           string   // Command is the actual, rendered command run.
 	Args              []string // Args are the actual, rendered args used.
 	CWD               string   // CWD is the actual, rendered CWD used.
 	Env               []string // Env are the rendered KEY=VALUE entries configured on the Command.
-	Output            string   // The stdout + stderr of the command.
+	Output            string   // The stdout + stderr of the command (up to Command.MaxOutputBytes, if set).
 	MessageIfNoOutput string   // If non-empty and Output is empty, will render a `message` attribute in `ToXML` set to this value.
 	ShowCWD           bool     // If ShowCWD, adds a `cwd` attribute to the opening tag in `ToXML`, showing the CWD from which the command was run.
 
@@ -274,6 +497,13 @@ type CommandResult struct {
 	Signal     string        // if the command was terminated due to a signal (ex: "TERM")
 	Outcome    Outcome       // Outcome is the semantic command result after applying command-specific success rules.
 	Duration   time.Duration // Duration is the elapsed time spent executing the command.
+
+	OmittedOutputBytes int    // OmittedOutputBytes counts output bytes discarded because of Command.MaxOutputBytes.
+	FailedDependency   string // If ExecStatus is ExecStatusSkipped, FailedDependency is the ID of the dependency that did not succeed.
+
+	Findings        []Finding // Findings are extracted from Output by the Command's ProblemMatchers, deduplicated, in order of first appearance.
+	ClippedFindings int       // ClippedFindings counts findings dropped by the Runner's finding filter.
+	UnmatchedOutput string    // UnmatchedOutput holds the non-blank lines of Output that no ProblemMatcher recognized. Set only when matchers are configured.
 }
 
 // normalizeInputs validates inputs against the runner schema and returns normalized values.
@@ -398,8 +628,14 @@ func renderTemplate(name, tmpl string, funcs template.FuncMap, data map[string]a
 	return buf.String(), nil
 }
 
-// executeCommand executes a rendered command and returns its populated result.
-func executeCommand(ctx context.Context, cmd Command, env []string, result CommandResult) CommandResult {
+// executeCommand executes a rendered command and returns its populated result. A positive timeout limits how long the command may run.
+func executeCommand(ctx context.Context, cmd Command, env []string, timeout time.Duration, result CommandResult) CommandResult {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	start := time.Now()
 	defer func() {
 		if result.Duration == 0 {
@@ -417,13 +653,14 @@ func executeCommand(ctx context.Context, cmd Command, env []string, result Comma
 
 	execCmd := exec.CommandContext(ctx, result.Command, result.Args...)
 	execCmd.Dir = result.CWD
+	execCmd.WaitDelay = waitDelay
 	if len(env) > 0 {
 		execCmd.Env = append(os.Environ(), env...)
 	}
 
 	var buf bytes.Buffer
 	var bufMu sync.Mutex
-	writer := &lockedBuffer{buf: &buf, mu: &bufMu}
+	writer := &lockedBuffer{buf: &buf, mu: &bufMu, limit: cmd.MaxOutputBytes}
 	execCmd.Stdout = writer
 	execCmd.Stderr = writer
 
@@ -432,12 +669,14 @@ func executeCommand(ctx context.Context, cmd Command, env []string, result Comma
 		result.ExecStatus = ExecStatusFailedToStart
 		result.ExitCode = -1
 		result.Output = writer.String()
+		result.OmittedOutputBytes = writer.omitted()
 		result.Outcome = determineOutcome(result.ExitCode, result.ExecStatus, result.Output, cmd.OutcomeFailIfAnyOutput)
 		return result
 	}
 
 	waitErr := execCmd.Wait()
 	result.Output = writer.String()
+	result.OmittedOutputBytes = writer.omitted()
 	result.Duration = time.Since(start)
 
 	state := execCmd.ProcessState
@@ -510,17 +749,57 @@ func determineOutcome(exitCode int, status ExecStatus, output string, failIfOutp
 
 // lockedBuffer is a concurrency-safe wrapper around a bytes.Buffer.
 type lockedBuffer struct {
-	buf *bytes.Buffer // buf stores the collected bytes.
-	mu  *sync.Mutex   // mu protects all access to buf.
+	buf   *bytes.Buffer // buf stores the collected bytes.
+	mu    *sync.Mutex   // mu protects all access to buf.
+	limit int           // limit is the maximum number of bytes kept in buf; 0 means no limit.
+	drops int           // drops counts bytes discarded because of limit.
 }
 
-// Write appends p to the buffer while holding the buffer lock.
+// Write appends p to the buffer while holding the buffer lock. Bytes beyond limit are counted and discarded, but still reported as written. The kept output ends
+// on a rune boundary, and once anything is discarded, later writes are discarded too (so kept output is always a prefix of the full output).
 func (b *lockedBuffer) Write(p []byte) (int, error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	if b.limit > 0 {
+		room := max(b.limit-b.buf.Len(), 0)
+		if b.drops > 0 {
+			room = 0
+		}
+		if len(p) > room {
+			cut := room
+			for cut > 0 && !utf8.RuneStart(p[cut]) {
+				cut--
+			}
+			b.drops += len(p) - cut
+			b.buf.Write(p[:cut])
+			b.trimPartialRune()
+			return len(p), nil
+		}
+	}
 	return b.buf.Write(p)
 }
 
+// trimPartialRune removes an incomplete UTF-8 sequence from the end of buf (ex: left by an earlier write that ended mid-rune when the next one is cut), counting
+// its bytes as dropped. The caller must hold mu.
+func (b *lockedBuffer) trimPartialRune() {
+	data := b.buf.Bytes()
+	start := len(data) - 1
+	for start > 0 && start > len(data)-utf8.UTFMax && !utf8.RuneStart(data[start]) {
+		start--
+	}
+	if start >= 0 && !utf8.FullRune(data[start:]) {
+		b.drops += len(data) - start
+		b.buf.Truncate(start)
+	}
+}
+
+// omitted returns the number of bytes discarded because of limit.
+func (b *lockedBuffer) omitted() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.drops
+}
+
 // String returns the current buffer contents while holding the buffer lock.
 func (b *lockedBuffer) String() string {
 	b.mu.Lock()
@@ -0,0 +1,332 @@
+package cmdrunner
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A ProblemMatcher turns command output into Findings. Either Preset or Pattern must be set, but not both.
+type ProblemMatcher struct {
+	// Preset names a built-in matcher: "go-build", "go-vet", "staticcheck", "golangci-lint-json" (output of `golangci-lint run --out-format=json`), or "gofmt-l".
+	Preset string `json:"preset"`
+
+	// Pattern is a regexp matched against each output line. Named groups supply finding fields: path (required), line, column, severity, rule, and message.
+	Pattern string `json:"pattern"`
+
+	// Severity, Rule, and Message are defaults for findings whose match doesn't capture them. Severity defaults to SeverityError.
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	Message  string   `json:"message"`
+}
+
+// Severity is the severity of a Finding.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Finding is a single diagnostic extracted from command output by a ProblemMatcher.
+type Finding struct {
+	Path     string   // Path is slash-separated and relative to the Runner's rootDir when inside it; otherwise it is absolute.
+	Line     int      // Line is 1-based; 0 if unknown.
+	Column   int      // Column is 1-based; 0 if unknown.
+	Severity Severity // Severity is SeverityError, SeverityWarning, or SeverityInfo.
+	Rule     string   // Rule is the check that produced the finding (ex: "SA4006", "errcheck"), if known.
+	Message  string   // Message describes the problem.
+}
+
+// String renders f compactly on one line (ex: "pkg/a.go:3:2: warning: this value of err is never used (SA4006)"). Unknown fields are omitted.
+func (f Finding) String() string {
+	var b strings.Builder
+	b.WriteString(f.Path)
+	if f.Line > 0 {
+		fmt.Fprintf(&b, ":%d", f.Line)
+		if f.Column > 0 {
+			fmt.Fprintf(&b, ":%d", f.Column)
+		}
+	}
+	b.WriteString(":")
+	if f.Severity != "" {
+		b.WriteString(" ")
+		b.WriteString(string(f.Severity))
+		b.WriteString(":")
+	}
+	if f.Message != "" {
+		b.WriteString(" ")
+		b.WriteString(f.Message)
+	}
+	if f.Rule != "" {
+		b.WriteString(" (")
+		b.WriteString(f.Rule)
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+// Problem matcher presets.
+const (
+	presetGoBuild          = "go-build"
+	presetGoVet            = "go-vet"
+	presetStaticcheck      = "staticcheck"
+	presetGolangciLintJSON = "golangci-lint-json"
+	presetGofmtL           = "gofmt-l"
+)
+
+// goPositionPattern matches `path.go:line[:column]: message` lines as printed by the go command and go vet (which may prefix lines with "vet: ").
+const goPositionPattern = `^(?:vet: )?(?P<path>[^\s:][^:]*\.go):(?P<line>\d+)(?::(?P<column>\d+))?: (?P<message>.+)$`
+
+// goPackageHeaderPattern matches the "# import/path" lines the go command prints above a package's errors.
+const goPackageHeaderPattern = `^# \S+$`
+
+// presetSkipPatterns maps preset names to patterns for lines that carry no problem of their own (ex: headers) and are dropped rather than kept as unmatched output.
+var presetSkipPatterns = map[string]string{
+	presetGoBuild: goPackageHeaderPattern,
+	presetGoVet:   goPackageHeaderPattern,
+}
+
+// presetMatchers maps preset names to their regexp-based definitions. presetGolangciLintJSON isn't regexp-based and is handled by parseGolangciLintJSON.
+var presetMatchers = map[string]ProblemMatcher{
+	presetGoBuild: {Pattern: goPositionPattern, Severity: SeverityError},
+	presetGoVet:   {Pattern: goPositionPattern, Severity: SeverityWarning, Rule: "vet"},
+	presetStaticcheck: {
+		Pattern:  `^(?P<path>[^\s:][^:]*\.go):(?P<line>\d+):(?P<column>\d+): (?P<message>.+?)(?: \((?P<rule>[A-Z]+\d+)\))?$`,
+		Severity: SeverityWarning,
+	},
+	presetGofmtL: {Pattern: `^(?P<path>\S.*\.go)$`, Severity: SeverityWarning, Rule: "gofmt", Message: "file is not gofmt-formatted"},
+}
+
+// problemMatcher is a validated, compiled ProblemMatcher.
+type problemMatcher struct {
+	re           *regexp.Regexp // re is matched against each line; nil when golangciJSON is set.
+	skip         *regexp.Regexp // skip, if set, matches lines that are neither findings nor unmatched output.
+	golangciJSON bool           // golangciJSON selects parseGolangciLintJSON instead of re.
+	severity     Severity       // severity is the default severity.
+	rule         string         // rule is the default rule.
+	message      string         // message is the default message.
+}
+
+// compileProblemMatcher validates m and compiles it.
+func compileProblemMatcher(m ProblemMatcher) (problemMatcher, error) {
+	if m.Preset != "" && m.Pattern != "" {
+		return problemMatcher{}, fmt.Errorf("preset and pattern are mutually exclusive")
+	}
+	if m.Preset == "" && m.Pattern == "" {
+		return problemMatcher{}, fmt.Errorf("one of preset or pattern is required")
+	}
+
+	def := m
+	if m.Preset != "" {
+		preset, ok := presetMatchers[m.Preset]
+		if !ok && m.Preset != presetGolangciLintJSON {
+			return problemMatcher{}, fmt.Errorf("unknown preset %q", m.Preset)
+		}
+		def = preset
+		// Explicit defaults override the preset's.
+		if m.Severity != "" {
+			def.Severity = m.Severity
+		}
+		if m.Rule != "" {
+			def.Rule = m.Rule
+		}
+		if m.Message != "" {
+			def.Message = m.Message
+		}
+	}
+
+	pm := problemMatcher{rule: def.Rule, message: def.Message}
+	if def.Severity == "" {
+		def.Severity = SeverityError
+		if m.Preset == presetGolangciLintJSON {
+			def.Severity = SeverityWarning
+		}
+	}
+	sev, ok := normalizeSeverity(string(def.Severity))
+	if !ok {
+		return problemMatcher{}, fmt.Errorf("unknown severity %q", def.Severity)
+	}
+	pm.severity = sev
+
+	if m.Preset == presetGolangciLintJSON {
+		pm.golangciJSON = true
+		return pm, nil
+	}
+
+	re, err := regexp.Compile(def.Pattern)
+	if err != nil {
+		return problemMatcher{}, fmt.Errorf("invalid pattern: %w", err)
+	}
+	if re.SubexpIndex("path") < 0 {
+		return problemMatcher{}, fmt.Errorf("pattern must have a named group \"path\"")
+	}
+	pm.re = re
+	if skip, ok := presetSkipPatterns[m.Preset]; ok {
+		pm.skip = regexp.MustCompile(skip)
+	}
+	return pm, nil
+}
+
+// normalizeSeverity maps common severity spellings (case-insensitive) to a Severity.
+func normalizeSeverity(s string) (Severity, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "error", "err", "fatal", "failure":
+		return SeverityError, true
+	case "warning", "warn":
+		return SeverityWarning, true
+	case "info", "information", "note", "hint":
+		return SeverityInfo, true
+	default:
+		return "", false
+	}
+}
+
+// matchFindings applies matchers to output in order and returns the findings, deduplicated in order of first appearance. Relative paths are resolved against cwd
+// and reported relative to rootDir. If keep is non-nil, findings whose absolute path keep rejects are dropped and counted in clipped. unmatched holds the non-blank
+// lines of output that no matcher recognized; a golangci-lint-json matcher that finds its JSON object recognizes all of output.
+func matchFindings(output string, cwd string, rootDir string, matchers []problemMatcher, keep func(absPath string) bool) (findings []Finding, clipped int, unmatched string) {
+	type rawFinding struct {
+		absPath string
+		finding Finding
+	}
+	var raw []rawFinding
+	add := func(pm problemMatcher, path string, line, column int, severity, rule, message string) {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			return
+		}
+		absPath := filepath.FromSlash(path)
+		if !filepath.IsAbs(absPath) {
+			absPath = filepath.Join(cwd, absPath)
+		}
+		absPath = filepath.Clean(absPath)
+
+		f := Finding{Path: displayPath(absPath, rootDir), Line: line, Column: column, Severity: pm.severity, Rule: pm.rule, Message: pm.message}
+		if sev, ok := normalizeSeverity(severity); ok {
+			f.Severity = sev
+		}
+		if rule = strings.TrimSpace(rule); rule != "" {
+			f.Rule = rule
+		}
+		if message = strings.TrimSpace(message); message != "" {
+			f.Message = message
+		}
+		raw = append(raw, rawFinding{absPath: absPath, finding: f})
+	}
+
+	lines := strings.Split(output, "\n")
+	recognized := make([]bool, len(lines))
+	for _, pm := range matchers {
+		if pm.golangciJSON {
+			issues, start, end, ok := parseGolangciLintJSON(output)
+			if ok {
+				// Only the lines holding the JSON object are recognized; logging around it (ex: level=error typecheck messages) stays unmatched.
+				offset := 0
+				for i, line := range lines {
+					if offset < end && offset+len(line) > start {
+						recognized[i] = true
+					}
+					offset += len(line) + 1
+				}
+			}
+			for _, issue := range issues {
+				add(pm, issue.Pos.Filename, issue.Pos.Line, issue.Pos.Column, issue.Severity, issue.FromLinter, issue.Text)
+			}
+			continue
+		}
+		for i, line := range lines {
+			line = strings.TrimRight(line, "\r")
+			if pm.skip != nil && pm.skip.MatchString(line) {
+				recognized[i] = true
+				continue
+			}
+			m := pm.re.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			recognized[i] = true
+			group := func(name string) string {
+				if i := pm.re.SubexpIndex(name); i >= 0 {
+					return m[i]
+				}
+				return ""
+			}
+			lineNum, _ := strconv.Atoi(group("line"))
+			colNum, _ := strconv.Atoi(group("column"))
+			add(pm, group("path"), lineNum, colNum, group("severity"), group("rule"), group("message"))
+		}
+	}
+
+	seen := make(map[Finding]bool, len(raw))
+	for _, r := range raw {
+		key := r.finding
+		key.Severity = ""
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if keep != nil && !keep(r.absPath) {
+			clipped++
+			continue
+		}
+		findings = append(findings, r.finding)
+	}
+
+	var b strings.Builder
+	for i, line := range lines {
+		if !recognized[i] && strings.TrimSpace(line) != "" {
+			b.WriteString(strings.TrimRight(line, "\r"))
+			b.WriteByte('\n')
+		}
+	}
+	return findings, clipped, b.String()
+}
+
+// displayPath returns absPath relative to rootDir (slash-separated) if it's inside rootDir, and absPath otherwise.
+func displayPath(absPath string, rootDir string) string {
+	rel, err := filepath.Rel(rootDir, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return absPath
+	}
+	return filepath.ToSlash(rel)
+}
+
+// golangciIssue is the subset of a golangci-lint JSON issue that becomes a Finding.
+type golangciIssue struct {
+	FromLinter string
+	Text       string
+	Severity   string
+	Pos        struct {
+		Filename string
+		Line     int
+		Column   int
+	}
+}
+
+// parseGolangciLintJSON extracts issues from `golangci-lint run --out-format=json` output. Output before the JSON object (ex: stderr logging) and after it (ex:
+// a summary line) is skipped. ok reports whether the JSON object was found, and [start, end) is its byte range in output; output with no JSON object yields no
+// issues.
+func parseGolangciLintJSON(output string) (issues []golangciIssue, start, end int, ok bool) {
+	for i := strings.IndexByte(output, '{'); i >= 0; {
+		var report map[string]json.RawMessage
+		dec := json.NewDecoder(strings.NewReader(output[i:]))
+		if err := dec.Decode(&report); err == nil {
+			if rawIssues, ok := report["Issues"]; ok {
+				if err := json.Unmarshal(rawIssues, &issues); err == nil {
+					return issues, i, i + int(dec.InputOffset()), true
+				}
+			}
+		}
+		next := strings.IndexByte(output[i+1:], '{')
+		if next < 0 {
+			break
+		}
+		i += 1 + next
+	}
+	return nil, 0, 0, false
+}
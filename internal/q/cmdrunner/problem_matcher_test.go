@@ -0,0 +1,319 @@
+package cmdrunner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustCompileProblemMatchers(t *testing.T, matchers ...ProblemMatcher) []problemMatcher {
+	t.Helper()
+
+	compiled := make([]problemMatcher, 0, len(matchers))
+	for _, m := range matchers {
+		pm, err := compileProblemMatcher(m)
+		require.NoError(t, err)
+		compiled = append(compiled, pm)
+	}
+	return compiled
+}
+
+func TestMatchFindingsPresets(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("/work")
+	cwd := filepath.Join(root, "mod")
+
+	tests := []struct {
+		name   string
+		preset string
+		output string
+		want   []Finding
+	}{
+		{
+			name:   "go build",
+			preset: presetGoBuild,
+			output: "# example.com/mod/pkg\npkg/a.go:3:2: undefined: foo\npkg/b.go:10: syntax error\n",
+			want: []Finding{
+				{Path: "mod/pkg/a.go", Line: 3, Column: 2, Severity: SeverityError, Message: "undefined: foo"},
+				{Path: "mod/pkg/b.go", Line: 10, Severity: SeverityError, Message: "syntax error"},
+			},
+		},
+		{
+			name:   "go vet",
+			preset: presetGoVet,
+			output: "# example.com/mod/pkg\n./pkg/a.go:7:3: fmt.Printf format %d has arg s of wrong type string\nvet: pkg/b.go:1:1: expected 'package', found 'EOF'\n",
+			want: []Finding{
+				{Path: "mod/pkg/a.go", Line: 7, Column: 3, Severity: SeverityWarning, Rule: "vet", Message: "fmt.Printf format %d has arg s of wrong type string"},
+				{Path: "mod/pkg/b.go", Line: 1, Column: 1, Severity: SeverityWarning, Rule: "vet", Message: "expected 'package', found 'EOF'"},
+			},
+		},
+		{
+			name:   "staticcheck",
+			preset: presetStaticcheck,
+			output: "pkg/a.go:5:2: this value of err is never used (SA4006)\npkg/a.go:9:1: should have comment or be unexported\n",
+			want: []Finding{
+				{Path: "mod/pkg/a.go", Line: 5, Column: 2, Severity: SeverityWarning, Rule: "SA4006", Message: "this value of err is never used"},
+				{Path: "mod/pkg/a.go", Line: 9, Column: 1, Severity: SeverityWarning, Message: "should have comment or be unexported"},
+			},
+		},
+		{
+			name:   "golangci-lint json",
+			preset: presetGolangciLintJSON,
+			output: `level=warning msg="[runner] deprecated linter"` + "\n" +
+				`{"Issues":[{"FromLinter":"errcheck","Text":"Error return value is not checked","Severity":"","Pos":{"Filename":"pkg/a.go","Line":4,"Column":9}},` +
+				`{"FromLinter":"gosec","Text":"G104: Errors unhandled","Severity":"ERROR","Pos":{"Filename":"pkg/b.go","Line":2,"Column":1}}],"Report":{"Linters":[{"Name":"errcheck"}]}}` + "\n" +
+				"2 issues.\n",
+			want: []Finding{
+				{Path: "mod/pkg/a.go", Line: 4, Column: 9, Severity: SeverityWarning, Rule: "errcheck", Message: "Error return value is not checked"},
+				{Path: "mod/pkg/b.go", Line: 2, Column: 1, Severity: SeverityError, Rule: "gosec", Message: "G104: Errors unhandled"},
+			},
+		},
+		{
+			name:   "golangci-lint json without issues",
+			preset: presetGolangciLintJSON,
+			output: `{"Issues":null,"Report":{}}`,
+		},
+		{
+			name:   "gofmt -l",
+			preset: presetGofmtL,
+			output: "pkg/a.go\npkg/sub/b.go\n",
+			want: []Finding{
+				{Path: "mod/pkg/a.go", Severity: SeverityWarning, Rule: "gofmt", Message: "file is not gofmt-formatted"},
+				{Path: "mod/pkg/sub/b.go", Severity: SeverityWarning, Rule: "gofmt", Message: "file is not gofmt-formatted"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchers := mustCompileProblemMatchers(t, ProblemMatcher{Preset: tt.preset})
+			findings, clipped, _ := matchFindings(tt.output, cwd, root, matchers, nil)
+			require.Equal(t, tt.want, findings)
+			require.Zero(t, clipped)
+		})
+	}
+}
+
+func TestMatchFindingsPattern(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("/work")
+	matchers := mustCompileProblemMatchers(t, ProblemMatcher{
+		Pattern: `^(?P<severity>\w+) (?P<rule>[\w-]+) (?P<path>\S+):(?P<line>\d+) (?P<message>.*)$`,
+		Rule:    "mylint",
+	})
+
+	output := "WARN unused-param a.go:3 param x is unused\nNOTE style b.go:9 prefer early return\nBOGUS cc c.go:1 unknown severity\nnot a finding\n"
+	findings, _, unmatched := matchFindings(output, root, root, matchers, nil)
+	require.Equal(t, []Finding{
+		{Path: "a.go", Line: 3, Severity: SeverityWarning, Rule: "unused-param", Message: "param x is unused"},
+		{Path: "b.go", Line: 9, Severity: SeverityInfo, Rule: "style", Message: "prefer early return"},
+		{Path: "c.go", Line: 1, Severity: SeverityError, Rule: "cc", Message: "unknown severity"},
+	}, findings)
+	require.Equal(t, "not a finding\n", unmatched)
+}
+
+func TestMatchFindingsUnmatchedOutput(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("/work")
+
+	// Package headers are recognized by the go presets; other lines no matcher recognizes are kept.
+	matchers := mustCompileProblemMatchers(t, ProblemMatcher{Preset: presetGoBuild}, ProblemMatcher{Preset: presetGoVet})
+	output := "# example.com/pkg\n# [example.com/pkg]\npkg/a.go:3:2: undefined: foo\n\nFAIL\texample.com/pkg [build failed]\r\n"
+	_, _, unmatched := matchFindings(output, root, root, matchers, nil)
+	require.Equal(t, "FAIL\texample.com/pkg [build failed]\n", unmatched)
+
+	// A golangci-lint JSON report recognizes only its own lines; logging around it is kept.
+	matchers = mustCompileProblemMatchers(t, ProblemMatcher{Preset: presetGolangciLintJSON})
+	_, _, unmatched = matchFindings("{\"Issues\":null}\n", root, root, matchers, nil)
+	require.Empty(t, unmatched)
+	_, _, unmatched = matchFindings("level=error msg=\"typechecking error: pkg/a.go:1:1: expected 'package'\"\n{\"Issues\":\n[]}\n0 issues.\n", root, root, matchers, nil)
+	require.Equal(t, "level=error msg=\"typechecking error: pkg/a.go:1:1: expected 'package'\"\n0 issues.\n", unmatched)
+	_, _, unmatched = matchFindings("Error: can't load config\n", root, root, matchers, nil)
+	require.Equal(t, "Error: can't load config\n", unmatched)
+}
+
+func TestMatchFindingsDeduplicatesAndClips(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("/work")
+	matchers := mustCompileProblemMatchers(t, ProblemMatcher{Preset: presetGoBuild}, ProblemMatcher{Preset: presetGoVet})
+
+	// go test prints build errors, then vet prints the same position again; each matcher sees every line.
+	output := "pkg/a.go:3:2: undefined: foo\nother/b.go:1:1: undefined: bar\npkg/a.go:3:2: undefined: foo\n"
+	keep := func(absPath string) bool {
+		return filepath.Dir(absPath) == filepath.Join(root, "pkg")
+	}
+
+	findings, clipped, _ := matchFindings(output, root, root, matchers, keep)
+	require.Equal(t, []Finding{
+		{Path: "pkg/a.go", Line: 3, Column: 2, Severity: SeverityError, Message: "undefined: foo"},
+		{Path: "pkg/a.go", Line: 3, Column: 2, Severity: SeverityWarning, Rule: "vet", Message: "undefined: foo"},
+	}, findings)
+	require.Equal(t, 2, clipped)
+}
+
+func TestMatchFindingsPathOutsideRootIsAbsolute(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("/work/mod")
+	matchers := mustCompileProblemMatchers(t, ProblemMatcher{Preset: presetGoBuild})
+
+	findings, _, _ := matchFindings("../dep/a.go:1:1: oops\n", root, root, matchers, nil)
+	require.Len(t, findings, 1)
+	require.Equal(t, filepath.FromSlash("/work/dep/a.go"), findings[0].Path)
+}
+
+func TestCompileProblemMatcherErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		matcher ProblemMatcher
+		wantErr string
+	}{
+		{name: "empty", matcher: ProblemMatcher{}, wantErr: "one of preset or pattern is required"},
+		{name: "both", matcher: ProblemMatcher{Preset: presetGoBuild, Pattern: "(?P<path>.*)"}, wantErr: "mutually exclusive"},
+		{name: "unknown preset", matcher: ProblemMatcher{Preset: "eslint"}, wantErr: `unknown preset "eslint"`},
+		{name: "invalid pattern", matcher: ProblemMatcher{Pattern: "(?P<path>"}, wantErr: "invalid pattern"},
+		{name: "no path group", matcher: ProblemMatcher{Pattern: `^(?P<line>\d+)$`}, wantErr: `named group "path"`},
+		{name: "unknown severity", matcher: ProblemMatcher{Preset: presetGofmtL, Severity: "critical"}, wantErr: `unknown severity "critical"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileProblemMatcher(tt.matcher)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFindingString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "pkg/a.go:5:2: warning: this value of err is never used (SA4006)",
+		Finding{Path: "pkg/a.go", Line: 5, Column: 2, Severity: SeverityWarning, Rule: "SA4006", Message: "this value of err is never used"}.String())
+	require.Equal(t, "pkg/a.go:10: error: syntax error", Finding{Path: "pkg/a.go", Line: 10, Severity: SeverityError, Message: "syntax error"}.String())
+	require.Equal(t, "pkg/a.go: warning: file is not gofmt-formatted (gofmt)",
+		Finding{Path: "pkg/a.go", Severity: SeverityWarning, Rule: "gofmt", Message: "file is not gofmt-formatted"}.String())
+}
+
+func TestRunnerRunProblemMatchers(t *testing.T) {
+	t.Parallel()
+	skipWithoutSh(t)
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+
+	runner := NewRunner(nil, nil)
+	runner.SetFindingFilter(func(absPath string) bool {
+		return filepath.Dir(absPath) == filepath.Join(root, "pkg")
+	})
+	runner.AddCommand(Command{
+		Command:         "sh",
+		Args:            []string{"-c", "echo '# example.com/pkg'; echo 'a.go:3:2: undefined: foo'; echo 'a.go:3:2: undefined: foo'; echo '../other/b.go:1:1: undefined: bar'; exit 1"},
+		CWD:             "pkg",
+		ProblemMatchers: []ProblemMatcher{{Preset: "go-build"}},
+	})
+
+	result, err := runner.Run(context.Background(), root, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Results, 1)
+
+	cr := result.Results[0]
+	require.Equal(t, OutcomeFailed, cr.Outcome)
+	require.Contains(t, cr.Output, "# example.com/pkg")
+	require.Equal(t, []Finding{{Path: "pkg/a.go", Line: 3, Column: 2, Severity: SeverityError, Message: "undefined: foo"}}, cr.Findings)
+	require.Equal(t, 1, cr.ClippedFindings)
+
+	xml := result.ToXML("build-status")
+	require.True(t, strings.HasPrefix(xml, `<build-status ok="false" clipped-findings="1">`), xml)
+	require.Contains(t, xml, "\npkg/a.go:3:2: error: undefined: foo\n</build-status>")
+	require.NotContains(t, xml, "\n# example.com/pkg\n")
+}
+
+func TestRunnerRunProblemMatchersKeepsUnmatchedOutput(t *testing.T) {
+	t.Parallel()
+	skipWithoutSh(t)
+
+	root := t.TempDir()
+	runner := NewRunner(nil, nil)
+	runner.AddCommand(Command{
+		Command:         "sh",
+		Args:            []string{"-c", "echo 'a.go:3:2: undefined: foo'; echo 'panic: linker crashed'; exit 1"},
+		ProblemMatchers: []ProblemMatcher{{Preset: "go-build"}},
+	})
+
+	result, err := runner.Run(context.Background(), root, nil)
+	require.NoError(t, err)
+
+	cr := result.Results[0]
+	require.Equal(t, OutcomeFailed, cr.Outcome)
+	require.Equal(t, "panic: linker crashed\n", cr.UnmatchedOutput)
+	require.Contains(t, result.ToXML("build-status"), "\na.go:3:2: error: undefined: foo\npanic: linker crashed\n</build-status>")
+}
+
+func TestRunnerRunProblemMatchersAllClipped(t *testing.T) {
+	t.Parallel()
+	skipWithoutSh(t)
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+
+	run := func(script string, maxOutputBytes int) CommandResult {
+		runner := NewRunner(nil, nil)
+		runner.SetFindingFilter(func(absPath string) bool {
+			return filepath.Dir(absPath) == filepath.Join(root, "pkg")
+		})
+		runner.AddCommand(Command{
+			Command:         "sh",
+			Args:            []string{"-c", script},
+			CWD:             "pkg",
+			MaxOutputBytes:  maxOutputBytes,
+			ProblemMatchers: []ProblemMatcher{{Preset: "go-build"}},
+		})
+		result, err := runner.Run(context.Background(), root, nil)
+		require.NoError(t, err)
+		return result.Results[0]
+	}
+
+	// The only problems are outside the kept files: not a failure.
+	cr := run("echo '# example.com/other'; echo '../other/b.go:1:1: undefined: bar'; exit 1", 0)
+	require.Equal(t, OutcomeSuccess, cr.Outcome)
+	require.Empty(t, cr.Findings)
+	require.Equal(t, 1, cr.ClippedFindings)
+	xml := Result{Results: []CommandResult{cr}}.ToXML("build-status")
+	require.True(t, strings.HasPrefix(xml, `<build-status ok="true" clipped-findings="1">`), xml)
+
+	// Unrecognized output might be this code's problem, so the failure stands and the output is shown.
+	cr = run("echo '../other/b.go:1:1: undefined: bar'; echo 'go: cannot find main module'; exit 1", 0)
+	require.Equal(t, OutcomeFailed, cr.Outcome)
+	xml = Result{Results: []CommandResult{cr}}.ToXML("build-status")
+	require.True(t, strings.HasPrefix(xml, `<build-status ok="false" clipped-findings="1">`), xml)
+	require.Contains(t, xml, "\ngo: cannot find main module\n</build-status>")
+
+	// Truncated output might have hidden a problem in the kept files, so the failure stands.
+	cr = run("echo '../other/b.go:1:1: undefined: bar'; echo 'a.go:1:1: undefined: foo'; exit 1", len("../other/b.go:1:1: undefined: bar\n"))
+	require.Equal(t, OutcomeFailed, cr.Outcome)
+	require.Empty(t, cr.Findings)
+	require.Equal(t, 1, cr.ClippedFindings)
+	require.Empty(t, cr.UnmatchedOutput)
+	require.Positive(t, cr.OmittedOutputBytes)
+}
+
+func TestRunnerRunProblemMatcherValidation(t *testing.T) {
+	t.Parallel()
+
+	runner := NewRunner(nil, nil)
+	runner.AddCommand(Command{Command: "true", ProblemMatchers: []ProblemMatcher{{Preset: "go-build"}, {Preset: "bogus"}}})
+
+	result, err := runner.Run(context.Background(), t.TempDir(), nil)
+	require.ErrorContains(t, err, `command[0]: problemmatchers[1]: unknown preset "bogus"`)
+	require.Empty(t, result.Results)
+}
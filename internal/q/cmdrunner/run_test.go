@@ -1,6 +1,7 @@
 package cmdrunner
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -517,3 +519,246 @@ func TestRunnerRunCommandAttrsMustBeEvenLength(t *testing.T) {
 	require.ErrorContains(t, err, "attrs must have even length")
 	require.ErrorContains(t, err, "command[0]")
 }
+
+// skipWithoutSh skips tests that script commands with sh.
+func skipWithoutSh(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+}
+
+func TestRunnerRunDependsOnOrdersExecution(t *testing.T) {
+	t.Parallel()
+	skipWithoutSh(t)
+
+	root := t.TempDir()
+
+	runner := NewRunner(nil, nil)
+	runner.SetMaxParallel(4)
+	runner.AddCommand(Command{ID: "read", Command: "cat", Args: []string{"generated.txt"}, DependsOn: []string{"write"}})
+	runner.AddCommand(Command{ID: "write", Command: "sh", Args: []string{"-c", "sleep 0.1; echo generated > generated.txt"}})
+
+	result, err := runner.Run(context.Background(), root, nil)
+	require.NoError(t, err)
+	require.True(t, result.Success())
+	require.Len(t, result.Results, 2)
+	require.Equal(t, "read", result.Results[0].ID)
+	require.Equal(t, "generated\n", result.Results[0].Output)
+	require.Equal(t, "write", result.Results[1].ID)
+}
+
+func TestRunnerRunMaxParallelRunsConcurrently(t *testing.T) {
+	t.Parallel()
+	skipWithoutSh(t)
+
+	root := t.TempDir()
+
+	// Each command creates its own marker and waits for the other's, so both only succeed if they run at the same time.
+	rendezvous := func(mine, theirs string) Command {
+		return Command{
+			Command: "sh",
+			Args:    []string{"-c", fmt.Sprintf("touch %s; while [ ! -f %s ]; do sleep 0.01; done", mine, theirs)},
+			Timeout: "5s",
+		}
+	}
+
+	runner := NewRunner(nil, nil)
+	runner.SetMaxParallel(2)
+	runner.AddCommand(rendezvous("a", "b"))
+	runner.AddCommand(rendezvous("b", "a"))
+
+	result, err := runner.Run(context.Background(), root, nil)
+	require.NoError(t, err)
+	require.True(t, result.Success())
+}
+
+func TestRunnerRunSkipsDependentsOfFailedCommands(t *testing.T) {
+	t.Parallel()
+	skipWithoutSh(t)
+
+	root := t.TempDir()
+
+	runner := NewRunner(nil, nil)
+	runner.SetMaxParallel(2)
+	runner.AddCommand(Command{ID: "build", Command: "sh", Args: []string{"-c", "echo broken; exit 1"}})
+	runner.AddCommand(Command{ID: "test", Command: "sh", Args: []string{"-c", "echo ran"}, DependsOn: []string{"build"}})
+	runner.AddCommand(Command{ID: "report", Command: "sh", Args: []string{"-c", "echo ran"}, DependsOn: []string{"test"}})
+	runner.AddCommand(Command{ID: "vet", Command: "sh", Args: []string{"-c", "echo vetted"}})
+
+	result, err := runner.Run(context.Background(), root, nil)
+	require.NoError(t, err)
+	require.False(t, result.Success())
+	require.Len(t, result.Results, 4)
+
+	require.Equal(t, ExecStatusCompleted, result.Results[0].ExecStatus)
+	require.Equal(t, OutcomeFailed, result.Results[0].Outcome)
+
+	for i, failed := range map[int]string{1: "build", 2: "test"} {
+		cr := result.Results[i]
+		require.Equal(t, ExecStatusSkipped, cr.ExecStatus)
+		require.Equal(t, OutcomeFailed, cr.Outcome)
+		require.Equal(t, failed, cr.FailedDependency)
+		require.Empty(t, cr.Output)
+		require.Error(t, cr.ExecError)
+	}
+
+	require.Equal(t, OutcomeSuccess, result.Results[3].Outcome)
+	require.Equal(t, "vetted\n", result.Results[3].Output)
+}
+
+func TestRunnerRunDependencyValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		commands []Command
+		wantErr  string
+	}{
+		{
+			name:     "duplicate id",
+			commands: []Command{{ID: "a", Command: "true"}, {ID: "a", Command: "true"}},
+			wantErr:  `command[1]: duplicate id "a"`,
+		},
+		{
+			name:     "unknown id",
+			commands: []Command{{ID: "a", Command: "true", DependsOn: []string{"missing"}}},
+			wantErr:  `command[0]: depends on unknown id "missing"`,
+		},
+		{
+			name:     "self dependency",
+			commands: []Command{{ID: "a", Command: "true", DependsOn: []string{"a"}}},
+			wantErr:  `command[0]: depends on itself`,
+		},
+		{
+			name: "cycle",
+			commands: []Command{
+				{ID: "a", Command: "true", DependsOn: []string{"c"}},
+				{ID: "b", Command: "true", DependsOn: []string{"a"}},
+				{ID: "c", Command: "true", DependsOn: []string{"b"}},
+			},
+			wantErr: "dependency cycle",
+		},
+		{
+			name:     "invalid timeout",
+			commands: []Command{{Command: "true", Timeout: "soon"}},
+			wantErr:  `command[0]: timeout must be a positive duration`,
+		},
+		{
+			name:     "negative max output",
+			commands: []Command{{Command: "true", MaxOutputBytes: -1}},
+			wantErr:  `command[0]: maxoutputbytes must not be negative`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := NewRunner(nil, nil)
+			for _, c := range tt.commands {
+				runner.AddCommand(c)
+			}
+			result, err := runner.Run(context.Background(), t.TempDir(), nil)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Empty(t, result.Results)
+		})
+	}
+}
+
+func TestRunnerRunCommandTimeout(t *testing.T) {
+	t.Parallel()
+	skipWithoutSh(t)
+
+	root := t.TempDir()
+
+	runner := NewRunner(nil, nil)
+	runner.AddCommand(Command{Command: "sleep", Args: []string{"10"}, Timeout: "100ms"})
+	runner.AddCommand(Command{Command: "echo", Args: []string{"after"}})
+
+	start := time.Now()
+	result, err := runner.Run(context.Background(), root, nil)
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), 5*time.Second)
+	require.Len(t, result.Results, 2)
+
+	cr := result.Results[0]
+	require.Equal(t, ExecStatusTimedOut, cr.ExecStatus)
+	require.Equal(t, OutcomeFailed, cr.Outcome)
+	require.True(t, errors.Is(cr.ExecError, context.DeadlineExceeded))
+
+	// The timeout only applies to its own command.
+	require.Equal(t, OutcomeSuccess, result.Results[1].Outcome)
+}
+
+func TestRunnerRunMaxOutputBytes(t *testing.T) {
+	t.Parallel()
+	skipWithoutSh(t)
+
+	root := t.TempDir()
+
+	runner := NewRunner(nil, nil)
+	runner.AddCommand(Command{Command: "sh", Args: []string{"-c", "printf abcdefghij; printf klmno >&2"}, MaxOutputBytes: 4})
+
+	result, err := runner.Run(context.Background(), root, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Results, 1)
+
+	cr := result.Results[0]
+	require.Equal(t, OutcomeSuccess, cr.Outcome)
+	require.Equal(t, "abcd", cr.Output)
+	require.Equal(t, 11, cr.OmittedOutputBytes)
+}
+
+func TestRunnerRunMaxOutputBytesKeepsWholeRunes(t *testing.T) {
+	t.Parallel()
+	skipWithoutSh(t)
+
+	root := t.TempDir()
+
+	runner := NewRunner(nil, nil)
+	// "é" is two bytes, so a 4-byte limit falls inside the second one.
+	runner.AddCommand(Command{Command: "sh", Args: []string{"-c", "printf 'aéé'; printf b"}, MaxOutputBytes: 4})
+
+	result, err := runner.Run(context.Background(), root, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Results, 1)
+
+	cr := result.Results[0]
+	require.Equal(t, "aé", cr.Output)
+	require.Equal(t, 3, cr.OmittedOutputBytes)
+}
+
+func TestLockedBufferTrimsRuneSplitAcrossWrites(t *testing.T) {
+	b := &lockedBuffer{buf: &bytes.Buffer{}, mu: &sync.Mutex{}, limit: 4}
+
+	// The first write fits exactly but ends inside the second "é"; the next write has no room, so the partial rune is dropped too.
+	n, err := b.Write([]byte("aé\xc3"))
+	require.NoError(t, err)
+	require.Equal(t, 4, n)
+	n, err = b.Write([]byte("\xa9b"))
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	require.Equal(t, "aé", b.String())
+	require.Equal(t, 3, b.omitted())
+}
+
+func TestRunnerRunContextCanceledSkipsPendingCommands(t *testing.T) {
+	t.Parallel()
+	skipWithoutSh(t)
+
+	root := t.TempDir()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	runner := NewRunner(nil, nil)
+	runner.AddCommand(Command{Command: "sleep", Args: []string{"10"}})
+	runner.AddCommand(Command{Command: "sh", Args: []string{"-c", "touch ran"}})
+
+	result, err := runner.Run(ctx, root, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Results, 2)
+	require.Equal(t, ExecStatusTimedOut, result.Results[0].ExecStatus)
+	require.Equal(t, ExecStatusTimedOut, result.Results[1].ExecStatus)
+	require.NoFileExists(t, filepath.Join(root, "ran"))
+}
@@ -53,7 +53,15 @@ func renderResultBlock(tag string, res CommandResult, trailingNewline bool) stri
 	b.WriteString(formatCommandLine(res))
 	b.WriteByte('\n')
 
-	if res.Output != "" {
+	switch {
+	case len(res.Findings) > 0 || res.ClippedFindings > 0:
+		// Findings replace the raw output: they're more compact, deduplicated, and clipped. Lines the matchers didn't recognize are kept after them.
+		for _, f := range res.Findings {
+			b.WriteString(f.String())
+			b.WriteByte('\n')
+		}
+		b.WriteString(res.UnmatchedOutput)
+	case res.Output != "":
 		b.WriteString(res.Output)
 		if !strings.HasSuffix(res.Output, "\n") {
 			b.WriteByte('\n')
@@ -90,12 +98,21 @@ func commandAttributes(res CommandResult) string {
 	if res.ExecStatus != "" && res.ExecStatus != ExecStatusCompleted {
 		parts = append(parts, fmt.Sprintf(`exec-status="%s"`, res.ExecStatus))
 	}
+	if res.FailedDependency != "" {
+		parts = append(parts, fmt.Sprintf(`failed-dependency="%s"`, res.FailedDependency))
+	}
 	if res.ExitCode != 0 && res.ExitCode != 1 {
 		parts = append(parts, fmt.Sprintf(`exit-code="%d"`, res.ExitCode))
 	}
 	if res.Signal != "" {
 		parts = append(parts, fmt.Sprintf(`signal="%s"`, res.Signal))
 	}
+	if res.ClippedFindings > 0 {
+		parts = append(parts, fmt.Sprintf(`clipped-findings="%d"`, res.ClippedFindings))
+	}
+	if res.OmittedOutputBytes > 0 {
+		parts = append(parts, fmt.Sprintf(`omitted-output-bytes="%d"`, res.OmittedOutputBytes))
+	}
 	if res.Duration > DurationWarnThreshold {
 		parts = append(parts, fmt.Sprintf(`duration="%s"`, formatWarnDuration(res.Duration)))
 	}
@@ -298,3 +298,73 @@ $ FOO=1 BAR=2 go test ./pkg
 </test-status>`
 	require.Equal(t, want, got)
 }
+
+func TestResultToXMLSkippedAndOmittedOutput(t *testing.T) {
+	t.Parallel()
+
+	result := Result{
+		Results: []CommandResult{
+			{
+				ID:                 "build",
+				Command:            "go",
+				Args:               []string{"build", "./..."},
+				Output:             "./a.go:1:1: expected 'package'",
+				ExecStatus:         ExecStatusCompleted,
+				ExitCode:           1,
+				Outcome:            OutcomeFailed,
+				OmittedOutputBytes: 2048,
+			},
+			{
+				ID:               "test",
+				Command:          "go",
+				Args:             []string{"test", "./..."},
+				ExecStatus:       ExecStatusSkipped,
+				ExitCode:         -1,
+				Outcome:          OutcomeFailed,
+				FailedDependency: "build",
+			},
+		},
+	}
+
+	got := result.ToXML("lint-status")
+	want := `<lint-status ok="false">
+<command ok="false" omitted-output-bytes="2048">
+$ go build ./...
+./a.go:1:1: expected 'package'
+</command>
+<command ok="false" exec-status="skipped" failed-dependency="build" exit-code="-1">
+$ go test ./...
+</command>
+</lint-status>`
+
+	require.Equal(t, want, got)
+}
+
+func TestResultToXMLFindingsReplaceOutput(t *testing.T) {
+	t.Parallel()
+
+	result := Result{
+		Results: []CommandResult{
+			{
+				Command:    "staticcheck",
+				Args:       []string{"./pkg"},
+				Output:     "pkg/a.go:5:2: this value of err is never used (SA4006)\nother/b.go:1:1: should be unexported (ST1000)\n",
+				ExecStatus: ExecStatusCompleted,
+				ExitCode:   1,
+				Outcome:    OutcomeFailed,
+				Findings: []Finding{
+					{Path: "pkg/a.go", Line: 5, Column: 2, Severity: SeverityWarning, Rule: "SA4006", Message: "this value of err is never used"},
+				},
+				ClippedFindings: 1,
+			},
+		},
+	}
+
+	got := result.ToXML("lint-status")
+	want := `<lint-status ok="false" clipped-findings="1">
+$ staticcheck ./pkg
+pkg/a.go:5:2: warning: this value of err is never used (SA4006)
+</lint-status>`
+
+	require.Equal(t, want, got)
+}
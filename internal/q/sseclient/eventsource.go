@@ -0,0 +1,438 @@
+package sseclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ReadyState is the connection state of an EventSource, like the browser EventSource's readyState.
+type ReadyState int
+
+const (
+	Connecting ReadyState = iota // Connecting means a connection is being opened, or a reconnect is waiting out its backoff delay.
+	Open                         // Open means a connection is open.
+	Closed                       // Closed means the EventSource is done: closed by the caller, ended, or given up on. It is final.
+)
+
+// String returns "connecting", "open", or "closed".
+func (s ReadyState) String() string {
+	switch s {
+	case Connecting:
+		return "connecting"
+	case Open:
+		return "open"
+	case Closed:
+		return "closed"
+	}
+	return "ReadyState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// Status is one EventSource state change.
+type Status struct {
+	State   ReadyState    // State is the new state.
+	Err     error         // Err is why a connection was lost or failed (when State is Connecting), or why the EventSource ended (when State is Closed; nil if closed by the caller).
+	Attempt int           // Attempt counts consecutive connection attempts since an event was last received; 0 for the initial connection.
+	Delay   time.Duration // Delay is the backoff delay before the next attempt, when State is Connecting after a failure.
+}
+
+// ReconnectOptions configures an EventSource. The zero value uses the defaults noted on each field.
+type ReconnectOptions struct {
+	InitialDelay         time.Duration // InitialDelay is the first backoff delay, used until the server sends a retry hint (default 1s).
+	MaxDelay             time.Duration // MaxDelay caps backoff delays, though never below the server's retry hint (default 30s).
+	Multiplier           float64       // Multiplier grows the delay after each consecutive failed attempt (default 2).
+	Jitter               float64       // Jitter randomizes each delay by up to this fraction either way (default 0.2; negative disables jitter).
+	MaxAttempts          int           // MaxAttempts limits consecutive reconnect attempts without receiving an event; 0 means unlimited.
+	RetryableStatusCodes []int         // RetryableStatusCodes are handshake status codes that are retried (default: 500, 502, 503, 504). Other handshake failures end the EventSource.
+
+	// RequireEventID stops reconnecting once an event has been received, unless the server has sent an event ID to resume from. Set it for endpoints where a new
+	// request without Last-Event-ID starts over rather than resuming (ex: POST APIs that generate the stream), so a dropped stream is returned as an error instead
+	// of being replayed.
+	RequireEventID bool
+}
+
+// EventSource is a reconnecting SSE stream: when a connection drops, it reconnects with backoff and resumes with a Last-Event-ID header, like the browser EventSource.
+// Events from all connections are received through one Recv loop.
+//
+// Recv and RecvContext must not be called concurrently; Close may be called from any goroutine.
+type EventSource struct {
+	client *Client          // client opens each connection.
+	req    *http.Request    // req is the template request; its context bounds the EventSource's lifetime.
+	opts   ReconnectOptions // opts is the reconnect policy, with defaults applied.
+
+	statuses chan Status   // statuses receives state changes; it is closed after the Closed status.
+	closeCh  chan struct{} // closeCh is closed by Close to interrupt backoff waits.
+	closeOne sync.Once     // closeOne ensures closeCh is closed once.
+
+	statusMu sync.Mutex // statusMu serializes status delivery with closing statuses.
+
+	mu       sync.Mutex     // mu guards the fields below.
+	stream   *Stream        // stream is the current connection, or nil between connections.
+	response *http.Response // response is the latest connection's handshake response.
+	state    State          // state is the reconnect state carried across connections.
+	ready    ReadyState     // ready is the current ready state.
+	attempt  int            // attempt counts consecutive connection attempts since an event was last received.
+	received bool           // received records whether any event has been received.
+	err      error          // err is the terminal error once ready is Closed.
+}
+
+// Connect opens a reconnecting EventSource for req. req's context bounds the EventSource's lifetime, including backoff waits. If req has a body, it must be replayable
+// (req.GetBody set, as http.NewRequest does for in-memory bodies); otherwise the EventSource never reconnects.
+//
+// Connect retries the initial connection like a reconnect. It returns the last error (typically an *OpenError) if no connection could be opened.
+func (c *Client) Connect(req *http.Request, opts ReconnectOptions) (*EventSource, error) {
+	if req == nil {
+		return nil, &OpenError{Err: errors.New("nil request")}
+	}
+	if opts.InitialDelay <= 0 {
+		opts.InitialDelay = time.Second
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = 30 * time.Second
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = 2
+	}
+	if opts.Jitter == 0 {
+		opts.Jitter = 0.2
+	}
+	if opts.RetryableStatusCodes == nil {
+		opts.RetryableStatusCodes = []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	}
+	es := &EventSource{
+		client:   c,
+		req:      req,
+		opts:     opts,
+		statuses: make(chan Status, 16),
+		closeCh:  make(chan struct{}),
+	}
+	es.setStatus(Status{State: Connecting})
+	if err := es.connect(context.Background(), nil); err != nil {
+		return nil, err
+	}
+	return es, nil
+}
+
+// Recv blocks until the next event, reconnecting as needed. It returns io.EOF when the stream ends cleanly or the EventSource is closed, and otherwise the error
+// that ended it.
+func (es *EventSource) Recv() (Event, error) {
+	return es.RecvContext(context.Background())
+}
+
+// RecvContext is like Recv with per-receive cancellation/deadline control. ctx ending doesn't close the EventSource.
+func (es *EventSource) RecvContext(ctx context.Context) (Event, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for {
+		es.mu.Lock()
+		stream, ready, err := es.stream, es.ready, es.err
+		es.mu.Unlock()
+		if ready == Closed {
+			return Event{}, err
+		}
+		if stream == nil {
+			// A previous RecvContext was interrupted while reconnecting.
+			if err := es.connect(ctx, nil); err != nil {
+				return Event{}, err
+			}
+			continue
+		}
+
+		ev, err := stream.RecvContext(ctx)
+		if err == nil {
+			es.mu.Lock()
+			es.state = stream.State()
+			es.attempt = 0
+			es.received = true
+			es.mu.Unlock()
+			return ev, nil
+		}
+		if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+			return Event{}, err
+		}
+
+		// The connection ended.
+		_ = stream.Close()
+		es.mu.Lock()
+		es.stream = nil
+		es.state = stream.State()
+		es.mu.Unlock()
+		if es.isClosed() {
+			return Event{}, io.EOF
+		}
+		if !es.canResume() {
+			return Event{}, es.finish(err)
+		}
+		if err := es.connect(ctx, err); err != nil {
+			return Event{}, err
+		}
+	}
+}
+
+// Close closes the EventSource and its current connection. Idempotent.
+func (es *EventSource) Close() error {
+	es.mu.Lock()
+	if es.ready == Closed {
+		es.mu.Unlock()
+		return nil
+	}
+	stream := es.stream
+	es.stream = nil
+	es.mu.Unlock()
+
+	es.closeOne.Do(func() { close(es.closeCh) })
+	var err error
+	if stream != nil {
+		err = stream.Close()
+	}
+	es.finish(nil)
+	return err
+}
+
+// ReadyState returns the current state.
+func (es *EventSource) ReadyState() ReadyState {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return es.ready
+}
+
+// Statuses returns a channel of state changes, starting with the initial Connecting status. It is buffered; if the caller falls behind, older statuses are dropped
+// so the latest is always delivered. It is closed after the Closed status.
+func (es *EventSource) Statuses() <-chan Status {
+	return es.statuses
+}
+
+// Response returns the current (or last) connection's handshake response.
+func (es *EventSource) Response() *http.Response {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return es.response
+}
+
+// State returns the reconnect state carried across connections.
+func (es *EventSource) State() State {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.stream != nil {
+		return es.stream.State()
+	}
+	return es.state
+}
+
+// connect opens a connection, retrying with backoff. cause is why the previous connection was lost (nil for the initial connection). On failure, it closes the
+// EventSource and returns the error, except that ctx ending returns ctx's error and leaves the EventSource reconnecting.
+func (es *EventSource) connect(ctx context.Context, cause error) error {
+	for {
+		if cause != nil {
+			es.mu.Lock()
+			es.attempt++
+			attempt := es.attempt
+			es.mu.Unlock()
+			if es.opts.MaxAttempts > 0 && attempt > es.opts.MaxAttempts {
+				return es.finish(cause)
+			}
+			delay := es.backoff(attempt, cause)
+			es.setStatus(Status{State: Connecting, Err: cause, Attempt: attempt, Delay: delay})
+			if err := es.wait(ctx, delay); err != nil {
+				return err
+			}
+		}
+
+		req, err := es.newRequest()
+		if err != nil {
+			return es.finish(err)
+		}
+		es.mu.Lock()
+		state := es.state
+		es.mu.Unlock()
+		stream, err := es.client.openRequest(req, state)
+		if err == nil {
+			es.mu.Lock()
+			if es.ready == Closed {
+				es.mu.Unlock()
+				_ = stream.Close()
+				return io.EOF
+			}
+			es.stream = stream
+			es.response = stream.Response()
+			attempt := es.attempt
+			es.mu.Unlock()
+			es.setStatus(Status{State: Open, Attempt: attempt})
+			return nil
+		}
+		if es.isClosed() {
+			return io.EOF
+		}
+		if !es.retryable(err) {
+			var openErr *OpenError
+			if cause != nil && errors.As(err, &openErr) && openErr.Response != nil && openErr.Response.StatusCode == http.StatusNoContent {
+				// Per the SSE spec, a server ends a stream for good by answering a reconnect with 204 No Content.
+				return es.finish(io.EOF)
+			}
+			return es.finish(err)
+		}
+		cause = err
+	}
+}
+
+// newRequest returns a request for the next connection, with a fresh body and the Last-Event-ID header.
+func (es *EventSource) newRequest() (*http.Request, error) {
+	req := es.req.Clone(es.req.Context())
+	if es.req.Body != nil && es.req.Body != http.NoBody {
+		if es.req.GetBody == nil {
+			// The body can only be sent once; canResume prevents reconnects.
+			req.Body = es.req.Body
+		} else {
+			body, err := es.req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+	es.mu.Lock()
+	lastEventID := es.state.LastEventID
+	es.mu.Unlock()
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	return req, nil
+}
+
+// canResume reports whether a lost connection may be reconnected.
+func (es *EventSource) canResume() bool {
+	if es.req.Context().Err() != nil {
+		return false
+	}
+	if es.req.Body != nil && es.req.Body != http.NoBody && es.req.GetBody == nil {
+		return false
+	}
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return !es.opts.RequireEventID || !es.received || es.state.LastEventID != ""
+}
+
+// retryable reports whether a failed connection attempt should be retried.
+func (es *EventSource) retryable(err error) bool {
+	if es.req.Context().Err() != nil || !es.canResume() {
+		return false
+	}
+	var openErr *OpenError
+	if !errors.As(err, &openErr) {
+		return false
+	}
+	if openErr.Response == nil {
+		// A transport failure; setup failures (no request) aren't retried.
+		return openErr.Request != nil
+	}
+	return errors.Is(err, ErrUnexpectedStatus) && slices.Contains(es.opts.RetryableStatusCodes, openErr.Response.StatusCode)
+}
+
+// backoff returns the delay before the given attempt (1 for the first retry): the server's retry hint (or InitialDelay) grown by Multiplier per attempt, capped
+// by MaxDelay, and jittered. A Retry-After header on a failed handshake is honored when longer.
+func (es *EventSource) backoff(attempt int, cause error) time.Duration {
+	es.mu.Lock()
+	base := es.state.Retry
+	es.mu.Unlock()
+	maxDelay := es.opts.MaxDelay
+	if base <= 0 {
+		base = es.opts.InitialDelay
+	} else if base > maxDelay {
+		maxDelay = base
+	}
+
+	delay := float64(base) * math.Pow(es.opts.Multiplier, float64(attempt-1))
+	if delay > float64(maxDelay) {
+		delay = float64(maxDelay)
+	}
+	if es.opts.Jitter > 0 {
+		delay *= 1 + es.opts.Jitter*(2*rand.Float64()-1)
+	}
+	d := time.Duration(delay)
+
+	var openErr *OpenError
+	if errors.As(cause, &openErr) && openErr.Response != nil {
+		if secs, err := strconv.Atoi(openErr.Response.Header.Get("Retry-After")); err == nil && time.Duration(secs)*time.Second > d {
+			d = time.Duration(secs) * time.Second
+		}
+	}
+	return d
+}
+
+// wait sleeps for d, returning early with an error if ctx or the request context ends or the EventSource is closed.
+func (es *EventSource) wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-es.req.Context().Done():
+		return es.finish(es.req.Context().Err())
+	case <-es.closeCh:
+		return io.EOF
+	}
+}
+
+func (es *EventSource) isClosed() bool {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return es.ready == Closed
+}
+
+// finish closes the EventSource with err (nil when closed by the caller) and returns the error Recv reports: err, or io.EOF for a nil err.
+func (es *EventSource) finish(err error) error {
+	es.mu.Lock()
+	if es.ready == Closed {
+		err = es.err
+		es.mu.Unlock()
+		return err
+	}
+	es.ready = Closed
+	es.err = err
+	if es.err == nil {
+		es.err = io.EOF
+	}
+	recvErr := es.err
+	es.mu.Unlock()
+
+	es.setStatus(Status{State: Closed, Err: err})
+	return recvErr
+}
+
+// setStatus records status and delivers it on the statuses channel, dropping the oldest undelivered status if the channel is full. Once Closed, later statuses
+// are ignored.
+func (es *EventSource) setStatus(status Status) {
+	es.statusMu.Lock()
+	defer es.statusMu.Unlock()
+	es.mu.Lock()
+	if es.ready == Closed && status.State != Closed {
+		es.mu.Unlock()
+		return
+	}
+	es.ready = status.State
+	es.mu.Unlock()
+	for {
+		select {
+		case es.statuses <- status:
+			if status.State == Closed {
+				close(es.statuses)
+			}
+			return
+		default:
+		}
+		select {
+		case <-es.statuses:
+		default:
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package sseclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastReconnect reconnects after a millisecond, without jitter.
+var fastReconnect = ReconnectOptions{InitialDelay: time.Millisecond, Jitter: -1}
+
+func collectStatuses(es *EventSource) []ReadyState {
+	var states []ReadyState
+	for status := range es.Statuses() {
+		states = append(states, status.State)
+	}
+	return states
+}
+
+func TestEventSource_ReconnectsWithLastEventID(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch requests.Add(1) {
+		case 1:
+			assert.Empty(t, r.Header.Get("Last-Event-ID"))
+			_, _ = io.WriteString(w, "retry: 1\nid: 1\ndata: a\n\n")
+		case 2:
+			assert.Equal(t, "1", r.Header.Get("Last-Event-ID"))
+			_, _ = io.WriteString(w, "id: 2\ndata: b\n\n")
+		default:
+			assert.Equal(t, "2", r.Header.Get("Last-Event-ID"))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	es, err := New(WithHTTPClient(srv.Client())).Connect(req, ReconnectOptions{InitialDelay: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, Open, es.ReadyState())
+
+	ev, err := es.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, Event{ID: "1", Type: "message", Data: "a"}, ev)
+
+	// The server's 1ms retry hint replaces the hour-long initial delay.
+	ev, err = es.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, Event{ID: "2", Type: "message", Data: "b"}, ev)
+	assert.Equal(t, State{LastEventID: "2", Retry: time.Millisecond}, es.State())
+
+	// 204 on reconnect ends the stream.
+	_, err = es.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, Closed, es.ReadyState())
+	assert.Equal(t, int32(3), requests.Load())
+	assert.Equal(t, []ReadyState{Connecting, Open, Connecting, Open, Connecting, Closed}, collectStatuses(es))
+}
+
+func TestEventSource_RetryableStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, "data: ok\n\n")
+		case "/teapot":
+			w.WriteHeader(http.StatusTeapot)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c := New(WithHTTPClient(srv.Client()))
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/flaky", nil)
+	require.NoError(t, err)
+	es, err := c.Connect(req, fastReconnect)
+	require.NoError(t, err)
+	ev, err := es.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "ok", ev.Data)
+	require.NoError(t, es.Close())
+	_, err = es.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+
+	// Non-retryable statuses fail immediately.
+	req, err = http.NewRequest(http.MethodGet, srv.URL+"/teapot", nil)
+	require.NoError(t, err)
+	_, err = c.Connect(req, fastReconnect)
+	var openErr *OpenError
+	require.True(t, errors.As(err, &openErr))
+	assert.Equal(t, http.StatusTeapot, openErr.Response.StatusCode)
+
+	// MaxAttempts bounds retries, and the codes are configurable.
+	opts := fastReconnect
+	opts.MaxAttempts = 2
+	req, err = http.NewRequest(http.MethodGet, srv.URL+"/down", nil)
+	require.NoError(t, err)
+	_, err = c.Connect(req, opts)
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+
+	opts.RetryableStatusCodes = []int{http.StatusTeapot}
+	_, err = c.Connect(req, opts)
+	require.True(t, errors.As(err, &openErr))
+	assert.Equal(t, http.StatusInternalServerError, openErr.Response.StatusCode)
+}
+
+func TestEventSource_RequireEventIDReplaysBodyButNotStream(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"q":1}`, string(body))
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: partial\n\n")
+		// The connection drops without an event ID to resume from.
+	}))
+	t.Cleanup(srv.Close)
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, bytes.NewReader([]byte(`{"q":1}`)))
+	require.NoError(t, err)
+	opts := fastReconnect
+	opts.RequireEventID = true
+	es, err := New(WithHTTPClient(srv.Client())).Connect(req, opts)
+	require.NoError(t, err)
+
+	ev, err := es.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "partial", ev.Data)
+	_, err = es.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, int32(2), requests.Load())
+
+	statuses := []Status{}
+	for status := range es.Statuses() {
+		statuses = append(statuses, status)
+	}
+	require.Len(t, statuses, 4)
+	assert.Equal(t, Status{State: Connecting}, statuses[0])
+	assert.Equal(t, Connecting, statuses[1].State)
+	assert.Equal(t, 1, statuses[1].Attempt)
+	assert.Equal(t, time.Millisecond, statuses[1].Delay)
+	assert.ErrorIs(t, statuses[1].Err, ErrUnexpectedStatus)
+	assert.Equal(t, Status{State: Open, Attempt: 1}, statuses[2])
+	assert.Equal(t, Status{State: Closed, Err: io.EOF}, statuses[3])
+}
+
+func TestEventSource_CloseAndCancelInterruptBackoff(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: once\n\n")
+	}))
+	t.Cleanup(srv.Close)
+	c := New(WithHTTPClient(srv.Client()))
+	slow := ReconnectOptions{InitialDelay: time.Hour, Jitter: -1}
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	es, err := c.Connect(req, slow)
+	require.NoError(t, err)
+	_, err = es.Recv()
+	require.NoError(t, err)
+
+	// A receive deadline interrupts the backoff without closing the EventSource.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = es.RecvContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, Connecting, es.ReadyState())
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := es.Recv()
+		done <- err
+	}()
+	// The interrupted reconnect is retried right away.
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Recv did not reconnect")
+	}
+
+	go func() {
+		_, err := es.Recv()
+		done <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, es.Close())
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, io.EOF)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not interrupt the backoff")
+	}
+	assert.Equal(t, Closed, es.ReadyState())
+
+	// Canceling the request context ends the EventSource.
+	reqCtx, reqCancel := context.WithCancel(context.Background())
+	req, err = http.NewRequestWithContext(reqCtx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	es, err = c.Connect(req, slow)
+	require.NoError(t, err)
+	_, err = es.Recv()
+	require.NoError(t, err)
+	time.AfterFunc(20*time.Millisecond, reqCancel)
+	_, err = es.Recv()
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestEventSource_Backoff(t *testing.T) {
+	t.Parallel()
+
+	es := &EventSource{opts: ReconnectOptions{InitialDelay: 10 * time.Millisecond, MaxDelay: 30 * time.Millisecond, Multiplier: 2, Jitter: -1}}
+	assert.Equal(t, 10*time.Millisecond, es.backoff(1, nil))
+	assert.Equal(t, 20*time.Millisecond, es.backoff(2, nil))
+	assert.Equal(t, 30*time.Millisecond, es.backoff(3, nil))
+
+	// A server retry hint replaces InitialDelay and may exceed MaxDelay.
+	es.state.Retry = 100 * time.Millisecond
+	assert.Equal(t, 100*time.Millisecond, es.backoff(1, nil))
+	assert.Equal(t, 100*time.Millisecond, es.backoff(4, nil))
+
+	// Retry-After is honored when longer.
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": []string{"2"}}}
+	assert.Equal(t, 2*time.Second, es.backoff(1, &OpenError{Response: resp, Err: ErrUnexpectedStatus}))
+
+	// Jitter stays within bounds.
+	es = &EventSource{opts: ReconnectOptions{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, Multiplier: 2, Jitter: 0.5}}
+	for i := 0; i < 50; i++ {
+		d := es.backoff(1, nil)
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+		assert.LessOrEqual(t, d, 150*time.Millisecond)
+	}
+}
+
+func TestReadyState_String(t *testing.T) {
+	assert.Equal(t, "connecting", Connecting.String())
+	assert.Equal(t, "open", Open.String())
+	assert.Equal(t, "closed", Closed.String())
+	assert.Equal(t, "ReadyState(7)", ReadyState(7).String())
+}
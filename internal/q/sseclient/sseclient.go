@@ -28,7 +28,7 @@ func init() {
 
 // Client opens SSE HTTP connections and decodes text/event-stream responses.
 //
-// Reconnect policy is caller-managed.
+// Streams from OpenRequest and OpenURL leave reconnects to the caller; Connect returns an EventSource that reconnects automatically.
 type Client struct {
 	httpClient     *http.Client // The HTTP client sends stream requests.
 	defaultHeaders http.Header  // Default headers are added to opened requests unless the request already sets the header.
@@ -130,6 +130,11 @@ type Stream struct {
 //   - If Accept is unset, sets Accept: text/event-stream.
 //   - Fails with *OpenError on transport/handshake problems.
 func (c *Client) OpenRequest(req *http.Request) (*Stream, error) {
+	return c.openRequest(req, State{})
+}
+
+// openRequest is OpenRequest for a stream whose parser starts with state (ex: reconnect hints carried over from an earlier connection).
+func (c *Client) openRequest(req *http.Request, state State) (*Stream, error) {
 	if req == nil {
 		return nil, &OpenError{Err: errors.New("nil request")}
 	}
@@ -194,6 +199,7 @@ func (c *Client) OpenRequest(req *http.Request) (*Stream, error) {
 	s := &Stream{
 		response: resp,
 		results:  make(chan recvResult),
+		state:    state,
 	}
 	go s.readLoop()
 	return s, nil
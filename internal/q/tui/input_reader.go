@@ -223,7 +223,8 @@ func (p *inputProcessor) run() {
 		}
 
 		n, err := p.read(buf)
-		if n > 0 {
+		// Input that arrives while the terminal is released belongs to whoever has it (ex: a child process); a blocking read may still return some of it.
+		if n > 0 && !p.t.released.Load() {
 			p.append(buf[:n])
 		}
 		if err != nil {
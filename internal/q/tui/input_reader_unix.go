@@ -28,42 +28,58 @@ func (p *inputProcessor) read(buf []byte) (int, error) {
 		if err := p.t.ctx.Err(); err != nil {
 			return 0, err
 		}
-
-		fds := []unix.PollFd{{
-			Fd:     int32(fd),
-			Events: unix.POLLIN | unix.POLLHUP | unix.POLLERR,
-		}}
-		n, err := unix.Poll(fds, timeout)
-		if err == unix.EINTR {
-			continue
-		}
-		if err != nil {
-			return 0, err
-		}
-		if n == 0 {
-			continue
+		if n, ok, err := p.pollRead(fd, buf, timeout); ok {
+			return n, err
 		}
+	}
+}
+
+// The pollRead method waits up to timeout milliseconds for fd to be readable and reads from it, holding the TUI's input lock so ReleaseTerminal can wait it out.
+//
+// ok is false if nothing was read and the caller should poll again (ex: timeout, EINTR, or the terminal is released).
+func (p *inputProcessor) pollRead(fd int, buf []byte, timeout int) (n int, ok bool, err error) {
+	p.t.inputMu.Lock()
+	if p.t.released.Load() {
+		p.t.inputMu.Unlock()
+		time.Sleep(inputPollInterval)
+		return 0, false, nil
+	}
+	defer p.t.inputMu.Unlock()
+
+	fds := []unix.PollFd{{
+		Fd:     int32(fd),
+		Events: unix.POLLIN | unix.POLLHUP | unix.POLLERR,
+	}}
+	ready, err := unix.Poll(fds, timeout)
+	if err == unix.EINTR {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, true, err
+	}
+	if ready == 0 {
+		return 0, false, nil
+	}
+
+	revents := fds[0].Revents
+	if revents&unix.POLLNVAL != 0 {
+		return 0, true, unix.EBADF
+	}
+	if revents&(unix.POLLIN|unix.POLLHUP|unix.POLLERR) == 0 {
+		return 0, false, nil
+	}
 
-		revents := fds[0].Revents
-		if revents&unix.POLLNVAL != 0 {
-			return 0, unix.EBADF
+	for {
+		n, err := unix.Read(fd, buf)
+		if n >= 0 {
+			return n, true, err
 		}
-		if revents&(unix.POLLIN|unix.POLLHUP|unix.POLLERR) == 0 {
+		if err == unix.EINTR {
 			continue
 		}
-
-		for {
-			n, err := unix.Read(fd, buf)
-			if n >= 0 {
-				return n, err
-			}
-			if err == unix.EINTR {
-				continue
-			}
-			if err == unix.EAGAIN {
-				break
-			}
-			return n, err
+		if err == unix.EAGAIN {
+			return 0, false, nil
 		}
+		return n, true, err
 	}
 }
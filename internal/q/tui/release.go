@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// ExecDoneFunc builds the message sent to Update when a process started by ExecProcess exits. err is the error from running the process or from releasing/restoring
+// the terminal. A nil return sends nothing.
+type ExecDoneFunc func(err error) Message
+
+// ReleaseTerminal hands the terminal back to the user or a child process: raw and alternate-screen modes are exited, input is no longer read, and View is no longer
+// rendered (Update is still called). It is a no-op if the terminal is already released or the TUI is stopping.
+//
+// While released, SIGINT and SIGTSTP (ex: Ctrl-C/Ctrl-Z typed into a pager that shares our process group) are ignored rather than interrupting or suspending the TUI.
+func (t *TUI) ReleaseTerminal() error {
+	t.releaseMu.Lock()
+	defer t.releaseMu.Unlock()
+
+	if t.released.Load() || t.isStopping() {
+		return nil
+	}
+
+	// Wait out any in-progress read so the input reader doesn't consume bytes meant for a child process.
+	t.inputMu.Lock()
+	t.released.Store(true)
+	t.inputMu.Unlock()
+
+	// Let any in-progress render finish before leaving the alternate screen.
+	t.renderMu.Lock()
+	defer t.renderMu.Unlock()
+	if t.term != nil {
+		if err := t.term.Exit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreTerminal undoes ReleaseTerminal: raw and alternate-screen modes are re-entered, input is read again, and the screen is fully redrawn. A ResizeEvent is always
+// sent afterwards (even if the size didn't change) so the model can re-layout and trigger the redraw. It is a no-op if the terminal isn't released or the TUI is
+// stopping.
+func (t *TUI) RestoreTerminal() error {
+	t.releaseMu.Lock()
+	defer t.releaseMu.Unlock()
+
+	if !t.released.Load() || t.isStopping() {
+		return nil
+	}
+	if err := t.enterTerminal(); err != nil {
+		return err
+	}
+	t.released.Store(false)
+
+	t.invalidateRenderCache(true)
+	t.sizeMu.Lock()
+	t.sizeKnown = false
+	t.sizeMu.Unlock()
+	t.triggerResizeEvent()
+	return nil
+}
+
+// ExecProcess runs cmd in a new goroutine (as with Go) with the terminal released, then restores it. Good for editors, pagers, and interactive commands (ex: `$EDITOR
+// file`, `less`, `git add -p`).
+//   - cmd's nil Stdin, Stdout, and Stderr are connected to the terminal.
+//   - Processes started by ExecProcess run one at a time; a second call waits for the first process to exit.
+//   - When cmd exits, done(err) is sent to Update, if done is non-nil.
+//   - If the TUI stops while cmd is running, RunTUI waits for cmd to exit.
+func (t *TUI) ExecProcess(cmd *exec.Cmd, done ExecDoneFunc) {
+	t.Go(func(context.Context) Message {
+		err := t.execProcess(cmd)
+		if done == nil {
+			return nil
+		}
+		return done(err)
+	})
+}
+
+// The execProcess method runs cmd to completion with the terminal released.
+func (t *TUI) execProcess(cmd *exec.Cmd) (err error) {
+	t.execMu.Lock()
+	defer t.execMu.Unlock()
+
+	if err := t.ReleaseTerminal(); err != nil {
+		return err
+	}
+	defer func() {
+		if restoreErr := t.RestoreTerminal(); restoreErr != nil && err == nil {
+			err = restoreErr
+		}
+	}()
+
+	in, out := t.processIO()
+	if cmd.Stdin == nil {
+		cmd.Stdin = in
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = out
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = out
+	}
+	return cmd.Run()
+}
+
+// The processIO method returns the terminal input and output for child processes: the TUI's own streams when they are files, else os.Stdin and os.Stdout.
+func (t *TUI) processIO() (io.Reader, io.Writer) {
+	var in io.Reader = os.Stdin
+	var out io.Writer = os.Stdout
+	if f, ok := t.input.(*os.File); ok && f != nil {
+		in = f
+	}
+	if f, ok := t.output.(*os.File); ok && f != nil {
+		out = f
+	}
+	return in, out
+}
+
+// The isStopping method reports whether shutdown has begun.
+func (t *TUI) isStopping() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stopping
+}
@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type releaseModel struct {
+	mu      sync.Mutex
+	step    int
+	resizes int
+	views   []string // views are the View calls, as "step:<n>", while released or not.
+}
+
+type releaseStepMsg struct{}
+
+func (m *releaseModel) Init(t *TUI) {}
+
+func (m *releaseModel) Update(t *TUI, msg Message) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch msg.(type) {
+	case ResizeEvent:
+		m.resizes++
+		if m.resizes == 1 {
+			if err := t.ReleaseTerminal(); err != nil {
+				panic(err)
+			}
+			m.step = 1
+			t.Send(releaseStepMsg{})
+		} else {
+			t.Quit()
+		}
+	case releaseStepMsg:
+		m.step = 2
+		if err := t.RestoreTerminal(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (m *releaseModel) View() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return "step " + string(rune('0'+m.step))
+}
+
+func TestReleaseRestoreTerminal(t *testing.T) {
+	term := &recordingTerminal{}
+	out := &recordingWriter{}
+	model := &releaseModel{}
+
+	err := runTUITest(t, model, func(opts *Options) {
+		opts.Output = out
+		opts.sizeProvider = func() (int, int, error) { return 80, 24, nil }
+		opts.terminalFactory = func(io.Reader, io.Writer) (terminalController, error) {
+			return term, nil
+		}
+	})
+	require.NoError(t, err)
+
+	term.mu.Lock()
+	defer term.mu.Unlock()
+	assert.Equal(t, 2, term.enterCount, "entered at startup and on restore")
+	assert.Equal(t, 2, term.exitCount, "exited on release and at shutdown")
+
+	// Nothing renders while released (step 1); restoring sends a ResizeEvent for the same size, and the screen is fully redrawn.
+	model.mu.Lock()
+	assert.Equal(t, 2, model.resizes)
+	model.mu.Unlock()
+	writes, _ := out.snapshot()
+	all := strings.Join(writes, "")
+	assert.NotContains(t, all, "step 1")
+	assert.Contains(t, all, clearScreen+"\x1b[1;1Hstep 2")
+}
+
+type execModel struct {
+	cmd  *exec.Cmd
+	mu   sync.Mutex
+	errs []error
+}
+
+type execDoneMsg struct{ err error }
+
+func (m *execModel) Init(t *TUI) {
+	t.ExecProcess(m.cmd, func(err error) Message { return execDoneMsg{err: err} })
+}
+
+func (m *execModel) Update(t *TUI, msg Message) {
+	if done, ok := msg.(execDoneMsg); ok {
+		m.mu.Lock()
+		m.errs = append(m.errs, done.err)
+		m.mu.Unlock()
+		t.Quit()
+	}
+}
+
+func (m *execModel) View() string { return "" }
+
+func TestExecProcess(t *testing.T) {
+	term := &recordingTerminal{}
+	run := func(m Model) error {
+		done := make(chan error, 1)
+		go func() {
+			done <- RunTUI(m, Options{
+				Output:            io.Discard,
+				skipTTYValidation: true,
+				terminalFactory: func(io.Reader, io.Writer) (terminalController, error) {
+					return term, nil
+				},
+			})
+		}()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(10 * time.Second):
+			t.Fatal("RunTUI timed out")
+			return nil
+		}
+	}
+
+	// The test binary with no matching tests exits 0 quickly.
+	var stdout strings.Builder
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stdout
+	model := &execModel{cmd: cmd}
+	require.NoError(t, run(model))
+	require.Len(t, model.errs, 1)
+	assert.NoError(t, model.errs[0])
+	assert.Contains(t, stdout.String(), "PASS")
+
+	term.mu.Lock()
+	assert.Equal(t, 2, term.enterCount)
+	assert.Equal(t, 2, term.exitCount)
+	term.mu.Unlock()
+
+	model = &execModel{cmd: exec.Command(t.TempDir() + "/does-not-exist")}
+	require.NoError(t, run(model))
+	require.Len(t, model.errs, 1)
+	assert.Error(t, model.errs[0])
+}
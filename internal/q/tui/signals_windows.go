@@ -9,7 +9,7 @@ import (
 
 func signalBindings() []signalBinding {
 	return []signalBinding{
-		{sig: os.Interrupt, action: func(t *TUI) { t.Interrupt() }},
+		{sig: os.Interrupt, action: func(t *TUI) { t.Interrupt() }, ignoreWhileReleased: true},
 		{sig: syscall.SIGTERM, action: func(t *TUI) { t.Quit() }},
 	}
 }
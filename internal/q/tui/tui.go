@@ -333,6 +333,10 @@ type TUI struct {
 	sizeKnown      bool                     // SizeKnown reports whether a terminal size has been observed.
 	suspendMu      sync.Mutex               // SuspendMu protects suspended.
 	suspended      bool                     // Suspended reports whether the process is currently suspended by the TUI.
+	releaseMu      sync.Mutex               // ReleaseMu serializes ReleaseTerminal and RestoreTerminal.
+	released       atomic.Bool              // Released reports whether the terminal is released (see ReleaseTerminal).
+	inputMu        sync.Mutex               // InputMu is held by the input reader while it reads, so ReleaseTerminal can wait out an in-progress read.
+	execMu         sync.Mutex               // ExecMu serializes processes run by ExecProcess.
 	wg             sync.WaitGroup           // WG tracks goroutines that must finish before final cleanup completes.
 	panicOnce      sync.Once                // PanicOnce ensures only the first recovered panic is recorded.
 	panicMu        sync.Mutex               // PanicMu protects panicValue and panicStack.
@@ -636,6 +640,11 @@ func (t *TUI) render() {
 	for {
 		t.renderMu.Lock()
 
+		if t.released.Load() {
+			t.renderMu.Unlock()
+			return
+		}
+
 		if t.frameDuration > 0 && !t.lastRender.IsZero() {
 			if remaining := t.frameDuration - time.Since(t.lastRender); remaining > 0 {
 				t.renderMu.Unlock()
@@ -1048,8 +1057,9 @@ func (t *TUI) handleResizeSignal() {
 
 // The signalBinding type associates an operating-system signal with the TUI action that handles it.
 type signalBinding struct {
-	sig    os.Signal  // Sig is the signal received from the operating system.
-	action func(*TUI) // Action handles sig for the active TUI.
+	sig                 os.Signal  // Sig is the signal received from the operating system.
+	action              func(*TUI) // Action handles sig for the active TUI.
+	ignoreWhileReleased bool       // IgnoreWhileReleased drops sig while the terminal is released (see ReleaseTerminal).
 }
 
 // The startSignalProcessor method starts platform signal handling for the TUI session.
@@ -1090,6 +1100,9 @@ func (t *TUI) startSignalProcessor() {
 				}
 				for _, b := range bindings {
 					if sig == b.sig {
+						if b.ignoreWhileReleased && t.released.Load() {
+							break
+						}
 						b.action(t)
 						break
 					}
@@ -1248,6 +1248,7 @@ type wrapGrapheme struct {
 	hasSHY   bool // U+00AD SOFT HYPHEN
 	ascii    byte // only if single-rune ASCII; otherwise 0
 	hasASCII bool // HasASCII is true when the cluster is exactly one ASCII rune.
+	uaxBreak bool // UAXBreak is true when UAX #14 allows a line break after the cluster. Only computed for non-ASCII lines.
 }
 
 // The graphemesForWrap function returns wrapping metadata for each grapheme cluster in s.
@@ -1257,6 +1258,16 @@ func graphemesForWrap(s string) []wrapGrapheme {
 	if s == "" {
 		return nil
 	}
+
+	// UAX #14 break opportunities only matter next to non-ASCII graphemes (see wrapBreakOpportunityAfter), so skip computing them for ASCII-only lines.
+	var uaxBreakEnds []int // increasing byte offsets
+	if !isASCII(s) {
+		lines := uni.NewLineIterator(s, nil)
+		for lines.Next() {
+			uaxBreakEnds = append(uaxBreakEnds, lines.End())
+		}
+	}
+
 	iter := uni.NewGraphemeIterator(s, nil)
 	out := make([]wrapGrapheme, 0, 32)
 	for iter.Next() {
@@ -1284,16 +1295,30 @@ func graphemesForWrap(s string) []wrapGrapheme {
 				g.isAlnum = true
 			}
 		}
+		for len(uaxBreakEnds) > 0 && uaxBreakEnds[0] < g.end {
+			uaxBreakEnds = uaxBreakEnds[1:]
+		}
+		g.uaxBreak = len(uaxBreakEnds) > 0 && uaxBreakEnds[0] == g.end
 		out = append(out, g)
 	}
 	return out
 }
 
+// isASCII reports whether s contains only ASCII bytes.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
 // The wrapBreakOpportunityAfter function reports whether TextArea wrapping may break between gs[i] and gs[i+1].
 //
 // It returns false for out-of-range indexes, for the final grapheme, and across WORD JOINER. It permits breaks after Unicode whitespace; after `/`, `|`, `!`, `?`,
 // and `}`; after `.` or `,` unless between alphanumeric graphemes; and after `-` only when between alphanumeric graphemes. It does not treat SOFT HYPHEN as a break
-// opportunity.
+// opportunity. Between graphemes where either is non-ASCII (ex: CJK ideographs, emoji), it permits UAX #14 break opportunities.
 func wrapBreakOpportunityAfter(gs []wrapGrapheme, i int) bool {
 	if i < 0 || i >= len(gs)-1 {
 		return false
@@ -1345,6 +1370,11 @@ func wrapBreakOpportunityAfter(gs []wrapGrapheme, i int) bool {
 		return false
 	}
 
+	// The ASCII rules above are tailored; elsewhere (ex: between CJK ideographs, which have no spaces), defer to UAX #14.
+	if !gs[i].hasASCII || !gs[i+1].hasASCII {
+		return gs[i].uaxBreak
+	}
+
 	return false
 }
 
@@ -369,6 +369,18 @@ func TestTextArea_Wrapping_DoesNotBreakAfterDotCommaBetweenAlnum(t *testing.T) {
 	require.Equal(t, []string{"aa", ",bb"}, ta.ClippedDisplayContents())
 }
 
+func TestTextArea_Wrapping_UsesUAX14ForNonASCII(t *testing.T) {
+	ta := tuicontrols.NewTextArea(7, 10)
+
+	// Ideographs break between each other, but closing punctuation stays with the preceding ideograph.
+	ta.SetContents("你好你。再")
+	require.Equal(t, []string{"你好", "你。再"}, ta.ClippedDisplayContents())
+
+	// No break between non-ASCII letters within a word.
+	ta.SetContents("añoño ab")
+	require.Equal(t, []string{"añoño ", "ab"}, ta.ClippedDisplayContents())
+}
+
 func TestTextArea_Wrapping_WordJoinerPreventsBreaksAroundIt(t *testing.T) {
 	ta := tuicontrols.NewTextArea(5, 10)
 	ta.SetContents("aa/\u2060bb")
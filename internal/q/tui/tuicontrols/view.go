@@ -10,7 +10,7 @@ import (
 // View is a scrollable, fixed-size view over a newline-delimited string.
 //
 // Invariant:
-//   - Offset() is always in the range [0, number of lines).
+//   - Offset() is always in the range [0, number of shown lines).
 //   - Empty content counts as 1 line.
 type View struct {
 	width                    int              // The width field is the nonnegative visible-cell width used for horizontal clipping.
@@ -20,6 +20,16 @@ type View struct {
 	lines                    []string         // The lines field caches content split on `\n`; empty content is cached as one empty line.
 	keyMap                   *KeyMap          // The keyMap field maps key events to scroll commands handled by Update.
 	emptyLineBackgroundColor termformat.Color // The emptyLineBackgroundColor field fills rows that have no content; nil disables the fill.
+
+	sections []Section // The sections field tags content line ranges for filtering and marks (see SetSections).
+	filter   Filter    // The filter field selects which content lines are shown (see SetFilter).
+	shown    []int     // The shown field lists the content lines shown, in order, when a filter is active; nil means all lines are shown.
+	marks    []int     // The marks field lists the shown-line indexes of section marks, ascending, for NextMark and PrevMark.
+
+	searching   bool        // The searching field is true while the search prompt is open.
+	query       string      // The query field is the current search query; empty means no search.
+	matches     []viewMatch // The matches field lists query matches in shown lines, in display order.
+	matchCursor int         // The matchCursor field indexes the current match in matches, or is -1 for none.
 }
 
 // NewView returns a new view of the given size.
@@ -37,7 +47,12 @@ func NewView(width, height int) *View {
 	km.Add(tui.KeyEvent{ControlKey: tui.ControlKeyDown}, "down")
 	km.Add(tui.KeyEvent{ControlKey: tui.ControlKeyHome}, "home")
 	km.Add(tui.KeyEvent{ControlKey: tui.ControlKeyEnd}, "end")
+	km.Add(tui.KeyEvent{ControlKey: tui.ControlKeyCtrlUp}, "prevmark")
+	km.Add(tui.KeyEvent{ControlKey: tui.ControlKeyCtrlDown}, "nextmark")
+	km.Add(tui.KeyEvent{ControlKey: tui.ControlKeyCtrlF}, "search")
+	km.Add(tui.KeyEvent{ControlKey: tui.ControlKeyNone, Runes: []rune{'/'}}, "search")
 	v.keyMap = km
+	v.matchCursor = -1
 
 	return v
 }
@@ -47,7 +62,13 @@ func (v *View) Init(t *tui.TUI) {}
 
 // Update implements tui.Model's Update.
 func (v *View) Update(t *tui.TUI, m tui.Message) {
-	if v == nil || v.keyMap == nil {
+	if v == nil {
+		return
+	}
+	if v.searching && v.updateSearchPrompt(m) {
+		return
+	}
+	if v.keyMap == nil {
 		return
 	}
 	switch v.keyMap.Process(m) {
@@ -63,6 +84,12 @@ func (v *View) Update(t *tui.TUI, m tui.Message) {
 		v.ScrollToTop()
 	case "end":
 		v.ScrollToBottom()
+	case "prevmark":
+		v.PrevMark()
+	case "nextmark":
+		v.NextMark()
+	case "search":
+		v.StartSearch()
 	}
 }
 
@@ -78,18 +105,22 @@ func (v *View) View() string {
 		v.lines = splitLines(v.content)
 	}
 
+	numRows := v.contentRows()
 	rows := make([]string, 0, v.height)
-	for i := 0; i < v.height; i++ {
+	for i := 0; i < numRows; i++ {
 		if v.content == "" {
 			rows = append(rows, v.renderEmptyRow())
 			continue
 		}
 		lineIdx := v.offset + i
-		if lineIdx < 0 || lineIdx >= len(v.lines) {
+		if lineIdx < 0 || lineIdx >= v.numShown() {
 			rows = append(rows, v.renderEmptyRow())
 			continue
 		}
-		rows = append(rows, clipLine(v.lines[lineIdx], v.width))
+		rows = append(rows, clipLine(v.highlightShownLine(lineIdx), v.width))
+	}
+	if v.searching && v.height > 0 {
+		rows = append(rows, v.renderSearchPrompt())
 	}
 	return strings.Join(rows, "\n")
 }
@@ -143,12 +174,12 @@ func (v *View) ScrollPercent() int {
 		return 0
 	}
 
-	numLines := len(v.lines)
+	numLines := v.numShown()
 	if numLines == 0 {
 		return 0
 	}
 
-	if v.height <= 0 {
+	if v.contentRows() <= 0 {
 		return 0
 	}
 
@@ -194,24 +225,24 @@ func (v *View) ScrollDown(n int) {
 	v.normalizeOffset()
 }
 
-// PageUp scrolls up one page: ScrollUp(max(1, v.Height()-1)).
+// PageUp scrolls up one page: ScrollUp(max(1, v.Height()-1)). While the search prompt is open, the page is one row shorter.
 func (v *View) PageUp() {
 	if v == nil {
 		return
 	}
-	n := v.height - 1
+	n := v.contentRows() - 1
 	if n < 1 {
 		n = 1
 	}
 	v.ScrollUp(n)
 }
 
-// PageDown scrolls down one page: ScrollDown(max(1, v.Height()-1)).
+// PageDown scrolls down one page: ScrollDown(max(1, v.Height()-1)). While the search prompt is open, the page is one row shorter.
 func (v *View) PageDown() {
 	if v == nil {
 		return
 	}
-	n := v.height - 1
+	n := v.contentRows() - 1
 	if n < 1 {
 		n = 1
 	}
@@ -248,24 +279,28 @@ func (v *View) AtBottom() bool {
 	if v == nil {
 		return true
 	}
-	numLines := len(v.lines)
+	numLines := v.numShown()
 	if numLines == 0 {
 		return true
 	}
-	if v.height <= 0 {
+	if v.contentRows() <= 0 {
 		return false
 	}
-	return v.offset+v.height >= numLines
+	return v.offset+v.contentRows() >= numLines
 }
 
-// SetContent sets the content to s. This won't change Offset() unless it violates the offset invariant.
+// SetContent sets the content to s. This won't change Offset() unless it violates the offset invariant. Sections are kept; if they also changed, call SetSections
+// afterwards.
+//
+// While a filter is active, the view stays anchored on the content line at its top (or the next shown line).
 func (v *View) SetContent(s string) {
 	if v == nil {
 		return
 	}
+	top := v.topContentLine()
 	v.content = s
 	v.lines = splitLines(s)
-	v.clampOffset()
+	v.applyFilter(top)
 }
 
 // The clampOffset method clamps the offset to the valid line range, initializing the line cache from content if necessary.
@@ -282,12 +317,12 @@ func (v *View) clampOffset() {
 		v.offset = 0
 		return
 	}
-	if len(v.lines) == 0 {
+	if v.numShown() == 0 {
 		v.offset = 0
 		return
 	}
-	if v.offset >= len(v.lines) {
-		v.offset = len(v.lines) - 1
+	if v.offset >= v.numShown() {
+		v.offset = v.numShown() - 1
 	}
 }
 
@@ -304,23 +339,23 @@ func (v *View) normalizeOffset() {
 	}
 }
 
-// The maxOffset method returns the greatest useful offset for the current content and height.
+// The maxOffset method returns the greatest useful offset for the shown lines and content rows.
 //
 // For a positive height, it places the final content line on the last visible row when possible; for a nonpositive height, it returns the last valid line offset.
 // It returns 0 for a nil view or empty line cache.
 func (v *View) maxOffset() int {
-	if v == nil || len(v.lines) == 0 {
+	if v == nil || v.numShown() == 0 {
 		return 0
 	}
-	if v.height <= 0 {
-		return len(v.lines) - 1
+	if v.contentRows() <= 0 {
+		return v.numShown() - 1
 	}
-	maxOffset := len(v.lines) - v.height
+	maxOffset := v.numShown() - v.contentRows()
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
-	if maxOffset > len(v.lines)-1 {
-		maxOffset = len(v.lines) - 1
+	if maxOffset > v.numShown()-1 {
+		maxOffset = v.numShown() - 1
 	}
 	return maxOffset
 }
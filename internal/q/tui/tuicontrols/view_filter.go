@@ -0,0 +1,238 @@
+package tuicontrols
+
+import "sort"
+
+// Section tags a range of content lines so a View can filter them (see Filter) and jump between them (see NextMark). Ex: a chat view might add one section per
+// message, tagged with the message's kind, and mark each user message as the start of a turn.
+type Section struct {
+	Start  int      // Start is the first content line (0-based) of the section.
+	End    int      // End is one past the last content line of the section.
+	Header int      // Header is the number of lines at the start of the section that stay shown when the section is collapsed.
+	Tags   []string // Tags classify the section (ex: "assistant", "tool", "error"). Tags are application-defined.
+	Mark   bool     // Mark makes the section's first shown line a jump target for NextMark and PrevMark.
+}
+
+// Filter selects which content lines a View shows. The zero Filter shows all lines.
+type Filter struct {
+	Only     []string // Only, if non-empty, shows only lines in sections having at least one of these tags. Lines outside any such section are hidden.
+	Collapse []string // Collapse hides all but the Header lines of sections having at least one of these tags.
+}
+
+// IsZero reports whether f shows all lines.
+func (f Filter) IsZero() bool {
+	return len(f.Only) == 0 && len(f.Collapse) == 0
+}
+
+// SetSections sets the sections of the content, replacing any previous ones. Sections may overlap; parts of sections outside the content are ignored. It should be
+// called whenever the content's layout changes.
+func (v *View) SetSections(sections []Section) {
+	if v == nil {
+		return
+	}
+	top := v.topContentLine()
+	v.sections = append([]Section(nil), sections...)
+	v.applyFilter(top)
+}
+
+// Sections returns the sections set by SetSections.
+func (v *View) Sections() []Section {
+	if v == nil {
+		return nil
+	}
+	return append([]Section(nil), v.sections...)
+}
+
+// SetFilter sets which content lines are shown. The view stays anchored on the content line at its top (or the next shown line). Offset() and the other scrolling
+// methods count shown lines, not content lines.
+func (v *View) SetFilter(f Filter) {
+	if v == nil {
+		return
+	}
+	top := v.topContentLine()
+	v.filter = Filter{Only: append([]string(nil), f.Only...), Collapse: append([]string(nil), f.Collapse...)}
+	v.applyFilter(top)
+}
+
+// Filter returns the filter set by SetFilter.
+func (v *View) Filter() Filter {
+	if v == nil {
+		return Filter{}
+	}
+	return v.filter
+}
+
+// LineAt returns the content line (0-based) rendered at row (0-based) of the view. It returns false if row shows no content line (ex: rows below the content, or
+// the search prompt).
+func (v *View) LineAt(row int) (int, bool) {
+	if v == nil || row < 0 || row >= v.contentRows() {
+		return 0, false
+	}
+	idx := v.offset + row
+	if idx >= v.numShown() {
+		return 0, false
+	}
+	return v.contentLine(idx), true
+}
+
+// NextMark scrolls down so that the next marked section below the top of the view is at the top. It returns false (without scrolling) if there is none, or if the
+// view can't scroll down any further.
+func (v *View) NextMark() bool {
+	if v == nil {
+		return false
+	}
+	i := sort.SearchInts(v.marks, v.offset+1)
+	if i >= len(v.marks) {
+		return false
+	}
+	prev := v.offset
+	v.offset = v.marks[i]
+	v.normalizeOffset()
+	return v.offset != prev
+}
+
+// PrevMark scrolls up so that the previous marked section above the top of the view is at the top. It returns false (without scrolling) if there is none.
+func (v *View) PrevMark() bool {
+	if v == nil {
+		return false
+	}
+	i := sort.SearchInts(v.marks, v.offset) - 1
+	if i < 0 {
+		return false
+	}
+	v.offset = v.marks[i]
+	v.normalizeOffset()
+	return true
+}
+
+// The applyFilter method recomputes the shown lines, marks, and search matches after the content, sections, or filter changed, then moves the offset to the shown
+// line at or after the content line top.
+func (v *View) applyFilter(top int) {
+	if v.lines == nil {
+		v.lines = splitLines(v.content)
+	}
+	v.shown = nil
+	if !v.filter.IsZero() {
+		hidden := make([]bool, len(v.lines))
+		if len(v.filter.Only) > 0 {
+			for i := range hidden {
+				hidden[i] = true
+			}
+			for _, sec := range v.sections {
+				if hasAnyTag(sec.Tags, v.filter.Only) {
+					v.setHidden(hidden, sec.Start, sec.End, false)
+				}
+			}
+		}
+		for _, sec := range v.sections {
+			if hasAnyTag(sec.Tags, v.filter.Collapse) {
+				v.setHidden(hidden, sec.Start+max0(sec.Header), sec.End, true)
+			}
+		}
+		v.shown = make([]int, 0, len(v.lines))
+		for i, h := range hidden {
+			if !h {
+				v.shown = append(v.shown, i)
+			}
+		}
+	}
+
+	v.marks = v.marks[:0]
+	for _, sec := range v.sections {
+		if !sec.Mark {
+			continue
+		}
+		idx := v.shownIndexAtOrAfter(sec.Start)
+		if idx < v.numShown() && v.contentLine(idx) < sec.End {
+			v.marks = append(v.marks, idx)
+		}
+	}
+	sort.Ints(v.marks)
+	v.marks = dedupeSorted(v.marks)
+
+	v.offset = v.shownIndexAtOrAfter(top)
+	v.clampOffset()
+	v.updateMatches()
+}
+
+// The setHidden method sets hidden for content lines [start, end), clipped to the content.
+func (v *View) setHidden(hidden []bool, start, end int, value bool) {
+	start = max0(start)
+	if end > len(hidden) {
+		end = len(hidden)
+	}
+	for i := start; i < end; i++ {
+		hidden[i] = value
+	}
+}
+
+// The numShown method returns the number of shown lines.
+func (v *View) numShown() int {
+	if v.shown == nil {
+		return len(v.lines)
+	}
+	return len(v.shown)
+}
+
+// The contentLine method returns the content line of shown line idx.
+func (v *View) contentLine(idx int) int {
+	if v.shown == nil {
+		return idx
+	}
+	return v.shown[idx]
+}
+
+// The shownLine method returns the text of shown line idx.
+func (v *View) shownLine(idx int) string {
+	return v.lines[v.contentLine(idx)]
+}
+
+// The shownIndexAtOrAfter method returns the index of the first shown line whose content line is at or after line, or numShown() if there is none.
+func (v *View) shownIndexAtOrAfter(line int) int {
+	if v.shown == nil {
+		if line > len(v.lines) {
+			return len(v.lines)
+		}
+		return max0(line)
+	}
+	return sort.SearchInts(v.shown, line)
+}
+
+// The topContentLine method returns the content line at the top of the view, or 0 if none.
+func (v *View) topContentLine() int {
+	if v.lines == nil || v.offset >= v.numShown() {
+		return v.offset
+	}
+	return v.contentLine(v.offset)
+}
+
+// The contentRows method returns the number of rows available for content lines: the height, less one row while the search prompt is open.
+func (v *View) contentRows() int {
+	if v.searching && v.height > 0 {
+		return v.height - 1
+	}
+	return v.height
+}
+
+func hasAnyTag(tags, want []string) bool {
+	for _, t := range tags {
+		for _, w := range want {
+			if t == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func dedupeSorted(s []int) []int {
+	if len(s) < 2 {
+		return s
+	}
+	out := s[:1]
+	for _, n := range s[1:] {
+		if n != out[len(out)-1] {
+			out = append(out, n)
+		}
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package tuicontrols
+
+import (
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/q/tui"
+	"github.com/stretchr/testify/require"
+)
+
+// newSectionedView returns a 3-row view over two turns, each a user line followed by a tool section (header + 2 body lines) and an assistant line.
+func newSectionedView() *View {
+	v := NewView(20, 3)
+	v.SetContent("u1\ntool1\nbody1a\nbody1b\na1\nu2\ntool2\nbody2a\nbody2b\na2")
+	v.SetSections([]Section{
+		{Start: 0, End: 1, Tags: []string{"user"}, Mark: true},
+		{Start: 1, End: 4, Header: 1, Tags: []string{"tool"}},
+		{Start: 4, End: 5, Tags: []string{"assistant"}},
+		{Start: 5, End: 6, Tags: []string{"user"}, Mark: true},
+		{Start: 6, End: 9, Header: 1, Tags: []string{"tool", "error"}},
+		{Start: 9, End: 10, Tags: []string{"assistant"}},
+	})
+	return v
+}
+
+func TestView_FilterOnly(t *testing.T) {
+	v := newSectionedView()
+
+	v.SetFilter(Filter{Only: []string{"assistant"}})
+	require.Equal(t, "a1\na2\n", v.View())
+	require.True(t, v.AtBottom())
+
+	// The top line (a1) is hidden, so the view anchors on the next shown line.
+	v.SetFilter(Filter{Only: []string{"error", "user"}})
+	require.Equal(t, "u2\ntool2\nbody2a", v.View())
+	v.ScrollToTop()
+	require.Equal(t, "u1\nu2\ntool2", v.View())
+	line, ok := v.LineAt(2)
+	require.True(t, ok)
+	require.Equal(t, 6, line)
+
+	v.SetFilter(Filter{})
+	require.Equal(t, "u1\ntool1\nbody1a", v.View())
+}
+
+func TestView_FilterCollapseKeepsHeaderAndAnchorsTop(t *testing.T) {
+	v := newSectionedView()
+	v.ScrollDown(4) // top is a1
+
+	v.SetFilter(Filter{Collapse: []string{"tool"}})
+	require.Equal(t, "a1\nu2\ntool2", v.View())
+	require.Equal(t, 2, v.Offset())
+
+	v.ScrollToTop()
+	require.Equal(t, "u1\ntool1\na1", v.View())
+	line, ok := v.LineAt(2)
+	require.True(t, ok)
+	require.Equal(t, 4, line)
+	_, ok = v.LineAt(3)
+	require.False(t, ok)
+}
+
+func TestView_Marks(t *testing.T) {
+	v := newSectionedView()
+
+	require.True(t, v.NextMark())
+	require.Equal(t, 5, v.Offset())
+	require.False(t, v.NextMark())
+
+	require.True(t, v.PrevMark())
+	require.Equal(t, 0, v.Offset())
+	require.False(t, v.PrevMark())
+
+	// Marks follow the filter.
+	v.SetFilter(Filter{Collapse: []string{"tool"}})
+	v.Update(nil, tui.KeyEvent{ControlKey: tui.ControlKeyCtrlDown})
+	require.Equal(t, "u2\ntool2\na2", v.View())
+	v.Update(nil, tui.KeyEvent{ControlKey: tui.ControlKeyCtrlUp})
+	require.Equal(t, 0, v.Offset())
+}
@@ -0,0 +1,346 @@
+package tuicontrols
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/codalotl/codalotl/internal/q/termformat"
+	"github.com/codalotl/codalotl/internal/q/tui"
+)
+
+const (
+	searchMatchOn         = "\x1b[7m"     // searchMatchOn starts a match highlight (reverse video).
+	searchCurrentMatchOn  = "\x1b[7;4m"   // searchCurrentMatchOn starts the current match's highlight (reverse video, underlined).
+	searchMatchOff        = "\x1b[27m"    // searchMatchOff ends a match highlight.
+	searchCurrentMatchOff = "\x1b[27;24m" // searchCurrentMatchOff ends the current match's highlight.
+)
+
+// viewMatch is one occurrence of the search query in a shown line.
+type viewMatch struct {
+	idx   int // idx is the shown-line index of the match.
+	start int // start is the index of the match's first rune in the line's visible text (ANSI codes excluded).
+	end   int // end is one past the index of the match's last rune.
+}
+
+// StartSearch opens the search prompt on the view's last row. While the prompt is open, Update sends key events to it:
+//   - Runes edit the query; Backspace deletes the last rune. Each edit moves to the first match at or below the top of the view.
+//   - Enter and Down Arrow move to the next match; Up Arrow moves to the previous match. Both wrap around.
+//   - Esc calls EndSearch.
+//   - Other keys are handled as usual (ex: Page Up/Page Down scroll).
+//
+// If a query was already set, it is kept.
+func (v *View) StartSearch() {
+	if v == nil || v.searching {
+		return
+	}
+	v.searching = true
+	v.normalizeOffset()
+}
+
+// EndSearch closes the search prompt and clears the query and highlights.
+func (v *View) EndSearch() {
+	if v == nil {
+		return
+	}
+	v.searching = false
+	v.query = ""
+	v.matches = nil
+	v.matchCursor = -1
+	v.normalizeOffset()
+}
+
+// Searching reports whether the search prompt is open.
+func (v *View) Searching() bool {
+	if v == nil {
+		return false
+	}
+	return v.searching
+}
+
+// SetSearchQuery sets the search query, highlights its matches in the shown lines, and scrolls to the first match at or below the top of the view (wrapping around).
+// Matching ignores ANSI codes and is case-insensitive unless query contains an upper-case letter. An empty query clears the highlights. It does not open the search
+// prompt.
+func (v *View) SetSearchQuery(query string) {
+	if v == nil {
+		return
+	}
+	v.query = query
+	v.matchCursor = -1
+	v.updateMatches()
+	if len(v.matches) == 0 {
+		return
+	}
+	v.matchCursor = sort.Search(len(v.matches), func(i int) bool { return v.matches[i].idx >= v.offset })
+	if v.matchCursor >= len(v.matches) {
+		v.matchCursor = 0
+	}
+	v.scrollToMatch()
+}
+
+// SearchQuery returns the search query.
+func (v *View) SearchQuery() string {
+	if v == nil {
+		return ""
+	}
+	return v.query
+}
+
+// MatchCount returns the number of matches of the search query in the shown lines.
+func (v *View) MatchCount() int {
+	if v == nil {
+		return 0
+	}
+	return len(v.matches)
+}
+
+// CurrentMatch returns the 1-based index of the current match, or 0 if there is none.
+func (v *View) CurrentMatch() int {
+	if v == nil || v.matchCursor < 0 {
+		return 0
+	}
+	return v.matchCursor + 1
+}
+
+// NextMatch makes the next match current (wrapping around) and scrolls it into view. It returns false if there are no matches.
+func (v *View) NextMatch() bool {
+	if v == nil || len(v.matches) == 0 {
+		return false
+	}
+	v.matchCursor = (v.matchCursor + 1) % len(v.matches)
+	v.scrollToMatch()
+	return true
+}
+
+// PrevMatch makes the previous match current (wrapping around) and scrolls it into view. It returns false if there are no matches.
+func (v *View) PrevMatch() bool {
+	if v == nil || len(v.matches) == 0 {
+		return false
+	}
+	if v.matchCursor <= 0 {
+		v.matchCursor = len(v.matches)
+	}
+	v.matchCursor--
+	v.scrollToMatch()
+	return true
+}
+
+// The updateSearchPrompt method handles m while the search prompt is open, reporting whether it was consumed.
+func (v *View) updateSearchPrompt(m tui.Message) bool {
+	key, ok := m.(tui.KeyEvent)
+	if !ok {
+		return false
+	}
+	switch key.ControlKey {
+	case tui.ControlKeyEsc:
+		v.EndSearch()
+	case tui.ControlKeyEnter, tui.ControlKeyDown:
+		v.NextMatch()
+	case tui.ControlKeyUp:
+		v.PrevMatch()
+	case tui.ControlKeyBackspace, tui.ControlKeyCtrlH:
+		if v.query != "" {
+			_, size := utf8.DecodeLastRuneInString(v.query)
+			v.SetSearchQuery(v.query[:len(v.query)-size])
+		}
+	case tui.ControlKeyNone:
+		if len(key.Runes) == 0 || key.Alt {
+			return false
+		}
+		v.SetSearchQuery(v.query + strings.ReplaceAll(string(key.Runes), "\n", " "))
+	default:
+		return false
+	}
+	return true
+}
+
+// The updateMatches method recomputes matches of the query in the shown lines, keeping the current match on the same or the next line when possible.
+func (v *View) updateMatches() {
+	prevIdx := -1
+	if v.matchCursor >= 0 && v.matchCursor < len(v.matches) {
+		prevIdx = v.matches[v.matchCursor].idx
+	}
+	v.matches = nil
+	v.matchCursor = -1
+	if v.query == "" {
+		return
+	}
+
+	foldCase := strings.ToLower(v.query) == v.query
+	query := []rune(v.query)
+	if foldCase {
+		query = foldRunes(query)
+	}
+	for idx := 0; idx < v.numShown(); idx++ {
+		text := visibleRunes(v.shownLine(idx))
+		if foldCase {
+			text = foldRunes(text)
+		}
+		for start := 0; start+len(query) <= len(text); {
+			if runesEqual(text[start:start+len(query)], query) {
+				v.matches = append(v.matches, viewMatch{idx: idx, start: start, end: start + len(query)})
+				start += len(query)
+				continue
+			}
+			start++
+		}
+	}
+	if prevIdx >= 0 && len(v.matches) > 0 {
+		v.matchCursor = sort.Search(len(v.matches), func(i int) bool { return v.matches[i].idx >= prevIdx })
+		if v.matchCursor >= len(v.matches) {
+			v.matchCursor = len(v.matches) - 1
+		}
+	}
+}
+
+// The scrollToMatch method scrolls the current match into view, placing it a third of the way down the view if it wasn't visible.
+func (v *View) scrollToMatch() {
+	if v.matchCursor < 0 || v.matchCursor >= len(v.matches) {
+		return
+	}
+	idx := v.matches[v.matchCursor].idx
+	rows := v.contentRows()
+	if idx >= v.offset && idx < v.offset+rows {
+		return
+	}
+	v.offset = max0(idx - rows/3)
+	v.normalizeOffset()
+}
+
+// The highlightShownLine method returns shown line idx with search matches highlighted.
+func (v *View) highlightShownLine(idx int) string {
+	line := v.shownLine(idx)
+	if len(v.matches) == 0 {
+		return line
+	}
+	first := sort.Search(len(v.matches), func(i int) bool { return v.matches[i].idx >= idx })
+	last := first
+	for last < len(v.matches) && v.matches[last].idx == idx {
+		last++
+	}
+	if first == last {
+		return line
+	}
+
+	var b strings.Builder
+	mi := first
+	inMatch := false
+	on, off := "", ""
+	runeIdx := 0
+	for i := 0; i < len(line); {
+		if inMatch && runeIdx == v.matches[mi].end {
+			b.WriteString(off)
+			inMatch = false
+			mi++
+		}
+		if line[i] == '\x1b' {
+			n := ansiSequenceLength(line[i:])
+			b.WriteString(line[i : i+n])
+			i += n
+			if inMatch {
+				// Re-establish the highlight in case the sequence reset it.
+				b.WriteString(on)
+			}
+			continue
+		}
+		if !inMatch && mi < last && runeIdx == v.matches[mi].start {
+			on, off = searchMatchOn, searchMatchOff
+			if mi == v.matchCursor {
+				on, off = searchCurrentMatchOn, searchCurrentMatchOff
+			}
+			b.WriteString(on)
+			inMatch = true
+		}
+		_, size := utf8.DecodeRuneInString(line[i:])
+		b.WriteString(line[i : i+size])
+		i += size
+		runeIdx++
+	}
+	if inMatch {
+		b.WriteString(off)
+	}
+	return b.String()
+}
+
+// The renderSearchPrompt method renders the search prompt row: the query and the match position.
+func (v *View) renderSearchPrompt() string {
+	status := "no matches"
+	if v.query == "" {
+		status = "type to search"
+	} else if len(v.matches) > 0 {
+		status = fmt.Sprintf("%d/%d", v.CurrentMatch(), len(v.matches))
+	}
+	text := clipLine(termformat.Sanitize("/"+v.query, 4)+"  ["+status+"]", v.width)
+
+	if v.emptyLineBackgroundColor == nil {
+		return text
+	}
+	bg := v.emptyLineBackgroundColor.ANSISequence(true)
+	if bg == "" {
+		return text
+	}
+	pad := v.width - termformat.TextWidthWithANSICodes(text)
+	return bg + text + strings.Repeat(" ", max0(pad)) + termformat.ANSIReset
+}
+
+// visibleRunes returns the runes of line with ANSI escape sequences removed.
+func visibleRunes(line string) []rune {
+	runes := make([]rune, 0, len(line))
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			i += ansiSequenceLength(line[i:])
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(line[i:])
+		runes = append(runes, r)
+		i += size
+	}
+	return runes
+}
+
+// ansiSequenceLength returns the byte length of the ANSI escape sequence at the start of s, which must start with ESC. Incomplete or unrecognized sequences count
+// as just the ESC byte.
+func ansiSequenceLength(s string) int {
+	if len(s) < 2 {
+		return 1
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']', 'P', '^', '_':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' && s[1] == ']' {
+				return i + 1
+			}
+			if s[i] == '\\' && s[i-1] == '\x1b' {
+				return i + 1
+			}
+		}
+	default:
+		return 2
+	}
+	return 1
+}
+
+func foldRunes(runes []rune) []rune {
+	out := make([]rune, len(runes))
+	for i, r := range runes {
+		out[i] = unicode.ToLower(r)
+	}
+	return out
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
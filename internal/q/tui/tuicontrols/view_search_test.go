@@ -0,0 +1,88 @@
+package tuicontrols
+
+import (
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/q/termformat"
+	"github.com/codalotl/codalotl/internal/q/tui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestView_SearchHighlightsAndNavigates(t *testing.T) {
+	v := NewView(30, 3)
+	v.SetContent("alpha\nbeta\ngamma\ndelta\nAlpha beta")
+
+	v.SetSearchQuery("alpha")
+	require.Equal(t, 2, v.MatchCount())
+	require.Equal(t, 1, v.CurrentMatch())
+	require.Equal(t, searchCurrentMatchOn+"alpha"+searchCurrentMatchOff+"\nbeta\ngamma", v.View())
+
+	require.True(t, v.NextMatch())
+	require.Equal(t, 2, v.CurrentMatch())
+	require.True(t, v.AtBottom())
+	require.Equal(t, "gamma\ndelta\n"+searchCurrentMatchOn+"Alpha"+searchCurrentMatchOff+" beta", v.View())
+
+	require.True(t, v.NextMatch())
+	require.Equal(t, 1, v.CurrentMatch())
+	require.Equal(t, searchCurrentMatchOn+"alpha"+searchCurrentMatchOff+"\nbeta\ngamma", v.View())
+	require.True(t, v.PrevMatch())
+	require.Equal(t, 2, v.CurrentMatch())
+
+	// An upper-case letter makes the search case-sensitive.
+	v.SetSearchQuery("Alpha")
+	require.Equal(t, 1, v.MatchCount())
+
+	v.SetSearchQuery("zeta")
+	require.Equal(t, 0, v.MatchCount())
+	require.Equal(t, 0, v.CurrentMatch())
+	require.False(t, v.NextMatch())
+}
+
+func TestView_SearchIgnoresANSICodes(t *testing.T) {
+	red := termformat.ANSIRed.ANSISequence(false)
+	v := NewView(30, 1)
+	v.SetContent("x" + red + "ab" + termformat.ANSIReset + "cd")
+
+	v.SetSearchQuery("bc")
+	require.Equal(t, 1, v.MatchCount())
+	want := "x" + red + "a" + searchCurrentMatchOn + "b" + termformat.ANSIReset + searchCurrentMatchOn + "c" + searchCurrentMatchOff + "d"
+	require.Equal(t, want, v.View())
+}
+
+func TestView_SearchPrompt(t *testing.T) {
+	v := NewView(30, 3)
+	v.SetContent("one\ntwo\nthree\nfour\nfive")
+
+	v.Update(nil, tui.KeyEvent{ControlKey: tui.ControlKeyNone, Runes: []rune{'/'}})
+	require.True(t, v.Searching())
+	require.Equal(t, "one\ntwo\n/  [type to search]", v.View())
+
+	for _, r := range "fiv" {
+		v.Update(nil, tui.KeyEvent{ControlKey: tui.ControlKeyNone, Runes: []rune{r}})
+	}
+	require.Equal(t, "fiv", v.SearchQuery())
+	require.Equal(t, "four\n"+searchCurrentMatchOn+"fiv"+searchCurrentMatchOff+"e\n/fiv  [1/1]", v.View())
+
+	v.Update(nil, tui.KeyEvent{ControlKey: tui.ControlKeyBackspace})
+	v.Update(nil, tui.KeyEvent{ControlKey: tui.ControlKeyBackspace})
+	require.Equal(t, "f", v.SearchQuery())
+	require.Equal(t, 2, v.MatchCount())
+
+	// Page keys still scroll while the prompt is open.
+	v.Update(nil, tui.KeyEvent{ControlKey: tui.ControlKeyPageUp})
+	require.Equal(t, 2, v.Offset())
+
+	v.Update(nil, tui.KeyEvent{ControlKey: tui.ControlKeyEsc})
+	require.False(t, v.Searching())
+	require.Equal(t, "", v.SearchQuery())
+	require.Equal(t, "three\nfour\nfive", v.View())
+}
+
+func TestView_SearchFollowsFilter(t *testing.T) {
+	v := newSectionedView()
+	v.SetSearchQuery("body")
+	require.Equal(t, 4, v.MatchCount())
+
+	v.SetFilter(Filter{Collapse: []string{"tool"}})
+	require.Equal(t, 0, v.MatchCount())
+}
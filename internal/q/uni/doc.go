@@ -1,5 +1,5 @@
 // Package uni provides Unicode helpers for terminal text layout and text segmentation.
 //
-// It measures monospace terminal display widths for strings, byte slices, and runes, and it iterates grapheme clusters with byte offsets. Width calculations default
-// to non-East Asian rules when no Options value is provided.
+// It measures monospace terminal display widths for strings, byte slices, and runes, and it iterates grapheme clusters, UAX #29 words and sentences, and UAX #14
+// line segments with byte offsets. Width calculations default to non-East Asian rules when no Options value is provided.
 package uni
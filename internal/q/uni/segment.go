@@ -0,0 +1,133 @@
+package uni
+
+import (
+	"github.com/clipperhouse/uax29/v2/sentences"
+	"github.com/clipperhouse/uax29/v2/words"
+	"github.com/rivo/uniseg"
+)
+
+// NewWordIterator returns a new word iterator for str (string or []byte), segmenting by UAX #29 word boundaries. Every byte of str belongs to some segment, so whitespace
+// and punctuation between words are returned as their own segments. If opts is nil, locale is assumed to be non-East Asian.
+func NewWordIterator[T string | []byte](str T, opts *Options) *Iterator[T] {
+	return &Iterator[T]{
+		iter: newWordIterator(str),
+		cond: conditionFromOptions(opts),
+	}
+}
+
+// NewSentenceIterator returns a new sentence iterator for str (string or []byte), segmenting by UAX #29 sentence boundaries. A sentence segment includes its trailing
+// whitespace. If opts is nil, locale is assumed to be non-East Asian.
+func NewSentenceIterator[T string | []byte](str T, opts *Options) *Iterator[T] {
+	return &Iterator[T]{
+		iter: newSentenceIterator(str),
+		cond: conditionFromOptions(opts),
+	}
+}
+
+// NewLineIterator returns a new line segment iterator for str (string or []byte). Each segment ends at a UAX #14 line break opportunity: a line may be broken
+// after any segment, and must be broken after a segment for which MustBreak returns true. Segments include trailing whitespace and never split grapheme clusters.
+// If opts is nil, locale is assumed to be non-East Asian.
+//
+// Example: "Hello, world. 你好" is segmented as "Hello, ", "world. ", "你", "好".
+func NewLineIterator[T string | []byte](str T, opts *Options) *Iterator[T] {
+	return &Iterator[T]{
+		iter: &lineIterator[T]{data: str, state: -1},
+		cond: conditionFromOptions(opts),
+	}
+}
+
+func newWordIterator[T string | []byte](text T) segmenter[T] {
+	switch v := any(text).(type) {
+	case string:
+		iter := words.FromString(v)
+		return any(&iter).(segmenter[T])
+	case []byte:
+		iter := words.FromBytes(v)
+		return any(&iter).(segmenter[T])
+	default:
+		panic("unsupported type")
+	}
+}
+
+func newSentenceIterator[T string | []byte](text T) segmenter[T] {
+	switch v := any(text).(type) {
+	case string:
+		iter := sentences.FromString(v)
+		return any(&iter).(segmenter[T])
+	case []byte:
+		iter := sentences.FromBytes(v)
+		return any(&iter).(segmenter[T])
+	default:
+		panic("unsupported type")
+	}
+}
+
+// lineIterator segments data at UAX #14 line break opportunities.
+type lineIterator[T string | []byte] struct {
+	data      T    // data is the text being segmented.
+	state     int  // state is the uniseg state carried between grapheme clusters; -1 before the first call.
+	start     int  // start is the byte offset of the current segment.
+	end       int  // end is the byte offset after the current segment.
+	mustBreak bool // mustBreak is true when the current segment ends with a mandatory break.
+}
+
+func (iter *lineIterator[T]) Next() bool {
+	if iter.end >= len(iter.data) {
+		return false
+	}
+	iter.start = iter.end
+	pos := iter.start
+	for pos < len(iter.data) {
+		n, boundaries, state := step(iter.data[pos:], iter.state)
+		iter.state = state
+		pos += n
+		if pos >= len(iter.data) {
+			// UAX #14 LB3 always breaks at the end of text; only report a real hard line break.
+			iter.mustBreak = hasTrailingLineBreak(iter.data[iter.start:pos])
+			break
+		}
+		if line := boundaries & uniseg.MaskLine; line != uniseg.LineDontBreak {
+			iter.mustBreak = line == uniseg.LineMustBreak
+			break
+		}
+	}
+	iter.end = pos
+	return true
+}
+
+func (iter *lineIterator[T]) Value() T {
+	return iter.data[iter.start:iter.end]
+}
+
+func (iter *lineIterator[T]) Start() int {
+	return iter.start
+}
+
+func (iter *lineIterator[T]) End() int {
+	return iter.end
+}
+
+// step returns the byte length of the first grapheme cluster in text, the boundary information after it, and the new state (see uniseg.Step).
+func step[T string | []byte](text T, state int) (int, int, int) {
+	switch v := any(text).(type) {
+	case string:
+		cluster, _, boundaries, newState := uniseg.StepString(v, state)
+		return len(cluster), boundaries, newState
+	case []byte:
+		cluster, _, boundaries, newState := uniseg.Step(v, state)
+		return len(cluster), boundaries, newState
+	default:
+		panic("unsupported type")
+	}
+}
+
+func hasTrailingLineBreak[T string | []byte](text T) bool {
+	switch v := any(text).(type) {
+	case string:
+		return uniseg.HasTrailingLineBreakInString(v)
+	case []byte:
+		return uniseg.HasTrailingLineBreak(v)
+	default:
+		panic("unsupported type")
+	}
+}
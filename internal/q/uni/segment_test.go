@@ -0,0 +1,82 @@
+package uni
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectSegments[T string | []byte](iter *Iterator[T]) []string {
+	var out []string
+	for iter.Next() {
+		out = append(out, string(iter.Value()))
+	}
+	return out
+}
+
+func TestNewWordIterator(t *testing.T) {
+	text := "Hello, world. 你好 can't 3.14"
+	expected := []string{"Hello", ",", " ", "world", ".", " ", "你", "好", " ", "can't", " ", "3.14"}
+	assert.Equal(t, expected, collectSegments(NewWordIterator(text, nil)))
+	assert.Equal(t, expected, collectSegments(NewWordIterator([]byte(text), nil)))
+
+	iter := NewWordIterator("ab 世界", nil)
+	var widths []int
+	for iter.Next() {
+		assert.Equal(t, iter.Value(), "ab 世界"[iter.Start():iter.End()])
+		widths = append(widths, iter.TextWidth())
+	}
+	assert.Equal(t, []int{2, 1, 2, 2}, widths)
+}
+
+func TestNewSentenceIterator(t *testing.T) {
+	text := "Pi is 3.14. Did he? 你好。再见！"
+	expected := []string{"Pi is 3.14. ", "Did he? ", "你好。", "再见！"}
+	assert.Equal(t, expected, collectSegments(NewSentenceIterator(text, nil)))
+	assert.Equal(t, expected, collectSegments(NewSentenceIterator([]byte(text), nil)))
+
+	// Abbreviations aren't tailored.
+	text = "Mr. Smith left."
+	expected = []string{"Mr. ", "Smith left."}
+	assert.Equal(t, expected, collectSegments(NewSentenceIterator(text, nil)))
+	assert.Equal(t, expected, collectSegments(NewSentenceIterator([]byte(text), nil)))
+}
+
+func TestNewLineIterator(t *testing.T) {
+	tests := []struct {
+		text      string
+		segments  []string
+		mustBreak []bool
+	}{
+		{text: "", segments: nil, mustBreak: nil},
+		{text: "Hello, world. 你好", segments: []string{"Hello, ", "world. ", "你", "好"}, mustBreak: []bool{false, false, false, false}},
+		// No break before closing CJK punctuation.
+		{text: "你好。再见", segments: []string{"你", "好。", "再", "见"}, mustBreak: []bool{false, false, false, false}},
+		{text: "a\nb\n", segments: []string{"a\n", "b\n"}, mustBreak: []bool{true, true}},
+		// Emoji ZWJ sequences and combining marks stay whole.
+		{text: "👩‍👩‍👧x a̐b", segments: []string{"👩‍👩‍👧", "x ", "a̐b"}, mustBreak: []bool{false, false, false}},
+		{text: "well-known", segments: []string{"well-", "known"}, mustBreak: []bool{false, false}},
+	}
+	for _, tt := range tests {
+		iter := NewLineIterator(tt.text, nil)
+		var segments []string
+		var mustBreak []bool
+		end := 0
+		for iter.Next() {
+			assert.Equal(t, end, iter.Start())
+			end = iter.End()
+			segments = append(segments, iter.Value())
+			mustBreak = append(mustBreak, iter.MustBreak())
+		}
+		assert.Equal(t, len(tt.text), end, tt.text)
+		assert.Equal(t, tt.segments, segments, tt.text)
+		assert.Equal(t, tt.mustBreak, mustBreak, tt.text)
+		assert.Equal(t, tt.segments, collectSegments(NewLineIterator([]byte(tt.text), nil)), tt.text)
+	}
+
+	// MustBreak is only meaningful for line iterators.
+	iter := NewGraphemeIterator("a\n", nil)
+	for iter.Next() {
+		assert.False(t, iter.MustBreak())
+	}
+}
@@ -25,10 +25,18 @@ func RuneWidth(r rune, opts *Options) int {
 	return cond.RuneWidth(rune(r))
 }
 
-// Iterator iterates over grapheme clusters.
+// Iterator iterates over text segments: grapheme clusters, words, sentences, or line segments, depending on how it was constructed.
 type Iterator[T string | []byte] struct {
-	iter *graphemes.Iterator[T] // This is the underlying grapheme segmentation iterator.
-	cond *runewidth.Condition   // This controls text width calculations for the iterator's current value.
+	iter segmenter[T]         // This is the underlying segmentation iterator.
+	cond *runewidth.Condition // This controls text width calculations for the iterator's current value.
+}
+
+// segmenter is the common shape of the underlying segmentation iterators.
+type segmenter[T string | []byte] interface {
+	Next() bool
+	Value() T
+	Start() int
+	End() int
 }
 
 // NewGraphemeIterator returns a new grapheme iterator for str (string or []byte). If opts is nil, locale is assumed to be non-East Asian.
@@ -40,12 +48,12 @@ func NewGraphemeIterator[T string | []byte](str T, opts *Options) *Iterator[T] {
 	}
 }
 
-// Next advances the iterator to the next grapheme cluster. It returns false when iteration is complete.
+// Next advances the iterator to the next segment. It returns false when iteration is complete.
 func (iter *Iterator[T]) Next() bool {
 	return iter.iter.Next()
 }
 
-// Value returns the current grapheme cluster. Call it only after Next returns true.
+// Value returns the current segment. Call it only after Next returns true.
 func (iter *Iterator[T]) Value() T {
 	return iter.iter.Value()
 }
@@ -65,6 +73,14 @@ func (iter *Iterator[T]) TextWidth() int {
 	return textWidth(iter.iter.Value(), iter.cond)
 }
 
+// MustBreak reports whether the current segment ends with a mandatory line break (ex: "\n"). It is always false for iterators not made by NewLineIterator.
+func (iter *Iterator[T]) MustBreak() bool {
+	if li, ok := iter.iter.(*lineIterator[T]); ok {
+		return li.mustBreak
+	}
+	return false
+}
+
 func conditionFromOptions(opts *Options) *runewidth.Condition {
 	cond := runewidth.NewCondition()
 	cond.EastAsianWidth = false
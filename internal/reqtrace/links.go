@@ -0,0 +1,197 @@
+package reqtrace
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LinkKind is the kind of code artifact a requirement is traced to.
+type LinkKind string
+
+const (
+	LinkKindSpecSection LinkKind = "spec-section" // LinkKindSpecSection links to a section of a package's SPEC.md; Link.Target is its heading path.
+	LinkKindTest        LinkKind = "test"         // LinkKindTest links to a Test function; Link.Target is its name.
+)
+
+// LinkSource is how a Link was established.
+type LinkSource string
+
+const (
+	LinkSourceAnnotation LinkSource = "annotation" // LinkSourceAnnotation links come from "traces:" annotations in SPEC.md or tests.
+	LinkSourceLLM        LinkSource = "llm"        // LinkSourceLLM links come from MatchPackage.
+)
+
+// Link traces a requirement to a SPEC.md section or test function in a package.
+type Link struct {
+	RequirementID string
+	Kind          LinkKind
+	PackageDir    string     // PackageDir is the absolute package directory.
+	Target        string     // Target is the SPEC.md heading path (joined by " > ") or the Test function name.
+	File          string     // File is the name of the file containing Target, within PackageDir (ex: "SPEC.md", "foo_test.go").
+	Line          int        // Line is the 1-based line of the annotation, or of Target for LLM links.
+	Source        LinkSource // Source is how the link was established.
+}
+
+// tracesRE matches a "traces:" annotation and captures its comma- or space-separated IDs.
+var tracesRE = regexp.MustCompile(`(?i)^\s*traces:\s*(.*?)\s*$`)
+
+// specTracesRE matches a "<!-- traces: ... -->" annotation in SPEC.md.
+var specTracesRE = regexp.MustCompile(`<!--\s*(?i:traces):\s*(.*?)\s*-->`)
+
+// inlineCodeRE matches a markdown inline code span, so annotations quoted in prose aren't treated as annotations.
+var inlineCodeRE = regexp.MustCompile("`+[^`]*`+")
+
+// ScanPackage returns the annotation links in the package at pkgDir:
+//   - In SPEC.md, `<!-- traces: CAS-1, CAS-2 -->` links the requirements to the enclosing section (the nearest preceding heading and its parents). Annotations in
+//     code blocks or inline code are ignored.
+//   - In _test.go files, a `// Traces: CAS-1, CAS-2` line in the doc comment of a Test function links the requirements to that test.
+//
+// Links are sorted by file, line, and requirement ID. IDs are not checked against any requirements, but malformed IDs are an error. A package without SPEC.md or
+// tests has no links.
+func ScanPackage(pkgDir string) ([]Link, error) {
+	var links []Link
+
+	specPath := filepath.Join(pkgDir, "SPEC.md")
+	specBody, err := os.ReadFile(specPath)
+	if err == nil {
+		specLinks, err := scanSpecAnnotations(pkgDir, specBody)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, specLinks...)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reqtrace: %w", err)
+	}
+
+	testFiles, err := filepath.Glob(filepath.Join(pkgDir, "*_test.go"))
+	if err != nil {
+		return nil, fmt.Errorf("reqtrace: %w", err)
+	}
+	sort.Strings(testFiles)
+	fset := token.NewFileSet()
+	for _, path := range testFiles {
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("reqtrace: %w", err)
+		}
+		for _, fn := range testFuncs(f) {
+			if fn.Doc == nil {
+				continue
+			}
+			for _, c := range fn.Doc.List {
+				m := tracesRE.FindStringSubmatch(commentText(c.Text))
+				if m == nil {
+					continue
+				}
+				line := fset.Position(c.Pos()).Line
+				ids, err := parseTracedIDs(m[1])
+				if err != nil {
+					return nil, fmt.Errorf("reqtrace: %s:%d: %w", path, line, err)
+				}
+				for _, id := range ids {
+					links = append(links, Link{
+						RequirementID: id,
+						Kind:          LinkKindTest,
+						PackageDir:    pkgDir,
+						Target:        fn.Name.Name,
+						File:          filepath.Base(path),
+						Line:          line,
+						Source:        LinkSourceAnnotation,
+					})
+				}
+			}
+		}
+	}
+
+	sortLinks(links)
+	return links, nil
+}
+
+// scanSpecAnnotations returns the links of the traces annotations in specBody, the SPEC.md of the package at pkgDir.
+func scanSpecAnnotations(pkgDir string, specBody []byte) ([]Link, error) {
+	var links []Link
+	err := walkMarkdown(specBody, func(lineNum int, text string, headings []string, _ bool) error {
+		for _, m := range specTracesRE.FindAllStringSubmatch(inlineCodeRE.ReplaceAllString(text, ""), -1) {
+			ids, err := parseTracedIDs(m[1])
+			if err != nil {
+				return fmt.Errorf("reqtrace: %s:%d: %w", filepath.Join(pkgDir, "SPEC.md"), lineNum, err)
+			}
+			for _, id := range ids {
+				links = append(links, Link{
+					RequirementID: id,
+					Kind:          LinkKindSpecSection,
+					PackageDir:    pkgDir,
+					Target:        sectionPath(headings),
+					File:          "SPEC.md",
+					Line:          lineNum,
+					Source:        LinkSourceAnnotation,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// testFuncs returns the top-level Test functions declared in f.
+func testFuncs(f *ast.File) []*ast.FuncDecl {
+	var fns []*ast.FuncDecl
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "Test") {
+			continue
+		}
+		fns = append(fns, fn)
+	}
+	return fns
+}
+
+// commentText returns the text of a single comment, without its // or /* */ markers.
+func commentText(c string) string {
+	if strings.HasPrefix(c, "//") {
+		return c[2:]
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(c, "/*"), "*/")
+}
+
+// parseTracedIDs splits the IDs of a traces annotation, separated by commas and/or spaces.
+func parseTracedIDs(s string) ([]string, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	if len(fields) == 0 {
+		return nil, errors.New("traces annotation has no requirement IDs")
+	}
+	for _, id := range fields {
+		if !ValidRequirementID(id) {
+			return nil, fmt.Errorf("invalid requirement ID %q in traces annotation", id)
+		}
+	}
+	return fields, nil
+}
+
+// sortLinks sorts links by package, file, line, and requirement ID.
+func sortLinks(links []Link) {
+	sort.SliceStable(links, func(i, j int) bool {
+		a, b := links[i], links[j]
+		if a.PackageDir != b.PackageDir {
+			return a.PackageDir < b.PackageDir
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.RequirementID < b.RequirementID
+	})
+}
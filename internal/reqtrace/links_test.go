@@ -0,0 +1,102 @@
+package reqtrace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTracePackage writes a package with a SPEC.md and a test file into a temp dir and returns the dir.
+func writeTracePackage(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	spec := strings.Join([]string{
+		"# mypkg",
+		"",
+		"## Hashing",
+		"",
+		"<!-- traces: CAS-1, CAS-2 -->",
+		"Packages are hashed by their source files.",
+		"",
+		"### Modes",
+		"",
+		"Test files may be excluded. <!-- traces: CAS-3 -->",
+		"",
+		"```",
+		"<!-- traces: CAS-99 -->",
+		"```",
+		"Annotate with `<!-- traces: CAS-98 -->`.",
+		"",
+		"## Public API",
+		"",
+		"```go {api}",
+		"func Hash() {",
+		"}",
+		"```",
+	}, "\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SPEC.md"), []byte(spec), 0o644))
+	test := strings.Join([]string{
+		"package mypkg",
+		"",
+		"import \"testing\"",
+		"",
+		"// TestHash checks hashing.",
+		"//",
+		"// Traces: CAS-1 CAS-4",
+		"func TestHash(t *testing.T) {}",
+		"",
+		"// Traces: CAS-5",
+		"func helper() {}",
+		"",
+		"func TestOther(t *testing.T) {}",
+	}, "\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mypkg_test.go"), []byte(test), 0o644))
+	return dir
+}
+
+func TestScanPackage(t *testing.T) {
+	dir := writeTracePackage(t)
+
+	links, err := ScanPackage(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []Link{
+		{RequirementID: "CAS-1", Kind: LinkKindSpecSection, PackageDir: dir, Target: "mypkg > Hashing", File: "SPEC.md", Line: 5, Source: LinkSourceAnnotation},
+		{RequirementID: "CAS-2", Kind: LinkKindSpecSection, PackageDir: dir, Target: "mypkg > Hashing", File: "SPEC.md", Line: 5, Source: LinkSourceAnnotation},
+		{RequirementID: "CAS-3", Kind: LinkKindSpecSection, PackageDir: dir, Target: "mypkg > Hashing > Modes", File: "SPEC.md", Line: 10, Source: LinkSourceAnnotation},
+		{RequirementID: "CAS-1", Kind: LinkKindTest, PackageDir: dir, Target: "TestHash", File: "mypkg_test.go", Line: 7, Source: LinkSourceAnnotation},
+		{RequirementID: "CAS-4", Kind: LinkKindTest, PackageDir: dir, Target: "TestHash", File: "mypkg_test.go", Line: 7, Source: LinkSourceAnnotation},
+	}, links)
+}
+
+func TestScanPackageInvalidID(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SPEC.md"), []byte("# x\n<!-- traces: cas-1 -->\n"), 0o644))
+
+	_, err := ScanPackage(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid requirement ID "cas-1"`)
+}
+
+func TestScanPackageEmpty(t *testing.T) {
+	links, err := ScanPackage(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, links)
+}
+
+func TestBuildReport(t *testing.T) {
+	reqs := []Requirement{{ID: "CAS-1"}, {ID: "CAS-2"}}
+	annotated := Link{RequirementID: "CAS-1", Kind: LinkKindTest, PackageDir: "/p", Target: "TestHash", File: "p_test.go", Line: 7, Source: LinkSourceAnnotation}
+	llmDup := Link{RequirementID: "CAS-1", Kind: LinkKindTest, PackageDir: "/p", Target: "TestHash", File: "p_test.go", Line: 8, Source: LinkSourceLLM}
+	unknown := Link{RequirementID: "CAS-9", Kind: LinkKindSpecSection, PackageDir: "/p", Target: "p", File: "SPEC.md", Line: 1, Source: LinkSourceAnnotation}
+
+	report := BuildReport(reqs, []Link{llmDup, unknown, annotated, annotated})
+	require.Len(t, report.Traces, 2)
+	assert.Equal(t, []Link{annotated}, report.Traces[0].Links)
+	assert.Empty(t, report.Traces[1].Links)
+	assert.Equal(t, []Link{unknown}, report.Unknown)
+	assert.Equal(t, []Requirement{{ID: "CAS-2"}}, report.Untraced())
+}
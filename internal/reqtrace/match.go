@@ -0,0 +1,233 @@
+package reqtrace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	"github.com/codalotl/codalotl/internal/llmstream"
+)
+
+// MatchOptions configures MatchPackage.
+type MatchOptions struct {
+	Completer llmstream.Completer // Completer sends the matching prompt. If nil, llmstream.NewCompleter() is used.
+	Model     llmmodel.ModelID    // Model is the LLM to use. If empty, the default model is used.
+}
+
+// maxSectionExcerpt caps how much of each SPEC.md section's prose is sent to the LLM.
+const maxSectionExcerpt = 600
+
+// matchCandidate is a SPEC.md section or Test function that requirements can be matched to.
+type matchCandidate struct {
+	kind    LinkKind
+	target  string
+	file    string
+	line    int
+	excerpt string // excerpt is the section's prose or the test's doc comment, possibly truncated.
+}
+
+// MatchPackage asks an LLM which of reqs are specified or tested by the package at pkgDir, by matching them against its SPEC.md sections (excluding Public API
+// sections) and Test functions. The returned links have LinkSourceLLM; matches to requirements or targets that don't exist are dropped.
+//
+// It returns nil without calling the LLM if reqs is empty or the package has no SPEC.md sections or Test functions. An error is returned if the package can't be
+// read, the LLM call fails, or the response isn't valid JSON.
+func MatchPackage(ctx context.Context, pkgDir string, reqs []Requirement, options MatchOptions) ([]Link, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	candidates, err := matchCandidates(pkgDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	completer := options.Completer
+	if completer == nil {
+		completer = llmstream.NewCompleter()
+	}
+	turn, err := completer.Complete(ctx, llmmodel.ModelIDOrFallback(options.Model), matchSystemPrompt, matchUserMessage(pkgDir, reqs, candidates))
+	if err != nil {
+		return nil, fmt.Errorf("reqtrace: match %s: %w", pkgDir, err)
+	}
+
+	var response struct {
+		Links []struct {
+			Requirement string `json:"requirement"`
+			Kind        string `json:"kind"`
+			Target      string `json:"target"`
+		} `json:"links"`
+	}
+	text := turn.TextContent()
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("reqtrace: match %s: response is not JSON", pkgDir)
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), &response); err != nil {
+		return nil, fmt.Errorf("reqtrace: match %s: %w", pkgDir, err)
+	}
+
+	known := make(map[string]bool, len(reqs))
+	for _, r := range reqs {
+		known[r.ID] = true
+	}
+	byTarget := make(map[string]matchCandidate, len(candidates))
+	for _, c := range candidates {
+		byTarget[string(c.kind)+"\x00"+c.target] = c
+	}
+	var links []Link
+	seen := map[string]bool{}
+	for _, l := range response.Links {
+		c, ok := byTarget[l.Kind+"\x00"+l.Target]
+		if !ok || !known[l.Requirement] {
+			continue
+		}
+		key := l.Requirement + "\x00" + l.Kind + "\x00" + l.Target
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		links = append(links, Link{
+			RequirementID: l.Requirement,
+			Kind:          c.kind,
+			PackageDir:    pkgDir,
+			Target:        c.target,
+			File:          c.file,
+			Line:          c.line,
+			Source:        LinkSourceLLM,
+		})
+	}
+	sortLinks(links)
+	return links, nil
+}
+
+const matchSystemPrompt = `You trace product requirements to the Go package that implements them.
+
+You are given product requirements (each with an ID), and the sections of one package's SPEC.md and its Test functions. For each requirement, decide whether a SPEC.md section specifies behavior that implements it, and whether a Test function tests it. Only match when the section or test is specifically about the requirement; most requirements will not match anything in a given package.
+
+Respond with only a JSON object, without commentary:
+{"links": [{"requirement": "<ID>", "kind": "spec-section" or "test", "target": "<section heading path or Test function name, exactly as given>"}]}
+
+Respond with {"links": []} if nothing matches.`
+
+// matchUserMessage builds the MatchPackage user message for matching reqs to candidates of the package at pkgDir.
+func matchUserMessage(pkgDir string, reqs []Requirement, candidates []matchCandidate) string {
+	var b strings.Builder
+	b.WriteString("Requirements:\n")
+	for _, r := range reqs {
+		fmt.Fprintf(&b, "- %s", r.ID)
+		if r.Section != "" {
+			fmt.Fprintf(&b, " (%s)", r.Section)
+		}
+		fmt.Fprintf(&b, ": %s\n", r.Text)
+	}
+	fmt.Fprintf(&b, "\nPackage directory: %s\n", filepath.Base(pkgDir))
+	for _, kind := range []LinkKind{LinkKindSpecSection, LinkKindTest} {
+		if kind == LinkKindSpecSection {
+			b.WriteString("\nSPEC.md sections (kind \"spec-section\"):\n")
+		} else {
+			b.WriteString("\nTest functions (kind \"test\"):\n")
+		}
+		for _, c := range candidates {
+			if c.kind != kind {
+				continue
+			}
+			fmt.Fprintf(&b, "- target %q", c.target)
+			if c.excerpt != "" {
+				fmt.Fprintf(&b, ": %s", c.excerpt)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// matchCandidates returns the SPEC.md sections (excluding Public API sections and sections without prose) and Test functions of the package at pkgDir.
+func matchCandidates(pkgDir string) ([]matchCandidate, error) {
+	var candidates []matchCandidate
+
+	specBody, err := os.ReadFile(filepath.Join(pkgDir, "SPEC.md"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reqtrace: %w", err)
+	}
+	if err == nil {
+		var current *matchCandidate
+		flush := func() {
+			if current != nil && current.excerpt != "" {
+				candidates = append(candidates, *current)
+			}
+			current = nil
+		}
+		err := walkMarkdown(specBody, func(lineNum int, text string, headings []string, isHeading bool) error {
+			if isHeading {
+				flush()
+				path := sectionPath(headings)
+				if strings.Contains(strings.ToLower(path), "public api") {
+					return nil
+				}
+				current = &matchCandidate{kind: LinkKindSpecSection, target: path, file: "SPEC.md", line: lineNum}
+				return nil
+			}
+			if current == nil || len(current.excerpt) >= maxSectionExcerpt {
+				return nil
+			}
+			text = strings.TrimSpace(text)
+			if text == "" || strings.HasPrefix(text, "<!--") {
+				return nil
+			}
+			if current.excerpt != "" {
+				current.excerpt += " "
+			}
+			current.excerpt += text
+			if len(current.excerpt) > maxSectionExcerpt {
+				cut := maxSectionExcerpt
+				for cut > 0 && !utf8.RuneStart(current.excerpt[cut]) {
+					cut--
+				}
+				current.excerpt = current.excerpt[:cut] + "..."
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		flush()
+	}
+
+	testFiles, err := filepath.Glob(filepath.Join(pkgDir, "*_test.go"))
+	if err != nil {
+		return nil, fmt.Errorf("reqtrace: %w", err)
+	}
+	sort.Strings(testFiles)
+	fset := token.NewFileSet()
+	for _, path := range testFiles {
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("reqtrace: %w", err)
+		}
+		for _, fn := range testFuncs(f) {
+			excerpt := ""
+			if fn.Doc != nil {
+				excerpt, _, _ = strings.Cut(strings.TrimSpace(fn.Doc.Text()), "\n")
+			}
+			candidates = append(candidates, matchCandidate{
+				kind:    LinkKindTest,
+				target:  fn.Name.Name,
+				file:    filepath.Base(path),
+				line:    fset.Position(fn.Pos()).Line,
+				excerpt: excerpt,
+			})
+		}
+	}
+	return candidates, nil
+}
@@ -0,0 +1,104 @@
+package reqtrace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	"github.com/codalotl/codalotl/internal/llmstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCompleter returns a canned response and records the user message it was sent.
+type fakeCompleter struct {
+	response    string
+	err         error
+	calls       int
+	userMessage string
+}
+
+// Complete implements llmstream.Completer.
+func (f *fakeCompleter) Complete(_ context.Context, _ llmmodel.ModelID, _, userMessage string, _ ...llmstream.SendOptions) (llmstream.Turn, error) {
+	f.calls++
+	f.userMessage = userMessage
+	if f.err != nil {
+		return llmstream.Turn{}, f.err
+	}
+	return llmstream.Turn{
+		Role:         llmstream.RoleAssistant,
+		Parts:        []llmstream.ContentPart{llmstream.TextContent{Content: f.response}},
+		FinishReason: llmstream.FinishReasonEndTurn,
+	}, nil
+}
+
+func TestMatchPackage(t *testing.T) {
+	dir := writeTracePackage(t)
+	reqs := []Requirement{{ID: "CAS-1", Text: "Hash packages.", Section: "CAS"}, {ID: "CAS-2", Text: "Exclude tests."}}
+	completer := &fakeCompleter{response: "Here you go:\n" + `{"links": [
+		{"requirement": "CAS-2", "kind": "spec-section", "target": "mypkg > Hashing > Modes"},
+		{"requirement": "CAS-1", "kind": "test", "target": "TestOther"},
+		{"requirement": "CAS-1", "kind": "test", "target": "TestOther"},
+		{"requirement": "CAS-9", "kind": "test", "target": "TestHash"},
+		{"requirement": "CAS-1", "kind": "test", "target": "TestMissing"},
+		{"requirement": "CAS-1", "kind": "spec-section", "target": "mypkg > Public API"}
+	]}`}
+
+	links, err := MatchPackage(context.Background(), dir, reqs, MatchOptions{Completer: completer})
+	require.NoError(t, err)
+	assert.Equal(t, []Link{
+		{RequirementID: "CAS-2", Kind: LinkKindSpecSection, PackageDir: dir, Target: "mypkg > Hashing > Modes", File: "SPEC.md", Line: 8, Source: LinkSourceLLM},
+		{RequirementID: "CAS-1", Kind: LinkKindTest, PackageDir: dir, Target: "TestOther", File: "mypkg_test.go", Line: 13, Source: LinkSourceLLM},
+	}, links)
+
+	assert.Equal(t, 1, completer.calls)
+	assert.Contains(t, completer.userMessage, "- CAS-1 (CAS): Hash packages.")
+	assert.Contains(t, completer.userMessage, `- target "mypkg > Hashing": Packages are hashed by their source files.`)
+	assert.Contains(t, completer.userMessage, `- target "TestHash": TestHash checks hashing.`)
+	assert.NotContains(t, completer.userMessage, "Public API")
+}
+
+func TestMatchPackageSkipsLLM(t *testing.T) {
+	completer := &fakeCompleter{response: `{"links": []}`}
+
+	links, err := MatchPackage(context.Background(), writeTracePackage(t), nil, MatchOptions{Completer: completer})
+	require.NoError(t, err)
+	assert.Nil(t, links)
+
+	links, err = MatchPackage(context.Background(), t.TempDir(), []Requirement{{ID: "CAS-1"}}, MatchOptions{Completer: completer})
+	require.NoError(t, err)
+	assert.Nil(t, links)
+	assert.Equal(t, 0, completer.calls)
+}
+
+func TestMatchPackageErrors(t *testing.T) {
+	dir := writeTracePackage(t)
+	reqs := []Requirement{{ID: "CAS-1"}}
+
+	_, err := MatchPackage(context.Background(), dir, reqs, MatchOptions{Completer: &fakeCompleter{err: errors.New("boom")}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+
+	_, err = MatchPackage(context.Background(), dir, reqs, MatchOptions{Completer: &fakeCompleter{response: "no idea"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not JSON")
+}
+
+func TestMatchCandidatesTruncatesOnRuneBoundary(t *testing.T) {
+	dir := t.TempDir()
+	prose := "a" + strings.Repeat("é", maxSectionExcerpt)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SPEC.md"), []byte("# mypkg\n\n"+prose+"\n"), 0644))
+
+	candidates, err := matchCandidates(dir)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	excerpt := candidates[0].excerpt
+	assert.True(t, utf8.ValidString(excerpt))
+	assert.True(t, strings.HasSuffix(excerpt, "..."))
+	assert.LessOrEqual(t, len(excerpt), maxSectionExcerpt+len("..."))
+}
@@ -0,0 +1,74 @@
+package reqtrace
+
+// Trace is a requirement and the links that trace it.
+type Trace struct {
+	Requirement Requirement
+	Links       []Link // Links are sorted by package, file, line, and requirement ID.
+}
+
+// Report is the traceability of a set of requirements.
+type Report struct {
+	Traces  []Trace // Traces has one entry per requirement, in requirement order.
+	Unknown []Link  // Unknown are links whose RequirementID isn't one of the requirements (ex: a typo, or a removed requirement).
+}
+
+// BuildReport groups links by requirement. Links to the same target (requirement, kind, package, and target) are collapsed into one, preferring annotations over
+// LLM matches.
+func BuildReport(reqs []Requirement, links []Link) Report {
+	type linkKey struct {
+		id         string
+		kind       LinkKind
+		pkgDir     string
+		target     string
+		annotation bool
+	}
+	hasAnnotation := map[linkKey]bool{}
+	for _, l := range links {
+		if l.Source == LinkSourceAnnotation {
+			hasAnnotation[linkKey{id: l.RequirementID, kind: l.Kind, pkgDir: l.PackageDir, target: l.Target}] = true
+		}
+	}
+
+	index := make(map[string]int, len(reqs))
+	report := Report{Traces: make([]Trace, len(reqs))}
+	for i, r := range reqs {
+		index[r.ID] = i
+		report.Traces[i].Requirement = r
+	}
+
+	seen := map[linkKey]bool{}
+	for _, l := range links {
+		key := linkKey{id: l.RequirementID, kind: l.Kind, pkgDir: l.PackageDir, target: l.Target}
+		if l.Source != LinkSourceAnnotation && hasAnnotation[key] {
+			continue
+		}
+		key.annotation = l.Source == LinkSourceAnnotation
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		i, ok := index[l.RequirementID]
+		if !ok {
+			report.Unknown = append(report.Unknown, l)
+			continue
+		}
+		report.Traces[i].Links = append(report.Traces[i].Links, l)
+	}
+	for i := range report.Traces {
+		sortLinks(report.Traces[i].Links)
+	}
+	sortLinks(report.Unknown)
+	return report
+}
+
+// Untraced returns the requirements without any links, in requirement order.
+func (r Report) Untraced() []Requirement {
+	var out []Requirement
+	for _, t := range r.Traces {
+		if len(t.Links) == 0 {
+			out = append(out, t.Requirement)
+		}
+	}
+	return out
+}
@@ -0,0 +1,183 @@
+// Package reqtrace traces product-spec requirements to the packages that implement them. Requirements are tagged with stable IDs in product-spec markdown (ex:
+// "- [CAS-3] The user can ..."); they are linked to SPEC.md sections and test functions by annotations, or by LLM-assisted matching.
+package reqtrace
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Requirement is one product-spec requirement with a stable ID.
+type Requirement struct {
+	ID      string // ID is the requirement's stable ID (ex: "CAS-3").
+	Text    string // Text is the requirement's line of markdown, without list markers or the ID tag.
+	File    string // File is the path of the markdown file, as passed to ParseRequirements.
+	Line    int    // Line is the 1-based line of the requirement in File.
+	Section string // Section is the heading path containing the requirement, joined by " > " (ex: "CAS > Hash mode"); "" before any heading.
+}
+
+// requirementIDPattern matches a requirement ID: an uppercase prefix (which may contain dashes) and a number.
+const requirementIDPattern = `[A-Z][A-Z0-9]*(?:-[A-Z0-9]+)*-[0-9]+`
+
+var (
+	requirementIDRE   = regexp.MustCompile(`^` + requirementIDPattern + `$`)
+	requirementLineRE = regexp.MustCompile(`^\s*(?:(?:[-*+]|[0-9]+[.)])\s+)?\[(` + requirementIDPattern + `)\]\s*(.*)$`)
+	headingRE         = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	fenceRE           = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+// ValidRequirementID reports whether id is a well-formed requirement ID (ex: "CAS-3", "PR-ORCH-12").
+func ValidRequirementID(id string) bool {
+	return requirementIDRE.MatchString(id)
+}
+
+// ParseRequirements returns the requirements tagged in markdown, in document order. A requirement is a list item, paragraph line, or heading that starts with an
+// ID in square brackets (ex: "- [CAS-3] The user can ..." or "## [CLI-1] Help"). Lines in fenced code blocks are ignored. path is only used to fill in Requirement.File.
+//
+// An error is returned if an ID is tagged more than once.
+func ParseRequirements(path string, markdown []byte) ([]Requirement, error) {
+	var reqs []Requirement
+	seen := map[string]int{}
+	err := walkMarkdown(markdown, func(lineNum int, text string, headings []string, _ bool) error {
+		m := requirementLineRE.FindStringSubmatch(text)
+		if m == nil {
+			return nil
+		}
+		id := m[1]
+		if prev, ok := seen[id]; ok {
+			return fmt.Errorf("reqtrace: %s:%d: requirement %s is already defined at line %d", path, lineNum, id, prev)
+		}
+		seen[id] = lineNum
+		reqs = append(reqs, Requirement{
+			ID:      id,
+			Text:    strings.TrimSpace(m[2]),
+			File:    path,
+			Line:    lineNum,
+			Section: sectionPath(headings),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reqs, nil
+}
+
+// LoadRequirements parses every .md file under dir (recursively, in lexical path order) and returns their requirements. File paths are relative to dir, with
+// forward slashes. An error is returned if dir can't be read or an ID is defined more than once across files.
+func LoadRequirements(dir string) ([]Requirement, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".md") {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reqtrace: %w", err)
+	}
+	sort.Strings(paths)
+
+	var all []Requirement
+	defined := map[string]Requirement{}
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("reqtrace: %w", err)
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return nil, fmt.Errorf("reqtrace: %w", err)
+		}
+		reqs, err := ParseRequirements(filepath.ToSlash(rel), b)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range reqs {
+			if prev, ok := defined[r.ID]; ok {
+				return nil, fmt.Errorf("reqtrace: %s:%d: requirement %s is already defined at %s:%d", r.File, r.Line, r.ID, prev.File, prev.Line)
+			}
+			defined[r.ID] = r
+		}
+		all = append(all, reqs...)
+	}
+	return all, nil
+}
+
+// walkMarkdown calls visit for each line of markdown outside fenced code blocks, with its 1-based line number, its text (for headings, without the leading #s),
+// the heading path that contains it (for headings, including the heading itself, as returned by headingTitle), and whether it is a heading. It stops at, and
+// returns, the first error from visit.
+func walkMarkdown(markdown []byte, visit func(lineNum int, text string, headings []string, isHeading bool) error) error {
+	var headings []string
+	inFence := ""
+	scanner := bufio.NewScanner(bytes.NewReader(markdown))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		text := scanner.Text()
+		if m := fenceRE.FindStringSubmatch(text); m != nil {
+			switch inFence {
+			case "":
+				inFence = m[1]
+			case m[1]:
+				inFence = ""
+			}
+			continue
+		}
+		if inFence != "" {
+			continue
+		}
+		m := headingRE.FindStringSubmatch(text)
+		if m != nil {
+			level := len(m[1])
+			if len(headings) >= level {
+				headings = headings[:level-1]
+			}
+			for len(headings) < level-1 {
+				headings = append(headings, "")
+			}
+			text = m[2]
+			headings = append(headings, headingTitle(text))
+		}
+		if err := visit(lineNum, text, headings, m != nil); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reqtrace: read markdown: %w", err)
+	}
+	return nil
+}
+
+// htmlCommentRE matches an HTML comment on one line.
+var htmlCommentRE = regexp.MustCompile(`<!--.*?-->`)
+
+// headingTitle returns heading text without a leading "[ID]" requirement tag or HTML comments (ex: annotations).
+func headingTitle(text string) string {
+	if m := requirementLineRE.FindStringSubmatch(text); m != nil {
+		text = m[2]
+	}
+	return strings.TrimSpace(htmlCommentRE.ReplaceAllString(text, ""))
+}
+
+// sectionPath joins the non-empty headings with " > ".
+func sectionPath(headings []string) string {
+	var parts []string
+	for _, h := range headings {
+		if h != "" {
+			parts = append(parts, h)
+		}
+	}
+	return strings.Join(parts, " > ")
+}
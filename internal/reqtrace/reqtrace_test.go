@@ -0,0 +1,82 @@
+package reqtrace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidRequirementID(t *testing.T) {
+	assert.True(t, ValidRequirementID("CAS-3"))
+	assert.True(t, ValidRequirementID("PR-ORCH-12"))
+	assert.True(t, ValidRequirementID("A1-1"))
+	assert.False(t, ValidRequirementID("cas-3"))
+	assert.False(t, ValidRequirementID("CAS"))
+	assert.False(t, ValidRequirementID("CAS-"))
+	assert.False(t, ValidRequirementID("3-CAS"))
+}
+
+func TestParseRequirements(t *testing.T) {
+	md := strings.Join([]string{
+		"# CAS",
+		"",
+		"Intro text without an ID.",
+		"",
+		"## Hash mode",
+		"",
+		"- [CAS-1] The user can hash a package.",
+		"  * [CAS-2] Nested items work too.",
+		"1. [CAS-3] So do ordered items.",
+		"",
+		"```",
+		"- [CAS-99] not a requirement; in a fence",
+		"```",
+		"",
+		"### [CAS-4] Headings can be requirements",
+		"",
+		"[CAS-5] A paragraph line.",
+	}, "\n")
+
+	reqs, err := ParseRequirements("cas.md", []byte(md))
+	require.NoError(t, err)
+	require.Len(t, reqs, 5)
+
+	assert.Equal(t, Requirement{ID: "CAS-1", Text: "The user can hash a package.", File: "cas.md", Line: 7, Section: "CAS > Hash mode"}, reqs[0])
+	assert.Equal(t, "CAS-2", reqs[1].ID)
+	assert.Equal(t, "CAS-3", reqs[2].ID)
+	assert.Equal(t, Requirement{ID: "CAS-4", Text: "Headings can be requirements", File: "cas.md", Line: 15, Section: "CAS > Hash mode > Headings can be requirements"}, reqs[3])
+	assert.Equal(t, "CAS-5", reqs[4].ID)
+	assert.Equal(t, "CAS > Hash mode > Headings can be requirements", reqs[4].Section)
+}
+
+func TestParseRequirementsDuplicate(t *testing.T) {
+	_, err := ParseRequirements("cas.md", []byte("- [CAS-1] one\n- [CAS-1] two\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cas.md:2")
+	assert.Contains(t, err.Error(), "already defined at line 1")
+}
+
+func TestLoadRequirements(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "refactors"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cli.md"), []byte("# CLI\n- [CLI-1] Help.\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "refactors", "docs.md"), []byte("# Docs\n- [DOCS-1] Fix.\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("- [TXT-1] ignored\n"), 0o644))
+
+	reqs, err := LoadRequirements(dir)
+	require.NoError(t, err)
+	require.Len(t, reqs, 2)
+	assert.Equal(t, "CLI-1", reqs[0].ID)
+	assert.Equal(t, "cli.md", reqs[0].File)
+	assert.Equal(t, "DOCS-1", reqs[1].ID)
+	assert.Equal(t, "refactors/docs.md", reqs[1].File)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "more.md"), []byte("- [CLI-1] Again.\n"), 0o644))
+	_, err = LoadRequirements(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already defined at cli.md:2")
+}
@@ -0,0 +1,5 @@
+// Package slashcommands loads user-defined slash commands from `.codalotl/commands/*.md` files.
+//
+// Each file holds optional YAML front matter (description, argument hint, agent, mode, model, and allowed tools) and a text/template prompt body that is rendered
+// with the command's arguments. The TUI and `codalotl exec --slash-command` use these commands.
+package slashcommands
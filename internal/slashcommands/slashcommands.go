@@ -0,0 +1,380 @@
+package slashcommands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	"gopkg.in/yaml.v3"
+)
+
+// Command modes, for Command.Mode.
+const (
+	ModeDefault = ""        // ModeDefault keeps the caller's current mode.
+	ModeGeneric = "generic" // ModeGeneric runs the command in Generic Mode.
+	ModePackage = "package" // ModePackage runs the command in Package Mode on the current package.
+)
+
+// commandNameRe matches valid command names (the file name without ".md").
+var commandNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// argsRefRe matches template references to the command arguments.
+var argsRefRe = regexp.MustCompile(`\.Arg[sv]\b`)
+
+// Command is a custom slash command loaded from a markdown file.
+type Command struct {
+	Name         string           // Name is the file name without ".md"; the command is invoked as "/" + Name.
+	Path         string           // Path is the absolute path of the command file.
+	Description  string           // Description is a one-line summary for listings.
+	ArgumentHint string           // ArgumentHint describes the expected arguments for listings (ex: "[focus area]").
+	Agent        string           // Agent, if set, is the registered agent name that runs the command.
+	Mode         string           // Mode is one of ModeDefault, ModeGeneric, or ModePackage.
+	Model        llmmodel.ModelID // Model, if set, is the model the command runs with.
+	AllowedTools []string         // AllowedTools, if non-nil, restricts the agent's tools to these names.
+	Body         string           // Body is the prompt template.
+}
+
+// TemplateData is the data available to a command's prompt template.
+type TemplateData struct {
+	Args       string   // Args is the trimmed argument string after the command name.
+	Argv       []string // Argv is Args split on whitespace, with quoted words grouped.
+	Package    string   // Package is the package path relative to the sandbox, or "" outside Package Mode.
+	SandboxDir string   // SandboxDir is the absolute sandbox dir.
+}
+
+// frontMatter is the optional YAML front matter of a command file.
+type frontMatter struct {
+	Description  string   `yaml:"description"`
+	ArgumentHint string   `yaml:"argument-hint"`
+	Agent        string   `yaml:"agent"`
+	Mode         string   `yaml:"mode"`
+	Model        string   `yaml:"model"`
+	AllowedTools []string `yaml:"allowed-tools"`
+}
+
+// SearchPaths returns absolute directories where commands may be located, in priority order.
+//
+// Starting in startDir (can be "" for cwd), it looks for `$DIR/.codalotl/commands`, then repeats for each parent directory up to the filesystem root. Lastly, it
+// checks `~/.codalotl/commands`. Only existing directories are returned. Errors are ignored.
+func SearchPaths(startDir string) []string {
+	if startDir == "" {
+		if wd, err := os.Getwd(); err == nil {
+			startDir = wd
+		}
+	}
+	if startDir == "" {
+		return nil
+	}
+	startAbs, err := filepath.Abs(startDir)
+	if err != nil {
+		startAbs = startDir
+	}
+
+	var paths []string
+	seen := make(map[string]struct{})
+	addIfDir := func(dir string) {
+		if _, ok := seen[dir]; ok {
+			return
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return
+		}
+		seen[dir] = struct{}{}
+		paths = append(paths, dir)
+	}
+
+	for dir := startAbs; ; {
+		addIfDir(filepath.Join(dir, ".codalotl", "commands"))
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if homeAbs, err := filepath.Abs(home); err == nil {
+			home = homeAbs
+		}
+		addIfDir(filepath.Join(home, ".codalotl", "commands"))
+	}
+	return paths
+}
+
+// LoadError is a failure to load one command file.
+type LoadError struct {
+	Name string // Name is the command name derived from the file name.
+	Path string // Path is the path of the command file.
+	Err  error  // Err is the underlying error.
+}
+
+// Error returns the error message, prefixed with the file path.
+func (e *LoadError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// LoadCommand loads the command file at path. The command name is derived from the file name.
+//
+// The model must be a registered llmmodel ID. If toolNames is non-nil, every allowed tool must be one of toolNames (ex: the agent registry's tool names); a nil
+// toolNames skips that check.
+func LoadCommand(path string, toolNames []string) (Command, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return Command{}, err
+	}
+	name := strings.TrimSuffix(filepath.Base(absPath), ".md")
+	if !commandNameRe.MatchString(name) {
+		return Command{}, fmt.Errorf("invalid command name %q: use lowercase letters, digits, '-', or '_'", name)
+	}
+
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return Command{}, err
+	}
+	fmText, body, err := splitFrontMatter(string(content))
+	if err != nil {
+		return Command{}, err
+	}
+
+	var fm frontMatter
+	if fmText != "" {
+		dec := yaml.NewDecoder(strings.NewReader(fmText))
+		dec.KnownFields(true)
+		if err := dec.Decode(&fm); err != nil && !errors.Is(err, io.EOF) {
+			return Command{}, fmt.Errorf("front matter is invalid: %w", err)
+		}
+	}
+
+	cmd := Command{
+		Name:         name,
+		Path:         absPath,
+		Description:  strings.TrimSpace(fm.Description),
+		ArgumentHint: strings.TrimSpace(fm.ArgumentHint),
+		Agent:        strings.TrimSpace(fm.Agent),
+		Mode:         strings.TrimSpace(fm.Mode),
+		Model:        llmmodel.ModelID(strings.TrimSpace(fm.Model)),
+		AllowedTools: fm.AllowedTools,
+		Body:         strings.TrimSpace(body),
+	}
+	switch cmd.Mode {
+	case ModeDefault, ModeGeneric, ModePackage:
+	default:
+		return Command{}, fmt.Errorf("invalid mode %q: must be %q or %q", cmd.Mode, ModeGeneric, ModePackage)
+	}
+	if cmd.Model != "" && !cmd.Model.Valid() {
+		return Command{}, fmt.Errorf("unknown model %q", cmd.Model)
+	}
+	if toolNames != nil {
+		for _, tool := range cmd.AllowedTools {
+			if !slices.Contains(toolNames, tool) {
+				return Command{}, fmt.Errorf("allowed-tools: unknown tool %q", tool)
+			}
+		}
+	}
+	if cmd.Body == "" {
+		return Command{}, errors.New("command body is empty")
+	}
+	if _, err := cmd.parseTemplate(); err != nil {
+		return Command{}, err
+	}
+	return cmd, nil
+}
+
+// Load loads `*.md` command files (non-recursively) from searchDirs, in order, validating them with LoadCommand and toolNames. When names collide, the first command
+// found wins.
+//
+// It returns:
+//   - commands: loaded commands, sorted by name.
+//   - failedLoads: a *LoadError for each command file that could not be loaded.
+//   - fnErr: only non-nil for fatal errors. Missing search dirs are not errors.
+func Load(searchDirs []string, toolNames []string) (commands []Command, failedLoads []error, fnErr error) {
+	seen := make(map[string]struct{})
+	for _, searchDir := range searchDirs {
+		entries, err := os.ReadDir(searchDir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+				continue
+			}
+			name := strings.TrimSuffix(entry.Name(), ".md")
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			path := filepath.Join(searchDir, entry.Name())
+			cmd, err := LoadCommand(path, toolNames)
+			if err != nil {
+				failedLoads = append(failedLoads, &LoadError{Name: name, Path: path, Err: err})
+				continue
+			}
+			seen[name] = struct{}{}
+			commands = append(commands, cmd)
+		}
+	}
+
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+	return commands, failedLoads, nil
+}
+
+// LookupFailure returns the first error in failedLoads (as returned by Load) for the command named name (with or without a leading "/"), or nil if there is none.
+// Callers use it to explain why a command that exists could not be run.
+func LookupFailure(failedLoads []error, name string) error {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "/")
+	for _, err := range failedLoads {
+		var loadErr *LoadError
+		if errors.As(err, &loadErr) && loadErr.Name == name {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookup returns the command named name (with or without a leading "/").
+func Lookup(commands []Command, name string) (Command, bool) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "/")
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return cmd, true
+		}
+	}
+	return Command{}, false
+}
+
+// ParseInvocation splits input like "/name some args" into name ("name") and args ("some args"). ok is false if input does not start with "/" followed by a name.
+func ParseInvocation(input string) (name string, args string, ok bool) {
+	input = strings.TrimSpace(input)
+	if !strings.HasPrefix(input, "/") {
+		return "", "", false
+	}
+	rest := input[1:]
+	end := strings.IndexFunc(rest, isSpace)
+	if end < 0 {
+		end = len(rest)
+	}
+	name = rest[:end]
+	if name == "" {
+		return "", "", false
+	}
+	return name, strings.TrimSpace(rest[end:]), true
+}
+
+// SplitArgs splits args on whitespace, grouping single- or double-quoted words (quotes are removed). Unbalanced quotes extend to the end of args.
+func SplitArgs(args string) []string {
+	var out []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+	for _, r := range args {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case isSpace(r):
+			if inWord {
+				out = append(out, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		out = append(out, cur.String())
+	}
+	return out
+}
+
+// Render renders the command's prompt template with data. If the body references neither .Args nor .Argv and data.Args is non-empty, the arguments are appended
+// after a blank line. Data.Argv is derived from data.Args when nil.
+func (c Command) Render(data TemplateData) (string, error) {
+	tmpl, err := c.parseTemplate()
+	if err != nil {
+		return "", err
+	}
+	data.Args = strings.TrimSpace(data.Args)
+	if data.Argv == nil {
+		data.Argv = SplitArgs(data.Args)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("/%s: %w", c.Name, err)
+	}
+	out := strings.TrimSpace(b.String())
+	if data.Args != "" && !argsRefRe.MatchString(c.Body) {
+		out += "\n\n" + data.Args
+	}
+	return out, nil
+}
+
+// NeedsNewSession reports whether the command changes the agent, mode, model, or tools, so it must run in a fresh session rather than as a message in the current
+// one.
+func (c Command) NeedsNewSession() bool {
+	return c.Agent != "" || c.Mode != ModeDefault || c.Model != "" || c.AllowedTools != nil
+}
+
+// Usage returns a listing line like "/review [focus area] - Review the current package".
+func (c Command) Usage() string {
+	s := "/" + c.Name
+	if c.ArgumentHint != "" {
+		s += " " + c.ArgumentHint
+	}
+	if c.Description != "" {
+		s += " - " + c.Description
+	}
+	return s
+}
+
+// parseTemplate parses the command body. Missing map keys are errors so typos surface instead of rendering "<no value>".
+func (c Command) parseTemplate() (*template.Template, error) {
+	tmpl, err := template.New(c.Name).Option("missingkey=error").Parse(c.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// splitFrontMatter splits content into optional YAML front matter and body. Front matter is present only if the first line is "---"; it must then be closed by
+// another "---" line.
+func splitFrontMatter(content string) (frontMatter string, body string, err error) {
+	lines := strings.Split(content, "\n")
+	if strings.TrimRight(lines[0], "\r") != "---" {
+		return "", content, nil
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], "\r") == "---" {
+			return strings.Join(lines[1:i], "\n"), strings.Join(lines[i+1:], "\n"), nil
+		}
+	}
+	return "", "", errors.New("front matter not properly closed with ---")
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
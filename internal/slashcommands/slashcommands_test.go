@@ -0,0 +1,190 @@
+package slashcommands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/llmmodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCommand(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadCommand_FrontMatter(t *testing.T) {
+	path := writeCommand(t, t.TempDir(), "review.md", `---
+description: Review the package
+argument-hint: "[focus]"
+agent: package_mode_no_context
+mode: package
+model: `+string(llmmodel.DefaultModel)+`
+allowed-tools: [read_file, ls]
+---
+Review {{.Package}}. Focus: {{.Args}}
+`)
+
+	cmd, err := LoadCommand(path, []string{"ls", "read_file", "write"})
+	require.NoError(t, err)
+	assert.Equal(t, "review", cmd.Name)
+	assert.Equal(t, path, cmd.Path)
+	assert.Equal(t, "Review the package", cmd.Description)
+	assert.Equal(t, "[focus]", cmd.ArgumentHint)
+	assert.Equal(t, "package_mode_no_context", cmd.Agent)
+	assert.Equal(t, ModePackage, cmd.Mode)
+	assert.Equal(t, llmmodel.DefaultModel, cmd.Model)
+	assert.Equal(t, []string{"read_file", "ls"}, cmd.AllowedTools)
+	assert.Equal(t, "Review {{.Package}}. Focus: {{.Args}}", cmd.Body)
+	assert.True(t, cmd.NeedsNewSession())
+	assert.Equal(t, "/review [focus] - Review the package", cmd.Usage())
+}
+
+func TestLoadCommand_NoFrontMatter(t *testing.T) {
+	path := writeCommand(t, t.TempDir(), "explain.md", "Explain this code.\n")
+
+	cmd, err := LoadCommand(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "explain", cmd.Name)
+	assert.Equal(t, "Explain this code.", cmd.Body)
+	assert.Nil(t, cmd.AllowedTools)
+	assert.False(t, cmd.NeedsNewSession())
+	assert.Equal(t, "/explain", cmd.Usage())
+}
+
+func TestLoadCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "unknown field", file: "a.md", content: "---\ndescripton: typo\n---\nbody\n"},
+		{name: "bad mode", file: "a.md", content: "---\nmode: other\n---\nbody\n"},
+		{name: "unclosed front matter", file: "a.md", content: "---\ndescription: x\nbody\n"},
+		{name: "empty body", file: "a.md", content: "---\ndescription: x\n---\n\n"},
+		{name: "bad template", file: "a.md", content: "{{.Args"},
+		{name: "bad name", file: "Review.md", content: "body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeCommand(t, t.TempDir(), tt.file, tt.content)
+			_, err := LoadCommand(path, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadCommand_ValidatesModelAndTools(t *testing.T) {
+	dir := t.TempDir()
+
+	path := writeCommand(t, dir, "a.md", "---\nmodel: no-such-model\n---\nbody\n")
+	_, err := LoadCommand(path, nil)
+	assert.EqualError(t, err, `unknown model "no-such-model"`)
+
+	path = writeCommand(t, dir, "b.md", "---\nallowed-tools: [read_file, raed_file]\n---\nbody\n")
+	_, err = LoadCommand(path, []string{"read_file"})
+	assert.EqualError(t, err, `allowed-tools: unknown tool "raed_file"`)
+
+	// Without known tool names, tools aren't checked.
+	_, err = LoadCommand(path, nil)
+	assert.NoError(t, err)
+}
+
+func TestLoad_FirstDirWinsAndFailuresReported(t *testing.T) {
+	projectDir := filepath.Join(t.TempDir(), "project")
+	userDir := filepath.Join(t.TempDir(), "user")
+	writeCommand(t, projectDir, "review.md", "project review")
+	writeCommand(t, projectDir, "broken.md", "{{")
+	writeCommand(t, projectDir, "notes.txt", "ignored")
+	writeCommand(t, userDir, "review.md", "user review")
+	writeCommand(t, userDir, "alpha.md", "alpha")
+
+	commands, failed, err := Load([]string{projectDir, filepath.Join(t.TempDir(), "missing"), userDir}, nil)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Contains(t, failed[0].Error(), "broken.md")
+	assert.Equal(t, failed[0], LookupFailure(failed, "/broken"))
+	assert.Nil(t, LookupFailure(failed, "review"))
+
+	require.Len(t, commands, 2)
+	assert.Equal(t, "alpha", commands[0].Name)
+	assert.Equal(t, "review", commands[1].Name)
+	assert.Equal(t, "project review", commands[1].Body)
+
+	cmd, ok := Lookup(commands, "/review")
+	require.True(t, ok)
+	assert.Equal(t, "project review", cmd.Body)
+	_, ok = Lookup(commands, "nope")
+	assert.False(t, ok)
+}
+
+func TestSearchPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	require.NoError(t, os.MkdirAll(filepath.Join(nested, ".codalotl", "commands"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".codalotl", "commands"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".codalotl", "commands"), 0o755))
+
+	paths := SearchPaths(nested)
+	assert.Equal(t, []string{
+		filepath.Join(nested, ".codalotl", "commands"),
+		filepath.Join(root, ".codalotl", "commands"),
+		filepath.Join(home, ".codalotl", "commands"),
+	}, paths)
+}
+
+func TestParseInvocation(t *testing.T) {
+	name, args, ok := ParseInvocation("  /review  focus on errors ")
+	assert.True(t, ok)
+	assert.Equal(t, "review", name)
+	assert.Equal(t, "focus on errors", args)
+
+	name, args, ok = ParseInvocation("/review")
+	assert.True(t, ok)
+	assert.Equal(t, "review", name)
+	assert.Equal(t, "", args)
+
+	_, _, ok = ParseInvocation("review")
+	assert.False(t, ok)
+	_, _, ok = ParseInvocation("/ x")
+	assert.False(t, ok)
+}
+
+func TestSplitArgs(t *testing.T) {
+	assert.Equal(t, []string{"a", "b c", "d e", "f"}, SplitArgs(` a "b c" 'd e'  f`))
+	assert.Equal(t, []string{"x", "y z"}, SplitArgs(`x "y z`))
+	assert.Nil(t, SplitArgs("   "))
+}
+
+func TestRender(t *testing.T) {
+	cmd := Command{Name: "fix", Body: "Fix {{index .Argv 0}} in {{.Package}} ({{.SandboxDir}})."}
+	out, err := cmd.Render(TemplateData{Args: `"the bug" now`, Package: "internal/foo", SandboxDir: "/repo"})
+	require.NoError(t, err)
+	assert.Equal(t, "Fix the bug in internal/foo (/repo).", out)
+
+	withArgs := Command{Name: "with-args", Body: "Focus: {{.Args}}"}
+	out, err = withArgs.Render(TemplateData{Args: "errors"})
+	require.NoError(t, err)
+	assert.Equal(t, "Focus: errors", out)
+
+	// Bodies that don't reference the args get them appended.
+	plain := Command{Name: "plain", Body: "Do the thing."}
+	out, err = plain.Render(TemplateData{Args: "carefully"})
+	require.NoError(t, err)
+	assert.Equal(t, "Do the thing.\n\ncarefully", out)
+
+	out, err = plain.Render(TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "Do the thing.", out)
+
+	// Execution errors (ex: missing argument) are reported.
+	_, err = cmd.Render(TemplateData{})
+	assert.Error(t, err)
+}
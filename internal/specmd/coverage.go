@@ -0,0 +1,204 @@
+package specmd
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+)
+
+// UncoveredAPI is an exported identifier in the implementation that the SPEC.md Public API doesn't declare.
+type UncoveredAPI struct {
+	// ID is the identifier: the name of a package-level declaration, or "Type.Name" for methods, struct fields, and interface methods (nested struct fields add more
+	// dots). Pointer receivers are not marked.
+	ID string
+
+	ImplFile string // The .go file containing the declaration.
+	ImplLine int    // The line number of the declaration (for fields and interface methods, of the enclosing type declaration).
+}
+
+// APICoverage describes how much of a package's exported API its SPEC.md declares.
+type APICoverage struct {
+	Total     int            // Total is the number of exported identifiers in the implementation, counted as in UncoveredAPI.ID.
+	Uncovered []UncoveredAPI // Uncovered are the identifiers that the SPEC.md Public API doesn't declare, ordered by file and line.
+}
+
+// APICoverage is the reverse of ImplementationDiffs: it finds exported identifiers in the corresponding Go package that aren't declared in the SPEC.md's PublicAPIGoCodeBlocks.
+// Exported package-level declarations, methods on exported types, exported struct fields (recursively for nested struct types), and interface methods are counted.
+// Test files are ignored.
+//
+// Identifiers only need to be declared to be covered; whether the declarations match is ImplementationDiffs' concern. If the corresponding Go package cannot be
+// loaded, an error is returned.
+func (s *Spec) APICoverage() (APICoverage, error) {
+	if s == nil {
+		return APICoverage{}, errors.New("specmd: APICoverage: nil Spec")
+	}
+	if s.AbsPath == "" {
+		return APICoverage{}, errors.New("specmd: APICoverage: empty AbsPath")
+	}
+	md, err := parseMarkdown([]byte(s.Body))
+	if err != nil {
+		return APICoverage{}, err
+	}
+	specIDs := map[string]bool{}
+	for _, b := range md.goFencesInPublicAPI {
+		if !b.multiLine {
+			continue
+		}
+		f, err := parseGoFileFragment(b.code)
+		if err != nil {
+			return APICoverage{}, err
+		}
+		for _, d := range f.Decls {
+			for _, id := range coverageIDsForDecl(d) {
+				specIDs[id] = true
+			}
+		}
+	}
+
+	pkg, err := loadImplPackageForSpec(s.AbsPath)
+	if err != nil {
+		return APICoverage{}, err
+	}
+	var coverage APICoverage
+	for fileName, snippets := range pkg.SnippetsByFile(nil) {
+		if strings.HasSuffix(fileName, "_test.go") {
+			continue
+		}
+		for _, snip := range snippets {
+			if snip == nil || snip.Test() || !snip.HasExported() {
+				continue
+			}
+			if _, ok := snip.(*gocode.PackageDocSnippet); ok {
+				continue
+			}
+			// The public snippet has unexported members elided, so every identifier in it is exported.
+			public, err := snip.PublicSnippet(false)
+			if err != nil {
+				return APICoverage{}, fmt.Errorf("specmd: APICoverage: public snippet for %s in %s: %w", strings.Join(snip.IDs(), ","), fileName, err)
+			}
+			f, err := parseGoFileFragment(string(public))
+			if err != nil {
+				return APICoverage{}, fmt.Errorf("specmd: APICoverage: parse public snippet for %s in %s: %w", strings.Join(snip.IDs(), ","), fileName, err)
+			}
+			line := snip.Position().Line
+			for _, d := range f.Decls {
+				for _, id := range coverageIDsForDecl(d) {
+					coverage.Total++
+					if !specIDs[id] {
+						coverage.Uncovered = append(coverage.Uncovered, UncoveredAPI{ID: id, ImplFile: fileName, ImplLine: line})
+					}
+				}
+			}
+		}
+	}
+	sort.SliceStable(coverage.Uncovered, func(i, j int) bool {
+		a, b := coverage.Uncovered[i], coverage.Uncovered[j]
+		if a.ImplFile != b.ImplFile {
+			return a.ImplFile < b.ImplFile
+		}
+		if a.ImplLine != b.ImplLine {
+			return a.ImplLine < b.ImplLine
+		}
+		return a.ID < b.ID
+	})
+	return coverage, nil
+}
+
+// FormatUncoveredAPIs formats and writes uncovered to out, one identifier per line with its location (ex: "Foo (foo.go:12)").
+func FormatUncoveredAPIs(uncovered []UncoveredAPI, out io.Writer) error {
+	if out == nil {
+		return errors.New("specmd: FormatUncoveredAPIs: nil writer")
+	}
+	var b strings.Builder
+	for _, u := range uncovered {
+		fmt.Fprintf(&b, "%s (%s:%d)\n", u.ID, u.ImplFile, u.ImplLine)
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// coverageIDsForDecl returns the identifiers d declares, as in UncoveredAPI.ID, including struct fields and interface methods of declared types. Exportedness is
+// not checked.
+func coverageIDsForDecl(d ast.Decl) []string {
+	switch dd := d.(type) {
+	case *ast.FuncDecl:
+		recv, name := gocode.GetReceiverFuncName(dd)
+		if recv == "" {
+			return []string{name}
+		}
+		return []string{strings.TrimPrefix(recv, "*") + "." + name}
+	case *ast.GenDecl:
+		var ids []string
+		for _, spec := range dd.Specs {
+			switch ss := spec.(type) {
+			case *ast.TypeSpec:
+				ids = append(ids, ss.Name.Name)
+				ids = append(ids, memberCoverageIDs(ss.Name.Name, ss.Type)...)
+			case *ast.ValueSpec:
+				for _, n := range ss.Names {
+					ids = append(ids, n.Name)
+				}
+			}
+		}
+		return ids
+	default:
+		return nil
+	}
+}
+
+// memberCoverageIDs returns "<prefix>.<name>" for the fields of a struct type expr (recursing into nested struct types) or the methods of an interface type expr.
+// Embedded fields are named by their type name; embedded interfaces and type constraints aren't members.
+func memberCoverageIDs(prefix string, expr ast.Expr) []string {
+	var ids []string
+	switch t := expr.(type) {
+	case *ast.StructType:
+		for _, field := range t.Fields.List {
+			var names []string
+			if len(field.Names) == 0 {
+				if name := embeddedTypeName(field.Type); name != "" {
+					names = append(names, name)
+				}
+			}
+			for _, n := range field.Names {
+				names = append(names, n.Name)
+			}
+			for _, name := range names {
+				ids = append(ids, prefix+"."+name)
+				ids = append(ids, memberCoverageIDs(prefix+"."+name, field.Type)...)
+			}
+		}
+	case *ast.InterfaceType:
+		for _, method := range t.Methods.List {
+			if _, ok := method.Type.(*ast.FuncType); !ok {
+				continue
+			}
+			for _, n := range method.Names {
+				ids = append(ids, prefix+"."+n.Name)
+			}
+		}
+	}
+	return ids
+}
+
+// embeddedTypeName returns the field name of an embedded field with type expr (ex: "Bar" for *pkg.Bar[T]), or "" if expr isn't a valid embedded type.
+func embeddedTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return embeddedTypeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedTypeName(t.X)
+	case *ast.IndexListExpr:
+		return embeddedTypeName(t.X)
+	default:
+		return ""
+	}
+}
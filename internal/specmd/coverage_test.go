@@ -0,0 +1,95 @@
+package specmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPICoverage(t *testing.T) {
+	modDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "go.mod"), []byte("module example.com/tmp\n\ngo 1.24.4\n"), 0o644))
+	pkgDir := filepath.Join(modDir, "mypkg")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+	impl := strings.Join([]string{
+		"package mypkg",
+		"",
+		"func Foo() {}",
+		"",
+		"func Bar() {}",
+		"",
+		"func helper() {}",
+		"",
+		"type T struct {",
+		"	A int",
+		"	B struct {",
+		"		C int",
+		"	}",
+		"	d int",
+		"}",
+		"",
+		"func (t *T) M() {}",
+		"",
+		"func (t T) N() {}",
+		"",
+		"type I interface {",
+		"	Do()",
+		"}",
+		"",
+		"type hidden struct{}",
+		"",
+		"func (h hidden) Exported() {}",
+		"",
+		"const (",
+		"	X = 1",
+		"	y = 2",
+		")",
+		"",
+	}, "\n")
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "impl.go"), []byte(impl), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "impl_test.go"), []byte("package mypkg\n\nfunc TestHelper() {}\n"), 0o644))
+
+	s := &Spec{
+		AbsPath: filepath.Join(pkgDir, "SPEC.md"),
+		Body: strings.Join([]string{
+			"# mypkg",
+			"",
+			"## Public API",
+			"",
+			"```go",
+			"func Foo()",
+			"",
+			"type T struct {",
+			"	B struct {",
+			"		C int",
+			"	}",
+			"}",
+			"",
+			"func (t *T) M()",
+			"",
+			"const X = 1",
+			"```",
+			"",
+		}, "\n"),
+	}
+	require.NoError(t, os.WriteFile(s.AbsPath, []byte(s.Body), 0o644))
+	coverage, err := s.APICoverage()
+	require.NoError(t, err)
+	assert.Equal(t, 11, coverage.Total)
+	assert.Equal(t, []UncoveredAPI{
+		{ID: "Bar", ImplFile: "impl.go", ImplLine: 5},
+		{ID: "T.A", ImplFile: "impl.go", ImplLine: 9},
+		{ID: "T.N", ImplFile: "impl.go", ImplLine: 19},
+		{ID: "I", ImplFile: "impl.go", ImplLine: 21},
+		{ID: "I.Do", ImplFile: "impl.go", ImplLine: 21},
+	}, coverage.Uncovered)
+
+	var buf bytes.Buffer
+	require.NoError(t, FormatUncoveredAPIs(coverage.Uncovered[:2], &buf))
+	assert.Equal(t, "Bar (impl.go:5)\nT.A (impl.go:9)\n", buf.String())
+}
@@ -0,0 +1,219 @@
+package specmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/imports"
+)
+
+// ExampleBlock is a runnable example in a SPEC.md: a Go code block with {example} in its info string (ex: ```go {example}).
+type ExampleBlock struct {
+	Code string // Code is the contents of the code block.
+	Line int    // Line is the 1-based SPEC.md line of the first line of Code.
+}
+
+// ExampleResult is the outcome of running one ExampleBlock against the package's implementation.
+type ExampleResult struct {
+	Example ExampleBlock
+	Passed  bool   // Passed is true if the example compiled and its tests passed.
+	Output  string // Output is the go test output when the example failed; "" when it passed.
+}
+
+// exampleForm describes how an example block is turned into a test file.
+type exampleForm struct {
+	decls bool     // decls is true when the block is top-level declarations, used as is; otherwise it is statements, wrapped in a Test function.
+	tests []string // tests are the Test and Example functions a declarations block declares, in order.
+}
+
+// ExampleGoCodeBlocks returns the Go code blocks with {example} in the info string (this includes things like {example, other_tag}), in document order. Example
+// blocks are never part of PublicAPIGoCodeBlocks, even in a Public API section, unless they also have {api}.
+//
+// An example block is either top-level declarations (ex: `func ExampleFoo()` with an `// Output:` comment, or `func TestFoo(t *testing.T)`) or a sequence of
+// statements. Errors are returned for the same reasons as GoCodeBlocks.
+func (s *Spec) ExampleGoCodeBlocks() ([]ExampleBlock, error) {
+	if s == nil {
+		return nil, errors.New("specmd: ExampleGoCodeBlocks: nil Spec")
+	}
+	md, err := parseMarkdown([]byte(s.Body))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ExampleBlock, 0, len(md.exampleGoFences))
+	for _, b := range md.exampleGoFences {
+		out = append(out, ExampleBlock{Code: b.code, Line: b.contentStartLine})
+	}
+	return out, nil
+}
+
+// RunExamples compiles and runs each of the SPEC.md's ExampleGoCodeBlocks against the package in the SPEC.md's directory, and returns one result per example,
+// in document order. It returns nil if there are no examples.
+//
+// Each example becomes a throwaway `_test.go` file in the external test package (`<pkg>_test`), supplied to `go test` with -overlay so nothing is written to the
+// package directory:
+//   - A declarations block is used as is; its Test and Example functions are run (an Example without an `// Output:` comment is only compiled).
+//   - A statements block is wrapped in a Test function, so `t *testing.T` is in scope.
+//
+// Imports are added automatically (goimports-style); the package under test is imported by its name. Each example is run in its own `go test` invocation, so one
+// example's compile error doesn't fail another. An error is returned if the markdown is invalid, the package can't be loaded (or is package main), or go test can't
+// be run; a failing example is not an error.
+func (s *Spec) RunExamples(ctx context.Context) ([]ExampleResult, error) {
+	if s == nil {
+		return nil, errors.New("specmd: RunExamples: nil Spec")
+	}
+	if s.AbsPath == "" {
+		return nil, errors.New("specmd: RunExamples: empty AbsPath")
+	}
+	examples, err := s.ExampleGoCodeBlocks()
+	if err != nil {
+		return nil, err
+	}
+	if len(examples) == 0 {
+		return nil, nil
+	}
+
+	pkg, err := loadImplPackageForSpec(s.AbsPath)
+	if err != nil {
+		return nil, err
+	}
+	if pkg.Name == "main" {
+		return nil, fmt.Errorf("specmd: RunExamples: examples are not supported for package main (%s)", pkg.ImportPath)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "specmd-examples-*")
+	if err != nil {
+		return nil, fmt.Errorf("specmd: RunExamples: create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	results := make([]ExampleResult, 0, len(examples))
+	for i, ex := range examples {
+		result, err := runExample(ctx, pkg.AbsolutePath(), pkg.Name, pkg.ImportPath, ex, tmpDir, i)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// runExample runs ex against the package named pkgName with importPath in pkgDir, using tmpDir for the overlay files of the example with index i.
+func runExample(ctx context.Context, pkgDir string, pkgName string, importPath string, ex ExampleBlock, tmpDir string, i int) (ExampleResult, error) {
+	result := ExampleResult{Example: ex}
+
+	form, err := parseExample(ex.Code)
+	if err != nil {
+		result.Output = err.Error()
+		return result, nil
+	}
+	testPath := filepath.Join(pkgDir, fmt.Sprintf("zz_specmd_example_line%d_test.go", ex.Line))
+	src, err := exampleTestFileSource(testPath, pkgName, importPath, ex, form)
+	if err != nil {
+		result.Output = err.Error()
+		return result, nil
+	}
+
+	srcPath := filepath.Join(tmpDir, fmt.Sprintf("example%d_test.go", i))
+	if err := os.WriteFile(srcPath, src, 0o644); err != nil {
+		return result, fmt.Errorf("specmd: RunExamples: write example: %w", err)
+	}
+	overlay, err := json.Marshal(map[string]map[string]string{"Replace": {testPath: srcPath}})
+	if err != nil {
+		return result, fmt.Errorf("specmd: RunExamples: marshal overlay: %w", err)
+	}
+	overlayPath := filepath.Join(tmpDir, fmt.Sprintf("overlay%d.json", i))
+	if err := os.WriteFile(overlayPath, overlay, 0o644); err != nil {
+		return result, fmt.Errorf("specmd: RunExamples: write overlay: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "test", "-count=1", "-vet=off", "-overlay="+overlayPath, "-run="+exampleRunPattern(form, ex), ".")
+	cmd.Dir = pkgDir
+	out, err := cmd.CombinedOutput()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return result, ctxErr
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return result, fmt.Errorf("specmd: RunExamples: run go test: %w", err)
+		}
+		result.Output = string(out)
+		return result, nil
+	}
+	result.Passed = true
+	return result, nil
+}
+
+// parseExample classifies example code as declarations or statements, returning a syntax error if it is neither.
+func parseExample(code string) (exampleForm, error) {
+	if f, err := parseGoFileFragment(code); err == nil {
+		form := exampleForm{decls: true}
+		for _, d := range f.Decls {
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil {
+				continue
+			}
+			if strings.HasPrefix(fn.Name.Name, "Test") || strings.HasPrefix(fn.Name.Name, "Example") {
+				form.tests = append(form.tests, fn.Name.Name)
+			}
+		}
+		return form, nil
+	}
+	src := "package p\n\nfunc _() {\n" + code + "\n}\n"
+	if _, err := parser.ParseFile(token.NewFileSet(), "example.go", src, parser.AllErrors); err != nil {
+		return exampleForm{}, fmt.Errorf("specmd: go syntax error in example: %w", err)
+	}
+	return exampleForm{}, nil
+}
+
+// exampleTestFunc returns the name of the Test function that wraps the statements example ex.
+func exampleTestFunc(ex ExampleBlock) string {
+	return fmt.Sprintf("TestSpecExampleLine%d", ex.Line)
+}
+
+// exampleRunPattern returns the go test -run pattern selecting the tests of example ex, which has form. A declarations example without tests matches nothing,
+// so it is only compiled.
+func exampleRunPattern(form exampleForm, ex ExampleBlock) string {
+	if !form.decls {
+		return "^" + exampleTestFunc(ex) + "$"
+	}
+	if len(form.tests) == 0 {
+		return "^$"
+	}
+	quoted := make([]string, 0, len(form.tests))
+	for _, name := range form.tests {
+		quoted = append(quoted, regexp.QuoteMeta(name))
+	}
+	return "^(" + strings.Join(quoted, "|") + ")$"
+}
+
+// exampleTestFileSource returns the source of the test file at testPath for example ex, in the external test package of the package named pkgName with importPath,
+// with imports resolved.
+func exampleTestFileSource(testPath string, pkgName string, importPath string, ex ExampleBlock, form exampleForm) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by specmd from the SPEC.md example at line %d. DO NOT EDIT.\n\n", ex.Line)
+	fmt.Fprintf(&b, "package %s_test\n\n", pkgName)
+	fmt.Fprintf(&b, "import (\n\t\"testing\"\n\n\t%q\n)\n\n", importPath)
+	if form.decls {
+		b.WriteString(ex.Code)
+	} else {
+		fmt.Fprintf(&b, "func %s(t *testing.T) {\n%s\n}\n", exampleTestFunc(ex), ex.Code)
+	}
+	// Process adds missing imports and drops unused ones, including testing and the package under test.
+	src, err := imports.Process(testPath, b.Bytes(), &imports.Options{Comments: true, TabIndent: true, TabWidth: 8})
+	if err != nil {
+		return nil, fmt.Errorf("specmd: example at line %d: %w", ex.Line, err)
+	}
+	return src, nil
+}
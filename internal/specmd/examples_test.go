@@ -0,0 +1,107 @@
+package specmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExampleGoCodeBlocks(t *testing.T) {
+	s := &Spec{
+		AbsPath: filepath.Join(t.TempDir(), "SPEC.md"),
+		Body: strings.Join([]string{
+			"# p",
+			"",
+			"```go {example}",
+			"x := p.Foo()",
+			"_ = x",
+			"```",
+			"",
+			"## Public API",
+			"",
+			"```go {example, other}",
+			"func ExampleFoo() {}",
+			"```",
+			"",
+			"```go",
+			"// Foo returns 1.",
+			"func Foo() int",
+			"```",
+			"",
+		}, "\n"),
+	}
+	examples, err := s.ExampleGoCodeBlocks()
+	require.NoError(t, err)
+	assert.Equal(t, []ExampleBlock{
+		{Code: "x := p.Foo()\n_ = x\n", Line: 4},
+		{Code: "func ExampleFoo() {}\n", Line: 11},
+	}, examples)
+
+	// Examples in a Public API section aren't API declarations.
+	api, err := s.PublicAPIGoCodeBlocks()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"// Foo returns 1.\nfunc Foo() int\n"}, api)
+
+	// Statement examples are valid, even though they aren't declarations.
+	require.NoError(t, s.Validate())
+}
+
+func TestRunExamples(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	modDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "go.mod"), []byte("module example.com/tmp\n\ngo 1.24.4\n"), 0o644))
+	pkgDir := filepath.Join(modDir, "mypkg")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "impl.go"), []byte("package mypkg\n\n// Double returns 2*n.\nfunc Double(n int) int { return 2 * n }\n"), 0o644))
+	specBody := strings.Join([]string{
+		"# mypkg",
+		"",
+		"```go {example}",
+		"if got := mypkg.Double(2); got != 4 {",
+		"\tt.Fatalf(\"Double(2) = %d\", got)",
+		"}",
+		"```",
+		"",
+		"```go {example}",
+		"func ExampleDouble() {",
+		"\tfmt.Println(mypkg.Double(3))",
+		"\t// Output: 7",
+		"}",
+		"```",
+		"",
+		"```go {example}",
+		"mypkg.Triple(1)",
+		"```",
+		"",
+	}, "\n")
+	specPath := filepath.Join(pkgDir, "SPEC.md")
+	require.NoError(t, os.WriteFile(specPath, []byte(specBody), 0o644))
+
+	s, err := Read(specPath)
+	require.NoError(t, err)
+	results, err := s.RunExamples(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.True(t, results[0].Passed, results[0].Output)
+	assert.Equal(t, 4, results[0].Example.Line)
+	assert.Empty(t, results[0].Output)
+
+	assert.False(t, results[1].Passed)
+	assert.Contains(t, results[1].Output, "ExampleDouble")
+
+	assert.False(t, results[2].Passed)
+	assert.Contains(t, results[2].Output, "Triple")
+
+	// Nothing is written to the package directory.
+	entries, err := os.ReadDir(pkgDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
@@ -13,6 +13,7 @@ import (
 type parsedMarkdown struct {
 	allGoFences         []goFenceBlock // The list contains every Go-tagged fence in document order.
 	goFencesInPublicAPI []goFenceBlock // The list contains Go fences selected by a public API section or api flag.
+	exampleGoFences     []goFenceBlock // The list contains Go fences with an example flag.
 }
 
 // goFenceBlock records a Go fenced code block and the SPEC.md context needed to edit or classify it.
@@ -25,6 +26,7 @@ type goFenceBlock struct {
 	contentStartLine int    // 1-based line number in source where code content starts
 	inPublicAPI      bool   // The value is true when the fence appears under a public API heading.
 	hasAPIFlag       bool   // The value is true when the fence info string contains an api flag.
+	hasExampleFlag   bool   // The value is true when the fence info string contains an example flag.
 }
 
 // markdownHeading records the source location and normalized text of a Markdown heading.
@@ -52,9 +54,13 @@ func parseMarkdown(src []byte) (*parsedMarkdown, error) {
 	out := &parsedMarkdown{}
 	out.allGoFences = blocks
 	for _, b := range blocks {
-		if b.hasAPIFlag || b.inPublicAPI {
+		// Example blocks are usage code, not declarations, even inside a Public API section.
+		if b.hasAPIFlag || (b.inPublicAPI && !b.hasExampleFlag) {
 			out.goFencesInPublicAPI = append(out.goFencesInPublicAPI, b)
 		}
+		if b.hasExampleFlag {
+			out.exampleGoFences = append(out.exampleGoFences, b)
+		}
 	}
 	return out, nil
 }
@@ -195,6 +201,7 @@ func collectGoFencesWithContext(src []byte, root ast.Node) ([]goFenceBlock, erro
 			contentStartLine: startLine,
 			inPublicAPI:      public != nil,
 			hasAPIFlag:       hasAPIFlag,
+			hasExampleFlag:   infoStringHasFlag(info, "example"),
 		})
 		return ast.WalkContinue, nil
 	})
@@ -0,0 +1,62 @@
+package specmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+)
+
+// PublicAPI returns Go code declaring pkg's public API, suitable for a SPEC.md `{api}` code block: exported declarations with their doc comments, grouped by file
+// (sorted by name) and in source order within a file, without function bodies or unexported members. Because it is generated from the declarations themselves,
+// a SPEC.md whose Public API is exactly this code conforms to pkg.
+//
+// The package doc comment and test files are not included. It returns "" if pkg has no exported declarations.
+func PublicAPI(pkg *gocode.Package) (string, error) {
+	if pkg == nil {
+		return "", errors.New("specmd: PublicAPI: nil package")
+	}
+	if pkg.IsTestPackage() {
+		return "", fmt.Errorf("specmd: PublicAPI: %q is a test package", pkg.ImportPath)
+	}
+
+	perFile := pkg.SnippetsByFile(nil)
+	fileNames := make([]string, 0, len(perFile))
+	for fileName := range perFile {
+		if strings.HasSuffix(fileName, "_test.go") {
+			continue
+		}
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	var decls []string
+	for _, fileName := range fileNames {
+		for _, snip := range perFile[fileName] {
+			if snip == nil || snip.Test() || !snip.HasExported() {
+				continue
+			}
+			if _, ok := snip.(*gocode.PackageDocSnippet); ok {
+				continue
+			}
+			b, err := snip.PublicSnippet(false)
+			if err != nil {
+				return "", fmt.Errorf("specmd: PublicAPI: public snippet for %s in %s: %w", strings.Join(snip.IDs(), ","), fileName, err)
+			}
+			if decl := strings.TrimSpace(string(b)); decl != "" {
+				decls = append(decls, decl)
+			}
+		}
+	}
+	if len(decls) == 0 {
+		return "", nil
+	}
+
+	code := strings.Join(decls, "\n\n") + "\n"
+	if formatted, ok := gofmtFragment(code); ok {
+		code = formatted
+	}
+	return code, nil
+}
@@ -0,0 +1,87 @@
+package specmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicAPI(t *testing.T) {
+	modDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "go.mod"), []byte("module example.com/tmp\n\ngo 1.24.4\n"), 0o644))
+	pkgDir := filepath.Join(modDir, "mypkg")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+	files := map[string]string{
+		"b.go": strings.Join([]string{
+			"// Package mypkg does things.",
+			"package mypkg",
+			"",
+			"// Foo does things.",
+			"func Foo(a int) error { return nil }",
+			"",
+			"func helper() {}",
+			"",
+			"// T is a thing.",
+			"type T struct {",
+			"	N int // N counts.",
+			"	n int",
+			"}",
+			"",
+			"// M returns N.",
+			"func (t *T) M() int { return t.N }",
+			"",
+			"const (",
+			"	A = 1",
+			"	b = 2",
+			")",
+			"",
+		}, "\n"),
+		"a.go":      "package mypkg\n\nvar Bar = 3\n",
+		"a_test.go": "package mypkg\n\nfunc TestX() {}\n",
+	}
+	for name, src := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(pkgDir, name), []byte(src), 0o644))
+	}
+
+	mod, err := gocode.NewModule(modDir)
+	require.NoError(t, err)
+	pkg, err := mod.LoadPackageByRelativeDir("mypkg")
+	require.NoError(t, err)
+
+	api, err := PublicAPI(pkg)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"var Bar = 3",
+		"",
+		"// Foo does things.",
+		"func Foo(a int) error",
+		"",
+		"// T is a thing.",
+		"type T struct {",
+		"	N int // N counts.",
+		"	// contains filtered or unexported fields",
+		"}",
+		"",
+		"// M returns N.",
+		"func (t *T) M() int",
+		"",
+		"const (",
+		"	A = 1",
+		")",
+		"",
+	}, "\n"), api)
+
+	// The generated API conforms to the package.
+	specPath := filepath.Join(pkgDir, "SPEC.md")
+	require.NoError(t, os.WriteFile(specPath, []byte("# mypkg\n\n## Public API\n\n```go {api}\n"+api+"```\n"), 0o644))
+	s, err := Read(specPath)
+	require.NoError(t, err)
+	diffs, err := s.ImplementationDiffs()
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+}
@@ -59,8 +59,8 @@ func (s *Spec) WithoutPublicAPI() (*Spec, error) {
 	}, nil
 }
 
-// Validate parses Body as a markdown file, and ensures each Go code block has valid code without syntax errors. The code is not checked for type errors. The first
-// error encountered is returned; nil if no errors.
+// Validate parses Body as a markdown file, and ensures each Go code block has valid code without syntax errors ({example} blocks may also be statements). The code
+// is not checked for type errors. The first error encountered is returned; nil if no errors.
 func (s *Spec) Validate() error {
 	if s == nil {
 		return errors.New("specmd: Validate: nil Spec")
@@ -70,6 +70,12 @@ func (s *Spec) Validate() error {
 		return err
 	}
 	for _, b := range md.allGoFences {
+		if b.hasExampleFlag {
+			if _, err := parseExample(b.code); err != nil {
+				return err
+			}
+			continue
+		}
 		_, err := parseGoFileFragment(b.code)
 		if err != nil {
 			return err
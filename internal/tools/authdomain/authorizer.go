@@ -39,6 +39,10 @@ type UserRequest struct {
 	Allow      func()   // idempotent; unblocks the pending authorization with an "allow".
 	Disallow   func()   // idempotent; unblocks the pending authorization with a "deny".
 
+	// AlwaysAllow, if non-nil, adds allow rules for this request to the project's PermissionsFile and then behaves like Allow. The request is allowed even if saving
+	// fails; the save error is returned. It is nil when the authorizer has no permission rules (see SetPermissionRules) or a rule couldn't cover the request.
+	AlwaysAllow func() error
+
 	// private fields ok
 }
 
@@ -139,17 +143,31 @@ func NewAutoApproveAuthorizer(sandboxDir string) Authorizer {
 
 // NewSessionAuthorizer constructs the standard codalotl session authorizer.
 //
-// When autoApprove is false, this uses the permissive sandbox policy and returns the user request channel for interactive approvals. When autoApprove is true, this
-// uses the auto-approve policy and returns a nil request channel because no approval prompts can be emitted.
+// When autoApprove is false, this uses the permissive sandbox policy with the project permission rules in sandboxDir's PermissionsFile, and returns the user request
+// channel for interactive approvals. An invalid PermissionsFile is an error. When autoApprove is true, this uses the auto-approve policy (which ignores permission
+// rules) and returns a nil request channel because no approval prompts can be emitted.
 func NewSessionAuthorizer(sandboxDir string, commands *ShellAllowedCommands, autoApprove bool) (Authorizer, <-chan UserRequest, error) {
+	sandbox, err := normalizeSandboxDir(sandboxDir)
+	if err != nil {
+		return nil, nil, err
+	}
 	if autoApprove {
-		sandbox, err := normalizeSandboxDir(sandboxDir)
-		if err != nil {
-			return nil, nil, err
-		}
 		return autoApproveAuthorizer{sandboxDir: sandbox}, nil, nil
 	}
-	return NewPermissiveSandboxAuthorizer(sandboxDir, commands)
+
+	rules, err := LoadPermissionRules(sandbox)
+	if err != nil {
+		return nil, nil, err
+	}
+	auth, requests, err := NewPermissiveSandboxAuthorizer(sandbox, commands)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := SetPermissionRules(auth, sandbox, rules); err != nil {
+		auth.Close()
+		return nil, nil, err
+	}
+	return auth, requests, nil
 }
 
 // NewCodeUnitAuthorizer constructs an Authorizer that enforces membership in unit before delegating to fallback.
@@ -274,12 +292,14 @@ func (a *sandboxAuthorizer) IsAuthorizedForRead(requestPermission bool, requestR
 		return fmt.Errorf("path %q is outside sandbox %q; operating in strict sandbox mode - request denied", outside[0], sandbox)
 	}
 
-	if requestPermission && len(inside) > 0 {
-		inside = filterGrantedReadPaths(a.baseAuthorizer.grants, sandbox, toolName, false, inside)
-		return a.promptForPaths(toolName, "read", scopeInsideSandbox, sandbox, inside, requestReason, true)
+	askInside, inside, err := a.permissionRules().splitPaths(toolName, inside)
+	if err != nil {
+		return err
 	}
-
-	return nil
+	if requestPermission {
+		askInside = append(askInside, filterGrantedReadPaths(a.baseAuthorizer.grants, sandbox, toolName, false, inside)...)
+	}
+	return a.promptForPaths(toolName, "read", scopeInsideSandbox, sandbox, askInside, requestReason, requestPermission)
 }
 
 // IsAuthorizedForWrite reports whether all paths may be written under the strict sandbox policy. Paths outside SandboxDir are denied without prompting. Inside paths
@@ -302,11 +322,19 @@ func (a *sandboxAuthorizer) IsAuthorizedForWrite(requestPermission bool, request
 		return fmt.Errorf("path %q is outside sandbox %q; operating in strict sandbox mode - request denied", outside[0], sandbox)
 	}
 
-	if requestPermission && len(inside) > 0 {
-		return a.promptForPaths(toolName, "write", scopeInsideSandbox, sandbox, inside, requestReason, true)
+	protectedInside, inside, err := a.splitPermissionsFilePaths(toolName, sandbox, inside)
+	if err != nil {
+		return err
 	}
-
-	return nil
+	askInside, inside, err := a.permissionRules().splitPaths(toolName, inside)
+	if err != nil {
+		return err
+	}
+	askInside = append(askInside, protectedInside...)
+	if requestPermission {
+		askInside = append(askInside, inside...)
+	}
+	return a.promptForPaths(toolName, "write", scopeInsideSandbox, sandbox, askInside, requestReason, requestPermission)
 }
 
 // IsShellAuthorized reports whether command may run from cwd under the strict sandbox policy. A non-empty cwd is normalized and must be inside SandboxDir. The command
@@ -332,6 +360,12 @@ func (a *sandboxAuthorizer) IsShellAuthorized(requestPermission bool, requestRea
 	if err != nil {
 		return err
 	}
+	if handled, err := a.applyPermissionsFileCommand(command, requestReason, result, requestPermission, false); handled {
+		return err
+	}
+	if handled, err := a.applyCommandRules(cwd, command, requestReason, result, requestPermission, false); handled {
+		return err
+	}
 
 	switch result {
 	case CommandCheckResultSafe:
@@ -396,19 +430,25 @@ func (a *permissiveSandboxAuthorizer) IsAuthorizedForRead(requestPermission bool
 		return err
 	}
 
-	if len(outside) > 0 {
-		outside = filterGrantedReadPaths(a.baseAuthorizer.grants, sandbox, toolName, true, outside)
-		if len(outside) > 0 {
-			return a.promptForPaths(toolName, "read", scopeOutsideSandbox, sandbox, outside, requestReason, requestPermission)
-		}
+	rules := a.permissionRules()
+	askOutside, outside, err := rules.splitPaths(toolName, outside)
+	if err != nil {
+		return err
+	}
+	askInside, inside, err := rules.splitPaths(toolName, inside)
+	if err != nil {
+		return err
 	}
 
-	if requestPermission && len(inside) > 0 {
-		inside = filterGrantedReadPaths(a.baseAuthorizer.grants, sandbox, toolName, true, inside)
-		return a.promptForPaths(toolName, "read", scopeInsideSandbox, sandbox, inside, requestReason, true)
+	askOutside = append(askOutside, filterGrantedReadPaths(a.baseAuthorizer.grants, sandbox, toolName, true, outside)...)
+	if len(askOutside) > 0 {
+		return a.promptForPaths(toolName, "read", scopeOutsideSandbox, sandbox, askOutside, requestReason, requestPermission)
 	}
 
-	return nil
+	if requestPermission {
+		askInside = append(askInside, filterGrantedReadPaths(a.baseAuthorizer.grants, sandbox, toolName, true, inside)...)
+	}
+	return a.promptForPaths(toolName, "read", scopeInsideSandbox, sandbox, askInside, requestReason, requestPermission)
 }
 
 // IsAuthorizedForWrite reports whether all paths may be written under the permissive sandbox policy. Inside paths are allowed unless requestPermission is true.
@@ -427,15 +467,35 @@ func (a *permissiveSandboxAuthorizer) IsAuthorizedForWrite(requestPermission boo
 		return err
 	}
 
-	if len(outside) > 0 {
-		return a.promptForPaths(toolName, "write", scopeOutsideSandbox, sandbox, outside, requestReason, requestPermission)
+	protectedOutside, outside, err := a.splitPermissionsFilePaths(toolName, sandbox, outside)
+	if err != nil {
+		return err
+	}
+	protectedInside, inside, err := a.splitPermissionsFilePaths(toolName, sandbox, inside)
+	if err != nil {
+		return err
+	}
+	rules := a.permissionRules()
+	askOutside, outside, err := rules.splitPaths(toolName, outside)
+	if err != nil {
+		return err
+	}
+	askInside, inside, err := rules.splitPaths(toolName, inside)
+	if err != nil {
+		return err
 	}
 
-	if requestPermission && len(inside) > 0 {
-		return a.promptForPaths(toolName, "write", scopeInsideSandbox, sandbox, inside, requestReason, true)
+	askOutside = append(askOutside, protectedOutside...)
+	askOutside = append(askOutside, outside...)
+	if len(askOutside) > 0 {
+		return a.promptForPaths(toolName, "write", scopeOutsideSandbox, sandbox, askOutside, requestReason, requestPermission)
 	}
 
-	return nil
+	askInside = append(askInside, protectedInside...)
+	if requestPermission {
+		askInside = append(askInside, inside...)
+	}
+	return a.promptForPaths(toolName, "write", scopeInsideSandbox, sandbox, askInside, requestReason, requestPermission)
 }
 
 // IsShellAuthorized reports whether command may run from cwd under the permissive sandbox policy. A non-empty cwd is normalized; an outside-sandbox cwd requires
@@ -465,6 +525,12 @@ func (a *permissiveSandboxAuthorizer) IsShellAuthorized(requestPermission bool,
 	if err != nil {
 		return err
 	}
+	if handled, err := a.applyPermissionsFileCommand(command, requestReason, result, requestPermission, cwdOutside); handled {
+		return err
+	}
+	if handled, err := a.applyCommandRules(cwd, command, requestReason, result, requestPermission, cwdOutside); handled {
+		return err
+	}
 
 	switch result {
 	case CommandCheckResultSafe:
@@ -524,13 +590,27 @@ func (autoApproveAuthorizer) IsAuthorizedForRead(bool, string, string, ...string
 	return nil
 }
 
-// IsAuthorizedForWrite always authorizes writes without prompting or inspecting paths.
-func (autoApproveAuthorizer) IsAuthorizedForWrite(bool, string, string, ...string) error {
+// IsAuthorizedForWrite authorizes writes without prompting, except writes that could change the sandbox's PermissionsFile, which are denied (there is no user
+// to ask).
+func (a autoApproveAuthorizer) IsAuthorizedForWrite(_ bool, _ string, _ string, absPath ...string) error {
+	for _, raw := range absPath {
+		path, err := normalizeAbsolutePath(raw)
+		if err != nil {
+			return err
+		}
+		if isPermissionsFilePath(a.sandboxDir, path) {
+			return fmt.Errorf("writing %q could change %s; it is denied under auto-approve", path, PermissionsFile)
+		}
+	}
 	return nil
 }
 
-// IsShellAuthorized always authorizes shell execution without prompting or inspecting the command.
-func (autoApproveAuthorizer) IsShellAuthorized(bool, string, string, []string) error {
+// IsShellAuthorized authorizes shell execution without prompting, except commands that mention the PermissionsFile (see mentionsPermissionsFile), which are
+// denied (there is no user to ask).
+func (autoApproveAuthorizer) IsShellAuthorized(_ bool, _ string, _ string, command []string) error {
+	if mentionsPermissionsFile(command) {
+		return fmt.Errorf("command %q mentions %s; it is denied under auto-approve", strings.Join(command, " "), PermissionsFile)
+	}
 	return nil
 }
 
@@ -705,6 +785,7 @@ type baseAuthorizer struct {
 	wg        sync.WaitGroup               // Wg waits for in-flight request enqueues before requests is closed.
 	closedCh  chan struct{}                // ClosedCh is closed by Close to wake enqueues waiting to send on requests.
 	closeOnce sync.Once                    // CloseOnce makes Close idempotent.
+	rules     *permissionRules             // Rules are the project permission rules, or nil. Protected by mu.
 }
 
 func newBaseAuthorizer() *baseAuthorizer {
@@ -724,6 +805,52 @@ func (b *baseAuthorizer) addGrantUserMessage(userMessage string) {
 	b.grants.addGrantUserMessage(userMessage)
 }
 
+// setPermissionRules replaces the permission rules. Authorizers sharing this base (see WithUpdatedSandbox) share the rules.
+func (b *baseAuthorizer) setPermissionRules(rules *permissionRules) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rules = rules
+}
+
+// permissionRules returns the permission rules, or nil if none are set.
+func (b *baseAuthorizer) permissionRules() *permissionRules {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rules
+}
+
+// applyCommandRules applies permission rules to command. handled reports whether a rule decided the request, in which case err is the result: deny rules deny,
+// ask rules prompt, and allow rules allow (but still prompt when cwdOutside).
+func (b *baseAuthorizer) applyCommandRules(cwd string, command []string, requestReason string, result CommandCheckResult, requestPermission bool, cwdOutside bool) (handled bool, err error) {
+	rule, ok := b.permissionRules().commandRule(command)
+	if !ok {
+		return false, nil
+	}
+	switch rule.Action {
+	case PermissionDeny:
+		return true, fmt.Errorf("command %q is denied by rule %q in %s", strings.Join(command, " "), rule.String(), PermissionsFile)
+	case PermissionAllow:
+		if !cwdOutside {
+			return true, nil
+		}
+	}
+	return true, b.promptForCommand(cwd, command, requestReason, result, requestPermission, cwdOutside)
+}
+
+// applyPermissionsFileCommand handles commands that mention PermissionsFile (see mentionsPermissionsFile), so the agent can't grant itself permissions with a shell
+// write. Such commands always prompt, regardless of allow rules and policy, and never offer AlwaysAllow; a matching deny rule still denies them. Blocked commands
+// are left to the caller. handled is false when command doesn't mention PermissionsFile.
+func (b *baseAuthorizer) applyPermissionsFileCommand(command []string, requestReason string, result CommandCheckResult, requestPermission bool, cwdOutside bool) (handled bool, err error) {
+	if result == CommandCheckResultBlocked || !mentionsPermissionsFile(command) {
+		return false, nil
+	}
+	if rule, ok := b.permissionRules().commandRule(command); ok && rule.Action == PermissionDeny {
+		return true, fmt.Errorf("command %q is denied by rule %q in %s", strings.Join(command, " "), rule.String(), PermissionsFile)
+	}
+	prompt := buildCommandPrompt(strings.Join(command, " "), requestReason, result, requestPermission, cwdOutside)
+	return true, b.requestApproval(prompt, "", command, nil)
+}
+
 // The checkOpen method reports whether the authorizer is still accepting work. It returns ErrAuthorizerClosed after Close has started.
 func (b *baseAuthorizer) checkOpen() error {
 	b.mu.Lock()
@@ -735,6 +862,23 @@ func (b *baseAuthorizer) checkOpen() error {
 	return nil
 }
 
+// splitPermissionsFilePaths separates the paths whose write could change the PermissionsFile of sandbox or of the active rules' project dir. Those writes always
+// prompt, regardless of allow rules and policy, so the agent can't grant itself permissions; deny rules still deny them, and an error is returned if one does.
+func (b *baseAuthorizer) splitPermissionsFilePaths(toolName string, sandbox string, paths []string) (protected []string, rest []string, err error) {
+	rules := b.permissionRules()
+	for _, path := range paths {
+		if isPermissionsFilePath(sandbox, path) || (rules != nil && isPermissionsFilePath(rules.projectDir, path)) {
+			protected = append(protected, path)
+		} else {
+			rest = append(rest, path)
+		}
+	}
+	if _, _, err := rules.splitPaths(toolName, protected); err != nil {
+		return nil, nil, err
+	}
+	return protected, rest, nil
+}
+
 // The promptForPaths method asks the user to approve a filesystem operation for paths. It returns nil immediately when paths is empty. Otherwise it builds a prompt
 // from toolName, operation, scope, paths, requestReason, and requestPermission, then returns the result of the approval request; sandbox is accepted for call-site
 // consistency and is not used.
@@ -746,22 +890,27 @@ func (b *baseAuthorizer) promptForPaths(toolName string, operation string, scope
 	_ = sandbox // keep signature consistent with historical callers
 
 	prompt := buildPathPrompt(toolName, operation, scope, requestReason, paths, requestPermission)
-	return b.requestApproval(prompt, toolName, nil)
+	return b.requestApproval(prompt, toolName, nil, b.permissionRules().allowRulesForPaths(toolName, paths))
 }
 
 // The promptForCommand method requests user approval for a shell command.
 //
 // The prompt includes the command classification, explicit permission request, outside-sandbox working-directory status, and request reason. The request carries
-// command in UserRequest.Argv and returns nil only when approved.
+// command in UserRequest.Argv and returns nil only when approved. AlwaysAllow is not offered when cwdOutside, since command rules don't cover the cwd.
 func (b *baseAuthorizer) promptForCommand(cwd string, command []string, requestReason string, result CommandCheckResult, requestPermission bool, cwdOutside bool) error {
 	commandString := strings.Join(command, " ")
 	prompt := buildCommandPrompt(commandString, requestReason, result, requestPermission, cwdOutside)
-	return b.requestApproval(prompt, "", command)
+	var alwaysAllow []PermissionRule
+	if !cwdOutside {
+		alwaysAllow = b.permissionRules().allowRuleForCommand(command)
+	}
+	return b.requestApproval(prompt, "", command, alwaysAllow)
 }
 
 // The requestApproval method queues a UserRequest and waits for the user's decision. It returns nil when the request is allowed, ErrAuthorizationDenied when it
-// is denied, and ErrAuthorizerClosed if the authorizer closes before the request completes. argv is copied into UserRequest.Argv when provided.
-func (b *baseAuthorizer) requestApproval(prompt string, toolName string, argv []string) error {
+// is denied, and ErrAuthorizerClosed if the authorizer closes before the request completes. argv is copied into UserRequest.Argv when provided. When alwaysAllow
+// is non-empty, UserRequest.AlwaysAllow saves those rules before allowing.
+func (b *baseAuthorizer) requestApproval(prompt string, toolName string, argv []string, alwaysAllow []PermissionRule) error {
 	if err := b.checkOpen(); err != nil {
 		return err
 	}
@@ -780,6 +929,15 @@ func (b *baseAuthorizer) requestApproval(prompt string, toolName string, argv []
 	req.Disallow = func() {
 		b.resolvePending(pending, decisionDeny)
 	}
+	if rules := b.permissionRules(); rules != nil && len(alwaysAllow) > 0 {
+		var once sync.Once
+		req.AlwaysAllow = func() error {
+			var err error
+			once.Do(func() { err = rules.addAndSave(alwaysAllow) })
+			b.resolvePending(pending, decisionAllow)
+			return err
+		}
+	}
 
 	if err := b.enqueueRequest(req, pending); err != nil {
 		return err
@@ -0,0 +1,440 @@
+package authdomain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// PermissionsFile is the project permission rules file, relative to the project (sandbox) dir.
+const PermissionsFile = ".codalotl/permissions.json"
+
+// PermissionAction is the effect of a PermissionRule.
+type PermissionAction string
+
+const (
+	PermissionAllow PermissionAction = "allow" // Allow the request without asking the user.
+	PermissionAsk   PermissionAction = "ask"   // Ask the user, even if the request would otherwise be allowed.
+	PermissionDeny  PermissionAction = "deny"  // Deny the request without asking the user.
+)
+
+// ErrAuthorizerCannotAcceptPermissions is returned when an authorizer cannot use permission rules.
+var ErrAuthorizerCannotAcceptPermissions = errors.New("authdomain: authorizer cannot accept permission rules")
+
+// PermissionRule is a project-level rule that allows, asks about, or denies matching requests. A rule is either a command rule (Command set; applies to shell commands)
+// or a filesystem rule (Tool and/or Path set; applies to reads and writes).
+type PermissionRule struct {
+	Action PermissionAction `json:"action"`
+
+	// Command is a shell command prefix, matched word by word (ex: "go test" matches `go test ./...` but not `go testx`). Allow rules only match commands that are
+	// not inscrutable (pipes, subshells, etc.) unless the whole command equals Command, so an allowed prefix can't smuggle in other commands.
+	Command string `json:"command,omitempty"`
+
+	// Exact makes a command rule match only the whole command, not commands that start with it. Rules saved by UserRequest.AlwaysAllow are exact, so approving
+	// `rm -rf build` doesn't also approve `rm -rf build /`.
+	Exact bool `json:"exact,omitempty"`
+
+	// Path is a filepath.Match glob, relative to the project dir or absolute. It matches a path if it matches the path or any of its parent dirs, so "secrets" and
+	// "secrets/*" both cover the whole tree. A pattern without a "/" is matched against each path segment (ex: "*.pem" matches any .pem file).
+	Path string `json:"path,omitempty"`
+
+	// Tool is a tool name (ex: "write_file"). It limits a filesystem rule to that tool.
+	Tool string `json:"tool,omitempty"`
+}
+
+// permissionsFileContents is the JSON shape of PermissionsFile.
+type permissionsFileContents struct {
+	Rules []PermissionRule `json:"rules"`
+}
+
+// Validate returns an error if r has an unknown action, or is neither a valid command rule nor a valid filesystem rule.
+func (r PermissionRule) Validate() error {
+	switch r.Action {
+	case PermissionAllow, PermissionAsk, PermissionDeny:
+	default:
+		return fmt.Errorf("invalid action %q (allowed: allow, ask, deny)", r.Action)
+	}
+	command := normalizeCommandText(r.Command)
+	switch {
+	case command != "" && (r.Path != "" || r.Tool != ""):
+		return errors.New("a command rule cannot also set path or tool")
+	case command == "" && strings.TrimSpace(r.Path) == "" && strings.TrimSpace(r.Tool) == "":
+		return errors.New("rule must set command, path, or tool")
+	case command == "" && r.Exact:
+		return errors.New("exact only applies to command rules")
+	}
+	if r.Path != "" {
+		if _, err := filepath.Match(filepath.ToSlash(r.Path), ""); err != nil {
+			return fmt.Errorf("invalid path glob %q: %w", r.Path, err)
+		}
+	}
+	return nil
+}
+
+// String returns a one-line description of r (ex: "allow command `go test`", "allow exact command `rm -rf build`", "deny tool write_file path secrets").
+func (r PermissionRule) String() string {
+	parts := []string{string(r.Action)}
+	if r.Command != "" {
+		if r.Exact {
+			parts = append(parts, "exact")
+		}
+		parts = append(parts, "command `"+normalizeCommandText(r.Command)+"`")
+	}
+	if r.Tool != "" {
+		parts = append(parts, "tool "+r.Tool)
+	}
+	if r.Path != "" {
+		parts = append(parts, "path "+r.Path)
+	}
+	return strings.Join(parts, " ")
+}
+
+// LoadPermissionRules reads the rules in projectDir's PermissionsFile. A missing file yields no rules and no error. Invalid JSON or rules are errors.
+func LoadPermissionRules(projectDir string) ([]PermissionRule, error) {
+	path := filepath.Join(projectDir, filepath.FromSlash(PermissionsFile))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var contents permissionsFileContents
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for i, rule := range contents.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: rule %d: %w", path, i+1, err)
+		}
+	}
+	return contents.Rules, nil
+}
+
+// SavePermissionRules writes rules to projectDir's PermissionsFile, creating the `.codalotl` dir if needed. Each rule must be valid.
+func SavePermissionRules(projectDir string, rules []PermissionRule) error {
+	for i, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+	if rules == nil {
+		rules = []PermissionRule{}
+	}
+	data, err := json.MarshalIndent(permissionsFileContents{Rules: rules}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(projectDir, filepath.FromSlash(PermissionsFile))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// AddPermissionRule appends rule to projectDir's PermissionsFile unless an identical rule is already present. It reports whether the rule was added.
+func AddPermissionRule(projectDir string, rule PermissionRule) (bool, error) {
+	if err := rule.Validate(); err != nil {
+		return false, err
+	}
+	rules, err := LoadPermissionRules(projectDir)
+	if err != nil {
+		return false, err
+	}
+	for _, existing := range rules {
+		if existing == rule {
+			return false, nil
+		}
+	}
+	return true, SavePermissionRules(projectDir, append(rules, rule))
+}
+
+// RemovePermissionRule removes the rule at index (0-based, in file order) from projectDir's PermissionsFile and returns it.
+func RemovePermissionRule(projectDir string, index int) (PermissionRule, error) {
+	rules, err := LoadPermissionRules(projectDir)
+	if err != nil {
+		return PermissionRule{}, err
+	}
+	if index < 0 || index >= len(rules) {
+		return PermissionRule{}, fmt.Errorf("no rule %d (%d rules)", index+1, len(rules))
+	}
+	removed := rules[index]
+	rules = append(rules[:index], rules[index+1:]...)
+	return removed, SavePermissionRules(projectDir, rules)
+}
+
+// permissionAcceptor is implemented by authorizers that can enforce permission rules.
+type permissionAcceptor interface {
+	// setPermissionRules replaces the authorizer's permission rules.
+	setPermissionRules(rules *permissionRules)
+}
+
+// SetPermissionRules makes authorizer (and its fallback, if present) enforce rules, interpreting relative rule paths against projectDir. It also enables
+// UserRequest.AlwaysAllow, which saves new allow rules to projectDir's PermissionsFile.
+//
+// Rules apply to the sandbox and permissive sandbox policies: deny rules deny, ask rules prompt, and allow rules skip prompts (including for blocked or dangerous
+// commands). Rules never widen a strict sandbox to paths or cwds outside of it. An error is returned if authorizer cannot enforce rules (ex: AutoApprove) or a rule
+// is invalid.
+func SetPermissionRules(authorizer Authorizer, projectDir string, rules []PermissionRule) error {
+	for i, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+	if authorizer == nil {
+		return ErrAuthorizerCannotAcceptPermissions
+	}
+	dir, err := normalizeSandboxDir(projectDir)
+	if err != nil {
+		return err
+	}
+	acceptor, ok := authorizer.WithoutCodeUnit().(permissionAcceptor)
+	if !ok {
+		return ErrAuthorizerCannotAcceptPermissions
+	}
+	acceptor.setPermissionRules(&permissionRules{projectDir: dir, rules: append([]PermissionRule(nil), rules...)})
+	return nil
+}
+
+// permissionRules holds the rules enforced by an authorizer. A nil *permissionRules has no rules and cannot save new ones.
+type permissionRules struct {
+	mu         sync.RWMutex     // mu protects rules.
+	projectDir string           // projectDir is the normalized dir that relative rule paths are resolved against and that AlwaysAllow saves to.
+	rules      []PermissionRule // rules are the active rules, in file order.
+}
+
+// commandRule returns the most restrictive rule matching argv (deny, then ask, then allow). ok is false if no rule matches.
+func (p *permissionRules) commandRule(argv []string) (PermissionRule, bool) {
+	if p == nil {
+		return PermissionRule{}, false
+	}
+	text, inscrutable := shellCommandText(argv)
+	if text == "" {
+		return PermissionRule{}, false
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return mostRestrictive(p.rules, func(rule PermissionRule) bool {
+		prefix := normalizeCommandText(rule.Command)
+		if prefix == "" {
+			return false
+		}
+		if text == prefix {
+			return true
+		}
+		if rule.Exact || (rule.Action == PermissionAllow && inscrutable) {
+			return false
+		}
+		if strings.HasPrefix(text, prefix+" ") {
+			return true
+		}
+		// Deny/ask rules also catch the prefix anywhere in an inscrutable command (ex: "git push" in `make && git push`).
+		return inscrutable && strings.Contains(" "+text+" ", " "+prefix+" ")
+	})
+}
+
+// pathRule returns the most restrictive filesystem rule matching toolName and absPath. ok is false if no rule matches.
+func (p *permissionRules) pathRule(toolName string, absPath string) (PermissionRule, bool) {
+	if p == nil {
+		return PermissionRule{}, false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return mostRestrictive(p.rules, func(rule PermissionRule) bool {
+		if rule.Command != "" {
+			return false
+		}
+		if rule.Tool != "" && rule.Tool != toolName {
+			return false
+		}
+		return rule.Path == "" || p.pathMatches(rule.Path, absPath)
+	})
+}
+
+// pathMatches reports whether pattern (relative to p.projectDir, or absolute) matches absPath or one of its parent dirs.
+func (p *permissionRules) pathMatches(pattern string, absPath string) bool {
+	pattern = filepath.ToSlash(filepath.Clean(pattern))
+	target := absPath
+	if !filepath.IsAbs(pattern) {
+		rel, err := filepath.Rel(p.projectDir, absPath)
+		if err != nil || !withinSandbox(p.projectDir, absPath) {
+			return false
+		}
+		target = rel
+	}
+	target = filepath.ToSlash(target)
+
+	if !strings.Contains(pattern, "/") {
+		for _, segment := range strings.Split(target, "/") {
+			if ok, _ := filepath.Match(pattern, segment); ok {
+				return true
+			}
+		}
+		return false
+	}
+	for candidate := target; candidate != "" && candidate != "." && candidate != "/"; candidate = pathDir(candidate) {
+		if ok, _ := filepath.Match(pattern, candidate); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// splitPaths applies filesystem rules to paths. It returns an error if any path is denied; otherwise it returns the paths with an ask rule and the remaining paths
+// with no matching rule. Paths with an allow rule are dropped.
+func (p *permissionRules) splitPaths(toolName string, paths []string) (ask []string, rest []string, err error) {
+	if p == nil {
+		return nil, paths, nil
+	}
+	for _, path := range paths {
+		rule, ok := p.pathRule(toolName, path)
+		switch {
+		case !ok:
+			rest = append(rest, path)
+		case rule.Action == PermissionDeny:
+			return nil, nil, fmt.Errorf("access to %q is denied by rule %q in %s", path, rule.String(), PermissionsFile)
+		case rule.Action == PermissionAsk:
+			ask = append(ask, path)
+		}
+	}
+	return ask, rest, nil
+}
+
+// allowRulesForPaths returns allow rules covering toolName's access to paths, for UserRequest.AlwaysAllow. It returns nil if an ask rule matches any path, since
+// the ask rule would take precedence over a new allow rule, or if any path is the PermissionsFile (or its dir), since writes to it always prompt.
+func (p *permissionRules) allowRulesForPaths(toolName string, paths []string) []PermissionRule {
+	if p == nil {
+		return nil
+	}
+	rules := make([]PermissionRule, 0, len(paths))
+	for _, path := range paths {
+		if isPermissionsFilePath(p.projectDir, path) {
+			return nil
+		}
+		if rule, ok := p.pathRule(toolName, path); ok && rule.Action != PermissionAllow {
+			return nil
+		}
+		rulePath := path
+		if rel, err := filepath.Rel(p.projectDir, path); err == nil && withinSandbox(p.projectDir, path) {
+			rulePath = filepath.ToSlash(rel)
+		}
+		rules = append(rules, PermissionRule{Action: PermissionAllow, Tool: toolName, Path: rulePath})
+	}
+	return rules
+}
+
+// allowRuleForCommand returns an exact allow rule covering argv, for UserRequest.AlwaysAllow. It returns nil if an ask rule matches argv.
+func (p *permissionRules) allowRuleForCommand(argv []string) []PermissionRule {
+	if p == nil {
+		return nil
+	}
+	if rule, ok := p.commandRule(argv); ok && rule.Action != PermissionAllow {
+		return nil
+	}
+	text, _ := shellCommandText(argv)
+	if text == "" {
+		return nil
+	}
+	return []PermissionRule{{Action: PermissionAllow, Command: text, Exact: true}}
+}
+
+// addAndSave adds rules to the active rules and saves them to the project's PermissionsFile. Rules are active for this authorizer even if saving fails.
+func (p *permissionRules) addAndSave(rules []PermissionRule) error {
+	p.mu.Lock()
+	p.rules = append(p.rules, rules...)
+	p.mu.Unlock()
+
+	var errs []error
+	for _, rule := range rules {
+		if _, err := AddPermissionRule(p.projectDir, rule); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// mostRestrictive returns the first deny rule, else first ask rule, else first allow rule among rules that satisfy match.
+func mostRestrictive(rules []PermissionRule, match func(PermissionRule) bool) (PermissionRule, bool) {
+	var ask, allow *PermissionRule
+	for i := range rules {
+		rule := rules[i]
+		if !match(rule) {
+			continue
+		}
+		switch rule.Action {
+		case PermissionDeny:
+			return rule, true
+		case PermissionAsk:
+			if ask == nil {
+				ask = &rules[i]
+			}
+		case PermissionAllow:
+			if allow == nil {
+				allow = &rules[i]
+			}
+		}
+	}
+	switch {
+	case ask != nil:
+		return *ask, true
+	case allow != nil:
+		return *allow, true
+	}
+	return PermissionRule{}, false
+}
+
+// shellCommandText returns the normalized command text of argv, unwrapping `bash -c "..."` style wrappers, and whether the command is inscrutable.
+func shellCommandText(argv []string) (text string, inscrutable bool) {
+	if len(argv) == 0 {
+		return "", false
+	}
+	if unwrapped, handled, isInscrutable := unwrapShellCommand(argv); handled {
+		if !isInscrutable {
+			return normalizeCommandText(strings.Join(unwrapped, " ")), isInscrutableCommand(unwrapped)
+		}
+		if idx, ok, _ := shellCommandStringIndex(argv); ok && idx == len(argv)-1 {
+			return normalizeCommandText(argv[idx]), true
+		}
+		return normalizeCommandText(strings.Join(argv, " ")), true
+	}
+	return normalizeCommandText(strings.Join(argv, " ")), isInscrutableCommand(argv)
+}
+
+// normalizeCommandText collapses runs of whitespace in s to single spaces and trims it.
+func normalizeCommandText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// isPermissionsFilePath reports whether absPath is projectDir's PermissionsFile or the dir containing it, so that writing absPath could change the project's rules.
+func isPermissionsFilePath(projectDir string, absPath string) bool {
+	file := filepath.Join(projectDir, filepath.FromSlash(PermissionsFile))
+	return absPath == file || absPath == filepath.Dir(file)
+}
+
+// mentionsPermissionsFile reports whether any word of command mentions PermissionsFile's name or its `.codalotl` dir (ex: `cp x .codalotl/permissions.json`, or
+// a redirect inside `sh -c`). What a shell command writes can't be modeled precisely, so any mention counts.
+func mentionsPermissionsFile(command []string) bool {
+	dir, file := path.Split(PermissionsFile)
+	dir = strings.TrimSuffix(dir, "/")
+	for _, word := range command {
+		if strings.Contains(word, file) || strings.Contains(word, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathDir returns the parent of a slash-separated path, or "" at the top.
+func pathDir(p string) string {
+	i := strings.LastIndex(p, "/")
+	if i <= 0 {
+		return ""
+	}
+	return p[:i]
+}
@@ -0,0 +1,327 @@
+package authdomain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionRuleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    PermissionRule
+		wantErr bool
+	}{
+		{name: "command", rule: PermissionRule{Action: PermissionAllow, Command: "go test"}},
+		{name: "path", rule: PermissionRule{Action: PermissionDeny, Path: "secrets"}},
+		{name: "tool", rule: PermissionRule{Action: PermissionAsk, Tool: "write_file"}},
+		{name: "tool and path", rule: PermissionRule{Action: PermissionAllow, Tool: "write_file", Path: "docs/*.md"}},
+		{name: "bad action", rule: PermissionRule{Action: "maybe", Command: "ls"}, wantErr: true},
+		{name: "empty", rule: PermissionRule{Action: PermissionAllow}, wantErr: true},
+		{name: "command and path", rule: PermissionRule{Action: PermissionAllow, Command: "ls", Path: "x"}, wantErr: true},
+		{name: "bad glob", rule: PermissionRule{Action: PermissionDeny, Path: "a/["}, wantErr: true},
+		{name: "exact command", rule: PermissionRule{Action: PermissionAllow, Command: "rm -rf build", Exact: true}},
+		{name: "exact path", rule: PermissionRule{Action: PermissionAllow, Path: "x", Exact: true}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPermissionRulesFile(t *testing.T) {
+	dir := t.TempDir()
+
+	rules, err := LoadPermissionRules(dir)
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	rule := PermissionRule{Action: PermissionAllow, Command: "go test"}
+	added, err := AddPermissionRule(dir, rule)
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = AddPermissionRule(dir, rule)
+	require.NoError(t, err)
+	assert.False(t, added)
+	_, err = AddPermissionRule(dir, PermissionRule{Action: PermissionDeny, Path: ".env"})
+	require.NoError(t, err)
+
+	rules, err = LoadPermissionRules(dir)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	removed, err := RemovePermissionRule(dir, 0)
+	require.NoError(t, err)
+	assert.Equal(t, rule, removed)
+	_, err = RemovePermissionRule(dir, 5)
+	assert.Error(t, err)
+
+	rules, err = LoadPermissionRules(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []PermissionRule{{Action: PermissionDeny, Path: ".env"}}, rules)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PermissionsFile), []byte(`{"rules":[{"action":"nope","command":"ls"}]}`), 0o644))
+	_, err = LoadPermissionRules(dir)
+	assert.ErrorContains(t, err, "rule 1")
+}
+
+func TestPermissionRulesCommandMatching(t *testing.T) {
+	p := &permissionRules{projectDir: "/proj", rules: []PermissionRule{
+		{Action: PermissionAllow, Command: "go test"},
+		{Action: PermissionDeny, Command: "git push"},
+		{Action: PermissionAsk, Command: "go test ./slow/..."},
+	}}
+
+	tests := []struct {
+		argv   []string
+		want   PermissionAction
+		wantOK bool
+	}{
+		{argv: []string{"go", "test", "./..."}, want: PermissionAllow, wantOK: true},
+		{argv: []string{"go", "test"}, want: PermissionAllow, wantOK: true},
+		{argv: []string{"go", "testx"}},
+		{argv: []string{"go", "test", "./slow/..."}, want: PermissionAsk, wantOK: true},
+		{argv: []string{"bash", "-c", "go test ./..."}, want: PermissionAllow, wantOK: true},
+		{argv: []string{"bash", "-c", "go test ./... && rm -rf /"}},
+		{argv: []string{"git", "push", "origin"}, want: PermissionDeny, wantOK: true},
+		{argv: []string{"bash", "-c", "make && git push"}, want: PermissionDeny, wantOK: true},
+	}
+	for _, tt := range tests {
+		rule, ok := p.commandRule(tt.argv)
+		assert.Equal(t, tt.wantOK, ok, "%v", tt.argv)
+		if tt.wantOK {
+			assert.Equal(t, tt.want, rule.Action, "%v", tt.argv)
+		}
+	}
+
+	var nilRules *permissionRules
+	_, ok := nilRules.commandRule([]string{"ls"})
+	assert.False(t, ok)
+}
+
+func TestPermissionRulesPathMatching(t *testing.T) {
+	proj := t.TempDir()
+	p := &permissionRules{projectDir: proj, rules: []PermissionRule{
+		{Action: PermissionDeny, Path: "secrets"},
+		{Action: PermissionAsk, Path: "*.pem"},
+		{Action: PermissionAllow, Tool: "write_file", Path: "docs/*.md"},
+		{Action: PermissionAllow, Path: "/etc/hosts"},
+	}}
+
+	tests := []struct {
+		tool   string
+		path   string
+		want   PermissionAction
+		wantOK bool
+	}{
+		{tool: "read_file", path: filepath.Join(proj, "secrets"), want: PermissionDeny, wantOK: true},
+		{tool: "read_file", path: filepath.Join(proj, "secrets", "a", "b.txt"), want: PermissionDeny, wantOK: true},
+		{tool: "read_file", path: filepath.Join(proj, "keys", "server.pem"), want: PermissionAsk, wantOK: true},
+		{tool: "write_file", path: filepath.Join(proj, "docs", "intro.md"), want: PermissionAllow, wantOK: true},
+		{tool: "read_file", path: filepath.Join(proj, "docs", "intro.md")},
+		{tool: "read_file", path: "/etc/hosts", want: PermissionAllow, wantOK: true},
+		{tool: "read_file", path: filepath.Join(proj, "main.go")},
+	}
+	for _, tt := range tests {
+		rule, ok := p.pathRule(tt.tool, tt.path)
+		assert.Equal(t, tt.wantOK, ok, "%s %s", tt.tool, tt.path)
+		if tt.wantOK {
+			assert.Equal(t, tt.want, rule.Action, "%s %s", tt.tool, tt.path)
+		}
+	}
+}
+
+func TestSessionAuthorizerAppliesPermissionRules(t *testing.T) {
+	sandbox := t.TempDir()
+	require.NoError(t, SavePermissionRules(sandbox, []PermissionRule{
+		{Action: PermissionDeny, Path: ".env"},
+		{Action: PermissionAsk, Tool: "write_file", Path: "go.mod"},
+		{Action: PermissionAllow, Command: "npm install"},
+		{Action: PermissionDeny, Command: "git push"},
+	}))
+
+	commands := NewShellAllowedCommands()
+	commands.AddDangerous(CommandMatcher{Command: "npm"})
+	auth, requests, err := NewSessionAuthorizer(sandbox, commands, false)
+	require.NoError(t, err)
+	defer auth.Close()
+
+	assert.ErrorContains(t, auth.IsAuthorizedForRead(false, "", "read_file", filepath.Join(sandbox, ".env")), PermissionsFile)
+	assert.NoError(t, auth.IsShellAuthorized(false, "", sandbox, []string{"npm", "install"}))
+	assert.ErrorContains(t, auth.IsShellAuthorized(false, "", sandbox, []string{"git", "push"}), "denied by rule")
+	assert.NoError(t, auth.IsAuthorizedForWrite(false, "", "write_file", filepath.Join(sandbox, "main.go")))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- auth.IsAuthorizedForWrite(false, "", "write_file", filepath.Join(sandbox, "go.mod"))
+	}()
+	req := <-requests
+	assert.Contains(t, req.Prompt, "go.mod")
+	assert.Nil(t, req.AlwaysAllow, "an allow rule can't override an ask rule")
+	req.Allow()
+	require.NoError(t, <-done)
+}
+
+func TestSessionAuthorizerRejectsInvalidPermissionsFile(t *testing.T) {
+	sandbox := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(sandbox, ".codalotl"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(sandbox, PermissionsFile), []byte("{"), 0o644))
+
+	_, _, err := NewSessionAuthorizer(sandbox, nil, false)
+	assert.Error(t, err)
+
+	_, _, err = NewSessionAuthorizer(sandbox, nil, true)
+	assert.NoError(t, err)
+}
+
+func TestUserRequestAlwaysAllowSavesRule(t *testing.T) {
+	sandbox := t.TempDir()
+	commands := NewShellAllowedCommands()
+	commands.AddDangerous(CommandMatcher{Command: "npm"})
+	auth, requests, err := NewSessionAuthorizer(sandbox, commands, false)
+	require.NoError(t, err)
+	defer auth.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- auth.IsShellAuthorized(false, "", sandbox, []string{"npm", "install"})
+	}()
+	req := <-requests
+	require.NotNil(t, req.AlwaysAllow)
+	require.NoError(t, req.AlwaysAllow())
+	require.NoError(t, <-done)
+
+	rules, err := LoadPermissionRules(sandbox)
+	require.NoError(t, err)
+	assert.Equal(t, []PermissionRule{{Action: PermissionAllow, Command: "npm install", Exact: true}}, rules)
+
+	// The new rule is active immediately; no prompt is needed.
+	assert.NoError(t, auth.IsShellAuthorized(false, "", sandbox, []string{"npm", "install"}))
+
+	// Path rules are saved relative to the project dir.
+	outside := t.TempDir()
+	go func() {
+		done <- auth.IsAuthorizedForWrite(false, "", "write_file", filepath.Join(sandbox, "gen", "out.txt"), filepath.Join(outside, "x"))
+	}()
+	req = <-requests
+	require.NotNil(t, req.AlwaysAllow)
+	require.NoError(t, req.AlwaysAllow())
+	require.NoError(t, <-done)
+
+	rules, err = LoadPermissionRules(sandbox)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, PermissionRule{Action: PermissionAllow, Tool: "write_file", Path: filepath.Join(outside, "x")}, rules[1])
+}
+
+func TestAlwaysAllowedCommandMatchesExactly(t *testing.T) {
+	sandbox := t.TempDir()
+	commands := NewShellAllowedCommands()
+	commands.AddDangerous(CommandMatcher{Command: "rm"})
+	auth, requests, err := NewSessionAuthorizer(sandbox, commands, false)
+	require.NoError(t, err)
+	defer auth.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- auth.IsShellAuthorized(false, "", sandbox, []string{"rm", "-rf", "build"})
+	}()
+	req := <-requests
+	require.NotNil(t, req.AlwaysAllow)
+	require.NoError(t, req.AlwaysAllow())
+	require.NoError(t, <-done)
+
+	assert.NoError(t, auth.IsShellAuthorized(false, "", sandbox, []string{"rm", "-rf", "build"}))
+
+	// A longer command starting with the approved one still prompts.
+	go func() {
+		done <- auth.IsShellAuthorized(false, "", sandbox, []string{"rm", "-rf", "build", "/"})
+	}()
+	req = <-requests
+	assert.Contains(t, req.Prompt, "rm -rf build /")
+	req.Disallow()
+	assert.ErrorIs(t, <-done, ErrAuthorizationDenied)
+}
+
+func TestPermissionsFileWritesAlwaysAsk(t *testing.T) {
+	sandbox := t.TempDir()
+	require.NoError(t, SavePermissionRules(sandbox, []PermissionRule{
+		{Action: PermissionAllow, Tool: "write_file"},
+		{Action: PermissionAllow, Path: ".codalotl"},
+	}))
+	auth, requests, err := NewSessionAuthorizer(sandbox, nil, false)
+	require.NoError(t, err)
+	defer auth.Close()
+
+	permissionsPath := filepath.Join(sandbox, PermissionsFile)
+	assert.NoError(t, auth.IsAuthorizedForWrite(false, "", "write_file", filepath.Join(sandbox, "main.go")))
+
+	for _, path := range []string{permissionsPath, filepath.Dir(permissionsPath)} {
+		done := make(chan error, 1)
+		go func() {
+			done <- auth.IsAuthorizedForWrite(false, "", "write_file", path)
+		}()
+		req := <-requests
+		assert.Contains(t, req.Prompt, path)
+		assert.Nil(t, req.AlwaysAllow, "writes to the permissions file can't be always-allowed")
+		req.Disallow()
+		assert.ErrorIs(t, <-done, ErrAuthorizationDenied)
+	}
+
+	// Deny rules still deny.
+	require.NoError(t, SetPermissionRules(auth, sandbox, []PermissionRule{{Action: PermissionDeny, Path: PermissionsFile}}))
+	assert.ErrorContains(t, auth.IsAuthorizedForWrite(false, "", "write_file", permissionsPath), "denied by rule")
+
+	// AutoApprove has no one to ask, so it denies.
+	autoApprove := NewAutoApproveAuthorizer(sandbox)
+	assert.Error(t, autoApprove.IsAuthorizedForWrite(false, "", "write_file", permissionsPath))
+	assert.NoError(t, autoApprove.IsAuthorizedForWrite(false, "", "write_file", filepath.Join(sandbox, "main.go")))
+}
+
+func TestPermissionsFileShellWritesAlwaysAsk(t *testing.T) {
+	sandbox := t.TempDir()
+	require.NoError(t, SavePermissionRules(sandbox, []PermissionRule{{Action: PermissionAllow, Command: "cp"}}))
+	commands := NewShellAllowedCommands()
+	commands.AddSafe(CommandMatcher{Command: "tee"})
+	auth, requests, err := NewSessionAuthorizer(sandbox, commands, false)
+	require.NoError(t, err)
+	defer auth.Close()
+
+	assert.NoError(t, auth.IsShellAuthorized(false, "", sandbox, []string{"cp", "a.go", "b.go"}))
+
+	for _, command := range [][]string{
+		{"cp", "rules.json", PermissionsFile},
+		{"tee", filepath.Join(sandbox, PermissionsFile)},
+		{"sh", "-c", `echo '{"rules":[]}' > .codalotl/permissions.json`},
+		{"mv", "rules.json", ".codalotl/"},
+	} {
+		done := make(chan error, 1)
+		go func() {
+			done <- auth.IsShellAuthorized(false, "", sandbox, command)
+		}()
+		req := <-requests
+		assert.Nil(t, req.AlwaysAllow, "commands that mention the permissions file can't be always-allowed")
+		req.Disallow()
+		assert.ErrorIs(t, <-done, ErrAuthorizationDenied, command)
+	}
+
+	// AutoApprove has no one to ask, so it denies.
+	autoApprove := NewAutoApproveAuthorizer(sandbox)
+	assert.ErrorContains(t, autoApprove.IsShellAuthorized(false, "", sandbox, []string{"cp", "rules.json", PermissionsFile}), "denied under auto-approve")
+	assert.NoError(t, autoApprove.IsShellAuthorized(false, "", sandbox, []string{"cp", "a.go", "b.go"}))
+}
+
+func TestSetPermissionRulesAutoApprove(t *testing.T) {
+	sandbox := t.TempDir()
+	err := SetPermissionRules(NewAutoApproveAuthorizer(sandbox), sandbox, nil)
+	assert.ErrorIs(t, err, ErrAuthorizerCannotAcceptPermissions)
+}
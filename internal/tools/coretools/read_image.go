@@ -0,0 +1,140 @@
+package coretools
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"github.com/codalotl/codalotl/internal/llmstream"
+	"github.com/codalotl/codalotl/internal/tools/authdomain"
+	"strings"
+)
+
+//go:embed read_image.md
+var descriptionReadImage string
+
+// A toolReadImage implements the read_image tool for returning authorized image files to the model.
+type toolReadImage struct {
+	sandboxAbsDir string                // This is the absolute sandbox root used to resolve relative image paths.
+	authorizer    authdomain.Authorizer // This authorizes read requests before files are opened.
+}
+
+// ParamsReadImage contains the JSON arguments for the read_image tool.
+type ParamsReadImage struct {
+	Path              string `json:"path"`               // Path is the image file to read. Relative paths are resolved from the sandbox root.
+	RequestPermission bool   `json:"request_permission"` // RequestPermission asks for approval to read the file when policy requires it.
+}
+
+const (
+	ToolNameReadImage = "read_image" // ToolNameReadImage is the registered name of the read_image tool.
+)
+
+// NewReadImageTool returns a read_image tool that reads authorized image files resolved relative to authorizer's sandbox. The authorizer must be non-nil.
+func NewReadImageTool(authorizer authdomain.Authorizer) llmstream.Tool {
+	sandboxAbsDir := authorizer.SandboxDir()
+	return &toolReadImage{
+		sandboxAbsDir: sandboxAbsDir,
+		authorizer:    authorizer,
+	}
+}
+
+// Name returns ToolNameReadImage, the registered name of the read_image tool.
+func (t *toolReadImage) Name() string {
+	return ToolNameReadImage
+}
+
+// Presenter returns the semantic presenter for read_image tool calls and results.
+func (t *toolReadImage) Presenter() llmstream.Presenter {
+	return readImagePresenterInstance
+}
+
+// Info returns the tool metadata for read_image, including its embedded description and JSON parameters. The returned schema requires path and accepts request_permission
+// for authorization escalation.
+func (t *toolReadImage) Info() llmstream.ToolInfo {
+	return llmstream.ToolInfo{
+		Name:        ToolNameReadImage,
+		Description: strings.TrimSpace(descriptionReadImage),
+		Parameters: map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "The path of the image to read (absolute, or relative to **sandbox** dir)",
+			},
+			"request_permission": map[string]any{
+				"type":        "boolean",
+				"description": "Optionally request the user's permission to run this command. Set to true for material access outside sandbox dir",
+			},
+		},
+		Required: []string{"path"},
+	}
+}
+
+// Run executes a read_image tool call. It parses ParamsReadImage from call.Input, requires an existing file path, authorizes the read, and returns the image in
+// ToolResult.Images with an `<image>` text summary (name, media type, dimensions, and byte count). Parameter, path, authorization, I/O, unsupported-format, and
+// size-limit failures are returned as error tool results.
+func (t *toolReadImage) Run(ctx context.Context, call llmstream.ToolCall) llmstream.ToolResult {
+	var params ParamsReadImage
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewToolErrorResult(call, fmt.Sprintf("error parsing parameters: %s", err), err)
+	}
+
+	if strings.TrimSpace(params.Path) == "" {
+		return llmstream.NewErrorToolResult("path is required", call)
+	}
+
+	absPath, relPath, normErr := NormalizePath(params.Path, t.sandboxAbsDir, WantPathTypeFile, true)
+	if normErr != nil {
+		return NewToolErrorResult(call, normErr.Error(), normErr)
+	}
+
+	if t.authorizer != nil {
+		if authErr := t.authorizer.IsAuthorizedForRead(params.RequestPermission, "", ToolNameReadImage, absPath); authErr != nil {
+			return NewToolErrorResult(call, authErr.Error(), authErr)
+		}
+	}
+
+	relToSandbox := relPath
+	if relToSandbox == "" {
+		relToSandbox = absPath
+	}
+
+	img, err := llmstream.ReadImageFile(absPath)
+	if err != nil {
+		return NewToolErrorResult(call, err.Error(), err)
+	}
+
+	result := fmt.Sprintf("<image name=%q media-type=%q width=\"%d\" height=\"%d\" byte-count=\"%d\" />\n", relToSandbox, img.MediaType, img.Width, img.Height, len(img.Data))
+	return llmstream.ToolResult{CallID: call.CallID, Name: call.Name, Type: call.Type, Result: result, Images: []llmstream.ImageContent{img}}
+}
+
+var readImagePresenterInstance llmstream.Presenter = readImagePresenter{}
+
+// A readImagePresenter presents read_image tool calls as replacement summaries in the form "Read image <path>".
+type readImagePresenter struct{}
+
+// Present returns a replacement presentation with the summary "Read image <path>" for a read_image tool call. It uses the requested path when available and falls
+// back to the call or tool name; result is ignored.
+func (p readImagePresenter) Present(call llmstream.ToolCall, result *llmstream.ToolResult) llmstream.Presentation {
+	_ = result
+
+	target := strings.TrimSpace(call.Name)
+	if target == "" {
+		target = ToolNameReadImage
+	}
+	var params ParamsReadImage
+	if err := json.Unmarshal([]byte(call.Input), &params); err == nil {
+		if path := strings.TrimSpace(params.Path); path != "" {
+			target = path
+		}
+	}
+
+	return llmstream.Presentation{
+		Behavior: llmstream.CompletionBehaviorReplace,
+		Summary: llmstream.Line{
+			JoinWithSpace: true,
+			Segments: []llmstream.Segment{
+				{Text: "Read image", Role: llmstream.RoleAction},
+				{Text: target, Role: llmstream.RoleNormal},
+			},
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package coretools
+
+import (
+	"bytes"
+	"context"
+	"github.com/codalotl/codalotl/internal/llmstream"
+	"github.com/codalotl/codalotl/internal/tools/authdomain"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadImage_ReturnsImage(t *testing.T) {
+	sandbox := t.TempDir()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 6, 4))))
+	require.NoError(t, os.WriteFile(filepath.Join(sandbox, "shot.png"), buf.Bytes(), 0o644))
+
+	tool := NewReadImageTool(authdomain.NewAutoApproveAuthorizer(sandbox))
+	call := llmstream.ToolCall{CallID: "call1", Name: ToolNameReadImage, Type: "function_call", Input: `{"path":"shot.png"}`}
+
+	res := tool.Run(context.Background(), call)
+	require.False(t, res.IsError, res.Result)
+	require.Len(t, res.Images, 1)
+	assert.Equal(t, llmstream.ImageMediaTypePNG, res.Images[0].MediaType)
+	assert.Equal(t, buf.Bytes(), res.Images[0].Data)
+
+	assert.Equal(t, "<image name=\"shot.png\" media-type=\"image/png\" width=\"6\" height=\"4\" byte-count=\""+strconv.Itoa(buf.Len())+"\" />\n", res.Result)
+}
+
+func TestReadImage_RejectsNonImage(t *testing.T) {
+	sandbox := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(sandbox, "notes.txt"), []byte("hello"), 0o644))
+
+	tool := NewReadImageTool(authdomain.NewAutoApproveAuthorizer(sandbox))
+	call := llmstream.ToolCall{CallID: "call1", Name: ToolNameReadImage, Type: "function_call", Input: `{"path":"notes.txt"}`}
+
+	res := tool.Run(context.Background(), call)
+	assert.True(t, res.IsError)
+	assert.Contains(t, res.Result, "unsupported image type")
+	assert.Empty(t, res.Images)
+}
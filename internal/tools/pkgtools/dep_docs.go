@@ -0,0 +1,267 @@
+package pkgtools
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/gocodecontext"
+	"github.com/codalotl/codalotl/internal/llmstream"
+	"github.com/codalotl/codalotl/internal/tools/authdomain"
+	"github.com/codalotl/codalotl/internal/tools/coretools"
+)
+
+//go:embed dep_docs.md
+var descriptionDepDocs string
+
+// ToolNameDepDocs is the registered name of the dep_docs tool.
+const ToolNameDepDocs = "dep_docs"
+
+// maxDepDocsReadmeBytes caps the README content included in a dep_docs result.
+const maxDepDocsReadmeBytes = 16 * 1024
+
+// The toolDepDocs type implements the dep_docs tool by returning documentation, examples, and READMEs for dependency packages, or searching dependency symbols.
+type toolDepDocs struct {
+	sandboxAbsDir string                // The sandbox root is used to locate the Go module and resolve import paths.
+	authorizer    authdomain.Authorizer // The authorizer controls reads of packages inside the sandbox.
+}
+
+// The depDocsParams type contains JSON parameters for the dep_docs tool. Exactly one of Path and Search is required.
+type depDocsParams struct {
+	Path        string   `json:"path"`        // Path is the import path of the package to document.
+	Identifiers []string `json:"identifiers"` // Identifiers optionally restrict the API and examples to specific identifiers.
+	Search      string   `json:"search"`      // Search is a regexp matched against exported symbols of dependency packages, direct and indirect.
+}
+
+var depDocsPresenterInstance llmstream.Presenter = depDocsPresenter{}
+
+// The depDocsPresenter type formats dep_docs tool summaries and requested identifiers.
+type depDocsPresenter struct{}
+
+// NewDepDocsTool returns a dep_docs tool that resolves import paths from the authorizer sandbox's module. Dependency and standard library packages are always readable
+// (they are shared Go deps); packages resolved inside the sandbox are authorized with authorizer.
+//
+// The authorizer must be non-nil.
+func NewDepDocsTool(authorizer authdomain.Authorizer) llmstream.Tool {
+	return &toolDepDocs{
+		sandboxAbsDir: authorizer.SandboxDir(),
+		authorizer:    authorizer,
+	}
+}
+
+// Name returns the registered tool name, "dep_docs".
+func (t *toolDepDocs) Name() string {
+	return ToolNameDepDocs
+}
+
+// Presenter returns the presenter that formats dep_docs calls and results.
+func (t *toolDepDocs) Presenter() llmstream.Presenter {
+	return depDocsPresenterInstance
+}
+
+// Present returns the dep_docs presentation for call. The presentation replaces prior progress. Package lookups present as "Read Dep Docs some/pkg" with requested
+// identifiers in the body; searches present as "Search Dep Docs <regexp>".
+func (p depDocsPresenter) Present(call llmstream.ToolCall, result *llmstream.ToolResult) llmstream.Presentation {
+	var params depDocsParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return pkgToolReplaceSummaryPresentation(pkgToolPresenterFallbackSummary(call))
+	}
+
+	if search := strings.TrimSpace(params.Search); search != "" && strings.TrimSpace(params.Path) == "" {
+		return pkgToolReplaceSummaryPresentation(pkgToolActionSummary("Search Dep Docs", llmstream.Segment{Text: search, Role: llmstream.RoleNormal}))
+	}
+
+	path := strings.TrimSpace(params.Path)
+	if path == "" {
+		return pkgToolReplaceSummaryPresentation(pkgToolActionSummary("Read Dep Docs"))
+	}
+	presentation := pkgToolReplaceSummaryPresentation(pkgToolActionSummary("Read Dep Docs", llmstream.Segment{Text: path, Role: llmstream.RoleNormal}))
+	if identifiers := trimmedNonEmpty(params.Identifiers); len(identifiers) > 0 {
+		presentation.Body = llmstream.Output{Lines: []string{strings.Join(identifiers, ", ")}}
+	}
+	return presentation
+}
+
+// Info returns the LLM-facing metadata for the dep_docs tool.
+func (t *toolDepDocs) Info() llmstream.ToolInfo {
+	return llmstream.ToolInfo{
+		Name:        ToolNameDepDocs,
+		Description: strings.TrimSpace(descriptionDepDocs),
+		Parameters: map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "A Go import path of a dependency or standard library package. Omit when using search.",
+			},
+			"identifiers": map[string]any{
+				"type":        "array",
+				"description": "Optionally, supply specific identifiers to fetch docs and examples for.",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"search": map[string]any{
+				"type":        "string",
+				"description": "Go RE2 regexp to search exported symbols across dependency packages, direct and indirect. Omit when using path.",
+			},
+		},
+	}
+}
+
+// Run executes the dep_docs tool call. With path, it returns the package's public API documentation, examples, and README (README only without identifiers). With
+// search, it returns matching exported symbols of dependency packages. Run returns an error tool result for invalid input, packages in the current module,
+// resolution or authorization failures, and documentation errors.
+func (t *toolDepDocs) Run(ctx context.Context, call llmstream.ToolCall) llmstream.ToolResult {
+	var params depDocsParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return coretools.NewToolErrorResult(call, fmt.Sprintf("error parsing parameters: %s", err), err)
+	}
+	params.Path = strings.TrimSpace(params.Path)
+	params.Search = strings.TrimSpace(params.Search)
+
+	switch {
+	case params.Path != "" && params.Search != "":
+		return llmstream.NewErrorToolResult("supply either path or search, not both", call)
+	case params.Path == "" && params.Search == "":
+		return llmstream.NewErrorToolResult("path or search is required", call)
+	}
+
+	mod, err := gocode.NewModule(t.sandboxAbsDir)
+	if err != nil {
+		return coretools.NewToolErrorResult(call, err.Error(), err)
+	}
+
+	var result string
+	if params.Search != "" {
+		_, result, err = gocodecontext.SearchDependencySymbols(ctx, mod.AbsolutePath, params.Search)
+	} else {
+		result, err = t.packageDocs(ctx, mod, params.Path, trimmedNonEmpty(params.Identifiers))
+	}
+	if err != nil {
+		return coretools.NewToolErrorResult(call, err.Error(), err)
+	}
+
+	return llmstream.ToolResult{
+		CallID: call.CallID,
+		Name:   call.Name,
+		Type:   call.Type,
+		Result: result,
+	}
+}
+
+// packageDocs resolves path from mod and renders its API, examples, and (without identifiers) README. It returns ctx's error if ctx is canceled before the package
+// is loaded.
+func (t *toolDepDocs) packageDocs(ctx context.Context, mod *gocode.Module, path string, identifiers []string) (string, error) {
+	res, err := resolveToolPackageRef(mod, path)
+	if err != nil {
+		return "", err
+	}
+	if res.ModuleAbsDir == mod.AbsolutePath {
+		return "", fmt.Errorf("path %q resolves to %q, which is in the current module; use %s instead", path, res.ImportPath, ToolNameGetPublicAPI)
+	}
+	if t.authorizer != nil && res.isWithinSandbox(t.sandboxAbsDir) {
+		// Only prompt/deny for sandbox reads (ex: a replaced module in the sandbox); dependency/stdlib packages are always readable.
+		if authErr := t.authorizer.IsAuthorizedForRead(false, "", ToolNameDepDocs, res.PackageAbsDir); authErr != nil {
+			return "", authErr
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	pkg, err := loadPackageForResolved(mod, res.ModuleAbsDir, res.PackageAbsDir, res.PackageRelDir, res.ImportPath)
+	if err != nil {
+		return "", err
+	}
+
+	api, err := gocodecontext.PublicPackageDocumentation(pkg, identifiers...)
+	if err != nil {
+		return "", err
+	}
+	examples, err := gocodecontext.PackageExamples(pkg, identifiers...)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Package %s (%s)\n", res.ImportPath, res.PackageAbsDir)
+
+	b.WriteString("\n--- Public API ---\n\n")
+	if strings.TrimSpace(api) == "" {
+		b.WriteString("(no matching public API)\n")
+	} else {
+		b.WriteString(strings.TrimSpace(api) + "\n")
+	}
+
+	if strings.TrimSpace(examples) != "" {
+		b.WriteString("\n--- Examples ---\n\n")
+		b.WriteString(strings.TrimSpace(examples) + "\n")
+	}
+
+	if len(identifiers) == 0 {
+		if readmePath, readme, ok := findDepReadme(res.PackageAbsDir, res.ModuleAbsDir); ok {
+			fmt.Fprintf(&b, "\n--- README (%s) ---\n\n", readmePath)
+			b.WriteString(readme)
+		}
+	}
+	return b.String(), nil
+}
+
+// findDepReadme returns the README in packageAbsDir or, failing that, in moduleAbsDir (when non-empty). The README content is capped at maxDepDocsReadmeBytes with
+// a truncation note. ok is false when neither directory has a readable README.
+func findDepReadme(packageAbsDir string, moduleAbsDir string) (readmePath string, content string, ok bool) {
+	dirs := []string{packageAbsDir}
+	if moduleAbsDir != "" && moduleAbsDir != packageAbsDir {
+		dirs = append(dirs, moduleAbsDir)
+	}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		var names []string
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && strings.HasPrefix(strings.ToLower(entry.Name()), "readme") {
+				names = append(names, entry.Name())
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		// Prefer README.md over README.txt and other variants.
+		sort.Slice(names, func(i, j int) bool {
+			mi, mj := strings.EqualFold(filepath.Ext(names[i]), ".md"), strings.EqualFold(filepath.Ext(names[j]), ".md")
+			if mi != mj {
+				return mi
+			}
+			return names[i] < names[j]
+		})
+		readmePath = filepath.Join(dir, names[0])
+		data, err := os.ReadFile(readmePath)
+		if err != nil {
+			continue
+		}
+		content = strings.TrimSpace(string(data)) + "\n"
+		if len(data) > maxDepDocsReadmeBytes {
+			content = strings.ToValidUTF8(string(data[:maxDepDocsReadmeBytes]), "") + fmt.Sprintf("\n... README truncated (%d of %d bytes shown)\n", maxDepDocsReadmeBytes, len(data))
+		}
+		return readmePath, content, true
+	}
+	return "", "", false
+}
+
+// trimmedNonEmpty returns values with surrounding whitespace trimmed and empty values removed.
+func trimmedNonEmpty(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package pkgtools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/gocode"
+	"github.com/codalotl/codalotl/internal/llmstream"
+	"github.com/codalotl/codalotl/internal/tools/authdomain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepDocs_RunStdlibWithExamples(t *testing.T) {
+	withSimplePackage(t, func(pkg *gocode.Package) {
+		tool := NewDepDocsTool(authdomain.NewAutoApproveAuthorizer(pkg.Module.AbsolutePath))
+		res := tool.Run(context.Background(), llmstream.ToolCall{
+			CallID: "call-stdlib",
+			Name:   ToolNameDepDocs,
+			Type:   "function_call",
+			Input:  `{"path":"strings","identifiers":["ToUpper"]}`,
+		})
+		require.False(t, res.IsError, res.Result)
+		assert.Contains(t, res.Result, "--- Public API ---")
+		assert.Contains(t, res.Result, "func ToUpper(s string) string")
+		assert.Contains(t, res.Result, "--- Examples ---")
+		assert.Contains(t, res.Result, "func ExampleToUpper() {")
+		assert.NotContains(t, res.Result, "--- README")
+	})
+}
+
+func TestDepDocs_RunDependencyIncludesModuleReadme(t *testing.T) {
+	_, thisFile, _, ok := runtime.Caller(0)
+	require.True(t, ok)
+	mod, err := gocode.NewModule(thisFile)
+	require.NoError(t, err)
+
+	tool := NewDepDocsTool(authdomain.NewAutoApproveAuthorizer(mod.AbsolutePath))
+	res := tool.Run(context.Background(), llmstream.ToolCall{
+		CallID: "call-dep",
+		Name:   ToolNameDepDocs,
+		Type:   "function_call",
+		Input:  `{"path":"github.com/stretchr/testify/require"}`,
+	})
+	require.False(t, res.IsError, res.Result)
+	assert.Contains(t, res.Result, "Package github.com/stretchr/testify/require (")
+	assert.Contains(t, res.Result, "func Equal(")
+	assert.Contains(t, res.Result, "--- README (")
+}
+
+func TestDepDocs_RunRejectsCurrentModulePackage(t *testing.T) {
+	withSimplePackage(t, func(pkg *gocode.Package) {
+		tool := NewDepDocsTool(authdomain.NewAutoApproveAuthorizer(pkg.Module.AbsolutePath))
+		res := tool.Run(context.Background(), llmstream.ToolCall{
+			CallID: "call-local",
+			Name:   ToolNameDepDocs,
+			Type:   "function_call",
+			Input:  fmt.Sprintf(`{"path":%q}`, pkg.Module.Name+"/mypkg"),
+		})
+		assert.True(t, res.IsError)
+		assert.Contains(t, res.Result, ToolNameGetPublicAPI)
+	})
+}
+
+func TestDepDocs_RunValidatesParams(t *testing.T) {
+	tool := NewDepDocsTool(authdomain.NewAutoApproveAuthorizer(t.TempDir()))
+	for _, input := range []string{`{}`, `{"path":"strings","search":"x"}`, `not json`} {
+		res := tool.Run(context.Background(), llmstream.ToolCall{CallID: "call", Name: ToolNameDepDocs, Input: input})
+		assert.True(t, res.IsError, input)
+	}
+}
+
+func TestDepDocs_RunSearch(t *testing.T) {
+	root := t.TempDir()
+	mainDir := filepath.Join(root, "main")
+	depDir := filepath.Join(root, "dep")
+	writeDepDocsFile(t, filepath.Join(depDir, "go.mod"), "module example.com/dep\n\ngo 1.20\n")
+	writeDepDocsFile(t, filepath.Join(depDir, "widget", "widget.go"), "package widget\n\n// NewWidget makes a widget.\nfunc NewWidget() {}\n")
+	writeDepDocsFile(t, filepath.Join(mainDir, "go.mod"), "module example.com/main\n\ngo 1.20\n\nrequire example.com/dep v0.0.0\n\nreplace example.com/dep => ../dep\n")
+	writeDepDocsFile(t, filepath.Join(mainDir, "main.go"), "package main\n\nfunc main() {}\n")
+
+	tool := NewDepDocsTool(authdomain.NewAutoApproveAuthorizer(mainDir))
+	res := tool.Run(context.Background(), llmstream.ToolCall{CallID: "call-search", Name: ToolNameDepDocs, Input: `{"search":"(?i)^new"}`})
+	require.False(t, res.IsError, res.Result)
+	assert.Contains(t, res.Result, "example.com/dep/widget:\n- func NewWidget")
+}
+
+func TestFindDepReadme(t *testing.T) {
+	moduleDir := t.TempDir()
+	pkgDir := filepath.Join(moduleDir, "sub")
+	writeDepDocsFile(t, filepath.Join(moduleDir, "README.txt"), "txt")
+	writeDepDocsFile(t, filepath.Join(moduleDir, "readme.md"), "# Module\n")
+	writeDepDocsFile(t, filepath.Join(pkgDir, "sub.go"), "package sub\n")
+
+	path, content, ok := findDepReadme(pkgDir, moduleDir)
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(moduleDir, "readme.md"), path)
+	assert.Equal(t, "# Module\n", content)
+
+	writeDepDocsFile(t, filepath.Join(pkgDir, "README.md"), strings.Repeat("x", maxDepDocsReadmeBytes+10))
+	path, content, ok = findDepReadme(pkgDir, moduleDir)
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(pkgDir, "README.md"), path)
+	assert.Contains(t, content, "README truncated")
+
+	_, _, ok = findDepReadme(t.TempDir(), "")
+	assert.False(t, ok)
+}
+
+func writeDepDocsFile(t *testing.T, path string, contents string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+}
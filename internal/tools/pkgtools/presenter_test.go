@@ -19,6 +19,7 @@ func TestReadOnlyTools_ExposePresenters(t *testing.T) {
 		NewGetPublicAPITool(auth),
 		NewGetUsageTool(auth),
 		NewModuleInfoTool(auth),
+		NewDepDocsTool(auth),
 		NewUpdateUsageTool(".", auth, nil, "", nil),
 	}
 
@@ -241,3 +242,38 @@ func TestModuleInfoPresenter(t *testing.T) {
 	assert.Equal(t, expectedBody, resultPresentation.Body)
 	assert.Equal(t, llmstream.ErrorBehaviorDefault, resultPresentation.ErrorBehavior)
 }
+
+func TestDepDocsPresenter(t *testing.T) {
+	presenter := NewDepDocsTool(authdomain.NewAutoApproveAuthorizer(t.TempDir())).Presenter()
+
+	require.NotNil(t, presenter)
+
+	readCall := llmstream.ToolCall{
+		Name:  ToolNameDepDocs,
+		Input: `{"path":"golang.org/x/mod/modfile","identifiers":["Parse"," File "]}`,
+	}
+	readPresentation := presenter.Present(readCall, nil)
+	assert.Equal(t, llmstream.CompletionBehaviorReplace, readPresentation.Behavior)
+	assert.Equal(t, llmstream.Line{
+		JoinWithSpace: true,
+		Segments: []llmstream.Segment{
+			{Text: "Read Dep Docs", Role: llmstream.RoleAction},
+			{Text: "golang.org/x/mod/modfile", Role: llmstream.RoleNormal},
+		},
+	}, readPresentation.Summary)
+	assert.Equal(t, llmstream.Output{Lines: []string{"Parse, File"}}, readPresentation.Body)
+
+	searchCall := llmstream.ToolCall{
+		Name:  ToolNameDepDocs,
+		Input: `{"search":"^New"}`,
+	}
+	searchPresentation := presenter.Present(searchCall, nil)
+	assert.Equal(t, llmstream.Line{
+		JoinWithSpace: true,
+		Segments: []llmstream.Segment{
+			{Text: "Search Dep Docs", Role: llmstream.RoleAction},
+			{Text: "^New", Role: llmstream.RoleNormal},
+		},
+	}, searchPresentation.Summary)
+	assert.Nil(t, searchPresentation.Body)
+}
@@ -95,6 +95,9 @@ type refactorKind string
 const (
 	refactorKindDocsAdd                refactorKind = "docs-add"
 	refactorKindDocsFix                refactorKind = "docs-fix"
+	refactorKindDocsImprove            refactorKind = "docs-improve"
+	refactorKindDocsPolish             refactorKind = "docs-polish"
+	refactorKindDocsExamples           refactorKind = "docs-examples"
 	refactorKindDocsImproveFromClarify refactorKind = "docs-improve-from-clarify"
 	refactorKindPrompt                 refactorKind = "prompt"
 )
@@ -123,6 +126,24 @@ var refactorRegistry = []refactorConfig{
 		kind:        refactorKindDocsFix,
 		casPolicy:   casPolicyIgnore,
 	},
+	{
+		name:        "docs-improve",
+		description: "Rewrite existing Go documentation where an LLM judges a better version, with codalotl docs improve.",
+		kind:        refactorKindDocsImprove,
+		casPolicy:   casPolicyIgnore,
+	},
+	{
+		name:        "docs-polish",
+		description: "Make minimal style edits to existing Go documentation with codalotl docs polish.",
+		kind:        refactorKindDocsPolish,
+		casPolicy:   casPolicyIgnore,
+	},
+	{
+		name:        "docs-examples",
+		description: "Generate and verify runnable Example functions for important public APIs with codalotl docs examples.",
+		kind:        refactorKindDocsExamples,
+		casPolicy:   casPolicyIgnore,
+	},
 	{
 		name:        "docs-improve-from-clarify",
 		description: "Improve public Go documentation from clarify_public_api Q/A records.",
@@ -245,7 +266,13 @@ func (t refactorTool) Run(ctx context.Context, toolCall llmstream.ToolCall) llms
 	case refactorKindDocsAdd:
 		result, err = t.runDocsAdd(ctx, resolved, cfg)
 	case refactorKindDocsFix:
-		result, err = t.runDocsFix(ctx, resolved, cfg)
+		result, err = t.runDocsCLI(ctx, resolved, cfg, "fix")
+	case refactorKindDocsImprove:
+		result, err = t.runDocsCLI(ctx, resolved, cfg, "improve", "--yes")
+	case refactorKindDocsPolish:
+		result, err = t.runDocsCLI(ctx, resolved, cfg, "polish", "--yes")
+	case refactorKindDocsExamples:
+		result, err = t.runDocsCLI(ctx, resolved, cfg, "examples")
 	case refactorKindDocsImproveFromClarify:
 		result, err = t.runDocsImproveFromClarify(ctx, resolved, cfg)
 	case refactorKindPrompt:
@@ -373,13 +400,15 @@ func docsAddNoOpportunity(stdout string) bool {
 		!strings.Contains(stdout, "Applied ")
 }
 
-// runDocsFix runs the docs-fix refactor for resolved.
-func (t refactorTool) runDocsFix(ctx context.Context, resolved resolvedPackage, cfg refactorConfig) (Result, error) {
+// runDocsCLI runs a docs refactor for resolved that delegates to `codalotl docs <subcommand> [flags] <package>` and reports edited files. It is used by refactors
+// whose delegated command writes its own CAS record (docs-fix, docs-improve, docs-polish, docs-examples). flags are passed before the package (ex: "--yes" for
+// commands that otherwise ask for confirmation).
+func (t refactorTool) runDocsCLI(ctx context.Context, resolved resolvedPackage, cfg refactorConfig, subcommand string, flags ...string) (Result, error) {
 	if cfg.casPolicy != casPolicyIgnore {
-		return Result{}, fmt.Errorf("docs-fix refactor requires CAS policy %q", casPolicyIgnore)
+		return Result{}, fmt.Errorf("%s refactor requires CAS policy %q", cfg.name, casPolicyIgnore)
 	}
 	if t.options.NewCommandTree == nil {
-		return Result{}, errors.New("docs-fix refactor requires NewCommandTree")
+		return Result{}, fmt.Errorf("%s refactor requires NewCommandTree", cfg.name)
 	}
 
 	tracker, err := newDefaultGoCodeUnitChangeTracker(resolved.absDir)
@@ -390,7 +419,7 @@ func (t refactorTool) runDocsFix(ctx context.Context, resolved resolvedPackage,
 	cliTool := toolcli.NewCodalotlCLITool(t.options.NewCommandTree)
 	cliParams := toolcli.Params{
 		Subcommand: "docs",
-		Argv:       []string{"fix", resolved.absDir},
+		Argv:       append(append([]string{subcommand}, flags...), resolved.absDir),
 	}
 	input, err := json.Marshal(cliParams)
 	if err != nil {
@@ -398,7 +427,7 @@ func (t refactorTool) runDocsFix(ctx context.Context, resolved resolvedPackage,
 	}
 
 	cliResult := cliTool.Run(ctx, llmstream.ToolCall{
-		CallID: "refactor-docs-fix",
+		CallID: "refactor-" + cfg.name,
 		Name:   toolcli.ToolNameCodalotlCLI,
 		Type:   "function_call",
 		Input:  string(input),
@@ -412,7 +441,7 @@ func (t refactorTool) runDocsFix(ctx context.Context, resolved resolvedPackage,
 		return Result{}, err
 	}
 	if !parsed.Success {
-		msg := "codalotl docs fix failed"
+		msg := "codalotl docs " + subcommand + " failed"
 		if parsed.Stderr != "" {
 			msg = parsed.Stderr
 		} else if parsed.Stdout != "" {
@@ -35,6 +35,9 @@ func TestInfo(t *testing.T) {
 	assert.Contains(t, info.Description, "important")
 	assert.Contains(t, info.Description, "docs-fix")
 	assert.Contains(t, info.Description, "materially false")
+	assert.Contains(t, info.Description, "docs-improve")
+	assert.Contains(t, info.Description, "docs-polish")
+	assert.Contains(t, info.Description, "docs-examples")
 	assert.Contains(t, info.Description, "docs-improve-from-clarify")
 	assert.Contains(t, info.Description, "clarify_public_api")
 	assert.Contains(t, info.Description, "dry")
@@ -203,6 +206,49 @@ func TestDocsFixIgnoresRefactorCASRecord(t *testing.T) {
 	assert.Equal(t, []string{pkgDir}, captured.args)
 }
 
+func TestDocsImproveAndPolishDelegateToCodalotlCLI(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		subcommand string
+		edit       bool
+		file       string
+		wantStatus ResultStatus
+	}{
+		{name: "docs-improve", subcommand: "improve", edit: true, wantStatus: ResultStatusApplied},
+		{name: "docs-polish", subcommand: "polish", edit: true, wantStatus: ResultStatusApplied},
+		{name: "docs-polish", subcommand: "polish", wantStatus: ResultStatusNoOpportunity},
+		{name: "docs-examples", subcommand: "examples", edit: true, file: "example_test.go", wantStatus: ResultStatusApplied},
+	} {
+		t.Run(fmt.Sprintf("%s/edit=%v", tt.name, tt.edit), func(t *testing.T) {
+			moduleDir, pkgDir := newTestModule(t)
+			var captured docsFixCapture
+			tool := NewRefactorTool(authdomain.NewAutoApproveAuthorizer(moduleDir), Options{
+				NewCommandTree: docsSubcommandTreeFunc(tt.subcommand, &captured, func(c *qcli.Context) error {
+					if tt.edit && tt.file != "" {
+						if err := os.WriteFile(filepath.Join(pkgDir, tt.file), []byte("package foo_test\n\nfunc ExampleA() {}\n"), 0o644); err != nil {
+							return err
+						}
+					} else if tt.edit {
+						if err := os.WriteFile(filepath.Join(pkgDir, "foo.go"), []byte("package foo\n\n// A returns one.\nfunc A() int { return 1 }\n"), 0o644); err != nil {
+							return err
+						}
+					}
+					_, err := fmt.Fprint(c.Out, "Applied 0 documentation change(s).\n")
+					return err
+				}),
+			})
+
+			result := runRefactorTool(t, tool, Params{Name: tt.name, Package: "internal/foo"})
+
+			require.False(t, result.toolResult.IsError)
+			assert.Equal(t, tt.wantStatus, result.result.Status)
+			assert.Nil(t, result.result.SavedCASRecord)
+			assert.Equal(t, []string{pkgDir}, captured.args)
+			assert.Equal(t, tt.subcommand != "examples", captured.yes, "improve and polish must not wait for confirmation")
+		})
+	}
+}
+
 func TestDocsImproveFromClarifyNoRelevantEntriesSkipsAgent(t *testing.T) {
 	moduleDir, _ := newTestModule(t)
 	recordPath := newTestClarifyRecordFile(t, moduleDir)
@@ -1048,6 +1094,7 @@ func docsAddCommandTreeFunc(capture *docsAddCapture, run func(*qcli.Context) err
 
 type docsFixCapture struct {
 	args []string
+	yes  bool // yes is the --yes flag, which the improve and polish stubs accept.
 }
 
 func docsFixCommandTree(capture *docsFixCapture, stdout string) toolcli.CommandTreeFunc {
@@ -1068,16 +1115,25 @@ func docsFixEditingCommandTree(capture *docsFixCapture, pkgDir string) toolcli.C
 }
 
 func docsFixCommandTreeFunc(capture *docsFixCapture, run func(*qcli.Context) error) toolcli.CommandTreeFunc {
+	return docsSubcommandTreeFunc("fix", capture, run)
+}
+
+func docsSubcommandTreeFunc(subcommand string, capture *docsFixCapture, run func(*qcli.Context) error) toolcli.CommandTreeFunc {
 	return func() *qcli.Command {
 		root := &qcli.Command{Name: "codalotl"}
 		docs := &qcli.Command{Name: "docs"}
-		fix := &qcli.Command{Name: "fix"}
-		fix.Run = func(c *qcli.Context) error {
+		sub := &qcli.Command{Name: subcommand}
+		yes := new(bool)
+		if subcommand == "improve" || subcommand == "polish" {
+			yes = sub.Flags().Bool("yes", 'y', false, "Apply without confirmation.")
+		}
+		sub.Run = func(c *qcli.Context) error {
 			capture.args = append([]string(nil), c.Args...)
+			capture.yes = *yes
 			return run(c)
 		}
 		root.AddCommand(docs)
-		docs.AddCommand(fix)
+		docs.AddCommand(sub)
 		return root
 	}
 }
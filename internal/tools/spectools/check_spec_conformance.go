@@ -58,6 +58,12 @@ type toolCheckSpecConformance struct {
 	specDiffContext func(pkg *gocode.Package) (string, error)                   // specDiffContext computes extra SPEC diff context for a package; nil omits it.
 	heuristicBase   func(repoDir string) (commit string, ref string, err error) // heuristicBase selects the comparison base commit and parent ref.
 
+	// specExamples runs the {example} blocks of a package's SPEC.md; failed examples become nonconformances. nil skips them.
+	specExamples func(ctx context.Context, pkg *gocode.Package) ([]specmd.ExampleResult, error)
+
+	// specCoverage finds exported identifiers missing from a package's SPEC.md Public API, reported in uncovered_api. nil skips it.
+	specCoverage func(pkg *gocode.Package) ([]specmd.UncoveredAPI, error)
+
 	// changedPaths returns repository paths changed since the comparison base.
 	changedPaths func(repoDir string, baseCommit string, includeUncommitted bool) ([]string, error)
 
@@ -131,6 +137,9 @@ type packageCheckResult struct {
 
 	// PostcheckError reports package-scoped work that failed after a valid verdict was produced.
 	PostcheckError string `json:"postcheck_error,omitempty"`
+
+	// UncoveredAPI lists exported identifiers that the SPEC.md Public API doesn't declare (ex: "Foo.Bar (foo.go:12)"). It is informational and doesn't affect Conforms.
+	UncoveredAPI []string `json:"uncovered_api,omitempty"`
 }
 
 // The packageIssue type describes one SPEC.md nonconformance in a package-check result.
@@ -171,6 +180,7 @@ type CheckSpecConformancePostcheckError struct {
 type packageResultValidationOptions struct {
 	allowError          bool  // AllowError permits an error-only package result instead of a verdict.
 	allowPostcheckError bool  // AllowPostcheckError permits postcheck_error to accompany a valid verdict.
+	allowUncoveredAPI   bool  // AllowUncoveredAPI permits uncovered_api to accompany a valid verdict.
 	requireAnalysis     bool  // RequireAnalysis requires every nonconformance to include analysis text.
 	hasDiff             *bool // HasDiff, when non-nil, reports package diff presence and marks all issues latent when false.
 }
@@ -206,6 +216,8 @@ func NewCheckSpecConformanceTool(authorizer authdomain.Authorizer, options ...Ch
 		maxConcurrency:             option.MaxConcurrency,
 		git:                        execGitRunner{},
 		specDiffContext:            computeSpecDiffContext,
+		specExamples:               runSpecExamples,
+		specCoverage:               computeSpecCoverage,
 		heuristicBase:              gittools.HeuristicMergeBase,
 		changedPaths:               gittools.ChangedPathsSince,
 		subAgentCreatorFromContext: subAgentCreatorFromContextSafe,
@@ -534,6 +546,19 @@ func (t *toolCheckSpecConformance) checkPackage(ctx context.Context, moduleAbsDi
 		}
 	}
 
+	var failedExamples []specmd.ExampleResult
+	if t.specExamples != nil {
+		examples, err := t.specExamples(ctx, pkg.Package)
+		if err != nil {
+			return packageErrorResult(fmt.Errorf("run SPEC.md examples: %w", err))
+		}
+		for _, ex := range examples {
+			if !ex.Passed {
+				failedExamples = append(failedExamples, ex)
+			}
+		}
+	}
+
 	answer, err := t.runPackageCheck(ctx, packageCheckRequest{
 		Key:            pkg.Key,
 		Package:        pkg.Package,
@@ -550,6 +575,17 @@ func (t *toolCheckSpecConformance) checkPackage(ctx context.Context, moduleAbsDi
 	if err != nil {
 		return packageErrorResult(err)
 	}
+	result = addFailedExampleNonconformances(result, failedExamples, pkg.HasDiff)
+
+	if t.specCoverage != nil && result.Conforms != nil {
+		uncovered, err := t.specCoverage(pkg.Package)
+		if err != nil {
+			result.PostcheckError = appendPackagePostcheckError(result.PostcheckError, fmt.Sprintf("compute SPEC.md API coverage: %s", err))
+		}
+		for _, u := range uncovered {
+			result.UncoveredAPI = append(result.UncoveredAPI, fmt.Sprintf("%s (%s:%d)", u.ID, u.ImplFile, u.ImplLine))
+		}
+	}
 
 	if result.Conforms != nil {
 		if *result.Conforms {
@@ -802,6 +838,55 @@ func computeSpecDiffContext(pkg *gocode.Package) (string, error) {
 	return strings.TrimRight(buf.String(), "\n"), nil
 }
 
+// runSpecExamples runs the {example} blocks of pkg's SPEC.md with specmd.
+func runSpecExamples(ctx context.Context, pkg *gocode.Package) ([]specmd.ExampleResult, error) {
+	spec, err := specmd.Read(filepath.Join(pkg.AbsolutePath(), "SPEC.md"))
+	if err != nil {
+		return nil, err
+	}
+	return spec.RunExamples(ctx)
+}
+
+// computeSpecCoverage returns the exported identifiers of pkg that its SPEC.md Public API doesn't declare.
+func computeSpecCoverage(pkg *gocode.Package) ([]specmd.UncoveredAPI, error) {
+	spec, err := specmd.Read(filepath.Join(pkg.AbsolutePath(), "SPEC.md"))
+	if err != nil {
+		return nil, err
+	}
+	coverage, err := spec.APICoverage()
+	if err != nil {
+		return nil, err
+	}
+	return coverage.Uncovered, nil
+}
+
+// maxExampleFailureOutput caps how much go test output of a failing SPEC.md example is included in its nonconformance message.
+const maxExampleFailureOutput = 2000
+
+// addFailedExampleNonconformances returns result with a major nonconformance appended for each failed SPEC.md example, making it nonconforming. Failures are latent
+// when the package has no diff. result is returned unchanged if it has no verdict or there are no failures.
+func addFailedExampleNonconformances(result packageCheckResult, failed []specmd.ExampleResult, hasDiff bool) packageCheckResult {
+	if result.Conforms == nil || len(failed) == 0 {
+		return result
+	}
+	conforms := false
+	result.Conforms = &conforms
+	result.Nonconformances = append([]packageIssue(nil), result.Nonconformances...)
+	for _, ex := range failed {
+		output := strings.TrimSpace(ex.Output)
+		if len(output) > maxExampleFailureOutput {
+			output = output[:maxExampleFailureOutput] + "\n... (truncated)"
+		}
+		result.Nonconformances = append(result.Nonconformances, packageIssue{
+			Severity: "major",
+			Latent:   !hasDiff,
+			Message:  fmt.Sprintf("The SPEC.md example at line %d fails against the implementation:\n%s", ex.Example.Line, output),
+			Analysis: "SPEC.md examples are executable checks of documented behavior. Either the implementation no longer behaves as documented, or the example is out of date; compare the failure with the SPEC.md prose to decide which to change.",
+		})
+	}
+	return result
+}
+
 // The determineComparisonBase method selects the git commit used as the diff baseline for a conformance run. It requires a named current branch and a configured
 // heuristic-base helper, and returns the current branch, optional parent branch, and comparison-base commit.
 func (t *toolCheckSpecConformance) determineComparisonBase(ctx context.Context, repoAbsDir string) (comparisonBase, error) {
@@ -1250,6 +1335,7 @@ func ParseCheckSpecConformanceResults(raw string) (CheckSpecConformanceResults,
 		validated, err := validatePackageCheckResult(packageCheckResult(result), packageResultValidationOptions{
 			allowError:          true,
 			allowPostcheckError: true,
+			allowUncoveredAPI:   true,
 			requireAnalysis:     true,
 		})
 		if err != nil {
@@ -1454,7 +1540,7 @@ func validatePackageCheckResult(result packageCheckResult, options packageResult
 		if !options.allowError {
 			return packageCheckResult{}, fmt.Errorf("subagent returned unexpected error payload: %s", result.Error)
 		}
-		if result.Conforms != nil || result.Nonconformances != nil || result.PostcheckError != "" {
+		if result.Conforms != nil || result.Nonconformances != nil || result.PostcheckError != "" || result.UncoveredAPI != nil {
 			return packageCheckResult{}, fmt.Errorf("error result cannot include verdict fields")
 		}
 		return result, nil
@@ -1463,6 +1549,9 @@ func validatePackageCheckResult(result packageCheckResult, options packageResult
 	if result.PostcheckError != "" && !options.allowPostcheckError {
 		return packageCheckResult{}, fmt.Errorf("subagent returned unexpected postcheck_error payload: %s", result.PostcheckError)
 	}
+	if result.UncoveredAPI != nil && !options.allowUncoveredAPI {
+		return packageCheckResult{}, fmt.Errorf("subagent returned unexpected uncovered_api payload")
+	}
 	if result.Conforms == nil {
 		return packageCheckResult{}, fmt.Errorf("subagent JSON must include conforms")
 	}
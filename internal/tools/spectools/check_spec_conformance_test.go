@@ -17,6 +17,7 @@ import (
 	"github.com/codalotl/codalotl/internal/gocas/casconformance"
 	"github.com/codalotl/codalotl/internal/gocode"
 	"github.com/codalotl/codalotl/internal/llmstream"
+	"github.com/codalotl/codalotl/internal/specmd"
 	"github.com/codalotl/codalotl/internal/tools/authdomain"
 	"github.com/codalotl/codalotl/internal/tools/toolsetinterface"
 	"github.com/stretchr/testify/assert"
@@ -1626,3 +1627,85 @@ func renderBlock(t *testing.T, block llmstream.Block) string {
 	require.True(t, ok)
 	return renderPresentationLines(paragraph.Lines)
 }
+
+func TestRunReportsFailedSpecExamplesAsNonconformances(t *testing.T) {
+	moduleDir := setupModuleRepo(t)
+
+	tool := NewCheckSpecConformanceTool(allowAllAuthorizer{sandboxDir: moduleDir}).(*toolCheckSpecConformance)
+	tool.specExamples = func(ctx context.Context, pkg *gocode.Package) ([]specmd.ExampleResult, error) {
+		if pkg.RelativeDir == "internal/bar" {
+			return []specmd.ExampleResult{
+				{Example: specmd.ExampleBlock{Line: 3}, Passed: true},
+				{Example: specmd.ExampleBlock{Line: 9}, Output: "--- FAIL: TestSpecExampleLine9"},
+			}, nil
+		}
+		return nil, nil
+	}
+	tool.runPackageCheck = func(ctx context.Context, req packageCheckRequest) (string, error) {
+		return `{"conforms":true}`, nil
+	}
+
+	result := tool.Run(context.Background(), llmstream.ToolCall{
+		CallID: "call-spec-examples",
+		Name:   ToolNameCheckSpecConformance,
+		Type:   "function_call",
+		Input:  `{"only_changed":false}`,
+	})
+	require.False(t, result.IsError)
+
+	var parsed map[string]packageCheckResult
+	require.NoError(t, json.Unmarshal([]byte(result.Result), &parsed))
+	require.NotNil(t, parsed["internal/foo"].Conforms)
+	assert.True(t, *parsed["internal/foo"].Conforms)
+	bar := parsed["internal/bar"]
+	require.NotNil(t, bar.Conforms)
+	assert.False(t, *bar.Conforms)
+	require.Len(t, bar.Nonconformances, 1)
+	assert.Equal(t, "major", bar.Nonconformances[0].Severity)
+	assert.Contains(t, bar.Nonconformances[0].Message, "line 9")
+	assert.Contains(t, bar.Nonconformances[0].Message, "--- FAIL: TestSpecExampleLine9")
+
+	mod, err := gocode.NewModule(moduleDir)
+	require.NoError(t, err)
+	barPkg, err := mod.LoadPackageByRelativeDir("internal/bar")
+	require.NoError(t, err)
+	found, _, err := casconformance.Retrieve(newTestCASDB(t, moduleDir), barPkg)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestRunReportsUncoveredAPIWithoutAffectingVerdict(t *testing.T) {
+	moduleDir := setupModuleRepo(t)
+
+	tool := NewCheckSpecConformanceTool(allowAllAuthorizer{sandboxDir: moduleDir}).(*toolCheckSpecConformance)
+	tool.specExamples = nil
+	tool.specCoverage = func(pkg *gocode.Package) ([]specmd.UncoveredAPI, error) {
+		if pkg.RelativeDir == "internal/bar" {
+			return []specmd.UncoveredAPI{{ID: "Baz", ImplFile: "bar.go", ImplLine: 7}}, nil
+		}
+		return nil, nil
+	}
+	tool.runPackageCheck = func(ctx context.Context, req packageCheckRequest) (string, error) {
+		return `{"conforms":true}`, nil
+	}
+
+	result := tool.Run(context.Background(), llmstream.ToolCall{
+		CallID: "call-spec-coverage",
+		Name:   ToolNameCheckSpecConformance,
+		Type:   "function_call",
+		Input:  `{"only_changed":false}`,
+	})
+	require.False(t, result.IsError)
+
+	parsed, err := ParseCheckSpecConformanceResults(result.Result)
+	require.NoError(t, err)
+	assert.Nil(t, parsed["internal/foo"].UncoveredAPI)
+	bar := parsed["internal/bar"]
+	require.NotNil(t, bar.Conforms)
+	assert.True(t, *bar.Conforms)
+	assert.Equal(t, []string{"Baz (bar.go:7)"}, bar.UncoveredAPI)
+
+	// A subagent can't report coverage itself.
+	_, err = parseFinalPackageCheckResult(`{"conforms":true,"uncovered_api":["Baz"]}`, true)
+	assert.Error(t, err)
+}
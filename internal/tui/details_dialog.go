@@ -210,19 +210,6 @@ func (m *model) detailsDialogView(_ string) string {
 	}
 	m.detailsDialogEnsureSized()
 
-	w := m.windowWidth
-	h := m.windowHeight
-	if w <= 0 || h <= 0 {
-		return ""
-	}
-	if w < 2*detailsDialogMargin+10 || h < 2*detailsDialogMargin+8 {
-		// Too small for a proper dialog; fall back to a minimal view.
-		return "window too small for details"
-	}
-
-	dialogW := w - 2*detailsDialogMargin
-	dialogH := h - 2*detailsDialogMargin
-
 	dlg := m.detailsDialog
 
 	title := strings.Join(dlg.titleLines, "\n")
@@ -236,6 +223,25 @@ func (m *model) detailsDialogView(_ string) string {
 	b.WriteString("\n\n")
 	b.WriteString(dlg.view.View())
 
+	return m.modalDialogView(b.String(), "details")
+}
+
+// modalDialogView renders content inside a bordered dialog that fills the window, less detailsDialogMargin on each side, drawn over a blank background. It is shared
+// by the Details and Review dialogs. If the window is too small, it returns a short notice naming the dialog.
+func (m *model) modalDialogView(content string, name string) string {
+	w := m.windowWidth
+	h := m.windowHeight
+	if w <= 0 || h <= 0 {
+		return ""
+	}
+	if w < 2*detailsDialogMargin+10 || h < 2*detailsDialogMargin+8 {
+		// Too small for a proper dialog; fall back to a minimal view.
+		return "window too small for " + name
+	}
+
+	dialogW := w - 2*detailsDialogMargin
+	dialogH := h - 2*detailsDialogMargin
+
 	dialog := termformat.BlockStyle{
 		TotalWidth:         dialogW,
 		MinTotalHeight:     dialogH,
@@ -246,7 +252,7 @@ func (m *model) detailsDialogView(_ string) string {
 		BorderForeground:   m.palette.borderColor,
 		BorderBackground:   m.palette.primaryBackground,
 		BlockNormalizeMode: termformat.BlockNormalizeModeExtend,
-	}.Apply(b.String())
+	}.Apply(content)
 
 	dialogLines := strings.Split(strings.TrimSuffix(dialog, "\n"), "\n")
 	if len(dialogLines) < dialogH {
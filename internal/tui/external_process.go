@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	qtui "github.com/codalotl/codalotl/internal/q/tui"
+)
+
+// editorDoneMsg is sent when the $EDITOR launched by Ctrl-G exits.
+type editorDoneMsg struct {
+	path string // path is the temp file holding the message being edited.
+	err  error  // err is the error from running the editor, if any.
+}
+
+// pagerDoneMsg is sent when the pager launched by /diff exits.
+type pagerDoneMsg struct {
+	err error // err is the error from running the pager, if any.
+}
+
+// editMessageInEditor opens the text area's contents in the user's editor (Ctrl-G). When the editor exits, the edited file replaces the text area's contents (see
+// handleEditorDone).
+func (m *model) editMessageInEditor() {
+	if m.execProcess == nil || m.textarea == nil {
+		return
+	}
+
+	f, err := os.CreateTemp("", "codalotl-message-*.md")
+	if err != nil {
+		m.showSystemMessage(fmt.Sprintf("Could not open editor: %v", err))
+		return
+	}
+	path := f.Name()
+	_, err = f.WriteString(m.textarea.Contents())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		m.showSystemMessage(fmt.Sprintf("Could not open editor: %v", err))
+		return
+	}
+
+	cmd := commandFromEnv(editorCommand(), path)
+	m.execProcess(cmd, func(err error) qtui.Message {
+		return editorDoneMsg{path: path, err: err}
+	})
+}
+
+// handleEditorDone replaces the text area's contents with the edited message and removes the temp file. If the editor failed, the text area is left unchanged.
+func (m *model) handleEditorDone(msg editorDoneMsg) {
+	defer os.Remove(msg.path)
+
+	if msg.err != nil {
+		m.showSystemMessage(fmt.Sprintf("Editor failed: %v", msg.err))
+		return
+	}
+	data, err := os.ReadFile(msg.path)
+	if err != nil {
+		m.showSystemMessage(fmt.Sprintf("Could not read edited message: %v", err))
+		return
+	}
+	if m.textarea == nil {
+		return
+	}
+	// Most editors end files with a newline, which would otherwise become a trailing blank line in the message.
+	text := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	m.textarea.SetContents(text)
+	m.updateTextareaHeight()
+}
+
+// handleDiffCommand pages the changes made in the sandbox's git repo since the session started (/diff).
+func (m *model) handleDiffCommand() {
+	if m.session == nil || m.session.changes == nil {
+		m.showSystemMessage("/diff requires the sandbox to be in a git repository with at least one commit.")
+		return
+	}
+	diff, err := m.session.changes.Diff(true)
+	if err != nil {
+		m.showSystemMessage(fmt.Sprintf("Could not compute diff: %v", err))
+		return
+	}
+	if diff == "" {
+		m.showSystemMessage("No changes since the session started.")
+		return
+	}
+	if m.execProcess == nil {
+		return
+	}
+
+	cmd := commandFromEnv(pagerCommand())
+	cmd.Stdin = strings.NewReader(diff)
+	m.execProcess(cmd, func(err error) qtui.Message {
+		return pagerDoneMsg{err: err}
+	})
+}
+
+// handlePagerDone reports a pager failure, if any.
+func (m *model) handlePagerDone(msg pagerDoneMsg) {
+	if msg.err != nil {
+		m.showSystemMessage(fmt.Sprintf("Pager failed: %v", msg.err))
+	}
+}
+
+// showSystemMessage appends a system message, refreshes the viewport, and scrolls to the bottom.
+func (m *model) showSystemMessage(text string) {
+	m.appendSystemMessage(text)
+	m.refreshViewport(true)
+	if m.viewport != nil {
+		m.viewport.ScrollToBottom()
+	}
+}
+
+// editorCommand returns the user's editor command line: $VISUAL, else $EDITOR, else a platform default.
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return v
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// pagerCommand returns the user's pager command line: $PAGER, else a platform default. The default pager passes through the diff's ANSI colors.
+func pagerCommand() string {
+	if v := strings.TrimSpace(os.Getenv("PAGER")); v != "" {
+		return v
+	}
+	if runtime.GOOS == "windows" {
+		return "more"
+	}
+	return "less -R"
+}
+
+// commandFromEnv builds a command from a command line taken from the environment (ex: "code --wait"), appending args. Like git, it runs the command line with sh,
+// so quoting and paths with spaces work; args are passed as positional parameters, never re-parsed. On Windows, the command line is split on whitespace.
+func commandFromEnv(commandLine string, args ...string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		fields := strings.Fields(commandLine)
+		return exec.Command(fields[0], append(fields[1:], args...)...)
+	}
+	return exec.Command("sh", append([]string{"-c", commandLine + ` "$@"`, commandLine}, args...)...)
+}
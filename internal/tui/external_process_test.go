@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	qtui "github.com/codalotl/codalotl/internal/q/tui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCtrlGEditsMessageInEditor(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "myeditor --wait")
+
+	m := newModel(colorPalette{}, noopFormatter{}, nil, sessionConfig{}, nil, nil, nil, nil)
+	m.Update(nil, qtui.ResizeEvent{Width: 80, Height: 20})
+	m.textarea.SetContents("draft")
+
+	var path string
+	var done qtui.ExecDoneFunc
+	m.execProcess = func(cmd *exec.Cmd, d qtui.ExecDoneFunc) {
+		require.Len(t, cmd.Args, 5)
+		assert.Equal(t, []string{"sh", "-c", `myeditor --wait "$@"`, "myeditor --wait"}, cmd.Args[:4])
+		path = cmd.Args[4]
+		done = d
+	}
+
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyCtrlG})
+	require.NotNil(t, done)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "draft", string(data))
+
+	// The "editor" rewrites the file; the text area picks it up when it exits.
+	require.NoError(t, os.WriteFile(path, []byte("edited\nmessage\n"), 0644))
+	m.Update(nil, done(nil))
+	assert.Equal(t, "edited\nmessage", m.textarea.Contents())
+	assert.NoFileExists(t, path)
+}
+
+func TestCtrlGEditorFailureKeepsMessage(t *testing.T) {
+	m := newModel(colorPalette{}, noopFormatter{}, nil, sessionConfig{}, nil, nil, nil, nil)
+	m.Update(nil, qtui.ResizeEvent{Width: 80, Height: 20})
+	m.textarea.SetContents("draft")
+
+	var path string
+	m.execProcess = func(cmd *exec.Cmd, done qtui.ExecDoneFunc) {
+		path = cmd.Args[len(cmd.Args)-1]
+		require.NoError(t, os.WriteFile(path, []byte("partial"), 0644))
+		m.Update(nil, done(assert.AnError))
+	}
+
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyCtrlG})
+	assert.Equal(t, "draft", m.textarea.Contents())
+	assert.NoFileExists(t, path)
+	require.NotEmpty(t, m.messages)
+	assert.Contains(t, m.messages[len(m.messages)-1].userMessage, "Editor failed")
+}
+
+func TestCommandFromEnvRunsCommandLineWithShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("command lines are split on whitespace on Windows")
+	}
+	out, err := commandFromEnv(`printf '%s|' 'my editor'`, "file with spaces.md", "$HOME").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "my editor|file with spaces.md|$HOME|", string(out))
+}
+
+func TestDiffCommandPagesSessionChanges(t *testing.T) {
+	repo := newSessionChangesTestRepo(t)
+	t.Setenv("PAGER", "mypager -X")
+
+	m := newModel(colorPalette{}, noopFormatter{}, nil, sessionConfig{}, nil, nil, nil, nil)
+	m.Update(nil, qtui.ResizeEvent{Width: 80, Height: 20})
+	m.session = &session{changes: captureSessionChanges(repo)}
+	require.NotNil(t, m.session.changes)
+
+	var paged string
+	m.execProcess = func(cmd *exec.Cmd, done qtui.ExecDoneFunc) {
+		assert.Equal(t, []string{"sh", "-c", `mypager -X "$@"`, "mypager -X"}, cmd.Args)
+		data, err := io.ReadAll(cmd.Stdin)
+		require.NoError(t, err)
+		paged = string(data)
+		m.Update(nil, done(nil))
+	}
+
+	require.True(t, m.handleSlashCommand("/diff"))
+	assert.Empty(t, paged)
+	assert.Contains(t, m.messages[len(m.messages)-1].userMessage, "No changes since the session started.")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\nchanged\n"), 0644))
+	require.True(t, m.handleSlashCommand("/diff"))
+	assert.Contains(t, paged, "changed")
+	assert.False(t, m.shouldSaveToHistory("/diff"))
+}
+
+func TestSessionChangesDiff(t *testing.T) {
+	repo := newSessionChangesTestRepo(t)
+
+	// Uncommitted changes and untracked files from before the session aren't part of the session's changes.
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\nbefore\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "old.txt"), []byte("old\n"), 0644))
+	changes := captureSessionChanges(filepath.Join(repo, "sub"))
+	require.NotNil(t, changes)
+
+	diff, err := changes.Diff(false)
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\nbefore\nduring\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "old.txt"), []byte("old\nchanged\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "sub", "new.txt"), []byte("new\n"), 0644))
+
+	diff, err = changes.Diff(false)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+during\n")
+	assert.NotContains(t, diff, "+before\n")
+	assert.Contains(t, diff, "b/sub/new.txt")
+	assert.Contains(t, diff, "+new\n")
+	assert.NotContains(t, diff, "old.txt")
+
+	assert.Nil(t, captureSessionChanges(t.TempDir()))
+}
+
+// newSessionChangesTestRepo creates a git repo with one commit containing a.txt and sub/keep.txt.
+func newSessionChangesTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "sub", "keep.txt"), []byte("keep\n"), 0644))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, strings.TrimSpace(string(out)))
+	}
+	return repo
+}
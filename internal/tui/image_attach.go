@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/llmstream"
+	"github.com/codalotl/codalotl/internal/q/clipboard"
+)
+
+// attachedImage is an image waiting to be sent with the next user message.
+type attachedImage struct {
+	label string                 // label names the image in the Messages Area (ex: a file name, or "clipboard").
+	image llmstream.ImageContent // image is the content sent to the agent.
+}
+
+// attachPastedImagePaths attaches the images named by a bracketed paste, which is how most terminals deliver files dropped onto the window. It returns false, attaching
+// nothing, unless every pasted path is an existing image file; the paste is then handled as ordinary text.
+func (m *model) attachPastedImagePaths(text string) bool {
+	paths := parseDroppedPaths(text)
+	if len(paths) == 0 {
+		return false
+	}
+	for _, path := range paths {
+		if !llmstream.IsImagePath(path) {
+			return false
+		}
+		if fi, err := os.Stat(path); err != nil || fi.IsDir() {
+			return false
+		}
+	}
+	for _, path := range paths {
+		img, err := llmstream.ReadImageFile(path)
+		if err != nil {
+			m.appendSystemMessage(fmt.Sprintf("Could not attach image: %v", err))
+			continue
+		}
+		m.attachImage(filepath.Base(path), img)
+	}
+	m.refreshViewport(true)
+	if m.viewport != nil {
+		m.viewport.ScrollToBottom()
+	}
+	return true
+}
+
+// clipboardImageMsg carries the result of an asynchronous OS clipboard image read.
+type clipboardImageMsg struct {
+	data []byte // data is the encoded image.
+	err  error  // err is the read error; clipboard.ErrNoImage if the clipboard holds no image.
+}
+
+// startClipboardImageRead reads the OS clipboard's image in the background, since the read runs a subprocess on most platforms, and delivers the result as a clipboardImageMsg.
+// It returns false, so the key can be handled normally, when clipboard reads are unavailable. Presses while a read is in flight are ignored.
+func (m *model) startClipboardImageRead() bool {
+	if m.osClipboardReadImage == nil || m.tui == nil {
+		return false
+	}
+	if m.clipboardImageReadPending {
+		return true
+	}
+	m.clipboardImageReadPending = true
+
+	read := m.osClipboardReadImage
+	prog := m.tui
+	go func() {
+		data, err := read()
+		prog.Send(clipboardImageMsg{data: data, err: err})
+	}()
+	return true
+}
+
+// handleClipboardImage attaches the image read by startClipboardImageRead. An empty clipboard (or a failed read) attaches nothing.
+func (m *model) handleClipboardImage(msg clipboardImageMsg) {
+	m.clipboardImageReadPending = false
+	if msg.err != nil {
+		if !errors.Is(msg.err, clipboard.ErrNoImage) {
+			debugLogf("clipboard image read error: %v", msg.err)
+		}
+		return
+	}
+	img, err := llmstream.NewImageContent(msg.data)
+	if err != nil {
+		m.appendSystemMessage(fmt.Sprintf("Could not attach clipboard image: %v", err))
+	} else {
+		m.attachImage("clipboard", img)
+	}
+	m.refreshViewport(true)
+	if m.viewport != nil {
+		m.viewport.ScrollToBottom()
+	}
+}
+
+// attachImage adds img to the pending attachments and reports it in the Messages Area. Callers are responsible for refreshing the viewport.
+func (m *model) attachImage(label string, img llmstream.ImageContent) {
+	m.pendingImages = append(m.pendingImages, attachedImage{label: label, image: img})
+	m.appendSystemMessage(fmt.Sprintf("Attached image %d: %s (%s, ~%d tokens). It will be sent with your next message; ESC on an empty input removes attachments.",
+		len(m.pendingImages), label, img.String(), img.EstimatedTokens()))
+}
+
+// clearPendingImages drops all pending attachments, reporting it in the Messages Area. It returns false if there were none.
+func (m *model) clearPendingImages() bool {
+	if len(m.pendingImages) == 0 {
+		return false
+	}
+	m.appendSystemMessage(fmt.Sprintf("Removed %d attached image(s).", len(m.pendingImages)))
+	m.pendingImages = nil
+	m.refreshViewport(true)
+	if m.viewport != nil {
+		m.viewport.ScrollToBottom()
+	}
+	return true
+}
+
+// takePendingImages returns and clears the pending attachments.
+func (m *model) takePendingImages() []attachedImage {
+	images := m.pendingImages
+	m.pendingImages = nil
+	return images
+}
+
+// imageContents returns the content of each image.
+func imageContents(images []attachedImage) []llmstream.ImageContent {
+	if len(images) == 0 {
+		return nil
+	}
+	contents := make([]llmstream.ImageContent, 0, len(images))
+	for _, img := range images {
+		contents = append(contents, img.image)
+	}
+	return contents
+}
+
+// userMessageDisplay returns value as shown in the Messages Area, with one "[image: label]" line per attached image.
+func userMessageDisplay(value string, images []attachedImage) string {
+	if len(images) == 0 {
+		return value
+	}
+	lines := make([]string, 0, len(images)+1)
+	if strings.TrimSpace(value) != "" {
+		lines = append(lines, value)
+	}
+	for _, img := range images {
+		lines = append(lines, "[image: "+img.label+"]")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseDroppedPaths splits pasted text into file paths the way terminals format dropped files: paths separated by whitespace or newlines, optionally single- or
+// double-quoted, with backslash-escaped spaces, or as file:// URLs. It returns nil if text is empty or contains unbalanced quotes.
+func parseDroppedPaths(text string) []string {
+	var paths []string
+	var cur strings.Builder
+	inToken := false
+	var quote rune
+	escaped := false
+
+	flush := func() {
+		if !inToken {
+			return
+		}
+		path := cur.String()
+		if strings.HasPrefix(path, "file://") {
+			if u, err := url.Parse(path); err == nil {
+				path = u.Path
+			}
+		}
+		paths = append(paths, path)
+		cur.Reset()
+		inToken = false
+	}
+
+	for _, r := range strings.TrimSpace(text) {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inToken = true
+		case r == '\'' || r == '"':
+			quote = r
+			inToken = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+		default:
+			cur.WriteRune(r)
+			inToken = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil
+	}
+	flush()
+	return paths
+}
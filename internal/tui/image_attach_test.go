@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/q/clipboard"
+	qtui "github.com/codalotl/codalotl/internal/q/tui"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestPNG(t *testing.T, path string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 3, 2))))
+	if path != "" {
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	}
+	return buf.Bytes()
+}
+
+func TestParseDroppedPaths(t *testing.T) {
+	require.Equal(t, []string{"/tmp/a b.png"}, parseDroppedPaths(`/tmp/a\ b.png`))
+	require.Equal(t, []string{"/tmp/a b.png", "/tmp/c.jpg"}, parseDroppedPaths(`'/tmp/a b.png' "/tmp/c.jpg"`+"\n"))
+	require.Equal(t, []string{"/tmp/a b.png"}, parseDroppedPaths("file:///tmp/a%20b.png"))
+	require.Nil(t, parseDroppedPaths(`'/tmp/unterminated`))
+	require.Nil(t, parseDroppedPaths("   "))
+}
+
+func TestPasteOfImagePathAttachesImage(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "shot one.png")
+	writeTestPNG(t, path)
+
+	m := newModel(colorPalette{}, noopFormatter{}, nil, sessionConfig{}, nil, nil, nil, nil)
+	skip := m.handleKeyEvent(qtui.KeyEvent{Runes: []rune("'" + path + "'"), Paste: true})
+	require.True(t, skip)
+	require.Len(t, m.pendingImages, 1)
+	require.Equal(t, "shot one.png", m.pendingImages[0].label)
+	require.Equal(t, "", m.textarea.Contents())
+
+	// Ordinary text pastes (including paths to non-images) are left to the text area.
+	skip = m.handleKeyEvent(qtui.KeyEvent{Runes: []rune(dir), Paste: true})
+	require.False(t, skip)
+	require.Len(t, m.pendingImages, 1)
+}
+
+func TestClipboardImageMsgAttachesImage(t *testing.T) {
+	m := newModel(colorPalette{}, noopFormatter{}, nil, sessionConfig{}, nil, nil, nil, nil)
+	m.clipboardImageReadPending = true
+	m.Update(nil, clipboardImageMsg{err: clipboard.ErrNoImage})
+	require.False(t, m.clipboardImageReadPending)
+	require.Empty(t, m.pendingImages)
+
+	m.Update(nil, clipboardImageMsg{data: writeTestPNG(t, "")})
+	require.Len(t, m.pendingImages, 1)
+	require.Equal(t, "clipboard", m.pendingImages[0].label)
+}
+
+func TestCtrlVWithoutTUIDoesNotReadClipboard(t *testing.T) {
+	m := newModel(colorPalette{}, noopFormatter{}, nil, sessionConfig{}, nil, nil, nil, nil)
+	m.osClipboardReadImage = func() ([]byte, error) {
+		t.Fatal("clipboard read on the UI goroutine")
+		return nil, nil
+	}
+	require.False(t, m.handleKeyEvent(qtui.KeyEvent{ControlKey: qtui.ControlKeyCtrlV}))
+	require.False(t, m.clipboardImageReadPending)
+}
+
+func TestEnterSendsPendingImagesWithoutText(t *testing.T) {
+	m := newModel(colorPalette{}, noopFormatter{}, &session{}, sessionConfig{}, nil, nil, nil, nil)
+	var started []string
+	m.startAgentRunHook = func(message string) {
+		started = append(started, message)
+	}
+	m.Update(nil, clipboardImageMsg{data: writeTestPNG(t, "")})
+
+	require.True(t, m.handleKeyEvent(qtui.KeyEvent{ControlKey: qtui.ControlKeyEnter}))
+	require.Empty(t, m.pendingImages)
+	require.Equal(t, []string{""}, started)
+	last := m.messages[len(m.messages)-1]
+	require.Equal(t, messageKindUser, last.kind)
+	require.Equal(t, "[image: clipboard]", last.userMessage)
+}
+
+func TestEscOnEmptyInputClearsPendingImages(t *testing.T) {
+	m := newModel(colorPalette{}, noopFormatter{}, nil, sessionConfig{}, nil, nil, nil, nil)
+	m.Update(nil, clipboardImageMsg{data: writeTestPNG(t, "")})
+
+	require.True(t, m.handleKeyEvent(qtui.KeyEvent{ControlKey: qtui.ControlKeyEsc}))
+	require.Empty(t, m.pendingImages)
+	require.Contains(t, m.messages[len(m.messages)-1].userMessage, "Removed 1 attached image")
+}
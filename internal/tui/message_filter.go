@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/agent"
+	"github.com/codalotl/codalotl/internal/llmstream"
+	"github.com/codalotl/codalotl/internal/q/termformat"
+	qtui "github.com/codalotl/codalotl/internal/q/tui"
+	"github.com/codalotl/codalotl/internal/q/tui/tuicontrols"
+)
+
+// Message tags classify Messages Area sections for /filter (see tuicontrols.Section).
+const (
+	messageTagUser      = "user"      // messageTagUser tags user messages, which also mark the start of each turn.
+	messageTagAssistant = "assistant" // messageTagAssistant tags assistant text.
+	messageTagTool      = "tool"      // messageTagTool tags tool calls and their results.
+	messageTagEdit      = "edit"      // messageTagEdit tags tool calls that change files (their presentation body is a diff).
+	messageTagError     = "error"     // messageTagError tags agent errors and failed tool calls.
+	messageTagSystem    = "system"    // messageTagSystem tags system messages, the welcome banner, and other UI-generated messages.
+	messageTagIndicator = "indicator" // messageTagIndicator tags synthetic blocks (ex: the Working Indicator) that stay shown under every filter.
+)
+
+// messageFilterNames maps /filter arguments to the tags they show.
+var messageFilterNames = map[string]string{
+	"assistant": messageTagAssistant,
+	"errors":    messageTagError,
+	"edits":     messageTagEdit,
+}
+
+// handleFilterCommand applies a /filter command. args is a list of filter names: "assistant", "errors", and "edits" show only those messages (plus user messages);
+// "collapse" collapses tool calls to their first line; "off" shows everything. With no args, it prints usage and the current filter.
+func (m *model) handleFilterCommand(args []string) {
+	if m.viewport == nil {
+		return
+	}
+	if len(args) == 0 {
+		m.showSystemMessage(fmt.Sprintf("Current filter: %s.\nUsage: `/filter [assistant|errors|edits] [collapse]`, or `/filter off`.", describeMessageFilter(m.viewport.Filter())))
+		return
+	}
+
+	var filter tuicontrols.Filter
+	for _, arg := range args {
+		switch arg = strings.ToLower(arg); arg {
+		case "off":
+			if len(args) > 1 {
+				m.showSystemMessage("`/filter off` takes no other arguments.")
+				return
+			}
+		case "collapse":
+			filter.Collapse = []string{messageTagTool}
+		default:
+			tag, ok := messageFilterNames[arg]
+			if !ok {
+				m.showSystemMessage(fmt.Sprintf("Unknown filter %q. Usage: `/filter [assistant|errors|edits] [collapse]`, or `/filter off`.", arg))
+				return
+			}
+			if len(filter.Only) == 0 {
+				filter.Only = []string{messageTagUser, messageTagIndicator}
+			}
+			filter.Only = append(filter.Only, tag)
+		}
+	}
+	m.viewport.SetFilter(filter)
+	m.refreshViewport(true)
+}
+
+// describeMessageFilter describes f in words, for /filter and the filter status line.
+func describeMessageFilter(f tuicontrols.Filter) string {
+	if f.IsZero() {
+		return "off"
+	}
+	var parts []string
+	for _, tag := range f.Only {
+		switch tag {
+		case messageTagAssistant:
+			parts = append(parts, "assistant text")
+		case messageTagError:
+			parts = append(parts, "errors")
+		case messageTagEdit:
+			parts = append(parts, "edits")
+		}
+	}
+	desc := ""
+	if len(parts) > 0 {
+		desc = "only " + strings.Join(parts, ", ")
+	}
+	if len(f.Collapse) > 0 {
+		if desc != "" {
+			desc += "; "
+		}
+		desc += "tool calls collapsed"
+	}
+	return desc
+}
+
+// renderFilterStatus renders the status line shown at the bottom of the Messages Area while a /filter is active, or "" if none is.
+func (m *model) renderFilterStatus(width int) string {
+	if m.viewport == nil || m.viewport.Filter().IsZero() {
+		return ""
+	}
+	text := fmt.Sprintf("Filter: %s (/filter off to show everything)", describeMessageFilter(m.viewport.Filter()))
+	styled := termformat.Style{
+		Foreground: m.palette.accentForeground,
+	}.Wrap(termformat.Sanitize(text, 4))
+	return m.setMessageWidthBG(styled, width, m.palette.primaryBackground)
+}
+
+// viewportSections returns the Messages Area sections for blocks, as joined by joinRenderedBlocksWithOverlay: each block is followed by one separator row, which
+// belongs to the block's section.
+func (m *model) viewportSections(blocks []renderedBlock) []tuicontrols.Section {
+	sections := make([]tuicontrols.Section, 0, len(blocks))
+	line := 0
+	for _, blk := range blocks {
+		height := termformat.BlockHeight(strings.TrimSuffix(blk.text, "\n"))
+		if height < 1 {
+			height = 1
+		}
+		sec := tuicontrols.Section{Start: line, End: line + height + 1, Header: 1, Tags: []string{messageTagIndicator}}
+		if blk.messageIndex >= 0 && blk.messageIndex < len(m.messages) {
+			sec.Tags = cachedMessageTags(&m.messages[blk.messageIndex])
+			sec.Mark = m.messages[blk.messageIndex].kind == messageKindUser || m.messages[blk.messageIndex].kind == messageKindQueuedUser
+		}
+		sections = append(sections, sec)
+		line += height + 1
+	}
+	return sections
+}
+
+// cachedMessageTags returns messageTags(msg), caching the result on msg.
+func cachedMessageTags(msg *chatMessage) []string {
+	if msg.tags == nil || msg.tagsKind != msg.kind || msg.tagsForEvent != msg.event.Type {
+		msg.tags = messageTags(msg)
+		msg.tagsKind = msg.kind
+		msg.tagsForEvent = msg.event.Type
+	}
+	return msg.tags
+}
+
+// messageTags returns the section tags for msg.
+func messageTags(msg *chatMessage) []string {
+	switch msg.kind {
+	case messageKindUser, messageKindQueuedUser:
+		return []string{messageTagUser}
+	case messageKindAgent:
+	default:
+		return []string{messageTagSystem}
+	}
+
+	ev := msg.event
+	switch ev.Type {
+	case agent.EventTypeAssistantText:
+		return []string{messageTagAssistant}
+	case agent.EventTypeError:
+		return []string{messageTagError}
+	case agent.EventTypeToolCall, agent.EventTypeToolComplete:
+		tags := []string{messageTagTool}
+		if presentsDiff(ev) {
+			tags = append(tags, messageTagEdit)
+		}
+		if ev.ToolResult != nil && ev.ToolResult.IsError {
+			tags = append(tags, messageTagError)
+		}
+		return tags
+	default:
+		return []string{string(ev.Type)}
+	}
+}
+
+// handleViewportNavigationKey handles Messages Area search and jump-to-turn keys, reporting whether key was consumed:
+//   - Ctrl-F opens the search prompt when the Text Area is empty; while it's open, all keys go to it. With text in the Text Area, Ctrl-F is left to it (cursor
+//     right).
+//   - Ctrl-Up/Ctrl-Down jump to the previous/next user message (turn).
+func (m *model) handleViewportNavigationKey(key qtui.KeyEvent) bool {
+	if m.viewport == nil {
+		return false
+	}
+	if m.viewport.Searching() {
+		m.viewport.Update(m.tui, key)
+		return true
+	}
+	switch key.ControlKey {
+	case qtui.ControlKeyCtrlF:
+		if m.textarea != nil && m.textarea.Contents() != "" {
+			return false
+		}
+		m.viewport.StartSearch()
+		return true
+	case qtui.ControlKeyCtrlUp:
+		m.viewport.PrevMark()
+		return true
+	case qtui.ControlKeyCtrlDown:
+		m.viewport.NextMark()
+		return true
+	}
+	return false
+}
+
+// presentsDiff reports whether ev's tool presents itself as a diff (ex: file edits), which is how edits are recognized without hard-coding tool names.
+func presentsDiff(ev agent.Event) bool {
+	if ev.Tool == nil || ev.ToolCall == nil {
+		return false
+	}
+	presenter := ev.Tool.Presenter()
+	if presenter == nil {
+		return false
+	}
+	var result *llmstream.ToolResult
+	if ev.Type == agent.EventTypeToolComplete {
+		result = ev.ToolResult
+	}
+	switch presenter.Present(*ev.ToolCall, result).Body.(type) {
+	case llmstream.Diff, *llmstream.Diff:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/codalotl/codalotl/internal/agent"
+	"github.com/codalotl/codalotl/internal/llmstream"
+	qtui "github.com/codalotl/codalotl/internal/q/tui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// diffTool is a tool whose presenter presents a diff, like file-editing tools.
+type diffTool struct{}
+
+func (diffTool) Info() llmstream.ToolInfo { return llmstream.ToolInfo{Name: "patch"} }
+func (diffTool) Name() string             { return "patch" }
+func (diffTool) Presenter() llmstream.Presenter {
+	return diffTool{}
+}
+func (diffTool) Present(llmstream.ToolCall, *llmstream.ToolResult) llmstream.Presentation {
+	return llmstream.Presentation{Body: llmstream.Diff{}}
+}
+func (diffTool) Run(context.Context, llmstream.ToolCall) llmstream.ToolResult {
+	return llmstream.ToolResult{}
+}
+
+func newMessageFilterTestModel(t *testing.T, height int) *model {
+	t.Helper()
+	m := newModel(colorPalette{}, descriptiveFormatter{}, nil, sessionConfig{}, nil, nil, nil, nil)
+	m.Update(nil, qtui.ResizeEvent{Width: 80, Height: height})
+	m.messages = append(m.messages,
+		chatMessage{kind: messageKindUser, userMessage: "first question"},
+		chatMessage{kind: messageKindAgent, event: agent.Event{Type: agent.EventTypeToolCall, Tool: diffTool{}, ToolCall: &llmstream.ToolCall{Name: "patch"}}},
+		chatMessage{kind: messageKindAgent, event: agent.Event{Type: agent.EventTypeToolCall, ToolCall: &llmstream.ToolCall{Name: "read_file"}}},
+		chatMessage{kind: messageKindAgent, event: agent.Event{Type: agent.EventTypeAssistantText, TextContent: llmstream.TextContent{Content: "first answer"}}},
+		chatMessage{kind: messageKindUser, userMessage: "second question"},
+		chatMessage{kind: messageKindAgent, event: agent.Event{Type: agent.EventTypeError, Error: errors.New("boom")}},
+	)
+	m.refreshViewport(true)
+	return m
+}
+
+func TestFilterCommand(t *testing.T) {
+	m := newMessageFilterTestModel(t, 40)
+
+	require.True(t, m.handleSlashCommand("/filter edits"))
+	view := m.viewport.View()
+	assert.Contains(t, view, "first question")
+	assert.Contains(t, view, "tool-call(depth=0): "+"patch")
+	assert.NotContains(t, view, "read_file")
+	assert.NotContains(t, view, "first answer")
+	assert.Contains(t, view, "Filter: only edits (/filter off to show everything)")
+
+	require.True(t, m.handleSlashCommand("/filter assistant errors"))
+	view = m.viewport.View()
+	assert.Contains(t, view, "first answer")
+	assert.Contains(t, view, "boom")
+	assert.NotContains(t, view, "patch")
+
+	require.True(t, m.handleSlashCommand("/filter off"))
+	view = m.viewport.View()
+	assert.Contains(t, view, "read_file")
+	assert.NotContains(t, view, "Filter:")
+
+	require.True(t, m.handleSlashCommand("/filter bogus"))
+	assert.Contains(t, m.messages[len(m.messages)-1].userMessage, `Unknown filter "bogus"`)
+	assert.True(t, m.viewport.Filter().IsZero())
+	assert.False(t, m.shouldSaveToHistory("/filter edits"))
+}
+
+func TestCtrlFSearchesMessagesArea(t *testing.T) {
+	m := newMessageFilterTestModel(t, 40)
+
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyCtrlF})
+	require.True(t, m.viewport.Searching())
+	for _, r := range "answer" {
+		m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyNone, Runes: []rune{r}})
+	}
+	assert.Equal(t, "answer", m.viewport.SearchQuery())
+	assert.Equal(t, 1, m.viewport.MatchCount())
+	assert.Empty(t, m.textarea.Contents())
+
+	// ESC closes the search.
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyEsc})
+	assert.False(t, m.viewport.Searching())
+	assert.Empty(t, m.textarea.Contents())
+}
+
+func TestCtrlFMovesCursorWithDraft(t *testing.T) {
+	m := newMessageFilterTestModel(t, 40)
+	m.textarea.SetContents("draft")
+	m.textarea.MoveToBeginningOfText()
+
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyCtrlF})
+	assert.False(t, m.viewport.Searching())
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyNone, Runes: []rune{'x'}})
+	assert.Equal(t, "dxraft", m.textarea.Contents())
+}
+
+func TestCtrlUpDownJumpBetweenTurns(t *testing.T) {
+	m := newMessageFilterTestModel(t, 12)
+	require.True(t, m.viewport.AtBottom())
+
+	// The view is too short to put "second question" at the top, so the bottom already shows it; Ctrl-Up goes to the first turn.
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyCtrlUp})
+	line, ok := m.viewport.LineAt(0)
+	require.True(t, ok)
+	assert.Equal(t, 0, line)
+	assert.Contains(t, m.viewport.View(), "first question")
+
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyCtrlDown})
+	assert.True(t, m.viewport.AtBottom())
+	assert.Contains(t, m.viewport.View(), "second question")
+}
@@ -93,7 +93,10 @@ func (m *model) tryHandleOverlayClick(ev qtui.MouseEvent) bool {
 		return false
 	}
 
-	contentLine := ev.Y + m.viewport.Offset()
+	contentLine, ok := m.viewport.LineAt(ev.Y)
+	if !ok {
+		return false
+	}
 	for _, t := range m.overlayTargets {
 		if t.contentLine != contentLine {
 			continue
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/codalotl/codalotl/internal/q/termformat"
+	qtui "github.com/codalotl/codalotl/internal/q/tui"
 	"github.com/codalotl/codalotl/internal/tools/authdomain"
 
 	"github.com/stretchr/testify/assert"
@@ -93,3 +94,43 @@ func TestPermissionView_NarrowTerminal_FallsBackWithoutPanic(t *testing.T) {
 	})
 	assert.Equal(t, termformat.Sanitize("Allow the test request?", 4), m.permissionViewText)
 }
+
+func TestPermissionView_AlwaysAllow(t *testing.T) {
+	m := &model{
+		palette:      newColorPalette(Config{Palette: PalettePlain}),
+		windowWidth:  80,
+		windowHeight: 24,
+	}
+	m.updateSizes()
+
+	var allowed, alwaysAllowed bool
+	m.activePermission = &permissionPrompt{
+		request: authdomain.UserRequest{
+			ToolName:    "test-permission",
+			Prompt:      "Allow the test request?",
+			Allow:       func() { allowed = true },
+			Disallow:    func() {},
+			AlwaysAllow: func() error { alwaysAllowed = true; return nil },
+		},
+	}
+	m.refreshPermissionView()
+	assert.Contains(t, m.permissionViewText, "A    always allow (this project)")
+
+	m.handlePermissionKey(qtui.KeyEvent{ControlKey: qtui.ControlKeyNone, Runes: []rune{'a'}})
+	assert.True(t, alwaysAllowed)
+	assert.False(t, allowed)
+	assert.Nil(t, m.activePermission)
+
+	// Without AlwaysAllow, "a" is swallowed and the option isn't shown.
+	m.activePermission = &permissionPrompt{
+		request: authdomain.UserRequest{
+			Prompt:   "Allow the test request?",
+			Allow:    func() { allowed = true },
+			Disallow: func() {},
+		},
+	}
+	m.refreshPermissionView()
+	assert.NotContains(t, m.permissionViewText, "always allow")
+	m.handlePermissionKey(qtui.KeyEvent{ControlKey: qtui.ControlKeyNone, Runes: []rune{'a'}})
+	assert.NotNil(t, m.activePermission)
+}
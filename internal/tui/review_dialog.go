@@ -0,0 +1,453 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/codalotl/codalotl/internal/diff"
+	"github.com/codalotl/codalotl/internal/q/termformat"
+	qtui "github.com/codalotl/codalotl/internal/q/tui"
+	"github.com/codalotl/codalotl/internal/q/tui/tuicontrols"
+)
+
+const (
+	reviewDialogContextLines = 3    // reviewDialogContextLines is the number of unchanged lines shown around each hunk.
+	reviewDialogSelectedBar  = "▌ " // reviewDialogSelectedBar prefixes the lines of the selected item.
+	reviewDialogHeaderHeight = 4    // reviewDialogHeaderHeight is the title, blank, hint, and blank rows above the body.
+)
+
+// reviewDialogMode is what the Review dialog's keys currently do.
+type reviewDialogMode int
+
+const (
+	reviewDialogModeBrowse  reviewDialogMode = iota // reviewDialogModeBrowse selects and scrolls items.
+	reviewDialogModeConfirm                         // reviewDialogModeConfirm waits for "y" to revert the selected item.
+	reviewDialogModeNote                            // reviewDialogModeNote edits the note sent to the agent.
+)
+
+// reviewItem is a selectable item in the Review dialog: a whole file, or one of its hunks.
+type reviewItem struct {
+	file  int // file is the index into reviewDialog.files.
+	hunk  int // hunk is the index into the file's diff hunks, or -1 for the whole file.
+	start int // start is the item's first line in the dialog's body.
+	end   int // end is one past the item's last line in the dialog's body.
+}
+
+// reviewDialog is the modal Review overlay (opened with /changes), which lists everything that changed since the session started and lets the user revert individual
+// hunks or files.
+type reviewDialog struct {
+	files    []sessionFileChange // files are the changed files, as of the last reload.
+	diffs    []diff.Diff         // diffs are the files' diffs, parallel to files.
+	items    []reviewItem        // items are the selectable items, in display order.
+	selected int                 // selected is the index of the selected item.
+	mode     reviewDialogMode    // mode is what keys currently do.
+	note     string              // note is the reason typed in reviewDialogModeNote.
+	status   string              // status is a one-off message shown in place of the key hint until the next key press.
+	reverted []string            // reverted describes reverts not yet reported to the agent (ex: "your changes to a.go").
+	view     *tuicontrols.View   // view scrolls the rendered files and hunks.
+}
+
+// openReviewDialog opens the Review dialog for the session's changes. If there's nothing to review, it explains why in a system message instead.
+func (m *model) openReviewDialog() {
+	if m.session == nil || m.session.changes == nil {
+		m.showSystemMessage("/changes requires the sandbox to be in a git repository with at least one commit.")
+		return
+	}
+	dlg := &reviewDialog{view: tuicontrols.NewView(0, 0)}
+	dlg.view.SetEmptyLineBackgroundColor(m.palette.accentBackground)
+	m.reviewDialog = dlg
+	if err := m.reloadReviewDialog(); err != nil {
+		m.reviewDialog = nil
+		m.showSystemMessage(fmt.Sprintf("Could not list changes: %v", err))
+		return
+	}
+	if len(dlg.files) == 0 {
+		m.reviewDialog = nil
+		m.showSystemMessage("No changes since the session started.")
+	}
+}
+
+// closeReviewDialog closes the Review dialog. Reverts that weren't reported with a note become "I reverted X.", which is prepended to the user's next message rather
+// than starting an agent turn on its own.
+func (m *model) closeReviewDialog() {
+	dlg := m.reviewDialog
+	m.reviewDialog = nil
+	if dlg == nil || len(dlg.reverted) == 0 {
+		return
+	}
+	note := reviewNoteText(dlg.reverted, "")
+	if m.pendingReviewNote != "" {
+		note = m.pendingReviewNote + " " + note
+	}
+	m.pendingReviewNote = note
+	m.showSystemMessage("Your next message will start with: " + note)
+}
+
+// sendReviewNote sends "I reverted X because Y." to the agent as a user message (after any pending review note), and forgets the reported reverts.
+func (m *model) sendReviewNote(dlg *reviewDialog, reason string) {
+	text := m.withPendingReviewNote(reviewNoteText(dlg.reverted, reason))
+	dlg.reverted = nil
+	m.sendOrQueueMessage(text)
+	m.startAgentRunIfPossible(text)
+}
+
+// reviewNoteText returns "I reverted X because Y." for reverted (or "I reverted X." if reason is empty).
+func reviewNoteText(reverted []string, reason string) string {
+	text := "I reverted " + joinWithAnd(reverted)
+	if reason = strings.TrimSpace(reason); reason != "" {
+		text += " because " + strings.TrimSuffix(reason, ".")
+	}
+	return text + "."
+}
+
+// withPendingReviewNote returns value with the pending review note (if any) prepended, and clears the pending note.
+func (m *model) withPendingReviewNote(value string) string {
+	note := m.pendingReviewNote
+	m.pendingReviewNote = ""
+	switch {
+	case note == "":
+		return value
+	case strings.TrimSpace(value) == "":
+		return note
+	}
+	return note + "\n\n" + value
+}
+
+// reloadReviewDialog re-reads the session's changes and re-renders the Review dialog, keeping the selection near where it was.
+func (m *model) reloadReviewDialog() error {
+	dlg := m.reviewDialog
+	files, err := m.session.changes.Files()
+	if err != nil {
+		return err
+	}
+	dlg.files = files
+	dlg.diffs = make([]diff.Diff, len(files))
+	for i, f := range files {
+		if !f.binary {
+			dlg.diffs[i] = f.Diff()
+		}
+	}
+	m.renderReviewDialogBody()
+	return nil
+}
+
+// renderReviewDialogBody renders the files and hunks into the dialog's view and recomputes its items. The selected item is prefixed with reviewDialogSelectedBar.
+func (m *model) renderReviewDialogBody() {
+	dlg := m.reviewDialog
+	dlg.items = dlg.items[:0]
+	var lines []string
+	addItem := func(file, hunk int, itemLines []string) {
+		selected := len(dlg.items) == dlg.selected
+		dlg.items = append(dlg.items, reviewItem{file: file, hunk: hunk, start: len(lines), end: len(lines) + len(itemLines)})
+		prefix := "  "
+		if selected {
+			prefix = termformat.Style{Foreground: m.palette.accentForeground, Bold: termformat.StyleSetOn}.Wrap(reviewDialogSelectedBar)
+		}
+		for _, line := range itemLines {
+			lines = append(lines, prefix+line)
+		}
+	}
+
+	// Clamp the selection before rendering, since it determines which item gets the bar.
+	numItems := 0
+	for i, f := range dlg.files {
+		numItems++
+		if !f.binary {
+			numItems += len(changedHunks(dlg.diffs[i]))
+		}
+	}
+	if dlg.selected >= numItems {
+		dlg.selected = numItems - 1
+	}
+	if dlg.selected < 0 {
+		dlg.selected = 0
+	}
+
+	for i, f := range dlg.files {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		header := termformat.Style{Foreground: m.palette.primaryForeground, Bold: termformat.StyleSetOn}.Wrap(termformat.Sanitize(f.path, 4) + reviewFileSuffix(f))
+		addItem(i, -1, []string{header})
+		if f.binary {
+			lines = append(lines, "  "+termformat.Style{Foreground: m.palette.accentForeground}.Wrap("Binary file; it can only be reverted as a whole."))
+			continue
+		}
+		d := dlg.diffs[i]
+		for _, h := range changedHunks(d) {
+			itemLines := []string{termformat.Style{Foreground: m.palette.accentForeground}.Wrap(fmt.Sprintf("@ line %d", hunkLine(d, h)))}
+			itemLines = append(itemLines, strings.Split(hunkDiff(d, h).RenderPretty("", "", reviewDialogContextLines), "\n")...)
+			addItem(i, h, itemLines)
+		}
+	}
+	dlg.view.SetContent(strings.Join(lines, "\n"))
+	m.reviewDialogEnsureSized()
+	m.reviewDialogScrollToSelected()
+}
+
+// reviewDialogEnsureSized sizes the Review dialog's view for the current terminal size (see detailsDialogEnsureSized).
+func (m *model) reviewDialogEnsureSized() {
+	if m.reviewDialog == nil || m.windowWidth <= 0 || m.windowHeight <= 0 {
+		return
+	}
+	// Margins, border (2 rows/cols), and padding (2 rows/cols).
+	innerW := m.windowWidth - 2*detailsDialogMargin - 4
+	innerH := m.windowHeight - 2*detailsDialogMargin - 4
+	m.reviewDialog.view.SetSize(max(innerW, 1), max(innerH-reviewDialogHeaderHeight, 1))
+}
+
+// reviewDialogScrollToSelected scrolls the Review dialog so the selected item is visible, showing as much of it as fits.
+func (m *model) reviewDialogScrollToSelected() {
+	dlg := m.reviewDialog
+	if dlg.selected >= len(dlg.items) {
+		return
+	}
+	item := dlg.items[dlg.selected]
+	top := dlg.view.Offset()
+	height := dlg.view.Height()
+	switch {
+	case item.start < top:
+		dlg.view.ScrollUp(top - item.start)
+	case item.end > top+height:
+		dlg.view.ScrollDown(min(item.end-(top+height), item.start-top))
+	}
+}
+
+// reviewDialogView renders the Review dialog over a blank background.
+func (m *model) reviewDialogView() string {
+	dlg := m.reviewDialog
+	m.reviewDialogEnsureSized()
+
+	innerW := max(m.windowWidth-2*detailsDialogMargin-4, 1)
+	title := fmt.Sprintf("Changes since the session started (%d files)", len(dlg.files))
+	if len(dlg.files) == 1 {
+		title = "Changes since the session started (1 file)"
+	}
+	title = termformat.Style{Foreground: m.palette.primaryForeground, Bold: termformat.StyleSetOn}.Wrap(title)
+
+	hint := "↑/↓ select · r revert · n note to agent · ESC close"
+	switch {
+	case dlg.mode == reviewDialogModeConfirm:
+		hint = fmt.Sprintf("Revert %s? y to confirm, any other key to cancel", m.describeReviewItem(dlg.items[dlg.selected]))
+	case dlg.mode == reviewDialogModeNote:
+		hint = fmt.Sprintf("I reverted %s because: %s▏ (Enter to send, ESC to cancel)", joinWithAnd(dlg.reverted), dlg.note)
+	case dlg.status != "":
+		hint = dlg.status
+	}
+	hint = termformat.Style{Foreground: m.palette.accentForeground}.Wrap(termformat.Sanitize(hint, 4))
+
+	// Keep the header one row each. While typing a note, keep its end (where the cursor is) visible.
+	var b strings.Builder
+	b.WriteString(clipToWidth(title, innerW, false))
+	b.WriteString("\n\n")
+	b.WriteString(clipToWidth(hint, innerW, dlg.mode == reviewDialogModeNote))
+	b.WriteString("\n\n")
+	b.WriteString(dlg.view.View())
+	return m.modalDialogView(b.String(), "review")
+}
+
+// handleReviewDialogKey handles a key press while the Review dialog is open. All keys are consumed.
+func (m *model) handleReviewDialogKey(key qtui.KeyEvent) {
+	dlg := m.reviewDialog
+	dlg.status = ""
+	switch dlg.mode {
+	case reviewDialogModeConfirm:
+		dlg.mode = reviewDialogModeBrowse
+		if key.ControlKey == qtui.ControlKeyNone && string(key.Runes) == "y" {
+			m.revertReviewItem()
+		}
+		return
+	case reviewDialogModeNote:
+		m.handleReviewNoteKey(key)
+		return
+	}
+
+	switch key.ControlKey {
+	case qtui.ControlKeyEsc:
+		m.closeReviewDialog()
+	case qtui.ControlKeyUp:
+		m.selectReviewItem(dlg.selected - 1)
+	case qtui.ControlKeyDown:
+		m.selectReviewItem(dlg.selected + 1)
+	case qtui.ControlKeyPageUp, qtui.ControlKeyCtrlPageUp:
+		dlg.view.PageUp()
+	case qtui.ControlKeyPageDown, qtui.ControlKeyCtrlPageDown:
+		dlg.view.PageDown()
+	case qtui.ControlKeyHome, qtui.ControlKeyCtrlHome:
+		m.selectReviewItem(0)
+	case qtui.ControlKeyEnd, qtui.ControlKeyCtrlEnd:
+		m.selectReviewItem(len(dlg.items) - 1)
+	case qtui.ControlKeyNone:
+		switch string(key.Runes) {
+		case "r":
+			dlg.mode = reviewDialogModeConfirm
+		case "n":
+			if len(dlg.reverted) == 0 {
+				dlg.status = "Nothing reverted yet; revert a hunk or file first."
+				return
+			}
+			dlg.mode = reviewDialogModeNote
+			dlg.note = ""
+		}
+	}
+}
+
+// handleReviewNoteKey edits the note while the Review dialog is in reviewDialogModeNote. Enter sends it; Esc cancels.
+func (m *model) handleReviewNoteKey(key qtui.KeyEvent) {
+	dlg := m.reviewDialog
+	switch key.ControlKey {
+	case qtui.ControlKeyEsc:
+		dlg.mode = reviewDialogModeBrowse
+	case qtui.ControlKeyEnter:
+		dlg.mode = reviewDialogModeBrowse
+		m.sendReviewNote(dlg, dlg.note)
+		dlg.status = "Sent the note to the agent."
+	case qtui.ControlKeyBackspace, qtui.ControlKeyCtrlH:
+		if dlg.note != "" {
+			_, size := utf8.DecodeLastRuneInString(dlg.note)
+			dlg.note = dlg.note[:len(dlg.note)-size]
+		}
+	case qtui.ControlKeyNone:
+		if !key.Alt {
+			dlg.note += strings.ReplaceAll(string(key.Runes), "\n", " ")
+		}
+	}
+}
+
+// selectReviewItem selects item i (clamped to the items) and scrolls it into view.
+func (m *model) selectReviewItem(i int) {
+	dlg := m.reviewDialog
+	dlg.selected = max(min(i, len(dlg.items)-1), 0)
+	m.renderReviewDialogBody()
+}
+
+// revertReviewItem reverts the selected hunk or file, then reloads the dialog. If the file changed since the dialog last loaded it, nothing is reverted. The dialog
+// closes once nothing is left to review.
+func (m *model) revertReviewItem() {
+	dlg := m.reviewDialog
+	if dlg.selected >= len(dlg.items) {
+		return
+	}
+	item := dlg.items[dlg.selected]
+	f := dlg.files[item.file]
+	desc := m.describeReviewItem(item)
+
+	var err error
+	if item.hunk < 0 {
+		err = m.session.changes.RevertFile(f)
+	} else {
+		err = m.session.changes.RevertHunk(f, item.hunk)
+	}
+	switch {
+	case errors.Is(err, errSessionFileModified):
+		dlg.status = fmt.Sprintf("%s changed since it was shown; nothing was reverted. Review the reloaded changes and try again.", f.path)
+	case err != nil:
+		dlg.status = fmt.Sprintf("Could not revert %s: %v", desc, err)
+	default:
+		dlg.reverted = append(dlg.reverted, desc)
+		dlg.status = fmt.Sprintf("Reverted %s. Press n to tell the agent why.", desc)
+	}
+
+	if err := m.reloadReviewDialog(); err != nil {
+		dlg.status = fmt.Sprintf("Could not list changes: %v", err)
+		return
+	}
+	if len(dlg.files) == 0 {
+		m.closeReviewDialog()
+	}
+}
+
+// describeReviewItem describes item for "I reverted X" (ex: "your change at a.go:12" or "your changes to a.go").
+func (m *model) describeReviewItem(item reviewItem) string {
+	dlg := m.reviewDialog
+	f := dlg.files[item.file]
+	switch {
+	case item.hunk >= 0:
+		return fmt.Sprintf("your change at %s:%d", f.path, hunkLine(dlg.diffs[item.file], item.hunk))
+	case f.added:
+		return fmt.Sprintf("the creation of %s", f.path)
+	case f.deleted:
+		return fmt.Sprintf("the deletion of %s", f.path)
+	default:
+		return fmt.Sprintf("your changes to %s", f.path)
+	}
+}
+
+// reviewFileSuffix annotates a file header in the Review dialog.
+func reviewFileSuffix(f sessionFileChange) string {
+	switch {
+	case f.added:
+		return " (new file)"
+	case f.deleted:
+		return " (deleted)"
+	default:
+		return ""
+	}
+}
+
+// changedHunks returns the indexes of d's hunks that aren't OpEqual.
+func changedHunks(d diff.Diff) []int {
+	var hunks []int
+	for i, h := range d.Hunks {
+		if h.Op != diff.OpEqual {
+			hunks = append(hunks, i)
+		}
+	}
+	return hunks
+}
+
+// hunkLine returns the 1-based line in d's new text where hunk starts (for deletions, the line the deleted text would be restored at).
+func hunkLine(d diff.Diff, hunk int) int {
+	line := 1
+	for _, h := range d.Hunks[:hunk] {
+		line += strings.Count(h.NewText, "\n")
+	}
+	return line
+}
+
+// hunkDiff returns a diff of only hunk of d and its neighboring unchanged hunks, so that it renders as a single group of changes with context. The text is sanitized
+// for terminal display.
+func hunkDiff(d diff.Diff, hunk int) diff.Diff {
+	start, end := hunk, hunk+1
+	if start > 0 && d.Hunks[start-1].Op == diff.OpEqual {
+		start--
+	}
+	if end < len(d.Hunks) && d.Hunks[end].Op == diff.OpEqual {
+		end++
+	}
+	var oldText, newText strings.Builder
+	for _, h := range d.Hunks[start:end] {
+		oldText.WriteString(h.OldText)
+		newText.WriteString(h.NewText)
+	}
+	// Sanitizing keeps lines intact, so re-diffing the sanitized text yields the same changes.
+	return diff.DiffText(termformat.Sanitize(oldText.String(), 4), termformat.Sanitize(newText.String(), 4))
+}
+
+// clipToWidth clips the single-line s to width cells, dropping cells from the start if fromLeft, and from the end otherwise.
+func clipToWidth(s string, width int, fromLeft bool) string {
+	over := termformat.TextWidthWithANSICodes(s) - width
+	if over <= 0 {
+		return s
+	}
+	if fromLeft {
+		return termformat.Cut(s, over, 0)
+	}
+	return termformat.Cut(s, 0, over)
+}
+
+// joinWithAnd joins items as an English list (ex: "a, b, and c").
+func joinWithAnd(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return items[0] + " and " + items[1]
+	default:
+		return strings.Join(items[:len(items)-1], ", ") + ", and " + items[len(items)-1]
+	}
+}
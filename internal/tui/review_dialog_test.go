@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	qtui "github.com/codalotl/codalotl/internal/q/tui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionChangesRevert(t *testing.T) {
+	repo := newSessionChangesTestRepo(t)
+	aPath := filepath.Join(repo, "a.txt")
+	require.NoError(t, os.WriteFile(aPath, []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n"), 0644))
+	changes := captureSessionChanges(repo)
+	require.NotNil(t, changes)
+
+	require.NoError(t, os.WriteFile(aPath, []byte("1\ntwo\n3\n4\n5\n6\n7\neight\n9\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "new.txt"), []byte("new\n"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(repo, "sub", "keep.txt")))
+
+	files, err := changes.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	assert.Equal(t, "a.txt", files[0].path)
+	assert.Equal(t, "new.txt", files[1].path)
+	assert.True(t, files[1].added)
+	assert.Equal(t, "sub/keep.txt", files[2].path)
+	assert.True(t, files[2].deleted)
+	assert.Equal(t, "keep\n", files[2].oldText)
+
+	// Reverting the first hunk keeps the second change.
+	hunks := changedHunks(files[0].Diff())
+	require.Len(t, hunks, 2)
+	require.NoError(t, changes.RevertHunk(files[0], hunks[0]))
+	data, err := os.ReadFile(aPath)
+	require.NoError(t, err)
+	assert.Equal(t, "1\n2\n3\n4\n5\n6\n7\neight\n9\n", string(data))
+
+	// files[0] is now stale, so reverting from it is refused.
+	assert.ErrorIs(t, changes.RevertHunk(files[0], hunks[1]), errSessionFileModified)
+
+	require.NoError(t, changes.RevertFile(files[1]))
+	assert.NoFileExists(t, filepath.Join(repo, "new.txt"))
+	require.NoError(t, changes.RevertFile(files[2]))
+	data, err = os.ReadFile(filepath.Join(repo, "sub", "keep.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "keep\n", string(data))
+
+	files, err = changes.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "a.txt", files[0].path)
+}
+
+func TestReviewDialogRevertsAndSendsNote(t *testing.T) {
+	repo := newSessionChangesTestRepo(t)
+	aPath := filepath.Join(repo, "a.txt")
+	require.NoError(t, os.WriteFile(aPath, []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n"), 0644))
+
+	m := newModel(colorPalette{}, noopFormatter{}, nil, sessionConfig{}, nil, nil, nil, nil)
+	m.Update(nil, qtui.ResizeEvent{Width: 80, Height: 30})
+	m.session = &session{changes: captureSessionChanges(repo)}
+	require.NotNil(t, m.session.changes)
+	var sent []string
+	m.startAgentRunHook = func(value string) { sent = append(sent, value) }
+
+	require.True(t, m.handleSlashCommand("/changes"))
+	assert.Nil(t, m.reviewDialog)
+	assert.Contains(t, m.messages[len(m.messages)-1].userMessage, "No changes since the session started.")
+	assert.False(t, m.shouldSaveToHistory("/changes"))
+
+	require.NoError(t, os.WriteFile(aPath, []byte("1\ntwo\n3\n4\n5\n6\n7\neight\n9\n"), 0644))
+	require.True(t, m.handleSlashCommand("/changes"))
+	require.NotNil(t, m.reviewDialog)
+	view := stripAnsi(m.View())
+	assert.Contains(t, view, "a.txt")
+	assert.Contains(t, view, "@ line 2")
+	assert.Contains(t, view, "@ line 8")
+
+	key := func(r string) qtui.KeyEvent {
+		return qtui.KeyEvent{ControlKey: qtui.ControlKeyNone, Runes: []rune(r)}
+	}
+
+	// A note needs something to explain.
+	m.Update(nil, key("n"))
+	assert.Contains(t, stripAnsi(m.View()), "Nothing reverted yet")
+
+	// Select the first hunk; "r" then any key but "y" cancels.
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyDown})
+	m.Update(nil, key("r"))
+	assert.Contains(t, stripAnsi(m.View()), "Revert your change at a.txt:2?")
+	m.Update(nil, key("x"))
+	data, err := os.ReadFile(aPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "two")
+
+	m.Update(nil, key("r"))
+	m.Update(nil, key("y"))
+	data, err = os.ReadFile(aPath)
+	require.NoError(t, err)
+	assert.Equal(t, "1\n2\n3\n4\n5\n6\n7\neight\n9\n", string(data))
+	require.NotNil(t, m.reviewDialog)
+	assert.NotContains(t, stripAnsi(m.View()), "@ line 2")
+
+	m.Update(nil, key("n"))
+	for _, r := range "it broke the build" {
+		m.Update(nil, key(string(r)))
+	}
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyEnter})
+	assert.Equal(t, []string{"I reverted your change at a.txt:2 because it broke the build."}, sent)
+
+	// Closing sends nothing more, since every revert was reported.
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyEsc})
+	assert.Nil(t, m.reviewDialog)
+	assert.Len(t, sent, 1)
+}
+
+func TestReviewDialogReportsUnexplainedRevertsOnClose(t *testing.T) {
+	repo := newSessionChangesTestRepo(t)
+
+	m := newModel(colorPalette{}, noopFormatter{}, nil, sessionConfig{}, nil, nil, nil, nil)
+	m.Update(nil, qtui.ResizeEvent{Width: 80, Height: 30})
+	m.session = &session{changes: captureSessionChanges(repo)}
+	require.NotNil(t, m.session.changes)
+	var sent []string
+	m.startAgentRunHook = func(value string) { sent = append(sent, value) }
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "new.txt"), []byte("new\n"), 0644))
+	m.openReviewDialog()
+	require.NotNil(t, m.reviewDialog)
+	assert.Contains(t, stripAnsi(m.View()), "new.txt (new file)")
+
+	// Reverting the whole (only) file closes the dialog. The revert is reported with the next message instead of starting a turn by itself.
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyNone, Runes: []rune("r")})
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyNone, Runes: []rune("y")})
+	assert.NoFileExists(t, filepath.Join(repo, "new.txt"))
+	assert.Nil(t, m.reviewDialog)
+	assert.Empty(t, sent)
+	assert.Contains(t, m.messages[len(m.messages)-1].userMessage, "Your next message will start with: I reverted the creation of new.txt.")
+
+	m.textarea.SetContents("try a different name")
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyEnter})
+	assert.Equal(t, []string{"I reverted the creation of new.txt.\n\ntry a different name"}, sent)
+
+	// The note is only sent once.
+	m.textarea.SetContents("thanks")
+	m.Update(nil, qtui.KeyEvent{ControlKey: qtui.ControlKeyEnter})
+	assert.Equal(t, "thanks", sent[1])
+}
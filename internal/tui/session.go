@@ -16,6 +16,7 @@ import (
 	"github.com/codalotl/codalotl/internal/initialcontext"
 	"github.com/codalotl/codalotl/internal/lints"
 	"github.com/codalotl/codalotl/internal/llmmodel"
+	"github.com/codalotl/codalotl/internal/llmstream"
 	"github.com/codalotl/codalotl/internal/prompt"
 	"github.com/codalotl/codalotl/internal/skills"
 	"github.com/codalotl/codalotl/internal/tools/authdomain"
@@ -50,16 +51,20 @@ type session struct {
 	authorizer    authdomain.Authorizer         // authorizer mediates tool permissions for this session and must be closed when the session ends.
 	userRequests  <-chan authdomain.UserRequest // userRequests receives permission prompts emitted by the session authorizer.
 	config        sessionConfig                 // config is the normalized configuration used to construct this session.
+	changes       *sessionChanges               // changes snapshots the sandbox's git worktree at session start for /diff and /changes; nil outside a git repo.
 }
 
 // sessionConfig configures construction and reset of a TUI agent session.
 type sessionConfig struct {
 	packagePath string           // Package path selects Package Mode when non-blank and is interpreted relative to the sandbox.
-	agentName   string           // Agent name selects a specialized non-package agent; Package Mode overrides it.
+	agentName   string           // Agent name selects a specialized agent; an empty value uses the mode's default agent.
 	modelID     llmmodel.ModelID // Model ID selects the LLM model; an empty value uses the default model.
 	lintSteps   []lints.Step     // Lint steps configure package checks used by tools and package-context gathering.
 	autoYes     bool             // Auto yes approves permission requests through the session authorizer.
 
+	// allowedTools, if non-nil, restricts the agent's tools to these names (set by custom slash commands).
+	allowedTools []string
+
 	// sandboxDir, if set, overrides the default sandbox detection (os.Getwd). This is primarily to make tests independent of process-wide working directory and to avoid
 	// path aliasing issues (ex: /var vs /private/var on macOS).
 	sandboxDir string
@@ -139,9 +144,6 @@ func newSession(cfg sessionConfig) (*session, error) {
 		LintSteps:  cfg.lintSteps,
 	}
 	agentName := agentbuilder.AgentGeneric
-	if cfg.orchestrateMode() {
-		agentName = orchestrateAgentName
-	}
 	if cfg.packageMode() {
 		unit, err := codeunit.DefaultGoCodeUnit(pkgAbsPath)
 		if err != nil {
@@ -154,6 +156,9 @@ func newSession(cfg sessionConfig) (*session, error) {
 		toolOptions.GoPkgAbsDir = pkgAbsPath
 		agentName = agentbuilder.AgentPackageModeNoContext
 	}
+	if cfg.agentName != "" {
+		agentName = cfg.agentName
+	}
 
 	registry, err := agentbuilder.BuildRegistry()
 	if err != nil {
@@ -168,6 +173,7 @@ func newSession(cfg sessionConfig) (*session, error) {
 		sandboxAuthorizer.Close()
 		return nil, fmt.Errorf("prepare agent: %w", err)
 	}
+	prepared.RestrictTools(cfg.allowedTools)
 	prepared.InitialTurns = append(prepared.InitialTurns, buildEnvironmentInfo(sandboxDir))
 
 	agentInstance, err := prepared.Create(newSessionAgentCreator())
@@ -190,6 +196,7 @@ func newSession(cfg sessionConfig) (*session, error) {
 		authorizer:       toolAuthorizer,
 		userRequests:     userRequests,
 		config:           cfg,
+		changes:          captureSessionChanges(sandboxDir),
 	}, nil
 }
 
@@ -226,6 +233,17 @@ func (s *session) SendMessage(ctx context.Context, message string) <-chan agent.
 	return s.agent.SendUserMessage(ctx, message)
 }
 
+// SendMessageWithImages is like SendMessage, but attaches images to the user message.
+func (s *session) SendMessageWithImages(ctx context.Context, message string, images []llmstream.ImageContent) <-chan agent.Event {
+	if len(images) == 0 {
+		return s.SendMessage(ctx, message)
+	}
+	if s == nil || s.agent == nil {
+		return nil
+	}
+	return s.agent.SendUserMessageWithImages(ctx, message, images)
+}
+
 // QueueUserMessage queues message for delivery at the agent's next safe boundary.
 func (s *session) QueueUserMessage(message string) error {
 	if s == nil {
@@ -0,0 +1,259 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/codalotl/codalotl/internal/diff"
+)
+
+// sessionChanges is a snapshot of the sandbox's git worktree taken when a session starts. It lets the TUI show what changed during the session (ex: `/diff`), including
+// changes the user made outside the agent.
+type sessionChanges struct {
+	repoDir   string          // repoDir is the root of the git worktree.
+	base      string          // base is a commit holding the tracked contents of the worktree at session start (HEAD plus any uncommitted changes).
+	untracked map[string]bool // untracked are the repo-relative untracked, non-ignored files that existed at session start.
+}
+
+// captureSessionChanges snapshots the git worktree containing dir. It returns nil if dir isn't in a git worktree with at least one commit, or if git isn't available.
+func captureSessionChanges(dir string) *sessionChanges {
+	root, err := runSessionGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil
+	}
+	root = strings.TrimSpace(root)
+
+	// `git stash create` records uncommitted tracked changes as a commit without touching refs, the index, or the worktree. It prints nothing for a clean worktree.
+	// The commit is never referenced, so a fixed identity avoids failing when the user has none configured.
+	base, err := runSessionGit(root, "-c", "user.name=codalotl", "-c", "user.email=codalotl@localhost", "stash", "create")
+	if err != nil {
+		return nil
+	}
+	base = strings.TrimSpace(base)
+	if base == "" {
+		base, err = runSessionGit(root, "rev-parse", "--verify", "HEAD")
+		if err != nil {
+			return nil
+		}
+		base = strings.TrimSpace(base)
+	}
+
+	untracked, err := listUntrackedFiles(root)
+	if err != nil {
+		return nil
+	}
+	set := make(map[string]bool, len(untracked))
+	for _, path := range untracked {
+		set[path] = true
+	}
+	return &sessionChanges{repoDir: root, base: base, untracked: set}
+}
+
+// Diff returns a unified diff of the worktree against the session-start snapshot: changes to tracked files, plus files created since the session started. Untracked
+// files that already existed at session start are not shown. If color, the diff includes ANSI color codes. It returns "" if nothing changed.
+func (c *sessionChanges) Diff(color bool) (string, error) {
+	colorArg := "--color=never"
+	if color {
+		colorArg = "--color=always"
+	}
+
+	var out strings.Builder
+	tracked, err := runSessionGit(c.repoDir, "diff", colorArg, c.base)
+	if err != nil {
+		return "", err
+	}
+	out.WriteString(tracked)
+
+	untracked, err := listUntrackedFiles(c.repoDir)
+	if err != nil {
+		return "", err
+	}
+	for _, path := range untracked {
+		if c.untracked[path] {
+			continue
+		}
+		// --no-index exits 1 when the files differ, which is always the case here.
+		added, err := runSessionGit(c.repoDir, "diff", "--no-index", colorArg, "--", "/dev/null", path)
+		var exitErr *exec.ExitError
+		if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+			return "", err
+		}
+		out.WriteString(added)
+	}
+	return out.String(), nil
+}
+
+// sessionFileChange is one file that changed since the session started.
+type sessionFileChange struct {
+	path    string // path is the repo-relative path, with forward slashes.
+	oldText string // oldText is the file's contents at session start ("" if added).
+	newText string // newText is the file's current contents ("" if deleted).
+	added   bool   // added reports whether the file didn't exist at session start.
+	deleted bool   // deleted reports whether the file no longer exists.
+	binary  bool   // binary reports whether either version looks binary (contains a NUL byte). Binary files can only be reverted as a whole.
+}
+
+// Diff returns the line diff from f's session-start contents to its current contents.
+func (f sessionFileChange) Diff() diff.Diff {
+	return diff.DiffText(f.oldText, f.newText)
+}
+
+// errSessionFileModified is returned when reverting a file whose contents changed since its sessionFileChange was read.
+var errSessionFileModified = errors.New("file changed since it was read")
+
+// Files returns the files that changed since the session started, sorted by path: tracked files that differ from the session-start snapshot, plus files created since
+// the session started. Like Diff, untracked files that already existed at session start are not included.
+func (c *sessionChanges) Files() ([]sessionFileChange, error) {
+	out, err := runSessionGit(c.repoDir, "diff", "--name-status", "--no-renames", "-z", c.base)
+	if err != nil {
+		return nil, err
+	}
+	var files []sessionFileChange
+	fields := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		status, path := fields[i], fields[i+1]
+		f := sessionFileChange{path: path, added: status == "A", deleted: status == "D"}
+		if !f.added {
+			old, err := runSessionGit(c.repoDir, "show", c.base+":"+path)
+			if err != nil {
+				return nil, err
+			}
+			f.oldText = old
+		}
+		if !f.deleted {
+			data, err := os.ReadFile(filepath.Join(c.repoDir, filepath.FromSlash(path)))
+			if err != nil {
+				return nil, err
+			}
+			f.newText = string(data)
+		}
+		files = append(files, f)
+	}
+
+	untracked, err := listUntrackedFiles(c.repoDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range untracked {
+		if c.untracked[path] {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.repoDir, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sessionFileChange{path: path, newText: string(data), added: true})
+	}
+
+	for i := range files {
+		files[i].binary = strings.IndexByte(files[i].oldText, 0) >= 0 || strings.IndexByte(files[i].newText, 0) >= 0
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+// RevertFile restores f to its session-start contents, deleting it if it was added. It returns errSessionFileModified (without changing anything) if the file no longer
+// has f's current contents.
+func (c *sessionChanges) RevertFile(f sessionFileChange) error {
+	if f.added {
+		return c.writeFileContents(f, "", true)
+	}
+	return c.writeFileContents(f, f.oldText, false)
+}
+
+// RevertHunk restores hunk (an index into f.Diff().Hunks) to its session-start contents, keeping the file's other changes. If f was added and nothing remains, the
+// file is deleted. It returns errSessionFileModified (without changing anything) if the file no longer has f's current contents.
+func (c *sessionChanges) RevertHunk(f sessionFileChange, hunk int) error {
+	d := f.Diff()
+	if hunk < 0 || hunk >= len(d.Hunks) || d.Hunks[hunk].Op == diff.OpEqual {
+		return fmt.Errorf("%s has no change at hunk %d", f.path, hunk)
+	}
+	var b strings.Builder
+	for i, h := range d.Hunks {
+		if i == hunk {
+			b.WriteString(h.OldText)
+		} else {
+			b.WriteString(h.NewText)
+		}
+	}
+	reverted := b.String()
+	return c.writeFileContents(f, reverted, f.added && reverted == "")
+}
+
+// writeFileContents replaces f's current contents with text, or deletes the file if remove. It first checks that the file still has f's current contents.
+func (c *sessionChanges) writeFileContents(f sessionFileChange, text string, remove bool) error {
+	abs := filepath.Join(c.repoDir, filepath.FromSlash(f.path))
+	data, err := os.ReadFile(abs)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if !f.deleted {
+			return fmt.Errorf("%s: %w", f.path, errSessionFileModified)
+		}
+	case err != nil:
+		return err
+	case f.deleted || string(data) != f.newText:
+		return fmt.Errorf("%s: %w", f.path, errSessionFileModified)
+	}
+
+	if remove {
+		if f.deleted {
+			return nil
+		}
+		return os.Remove(abs)
+	}
+
+	mode := fs.FileMode(0644)
+	if info, err := os.Stat(abs); err == nil {
+		mode = info.Mode().Perm()
+	} else if c.baseFileExecutable(f.path) {
+		mode = 0755
+	}
+	if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(abs, []byte(text), mode)
+}
+
+// baseFileExecutable reports whether path is executable in the session-start snapshot.
+func (c *sessionChanges) baseFileExecutable(path string) bool {
+	out, err := runSessionGit(c.repoDir, "ls-tree", c.base, "--", path)
+	return err == nil && strings.HasPrefix(out, "100755 ")
+}
+
+// listUntrackedFiles returns the repo-relative untracked, non-ignored files in the worktree rooted at repoDir.
+func listUntrackedFiles(repoDir string) ([]string, error) {
+	out, err := runSessionGit(repoDir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, path := range strings.Split(out, "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// runSessionGit runs git in dir and returns its stdout. On failure, the error includes git's stderr. Stdout is returned even on failure, since some commands (ex:
+// `git diff --no-index`) exit non-zero on success.
+func runSessionGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("git %s: %s: %w", strings.Join(args, " "), msg, err)
+		}
+		return stdout.String(), err
+	}
+	return stdout.String(), nil
+}
@@ -63,77 +63,12 @@ func formatContextUsage(percentUsed int, usedTokens int64) string {
 }
 
 func formatCostLine(usage llmstream.TokenUsage, info llmmodel.ModelInfo) string {
-	if cost, ok := estimateUsageCostUSD(usage, info); ok {
+	if cost, ok := usage.EstimatedCostUSD(info); ok {
 		return fmt.Sprintf("$%.2f", cost)
 	}
 	return "unavailable"
 }
 
-// estimateUsageCostUSD estimates the total USD cost for usage using the model's pricing metadata.
-func estimateUsageCostUSD(usage llmstream.TokenUsage, info llmmodel.ModelInfo) (float64, bool) {
-	if info.ID == llmmodel.ModelIDUnknown {
-		return 0, false
-	}
-
-	const million = 1_000_000.0
-
-	uncached := uncachedInputTokens(usage)
-	cacheCreation := clamp64(usage.CacheCreationInputTokens)
-
-	var (
-		totalCost float64
-		missing   bool
-	)
-
-	if uncached > 0 {
-		if info.CostPer1MIn > 0 {
-			totalCost += (float64(uncached) / million) * info.CostPer1MIn
-		} else {
-			missing = true
-		}
-	}
-
-	if usage.CachedInputTokens > 0 {
-		rate := info.CostPer1MInCached
-		if rate <= 0 {
-			rate = info.CostPer1MIn
-		}
-		if rate > 0 {
-			totalCost += (float64(usage.CachedInputTokens) / million) * rate
-		} else {
-			missing = true
-		}
-	}
-
-	if cacheCreation > 0 {
-		rate := info.CostPer1MInSaveToCache
-		if rate <= 0 {
-			rate = info.CostPer1MIn
-		}
-		if rate > 0 {
-			totalCost += (float64(cacheCreation) / million) * rate
-		} else {
-			missing = true
-		}
-	}
-
-	if usage.TotalOutputTokens > 0 {
-		if info.CostPer1MOut > 0 {
-			totalCost += (float64(usage.TotalOutputTokens) / million) * info.CostPer1MOut
-		} else {
-			missing = true
-		}
-	}
-
-	if missing {
-		return 0, false
-	}
-	if totalCost == 0 && (usage.TotalInputTokens > 0 || usage.TotalOutputTokens > 0) {
-		return 0, false
-	}
-	return totalCost, true
-}
-
 func formatTokenCount(tokens int64) string {
 	value := tokens
 	if value < 0 {
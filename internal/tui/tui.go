@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/codalotl/codalotl/internal/agent"
+	"github.com/codalotl/codalotl/internal/agentbuilder"
 	"github.com/codalotl/codalotl/internal/agentformatter"
 	"github.com/codalotl/codalotl/internal/llmmodel"
 	"github.com/codalotl/codalotl/internal/llmstream"
@@ -20,6 +22,7 @@ import (
 	qtui "github.com/codalotl/codalotl/internal/q/tui"
 	"github.com/codalotl/codalotl/internal/q/tui/tuicontrols"
 	"github.com/codalotl/codalotl/internal/skills"
+	"github.com/codalotl/codalotl/internal/slashcommands"
 	"github.com/codalotl/codalotl/internal/tools/authdomain"
 )
 
@@ -89,6 +92,10 @@ type chatMessage struct {
 
 	// FormattedWidth is the viewport width used to produce formatted.
 	formattedWidth int
+
+	tags         []string        // tags caches messageTags(msg) for Messages Area sections (see /filter).
+	tagsKind     messageKind     // tagsKind is the kind tags was computed for.
+	tagsForEvent agent.EventType // tagsForEvent is the event type tags was computed for; a tool call's tags are recomputed when its result replaces it.
 }
 
 // agentRun tracks an active agent execution and its event stream.
@@ -149,8 +156,9 @@ const (
 
 // queuedMessage is user text waiting to be delivered to the agent.
 type queuedMessage struct {
-	text string            // text is the user message to send.
-	dest queuedMessageDest // dest records where the pending message is queued.
+	text   string            // text is the user message to send.
+	dest   queuedMessageDest // dest records where the pending message is queued.
+	images []attachedImage   // images are attached to the message. Messages with images are always queued locally.
 }
 
 // toolDisplayScope records a tool call whose descendant subagent events are rendered under that tool.
@@ -288,11 +296,29 @@ type model struct {
 	clipboardSetter             func(text string)      // clipboardSetter is injected from *qtui.TUI in Init/Update, but can be overridden in tests.
 	osClipboardAvailable        func() bool            // OS clipboard integration (best-effort); separated for testability so unit tests don't mutate the real system clipboard.
 
+	// Exec process runs an interactive child process (editor, pager) with the terminal released. It is injected from *qtui.TUI in Init/Update, but can be overridden
+	// in tests.
+	execProcess func(cmd *exec.Cmd, done qtui.ExecDoneFunc)
+
 	// OS clipboard write performs best-effort direct clipboard writes.
 	osClipboardWrite func(text string) error
 
+	// OS clipboard read image reads a PNG image from the clipboard (for Ctrl+V image attachment).
+	osClipboardReadImage func() ([]byte, error)
+
+	// Clipboard image read pending is set while an asynchronous osClipboardReadImage call is in flight.
+	clipboardImageReadPending bool
+
+	// Pending images are attached to the next submitted user message.
+	pendingImages []attachedImage
+
+	// Pending review note (ex: "I reverted your changes to a.go.") is prepended to the next submitted user message. It's set when the Review dialog closes with unreported
+	// reverts.
+	pendingReviewNote string
+
 	now                    func() time.Time                        // now allows deterministic tests around transient UI state (ex: "copied!").
 	detailsDialog          *detailsDialog                          // detailsDialog is a modal "Details" overlay, opened from Overlay Mode for tool calls and package context gathering.
+	reviewDialog           *reviewDialog                           // reviewDialog is the modal Review overlay (/changes) of changes since the session started.
 	agentParents           map[string]string                       // Agent parents maps agent IDs to parent agent IDs for hierarchy routing.
 	subagentLabels         map[string]string                       // Subagent labels stores labels announced by subagent-start events.
 	activeToolScopes       map[string][]toolDisplayScope           // Active tool scopes records tool calls whose descendants render under that tool.
@@ -343,6 +369,7 @@ func newModel(
 		now:                    time.Now,
 		osClipboardAvailable:   clipboard.Available,
 		osClipboardWrite:       clipboard.Write,
+		osClipboardReadImage:   clipboard.ReadImage,
 		agentParents:           make(map[string]string),
 		subagentLabels:         make(map[string]string),
 		activeToolScopes:       make(map[string][]toolDisplayScope),
@@ -364,6 +391,9 @@ func (m *model) Init(t *qtui.TUI) {
 	if m.clipboardSetter == nil && t != nil {
 		m.clipboardSetter = t.SetClipboard
 	}
+	if m.execProcess == nil && t != nil {
+		m.execProcess = t.ExecProcess
+	}
 	if m.requests != nil {
 		m.startUserRequestListener(m.requestSource, m.requests)
 	}
@@ -380,6 +410,9 @@ func (m *model) Update(t *qtui.TUI, msg qtui.Message) {
 	if m.clipboardSetter == nil && t != nil {
 		m.clipboardSetter = t.SetClipboard
 	}
+	if m.execProcess == nil && t != nil {
+		m.execProcess = t.ExecProcess
+	}
 
 	switch ev := msg.(type) {
 	case qtui.KeyEvent:
@@ -403,6 +436,10 @@ func (m *model) Update(t *qtui.TUI, msg qtui.Message) {
 		if m.session != nil {
 			m.session.Close()
 		}
+	case editorDoneMsg:
+		m.handleEditorDone(ev)
+	case pagerDoneMsg:
+		m.handlePagerDone(ev)
 	case agentEventMsg:
 		if m.currentRun != nil && ev.runID == m.currentRun.id {
 			m.handleAgentEvent(ev.event)
@@ -443,6 +480,8 @@ func (m *model) Update(t *qtui.TUI, msg qtui.Message) {
 		m.latestVersion = ev.latest
 	case specConformanceResultMsg:
 		m.handleSpecConformanceResult(ev)
+	case clipboardImageMsg:
+		m.handleClipboardImage(ev)
 	case overlayCopyExpiredMsg:
 		m.clearExpiredOverlayCopyFeedback()
 		m.refreshViewport(false)
@@ -495,6 +534,9 @@ func (m *model) View() string {
 
 	if m.infoPanelWidth == 0 {
 		base := b.String()
+		if m.reviewDialog != nil {
+			return m.reviewDialogView()
+		}
 		if m.detailsDialog != nil {
 			return m.detailsDialogView(base)
 		}
@@ -510,6 +552,9 @@ func (m *model) View() string {
 	combo, err := termformat.Layout(blocks, nil)
 	if err == nil {
 		debugLogf("h=%d lines=%d rectHeight=%d", m.windowHeight, len(strings.Split(combo, "\n")), termformat.BlockHeight(combo))
+		if m.reviewDialog != nil {
+			return m.reviewDialogView()
+		}
 		if m.detailsDialog != nil {
 			return m.detailsDialogView(combo)
 		}
@@ -533,12 +578,25 @@ func (m *model) handleWindowSize(msg qtui.ResizeEvent) {
 	m.ready = true
 }
 
-// HandleMouseEvent applies mouse input to the active TUI view. Wheel events scroll the details dialog when one is open, otherwise they scroll the messages viewport.
+// HandleMouseEvent applies mouse input to the active TUI view. Wheel events scroll the open modal dialog (Review or Details) when there is one, otherwise they scroll
+// the messages viewport.
 func (m *model) handleMouseEvent(ev qtui.MouseEvent) {
 	if m.viewport == nil {
 		return
 	}
 
+	if m.reviewDialog != nil {
+		if ev.IsWheel() {
+			switch ev.Button {
+			case qtui.MouseButtonWheelUp:
+				m.reviewDialog.view.ScrollUp(mouseWheelScrollLines)
+			case qtui.MouseButtonWheelDown:
+				m.reviewDialog.view.ScrollDown(mouseWheelScrollLines)
+			}
+		}
+		return
+	}
+
 	if m.detailsDialog != nil {
 		// When a modal dialog is up, mouse interactions apply to it (or are ignored).
 		if ev.IsWheel() {
@@ -657,6 +715,11 @@ func (m *model) handleKeyEvent(key qtui.KeyEvent) (skipTextarea bool) {
 		return true
 	}
 
+	if m.reviewDialog != nil {
+		m.handleReviewDialogKey(key)
+		return true
+	}
+
 	if m.detailsDialog != nil {
 		switch key.ControlKey {
 		case qtui.ControlKeyEsc:
@@ -684,14 +747,28 @@ func (m *model) handleKeyEvent(key qtui.KeyEvent) (skipTextarea bool) {
 		return m.handlePermissionKey(key)
 	}
 
+	if m.handleViewportNavigationKey(key) {
+		return true
+	}
+
 	if m.cyclingMode && m.shouldExitCyclingForKey(key) {
 		m.exitCyclingModeForEditing()
 	}
 
+	// Files dropped onto the terminal arrive as a paste of their paths.
+	if key.Paste && m.attachPastedImagePaths(string(key.Runes)) {
+		return true
+	}
+
 	switch key.ControlKey {
 	case qtui.ControlKeyCtrlO:
 		m.toggleOverlayMode()
 		return true
+	case qtui.ControlKeyCtrlV:
+		return m.startClipboardImageRead()
+	case qtui.ControlKeyCtrlG:
+		m.editMessageInEditor()
+		return true
 	// Spec: these keys scroll the message area (viewport), not the text area.
 	case qtui.ControlKeyPageUp, qtui.ControlKeyCtrlPageUp:
 		if m.viewport != nil {
@@ -726,6 +803,9 @@ func (m *model) handleKeyEvent(key qtui.KeyEvent) (skipTextarea bool) {
 			m.exitCyclingModeToDefault()
 			return true
 		}
+		if m.clearPendingImages() {
+			return true
+		}
 		if m.isEditingHistory() {
 			// Spec: when editing a previous message (not cycling), ESC exits the edit state
 			// and clears the input (it does not re-enter cycling mode).
@@ -747,6 +827,17 @@ func (m *model) handleKeyEvent(key qtui.KeyEvent) (skipTextarea bool) {
 			value = m.textarea.Contents()
 		}
 		trimmed := strings.TrimSpace(value)
+		if trimmed == "" && len(m.pendingImages) > 0 {
+			images := m.takePendingImages()
+			m.exitEditingState()
+			if m.textarea != nil {
+				m.textarea.SetContents("")
+			}
+			value = m.withPendingReviewNote("")
+			m.sendOrQueueMessage(value, images...)
+			m.startAgentRunIfPossible(value, images...)
+			return true
+		}
 		if trimmed == "" {
 			if m.textarea != nil {
 				m.textarea.SetContents("")
@@ -769,8 +860,10 @@ func (m *model) handleKeyEvent(key qtui.KeyEvent) (skipTextarea bool) {
 		if m.textarea != nil {
 			m.textarea.SetContents("")
 		}
-		m.sendOrQueueMessage(value)
-		m.startAgentRunIfPossible(value)
+		images := m.takePendingImages()
+		value = m.withPendingReviewNote(value)
+		m.sendOrQueueMessage(value, images...)
+		m.startAgentRunIfPossible(value, images...)
 		return true
 	case qtui.ControlKeyUp:
 		if m.cyclingMode {
@@ -866,7 +959,19 @@ func (m *model) handleSlashCommand(cmd string) bool {
 	case "/permission":
 		m.triggerPermissionDemo()
 		return true
+	case "/diff":
+		m.handleDiffCommand()
+		return true
+	case "/changes":
+		m.openReviewDialog()
+		return true
+	case "/filter":
+		m.handleFilterCommand(fields[1:])
+		return true
 	default:
+		if m.handleCustomCommand(cmd) {
+			return true
+		}
 		m.appendSystemMessage(fmt.Sprintf("Command %s not supported.", cmd))
 		m.refreshViewport(true)
 		return true
@@ -877,6 +982,7 @@ func (m *model) handleSlashCommand(cmd string) bool {
 // when an active run must be canceled first.
 func (m *model) handleNewSessionCommand() {
 	cfg := m.sessionConfig
+	cfg.allowedTools = nil
 	message := ""
 	if m.isAgentRunning() {
 		message = "Stopping current task before starting a new session..."
@@ -890,6 +996,7 @@ func (m *model) handleGenericCommand() {
 	cfg := m.sessionConfig
 	cfg.packagePath = ""
 	cfg.agentName = ""
+	cfg.allowedTools = nil
 	m.requestSessionReset(cfg, "Generic mode enabled. Use `/package path/to/pkg` (path relative to sandbox) to select a package.")
 }
 
@@ -899,9 +1006,94 @@ func (m *model) handleOrchestrateCommand(arg string) {
 	cfg := m.sessionConfig
 	cfg.packagePath = ""
 	cfg.agentName = orchestrateAgentName
+	cfg.allowedTools = nil
 	m.requestSessionResetWithFollowUp(cfg, "", "", arg, true)
 }
 
+// handleCustomCommand handles a custom slash command from `.codalotl/commands` (see package slashcommands). It returns false if input does not name a custom command.
+// Commands that only supply a prompt are sent as a user message in the current session; commands that select an agent, mode, model, or tools start a new session
+// with the rendered prompt as its first message. Load and render errors are shown as system messages.
+func (m *model) handleCustomCommand(input string) bool {
+	name, args, ok := slashcommands.ParseInvocation(input)
+	if !ok {
+		return false
+	}
+
+	cfg := m.sessionConfig
+	sandboxDir, searchDir, pkgRelPath := cfg.sandboxDir, cfg.sandboxDir, ""
+	if m.session != nil {
+		sandboxDir, searchDir = m.session.sandboxDir, m.session.sandboxDir
+		if m.session.packageAbsPath != "" {
+			searchDir = m.session.packageAbsPath
+			pkgRelPath = m.session.packagePath
+		}
+	}
+	commands, failed, err := slashcommands.Load(slashcommands.SearchPaths(searchDir), agentbuilder.ToolNames())
+	if err != nil {
+		debugLogf("slashcommands.Load failed: %v", err)
+	}
+	for _, failure := range failed {
+		debugLogf("slash command load failed: %v", failure)
+	}
+
+	showError := func(msg string) {
+		m.appendSystemMessage(msg)
+		m.refreshViewport(true)
+		if m.viewport != nil {
+			m.viewport.ScrollToBottom()
+		}
+	}
+
+	command, ok := slashcommands.Lookup(commands, name)
+	if !ok {
+		if loadErr := slashcommands.LookupFailure(failed, name); loadErr != nil {
+			showError(fmt.Sprintf("Cannot run /%s: %v", name, loadErr))
+			return true
+		}
+		return false
+	}
+
+	if command.Mode == slashcommands.ModePackage && !cfg.packageMode() {
+		showError(fmt.Sprintf("Command /%s requires Package Mode. Use `/package path/to/pkg` first.", command.Name))
+		return true
+	}
+	if command.Mode == slashcommands.ModeGeneric {
+		pkgRelPath = ""
+	}
+	rendered, err := command.Render(slashcommands.TemplateData{
+		Args:       args,
+		Package:    pkgRelPath,
+		SandboxDir: sandboxDir,
+	})
+	if err != nil {
+		showError(fmt.Sprintf("Cannot run /%s: %v", command.Name, err))
+		return true
+	}
+
+	if !command.NeedsNewSession() {
+		m.sendOrQueueMessage(rendered)
+		m.startAgentRunIfPossible(rendered)
+		return true
+	}
+
+	if command.Mode != slashcommands.ModeDefault {
+		// An explicit mode selects that mode's default agent unless the command names one.
+		cfg.agentName = ""
+	}
+	if command.Mode == slashcommands.ModeGeneric {
+		cfg.packagePath = ""
+	}
+	if command.Agent != "" {
+		cfg.agentName = command.Agent
+	}
+	if command.Model != "" {
+		cfg.modelID = command.Model
+	}
+	cfg.allowedTools = command.AllowedTools
+	m.requestSessionResetWithFollowUp(cfg, "", "", rendered, true)
+	return true
+}
+
 // handleModelCommand handles `/model` command input. With an empty argument it lists the current and callable models; with one model ID it validates the ID, persists
 // it best-effort when configured, and starts a new session using that model.
 func (m *model) handleModelCommand(arg string) {
@@ -1139,6 +1331,7 @@ func (m *model) handlePackageCommand(arg string) {
 	cfg := m.sessionConfig
 	cfg.packagePath = arg
 	cfg.agentName = ""
+	cfg.allowedTools = nil
 	cfg, err := m.normalizeConfigForCurrentSandbox(cfg)
 	if err != nil {
 		m.appendSystemMessage(fmt.Sprintf("Cannot enter package mode: %v", err))
@@ -1293,6 +1486,9 @@ func (m *model) handlePermissionKey(msg qtui.KeyEvent) bool {
 		case "n":
 			m.resolvePermission(false)
 			return true
+		case "a":
+			m.alwaysAllowPermission()
+			return true
 		}
 	}
 
@@ -1310,6 +1506,23 @@ func (m *model) resolvePermission(allow bool) {
 	} else {
 		req.Disallow()
 	}
+	m.finishActivePermission()
+}
+
+// alwaysAllowPermission allows the active permission prompt and saves a project permission rule so matching requests are allowed without prompting. It is a no-op
+// if the prompt does not offer AlwaysAllow.
+func (m *model) alwaysAllowPermission() {
+	if m.activePermission == nil || m.activePermission.request.AlwaysAllow == nil {
+		return
+	}
+	if err := m.activePermission.request.AlwaysAllow(); err != nil {
+		m.appendSystemMessage(fmt.Sprintf("Allowed, but could not save the permission rule to %s: %v", authdomain.PermissionsFile, err))
+	}
+	m.finishActivePermission()
+}
+
+// finishActivePermission hides the resolved active permission prompt and advances to the next prompt.
+func (m *model) finishActivePermission() {
 	m.activePermission = nil
 	m.refreshPermissionView()
 	m.refreshViewport(true)
@@ -1341,7 +1554,7 @@ func (m *model) shouldSaveToHistory(value string) bool {
 		return false
 	}
 	switch fields[0] {
-	case "/new", "/model", "/models", "/skills", "/quit", "/exit", "/logout":
+	case "/new", "/model", "/models", "/skills", "/diff", "/changes", "/filter", "/quit", "/exit", "/logout":
 		return false
 	}
 	return len(fields) > 1
@@ -1458,13 +1671,14 @@ func (m *model) persistEditedHistoryDraft() {
 	}
 }
 
-// sendOrQueueMessage reflects value in the Messages Area and queues it for delivery when the agent can accept it.
-func (m *model) sendOrQueueMessage(value string) {
+// sendOrQueueMessage reflects value (and any attached images) in the Messages Area and queues it for delivery when the agent can accept it.
+func (m *model) sendOrQueueMessage(value string, images ...attachedImage) {
+	display := userMessageDisplay(value, images)
 	// Package context gathering can be in-flight during package-mode session start. In
 	// that window, queue locally and send only after the context has been applied.
 	if m.packageContextPending() {
-		m.queuedMessages = append(m.queuedMessages, queuedMessage{text: value, dest: queuedMessageDestLocal})
-		m.appendUserMessage(value, true)
+		m.queuedMessages = append(m.queuedMessages, queuedMessage{text: value, dest: queuedMessageDestLocal, images: images})
+		m.appendUserMessage(display, true)
 		m.refreshViewport(true)
 		if m.viewport != nil {
 			m.viewport.ScrollToBottom()
@@ -1474,8 +1688,10 @@ func (m *model) sendOrQueueMessage(value string) {
 	if m.isAgentRunning() {
 		// Spec: allow enqueuing messages mid-run so they can be injected at the agent's
 		// next safe boundary (ex: after a tool result is appended).
+		// The agent's mid-run queue only carries text, so messages with images wait
+		// for the current run to finish.
 		queuedToAgent := false
-		if m.session != nil {
+		if m.session != nil && len(images) == 0 {
 			if err := m.session.QueueUserMessage(value); err == nil {
 				m.queuedMessages = append(m.queuedMessages, queuedMessage{text: value, dest: queuedMessageDestAgent})
 				queuedToAgent = true
@@ -1484,9 +1700,9 @@ func (m *model) sendOrQueueMessage(value string) {
 		if !queuedToAgent {
 			// Fallback: if the agent is no longer accepting queued messages, queue
 			// locally and send it once the current run finishes.
-			m.queuedMessages = append(m.queuedMessages, queuedMessage{text: value, dest: queuedMessageDestLocal})
+			m.queuedMessages = append(m.queuedMessages, queuedMessage{text: value, dest: queuedMessageDestLocal, images: images})
 		}
-		m.appendUserMessage(value, true)
+		m.appendUserMessage(display, true)
 		m.refreshViewport(true)
 		if m.viewport != nil {
 			m.viewport.ScrollToBottom()
@@ -1494,7 +1710,7 @@ func (m *model) sendOrQueueMessage(value string) {
 		return
 	}
 
-	m.appendUserMessage(value, false)
+	m.appendUserMessage(display, false)
 	m.refreshViewport(true)
 	if m.viewport != nil {
 		m.viewport.ScrollToBottom()
@@ -1502,7 +1718,7 @@ func (m *model) sendOrQueueMessage(value string) {
 }
 
 // startAgentRunIfPossible starts an agent run unless session, run, or package-context state prevents it.
-func (m *model) startAgentRunIfPossible(value string) {
+func (m *model) startAgentRunIfPossible(value string, images ...attachedImage) {
 	if m.session == nil || m.isAgentRunning() || m.packageContextPending() {
 		return
 	}
@@ -1510,11 +1726,11 @@ func (m *model) startAgentRunIfPossible(value string) {
 		m.startAgentRunHook(value)
 		return
 	}
-	m.startAgentRun(value)
+	m.startAgentRun(value, images...)
 }
 
-// startAgentRun starts an agent turn for value and connects its event stream to the TUI.
-func (m *model) startAgentRun(value string) {
+// startAgentRun starts an agent turn for value (with any attached images) and connects its event stream to the TUI.
+func (m *model) startAgentRun(value string, images ...attachedImage) {
 	if m.session == nil || m.tui == nil {
 		return
 	}
@@ -1522,7 +1738,7 @@ func (m *model) startAgentRun(value string) {
 	_ = m.session.AddGrantsFromUserMessage(value)
 
 	ctx, cancel := context.WithCancel(context.Background())
-	events := m.session.SendMessage(ctx, value)
+	events := m.session.SendMessageWithImages(ctx, value, imageContents(images))
 	if events == nil {
 		cancel()
 		return
@@ -1597,14 +1813,14 @@ func (m *model) startNextQueuedMessage() {
 		return
 	}
 
-	next := m.queuedMessages[0].text
+	next := m.queuedMessages[0]
 	m.queuedMessages = m.queuedMessages[1:]
-	m.appendUserMessage(next, false)
+	m.appendUserMessage(userMessageDisplay(next.text, next.images), false)
 	m.refreshViewport(true)
 	if m.viewport != nil {
 		m.viewport.ScrollToBottom()
 	}
-	m.startAgentRun(next)
+	m.startAgentRun(next.text, next.images...)
 }
 
 // startUserRequestListener forwards authorization requests from requests into the TUI update loop with sourceID.
@@ -1646,6 +1862,9 @@ func (m *model) restoreQueuedMessagesToInput() {
 		return
 	}
 	m.exitEditingState()
+	for _, msg := range m.queuedMessages {
+		m.pendingImages = append(m.pendingImages, msg.images...)
+	}
 	if m.textarea != nil {
 		pending := make([]string, 0, len(m.queuedMessages))
 		for _, msg := range m.queuedMessages {
@@ -2151,6 +2370,9 @@ func (m *model) refreshViewport(autoScroll bool) {
 			blocks = append(blocks, renderedBlock{text: indicator, messageIndex: -1, copyable: false})
 		}
 	}
+	if status := m.renderFilterStatus(width); status != "" {
+		blocks = append(blocks, renderedBlock{text: status, messageIndex: -1, copyable: false})
+	}
 	blocks = append(blocks, renderedBlock{text: m.blankRow(width, m.palette.primaryBackground), messageIndex: -1, copyable: false}) // always have one blank line at the end
 
 	content, targets := m.joinRenderedBlocksWithOverlay(blocks, width)
@@ -2158,6 +2380,7 @@ func (m *model) refreshViewport(autoScroll bool) {
 	content = m.padViewportContentHeight(content, height, width)
 	if m.viewport != nil {
 		m.viewport.SetContent(content)
+		m.viewport.SetSections(m.viewportSections(blocks))
 		if autoScroll {
 			m.viewport.ScrollToBottom()
 		}
@@ -2519,7 +2742,6 @@ func (m *model) tokensCostSection() string {
 		modelID = m.currentModelID()
 	}
 
-	info := m.currentModelInfo()
 	var (
 		usage          llmstream.TokenUsage
 		contextPercent = -1
@@ -2527,7 +2749,12 @@ func (m *model) tokensCostSection() string {
 	if agentInstance := m.currentAgent(); agentInstance != nil {
 		usage = agentInstance.TokenUsage()
 		contextPercent = agentInstance.ContextUsagePercent()
+		// The agent's model differs from the selected one after a failover to a fallback model.
+		if active := agentInstance.Model(); active != "" {
+			modelID = active
+		}
 	}
+	info := llmmodel.GetModelInfo(modelID)
 	lines := make([]string, 0, 4)
 	lines = append(lines,
 		termformat.Sanitize(fmt.Sprintf("Session: %s", sessionID), 4),
@@ -2560,11 +2787,6 @@ func (m *model) packageSection() string {
 	return strings.Join(lines, "\n")
 }
 
-// currentModelInfo returns metadata for the model selected for the active or next session.
-func (m *model) currentModelInfo() llmmodel.ModelInfo {
-	return llmmodel.GetModelInfo(m.currentModelID())
-}
-
 // currentModelID returns the model selected for the active or next session. It falls back to the default model when no session or configured model is available.
 func (m *model) currentModelID() llmmodel.ModelID {
 	if m == nil {
@@ -2670,6 +2892,9 @@ func (m *model) refreshPermissionView() {
 		return termformat.Style{Foreground: m.palette.primaryForeground}.Wrap(s)
 	}
 	b.WriteString(key("Y") + body("    allow\n"))
+	if req.AlwaysAllow != nil {
+		b.WriteString(key("A") + body("    always allow (this project)\n"))
+	}
 	b.WriteString(key("N") + body("    deny\n"))
 	b.WriteString(key("ESC") + body("  deny + stop agent"))
 
@@ -2890,6 +3115,7 @@ func (m *model) resetSessionWithConfig(cfg sessionConfig) {
 	m.pendingPostResetMessage = ""
 	m.pendingPostResetUserMessage = ""
 	m.pendingPostResetStartRun = false
+	m.pendingReviewNote = ""
 	m.packageContext = nil
 	m.specConformance = nil
 	m.resetToolDisplayState()
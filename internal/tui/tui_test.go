@@ -3,6 +3,8 @@ package tui
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -387,6 +389,77 @@ func TestOrchestrateCommandWithMessageAddsInitialUserMessage(t *testing.T) {
 	require.Equal(t, "plan the release work", m.messages[1].userMessage)
 }
 
+func TestCustomSlashCommands(t *testing.T) {
+	sandbox := t.TempDir()
+	commandsDir := filepath.Join(sandbox, ".codalotl", "commands")
+	require.NoError(t, os.MkdirAll(commandsDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(commandsDir, "explain.md"), []byte("Explain this code."), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(commandsDir, "audit.md"), []byte("---\nagent: pr-orchestrator\nallowed-tools: [read_file]\n---\nAudit: {{.Args}}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(commandsDir, "review.md"), []byte("---\nmode: package\n---\nReview {{.Package}}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(commandsDir, "typo.md"), []byte("---\nallowed-tools: [raed_file]\n---\nRead it.\n"), 0o644))
+
+	newTestModel := func() (*model, *[]string, *sessionConfig, *int) {
+		var factoryCfg sessionConfig
+		factoryCalls := 0
+		factory := func(cfg sessionConfig) (*session, error) {
+			factoryCalls++
+			factoryCfg = cfg
+			return &session{config: cfg, sandboxDir: sandbox}, nil
+		}
+		cfg := sessionConfig{sandboxDir: sandbox}
+		m := newModel(colorPalette{}, noopFormatter{}, &session{config: cfg, sandboxDir: sandbox}, cfg, factory, nil, nil, nil)
+		var started []string
+		m.startAgentRunHook = func(message string) {
+			started = append(started, message)
+		}
+		return m, &started, &factoryCfg, &factoryCalls
+	}
+
+	t.Run("prompt-only command sends to current session", func(t *testing.T) {
+		m, started, _, factoryCalls := newTestModel()
+		require.True(t, m.handleSlashCommand("/explain the parser"))
+		require.Equal(t, []string{"Explain this code.\n\nthe parser"}, *started)
+		require.Equal(t, 0, *factoryCalls)
+	})
+
+	t.Run("command with agent starts new session", func(t *testing.T) {
+		m, started, factoryCfg, factoryCalls := newTestModel()
+		require.True(t, m.handleSlashCommand("/audit deps"))
+		require.Equal(t, 1, *factoryCalls)
+		require.Equal(t, orchestrateAgentName, factoryCfg.agentName)
+		require.Equal(t, []string{"read_file"}, factoryCfg.allowedTools)
+		require.Equal(t, []string{"Audit: deps"}, *started)
+
+		// Built-in mode commands drop the tool restriction.
+		m.handleGenericCommand()
+		require.Nil(t, factoryCfg.allowedTools)
+		require.Empty(t, factoryCfg.agentName)
+	})
+
+	t.Run("package command requires package mode", func(t *testing.T) {
+		m, started, _, factoryCalls := newTestModel()
+		require.True(t, m.handleSlashCommand("/review"))
+		require.Empty(t, *started)
+		require.Equal(t, 0, *factoryCalls)
+		require.Contains(t, m.messages[len(m.messages)-1].userMessage, "requires Package Mode")
+	})
+
+	t.Run("command that fails to load reports why", func(t *testing.T) {
+		m, started, _, factoryCalls := newTestModel()
+		require.True(t, m.handleSlashCommand("/typo"))
+		require.Empty(t, *started)
+		require.Equal(t, 0, *factoryCalls)
+		require.Contains(t, m.messages[len(m.messages)-1].userMessage, `Cannot run /typo:`)
+		require.Contains(t, m.messages[len(m.messages)-1].userMessage, `unknown tool "raed_file"`)
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		m, _, _, _ := newTestModel()
+		require.True(t, m.handleSlashCommand("/nope"))
+		require.Contains(t, m.messages[len(m.messages)-1].userMessage, "Command /nope not supported.")
+	})
+}
+
 func TestNewSessionRetainsOrchestrateMode(t *testing.T) {
 	var factoryCfg sessionConfig
 	factory := func(cfg sessionConfig) (*session, error) {
@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"go/format"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/codalotl/codalotl/internal/q/uni"
 )
 
 // A reflowGroupKind classifies a parsed documentation fragment during reflow.
@@ -243,30 +247,48 @@ func formatDocComment(lines []string) (string, error) {
 	return strings.Join(commentLines, "\n") + "\n", nil
 }
 
-// wrapWords wraps words to approximately width columns without splitting tokens.
-func wrapWords(words []string, width int) []string {
+// A reflowWord is an unbreakable unit of reflowed text.
+type reflowWord struct {
+	text  string // text is the word's text.
+	glued bool   // glued is true when the word continues the previous word without a space (ex: consecutive CJK ideographs).
+}
+
+// wrapWords wraps words to approximately width columns (terminal cells) without splitting tokens.
+func wrapWords(words []reflowWord, width int) []string {
 	if len(words) == 0 {
 		return nil
 	}
 	var lines []string
-	line := words[0]
+	line := words[0].text
+	lineWidth := uni.TextWidth(line, nil)
 	for _, w := range words[1:] {
+		sep := " "
+		if w.glued {
+			sep = ""
+		}
+		wWidth := uni.TextWidth(w.text, nil)
+
 		// If current line already meets or exceeds width, start new line
-		if len(line) >= width {
+		if lineWidth >= width {
 			lines = append(lines, line)
-			line = w
+			line, lineWidth = w.text, wWidth
 		} else {
 			// Special case: if line is nearly full (75% of width) and adding word
 			// would make it way too long (150% of width), start new line instead
-			nearlyFull := len(line) >= int(float64(width)*0.75)
-			wouldBeTooLong := len(line)+1+len(w) > int(float64(width)*1.5)
+			nearlyFull := lineWidth >= int(float64(width)*0.75)
+			wouldBeTooLong := lineWidth+len(sep)+wWidth > int(float64(width)*1.5)
 
 			if nearlyFull && wouldBeTooLong {
 				lines = append(lines, line)
-				line = w
+				line, lineWidth = w.text, wWidth
+			} else if w.glued && lineWidth+wWidth > width {
+				// Glued words are small (ex: one ideograph), so break before overflowing rather than soft-wrapping past width.
+				lines = append(lines, line)
+				line, lineWidth = w.text, wWidth
 			} else {
 				// Add word to current line (even if it makes line exceed width)
-				line += " " + w
+				line += sep + w.text
+				lineWidth += len(sep) + wWidth
 			}
 		}
 	}
@@ -275,6 +297,72 @@ func wrapWords(words []string, width int) []string {
 	return lines
 }
 
+// splitAtLineBreaks splits whitespace-separated tokens further at UAX #14 line break opportunities between characters that are written without spaces (ex: CJK ideographs
+// and kana), so that such text can wrap. Tokens containing inline code are not split.
+func splitAtLineBreaks(tokens []string) []reflowWord {
+	words := make([]reflowWord, 0, len(tokens))
+	for _, token := range tokens {
+		if strings.Contains(token, "`") || !strings.ContainsFunc(token, isSpacelessScriptRune) {
+			words = append(words, reflowWord{text: token})
+			continue
+		}
+
+		start := 0
+		iter := uni.NewLineIterator(token, nil)
+		for iter.Next() {
+			end := iter.End()
+			if end == len(token) {
+				break
+			}
+			prev, _ := utf8.DecodeLastRuneInString(token[:end])
+			next, _ := utf8.DecodeRuneInString(token[end:])
+			if joinsWithoutSpace(prev, next) {
+				words = append(words, reflowWord{text: token[start:end], glued: start > 0})
+				start = end
+			}
+		}
+		words = append(words, reflowWord{text: token[start:], glued: start > 0})
+	}
+	return words
+}
+
+// joinReflowLines joins the trimmed text of consecutive comment lines into one string. Lines are joined with a space, except between characters that are written
+// without spaces (ex: a line ending and the next starting with CJK ideographs), which are joined directly.
+func joinReflowLines(parts []string) string {
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			prev, _ := utf8.DecodeLastRuneInString(parts[i-1])
+			next, _ := utf8.DecodeRuneInString(part)
+			if !joinsWithoutSpace(prev, next) {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+// joinsWithoutSpace reports whether prev and next are adjacent characters that are written without a space between them, so a line break between them doesn't
+// stand for a space.
+func joinsWithoutSpace(prev, next rune) bool {
+	return isSpacelessScriptRune(prev) && isSpacelessScriptRune(next)
+}
+
+// isSpacelessScriptRune reports whether r is from a script written without spaces between words (Han, Hiragana, Katakana) or is CJK or fullwidth punctuation.
+// Hangul is excluded: Korean uses spaces between words.
+func isSpacelessScriptRune(r rune) bool {
+	switch {
+	case r >= 0x3000 && r <= 0x303F: // CJK Symbols and Punctuation
+		return true
+	case r >= 0xFF01 && r <= 0xFF60: // Fullwidth ASCII variants and punctuation
+		return true
+	case r == 0x30FC: // Katakana-Hiragana prolonged sound mark
+		return true
+	}
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
 // groupDocLines groups formatted line-comment documentation into fragments for later text extraction and reflow.
 func groupDocLines(lines []string) []reflowGroup {
 
@@ -534,8 +622,8 @@ func calculateGroupText(group *reflowGroup) {
 				parts = append(parts, text)
 			}
 		}
-		// Join with single spaces and collapse any multiple spaces
-		group.text = strings.Join(parts, " ")
+		// Join with single spaces (none between CJK characters)
+		group.text = joinReflowLines(parts)
 
 	case reflowGroupKindListItem:
 		var parts []string
@@ -550,8 +638,8 @@ func calculateGroupText(group *reflowGroup) {
 				}
 			}
 		}
-		// Join with single spaces and collapse any multiple spaces
-		group.text = strings.Join(parts, " ")
+		// Join with single spaces (none between CJK characters)
+		group.text = joinReflowLines(parts)
 
 	case reflowGroupKindList:
 		// Calculate text for each list item
@@ -610,9 +698,9 @@ func reflowTextPreservingInlineCode(text string, width int) []string {
 		return []string{text}
 	}
 
-	// Split text into tokens, preserving inline code blocks
+	// Split text into tokens, preserving inline code blocks, then split runs of CJK text at line break opportunities
 	tokens := tokenizeWithInlineCode(text)
-	return wrapWords(tokens, width)
+	return wrapWords(splitAtLineBreaks(tokens), width)
 }
 
 // tokenizeWithInlineCode splits text into words while preserving inline code blocks as single tokens.
@@ -750,3 +750,42 @@ func TestReflowDocComment(t *testing.T) {
 		})
 	}
 }
+
+func TestReflowDocComment_CJK(t *testing.T) {
+	input := dedent(`
+		// 这个函数返回用户的名字。如果用户不存在，则返回错误。调用者应该检查错误。
+		// Use ` + "`Foo`" + ` 来获取用户。
+	`)
+	wrapped := dedent(`
+		// 这个函数返回用户的名字。如
+		// 果用户不存在，则返回错误。
+		// 调用者应该检查错误。 Use ` + "`Foo`" + `
+		// 来获取用户。
+	`)
+
+	// CJK text wraps between characters (by cell width), and never before closing punctuation.
+	got := reflowDocComment(input, 0, 4, 30)
+	if got != wrapped {
+		t.Errorf("reflowDocComment() =\n%s\nwant:\n%s", got, wrapped)
+	}
+
+	// Reflowing is stable, and unwrapping joins CJK lines without inserting spaces.
+	if got := reflowDocComment(wrapped, 0, 4, 30); got != wrapped {
+		t.Errorf("reflowDocComment() not stable =\n%s", got)
+	}
+	unwrapped := "// 这个函数返回用户的名字。如果用户不存在，则返回错误。调用者应该检查错误。 Use `Foo` 来获取用户。\n"
+	if got := reflowDocComment(wrapped, 0, 4, 120); got != unwrapped {
+		t.Errorf("reflowDocComment() =\n%s\nwant:\n%s", got, unwrapped)
+	}
+
+	// Emoji are measured in cells, not bytes.
+	emoji := "// Ship it 🚀🚀🚀 now 🎉🎉🎉🎉 when tests pass 🎉🎉🎉 ok and more text here\n"
+	wantEmoji := dedent(`
+		// Ship it 🚀🚀🚀 now 🎉🎉🎉🎉
+		// when tests pass 🎉🎉🎉
+		// ok and more text here
+	`)
+	if got := reflowDocComment(emoji, 0, 4, 24); got != wantEmoji {
+		t.Errorf("reflowDocComment() =\n%s\nwant:\n%s", got, wantEmoji)
+	}
+}